@@ -0,0 +1,98 @@
+// Package clientip resolves the client IP of the HTTP requests for the adapters, which is used as the
+// hotspot parameter for the per-IP rate limiting.
+//
+// The X-Forwarded-For and X-Real-IP headers could be forged by the clients, so they are respected only if
+// the request comes from the trusted proxies. The X-Forwarded-For header is walked from right to left,
+// and the first address not in the trusted proxies is the client IP.
+package clientip
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	HeaderXForwardedFor = "X-Forwarded-For"
+	HeaderXRealIP       = "X-Real-IP"
+)
+
+// Resolver resolves the client IP of the HTTP requests with the trusted proxies.
+type Resolver struct {
+	trustedProxies []*net.IPNet
+}
+
+// NewResolver creates a Resolver with the trusted proxies, which are the IPs (e.g. "10.0.0.1")
+// or the CIDRs (e.g. "10.0.0.0/8"). No trusted proxies means the forwarding headers are never respected.
+func NewResolver(trustedProxies ...string) (*Resolver, error) {
+	nets := make([]*net.IPNet, 0, len(trustedProxies))
+	for _, p := range trustedProxies {
+		p = strings.TrimSpace(p)
+		if !strings.Contains(p, "/") {
+			ip := net.ParseIP(p)
+			if ip == nil {
+				return nil, errors.Errorf("invalid trusted proxy: %s", p)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid trusted proxy: %s", p)
+		}
+		nets = append(nets, n)
+	}
+	return &Resolver{trustedProxies: nets}, nil
+}
+
+// ClientIP returns the client IP of the request, or empty string if it could not be resolved.
+func (r *Resolver) ClientIP(req *http.Request) string {
+	peer := hostOf(req.RemoteAddr)
+	if !r.trusted(peer) {
+		return peer
+	}
+	if xff := req.Header[http.CanonicalHeaderKey(HeaderXForwardedFor)]; len(xff) > 0 {
+		addrs := strings.Split(strings.Join(xff, ","), ",")
+		for i := len(addrs) - 1; i >= 0; i-- {
+			addr := hostOf(strings.TrimSpace(addrs[i]))
+			if net.ParseIP(addr) == nil {
+				// the malformed address could not be traced any further
+				break
+			}
+			if i == 0 || !r.trusted(addr) {
+				return addr
+			}
+		}
+	}
+	if ip := strings.TrimSpace(req.Header.Get(HeaderXRealIP)); net.ParseIP(ip) != nil {
+		return ip
+	}
+	return peer
+}
+
+func (r *Resolver) trusted(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, n := range r.trustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// hostOf strips the port of the address if present.
+func hostOf(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
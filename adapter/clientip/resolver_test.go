@@ -0,0 +1,48 @@
+package clientip
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newRequest(remoteAddr string, headers map[string]string) *http.Request {
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = remoteAddr
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return req
+}
+
+func TestNewResolver(t *testing.T) {
+	_, err := NewResolver("10.0.0.1", "192.168.0.0/16", "::1")
+	assert.NoError(t, err)
+	_, err = NewResolver("10.0.0")
+	assert.Error(t, err)
+	_, err = NewResolver("10.0.0.0/33")
+	assert.Error(t, err)
+}
+
+func TestResolver_ClientIP(t *testing.T) {
+	r, err := NewResolver("10.0.0.0/8", "192.168.1.1")
+	assert.NoError(t, err)
+
+	// the headers of the untrusted peers are ignored
+	assert.Equal(t, "1.1.1.1", r.ClientIP(newRequest("1.1.1.1:1234", map[string]string{HeaderXForwardedFor: "2.2.2.2"})))
+	// the trusted proxies are skipped from right to left
+	assert.Equal(t, "2.2.2.2", r.ClientIP(newRequest("10.0.0.1:1234", map[string]string{
+		HeaderXForwardedFor: "6.6.6.6, 2.2.2.2, 192.168.1.1, 10.1.1.1"})))
+	// all the forwarded addresses are trusted
+	assert.Equal(t, "10.0.0.3", r.ClientIP(newRequest("10.0.0.1:1234", map[string]string{
+		HeaderXForwardedFor: "10.0.0.3, 10.0.0.2"})))
+	// the malformed address stops the tracing
+	assert.Equal(t, "10.0.0.1", r.ClientIP(newRequest("10.0.0.1:1234", map[string]string{
+		HeaderXForwardedFor: "2.2.2.2, unknown"})))
+	assert.Equal(t, "3.3.3.3", r.ClientIP(newRequest("10.0.0.1:1234", map[string]string{HeaderXRealIP: "3.3.3.3"})))
+	assert.Equal(t, "10.0.0.1", r.ClientIP(newRequest("10.0.0.1:1234", nil)))
+
+	noProxy, _ := NewResolver()
+	assert.Equal(t, "10.0.0.1", noProxy.ClientIP(newRequest("10.0.0.1:1234", map[string]string{HeaderXRealIP: "3.3.3.3"})))
+}
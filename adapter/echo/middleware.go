@@ -1,45 +1,147 @@
 package echo
 
 import (
+	"bufio"
+	"net"
 	"net/http"
 
+	"github.com/alibaba/sentinel-golang/adapter/errclass"
+	"github.com/alibaba/sentinel-golang/adapter/route"
+	"github.com/alibaba/sentinel-golang/adapter/tier"
 	sentinel "github.com/alibaba/sentinel-golang/api"
 	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/flow"
 	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
 )
 
 // SentinelMiddleware returns new echo.HandlerFunc.
 // Default resource name pattern is {httpMethod}:{apiPath}, such as "GET:/api/:id".
-// Default block fallback is to return 429 (Too Many Requests) response.
+// Default block fallback is to return 429 (Too Many Requests) response,
+// which could be customized per block type by WithBlockStatusCodes.
 //
-// You may customize your own resource extractor and block handler by setting options.
+// You may customize your own resource extractor and block handler by setting options,
+// or override them per route by WithRouteConfig.
 func SentinelMiddleware(opts ...Option) echo.MiddlewareFunc {
 	options := evaluateOptions(opts)
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) (err error) {
+			if options.exclusions.ExcludesRequest(c.Request()) {
+				return next(c)
+			}
 			resourceName := c.Request().Method + ":" + c.Path()
 			if options.resourceExtract != nil {
 				resourceName = options.resourceExtract(c)
 			}
-			entry, blockErr := sentinel.Entry(
-				resourceName,
+			var settings route.Settings
+			if options.routeConfig != nil {
+				s, guarded := options.routeConfig.Resolve(c.Request().Method, c.Path())
+				if !guarded {
+					return next(c)
+				}
+				if len(s.Resource) > 0 {
+					resourceName = s.Resource
+				}
+				settings = s
+			}
+			entryOpts := []sentinel.EntryOption{
 				sentinel.WithResourceType(base.ResTypeWeb),
 				sentinel.WithTrafficType(base.Inbound),
-			)
+			}
+			if origin := options.originOf(c, settings.OriginHeader); len(origin) > 0 {
+				entryOpts = append(entryOpts, sentinel.WithOrigin(origin))
+			}
+			if len(settings.Tags) > 0 {
+				entryOpts = append(entryOpts, sentinel.WithTags(settings.Tags...))
+			}
+			if options.clientIP != nil {
+				entryOpts = append(entryOpts, sentinel.WithArgs(options.clientIP.ClientIP(c.Request())))
+			}
+			if options.tierResolver != nil {
+				var param interface{}
+				if apiKey := c.Request().Header.Get(options.apiKeyHeader); len(apiKey) > 0 {
+					param = tier.ParamOf(apiKey, options.tierResolver)
+				}
+				entryOpts = append(entryOpts, sentinel.WithArgs(param))
+			}
+			if c.Request().ContentLength > 0 {
+				// report the payload size for the Throughput flow rules
+				entryOpts = append(entryOpts, sentinel.WithAttachment(flow.PayloadBytesAttachmentKey, c.Request().ContentLength))
+			}
+			entry, blockErr := sentinel.Entry(resourceName, entryOpts...)
 			if blockErr != nil {
 				if options.blockFallback != nil {
 					err = options.blockFallback(c)
 				} else {
 					// default error response
-					err = c.JSON(http.StatusTooManyRequests, "Blocked by Sentinel")
+					code, msg := options.blockStatusCodeOf(blockErr.BlockType()), "Blocked by Sentinel"
+					if settings.BlockStatusCode > 0 {
+						code = settings.BlockStatusCode
+					}
+					if len(settings.BlockMessage) > 0 {
+						msg = settings.BlockMessage
+					}
+					err = c.JSON(code, msg)
 				}
 				return err
 			}
 			defer entry.Exit()
 
+			if options.errorClassifier == nil {
+				err = next(c)
+				return err
+			}
+			var sniffer *errclass.Sniffer
+			if options.errorClassifier.SniffBody {
+				sniffer = &errclass.Sniffer{}
+				w := c.Response().Writer
+				c.Response().Writer = &sniffingWriter{ResponseWriter: w, sniffer: sniffer}
+				defer func() {
+					c.Response().Writer = w
+				}()
+			}
 			err = next(c)
+			if traceErr := options.errorClassifier.Classify(statusOf(c, err), sniffer.Bytes()); traceErr != nil {
+				sentinel.TraceError(entry, traceErr)
+			}
 			return err
 		}
 
 	}
 }
+
+// statusOf returns the status code of the response, or the status code of the error returned by the handler
+// if the response is not committed yet (i.e. the error is to be handled by the HTTP error handler of echo).
+func statusOf(c echo.Context, err error) int {
+	if err == nil || c.Response().Committed {
+		return c.Response().Status
+	}
+	if he, ok := err.(*echo.HTTPError); ok {
+		return he.Code
+	}
+	return http.StatusInternalServerError
+}
+
+// sniffingWriter sniffs the response body for the error classifier.
+type sniffingWriter struct {
+	http.ResponseWriter
+	sniffer *errclass.Sniffer
+}
+
+func (w *sniffingWriter) Write(b []byte) (int, error) {
+	w.sniffer.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *sniffingWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *sniffingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, errors.New("the ResponseWriter doesn't support hijacking")
+}
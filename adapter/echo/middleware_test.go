@@ -6,8 +6,13 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/alibaba/sentinel-golang/adapter/errclass"
+	"github.com/alibaba/sentinel-golang/adapter/route"
 	sentinel "github.com/alibaba/sentinel-golang/api"
+	"github.com/alibaba/sentinel-golang/core/base"
 	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/hotspot"
+	"github.com/alibaba/sentinel-golang/core/stat"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 )
@@ -94,6 +99,70 @@ func TestSentinelMiddleware(t *testing.T) {
 					code: http.StatusTooManyRequests,
 				},
 			},
+			{
+				name: "customize block status code",
+				args: args{
+					opts: []Option{
+						WithResourceExtractor(func(ctx echo.Context) string {
+							return ctx.Path()
+						}),
+						WithBlockStatusCodes(map[base.BlockType]int{base.BlockTypeFlow: http.StatusServiceUnavailable}),
+					},
+					method:  http.MethodGet,
+					path:    "/api/:uid",
+					reqPath: "/api/123",
+					handler: func(ctx echo.Context) error {
+						return ctx.JSON(http.StatusOK, "ping")
+					},
+					body: nil,
+				},
+				want: want{
+					code: http.StatusServiceUnavailable,
+				},
+			},
+			{
+				name: "route config override",
+				args: args{
+					opts: []Option{
+						WithRouteConfig(&route.Config{
+							Routes: []*route.Route{
+								{Method: http.MethodGet, Path: "/api/:uid", Resource: "/api/:uid", BlockStatusCode: http.StatusServiceUnavailable},
+							},
+						}),
+					},
+					method:  http.MethodGet,
+					path:    "/api/:uid",
+					reqPath: "/api/123",
+					handler: func(ctx echo.Context) error {
+						return ctx.JSON(http.StatusOK, "ping")
+					},
+					body: nil,
+				},
+				want: want{
+					code: http.StatusServiceUnavailable,
+				},
+			},
+			{
+				name: "route config exclusion",
+				args: args{
+					opts: []Option{
+						WithResourceExtractor(func(ctx echo.Context) string {
+							return ctx.Path()
+						}),
+						WithRouteConfig(&route.Config{IncludePaths: []string{"/ping"}}),
+					},
+					method:  http.MethodGet,
+					path:    "/api/:uid",
+					reqPath: "/api/123",
+					handler: func(ctx echo.Context) error {
+						return ctx.JSON(http.StatusOK, "ping")
+					},
+					body: nil,
+				},
+				want: want{
+					code: http.StatusOK,
+				},
+			},
 			{
 				name: "customize block fallback",
 				args: args{
@@ -131,3 +200,121 @@ func TestSentinelMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestSentinelMiddlewareWithClientIPParam(t *testing.T) {
+	initSentinel(t)
+	_, err := hotspot.LoadRules([]*hotspot.Rule{
+		{
+			Resource:        "GET:/ip",
+			MetricType:      hotspot.QPS,
+			ControlBehavior: hotspot.Reject,
+			ParamIndex:      0,
+			Threshold:       1,
+			DurationInSec:   1,
+		},
+	})
+	assert.NoError(t, err)
+	defer hotspot.ClearRules()
+
+	router := echo.New()
+	// httptest.NewRequest comes from 192.0.2.1
+	router.Use(SentinelMiddleware(WithClientIPParam("192.0.2.0/24")))
+	router.GET("/ip", func(ctx echo.Context) error {
+		return ctx.String(http.StatusOK, "ip")
+	})
+	request := func(clientIP string) int {
+		r := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		r.Header.Set("X-Forwarded-For", clientIP)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("1.1.1.1"))
+	assert.Equal(t, http.StatusTooManyRequests, request("1.1.1.1"))
+	// the other clients are limited separately
+	assert.Equal(t, http.StatusOK, request("2.2.2.2"))
+}
+
+func TestOriginOfWithBaggageOrigin(t *testing.T) {
+	newContext := func(header map[string]string) echo.Context {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		for k, v := range header {
+			r.Header.Set(k, v)
+		}
+		return echo.New().NewContext(r, httptest.NewRecorder())
+	}
+	opts := evaluateOptions([]Option{WithBaggageOrigin("caller")})
+
+	// the origin header takes precedence
+	c := newContext(map[string]string{"X-Origin": "app-a", "ot-baggage-caller": "app-b"})
+	assert.Equal(t, "app-a", opts.originOf(c, "X-Origin"))
+	c = newContext(map[string]string{"ot-baggage-caller": "app-b"})
+	assert.Equal(t, "app-b", opts.originOf(c, "X-Origin"))
+	assert.Equal(t, "", evaluateOptions(nil).originOf(c, "X-Origin"))
+}
+
+func TestSentinelMiddlewareWithErrorClassifier(t *testing.T) {
+	initSentinel(t)
+
+	router := echo.New()
+	router.Use(SentinelMiddleware(WithErrorClassifier(errclass.JSONEnvelopeClassifier(500, "code", "message", "0"))))
+	router.GET("/envelope", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"code": c.QueryParam("code"), "message": "failed"})
+	})
+	router.GET("/fail", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusServiceUnavailable)
+	})
+	request := func(path string) int {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+	errorsOf := func(res string) int64 {
+		return stat.GetResourceNode(res).GetSum(base.MetricEventError)
+	}
+
+	assert.Equal(t, http.StatusOK, request("/envelope?code=0"))
+	assert.Equal(t, int64(0), errorsOf("GET:/envelope"))
+	// the error hidden behind HTTP 200 is traced
+	assert.Equal(t, http.StatusOK, request("/envelope?code=5001"))
+	assert.Equal(t, int64(1), errorsOf("GET:/envelope"))
+	// the error returned by the handler is classified by its status code
+	assert.Equal(t, http.StatusServiceUnavailable, request("/fail"))
+	assert.Equal(t, int64(1), errorsOf("GET:/fail"))
+}
+
+func TestSentinelMiddlewareWithExclusions(t *testing.T) {
+	initSentinel(t)
+	_, err := flow.LoadRules([]*flow.Rule{
+		{Resource: "GET:/healthz", Threshold: 0, StatIntervalInMs: 1000},
+		{Resource: "OPTIONS:/api", Threshold: 0, StatIntervalInMs: 1000},
+	})
+	assert.NoError(t, err)
+	defer flow.ClearRules()
+
+	exclusions, err := route.NewExclusions(route.CommonExcludedPaths, true)
+	assert.NoError(t, err)
+	router := echo.New()
+	router.Use(SentinelMiddleware(WithExclusions(exclusions)))
+	router.GET("/healthz", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+	router.OPTIONS("/api", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+	request := func(r *http.Request) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request(httptest.NewRequest(http.MethodGet, "/healthz", nil)))
+	node := stat.GetResourceNode("GET:/healthz")
+	assert.Equal(t, int64(0), node.GetSum(base.MetricEventPass)+node.GetSum(base.MetricEventBlock), "the excluded requests should not be counted")
+	preflight := httptest.NewRequest(http.MethodOptions, "/api", nil)
+	preflight.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	assert.Equal(t, http.StatusNoContent, request(preflight))
+	assert.Equal(t, http.StatusTooManyRequests, request(httptest.NewRequest(http.MethodOptions, "/api", nil)))
+}
@@ -1,6 +1,15 @@
 package echo
 
 import (
+	"net/http"
+
+	"github.com/alibaba/sentinel-golang/adapter/clientip"
+	"github.com/alibaba/sentinel-golang/adapter/errclass"
+	"github.com/alibaba/sentinel-golang/adapter/origin"
+	"github.com/alibaba/sentinel-golang/adapter/route"
+	"github.com/alibaba/sentinel-golang/adapter/tier"
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/logging"
 	"github.com/labstack/echo/v4"
 )
 
@@ -9,6 +18,15 @@ type (
 	options struct {
 		resourceExtract func(echo.Context) string
 		blockFallback   func(echo.Context) error
+		blockStatusCode map[base.BlockType]int
+		originExtract   func(echo.Context) string
+		routeConfig     *route.Config
+		originProvider  *origin.Provider
+		clientIP        *clientip.Resolver
+		apiKeyHeader    string
+		tierResolver    tier.Resolver
+		errorClassifier *errclass.Classifier
+		exclusions      *route.Exclusions
 	}
 )
 
@@ -21,6 +39,31 @@ func evaluateOptions(opts []Option) *options {
 	return optCopy
 }
 
+// blockStatusCodeOf returns the HTTP status code of the requests blocked with the block type.
+func (o *options) blockStatusCodeOf(blockType base.BlockType) int {
+	if code, ok := o.blockStatusCode[blockType]; ok {
+		return code
+	}
+	return http.StatusTooManyRequests
+}
+
+// originOf returns the origin of the request by the origin extractor, or the value of the origin header,
+// or the origin propagated by the tracing systems if WithBaggageOrigin is set.
+func (o *options) originOf(c echo.Context, originHeader string) string {
+	if o.originExtract != nil {
+		return o.originExtract(c)
+	}
+	if len(originHeader) > 0 {
+		if o := c.Request().Header.Get(originHeader); len(o) > 0 {
+			return o
+		}
+	}
+	if o.originProvider != nil {
+		return o.originProvider.OriginOf(c.Request().Header.Get)
+	}
+	return ""
+}
+
 // WithResourceExtractor sets the resource extractor of the web requests.
 func WithResourceExtractor(fn func(ctx echo.Context) string) Option {
 	return func(opts *options) {
@@ -34,3 +77,86 @@ func WithBlockFallback(fn func(ctx echo.Context) error) Option {
 		opts.blockFallback = fn
 	}
 }
+
+// WithBlockStatusCodes sets the HTTP status codes of the blocked requests per block type,
+// e.g. 429 for BlockTypeFlow, 503 for BlockTypeCircuitBreaking and 529 for BlockTypeSystemFlow.
+// The block types absent from the mapping fall back to 429 (Too Many Requests).
+// It doesn't take effect if the block fallback is set.
+func WithBlockStatusCodes(codes map[base.BlockType]int) Option {
+	return func(opts *options) {
+		opts.blockStatusCode = codes
+	}
+}
+
+// WithOriginExtractor sets the origin extractor of the web requests, which takes precedence over
+// the origin header of the route config.
+func WithOriginExtractor(fn func(ctx echo.Context) string) Option {
+	return func(opts *options) {
+		opts.originExtract = fn
+	}
+}
+
+// WithRouteConfig sets the declarative per-route configuration, which overrides the resource name,
+// the origin header and the block response (if the block fallback is not set) of the matched routes.
+// The routes are matched by the registered path of echo (e.g. "/api/:id"), and the requests of
+// the unguarded paths are passed through directly.
+func WithRouteConfig(cfg *route.Config) Option {
+	return func(opts *options) {
+		opts.routeConfig = cfg
+	}
+}
+
+// WithClientIPParam passes the client IP of the requests as the first argument of the entries,
+// so the hotspot rules with ParamIndex 0 limit the requests per client IP.
+// The forwarding headers (X-Forwarded-For and X-Real-IP) are respected only if the request comes from
+// the trusted proxies, which are the IPs or the CIDRs (e.g. "10.0.0.0/8"). If any trusted proxy is invalid,
+// no proxy is trusted and the peer address is used as the client IP.
+func WithClientIPParam(trustedProxies ...string) Option {
+	resolver, err := clientip.NewResolver(trustedProxies...)
+	if err != nil {
+		logging.Error(err, "[SentinelEchoAdapter] Invalid trusted proxies, no proxy is trusted", "trustedProxies", trustedProxies)
+		resolver, _ = clientip.NewResolver()
+	}
+	return func(opts *options) {
+		opts.clientIP = resolver
+	}
+}
+
+// WithAPIKeyTierParam passes the API key of the requests in the header with the tier of its plan
+// (see hotspot.TieredParam) as the argument of the entries, after the client IP if WithClientIPParam is set,
+// so the hotspot rules with TierThresholds limit the requests per API key by the thresholds of the tiers.
+// The requests without the API key are not limited by the hotspot rules of the param.
+func WithAPIKeyTierParam(header string, resolver tier.Resolver) Option {
+	return func(opts *options) {
+		opts.apiKeyHeader = header
+		opts.tierResolver = resolver
+	}
+}
+
+// WithBaggageOrigin takes the origin from the context propagated by the tracing systems (e.g. the OpenTelemetry baggage,
+// see package origin) when the origin header is missing. The baggage keys are looked up in order,
+// origin.DefaultBaggageKeys if absent. It doesn't take effect if the origin extractor is set.
+func WithBaggageOrigin(baggageKeys ...string) Option {
+	return func(opts *options) {
+		opts.originProvider = origin.NewProvider(baggageKeys...)
+	}
+}
+
+// WithErrorClassifier traces the responses classified as errors by the classifier (see package errclass),
+// e.g. the application-level failures with HTTP 200 and an error envelope in the JSON body,
+// so that the circuit breakers open on them. The response body is sniffed only if the classifier needs it.
+func WithErrorClassifier(c *errclass.Classifier) Option {
+	return func(opts *options) {
+		if c != nil && c.Classify != nil {
+			opts.errorClassifier = c
+		}
+	}
+}
+
+// WithExclusions excludes the requests (e.g. the health checks, the metrics scrapes and the CORS preflight requests,
+// see route.NewExclusions) from Sentinel, which are never counted in statistics or blocked.
+func WithExclusions(e *route.Exclusions) Option {
+	return func(opts *options) {
+		opts.exclusions = e
+	}
+}
@@ -0,0 +1,21 @@
+/*
+This package implements the Envoy rate limit service (envoy.service.ratelimit.v3.RateLimitService)
+backed by Sentinel, so that a Sentinel-golang process could serve as the global rate limit service
+of Envoy or Istio, while sharing the rule management with the in-process usage.
+
+Users may register the service to a gRPC server, like:
+
+		s := grpc.NewServer()
+		envoyrls.Register(s)
+
+Every descriptor of the request is checked as a Sentinel entry (with the hits addend as the acquire count).
+By default, the resource name is the domain followed by the descriptor keys, and the hotspot arguments are
+the descriptor values in order. For example, the descriptor [("remote_address", "10.0.0.1")] of domain "mesh"
+is checked as the resource "mesh:remote_address" with the argument "10.0.0.1", so that a flow rule of
+the resource limits all the remote addresses together, and a hotspot rule (ParamIndex 0) limits each of them.
+Users may provide customized resource extractor via WithResourceExtractor option.
+
+The messages are the wire-compatible subset of the protocol that Sentinel makes use of,
+so the generated stubs of go-control-plane are not needed.
+*/
+package envoyrls
@@ -0,0 +1,44 @@
+package envoyrls
+
+import (
+	"strings"
+)
+
+type (
+	Option  func(*options)
+	options struct {
+		resourceExtract func(domain string, descriptor *RateLimitDescriptor) (string, []interface{})
+	}
+)
+
+func evaluateOptions(opts []Option) *options {
+	optCopy := &options{
+		resourceExtract: defaultResourceOf,
+	}
+	for _, opt := range opts {
+		opt(optCopy)
+	}
+	return optCopy
+}
+
+// WithResourceExtractor sets the extractor of the Sentinel resource name and the hotspot arguments of the descriptor.
+// By default, the resource is the domain followed by the descriptor keys, e.g. "mesh:remote_address.path",
+// and the arguments are the descriptor values in order.
+func WithResourceExtractor(fn func(domain string, descriptor *RateLimitDescriptor) (string, []interface{})) Option {
+	return func(opts *options) {
+		opts.resourceExtract = fn
+	}
+}
+
+func defaultResourceOf(domain string, descriptor *RateLimitDescriptor) (string, []interface{}) {
+	keys := make([]string, 0, len(descriptor.Entries))
+	args := make([]interface{}, 0, len(descriptor.Entries))
+	for _, entry := range descriptor.Entries {
+		if entry == nil {
+			continue
+		}
+		keys = append(keys, entry.Key)
+		args = append(args, entry.Value)
+	}
+	return domain + ":" + strings.Join(keys, "."), args
+}
@@ -0,0 +1,89 @@
+package envoyrls
+
+import (
+	"github.com/golang/protobuf/proto"
+)
+
+// The messages below are the wire-compatible subset of the Envoy rate limit service protocol
+// (envoy/service/ratelimit/v3/rls.proto and envoy/extensions/common/ratelimit/v3/ratelimit.proto),
+// so that the service could be served without depending on the generated stubs of go-control-plane.
+// The fields that Sentinel doesn't make use of are skipped when unmarshalling.
+
+// Code is the rate limit code of the whole request or a single descriptor.
+type Code int32
+
+const (
+	// CodeUnknown means the response code is not known.
+	CodeUnknown Code = 0
+	// CodeOK means the request is not over limit.
+	CodeOK Code = 1
+	// CodeOverLimit means the request is over limit.
+	CodeOverLimit Code = 2
+)
+
+var codeNames = map[Code]string{
+	CodeUnknown:   "UNKNOWN",
+	CodeOK:        "OK",
+	CodeOverLimit: "OVER_LIMIT",
+}
+
+func (c Code) String() string {
+	if name, ok := codeNames[c]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+// RateLimitRequest is the request of RateLimitService.ShouldRateLimit.
+type RateLimitRequest struct {
+	// Domain is the namespace of the descriptors.
+	Domain string `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	// Descriptors are checked independently, the request is over limit if any of them is over limit.
+	Descriptors []*RateLimitDescriptor `protobuf:"bytes,2,rep,name=descriptors,proto3" json:"descriptors,omitempty"`
+	// HitsAddend is the number of hits of the request, 1 if absent.
+	HitsAddend uint32 `protobuf:"varint,3,opt,name=hits_addend,json=hitsAddend,proto3" json:"hits_addend,omitempty"`
+}
+
+func (m *RateLimitRequest) Reset()         { *m = RateLimitRequest{} }
+func (m *RateLimitRequest) String() string { return proto.CompactTextString(m) }
+func (*RateLimitRequest) ProtoMessage()    {}
+
+// RateLimitDescriptor is an ordered list of the descriptor entries, e.g. [("remote_address", "10.0.0.1"), ("path", "/foo")].
+type RateLimitDescriptor struct {
+	Entries []*RateLimitDescriptor_Entry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (m *RateLimitDescriptor) Reset()         { *m = RateLimitDescriptor{} }
+func (m *RateLimitDescriptor) String() string { return proto.CompactTextString(m) }
+func (*RateLimitDescriptor) ProtoMessage()    {}
+
+// RateLimitDescriptor_Entry is a key-value pair of the descriptor.
+type RateLimitDescriptor_Entry struct {
+	Key   string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *RateLimitDescriptor_Entry) Reset()         { *m = RateLimitDescriptor_Entry{} }
+func (m *RateLimitDescriptor_Entry) String() string { return proto.CompactTextString(m) }
+func (*RateLimitDescriptor_Entry) ProtoMessage()    {}
+
+// RateLimitResponse is the response of RateLimitService.ShouldRateLimit.
+type RateLimitResponse struct {
+	// OverallCode is CodeOverLimit if any of the descriptors is over limit.
+	OverallCode Code `protobuf:"varint,1,opt,name=overall_code,json=overallCode,proto3,enum=envoy.service.ratelimit.v3.RateLimitResponse_Code" json:"overall_code,omitempty"`
+	// Statuses are the results of the descriptors, in the same order of the request.
+	Statuses []*RateLimitResponse_DescriptorStatus `protobuf:"bytes,2,rep,name=statuses,proto3" json:"statuses,omitempty"`
+}
+
+func (m *RateLimitResponse) Reset()         { *m = RateLimitResponse{} }
+func (m *RateLimitResponse) String() string { return proto.CompactTextString(m) }
+func (*RateLimitResponse) ProtoMessage()    {}
+
+// RateLimitResponse_DescriptorStatus is the result of a single descriptor.
+type RateLimitResponse_DescriptorStatus struct {
+	Code Code `protobuf:"varint,1,opt,name=code,proto3,enum=envoy.service.ratelimit.v3.RateLimitResponse_Code" json:"code,omitempty"`
+}
+
+func (m *RateLimitResponse_DescriptorStatus) Reset()         { *m = RateLimitResponse_DescriptorStatus{} }
+func (m *RateLimitResponse_DescriptorStatus) String() string { return proto.CompactTextString(m) }
+func (*RateLimitResponse_DescriptorStatus) ProtoMessage()    {}
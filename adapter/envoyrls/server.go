@@ -0,0 +1,124 @@
+package envoyrls
+
+import (
+	"context"
+
+	sentinel "github.com/alibaba/sentinel-golang/api"
+	"github.com/alibaba/sentinel-golang/core/base"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RateLimitServiceName is the full name of the Envoy rate limit service (v3).
+const RateLimitServiceName = "envoy.service.ratelimit.v3.RateLimitService"
+
+// Register registers the Envoy rate limit service backed by Sentinel to the gRPC server, so that current process
+// could serve as the global rate limit service of Envoy (or Istio). Every descriptor of the request is checked
+// as a Sentinel entry, and the flow and hotspot rules of the resources are managed as those of in-process usage.
+func Register(s *grpc.Server, opts ...Option) {
+	s.RegisterService(&rateLimitServiceDesc, NewServer(opts...))
+}
+
+// Server implements the Envoy rate limit service.
+type Server struct {
+	options *options
+}
+
+func NewServer(opts ...Option) *Server {
+	return &Server{options: evaluateOptions(opts)}
+}
+
+// ShouldRateLimit checks the descriptors of the request independently,
+// and the request is over limit if any of the descriptors is over limit.
+func (s *Server) ShouldRateLimit(_ context.Context, req *RateLimitRequest) (*RateLimitResponse, error) {
+	if req == nil || len(req.Domain) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "rate limit domain must not be empty")
+	}
+	hits := req.HitsAddend
+	if hits == 0 {
+		hits = 1
+	}
+	resp := &RateLimitResponse{
+		OverallCode: CodeOK,
+		Statuses:    make([]*RateLimitResponse_DescriptorStatus, 0, len(req.Descriptors)),
+	}
+	for _, descriptor := range req.Descriptors {
+		code := CodeOK
+		if descriptor != nil && s.isBlocked(req.Domain, descriptor, hits) {
+			code = CodeOverLimit
+			resp.OverallCode = CodeOverLimit
+		}
+		resp.Statuses = append(resp.Statuses, &RateLimitResponse_DescriptorStatus{Code: code})
+	}
+	return resp, nil
+}
+
+func (s *Server) isBlocked(domain string, descriptor *RateLimitDescriptor, hits uint32) bool {
+	resource, args := s.options.resourceExtract(domain, descriptor)
+	entry, blockErr := sentinel.Entry(
+		resource,
+		sentinel.WithResourceType(base.ResTypeRPC),
+		sentinel.WithTrafficType(base.Inbound),
+		sentinel.WithAcquireCount(hits),
+		sentinel.WithArgs(args...),
+	)
+	if blockErr != nil {
+		return true
+	}
+	// the rate limit service only counts the hits, the requests are handled by Envoy.
+	entry.Exit()
+	return false
+}
+
+type rateLimitService interface {
+	ShouldRateLimit(ctx context.Context, req *RateLimitRequest) (*RateLimitResponse, error)
+}
+
+func shouldRateLimitHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RateLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(rateLimitService).ShouldRateLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + RateLimitServiceName + "/ShouldRateLimit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(rateLimitService).ShouldRateLimit(ctx, req.(*RateLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var rateLimitServiceDesc = grpc.ServiceDesc{
+	ServiceName: RateLimitServiceName,
+	HandlerType: (*rateLimitService)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ShouldRateLimit",
+			Handler:    shouldRateLimitHandler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "envoy/service/ratelimit/v3/rls.proto",
+}
+
+// Client is the client of the Envoy rate limit service.
+type Client struct {
+	cc *grpc.ClientConn
+}
+
+func NewClient(cc *grpc.ClientConn) *Client {
+	return &Client{cc: cc}
+}
+
+func (c *Client) ShouldRateLimit(ctx context.Context, req *RateLimitRequest, opts ...grpc.CallOption) (*RateLimitResponse, error) {
+	out := new(RateLimitResponse)
+	if err := c.cc.Invoke(ctx, "/"+RateLimitServiceName+"/ShouldRateLimit", req, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
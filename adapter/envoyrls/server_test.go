@@ -0,0 +1,135 @@
+package envoyrls
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	sentinel "github.com/alibaba/sentinel-golang/api"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/hotspot"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newClient(t *testing.T, opts ...Option) (*Client, func()) {
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	Register(s, opts...)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	assert.Nil(t, err)
+	return NewClient(conn), func() {
+		_ = conn.Close()
+		s.Stop()
+	}
+}
+
+func descriptorOf(kvs ...string) *RateLimitDescriptor {
+	d := &RateLimitDescriptor{}
+	for i := 0; i+1 < len(kvs); i += 2 {
+		d.Entries = append(d.Entries, &RateLimitDescriptor_Entry{Key: kvs[i], Value: kvs[i+1]})
+	}
+	return d
+}
+
+func TestShouldRateLimit(t *testing.T) {
+	if err := sentinel.InitDefault(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	client, stop := newClient(t)
+	defer stop()
+	defer flow.ClearRules()
+	defer hotspot.ClearRules()
+
+	_, err := flow.LoadRules([]*flow.Rule{
+		{
+			Resource:               "mesh:path",
+			Threshold:              2,
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			StatIntervalInMs:       1000,
+		},
+	})
+	assert.Nil(t, err)
+	_, err = hotspot.LoadRules([]*hotspot.Rule{
+		{
+			Resource:        "mesh:remote_address",
+			MetricType:      hotspot.QPS,
+			ControlBehavior: hotspot.Reject,
+			ParamIndex:      0,
+			Threshold:       1,
+			DurationInSec:   1,
+		},
+	})
+	assert.Nil(t, err)
+
+	t.Run("Flow", func(t *testing.T) {
+		req := &RateLimitRequest{Domain: "mesh", Descriptors: []*RateLimitDescriptor{descriptorOf("path", "/foo")}}
+		resp, err := client.ShouldRateLimit(context.Background(), req)
+		assert.Nil(t, err)
+		assert.Equal(t, CodeOK, resp.OverallCode)
+		assert.Equal(t, 1, len(resp.Statuses))
+		assert.Equal(t, CodeOK, resp.Statuses[0].Code)
+
+		// the hits addend is counted as the batch count
+		req.HitsAddend = 2
+		resp, err = client.ShouldRateLimit(context.Background(), req)
+		assert.Nil(t, err)
+		assert.Equal(t, CodeOverLimit, resp.OverallCode)
+		assert.Equal(t, CodeOverLimit, resp.Statuses[0].Code)
+	})
+
+	t.Run("Hotspot", func(t *testing.T) {
+		req := &RateLimitRequest{
+			Domain: "mesh",
+			Descriptors: []*RateLimitDescriptor{
+				descriptorOf("remote_address", "10.0.0.1"),
+				descriptorOf("remote_address", "10.0.0.2"),
+			},
+		}
+		resp, err := client.ShouldRateLimit(context.Background(), req)
+		assert.Nil(t, err)
+		assert.Equal(t, CodeOK, resp.OverallCode)
+
+		req.Descriptors = req.Descriptors[:1]
+		resp, err = client.ShouldRateLimit(context.Background(), req)
+		assert.Nil(t, err)
+		assert.Equal(t, CodeOverLimit, resp.OverallCode)
+		assert.Equal(t, CodeOverLimit, resp.Statuses[0].Code)
+
+		// each descriptor has its own status
+		req.Descriptors = append(req.Descriptors, descriptorOf("unlimited", "x"))
+		resp, err = client.ShouldRateLimit(context.Background(), req)
+		assert.Nil(t, err)
+		assert.Equal(t, CodeOverLimit, resp.OverallCode)
+		assert.Equal(t, CodeOverLimit, resp.Statuses[0].Code)
+		assert.Equal(t, CodeOK, resp.Statuses[1].Code)
+	})
+
+	t.Run("EmptyDomain", func(t *testing.T) {
+		_, err := client.ShouldRateLimit(context.Background(), &RateLimitRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestWithResourceExtractor(t *testing.T) {
+	opts := evaluateOptions(nil)
+	resource, args := opts.resourceExtract("mesh", descriptorOf("remote_address", "10.0.0.1", "path", "/foo"))
+	assert.Equal(t, "mesh:remote_address.path", resource)
+	assert.Equal(t, []interface{}{"10.0.0.1", "/foo"}, args)
+
+	opts = evaluateOptions([]Option{WithResourceExtractor(func(domain string, d *RateLimitDescriptor) (string, []interface{}) {
+		return d.Entries[0].Value, nil
+	})})
+	resource, args = opts.resourceExtract("mesh", descriptorOf("path", "/foo"))
+	assert.Equal(t, "/foo", resource)
+	assert.Nil(t, args)
+}
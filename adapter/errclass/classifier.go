@@ -0,0 +1,127 @@
+// Package errclass classifies the HTTP responses as errors for the adapters, so that the circuit breakers
+// open on the failures of the applications, including the ones hidden behind HTTP 200 with an error envelope
+// in the JSON body, e.g.
+//
+//	{"code": "INTERNAL_ERROR", "message": "db timeout", "data": null}
+//
+// The body is sniffed only if the classifier needs it (see Classifier), and at most MaxSniffBytes of it
+// is kept, so the large responses are not buffered.
+package errclass
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MaxSniffBytes is the max bytes of the response body kept for the classification.
+const MaxSniffBytes = 4096
+
+// Classifier classifies the response by the status code and the sniffed body, and returns the error traced
+// by the entry of the request, nil means the response is not an error.
+type Classifier struct {
+	// Classify classifies the response, the body is nil if SniffBody is false.
+	Classify func(statusCode int, body []byte) error
+	// SniffBody indicates whether the response body is needed by Classify.
+	SniffBody bool
+}
+
+// StatusClassifier classifies the responses with the status code not less than minStatusCode
+// (e.g. 500) as errors, without sniffing the body.
+func StatusClassifier(minStatusCode int) *Classifier {
+	return &Classifier{
+		Classify: func(statusCode int, _ []byte) error {
+			return statusError(statusCode, minStatusCode)
+		},
+	}
+}
+
+// JSONEnvelopeClassifier classifies the responses with the status code not less than minStatusCode as errors,
+// and the other responses with the JSON body whose codeField (e.g. "code", or "error.code" for the nested field)
+// is present and not one of the successCodes (e.g. "0", "OK") as errors. The message of the error is taken from
+// the messageField (optional). The bodies which are not JSON objects, or truncated by MaxSniffBytes,
+// are not classified as errors.
+func JSONEnvelopeClassifier(minStatusCode int, codeField, messageField string, successCodes ...string) *Classifier {
+	success := make(map[string]struct{}, len(successCodes))
+	for _, c := range successCodes {
+		success[c] = struct{}{}
+	}
+	return &Classifier{
+		Classify: func(statusCode int, body []byte) error {
+			if err := statusError(statusCode, minStatusCode); err != nil {
+				return err
+			}
+			var envelope map[string]interface{}
+			if len(body) == 0 || json.Unmarshal(body, &envelope) != nil {
+				return nil
+			}
+			code, ok := lookupField(envelope, codeField)
+			if !ok || code == nil {
+				return nil
+			}
+			codeStr := fmt.Sprint(code)
+			if _, ok := success[codeStr]; ok {
+				return nil
+			}
+			if msg, ok := lookupField(envelope, messageField); ok && msg != nil {
+				return errors.Errorf("error response with code %s: %v", codeStr, msg)
+			}
+			return errors.Errorf("error response with code %s", codeStr)
+		},
+		SniffBody: true,
+	}
+}
+
+func statusError(statusCode, minStatusCode int) error {
+	if minStatusCode > 0 && statusCode >= minStatusCode {
+		return errors.Errorf("error response with status %d %s", statusCode, http.StatusText(statusCode))
+	}
+	return nil
+}
+
+// lookupField looks up the field in the JSON object, the nested field is separated by ".".
+func lookupField(obj map[string]interface{}, field string) (interface{}, bool) {
+	if len(field) == 0 {
+		return nil, false
+	}
+	keys := strings.Split(field, ".")
+	for i, k := range keys {
+		v, ok := obj[k]
+		if !ok {
+			return nil, false
+		}
+		if i == len(keys)-1 {
+			return v, true
+		}
+		if obj, ok = v.(map[string]interface{}); !ok {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// Sniffer keeps the first MaxSniffBytes of the response body written.
+type Sniffer struct {
+	buf []byte
+}
+
+// Write keeps the bytes within MaxSniffBytes, it never fails.
+func (s *Sniffer) Write(p []byte) {
+	if remaining := MaxSniffBytes - len(s.buf); remaining > 0 {
+		if len(p) > remaining {
+			p = p[:remaining]
+		}
+		s.buf = append(s.buf, p...)
+	}
+}
+
+// Bytes returns the sniffed body, nil if the Sniffer is nil.
+func (s *Sniffer) Bytes() []byte {
+	if s == nil {
+		return nil
+	}
+	return s.buf
+}
@@ -0,0 +1,47 @@
+package errclass
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusClassifier(t *testing.T) {
+	c := StatusClassifier(500)
+	assert.False(t, c.SniffBody)
+	assert.Nil(t, c.Classify(200, nil))
+	assert.Nil(t, c.Classify(404, nil))
+	assert.EqualError(t, c.Classify(503, nil), "error response with status 503 Service Unavailable")
+}
+
+func TestJSONEnvelopeClassifier(t *testing.T) {
+	c := JSONEnvelopeClassifier(500, "code", "message", "0", "OK")
+	assert.True(t, c.SniffBody)
+
+	assert.Nil(t, c.Classify(200, []byte(`{"code": 0, "data": {}}`)))
+	assert.Nil(t, c.Classify(200, []byte(`{"code": "OK"}`)))
+	assert.Nil(t, c.Classify(200, []byte(`{"data": {}}`)))
+	assert.Nil(t, c.Classify(200, []byte(`[1, 2]`)))
+	assert.Nil(t, c.Classify(200, []byte(`not json`)))
+	assert.Nil(t, c.Classify(200, nil))
+	assert.EqualError(t, c.Classify(200, []byte(`{"code": 5001, "message": "db timeout"}`)), "error response with code 5001: db timeout")
+	assert.EqualError(t, c.Classify(200, []byte(`{"code": "INTERNAL"}`)), "error response with code INTERNAL")
+	assert.NotNil(t, c.Classify(500, []byte(`{"code": 0}`)))
+
+	nested := JSONEnvelopeClassifier(0, "error.code", "error.message")
+	assert.Nil(t, nested.Classify(500, []byte(`{"data": 1}`)))
+	assert.Nil(t, nested.Classify(200, []byte(`{"error": null}`)))
+	assert.EqualError(t, nested.Classify(200, []byte(`{"error": {"code": "E1", "message": "bad"}}`)), "error response with code E1: bad")
+}
+
+func TestSniffer(t *testing.T) {
+	s := &Sniffer{}
+	s.Write([]byte("abc"))
+	assert.Equal(t, "abc", string(s.Bytes()))
+	s.Write([]byte(strings.Repeat("x", MaxSniffBytes)))
+	assert.Equal(t, MaxSniffBytes, len(s.Bytes()))
+	s.Write([]byte("y"))
+	assert.Equal(t, MaxSniffBytes, len(s.Bytes()))
+	assert.Equal(t, "abcxx", string(s.Bytes()[:5]))
+}
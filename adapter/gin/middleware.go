@@ -1,42 +1,122 @@
 package gin
 
 import (
-	"net/http"
-
+	"github.com/alibaba/sentinel-golang/adapter/errclass"
+	"github.com/alibaba/sentinel-golang/adapter/route"
+	"github.com/alibaba/sentinel-golang/adapter/tier"
 	sentinel "github.com/alibaba/sentinel-golang/api"
 	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/flow"
 	"github.com/gin-gonic/gin"
 )
 
 // SentinelMiddleware returns new gin.HandlerFunc
 // Default resource name is {method}:{path}, such as "GET:/api/users/:id"
-// Default block fallback is returning 429 code
-// Define your own behavior by setting options
+// Default block fallback is returning 429 code, which could be customized per block type by WithBlockStatusCodes
+// Define your own behavior by setting options, or override per route by WithRouteConfig
 func SentinelMiddleware(opts ...Option) gin.HandlerFunc {
 	options := evaluateOptions(opts)
 	return func(c *gin.Context) {
+		if options.exclusions.ExcludesRequest(c.Request) {
+			c.Next()
+			return
+		}
 		resourceName := c.Request.Method + ":" + c.FullPath()
 
 		if options.resourceExtract != nil {
 			resourceName = options.resourceExtract(c)
 		}
 
-		entry, err := sentinel.Entry(
-			resourceName,
+		var settings route.Settings
+		if options.routeConfig != nil {
+			s, guarded := options.routeConfig.Resolve(c.Request.Method, c.FullPath())
+			if !guarded {
+				c.Next()
+				return
+			}
+			if len(s.Resource) > 0 {
+				resourceName = s.Resource
+			}
+			settings = s
+		}
+
+		entryOpts := []sentinel.EntryOption{
 			sentinel.WithResourceType(base.ResTypeWeb),
 			sentinel.WithTrafficType(base.Inbound),
-		)
+		}
+		if origin := options.originOf(c, settings.OriginHeader); len(origin) > 0 {
+			entryOpts = append(entryOpts, sentinel.WithOrigin(origin))
+		}
+		if len(settings.Tags) > 0 {
+			entryOpts = append(entryOpts, sentinel.WithTags(settings.Tags...))
+		}
+		if options.clientIP != nil {
+			entryOpts = append(entryOpts, sentinel.WithArgs(options.clientIP.ClientIP(c.Request)))
+		}
+		if options.tierResolver != nil {
+			var param interface{}
+			if apiKey := c.GetHeader(options.apiKeyHeader); len(apiKey) > 0 {
+				param = tier.ParamOf(apiKey, options.tierResolver)
+			}
+			entryOpts = append(entryOpts, sentinel.WithArgs(param))
+		}
+		if c.Request.ContentLength > 0 {
+			// report the payload size for the Throughput flow rules
+			entryOpts = append(entryOpts, sentinel.WithAttachment(flow.PayloadBytesAttachmentKey, c.Request.ContentLength))
+		}
+		entry, err := sentinel.Entry(resourceName, entryOpts...)
 
 		if err != nil {
 			if options.blockFallback != nil {
 				options.blockFallback(c)
 			} else {
-				c.AbortWithStatus(http.StatusTooManyRequests)
+				code := options.blockStatusCodeOf(err.BlockType())
+				if settings.BlockStatusCode > 0 {
+					code = settings.BlockStatusCode
+				}
+				if len(settings.BlockMessage) > 0 {
+					c.String(code, settings.BlockMessage)
+					c.Abort()
+				} else {
+					c.AbortWithStatus(code)
+				}
 			}
 			return
 		}
 
 		defer entry.Exit()
+		if options.errorClassifier == nil {
+			c.Next()
+			return
+		}
+		var sniffer *errclass.Sniffer
+		if options.errorClassifier.SniffBody {
+			sniffer = &errclass.Sniffer{}
+			w := c.Writer
+			c.Writer = &sniffingWriter{ResponseWriter: w, sniffer: sniffer}
+			defer func() {
+				c.Writer = w
+			}()
+		}
 		c.Next()
+		if err := options.errorClassifier.Classify(c.Writer.Status(), sniffer.Bytes()); err != nil {
+			sentinel.TraceError(entry, err)
+		}
 	}
 }
+
+// sniffingWriter sniffs the response body for the error classifier.
+type sniffingWriter struct {
+	gin.ResponseWriter
+	sniffer *errclass.Sniffer
+}
+
+func (w *sniffingWriter) Write(b []byte) (int, error) {
+	w.sniffer.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *sniffingWriter) WriteString(s string) (int, error) {
+	w.sniffer.Write([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
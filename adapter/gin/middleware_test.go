@@ -6,8 +6,14 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/alibaba/sentinel-golang/adapter/errclass"
+	"github.com/alibaba/sentinel-golang/adapter/route"
+	"github.com/alibaba/sentinel-golang/adapter/tier"
 	sentinel "github.com/alibaba/sentinel-golang/api"
+	"github.com/alibaba/sentinel-golang/core/base"
 	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/hotspot"
+	"github.com/alibaba/sentinel-golang/core/stat"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
@@ -94,6 +100,70 @@ func TestSentinelMiddleware(t *testing.T) {
 					code: http.StatusTooManyRequests,
 				},
 			},
+			{
+				name: "customize block status code",
+				args: args{
+					opts: []Option{
+						WithResourceExtractor(func(ctx *gin.Context) string {
+							return ctx.FullPath()
+						}),
+						WithBlockStatusCodes(map[base.BlockType]int{base.BlockTypeFlow: http.StatusServiceUnavailable}),
+					},
+					method:  http.MethodPost,
+					path:    "/api/users/:id",
+					reqPath: "/api/users/123",
+					handler: func(ctx *gin.Context) {
+						ctx.String(http.StatusOK, "ping")
+					},
+					body: nil,
+				},
+				want: want{
+					code: http.StatusServiceUnavailable,
+				},
+			},
+			{
+				name: "route config override",
+				args: args{
+					opts: []Option{
+						WithRouteConfig(&route.Config{
+							Routes: []*route.Route{
+								{Path: "/api/users/*", Resource: "/api/users/:id", BlockStatusCode: http.StatusServiceUnavailable},
+							},
+						}),
+					},
+					method:  http.MethodPost,
+					path:    "/api/users/:id",
+					reqPath: "/api/users/123",
+					handler: func(ctx *gin.Context) {
+						ctx.String(http.StatusOK, "ping")
+					},
+					body: nil,
+				},
+				want: want{
+					code: http.StatusServiceUnavailable,
+				},
+			},
+			{
+				name: "route config exclusion",
+				args: args{
+					opts: []Option{
+						WithResourceExtractor(func(ctx *gin.Context) string {
+							return ctx.FullPath()
+						}),
+						WithRouteConfig(&route.Config{ExcludePaths: []string{"/api/users/*"}}),
+					},
+					method:  http.MethodPost,
+					path:    "/api/users/:id",
+					reqPath: "/api/users/123",
+					handler: func(ctx *gin.Context) {
+						ctx.String(http.StatusOK, "ping")
+					},
+					body: nil,
+				},
+				want: want{
+					code: http.StatusOK,
+				},
+			},
 			{
 				name: "customize block fallback",
 				args: args{
@@ -131,3 +201,166 @@ func TestSentinelMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestSentinelMiddlewareWithClientIPParam(t *testing.T) {
+	initSentinel(t)
+	_, err := hotspot.LoadRules([]*hotspot.Rule{
+		{
+			Resource:        "GET:/ip",
+			MetricType:      hotspot.QPS,
+			ControlBehavior: hotspot.Reject,
+			ParamIndex:      0,
+			Threshold:       1,
+			DurationInSec:   1,
+		},
+	})
+	assert.NoError(t, err)
+	defer hotspot.ClearRules()
+
+	router := gin.New()
+	// httptest.NewRequest comes from 192.0.2.1
+	router.Use(SentinelMiddleware(WithClientIPParam("192.0.2.0/24")))
+	router.GET("/ip", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, "ip")
+	})
+	request := func(clientIP string) int {
+		r := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		r.Header.Set("X-Forwarded-For", clientIP)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("1.1.1.1"))
+	assert.Equal(t, http.StatusTooManyRequests, request("1.1.1.1"))
+	// the other clients are limited separately
+	assert.Equal(t, http.StatusOK, request("2.2.2.2"))
+}
+
+func TestSentinelMiddlewareWithAPIKeyTierParam(t *testing.T) {
+	initSentinel(t)
+	_, err := hotspot.LoadRules([]*hotspot.Rule{
+		{
+			Resource:        "GET:/tier",
+			MetricType:      hotspot.QPS,
+			ControlBehavior: hotspot.Reject,
+			ParamIndex:      0,
+			Threshold:       1,
+			DurationInSec:   1,
+			TierThresholds:  map[string]int64{"pro": 3},
+		},
+	})
+	assert.NoError(t, err)
+	defer hotspot.ClearRules()
+
+	plans := map[string]string{"free-key": "free", "pro-key": "pro"}
+	router := gin.New()
+	router.Use(SentinelMiddleware(WithAPIKeyTierParam("X-API-Key", tier.ResolverFunc(func(apiKey string) (string, error) {
+		return plans[apiKey], nil
+	}))))
+	router.GET("/tier", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, "tier")
+	})
+	passed := func(apiKey string) int {
+		ret := 0
+		for i := 0; i < 5; i++ {
+			r := httptest.NewRequest(http.MethodGet, "/tier", nil)
+			r.Header.Set("X-API-Key", apiKey)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			if w.Code == http.StatusOK {
+				ret++
+			}
+		}
+		return ret
+	}
+
+	assert.Equal(t, 1, passed("free-key"))
+	assert.Equal(t, 3, passed("pro-key"))
+	// the requests without the API key are not limited
+	assert.Equal(t, 5, passed(""))
+}
+
+func TestOriginOfWithBaggageOrigin(t *testing.T) {
+	newContext := func(header map[string]string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		for k, v := range header {
+			c.Request.Header.Set(k, v)
+		}
+		return c
+	}
+	opts := evaluateOptions([]Option{WithBaggageOrigin()})
+
+	// the origin header takes precedence
+	c := newContext(map[string]string{"X-Origin": "app-a", "baggage": "origin=app-b"})
+	assert.Equal(t, "app-a", opts.originOf(c, "X-Origin"))
+	c = newContext(map[string]string{"baggage": "origin=app-b"})
+	assert.Equal(t, "app-b", opts.originOf(c, "X-Origin"))
+	assert.Equal(t, "app-b", opts.originOf(c, ""))
+	assert.Equal(t, "", evaluateOptions(nil).originOf(c, "X-Origin"))
+}
+
+func TestSentinelMiddlewareWithErrorClassifier(t *testing.T) {
+	initSentinel(t)
+
+	router := gin.New()
+	router.Use(SentinelMiddleware(WithErrorClassifier(errclass.JSONEnvelopeClassifier(500, "code", "message", "0"))))
+	router.GET("/envelope", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"code": ctx.Query("code"), "message": "failed"})
+	})
+	router.GET("/fail", func(ctx *gin.Context) {
+		ctx.String(http.StatusBadGateway, "bad gateway")
+	})
+	request := func(path string) string {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w.Body.String()
+	}
+	errorsOf := func(res string) int64 {
+		return stat.GetResourceNode(res).GetSum(base.MetricEventError)
+	}
+
+	assert.JSONEq(t, `{"code":"0","message":"failed"}`, request("/envelope?code=0"))
+	assert.Equal(t, int64(0), errorsOf("GET:/envelope"))
+	// the error hidden behind HTTP 200 is traced, and the response is untouched
+	assert.JSONEq(t, `{"code":"5001","message":"failed"}`, request("/envelope?code=5001"))
+	assert.Equal(t, int64(1), errorsOf("GET:/envelope"))
+	assert.Equal(t, "bad gateway", request("/fail"))
+	assert.Equal(t, int64(1), errorsOf("GET:/fail"))
+}
+
+func TestSentinelMiddlewareWithExclusions(t *testing.T) {
+	initSentinel(t)
+	_, err := flow.LoadRules([]*flow.Rule{
+		{Resource: "GET:/healthz", Threshold: 0, StatIntervalInMs: 1000},
+		{Resource: "OPTIONS:/api", Threshold: 0, StatIntervalInMs: 1000},
+	})
+	assert.NoError(t, err)
+	defer flow.ClearRules()
+
+	exclusions, err := route.NewExclusions(route.CommonExcludedPaths, true)
+	assert.NoError(t, err)
+	router := gin.New()
+	router.Use(SentinelMiddleware(WithExclusions(exclusions)))
+	router.GET("/healthz", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, "ok")
+	})
+	router.OPTIONS("/api", func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+	request := func(r *http.Request) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request(httptest.NewRequest(http.MethodGet, "/healthz", nil)))
+	node := stat.GetResourceNode("GET:/healthz")
+	assert.Equal(t, int64(0), node.GetSum(base.MetricEventPass)+node.GetSum(base.MetricEventBlock), "the excluded requests should not be counted")
+	preflight := httptest.NewRequest(http.MethodOptions, "/api", nil)
+	preflight.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	assert.Equal(t, http.StatusNoContent, request(preflight))
+	assert.Equal(t, http.StatusTooManyRequests, request(httptest.NewRequest(http.MethodOptions, "/api", nil)))
+}
@@ -0,0 +1,302 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/chaos"
+	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
+	"github.com/alibaba/sentinel-golang/core/composite"
+	"github.com/alibaba/sentinel-golang/core/errorbudget"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/hotspot"
+	"github.com/alibaba/sentinel-golang/core/isolation"
+	"github.com/alibaba/sentinel-golang/core/outlier"
+	"github.com/alibaba/sentinel-golang/core/policy"
+	"github.com/alibaba/sentinel-golang/core/quota"
+	"github.com/alibaba/sentinel-golang/core/retry"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	"github.com/alibaba/sentinel-golang/core/system"
+	"github.com/alibaba/sentinel-golang/ext/ruleconv"
+	"github.com/golang/protobuf/jsonpb"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DebugServiceName is the full name of the Sentinel debug service.
+// All the methods take a google.protobuf.Struct as the request and return a google.protobuf.Struct,
+// so that the service could be called by any gRPC tooling without generated stubs:
+//
+//  1. GetEffectiveRules: optional request field "module" (e.g. "flow"), returns the rules keyed by module.
+//  2. GetResourceStats: optional request field "resource", returns the "resources" statistics.
+//  3. GetBreakerStates: optional request field "resource", returns the "breakers" states.
+//  4. ConvertRules: request fields "module" (e.g. "flow"), "from" and "to" (either "go" or "java") and "rules"
+//     (the JSON array of the rules), returns the converted "rules" JSON, see package ruleconv.
+//  5. GetSelfMetrics: returns the "timers" and "gauges" of Sentinel's own overhead, see package selfmetric.
+//  6. UpdateChaos: optional request fields "enabled" (bool) to toggle the fault injection and "rules" (the JSON array
+//     of the chaos rules) to replace the chaos rules, returns current "enabled" and "rules", see package chaos.
+const DebugServiceName = "sentinel.debug.DebugService"
+
+// RegisterDebugService registers the Sentinel debug service to the gRPC server, which exposes the effective rules,
+// resource statistics and circuit breaker states of current instance. It complements the HTTP command API
+// for gRPC-only environments.
+func RegisterDebugService(s *grpc.Server) {
+	s.RegisterService(&debugServiceDesc, &debugServer{})
+}
+
+type debugService interface {
+	GetEffectiveRules(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	GetResourceStats(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	GetBreakerStates(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	ConvertRules(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	GetSelfMetrics(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	UpdateChaos(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+}
+
+type debugServer struct {
+}
+
+func (s *debugServer) GetEffectiveRules(_ context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	getters := map[string]func() interface{}{
+		"flow":           func() interface{} { return flow.GetRules() },
+		"circuitbreaker": func() interface{} { return circuitbreaker.GetRules() },
+		"hotspot":        func() interface{} { return hotspot.GetRules() },
+		"isolation":      func() interface{} { return isolation.GetRules() },
+		"system":         func() interface{} { return system.GetRules() },
+		"outlier":        func() interface{} { return outlier.GetRules() },
+		"retry":          func() interface{} { return retry.GetRules() },
+		"quota":          func() interface{} { return quota.GetRules() },
+		"policy":         func() interface{} { return policy.GetRules() },
+		"errorbudget":    func() interface{} { return errorbudget.GetRules() },
+		"composite":      func() interface{} { return composite.GetRules() },
+		"chaos":          func() interface{} { return chaos.GetRules() },
+	}
+	ret := make(map[string]interface{})
+	if module := stringField(req, "module"); len(module) > 0 {
+		getter, ok := getters[module]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown rule module: %s", module)
+		}
+		ret[module] = getter()
+	} else {
+		for module, getter := range getters {
+			ret[module] = getter()
+		}
+	}
+	return toStruct(ret)
+}
+
+// ResourceStat is the statistics of a resource in GetResourceStats response.
+type ResourceStat struct {
+	Resource    string  `json:"resource"`
+	PassQps     float64 `json:"passQps"`
+	BlockQps    float64 `json:"blockQps"`
+	CompleteQps float64 `json:"completeQps"`
+	ErrorQps    float64 `json:"errorQps"`
+	AvgRt       float64 `json:"avgRt"`
+	AvgRtUs     float64 `json:"avgRtUs"`
+	Concurrency int32   `json:"concurrency"`
+}
+
+func (s *debugServer) GetResourceStats(_ context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	resource := stringField(req, "resource")
+	stats := make([]*ResourceStat, 0)
+	for _, n := range stat.ResourceNodeList() {
+		if len(resource) > 0 && n.ResourceName() != resource {
+			continue
+		}
+		stats = append(stats, &ResourceStat{
+			Resource:    n.ResourceName(),
+			PassQps:     n.GetQPS(base.MetricEventPass),
+			BlockQps:    n.GetQPS(base.MetricEventBlock),
+			CompleteQps: n.GetQPS(base.MetricEventComplete),
+			ErrorQps:    n.GetQPS(base.MetricEventError),
+			AvgRt:       n.AvgRT(),
+			AvgRtUs:     n.AvgRTMicros(),
+			Concurrency: n.CurrentGoroutineNum(),
+		})
+	}
+	return toStruct(map[string]interface{}{"resources": stats})
+}
+
+// BreakerState is the state of a circuit breaker in GetBreakerStates response.
+type BreakerState struct {
+	Resource string `json:"resource"`
+	RuleId   string `json:"ruleId"`
+	Strategy string `json:"strategy"`
+	State    string `json:"state"`
+}
+
+func (s *debugServer) GetBreakerStates(_ context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	var cbs []circuitbreaker.CircuitBreaker
+	if resource := stringField(req, "resource"); len(resource) > 0 {
+		cbs = circuitbreaker.CircuitBreakersOfResource(resource)
+	} else {
+		cbs = circuitbreaker.CircuitBreakers()
+	}
+	states := make([]*BreakerState, 0, len(cbs))
+	for _, cb := range cbs {
+		rule := cb.BoundRule()
+		state := cb.CurrentState()
+		states = append(states, &BreakerState{
+			Resource: rule.Resource,
+			RuleId:   rule.Id,
+			Strategy: rule.Strategy.String(),
+			State:    state.String(),
+		})
+	}
+	return toStruct(map[string]interface{}{"breakers": states})
+}
+
+func (s *debugServer) ConvertRules(_ context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	module, from, to := stringField(req, "module"), stringField(req, "from"), stringField(req, "to")
+	rules, err := ruleconv.Convert(module, from, to, []byte(stringField(req, "rules")))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "fail to convert the rules: %v", err)
+	}
+	return toStruct(map[string]interface{}{"rules": string(rules)})
+}
+
+func (s *debugServer) GetSelfMetrics(_ context.Context, _ *structpb.Struct) (*structpb.Struct, error) {
+	snapshot := selfmetric.TakeSnapshot()
+	return toStruct(map[string]interface{}{"timers": snapshot.Timers, "gauges": snapshot.Gauges})
+}
+
+func (s *debugServer) UpdateChaos(_ context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	if rulesJson := stringField(req, "rules"); len(rulesJson) > 0 {
+		var rules []*chaos.Rule
+		if err := json.Unmarshal([]byte(rulesJson), &rules); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "fail to parse the chaos rules: %v", err)
+		}
+		if _, err := chaos.LoadRules(rules); err != nil {
+			return nil, status.Errorf(codes.Internal, "fail to load the chaos rules: %v", err)
+		}
+	}
+	if req != nil && req.Fields != nil {
+		if v, ok := req.Fields["enabled"]; ok {
+			chaos.SetEnabled(v.GetBoolValue())
+		}
+	}
+	return toStruct(map[string]interface{}{"enabled": chaos.Enabled(), "rules": chaos.GetRules()})
+}
+
+func stringField(s *structpb.Struct, key string) string {
+	if s == nil || s.Fields == nil {
+		return ""
+	}
+	return s.Fields[key].GetStringValue()
+}
+
+// toStruct converts the JSON object to google.protobuf.Struct.
+func toStruct(v map[string]interface{}) (*structpb.Struct, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "fail to marshal the response: %v", err)
+	}
+	ret := &structpb.Struct{}
+	if err := jsonpb.UnmarshalString(string(b), ret); err != nil {
+		return nil, status.Errorf(codes.Internal, "fail to convert the response: %v", err)
+	}
+	return ret, nil
+}
+
+func debugMethodHandler(call func(debugService, context.Context, *structpb.Struct) (*structpb.Struct, error), fullMethod string) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		in := new(structpb.Struct)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		if interceptor == nil {
+			return call(srv.(debugService), ctx, in)
+		}
+		info := &grpc.UnaryServerInfo{
+			Server:     srv,
+			FullMethod: fullMethod,
+		}
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return call(srv.(debugService), ctx, req.(*structpb.Struct))
+		}
+		return interceptor(ctx, in, info, handler)
+	}
+}
+
+var debugServiceDesc = grpc.ServiceDesc{
+	ServiceName: DebugServiceName,
+	HandlerType: (*debugService)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetEffectiveRules",
+			Handler:    debugMethodHandler(debugService.GetEffectiveRules, "/"+DebugServiceName+"/GetEffectiveRules"),
+		},
+		{
+			MethodName: "GetResourceStats",
+			Handler:    debugMethodHandler(debugService.GetResourceStats, "/"+DebugServiceName+"/GetResourceStats"),
+		},
+		{
+			MethodName: "GetBreakerStates",
+			Handler:    debugMethodHandler(debugService.GetBreakerStates, "/"+DebugServiceName+"/GetBreakerStates"),
+		},
+		{
+			MethodName: "ConvertRules",
+			Handler:    debugMethodHandler(debugService.ConvertRules, "/"+DebugServiceName+"/ConvertRules"),
+		},
+		{
+			MethodName: "GetSelfMetrics",
+			Handler:    debugMethodHandler(debugService.GetSelfMetrics, "/"+DebugServiceName+"/GetSelfMetrics"),
+		},
+		{
+			MethodName: "UpdateChaos",
+			Handler:    debugMethodHandler(debugService.UpdateChaos, "/"+DebugServiceName+"/UpdateChaos"),
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sentinel/debug.proto",
+}
+
+// DebugClient is the client of the Sentinel debug service.
+type DebugClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDebugClient(cc *grpc.ClientConn) *DebugClient {
+	return &DebugClient{cc: cc}
+}
+
+func (c *DebugClient) GetEffectiveRules(ctx context.Context, req *structpb.Struct, opts ...grpc.CallOption) (*structpb.Struct, error) {
+	return c.invoke(ctx, "GetEffectiveRules", req, opts...)
+}
+
+func (c *DebugClient) GetResourceStats(ctx context.Context, req *structpb.Struct, opts ...grpc.CallOption) (*structpb.Struct, error) {
+	return c.invoke(ctx, "GetResourceStats", req, opts...)
+}
+
+func (c *DebugClient) GetBreakerStates(ctx context.Context, req *structpb.Struct, opts ...grpc.CallOption) (*structpb.Struct, error) {
+	return c.invoke(ctx, "GetBreakerStates", req, opts...)
+}
+
+func (c *DebugClient) ConvertRules(ctx context.Context, req *structpb.Struct, opts ...grpc.CallOption) (*structpb.Struct, error) {
+	return c.invoke(ctx, "ConvertRules", req, opts...)
+}
+
+func (c *DebugClient) GetSelfMetrics(ctx context.Context, req *structpb.Struct, opts ...grpc.CallOption) (*structpb.Struct, error) {
+	return c.invoke(ctx, "GetSelfMetrics", req, opts...)
+}
+
+func (c *DebugClient) UpdateChaos(ctx context.Context, req *structpb.Struct, opts ...grpc.CallOption) (*structpb.Struct, error) {
+	return c.invoke(ctx, "UpdateChaos", req, opts...)
+}
+
+func (c *DebugClient) invoke(ctx context.Context, method string, req *structpb.Struct, opts ...grpc.CallOption) (*structpb.Struct, error) {
+	if req == nil {
+		req = &structpb.Struct{}
+	}
+	out := new(structpb.Struct)
+	if err := c.cc.Invoke(ctx, "/"+DebugServiceName+"/"+method, req, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,179 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	sentinel "github.com/alibaba/sentinel-golang/api"
+	"github.com/alibaba/sentinel-golang/core/chaos"
+	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newDebugClient(t *testing.T) (*DebugClient, func()) {
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	RegisterDebugService(s)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	assert.Nil(t, err)
+	return NewDebugClient(conn), func() {
+		_ = conn.Close()
+		s.Stop()
+	}
+}
+
+func TestDebugService(t *testing.T) {
+	client, stop := newDebugClient(t)
+	defer stop()
+	defer flow.ClearRules()
+	defer circuitbreaker.ClearRules()
+
+	_, err := flow.LoadRules([]*flow.Rule{
+		{
+			Resource:               "debug-abc",
+			Threshold:              10,
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+		},
+	})
+	assert.Nil(t, err)
+	_, err, _ = circuitbreaker.LoadRules([]*circuitbreaker.Rule{
+		{
+			Id:               "cb-1",
+			Resource:         "debug-abc",
+			Strategy:         circuitbreaker.ErrorCount,
+			RetryTimeoutMs:   1000,
+			MinRequestAmount: 1,
+			StatIntervalMs:   1000,
+			Threshold:        10,
+		},
+	})
+	assert.Nil(t, err)
+	e, b := sentinel.Entry("debug-abc")
+	assert.Nil(t, b)
+	e.Exit()
+
+	ctx := context.Background()
+	rules, err := client.GetEffectiveRules(ctx, &structpb.Struct{Fields: map[string]*structpb.Value{
+		"module": {Kind: &structpb.Value_StringValue{StringValue: "flow"}},
+	}})
+	assert.Nil(t, err)
+	flowRules := rules.Fields["flow"].GetListValue().GetValues()
+	assert.Equal(t, 1, len(flowRules))
+	assert.Equal(t, "debug-abc", flowRules[0].GetStructValue().Fields["resource"].GetStringValue())
+
+	_, err = client.GetEffectiveRules(ctx, &structpb.Struct{Fields: map[string]*structpb.Value{
+		"module": {Kind: &structpb.Value_StringValue{StringValue: "unknown"}},
+	}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	stats, err := client.GetResourceStats(ctx, &structpb.Struct{Fields: map[string]*structpb.Value{
+		"resource": {Kind: &structpb.Value_StringValue{StringValue: "debug-abc"}},
+	}})
+	assert.Nil(t, err)
+	resources := stats.Fields["resources"].GetListValue().GetValues()
+	assert.Equal(t, 1, len(resources))
+	assert.Equal(t, float64(1), resources[0].GetStructValue().Fields["passQps"].GetNumberValue())
+
+	states, err := client.GetBreakerStates(ctx, nil)
+	assert.Nil(t, err)
+	breakers := states.Fields["breakers"].GetListValue().GetValues()
+	assert.Equal(t, 1, len(breakers))
+	assert.Equal(t, "cb-1", breakers[0].GetStructValue().Fields["ruleId"].GetStringValue())
+	assert.Equal(t, "Closed", breakers[0].GetStructValue().Fields["state"].GetStringValue())
+}
+
+func TestDebugService_ConvertRules(t *testing.T) {
+	client, stop := newDebugClient(t)
+	defer stop()
+
+	str := func(s string) *structpb.Value {
+		return &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: s}}
+	}
+	ctx := context.Background()
+	resp, err := client.ConvertRules(ctx, &structpb.Struct{Fields: map[string]*structpb.Value{
+		"module": str("flow"),
+		"from":   str("java"),
+		"to":     str("go"),
+		"rules":  str(`[{"resource":"abc","limitApp":"default","grade":1,"count":10,"controlBehavior":2,"maxQueueingTimeMs":500}]`),
+	}})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `[{"resource":"abc","tokenCalculateStrategy":0,"controlBehavior":1,"threshold":10,"relationStrategy":0,"refResource":"",
+		"maxQueueingTimeMs":500,"maxQueueingRequests":0,"warmUpPeriodSec":0,"warmUpColdFactor":0,"warmUpCurve":0,"coldStartCount":0,"statIntervalInMs":0}]`,
+		resp.Fields["rules"].GetStringValue())
+
+	_, err = client.ConvertRules(ctx, &structpb.Struct{Fields: map[string]*structpb.Value{
+		"module": str("flow"),
+		"from":   str("java"),
+		"to":     str("go"),
+		"rules":  str(`[{"resource":"abc","grade":0}]`),
+	}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestDebugService_GetSelfMetrics(t *testing.T) {
+	client, stop := newDebugClient(t)
+	defer stop()
+	selfmetric.SetEnabled(true)
+	defer selfmetric.SetEnabled(false)
+	defer selfmetric.ResetTimers()
+
+	e, b := sentinel.Entry("debug-self-metric")
+	assert.Nil(t, b)
+	e.Exit()
+
+	resp, err := client.GetSelfMetrics(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.True(t, len(resp.Fields["timers"].GetListValue().GetValues()) > 0)
+	gauges := make(map[string]float64)
+	for _, g := range resp.Fields["gauges"].GetListValue().GetValues() {
+		fields := g.GetStructValue().Fields
+		gauges[fields["name"].GetStringValue()] = fields["value"].GetNumberValue()
+	}
+	assert.True(t, gauges["stat.resourceNodeCount"] >= 1)
+	assert.True(t, gauges["stat.memoryBytes"] > 0)
+}
+
+func TestDebugService_UpdateChaos(t *testing.T) {
+	client, stop := newDebugClient(t)
+	defer stop()
+	defer chaos.ClearRules()
+	defer chaos.SetEnabled(false)
+
+	ctx := context.Background()
+	resp, err := client.UpdateChaos(ctx, &structpb.Struct{Fields: map[string]*structpb.Value{
+		"enabled": {Kind: &structpb.Value_BoolValue{BoolValue: true}},
+		"rules":   {Kind: &structpb.Value_StringValue{StringValue: `[{"resource":"debug-chaos","blockRate":1}]`}},
+	}})
+	assert.Nil(t, err)
+	assert.True(t, resp.Fields["enabled"].GetBoolValue())
+	assert.Equal(t, 1, len(resp.Fields["rules"].GetListValue().GetValues()))
+
+	_, b := sentinel.Entry("debug-chaos")
+	assert.NotNil(t, b)
+
+	resp, err = client.UpdateChaos(ctx, &structpb.Struct{Fields: map[string]*structpb.Value{
+		"enabled": {Kind: &structpb.Value_BoolValue{BoolValue: false}},
+	}})
+	assert.Nil(t, err)
+	assert.False(t, resp.Fields["enabled"].GetBoolValue())
+	assert.Equal(t, 1, len(resp.Fields["rules"].GetListValue().GetValues()))
+
+	_, err = client.UpdateChaos(ctx, &structpb.Struct{Fields: map[string]*structpb.Value{
+		"rules": {Kind: &structpb.Value_StringValue{StringValue: `{`}},
+	}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -3,8 +3,13 @@ package grpc
 import (
 	"context"
 
+	"github.com/alibaba/sentinel-golang/adapter/origin"
+	"github.com/alibaba/sentinel-golang/adapter/route"
 	"github.com/alibaba/sentinel-golang/core/base"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type (
@@ -22,6 +27,11 @@ type (
 
 		streamClientBlockFallback func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, *base.BlockError) (grpc.ClientStream, error)
 		streamServerBlockFallback func(interface{}, grpc.ServerStream, *grpc.StreamServerInfo, *base.BlockError) error
+
+		serverBlockCodes     map[base.BlockType]codes.Code
+		serverOriginExtract  func(context.Context) string
+		serverRouteConfig    *route.Config
+		serverOriginProvider *origin.Provider
 	}
 )
 
@@ -83,6 +93,91 @@ func WithStreamServerBlockFallback(fn func(interface{}, grpc.ServerStream, *grpc
 	}
 }
 
+// WithServerBlockCodes sets the gRPC status codes of the server requests blocked per block type,
+// e.g. codes.ResourceExhausted for BlockTypeFlow and codes.Unavailable for BlockTypeCircuitBreaking,
+// which are mapped to the HTTP status codes 429 and 503 by the gRPC-Gateway.
+// The block error is returned as it is for the block types absent from the mapping.
+// It doesn't take effect if the server block fallback is set.
+func WithServerBlockCodes(blockCodes map[base.BlockType]codes.Code) Option {
+	return func(opts *options) {
+		opts.serverBlockCodes = blockCodes
+	}
+}
+
+// serverBlockErrorOf converts the block error to the gRPC status error according to the server block codes.
+func (o *options) serverBlockErrorOf(blockErr *base.BlockError) error {
+	if code, ok := o.serverBlockCodes[blockErr.BlockType()]; ok {
+		return status.Error(code, blockErr.Error())
+	}
+	return blockErr
+}
+
+// WithServerOriginExtractor sets the origin extractor of the server requests, which takes precedence over
+// the origin header (i.e. metadata) of the route config.
+func WithServerOriginExtractor(fn func(context.Context) string) Option {
+	return func(opts *options) {
+		opts.serverOriginExtract = fn
+	}
+}
+
+// WithServerRouteConfig sets the declarative per-route configuration of the server requests, which overrides
+// the resource name and the origin metadata of the matched routes. The routes are matched by the full method
+// (e.g. "/pkg.Service/Method"), and the requests of the unguarded methods are handled directly.
+// The block response settings of the route config only take effect for the HTTP adapters.
+func WithServerRouteConfig(cfg *route.Config) Option {
+	return func(opts *options) {
+		opts.serverRouteConfig = cfg
+	}
+}
+
+// resolveServerRoute resolves the route settings of the server full method,
+// and returns false if the method is not guarded.
+func (o *options) resolveServerRoute(fullMethod string) (route.Settings, bool) {
+	if o.serverRouteConfig == nil {
+		return route.Settings{}, true
+	}
+	return o.serverRouteConfig.Resolve("", fullMethod)
+}
+
+// WithServerBaggageOrigin takes the origin of the server requests from the context propagated by the tracing
+// systems (e.g. the OpenTelemetry baggage, see package origin) when the origin metadata is missing.
+// The baggage keys are looked up in order, origin.DefaultBaggageKeys if absent.
+// It doesn't take effect if the origin extractor is set.
+func WithServerBaggageOrigin(baggageKeys ...string) Option {
+	return func(opts *options) {
+		opts.serverOriginProvider = origin.NewProvider(baggageKeys...)
+	}
+}
+
+// serverOriginOf returns the origin of the server request by the origin extractor, or the value of the origin metadata,
+// or the origin propagated by the tracing systems if WithServerBaggageOrigin is set.
+func (o *options) serverOriginOf(ctx context.Context, originHeader string) string {
+	if o.serverOriginExtract != nil {
+		return o.serverOriginExtract(ctx)
+	}
+	if ctx == nil {
+		return ""
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if len(originHeader) > 0 {
+		if values := md.Get(originHeader); len(values) > 0 && len(values[0]) > 0 {
+			return values[0]
+		}
+	}
+	if o.serverOriginProvider != nil {
+		return o.serverOriginProvider.OriginOf(func(name string) string {
+			if values := md.Get(name); len(values) > 0 {
+				return values[0]
+			}
+			return ""
+		})
+	}
+	return ""
+}
+
 func evaluateOptions(opts []Option) *options {
 	optCopy := &options{}
 	for _, o := range opts {
@@ -22,16 +22,29 @@ func NewUnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
 		if options.unaryServerResourceExtract != nil {
 			resourceName = options.unaryServerResourceExtract(ctx, req, info)
 		}
-		entry, blockErr := sentinel.Entry(
-			resourceName,
+		settings, guarded := options.resolveServerRoute(info.FullMethod)
+		if !guarded {
+			return handler(ctx, req)
+		}
+		if len(settings.Resource) > 0 {
+			resourceName = settings.Resource
+		}
+		entryOpts := []sentinel.EntryOption{
 			sentinel.WithResourceType(base.ResTypeRPC),
 			sentinel.WithTrafficType(base.Inbound),
-		)
+		}
+		if origin := options.serverOriginOf(ctx, settings.OriginHeader); len(origin) > 0 {
+			entryOpts = append(entryOpts, sentinel.WithOrigin(origin))
+		}
+		if len(settings.Tags) > 0 {
+			entryOpts = append(entryOpts, sentinel.WithTags(settings.Tags...))
+		}
+		entry, blockErr := sentinel.Entry(resourceName, entryOpts...)
 		if blockErr != nil {
 			if options.unaryServerBlockFallback != nil {
 				return options.unaryServerBlockFallback(ctx, req, info, blockErr)
 			}
-			return nil, blockErr
+			return nil, options.serverBlockErrorOf(blockErr)
 		}
 		defer entry.Exit()
 
@@ -57,16 +70,33 @@ func NewStreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
 		if options.streamServerResourceExtract != nil {
 			resourceName = options.streamServerResourceExtract(srv, ss, info)
 		}
-		entry, blockErr := sentinel.Entry(
-			resourceName,
+		settings, guarded := options.resolveServerRoute(info.FullMethod)
+		if !guarded {
+			return handler(srv, ss)
+		}
+		if len(settings.Resource) > 0 {
+			resourceName = settings.Resource
+		}
+		entryOpts := []sentinel.EntryOption{
 			sentinel.WithResourceType(base.ResTypeRPC),
 			sentinel.WithTrafficType(base.Inbound),
-		)
+		}
+		var streamCtx context.Context
+		if ss != nil {
+			streamCtx = ss.Context()
+		}
+		if origin := options.serverOriginOf(streamCtx, settings.OriginHeader); len(origin) > 0 {
+			entryOpts = append(entryOpts, sentinel.WithOrigin(origin))
+		}
+		if len(settings.Tags) > 0 {
+			entryOpts = append(entryOpts, sentinel.WithTags(settings.Tags...))
+		}
+		entry, blockErr := sentinel.Entry(resourceName, entryOpts...)
 		if blockErr != nil { // blocked
 			if options.streamServerBlockFallback != nil {
 				return options.streamServerBlockFallback(srv, ss, info, blockErr)
 			}
-			return blockErr
+			return options.serverBlockErrorOf(blockErr)
 		}
 		defer entry.Exit()
 
@@ -6,12 +6,16 @@ import (
 	"os"
 	"testing"
 
+	"github.com/alibaba/sentinel-golang/adapter/route"
 	sentinel "github.com/alibaba/sentinel-golang/api"
 	"github.com/alibaba/sentinel-golang/core/base"
 	"github.com/alibaba/sentinel-golang/core/flow"
 	"github.com/alibaba/sentinel-golang/core/stat"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func TestMain(m *testing.M) {
@@ -113,5 +117,31 @@ func TestUnaryServerIntercept(t *testing.T) {
 		rep, err := interceptor(nil, nil, info, successHandler)
 		assert.IsType(t, &base.BlockError{}, err)
 		assert.Nil(t, rep)
+
+		rep, err = NewUnaryServerInterceptor(WithServerBlockCodes(map[base.BlockType]codes.Code{
+			base.BlockTypeFlow: codes.ResourceExhausted,
+		}))(nil, nil, info, successHandler)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Nil(t, rep)
+
+		rep, err = NewUnaryServerInterceptor(WithServerRouteConfig(&route.Config{
+			ExcludePaths: []string{"/grpc.testing.TestService/*"},
+		}))(nil, nil, info, successHandler)
+		assert.Nil(t, err)
+		assert.Equal(t, "abc", rep)
 	})
 }
+
+func TestServerOriginOfWithBaggageOrigin(t *testing.T) {
+	opts := evaluateOptions([]Option{WithServerBaggageOrigin()})
+
+	// the origin metadata takes precedence
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-origin", "app-a", "baggage", "origin=app-b"))
+	assert.Equal(t, "app-a", opts.serverOriginOf(ctx, "x-origin"))
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("baggage", "origin=app-b"))
+	assert.Equal(t, "app-b", opts.serverOriginOf(ctx, "x-origin"))
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-envoy-downstream-service-cluster", "app-c"))
+	assert.Equal(t, "app-c", opts.serverOriginOf(ctx, ""))
+	assert.Equal(t, "", evaluateOptions(nil).serverOriginOf(ctx, "x-origin"))
+	assert.Equal(t, "", opts.serverOriginOf(context.Background(), "x-origin"))
+}
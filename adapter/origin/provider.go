@@ -0,0 +1,75 @@
+// Package origin provides the origin (i.e. the caller identity) of the requests from the context propagated
+// by the tracing systems, which is used by the adapters when the explicit origin header is missing,
+// so that the per-origin statistics work out of the box in the meshes already propagating the context.
+//
+// The origin is looked up in order:
+//
+//  1. the W3C baggage header of OpenTelemetry (e.g. "baggage: caller=order-service"),
+//  2. the baggage headers of OpenTracing used by Datadog APM (e.g. "ot-baggage-caller: order-service"),
+//  3. the downstream service cluster header added by Envoy (i.e. "x-envoy-downstream-service-cluster").
+package origin
+
+import (
+	"net/url"
+	"strings"
+)
+
+const (
+	// BaggageHeader is the W3C baggage header propagated by OpenTelemetry.
+	BaggageHeader = "baggage"
+	// OTBaggageHeaderPrefix is the prefix of the baggage headers of OpenTracing, which is propagated by Datadog APM.
+	OTBaggageHeaderPrefix = "ot-baggage-"
+	// EnvoyDownstreamClusterHeader is the header of the downstream service cluster added by Envoy.
+	EnvoyDownstreamClusterHeader = "x-envoy-downstream-service-cluster"
+)
+
+// DefaultBaggageKeys are the baggage keys of the caller identity looked up by default.
+var DefaultBaggageKeys = []string{"origin", "caller", "service.name"}
+
+// Provider provides the origin of the requests by the baggage keys.
+type Provider struct {
+	baggageKeys []string
+}
+
+// NewProvider creates the Provider looking up the baggage keys in order, DefaultBaggageKeys if absent.
+func NewProvider(baggageKeys ...string) *Provider {
+	if len(baggageKeys) == 0 {
+		baggageKeys = DefaultBaggageKeys
+	}
+	return &Provider{baggageKeys: baggageKeys}
+}
+
+// OriginOf returns the origin by the header getter (e.g. http.Header.Get, or the gRPC metadata getter),
+// or empty string if absent. The header getter must match the header names case-insensitively.
+func (p *Provider) OriginOf(header func(name string) string) string {
+	if baggage := header(BaggageHeader); len(baggage) > 0 {
+		for _, key := range p.baggageKeys {
+			if v := baggageValueOf(baggage, key); len(v) > 0 {
+				return v
+			}
+		}
+	}
+	for _, key := range p.baggageKeys {
+		if v := strings.TrimSpace(header(OTBaggageHeaderPrefix + key)); len(v) > 0 {
+			return v
+		}
+	}
+	return strings.TrimSpace(header(EnvoyDownstreamClusterHeader))
+}
+
+// baggageValueOf returns the value of the key in the W3C baggage, e.g. "k1=v1;property,k2=v2".
+func baggageValueOf(baggage, key string) string {
+	for _, member := range strings.Split(baggage, ",") {
+		// the properties of the member are ignored
+		kv := strings.SplitN(strings.SplitN(member, ";", 2)[0], "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) != key {
+			continue
+		}
+		v, err := url.PathUnescape(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return ""
+		}
+		return v
+	}
+	return ""
+}
@@ -0,0 +1,36 @@
+package origin
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvider_OriginOf(t *testing.T) {
+	p := NewProvider()
+	originOf := func(headers map[string]string) string {
+		h := http.Header{}
+		for k, v := range headers {
+			h.Set(k, v)
+		}
+		return p.OriginOf(h.Get)
+	}
+
+	assert.Equal(t, "", originOf(nil))
+	assert.Equal(t, "order service", originOf(map[string]string{
+		BaggageHeader: "userId=alice, caller=order%20service;ttl=1,service.name=ignored"}))
+	// the keys are looked up in order
+	assert.Equal(t, "gateway", originOf(map[string]string{BaggageHeader: "service.name=gateway"}))
+	assert.Equal(t, "payment", originOf(map[string]string{
+		BaggageHeader: "userId=alice", "Ot-Baggage-Caller": "payment"}))
+	assert.Equal(t, "outbound|8080||order.default.svc.cluster.local", originOf(map[string]string{
+		EnvoyDownstreamClusterHeader: "outbound|8080||order.default.svc.cluster.local"}))
+	// the malformed value is skipped
+	assert.Equal(t, "gateway", originOf(map[string]string{BaggageHeader: "caller=%zz,service.name=gateway"}))
+
+	custom := NewProvider("app")
+	h := http.Header{}
+	h.Set(BaggageHeader, "caller=order,app=shop")
+	assert.Equal(t, "shop", custom.OriginOf(h.Get))
+}
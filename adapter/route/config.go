@@ -0,0 +1,166 @@
+package route
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Route is the override of a route.
+type Route struct {
+	// Method is the HTTP method of the route, empty means any method.
+	// It's ignored by the gRPC adapters.
+	Method string `json:"method,omitempty" yaml:"method,omitempty"`
+	// Path is the path pattern of the route, which matches the route template of the adapters.
+	// The pattern with the trailing "*" matches the paths with the prefix.
+	Path string `json:"path" yaml:"path"`
+	// Resource is the resource name of the route, empty means the default resource name of the adapters.
+	Resource string `json:"resource,omitempty" yaml:"resource,omitempty"`
+	// OriginHeader is the header (or gRPC metadata) that carries the origin of the requests,
+	// which overrides the OriginHeader of the Config.
+	OriginHeader string `json:"originHeader,omitempty" yaml:"originHeader,omitempty"`
+	// BlockStatusCode is the HTTP status code of the blocked requests, which overrides the BlockStatusCode of the Config.
+	BlockStatusCode int `json:"blockStatusCode,omitempty" yaml:"blockStatusCode,omitempty"`
+	// BlockMessage is the response message of the blocked requests, which overrides the BlockMessage of the Config.
+	BlockMessage string `json:"blockMessage,omitempty" yaml:"blockMessage,omitempty"`
+	// Tags are attached to the resource of the route in addition to the Tags of the Config,
+	// so that the rules targeting the tags (e.g. "tier=gold") take effect on the resource.
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// Config is the declarative per-route configuration of the adapters.
+type Config struct {
+	// IncludePaths are the path patterns guarded by Sentinel, empty means all the paths.
+	IncludePaths []string `json:"includePaths,omitempty" yaml:"includePaths,omitempty"`
+	// ExcludePaths are the path patterns never guarded by Sentinel, which take precedence over IncludePaths.
+	ExcludePaths []string `json:"excludePaths,omitempty" yaml:"excludePaths,omitempty"`
+	// OriginHeader is the default header (or gRPC metadata) that carries the origin of the requests.
+	OriginHeader string `json:"originHeader,omitempty" yaml:"originHeader,omitempty"`
+	// BlockStatusCode is the default HTTP status code of the blocked requests, 0 means the default of the adapters.
+	BlockStatusCode int `json:"blockStatusCode,omitempty" yaml:"blockStatusCode,omitempty"`
+	// BlockMessage is the default response message of the blocked requests, empty means the default of the adapters.
+	BlockMessage string `json:"blockMessage,omitempty" yaml:"blockMessage,omitempty"`
+	// Tags are attached to the resources of all the guarded routes.
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Routes are the overrides of the routes, the first matched route takes effect.
+	Routes []*Route `json:"routes,omitempty" yaml:"routes,omitempty"`
+}
+
+// Settings is the effective settings of a route, resolved from the Config.
+type Settings struct {
+	// Resource is the resource name of the route, empty means the default resource name of the adapters.
+	Resource        string
+	OriginHeader    string
+	BlockStatusCode int
+	BlockMessage    string
+	// Tags are the tags attached to the resource of the route.
+	Tags []string
+}
+
+// LoadConfigFile loads the Config from the YAML (or JSON) file.
+func LoadConfigFile(filePath string) (*Config, error) {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to read the route config file: %s", filePath)
+	}
+	return ParseConfig(content)
+}
+
+// ParseConfig parses the Config from the YAML (or JSON) content.
+func ParseConfig(content []byte) (*Config, error) {
+	cfg := &Config{}
+	if err := yaml.Unmarshal(content, cfg); err != nil {
+		return nil, errors.Wrap(err, "fail to parse the route config")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate checks whether the Config is valid.
+func (c *Config) Validate() error {
+	for _, p := range append(append([]string{}, c.IncludePaths...), c.ExcludePaths...) {
+		if len(p) == 0 {
+			return errors.New("empty path pattern")
+		}
+	}
+	if c.BlockStatusCode < 0 {
+		return errors.New("negative BlockStatusCode")
+	}
+	for _, r := range c.Routes {
+		if r == nil {
+			return errors.New("nil Route")
+		}
+		if len(r.Path) == 0 {
+			return errors.New("empty path of Route")
+		}
+		if r.BlockStatusCode < 0 {
+			return errors.Errorf("negative BlockStatusCode of Route: %s", r.Path)
+		}
+	}
+	return nil
+}
+
+// Resolve returns the effective settings of the route with the method and the path (i.e. the route template),
+// and false if the route is not guarded by Sentinel.
+func (c *Config) Resolve(method, path string) (Settings, bool) {
+	if !c.guarded(path) {
+		return Settings{}, false
+	}
+	s := Settings{
+		OriginHeader:    c.OriginHeader,
+		BlockStatusCode: c.BlockStatusCode,
+		BlockMessage:    c.BlockMessage,
+		Tags:            c.Tags,
+	}
+	for _, r := range c.Routes {
+		if len(r.Method) > 0 && !strings.EqualFold(r.Method, method) {
+			continue
+		}
+		if !pathMatches(r.Path, path) {
+			continue
+		}
+		s.Resource = r.Resource
+		if len(r.OriginHeader) > 0 {
+			s.OriginHeader = r.OriginHeader
+		}
+		if r.BlockStatusCode > 0 {
+			s.BlockStatusCode = r.BlockStatusCode
+		}
+		if len(r.BlockMessage) > 0 {
+			s.BlockMessage = r.BlockMessage
+		}
+		if len(r.Tags) > 0 {
+			s.Tags = append(append(make([]string, 0, len(c.Tags)+len(r.Tags)), c.Tags...), r.Tags...)
+		}
+		break
+	}
+	return s, true
+}
+
+func (c *Config) guarded(path string) bool {
+	for _, p := range c.ExcludePaths {
+		if pathMatches(p, path) {
+			return false
+		}
+	}
+	if len(c.IncludePaths) == 0 {
+		return true
+	}
+	for _, p := range c.IncludePaths {
+		if pathMatches(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func pathMatches(pattern, path string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(path, pattern[:len(pattern)-1])
+	}
+	return pattern == path
+}
@@ -0,0 +1,49 @@
+package route
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`
+includePaths: ["/api/*"]
+excludePaths: ["/api/health"]
+originHeader: X-Caller
+blockStatusCode: 429
+routes:
+  - method: GET
+    path: /api/users/:id
+    resource: get-user
+  - path: /api/orders/*
+    resource: orders
+    originHeader: X-App
+    blockStatusCode: 503
+    blockMessage: busy
+    tags: [tier=gold]
+`))
+	assert.Nil(t, err)
+
+	s, guarded := cfg.Resolve("GET", "/api/users/:id")
+	assert.True(t, guarded)
+	assert.Equal(t, Settings{Resource: "get-user", OriginHeader: "X-Caller", BlockStatusCode: 429}, s)
+
+	s, guarded = cfg.Resolve("POST", "/api/users/:id")
+	assert.True(t, guarded)
+	assert.Equal(t, "", s.Resource)
+
+	s, guarded = cfg.Resolve("POST", "/api/orders/:id")
+	assert.True(t, guarded)
+	assert.Equal(t, Settings{Resource: "orders", OriginHeader: "X-App", BlockStatusCode: 503, BlockMessage: "busy", Tags: []string{"tier=gold"}}, s)
+
+	_, guarded = cfg.Resolve("GET", "/api/health")
+	assert.False(t, guarded)
+	_, guarded = cfg.Resolve("GET", "/metrics")
+	assert.False(t, guarded)
+
+	_, err = ParseConfig([]byte(`{"routes": [{"resource": "abc"}]}`))
+	assert.NotNil(t, err)
+	_, err = ParseConfig([]byte(`{"blockStatusCode": -1}`))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,38 @@
+// Package route provides the declarative per-route configuration of the adapters (Gin, Echo and gRPC),
+// so that the whole service could be wired by a single config struct or file without touching
+// each handler registration.
+//
+// The routes are matched by the route template of the adapters, i.e. the registered path of the HTTP
+// frameworks (e.g. "/api/users/:id") or the full method of gRPC (e.g. "/pkg.Service/Method").
+// The path pattern with the trailing "*" matches the paths with the prefix. Each route could override
+// the resource name, the origin header and the block response, and the excluded (or not included)
+// paths are not guarded by Sentinel at all.
+//
+// Here is an example of the config file (YAML or JSON):
+//
+//	includePaths: ["/api/*"]
+//	excludePaths: ["/api/health"]
+//	originHeader: X-Caller
+//	blockStatusCode: 429
+//	routes:
+//	  - method: GET
+//	    path: /api/users/:id
+//	    resource: get-user
+//	  - path: /api/orders/*
+//	    resource: orders
+//	    blockStatusCode: 503
+//	    blockMessage: orders service is busy
+//
+// which could be loaded and applied to the adapters:
+//
+//	cfg, err := route.LoadConfigFile("routes.yaml")
+//	...
+//	r.Use(sentinelgin.SentinelMiddleware(sentinelgin.WithRouteConfig(cfg)))
+//
+// The health checks, the metrics scrapes and the CORS preflight requests could be excluded by the actual path
+// (exact, prefix or regex) with the Exclusions, which are compiled once and never counted in statistics or blocked:
+//
+//	exclusions, err := route.NewExclusions(append(route.CommonExcludedPaths, "re:^/internal/.+"), true)
+//	...
+//	r.Use(sentinelgin.SentinelMiddleware(sentinelgin.WithExclusions(exclusions)))
+package route
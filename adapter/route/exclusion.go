@@ -0,0 +1,83 @@
+package route
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RegexPatternPrefix is the prefix of the regex exclusion patterns, e.g. "re:^/internal/.+/status$".
+const RegexPatternPrefix = "re:"
+
+// CommonExcludedPaths are the paths of the health checks, the metrics scrapes and the profiling commonly served.
+var CommonExcludedPaths = []string{"/healthz", "/livez", "/readyz", "/health", "/metrics", "/debug/pprof/*"}
+
+// Exclusions are the requests never counted in statistics or blocked by the adapters, e.g. the health checks,
+// the metrics scrapes and the CORS preflight requests. Unlike the ExcludePaths of Config matching the route
+// template, the Exclusions match the actual path of the requests before any route is resolved.
+// The patterns are compiled once, so the Exclusions should be created at the startup and shared.
+type Exclusions struct {
+	exact     map[string]struct{}
+	prefixes  []string
+	regexes   []*regexp.Regexp
+	preflight bool
+}
+
+// NewExclusions compiles the exclusion patterns. The pattern with the trailing "*" matches the paths with the prefix,
+// the pattern with RegexPatternPrefix is the regular expression matching the paths, and the other patterns match
+// the paths exactly. If excludePreflight is true, the CORS preflight requests (i.e. the OPTIONS requests with
+// the Access-Control-Request-Method header) are excluded as well.
+func NewExclusions(patterns []string, excludePreflight bool) (*Exclusions, error) {
+	e := &Exclusions{
+		exact:     make(map[string]struct{}),
+		preflight: excludePreflight,
+	}
+	for _, p := range patterns {
+		switch {
+		case len(p) == 0:
+			return nil, errors.New("empty exclusion pattern")
+		case strings.HasPrefix(p, RegexPatternPrefix):
+			regex, err := regexp.Compile(strings.TrimPrefix(p, RegexPatternPrefix))
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid regex exclusion pattern: %s", p)
+			}
+			e.regexes = append(e.regexes, regex)
+		case strings.HasSuffix(p, "*"):
+			e.prefixes = append(e.prefixes, p[:len(p)-1])
+		default:
+			e.exact[p] = struct{}{}
+		}
+	}
+	return e, nil
+}
+
+// Excludes checks whether the request with the method, the path and the header getter is excluded.
+func (e *Exclusions) Excludes(method, path string, header func(string) string) bool {
+	if e == nil {
+		return false
+	}
+	if e.preflight && method == http.MethodOptions && header != nil && len(header("Access-Control-Request-Method")) > 0 {
+		return true
+	}
+	if _, ok := e.exact[path]; ok {
+		return true
+	}
+	for _, p := range e.prefixes {
+		if strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	for _, r := range e.regexes {
+		if r.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExcludesRequest checks whether the HTTP request is excluded.
+func (e *Exclusions) ExcludesRequest(r *http.Request) bool {
+	return e.Excludes(r.Method, r.URL.Path, r.Header.Get)
+}
@@ -0,0 +1,38 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExclusions(t *testing.T) {
+	e, err := NewExclusions(append([]string{`re:^/internal/\w+/status$`}, CommonExcludedPaths...), true)
+	assert.NoError(t, err)
+
+	assert.True(t, e.Excludes(http.MethodGet, "/healthz", nil))
+	assert.False(t, e.Excludes(http.MethodGet, "/healthz/deep", nil))
+	assert.True(t, e.Excludes(http.MethodGet, "/debug/pprof/heap", nil))
+	assert.True(t, e.Excludes(http.MethodGet, "/internal/db/status", nil))
+	assert.False(t, e.Excludes(http.MethodGet, "/internal/db/status/x", nil))
+	assert.False(t, e.Excludes(http.MethodGet, "/api/users", nil))
+
+	preflight := httptest.NewRequest(http.MethodOptions, "/api/users", nil)
+	preflight.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	assert.True(t, e.ExcludesRequest(preflight))
+	assert.False(t, e.ExcludesRequest(httptest.NewRequest(http.MethodOptions, "/api/users", nil)))
+
+	noPreflight, err := NewExclusions(nil, false)
+	assert.NoError(t, err)
+	assert.False(t, noPreflight.ExcludesRequest(preflight))
+
+	var nilExclusions *Exclusions
+	assert.False(t, nilExclusions.Excludes(http.MethodGet, "/healthz", nil))
+
+	_, err = NewExclusions([]string{"re:("}, false)
+	assert.Error(t, err)
+	_, err = NewExclusions([]string{""}, false)
+	assert.Error(t, err)
+}
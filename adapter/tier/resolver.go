@@ -0,0 +1,107 @@
+// Package tier resolves the API keys of the requests to the plan tiers (e.g. "free", "pro") for the adapters,
+// so that the hotspot rules apply the thresholds of the tiers (see hotspot.Rule.TierThresholds) per API key:
+//
+//	hotspot.LoadRules([]*hotspot.Rule{{
+//		Resource:        "GET:/api/orders",
+//		MetricType:      hotspot.QPS,
+//		ControlBehavior: hotspot.Reject,
+//		ParamIndex:      0,
+//		Threshold:       10,
+//		DurationInSec:   1,
+//		TierThresholds:  map[string]int64{"free": 10, "pro": 100},
+//	}})
+//	resolver := tier.NewCachingResolver(tier.ResolverFunc(lookupPlanOfAPIKey), time.Minute, 10000)
+//	r.Use(sentinelgin.SentinelMiddleware(sentinelgin.WithAPIKeyTierParam("X-API-Key", resolver)))
+package tier
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/hotspot"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+// Resolver resolves the API key to the tier of its plan.
+type Resolver interface {
+	ResolveTier(apiKey string) (string, error)
+}
+
+// ResolverFunc is the function adapter of Resolver.
+type ResolverFunc func(apiKey string) (string, error)
+
+func (f ResolverFunc) ResolveTier(apiKey string) (string, error) {
+	return f(apiKey)
+}
+
+type cachedTier struct {
+	tier     string
+	expireAt uint64
+}
+
+type cachingResolver struct {
+	resolver Resolver
+	ttlMs    uint64
+	capacity int
+
+	mux   sync.RWMutex
+	tiers map[string]cachedTier
+}
+
+// NewCachingResolver wraps the resolver with the cache of at most capacity API keys, the resolved tiers expire after ttl.
+// The failed resolutions are not cached. If the cache is full, the expired entries are purged, or the cache is reset.
+func NewCachingResolver(resolver Resolver, ttl time.Duration, capacity int) Resolver {
+	return &cachingResolver{
+		resolver: resolver,
+		ttlMs:    uint64(ttl.Milliseconds()),
+		capacity: capacity,
+		tiers:    make(map[string]cachedTier),
+	}
+}
+
+func (r *cachingResolver) ResolveTier(apiKey string) (string, error) {
+	now := util.CurrentTimeMillis()
+	r.mux.RLock()
+	cached, ok := r.tiers[apiKey]
+	r.mux.RUnlock()
+	if ok && now < cached.expireAt {
+		return cached.tier, nil
+	}
+
+	t, err := r.resolver.ResolveTier(apiKey)
+	if err != nil {
+		return "", err
+	}
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if _, exists := r.tiers[apiKey]; !exists && len(r.tiers) >= r.capacity {
+		r.purge(now)
+	}
+	r.tiers[apiKey] = cachedTier{tier: t, expireAt: now + r.ttlMs}
+	return t, nil
+}
+
+// purge removes the expired entries, or all the entries if none is expired.
+func (r *cachingResolver) purge(now uint64) {
+	for k, v := range r.tiers {
+		if now >= v.expireAt {
+			delete(r.tiers, k)
+		}
+	}
+	if len(r.tiers) >= r.capacity {
+		r.tiers = make(map[string]cachedTier)
+	}
+}
+
+// ParamOf resolves the API key to the hotspot param with its tier. If the resolution fails,
+// the tier is empty and the Threshold of the hotspot rules takes effect.
+func ParamOf(apiKey string, resolver Resolver) hotspot.TieredParam {
+	t, err := resolver.ResolveTier(apiKey)
+	if err != nil {
+		logging.Warn("[Tier] Failed to resolve the tier of the API key", "err", err.Error())
+		t = ""
+	}
+	return hotspot.TieredParam{Key: apiKey, Tier: t}
+}
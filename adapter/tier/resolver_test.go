@@ -0,0 +1,63 @@
+package tier
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/hotspot"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachingResolver(t *testing.T) {
+	calls := 0
+	plans := map[string]string{"k1": "free", "k2": "pro", "k3": "pro"}
+	resolver := NewCachingResolver(ResolverFunc(func(apiKey string) (string, error) {
+		calls++
+		if p, ok := plans[apiKey]; ok {
+			return p, nil
+		}
+		return "", errors.New("unknown API key")
+	}), time.Hour, 2)
+
+	for i := 0; i < 3; i++ {
+		tier, err := resolver.ResolveTier("k1")
+		assert.NoError(t, err)
+		assert.Equal(t, "free", tier)
+	}
+	assert.Equal(t, 1, calls)
+
+	// the failed resolutions are not cached
+	_, err := resolver.ResolveTier("unknown")
+	assert.Error(t, err)
+	_, err = resolver.ResolveTier("unknown")
+	assert.Error(t, err)
+	assert.Equal(t, 3, calls)
+
+	_, _ = resolver.ResolveTier("k2")
+	// the cache is full and reset
+	_, _ = resolver.ResolveTier("k3")
+	_, _ = resolver.ResolveTier("k1")
+	assert.Equal(t, 6, calls)
+	assert.Equal(t, 2, len(resolver.(*cachingResolver).tiers))
+
+	expiring := NewCachingResolver(ResolverFunc(func(apiKey string) (string, error) {
+		calls++
+		return "free", nil
+	}), time.Millisecond, 10)
+	_, _ = expiring.ResolveTier("k1")
+	time.Sleep(5 * time.Millisecond)
+	_, _ = expiring.ResolveTier("k1")
+	assert.Equal(t, 8, calls)
+}
+
+func TestParamOf(t *testing.T) {
+	resolver := ResolverFunc(func(apiKey string) (string, error) {
+		if apiKey == "k1" {
+			return "pro", nil
+		}
+		return "", errors.New("unknown API key")
+	})
+	assert.Equal(t, hotspot.TieredParam{Key: "k1", Tier: "pro"}, ParamOf("k1", resolver))
+	assert.Equal(t, hotspot.TieredParam{Key: "k2"}, ParamOf("k2", resolver))
+}
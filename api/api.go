@@ -1,9 +1,13 @@
 package api
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/deadline"
+	"github.com/alibaba/sentinel-golang/core/stat"
 )
 
 var entryOptsPool = sync.Pool{
@@ -29,6 +33,13 @@ type EntryOptions struct {
 	slotChain    *base.SlotChain
 	args         []interface{}
 	attachments  map[interface{}]interface{}
+	autoExitCtx  context.Context
+	tags         []string
+	// namespace is the namespace of the Instance creating the entry, empty for the global entries.
+	namespace string
+	// idempotent indicates the operation is idempotent, so that it could be retried after blocked.
+	idempotent      bool
+	maxBlockRetries uint32
 }
 
 func (o *EntryOptions) Reset() {
@@ -39,6 +50,11 @@ func (o *EntryOptions) Reset() {
 	o.slotChain = nil
 	o.args = nil
 	o.attachments = nil
+	o.autoExitCtx = nil
+	o.tags = nil
+	o.namespace = ""
+	o.idempotent = false
+	o.maxBlockRetries = 0
 }
 
 type EntryOption func(*EntryOptions)
@@ -107,16 +123,69 @@ func WithAttachments(data map[interface{}]interface{}) EntryOption {
 	}
 }
 
-// Entry is the basic API of Sentinel.
-func Entry(resource string, opts ...EntryOption) (*base.SentinelEntry, *base.BlockError) {
-	options := entryOptsPool.Get().(*EntryOptions)
-	options.slotChain = globalSlotChain
+// WithOrigin sets the origin (i.e. the caller) of the resource entry, which is carried by the attachment
+// base.OriginAttachmentKey.
+func WithOrigin(origin string) EntryOption {
+	return WithAttachment(base.OriginAttachmentKey, origin)
+}
+
+// WithCategory categorizes the resource entry (e.g. base.CategoryRead), which is carried by the attachment
+// base.CategoryAttachmentKey. The statistics of each category are maintained separately,
+// and the rules targeting the category (e.g. flow.Rule.Category) only take effect on the requests of the category.
+func WithCategory(category base.RequestCategory) EntryOption {
+	return WithAttachment(base.CategoryAttachmentKey, category)
+}
+
+// WithAutoExit binds the entry to the context, the passed entry is exited automatically with the error
+// of the context (e.g. context.Canceled) when the context is done before the entry is exited,
+// which covers the handlers returning early on client disconnect without calling Exit.
+// It costs a goroutine per passed entry until the entry is exited.
+func WithAutoExit(ctx context.Context) EntryOption {
+	return func(opts *EntryOptions) {
+		opts.autoExitCtx = ctx
+	}
+}
+
+// WithLatencyBudget returns the context carrying the total latency budget of the inbound request,
+// the nested entries with WithBudgetOf(ctx) are checked against the remaining budget.
+func WithLatencyBudget(ctx context.Context, budget time.Duration) context.Context {
+	return deadline.WithBudget(ctx, budget)
+}
 
-	for _, opt := range opts {
-		opt(options)
+// WithBudgetOf carries the remaining latency budget of the context (see WithLatencyBudget and
+// context.WithDeadline) by the attachment deadline.DeadlineAttachmentKey, so that the entry is rejected
+// if the remaining budget is insufficient according to the deadline rules. It takes no effect if the
+// context has no budget.
+func WithBudgetOf(ctx context.Context) EntryOption {
+	d, ok := deadline.DeadlineOf(ctx)
+	if !ok {
+		return func(opts *EntryOptions) {}
 	}
+	return WithAttachment(deadline.DeadlineAttachmentKey, d)
+}
 
-	return entry(resource, options)
+// WithIdempotent marks the operation of the entry idempotent, so that DoWithFallback could retry it
+// after the wait hint (see base.BlockError.WaitHint) when blocked. The non-idempotent operations are never retried.
+func WithIdempotent() EntryOption {
+	return func(opts *EntryOptions) {
+		opts.idempotent = true
+	}
+}
+
+// WithMaxBlockRetries sets the max retries of the idempotent operation after blocked (DefaultMaxBlockRetries by default).
+// It takes no effect on the non-idempotent operations.
+func WithMaxBlockRetries(maxRetries uint32) EntryOption {
+	return func(opts *EntryOptions) {
+		opts.maxBlockRetries = maxRetries
+	}
+}
+
+// WithTags attaches the tags (e.g. "tier=gold", "team=payment") to the resource, so that the rules targeting
+// the tags (e.g. flow.Rule.TargetTag) take effect on the resource. The tags are kept once attached.
+func WithTags(tags ...string) EntryOption {
+	return func(opts *EntryOptions) {
+		opts.tags = append(opts.tags, tags...)
+	}
 }
 
 func entry(resource string, options *EntryOptions) (*base.SentinelEntry, *base.BlockError) {
@@ -126,6 +195,14 @@ func entry(resource string, options *EntryOptions) (*base.SentinelEntry, *base.B
 	if sc == nil {
 		return base.NewSentinelEntry(nil, rw, nil), nil
 	}
+	if stat.IsResourceOverflowRejected(resource) {
+		options.Reset()
+		entryOptsPool.Put(options)
+		return nil, base.NewBlockErrorWithMessage(base.BlockTypeResourceOverflow, "resource amount exceeds the max")
+	}
+	if len(options.tags) != 0 {
+		base.TagResource(resource, options.tags...)
+	}
 	// Get context from pool.
 	ctx := sc.GetPooledContext()
 	ctx.Resource = rw
@@ -137,6 +214,7 @@ func entry(resource string, options *EntryOptions) (*base.SentinelEntry, *base.B
 	if len(options.attachments) != 0 {
 		ctx.Input.Attachments = options.attachments
 	}
+	autoExitCtx := options.autoExitCtx
 	options.Reset()
 	entryOptsPool.Put(options)
 	e := base.NewSentinelEntry(ctx, rw, sc)
@@ -153,6 +231,9 @@ func entry(resource string, options *EntryOptions) (*base.SentinelEntry, *base.B
 		e.Exit()
 		return nil, blockErr
 	}
+	if autoExitCtx != nil {
+		e.ExitOnDone(autoExitCtx)
+	}
 
 	return e, nil
 }
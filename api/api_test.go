@@ -1,9 +1,16 @@
+//go:build !sentinel_noop
+// +build !sentinel_noop
+
 package api
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/stat"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -119,6 +126,9 @@ func Test_entryWithArgsAndChainBlock(t *testing.T) {
 	assert.Nil(t, entry)
 	assert.NotNil(t, b)
 	assert.Equal(t, blockType, b.BlockType())
+	assert.True(t, errors.Is(b, ErrBlocked))
+	assert.True(t, errors.Is(b, ErrFlowBlocked))
+	assert.False(t, errors.Is(b, ErrSystemBlocked))
 
 	ps1.AssertNumberOfCalls(t, "Prepare", 1)
 	rcs1.AssertNumberOfCalls(t, "Check", 1)
@@ -127,3 +137,43 @@ func Test_entryWithArgsAndChainBlock(t *testing.T) {
 	ssm.AssertNumberOfCalls(t, "OnEntryBlocked", 1)
 	ssm.AssertNumberOfCalls(t, "OnCompleted", 0)
 }
+
+func TestEntryWithAutoExit(t *testing.T) {
+	sc := base.NewSlotChain()
+	sc.AddStatPrepareSlotLast(&stat.ResourceNodePrepareSlot{})
+	sc.AddStatSlotLast(&stat.Slot{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e, b := Entry("auto-exit-abc", WithSlotChain(sc), WithAutoExit(ctx))
+	assert.Nil(t, b)
+	node := stat.GetResourceNode("auto-exit-abc")
+	assert.Equal(t, int32(1), node.CurrentGoroutineNum())
+
+	cancel()
+	assert.Eventually(t, func() bool {
+		return node.CurrentGoroutineNum() == 0
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int64(1), node.GetSum(base.MetricEventError))
+	// the late exit is ignored
+	e.Exit(base.WithError(errors.New("late error")))
+	assert.Equal(t, int64(1), node.GetSum(base.MetricEventComplete))
+
+	// the entry exited normally is never exited again
+	ctx, cancel = context.WithCancel(context.Background())
+	e, b = Entry("auto-exit-abc", WithSlotChain(sc), WithAutoExit(ctx))
+	assert.Nil(t, b)
+	e.Exit()
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, int64(2), node.GetSum(base.MetricEventComplete))
+	assert.Equal(t, int64(1), node.GetSum(base.MetricEventError))
+}
+
+func TestEntryWithTags(t *testing.T) {
+	defer base.ClearResourceTags()
+
+	e, b := Entry("entry-with-tags", WithTags("tier=gold", "team=payment"))
+	assert.Nil(t, b)
+	e.Exit()
+	assert.Equal(t, []string{"tier=gold", "team=payment"}, base.ResourceTags("entry-with-tags"))
+}
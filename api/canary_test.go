@@ -0,0 +1,36 @@
+//go:build !sentinel_noop
+// +build !sentinel_noop
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntryWithCanaryOfResourceWithoutRules(t *testing.T) {
+	res := "canary-without-rules"
+	assert.False(t, base.ResourceHasRules(res))
+
+	err := flow.StartCanary(res, []*flow.Rule{{Resource: res, Threshold: 0, StatIntervalInMs: 20000}}, 100, time.Minute)
+	assert.Nil(t, err)
+	defer flow.AbortCanary(res)
+
+	_, b := Entry(res)
+	assert.NotNil(t, b)
+	assert.Equal(t, base.BlockTypeFlow, b.BlockType())
+	stat, ok := flow.CanaryStatOf(res)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), stat.Canary.RequestCount)
+	assert.Equal(t, uint64(1), stat.Canary.BlockedCount)
+
+	flow.AbortCanary(res)
+	assert.False(t, base.ResourceHasRules(res))
+	e, b := Entry(res)
+	assert.Nil(t, b)
+	e.Exit()
+}
@@ -0,0 +1,38 @@
+//go:build !sentinel_noop
+// +build !sentinel_noop
+
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/deadline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntryWithBudgetOf(t *testing.T) {
+	defer deadline.ClearRules()
+
+	_, err := deadline.LoadRules([]*deadline.Rule{{Resource: "deadline-test", MinBudgetMs: 100}})
+	assert.Nil(t, err)
+
+	// no budget carried
+	e, b := Entry("deadline-test", WithBudgetOf(context.Background()))
+	assert.Nil(t, b)
+	e.Exit()
+
+	ctx := WithLatencyBudget(context.Background(), time.Second)
+	e, b = Entry("deadline-test", WithBudgetOf(ctx))
+	assert.Nil(t, b)
+	e.Exit()
+
+	ctx = WithLatencyBudget(context.Background(), 50*time.Millisecond)
+	_, b = Entry("deadline-test", WithBudgetOf(ctx))
+	assert.NotNil(t, b)
+	assert.Equal(t, base.BlockTypeDeadline, b.BlockType())
+	assert.True(t, errors.Is(b, ErrDeadlineBlocked))
+}
@@ -47,4 +47,47 @@
 //  }
 //  <-ch
 //
+// If the entry completes on another goroutine (e.g. in the event-driven pipelines), transfer the exit
+// to the async handle, which measures the RT and concurrency until the handle is exited:
+//
+//  e, b := sentinel.Entry("some-test")
+//  if b == nil {
+//      h := e.ExitAsync()
+//      go func() {
+//          defer h.Exit()
+//          // the async logic
+//      }()
+//  }
+//
+// The block errors (and the errors wrapping them) could be checked with errors.Is against the sentinel errors,
+// e.g. errors.Is(err, sentinel.ErrBlocked) for any block, and errors.Is(err, sentinel.ErrFlowBlocked) for flow control.
+//
+// DoWithFallback wraps the entry, the logic and the fallback on block. The operations marked idempotent are
+// retried after the wait hint of the block error (e.g. the throttling flow control), while the others never:
+//
+//  err := sentinel.DoWithFallback("some-test", func() error {
+//      return queryStock()
+//  }, func(b *base.BlockError) error {
+//      return errStockUnavailable
+//  }, sentinel.WithIdempotent())
+//
+// Each protection layer could be disabled at runtime without clearing the rules, e.g. sentinel.SetFlowEnabled(false)
+// and sentinel.SetCircuitBreakerEnabled(false), and enabled again later. In the break-glass scenarios,
+// sentinel.PauseAll(duration) bypasses all the rule checks temporarily, while the statistics are still recorded.
+//
+// The libraries could embed the instrumentation unconditionally, and the consumers opt out entirely at build time
+// by the build tag sentinel_noop (e.g. go build -tags sentinel_noop), under which Entry always passes without any
+// rule checking or statistic, and TraceError does nothing.
+//
+// The libraries could also use an isolated instance instead of the process-global rules, so that
+// the rules of the library and the application don't replace each other:
+//
+//  s, err := sentinel.New(sentinel.InstanceConfig{Namespace: "my-lib"})
+//  if err != nil {
+//      // handle the error
+//  }
+//  defer s.Close()
+//  _ = s.LoadFlowRules([]*flow.Rule{{Resource: "some-test", Threshold: 10, StatIntervalInMs: 1000}})
+//  e, b := s.Entry("some-test")
+//
 package api
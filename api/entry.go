@@ -0,0 +1,20 @@
+//go:build !sentinel_noop
+// +build !sentinel_noop
+
+package api
+
+import (
+	"github.com/alibaba/sentinel-golang/core/base"
+)
+
+// Entry is the basic API of Sentinel.
+// The resource name is normalized by the global resource name normalizers, see SetResourceNameNormalizers.
+func Entry(resource string, opts ...EntryOption) (*base.SentinelEntry, *base.BlockError) {
+	options := entryOptsPool.Get().(*EntryOptions)
+	options.slotChain = globalSlotChain
+
+	for _, opt := range opts {
+		opt(options)
+	}
+	return entry(entryResourceOf(resource, options.namespace), options)
+}
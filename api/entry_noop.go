@@ -0,0 +1,15 @@
+//go:build sentinel_noop
+// +build sentinel_noop
+
+package api
+
+import (
+	"github.com/alibaba/sentinel-golang/core/base"
+)
+
+// Entry always passes without any rule checking and statistic under the build tag sentinel_noop,
+// so that the libraries could embed the instrumentation unconditionally while the consumers opt out
+// entirely at build time. The options are ignored, and exiting the returned entry does nothing.
+func Entry(resource string, _ ...EntryOption) (*base.SentinelEntry, *base.BlockError) {
+	return base.NewSentinelEntry(nil, base.NewResourceWrapper(resource, base.ResTypeCommon, base.Outbound), nil), nil
+}
@@ -0,0 +1,26 @@
+//go:build sentinel_noop
+// +build sentinel_noop
+
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoopEntry(t *testing.T) {
+	_, err := flow.LoadRules([]*flow.Rule{{Resource: "noop-res", TokenCalculateStrategy: flow.Direct, ControlBehavior: flow.Reject, Threshold: 0}})
+	assert.NoError(t, err)
+	defer flow.ClearRules()
+
+	e, b := Entry("noop-res", WithTrafficType(base.Inbound))
+	assert.Nil(t, b)
+	assert.Equal(t, "noop-res", e.Resource().Name())
+	TraceError(e, errors.New("biz error"))
+	e.Exit()
+	e.Exit()
+}
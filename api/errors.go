@@ -0,0 +1,22 @@
+package api
+
+import "github.com/alibaba/sentinel-golang/core/base"
+
+// The errors of the blocked requests, so that the callers could check the block errors
+// returned by Entry with errors.Is, e.g. errors.Is(err, sentinel.ErrFlowBlocked).
+// The concrete *base.BlockError could still be extracted with errors.As.
+var (
+	ErrBlocked                 = base.ErrBlocked
+	ErrFlowBlocked             = base.ErrFlowBlocked
+	ErrIsolationBlocked        = base.ErrIsolationBlocked
+	ErrCircuitBreakingBlocked  = base.ErrCircuitBreakingBlocked
+	ErrSystemBlocked           = base.ErrSystemBlocked
+	ErrHotSpotParamBlocked     = base.ErrHotSpotParamBlocked
+	ErrQuotaBlocked            = base.ErrQuotaBlocked
+	ErrDefaultDenyBlocked      = base.ErrDefaultDenyBlocked
+	ErrResourceOverflowBlocked = base.ErrResourceOverflowBlocked
+	ErrCompositeBlocked        = base.ErrCompositeBlocked
+	ErrChaosBlocked            = base.ErrChaosBlocked
+	ErrInternalErrorBlocked    = base.ErrInternalErrorBlocked
+	ErrDeadlineBlocked         = base.ErrDeadlineBlocked
+)
@@ -0,0 +1,44 @@
+//go:build !sentinel_noop
+// +build !sentinel_noop
+
+package api
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+type panicStatSlot struct {
+}
+
+func (s *panicStatSlot) OnEntryPassed(_ *base.EntryContext) {
+	panic("stat slot panic")
+}
+
+func (s *panicStatSlot) OnEntryBlocked(_ *base.EntryContext, _ *base.BlockError) {
+}
+
+func (s *panicStatSlot) OnCompleted(_ *base.EntryContext) {
+}
+
+func TestEntryWithStatSlotPanic_FailClosed(t *testing.T) {
+	defer base.ResetFailurePolicies()
+	base.SetFailurePolicy(base.FailClosed)
+
+	sc := base.NewSlotChain()
+	sc.AddStatPrepareSlotLast(&stat.ResourceNodePrepareSlot{})
+	sc.AddStatSlotLast(&stat.Slot{})
+	sc.AddStatSlotLast(&panicStatSlot{})
+
+	// the request recorded as passed keeps passing, so that it's completed on exit
+	e, b := Entry("stat-slot-panic", WithSlotChain(sc))
+	assert.Nil(t, b)
+	assert.NotNil(t, e.Context().Err())
+	node := stat.GetResourceNode("stat-slot-panic")
+	assert.Equal(t, int32(1), node.CurrentGoroutineNum())
+	e.Exit()
+	assert.Equal(t, int32(0), node.CurrentGoroutineNum())
+}
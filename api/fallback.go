@@ -0,0 +1,58 @@
+package api
+
+import (
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/retry"
+)
+
+const (
+	// DefaultMaxBlockRetries is the default max retries of the idempotent operations after blocked.
+	DefaultMaxBlockRetries uint32 = 3
+)
+
+// DoWithFallback runs fn within the resource entry, and the error returned by fn is traced to the entry.
+// If the entry is blocked, the operation marked by WithIdempotent is retried after the wait hint
+// of the block error (e.g. the throttling flow control) until the max retries (see WithMaxBlockRetries)
+// is reached or the retry budget of the resource (see package retry) is exhausted. The block errors
+// without wait hint and the non-idempotent operations are never retried. Then the fallback is invoked
+// with the block error and its result is returned, or the block error is returned if fallback is nil.
+func DoWithFallback(resource string, fn func() error, fallback func(*base.BlockError) error, opts ...EntryOption) error {
+	options := fallbackOptionsOf(opts)
+	// The retry budget is kept by the resource name the entry is created with.
+	retryResource := entryResourceOf(resource, options.namespace)
+	retry.RecordRequest(retryResource)
+
+	e, b := Entry(resource, opts...)
+	for retries := uint32(0); b != nil && options.idempotent && retries < options.maxBlockRetries; retries++ {
+		waitHint := b.WaitHint()
+		if waitHint <= 0 || !retry.TryAcquireRetry(retryResource) {
+			break
+		}
+		time.Sleep(waitHint)
+		e, b = Entry(resource, opts...)
+	}
+	if b != nil {
+		if fallback == nil {
+			return b
+		}
+		return fallback(b)
+	}
+	defer e.Exit()
+
+	err := fn()
+	if err != nil {
+		TraceError(e, err)
+	}
+	return err
+}
+
+// fallbackOptionsOf evaluates the entry options regarding the retries after blocked and the resource name.
+func fallbackOptionsOf(opts []EntryOption) *EntryOptions {
+	options := &EntryOptions{maxBlockRetries: DefaultMaxBlockRetries}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
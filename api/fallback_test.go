@@ -0,0 +1,94 @@
+//go:build !sentinel_noop
+// +build !sentinel_noop
+
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoWithFallback(t *testing.T) {
+	defer flow.ClearRules()
+
+	errFallback := errors.New("fallback")
+	fallback := func(b *base.BlockError) error {
+		assert.Equal(t, base.BlockTypeFlow, b.BlockType())
+		return errFallback
+	}
+	_, err := flow.LoadRules([]*flow.Rule{{
+		Resource:               "fallback-test",
+		TokenCalculateStrategy: flow.Direct,
+		ControlBehavior:        flow.Throttling,
+		Threshold:              20,
+		MaxQueueingTimeMs:      1,
+		StatIntervalInMs:       1000,
+	}})
+	assert.Nil(t, err)
+
+	calls := 0
+	fn := func() error {
+		calls++
+		return nil
+	}
+	assert.Nil(t, DoWithFallback("fallback-test", fn, fallback))
+	// the non-idempotent operation is never retried
+	assert.Equal(t, errFallback, DoWithFallback("fallback-test", fn, fallback))
+	assert.Equal(t, 1, calls)
+
+	// the idempotent operation passes after the wait hint
+	assert.Nil(t, DoWithFallback("fallback-test", fn, fallback, WithIdempotent()))
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, errFallback, DoWithFallback("fallback-test", fn, fallback, WithIdempotent(), WithMaxBlockRetries(0)))
+	assert.Equal(t, 2, calls)
+
+	// the block error is returned without fallback
+	err = DoWithFallback("fallback-test", fn, nil)
+	assert.True(t, errors.Is(err, ErrFlowBlocked))
+
+	errBiz := errors.New("biz")
+	assert.Equal(t, errBiz, DoWithFallback("fallback-test-pass", func() error {
+		return errBiz
+	}, fallback))
+}
+
+func TestDoWithFallbackRetryBudgetOfNormalizedResource(t *testing.T) {
+	defer func() {
+		_ = flow.ClearRules()
+		_ = retry.ClearRules()
+		SetResourceNameNormalizers()
+	}()
+	SetResourceNameNormalizers(LowercaseResourceName)
+
+	_, err := flow.LoadRules([]*flow.Rule{{
+		Resource:               "fallback-budget",
+		TokenCalculateStrategy: flow.Direct,
+		ControlBehavior:        flow.Throttling,
+		Threshold:              20,
+		MaxQueueingTimeMs:      1,
+		StatIntervalInMs:       1000,
+	}})
+	assert.Nil(t, err)
+	// No retries are allowed by the budget.
+	_, err = retry.LoadRules([]*retry.Rule{{Resource: "fallback-budget", StatIntervalMs: 10000}})
+	assert.Nil(t, err)
+
+	calls := 0
+	fn := func() error {
+		calls++
+		return nil
+	}
+	errFallback := errors.New("fallback")
+	fallback := func(*base.BlockError) error {
+		return errFallback
+	}
+	assert.Nil(t, DoWithFallback("Fallback-Budget", fn, fallback, WithIdempotent()))
+	// The retry budget of the normalized resource takes effect.
+	assert.Equal(t, errFallback, DoWithFallback("Fallback-Budget", fn, fallback, WithIdempotent()))
+	assert.Equal(t, 1, calls)
+}
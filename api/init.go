@@ -2,10 +2,22 @@ package api
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/alibaba/sentinel-golang/core/anomaly"
+	"github.com/alibaba/sentinel-golang/core/audit"
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
 	"github.com/alibaba/sentinel-golang/core/config"
+	"github.com/alibaba/sentinel-golang/core/exporter"
+	"github.com/alibaba/sentinel-golang/core/flow"
 	"github.com/alibaba/sentinel-golang/core/log/metric"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	sbase "github.com/alibaba/sentinel-golang/core/stat/base"
 	"github.com/alibaba/sentinel-golang/core/system"
+	"github.com/alibaba/sentinel-golang/ext/datasource"
+	"github.com/alibaba/sentinel-golang/logging"
 	"github.com/alibaba/sentinel-golang/util"
 )
 
@@ -42,6 +54,37 @@ func InitWithConfig(confEntity *config.Entity) (err error) {
 	return initCoreComponents()
 }
 
+// InitAndWait initializes Sentinel using the configuration from system environment and the default value,
+// then initializes the given datasources and blocks until all the registered datasources have delivered
+// their first successful rule payload or config.InitialRulesTimeoutMs elapses, which prevents the unprotected
+// warm-up window after deploys. If the timeout elapses, error is returned when config.InitialRulesRequired
+// is true, otherwise a warning is logged.
+func InitAndWait(sources ...datasource.DataSource) error {
+	if err := InitDefault(); err != nil {
+		return err
+	}
+	return initDataSourcesAndWait(sources)
+}
+
+func initDataSourcesAndWait(sources []datasource.DataSource) error {
+	for _, ds := range sources {
+		if err := ds.Initialize(); err != nil {
+			if config.InitialRulesRequired() {
+				return err
+			}
+			logging.Error(err, "[InitAndWait] Failed to initialize the datasource")
+		}
+	}
+	timeout := time.Duration(config.InitialRulesTimeoutMs()) * time.Millisecond
+	if err := datasource.WaitForInitialRules(timeout); err != nil {
+		if config.InitialRulesRequired() {
+			return err
+		}
+		logging.Warn("[InitAndWait] Sentinel is initialized without the initial rules", "err", err.Error())
+	}
+	return nil
+}
+
 // Init loads Sentinel general configuration from the given YAML file
 // and initializes Sentinel.
 func InitWithConfigFile(configPath string) error {
@@ -51,6 +94,15 @@ func InitWithConfigFile(configPath string) error {
 // initCoreComponents init core components with default config
 // it's better SetDefaultConfig before initCoreComponents
 func initCoreComponents() error {
+	sbase.SetShardedCounterEnabled(config.ShardedCounterEnabled())
+	selfmetric.SetEnabled(config.SelfMetricEnabled())
+	initFailurePolicies()
+	if config.ResourceOverflowStrategy() == config.ResourceOverflowReject {
+		stat.SetMaxResourceAmount(config.MaxResourceAmount(), stat.OverflowReject)
+	} else {
+		stat.SetMaxResourceAmount(config.MaxResourceAmount(), stat.OverflowAggregate)
+	}
+
 	if config.MetricLogFlushIntervalSec() > 0 {
 		if err := metric.InitTask(); err != nil {
 			return err
@@ -58,12 +110,51 @@ func initCoreComponents() error {
 	}
 
 	if config.SystemStatCollectIntervalMs() > 0 {
+		if err := system.SetCpuUsageSmoothingFactor(config.CpuUsageSmoothingFactor()); err != nil {
+			return err
+		}
 		system.InitCollector(config.SystemStatCollectIntervalMs())
 	}
 
+	if config.ConcurrencySampleIntervalMs() > 0 {
+		stat.InitConcurrencyGaugeSampler(config.ConcurrencySampleIntervalMs())
+	}
+
+	if config.ResourceNodeIdleTtlMs() > 0 {
+		stat.InitResourceNodeReaper(config.ResourceNodeIdleTtlMs())
+	}
+
+	if config.AsyncEntryLeakTimeoutMs() > 0 {
+		base.StartAsyncEntryLeakDetector(config.AsyncEntryLeakTimeoutMs())
+	}
+
+	circuitbreaker.SetColdStartSuppression(time.Duration(config.ColdStartSuppressionSec()) * time.Second)
+
+	if config.ScaleWarmUpSec() > 0 {
+		flow.WarmUpFreshInstance(time.Duration(config.ScaleWarmUpSec())*time.Second, config.ScaleWarmUpColdFactor())
+	}
+
+	if config.BlockAnomalyDetectIntervalMs() > 0 {
+		if err := anomaly.StartDetector(anomaly.Options{IntervalMs: config.BlockAnomalyDetectIntervalMs()}); err != nil {
+			return err
+		}
+	}
+
+	if config.RuleDumpIntervalSec() > 0 {
+		registerRuleGetters()
+		if err := audit.StartRuleDump(config.RuleDumpFile(), time.Duration(config.RuleDumpIntervalSec())*time.Second); err != nil {
+			return err
+		}
+	}
+
+	if config.MetricExportIntervalMs() > 0 {
+		exporter.StartExportTask(time.Duration(config.MetricExportIntervalMs()) * time.Millisecond)
+	}
+
 	if config.UseCacheTime() {
-		util.StartTimeTicker()
+		util.StartTimeTickerWithResolution(time.Duration(config.TimeTickerResolutionMs()) * time.Millisecond)
 	}
+	base.SetPreciseRtEnabled(config.PreciseRt())
 
 	return nil
 }
@@ -84,3 +175,16 @@ func initSentinel(configPath string) (err error) {
 	}
 	return initCoreComponents()
 }
+
+// initFailurePolicies applies the failure policies of the config, which have been validated.
+func initFailurePolicies() {
+	base.ResetFailurePolicies()
+	if p, err := base.ParseFailurePolicy(config.FailurePolicy()); err == nil {
+		base.SetFailurePolicy(p)
+	}
+	for module, s := range config.ModuleFailurePolicies() {
+		if p, err := base.ParseFailurePolicy(s); err == nil {
+			base.SetModuleFailurePolicy(module, p)
+		}
+	}
+}
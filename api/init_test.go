@@ -0,0 +1,61 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/config"
+	"github.com/alibaba/sentinel-golang/ext/datasource"
+	"github.com/stretchr/testify/assert"
+)
+
+// asyncDataSource delivers the rule payload asynchronously after delay on Initialize.
+type asyncDataSource struct {
+	datasource.Base
+	delay time.Duration
+}
+
+func (s *asyncDataSource) ReadSource() ([]byte, error) {
+	return []byte("[]"), nil
+}
+
+func (s *asyncDataSource) Initialize() error {
+	datasource.Register("async", s)
+	go func() {
+		time.Sleep(s.delay)
+		_ = s.Handle([]byte("[]"))
+	}()
+	return nil
+}
+
+func (s *asyncDataSource) Close() error {
+	datasource.Deregister(s)
+	return nil
+}
+
+func Test_initDataSourcesAndWait(t *testing.T) {
+	defer config.SetDefaultConfig(config.NewDefaultConfig())
+	conf := config.NewDefaultConfig()
+	conf.Sentinel.Datasource.InitialRulesTimeoutMs = 50
+	config.SetDefaultConfig(conf)
+
+	t.Run("Delivered", func(t *testing.T) {
+		ds := &asyncDataSource{delay: 10 * time.Millisecond}
+		defer ds.Close()
+		assert.Nil(t, initDataSourcesAndWait([]datasource.DataSource{ds}))
+		assert.True(t, ds.Health().LastUpdateTime > 0)
+	})
+
+	t.Run("TimeoutNotRequired", func(t *testing.T) {
+		ds := &asyncDataSource{delay: time.Second}
+		defer ds.Close()
+		assert.Nil(t, initDataSourcesAndWait([]datasource.DataSource{ds}))
+	})
+
+	t.Run("TimeoutRequired", func(t *testing.T) {
+		conf.Sentinel.Datasource.InitialRulesRequired = true
+		ds := &asyncDataSource{delay: time.Second}
+		defer ds.Close()
+		assert.NotNil(t, initDataSourcesAndWait([]datasource.DataSource{ds}))
+	})
+}
@@ -0,0 +1,269 @@
+package api
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
+	"github.com/alibaba/sentinel-golang/core/exporter"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/hotspot"
+	"github.com/alibaba/sentinel-golang/core/isolation"
+	"github.com/pkg/errors"
+)
+
+// NamespaceSeparator separates the namespace of the Instance and the resource name in the namespaced resource names.
+const NamespaceSeparator = "::"
+
+// NamespacedResource returns the name of the resource in the namespace, i.e. the name in the global rule managers
+// and statistics.
+func NamespacedResource(namespace, resource string) string {
+	return namespace + NamespaceSeparator + resource
+}
+
+// InstanceConfig is the configuration of an Instance.
+type InstanceConfig struct {
+	// Namespace isolates the resources of the Instance, which must be unique in the process.
+	Namespace string
+	// SlotChain is the slot chain of the entries of the Instance, BuildDefaultSlotChain() if nil.
+	SlotChain *base.SlotChain
+}
+
+// Instance is an isolated Sentinel instance, so that the libraries could embed Sentinel without fighting over
+// the process-global rule managers with the application (or the other libraries).
+//
+// The resources of the Instance are namespaced (see NamespacedResource), so the rules, statistics and exporters
+// of the Instance only see its own resources, while the Instance still shares the global configuration,
+// the system adaptive rules and the background tasks (e.g. the metric log) of the process.
+// The rules of the Instance are loaded into the namespace of the Instance in the rule managers
+// (e.g. flow.LoadRulesOfNamespace), which are kept when the global rules are loaded.
+type Instance struct {
+	namespace string
+	chain     *base.SlotChain
+
+	mux       sync.Mutex
+	exporters []string
+	closed    bool
+}
+
+var (
+	instances    = make(map[string]*Instance)
+	instancesMux = new(sync.Mutex)
+)
+
+// New creates an isolated Sentinel instance with the given config, which should be closed once no longer used.
+func New(cfg InstanceConfig) (*Instance, error) {
+	if len(cfg.Namespace) == 0 {
+		return nil, errors.New("empty namespace")
+	}
+	if strings.Contains(cfg.Namespace, NamespaceSeparator) {
+		return nil, errors.Errorf("namespace %s contains the separator %s", cfg.Namespace, NamespaceSeparator)
+	}
+	chain := cfg.SlotChain
+	if chain == nil {
+		chain = BuildDefaultSlotChain()
+	}
+
+	instancesMux.Lock()
+	defer instancesMux.Unlock()
+
+	if _, exists := instances[cfg.Namespace]; exists {
+		return nil, errors.Errorf("duplicate instance of namespace %s", cfg.Namespace)
+	}
+	i := &Instance{namespace: cfg.Namespace, chain: chain}
+	instances[cfg.Namespace] = i
+	return i, nil
+}
+
+// Namespace returns the namespace of the Instance.
+func (i *Instance) Namespace() string {
+	return i.namespace
+}
+
+// Entry creates the entry of the resource in the namespace of the Instance, see the global Entry.
+func (i *Instance) Entry(resource string, opts ...EntryOption) (*base.SentinelEntry, *base.BlockError) {
+	// Copy the options, as appending to opts could write into the backing array of the caller.
+	o := make([]EntryOption, 0, len(opts)+1)
+	o = append(append(o, opts...), i.entryOption)
+	return Entry(resource, o...)
+}
+
+func (i *Instance) entryOption(opts *EntryOptions) {
+	opts.namespace = i.namespace
+	opts.slotChain = i.chain
+}
+
+func (i *Instance) resourceOf(resource string) string {
+	return NamespacedResource(i.namespace, resource)
+}
+
+// LoadFlowRules loads the flow rules of the Instance, which replace the previous flow rules of the Instance.
+// The resources (and the associated resources) of the rules are the names within the namespace.
+// The rules targeting tags are not supported, as the resource tags are global rather than namespaced.
+func (i *Instance) LoadFlowRules(rules []*flow.Rule) error {
+	namespaced := make([]*flow.Rule, 0, len(rules))
+	for _, r := range rules {
+		if r == nil {
+			continue
+		}
+		if len(r.TargetTag) > 0 {
+			return errors.Errorf("flow rules targeting tags are not supported in the instance of namespace %s, target tag: %s",
+				i.namespace, r.TargetTag)
+		}
+		c := *r
+		c.Resource = i.resourceOf(r.Resource)
+		if len(r.RefResource) > 0 {
+			c.RefResource = i.resourceOf(r.RefResource)
+		}
+		namespaced = append(namespaced, &c)
+	}
+	_, err := flow.LoadRulesOfNamespace(i.namespace, namespaced)
+	return err
+}
+
+// LoadCircuitBreakerRules loads the circuit breaking rules of the Instance, which replace the previous
+// circuit breaking rules of the Instance.
+func (i *Instance) LoadCircuitBreakerRules(rules []*circuitbreaker.Rule) error {
+	namespaced := make([]*circuitbreaker.Rule, 0, len(rules))
+	for _, r := range rules {
+		if r == nil {
+			continue
+		}
+		c := *r
+		c.Resource = i.resourceOf(r.Resource)
+		namespaced = append(namespaced, &c)
+	}
+	_, err, _ := circuitbreaker.LoadRulesOfNamespace(i.namespace, namespaced)
+	return err
+}
+
+// LoadIsolationRules loads the isolation rules of the Instance, which replace the previous isolation rules
+// of the Instance.
+func (i *Instance) LoadIsolationRules(rules []*isolation.Rule) error {
+	namespaced := make([]*isolation.Rule, 0, len(rules))
+	for _, r := range rules {
+		if r == nil {
+			continue
+		}
+		c := *r
+		c.Resource = i.resourceOf(r.Resource)
+		namespaced = append(namespaced, &c)
+	}
+	_, err := isolation.LoadRulesOfNamespace(i.namespace, namespaced)
+	return err
+}
+
+// LoadHotSpotRules loads the hotspot rules of the Instance, which replace the previous hotspot rules of the Instance.
+func (i *Instance) LoadHotSpotRules(rules []*hotspot.Rule) error {
+	namespaced := make([]*hotspot.Rule, 0, len(rules))
+	for _, r := range rules {
+		if r == nil {
+			continue
+		}
+		c := *r
+		c.Resource = i.resourceOf(r.Resource)
+		namespaced = append(namespaced, &c)
+	}
+	_, err := hotspot.LoadRulesOfNamespace(i.namespace, namespaced)
+	return err
+}
+
+// RegisterExporters registers the metric exporters of the Instance, which only export the metrics
+// of the resources of the Instance, with the resource names within the namespace.
+// The exporters are registered with the names prefixed by the namespace.
+func (i *Instance) RegisterExporters(exporters ...exporter.MetricExporter) error {
+	i.mux.Lock()
+	defer i.mux.Unlock()
+
+	for _, e := range exporters {
+		if e == nil {
+			continue
+		}
+		ne := &namespacedExporter{MetricExporter: e, namespace: i.namespace}
+		if err := exporter.RegisterExporters(ne); err != nil {
+			return err
+		}
+		i.exporters = append(i.exporters, ne.Name())
+	}
+	return nil
+}
+
+// Close clears the rules of the Instance and unregisters its exporters, and then the namespace could be reused.
+// The statistics of the resources of the Instance are evicted as the other idle resources.
+func (i *Instance) Close() error {
+	i.mux.Lock()
+	defer i.mux.Unlock()
+
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	var err error
+	if _, e := flow.LoadRulesOfNamespace(i.namespace, nil); e != nil {
+		err = e
+	}
+	if _, e, _ := circuitbreaker.LoadRulesOfNamespace(i.namespace, nil); e != nil {
+		err = e
+	}
+	if _, e := isolation.LoadRulesOfNamespace(i.namespace, nil); e != nil {
+		err = e
+	}
+	if _, e := hotspot.LoadRulesOfNamespace(i.namespace, nil); e != nil {
+		err = e
+	}
+	for _, name := range i.exporters {
+		if e := exporter.UnregisterExporter(name); e != nil {
+			err = e
+		}
+	}
+	i.exporters = nil
+
+	instancesMux.Lock()
+	delete(instances, i.namespace)
+	instancesMux.Unlock()
+	return err
+}
+
+// resetInstances releases the namespaces of all the instances, whose rules and exporters have been cleared
+// by ResetForTesting.
+func resetInstances() {
+	instancesMux.Lock()
+	defer instancesMux.Unlock()
+
+	for ns, i := range instances {
+		i.mux.Lock()
+		i.closed = true
+		i.exporters = nil
+		i.mux.Unlock()
+		delete(instances, ns)
+	}
+}
+
+// namespacedExporter exports the metrics of the resources in the namespace only.
+type namespacedExporter struct {
+	exporter.MetricExporter
+	namespace string
+}
+
+func (e *namespacedExporter) Name() string {
+	return NamespacedResource(e.namespace, e.MetricExporter.Name())
+}
+
+func (e *namespacedExporter) Export(items []*base.MetricItem) error {
+	prefix := e.namespace + NamespaceSeparator
+	ret := make([]*base.MetricItem, 0)
+	for _, item := range items {
+		if !strings.HasPrefix(item.Resource, prefix) {
+			continue
+		}
+		// the items are shared among the exporters
+		c := *item
+		c.Resource = strings.TrimPrefix(item.Resource, prefix)
+		ret = append(ret, &c)
+	}
+	if len(ret) == 0 {
+		return nil
+	}
+	return e.MetricExporter.Export(ret)
+}
@@ -0,0 +1,129 @@
+//go:build !sentinel_noop
+// +build !sentinel_noop
+
+package api
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingExporter struct {
+	items []*base.MetricItem
+}
+
+func (e *recordingExporter) Name() string {
+	return "recording"
+}
+
+func (e *recordingExporter) Start() error {
+	return nil
+}
+
+func (e *recordingExporter) Export(items []*base.MetricItem) error {
+	e.items = append(e.items, items...)
+	return nil
+}
+
+func (e *recordingExporter) Stop() error {
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	defer ResetForTesting()
+
+	_, err := New(InstanceConfig{})
+	assert.NotNil(t, err)
+	_, err = New(InstanceConfig{Namespace: "a::b"})
+	assert.NotNil(t, err)
+
+	i, err := New(InstanceConfig{Namespace: "lib"})
+	assert.Nil(t, err)
+	assert.Equal(t, "lib", i.Namespace())
+	_, err = New(InstanceConfig{Namespace: "lib"})
+	assert.NotNil(t, err)
+
+	assert.Nil(t, i.Close())
+	_, err = New(InstanceConfig{Namespace: "lib"})
+	assert.Nil(t, err)
+}
+
+func TestInstance_Entry(t *testing.T) {
+	defer ResetForTesting()
+
+	a, err := New(InstanceConfig{Namespace: "a"})
+	assert.Nil(t, err)
+	b, err := New(InstanceConfig{Namespace: "b"})
+	assert.Nil(t, err)
+
+	assert.Nil(t, a.LoadFlowRules([]*flow.Rule{{Resource: "res", Threshold: 0, StatIntervalInMs: 1000}}))
+	// the global rules don't replace the rules of the instances
+	_, err = flow.LoadRules([]*flow.Rule{{Resource: "other", Threshold: 0, StatIntervalInMs: 1000}})
+	assert.Nil(t, err)
+
+	_, blockErr := a.Entry("res")
+	assert.NotNil(t, blockErr)
+	assert.Equal(t, base.BlockTypeFlow, blockErr.BlockType())
+
+	e, blockErr := b.Entry("res")
+	assert.Nil(t, blockErr)
+	e.Exit()
+	assert.Equal(t, "b::res", e.Resource().Name())
+
+	e, blockErr = Entry("res")
+	assert.Nil(t, blockErr)
+	e.Exit()
+
+	// the rules targeting tags are rejected, and the previous rules are kept
+	err = a.LoadFlowRules([]*flow.Rule{{TargetTag: "tier=gold", Threshold: 0, StatIntervalInMs: 1000}})
+	assert.NotNil(t, err)
+	_, blockErr = a.Entry("res")
+	assert.NotNil(t, blockErr)
+
+	assert.Nil(t, a.Close())
+	// the rules of the closed instance are cleared
+	e, blockErr = a.Entry("res")
+	assert.Nil(t, blockErr)
+	e.Exit()
+}
+
+func TestInstance_EntryKeepsCallerOptions(t *testing.T) {
+	defer ResetForTesting()
+
+	i, err := New(InstanceConfig{Namespace: "lib"})
+	assert.Nil(t, err)
+
+	// The options with the spare capacity, e.g. the shared options of the caller.
+	opts := make([]EntryOption, 1, 2)
+	opts[0] = WithTrafficType(base.Inbound)
+	e, blockErr := i.Entry("res", opts...)
+	assert.Nil(t, blockErr)
+	e.Exit()
+	assert.Equal(t, "lib::res", e.Resource().Name())
+	// The option of the instance is not written into the spare capacity.
+	assert.Nil(t, opts[:2][1])
+}
+
+func TestInstance_RegisterExporters(t *testing.T) {
+	defer ResetForTesting()
+
+	i, err := New(InstanceConfig{Namespace: "ns"})
+	assert.Nil(t, err)
+	re := &recordingExporter{}
+	assert.Nil(t, i.RegisterExporters(re))
+
+	ne := &namespacedExporter{MetricExporter: re, namespace: "ns"}
+	assert.Equal(t, "ns::recording", ne.Name())
+	items := []*base.MetricItem{{Resource: "ns::abc", PassQps: 1}, {Resource: "abc", PassQps: 2}, {Resource: "other::abc"}}
+	assert.Nil(t, ne.Export(items))
+	assert.Equal(t, 1, len(re.items))
+	assert.Equal(t, "abc", re.items[0].Resource)
+	assert.Equal(t, uint64(1), re.items[0].PassQps)
+	// the shared items are untouched
+	assert.Equal(t, "ns::abc", items[0].Resource)
+
+	assert.Nil(t, i.Close())
+}
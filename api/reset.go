@@ -0,0 +1,94 @@
+package api
+
+import (
+	"sync"
+
+	"github.com/alibaba/sentinel-golang/core/anomaly"
+	"github.com/alibaba/sentinel-golang/core/audit"
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/chaos"
+	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
+	"github.com/alibaba/sentinel-golang/core/composite"
+	"github.com/alibaba/sentinel-golang/core/deadline"
+	"github.com/alibaba/sentinel-golang/core/errorbudget"
+	"github.com/alibaba/sentinel-golang/core/exporter"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/gateway"
+	"github.com/alibaba/sentinel-golang/core/hotspot"
+	"github.com/alibaba/sentinel-golang/core/isolation"
+	"github.com/alibaba/sentinel-golang/core/mirror"
+	"github.com/alibaba/sentinel-golang/core/outlier"
+	"github.com/alibaba/sentinel-golang/core/policy"
+	"github.com/alibaba/sentinel-golang/core/quota"
+	"github.com/alibaba/sentinel-golang/core/retry"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	"github.com/alibaba/sentinel-golang/core/system"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+var resetMux = new(sync.Mutex)
+
+// ResetForTesting restores the process-global state of Sentinel to the initial one, so that the state doesn't leak
+// across tests: the rules of all the rule managers, the statistics of all the resources, the listeners, the exporters
+// the mirror and the registrations of the instances are cleared, the switches are enabled, and the real clock is restored.
+// The concurrent calls are serialized, but it's not safe to run along with the traffic, so it's only for tests.
+func ResetForTesting() {
+	resetMux.Lock()
+	defer resetMux.Unlock()
+
+	ruleClears := []struct {
+		module string
+		clear  func() error
+	}{
+		{"flow", flow.ClearRules},
+		{"isolation", isolation.ClearRules},
+		{"circuitbreaker", circuitbreaker.ClearRules},
+		{"hotspot", hotspot.ClearRules},
+		{"system", system.ClearRules},
+		{"gateway", gateway.ClearRules},
+		{"gatewayApi", gateway.ClearApiDefinitions},
+		{"quota", quota.ClearRules},
+		{"composite", composite.ClearRules},
+		{"chaos", chaos.ClearRules},
+		{"deadline", deadline.ClearRules},
+		{"retry", retry.ClearRules},
+		{"policy", policy.ClearRules},
+		{"outlier", outlier.ClearRules},
+		{"errorbudget", errorbudget.ClearRules},
+	}
+	for _, c := range ruleClears {
+		if err := c.clear(); err != nil {
+			logging.Error(err, "[ResetForTesting] Failed to clear the rules", "module", c.module)
+		}
+	}
+	flow.ResetThresholdScaling()
+	flow.StopScaleWarmUp()
+
+	flow.ClearWarningListeners()
+	flow.ClearRuleUpdateListeners()
+	circuitbreaker.ClearStateChangeListeners()
+	anomaly.ClearListeners()
+	errorbudget.ClearAlertCallbacks()
+	base.ClearInternalErrorListeners()
+	stat.ClearConcurrencyGaugeExporters()
+	stat.StopConcurrencyGaugeSampler()
+	exporter.ClearExporters()
+	mirror.Stop()
+
+	SetFlowEnabled(true)
+	SetCircuitBreakerEnabled(true)
+	SetSystemAdaptiveEnabled(true)
+	SetHotSpotEnabled(true)
+	SetIsolationEnabled(true)
+	ResumeAll()
+	base.ResetFailurePolicies()
+	base.ClearResourceTags()
+
+	resetInstances()
+	stat.ResetResourceNodeMap()
+	audit.Reset()
+	selfmetric.ResetTimers()
+	util.SetClock(nil)
+}
@@ -0,0 +1,43 @@
+//go:build !sentinel_noop
+// +build !sentinel_noop
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResetForTesting(t *testing.T) {
+	_, err := flow.LoadRules([]*flow.Rule{{Resource: "reset-test", Threshold: 0}})
+	assert.Nil(t, err)
+	SetFlowEnabled(false)
+	PauseAll(time.Minute)
+	base.SetFailurePolicy(base.FailClosed)
+	base.TagResource("reset-test", "tier=gold")
+	e, b := Entry("reset-test")
+	assert.Nil(t, b)
+	e.Exit()
+	assert.NotNil(t, stat.GetResourceNode("reset-test"))
+
+	ResetForTesting()
+
+	assert.Equal(t, 0, len(flow.GetRules()))
+	assert.Nil(t, stat.GetResourceNode("reset-test"))
+	assert.False(t, base.RuleChecksPaused())
+	assert.True(t, flow.Enabled())
+	assert.Equal(t, base.FailOpen, base.FailurePolicyOf("flow"))
+	assert.False(t, base.ResourceHasTag("reset-test", "tier=gold"))
+
+	// the rules loaded after the reset take effect
+	_, err = flow.LoadRules([]*flow.Rule{{Resource: "reset-test", Threshold: 0}})
+	assert.Nil(t, err)
+	_, b = Entry("reset-test")
+	assert.NotNil(t, b)
+	ResetForTesting()
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// ResourceNameNormalizer normalizes the resource name before the entry is created,
+// e.g. "/users/123/orders" to "/users/{id}/orders".
+type ResourceNameNormalizer func(resource string) string
+
+// NumericPathSegmentPlaceholder replaces the numeric path segments in StripNumericPathSegments.
+const NumericPathSegmentPlaceholder = "{id}"
+
+var resourceNameNormalizers atomic.Value
+
+func init() {
+	resourceNameNormalizers.Store([]ResourceNameNormalizer(nil))
+}
+
+// SetResourceNameNormalizers sets the global resource name normalizers, which are applied in order to the resource
+// name of every entry (including the entries created by the adapters), so that the accidental cardinality explosions
+// (e.g. the resource names including IDs) are fixed centrally rather than at every call site.
+// The rules should be configured with the normalized resource names. Calling it without normalizers clears them.
+func SetResourceNameNormalizers(normalizers ...ResourceNameNormalizer) {
+	ns := make([]ResourceNameNormalizer, 0, len(normalizers))
+	for _, n := range normalizers {
+		if n != nil {
+			ns = append(ns, n)
+		}
+	}
+	resourceNameNormalizers.Store(ns)
+}
+
+// NormalizeResourceName applies the global resource name normalizers to the resource name.
+func NormalizeResourceName(resource string) string {
+	for _, n := range resourceNameNormalizers.Load().([]ResourceNameNormalizer) {
+		resource = n(resource)
+	}
+	return resource
+}
+
+// entryResourceOf returns the name of the resource the entry is created with, i.e. the normalized name,
+// namespaced if the entry is created by the Instance of the namespace.
+func entryResourceOf(resource, namespace string) string {
+	resource = NormalizeResourceName(resource)
+	if len(namespace) > 0 {
+		resource = NamespacedResource(namespace, resource)
+	}
+	return resource
+}
+
+// LowercaseResourceName is the normalizer that converts the resource name to lower case.
+func LowercaseResourceName(resource string) string {
+	return strings.ToLower(resource)
+}
+
+// StripNumericPathSegments is the normalizer that replaces the numeric segments of the path-like resource name
+// with NumericPathSegmentPlaceholder, e.g. "GET:/users/123" to "GET:/users/{id}".
+func StripNumericPathSegments(resource string) string {
+	if strings.IndexByte(resource, '/') < 0 {
+		return resource
+	}
+	segments := strings.Split(resource, "/")
+	changed := false
+	for i, seg := range segments {
+		// The first segment is the prefix before the path, e.g. "GET:".
+		if i == 0 || !isNumeric(seg) {
+			continue
+		}
+		segments[i] = NumericPathSegmentPlaceholder
+		changed = true
+	}
+	if !changed {
+		return resource
+	}
+	return strings.Join(segments, "/")
+}
+
+func isNumeric(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+//go:build !sentinel_noop
+// +build !sentinel_noop
+
+package api
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripNumericPathSegments(t *testing.T) {
+	assert.Equal(t, "GET:/users/{id}/orders/{id}", StripNumericPathSegments("GET:/users/123/orders/456"))
+	assert.Equal(t, "/users/{id}", StripNumericPathSegments("/users/123"))
+	assert.Equal(t, "/users/abc123/", StripNumericPathSegments("/users/abc123/"))
+	assert.Equal(t, "123", StripNumericPathSegments("123"))
+}
+
+func TestNormalizeResourceName(t *testing.T) {
+	defer SetResourceNameNormalizers()
+
+	assert.Equal(t, "GET:/Users/123", NormalizeResourceName("GET:/Users/123"))
+
+	SetResourceNameNormalizers(LowercaseResourceName, nil, StripNumericPathSegments)
+	assert.Equal(t, "get:/users/{id}", NormalizeResourceName("GET:/Users/123"))
+
+	var resource string
+	sc := base.NewSlotChain()
+	sc.AddStatPrepareSlotLast(prepareFunc(func(ctx *base.EntryContext) {
+		resource = ctx.Resource.Name()
+	}))
+	e, b := Entry("GET:/Users/123", WithSlotChain(sc))
+	assert.Nil(t, b)
+	e.Exit()
+	assert.Equal(t, "get:/users/{id}", resource)
+}
+
+type prepareFunc func(ctx *base.EntryContext)
+
+func (f prepareFunc) Prepare(ctx *base.EntryContext) {
+	f(ctx)
+}
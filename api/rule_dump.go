@@ -0,0 +1,35 @@
+package api
+
+import (
+	"github.com/alibaba/sentinel-golang/core/audit"
+	"github.com/alibaba/sentinel-golang/core/chaos"
+	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
+	"github.com/alibaba/sentinel-golang/core/composite"
+	"github.com/alibaba/sentinel-golang/core/deadline"
+	"github.com/alibaba/sentinel-golang/core/errorbudget"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/hotspot"
+	"github.com/alibaba/sentinel-golang/core/isolation"
+	"github.com/alibaba/sentinel-golang/core/outlier"
+	"github.com/alibaba/sentinel-golang/core/policy"
+	"github.com/alibaba/sentinel-golang/core/quota"
+	"github.com/alibaba/sentinel-golang/core/retry"
+	"github.com/alibaba/sentinel-golang/core/system"
+)
+
+// registerRuleGetters registers the getters of the effective rules of all the rule modules to the rule dump.
+func registerRuleGetters() {
+	audit.RegisterRuleGetter("flow", func() interface{} { return flow.GetRules() })
+	audit.RegisterRuleGetter("circuitbreaker", func() interface{} { return circuitbreaker.GetRules() })
+	audit.RegisterRuleGetter("hotspot", func() interface{} { return hotspot.GetRules() })
+	audit.RegisterRuleGetter("isolation", func() interface{} { return isolation.GetRules() })
+	audit.RegisterRuleGetter("system", func() interface{} { return system.GetRules() })
+	audit.RegisterRuleGetter("outlier", func() interface{} { return outlier.GetRules() })
+	audit.RegisterRuleGetter("retry", func() interface{} { return retry.GetRules() })
+	audit.RegisterRuleGetter("quota", func() interface{} { return quota.GetRules() })
+	audit.RegisterRuleGetter("policy", func() interface{} { return policy.GetRules() })
+	audit.RegisterRuleGetter("errorbudget", func() interface{} { return errorbudget.GetRules() })
+	audit.RegisterRuleGetter("composite", func() interface{} { return composite.GetRules() })
+	audit.RegisterRuleGetter("chaos", func() interface{} { return chaos.GetRules() })
+	audit.RegisterRuleGetter("deadline", func() interface{} { return deadline.GetRules() })
+}
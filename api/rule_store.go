@@ -0,0 +1,24 @@
+package api
+
+import (
+	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/hotspot"
+	"github.com/pkg/errors"
+)
+
+// LoadRulesFromStore loads the flow, circuit breaking and hotspot rules saved in the RuleStore
+// (see base.SetRuleStore), e.g. the worker processes of the prefork servers load the rules from the shared store.
+// The modules without rules saved are left as they are.
+func LoadRulesFromStore() error {
+	if _, err := flow.LoadRulesFromStore(); err != nil {
+		return errors.Wrap(err, "failed to load the flow rules from the store")
+	}
+	if _, err := circuitbreaker.LoadRulesFromStore(); err != nil {
+		return errors.Wrap(err, "failed to load the circuit breaking rules from the store")
+	}
+	if _, err := hotspot.LoadRulesFromStore(); err != nil {
+		return errors.Wrap(err, "failed to load the hotspot rules from the store")
+	}
+	return nil
+}
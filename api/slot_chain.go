@@ -2,11 +2,21 @@ package api
 
 import (
 	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/chaos"
 	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
+	"github.com/alibaba/sentinel-golang/core/composite"
+	"github.com/alibaba/sentinel-golang/core/deadline"
+	"github.com/alibaba/sentinel-golang/core/errorbudget"
 	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/gateway"
 	"github.com/alibaba/sentinel-golang/core/hotspot"
 	"github.com/alibaba/sentinel-golang/core/isolation"
 	"github.com/alibaba/sentinel-golang/core/log"
+	"github.com/alibaba/sentinel-golang/core/mirror"
+	"github.com/alibaba/sentinel-golang/core/outlier"
+	"github.com/alibaba/sentinel-golang/core/policy"
+	"github.com/alibaba/sentinel-golang/core/quota"
+	"github.com/alibaba/sentinel-golang/core/replay"
 	"github.com/alibaba/sentinel-golang/core/stat"
 	"github.com/alibaba/sentinel-golang/core/system"
 )
@@ -30,14 +40,25 @@ func BuildDefaultSlotChain() *base.SlotChain {
 	sc := base.NewSlotChain()
 	sc.AddStatPrepareSlotLast(&stat.ResourceNodePrepareSlot{})
 	sc.AddRuleCheckSlotLast(&system.AdaptiveSlot{})
+	sc.AddRuleCheckSlotLast(&deadline.Slot{})
 	sc.AddRuleCheckSlotLast(&flow.Slot{})
 	sc.AddRuleCheckSlotLast(&isolation.Slot{})
 	sc.AddRuleCheckSlotLast(&circuitbreaker.Slot{})
 	sc.AddRuleCheckSlotLast(&hotspot.Slot{})
+	sc.AddRuleCheckSlotLast(&gateway.Slot{})
+	sc.AddRuleCheckSlotLast(&composite.Slot{})
+	sc.AddRuleCheckSlotLast(&quota.Slot{})
+	sc.AddRuleCheckSlotLast(&policy.Slot{})
+	sc.AddRuleCheckSlotLast(&chaos.Slot{})
 	sc.AddStatSlotLast(&stat.Slot{})
 	sc.AddStatSlotLast(&log.Slot{})
 	sc.AddStatSlotLast(&circuitbreaker.MetricStatSlot{})
 	sc.AddStatSlotLast(&hotspot.ConcurrencyStatSlot{})
 	sc.AddStatSlotLast(&flow.StandaloneStatSlot{})
+	sc.AddStatSlotLast(&outlier.MetricStatSlot{})
+	sc.AddStatSlotLast(&quota.MetricStatSlot{})
+	sc.AddStatSlotLast(&errorbudget.MetricStatSlot{})
+	sc.AddStatSlotLast(&replay.RecordSlot{})
+	sc.AddStatSlotLast(&mirror.Slot{})
 	return sc
 }
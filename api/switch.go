@@ -0,0 +1,54 @@
+package api
+
+import (
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/hotspot"
+	"github.com/alibaba/sentinel-golang/core/isolation"
+	"github.com/alibaba/sentinel-golang/core/system"
+)
+
+// The switches below enable or disable the corresponding protection at runtime, all enabled by default.
+// The disabled rule check slot is short-circuited but the rules are kept, so that operators could instantly
+// disable a misbehaving protection layer, and enable it again without reloading the rules.
+
+// SetFlowEnabled enables or disables the flow control.
+func SetFlowEnabled(enabled bool) {
+	flow.SetEnabled(enabled)
+}
+
+// SetCircuitBreakerEnabled enables or disables the circuit breaking. The circuit breakers keep recording
+// the completed requests while disabled.
+func SetCircuitBreakerEnabled(enabled bool) {
+	circuitbreaker.SetEnabled(enabled)
+}
+
+// SetSystemAdaptiveEnabled enables or disables the system adaptive protection.
+func SetSystemAdaptiveEnabled(enabled bool) {
+	system.SetEnabled(enabled)
+}
+
+// SetHotSpotEnabled enables or disables the hotspot param flow control.
+func SetHotSpotEnabled(enabled bool) {
+	hotspot.SetEnabled(enabled)
+}
+
+// SetIsolationEnabled enables or disables the concurrency isolation.
+func SetIsolationEnabled(enabled bool) {
+	isolation.SetEnabled(enabled)
+}
+
+// PauseAll bypasses all the rule checks for the given duration and re-enables them automatically,
+// while the statistics are still recorded. It's the break-glass switch for the scenarios where the protection
+// itself is suspected of causing an outage. The later call overrides the former one.
+func PauseAll(d time.Duration) {
+	base.PauseRuleChecks(d)
+}
+
+// ResumeAll re-enables the rule checks paused by PauseAll immediately.
+func ResumeAll() {
+	base.ResumeRuleChecks()
+}
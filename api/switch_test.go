@@ -0,0 +1,71 @@
+//go:build !sentinel_noop
+// +build !sentinel_noop
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetFlowEnabled(t *testing.T) {
+	defer func() {
+		SetFlowEnabled(true)
+		_ = flow.ClearRules()
+	}()
+	_, err := flow.LoadRules([]*flow.Rule{{
+		Resource:               "switch-test",
+		TokenCalculateStrategy: flow.Direct,
+		ControlBehavior:        flow.Reject,
+		Threshold:              0,
+		StatIntervalInMs:       1000,
+	}})
+	assert.NoError(t, err)
+
+	_, b := Entry("switch-test")
+	assert.NotNil(t, b)
+
+	SetFlowEnabled(false)
+	e, b := Entry("switch-test")
+	assert.Nil(t, b)
+	e.Exit()
+	assert.Equal(t, 1, len(flow.GetRulesOfResource("switch-test")))
+
+	SetFlowEnabled(true)
+	_, b = Entry("switch-test")
+	assert.NotNil(t, b)
+}
+
+func TestPauseAll(t *testing.T) {
+	defer func() {
+		ResumeAll()
+		_ = flow.ClearRules()
+	}()
+	_, err := flow.LoadRules([]*flow.Rule{{
+		Resource:               "pause-test",
+		TokenCalculateStrategy: flow.Direct,
+		ControlBehavior:        flow.Reject,
+		Threshold:              0,
+		StatIntervalInMs:       1000,
+	}})
+	assert.NoError(t, err)
+
+	_, b := Entry("pause-test")
+	assert.NotNil(t, b)
+
+	PauseAll(time.Hour)
+	e, b := Entry("pause-test")
+	assert.Nil(t, b)
+	e.Exit()
+	// the statistics are still recorded
+	assert.Equal(t, int64(1), stat.GetResourceNode("pause-test").GetSum(base.MetricEventPass))
+
+	ResumeAll()
+	_, b = Entry("pause-test")
+	assert.NotNil(t, b)
+}
@@ -1,3 +1,6 @@
+//go:build !sentinel_noop
+// +build !sentinel_noop
+
 package api
 
 import (
@@ -0,0 +1,12 @@
+//go:build sentinel_noop
+// +build sentinel_noop
+
+package api
+
+import (
+	"github.com/alibaba/sentinel-golang/core/base"
+)
+
+// TraceError does nothing under the build tag sentinel_noop.
+func TraceError(_ *base.SentinelEntry, _ error) {
+}
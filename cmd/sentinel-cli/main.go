@@ -0,0 +1,242 @@
+// Command sentinel-cli validates, diffs and converts the rule files, and queries the effective rules and statistics
+// of a running instance via the gRPC debug service (see adapter/grpc.RegisterDebugService).
+//
+// Usage:
+//
+//	sentinel-cli validate [-module flow] [-format go|java] FILE
+//	sentinel-cli diff [-module flow] OLD_FILE NEW_FILE
+//	sentinel-cli convert [-module flow] -from java -to go FILE
+//	sentinel-cli query -addr HOST:PORT [-module flow] [-resource RES] rules|stats|breakers
+//
+// FILE could be "-" to read from the standard input. The commands exit with status 1 if the rules are invalid
+// or different, and 2 on the other errors.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	sentinelgrpc "github.com/alibaba/sentinel-golang/adapter/grpc"
+	"github.com/alibaba/sentinel-golang/ext/ruleconv"
+	"github.com/golang/protobuf/jsonpb"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+const (
+	exitOK      = 0
+	exitFailed  = 1
+	exitInvalid = 2
+)
+
+const usage = `Usage:
+  sentinel-cli validate [-module flow] [-format go|java] FILE
+  sentinel-cli diff [-module flow] OLD_FILE NEW_FILE
+  sentinel-cli convert [-module flow] -from java -to go FILE
+  sentinel-cli query -addr HOST:PORT [-module flow] [-resource RES] rules|stats|breakers
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitInvalid
+	}
+	c := &cli{stdin: stdin, stdout: stdout, stderr: stderr}
+	commands := map[string]func([]string) (int, error){
+		"validate": c.validate,
+		"diff":     c.diff,
+		"convert":  c.convert,
+		"query":    c.query,
+	}
+	command, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command: %s\n%s", args[0], usage)
+		return exitInvalid
+	}
+	code, err := command(args[1:])
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", args[0], err)
+	}
+	return code
+}
+
+func (c *cli) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	return fs
+}
+
+func (c *cli) validate(args []string) (int, error) {
+	fs := c.newFlagSet("validate")
+	module := fs.String("module", ruleconv.ModuleFlow, "rule module: flow, circuitbreaker, hotspot or system")
+	format := fs.String("format", ruleconv.FormatGo, "rule format: go or java")
+	if err := fs.Parse(args); err != nil {
+		return exitInvalid, nil
+	}
+	if fs.NArg() != 1 {
+		return exitInvalid, errors.New("exactly one rule file is expected")
+	}
+	src, err := c.readFile(fs.Arg(0))
+	if err != nil {
+		return exitInvalid, err
+	}
+	ruleErrs, err := ruleconv.Validate(*module, *format, src)
+	if err != nil {
+		return exitInvalid, err
+	}
+	for _, e := range ruleErrs {
+		fmt.Fprintln(c.stdout, e.Error())
+	}
+	if len(ruleErrs) > 0 {
+		return exitFailed, nil
+	}
+	fmt.Fprintln(c.stdout, "OK")
+	return exitOK, nil
+}
+
+func (c *cli) diff(args []string) (int, error) {
+	fs := c.newFlagSet("diff")
+	module := fs.String("module", ruleconv.ModuleFlow, "rule module: flow, circuitbreaker, hotspot or system")
+	if err := fs.Parse(args); err != nil {
+		return exitInvalid, nil
+	}
+	if fs.NArg() != 2 {
+		return exitInvalid, errors.New("exactly two rule files are expected")
+	}
+	oldSrc, err := c.readFile(fs.Arg(0))
+	if err != nil {
+		return exitInvalid, err
+	}
+	newSrc, err := c.readFile(fs.Arg(1))
+	if err != nil {
+		return exitInvalid, err
+	}
+	diffs, err := ruleconv.Diff(*module, oldSrc, newSrc)
+	if err != nil {
+		return exitInvalid, err
+	}
+	for _, d := range diffs {
+		switch d.Type {
+		case ruleconv.DiffAdded:
+			fmt.Fprintf(c.stdout, "+ %s %s\n", d.Key, d.New)
+		case ruleconv.DiffRemoved:
+			fmt.Fprintf(c.stdout, "- %s %s\n", d.Key, d.Old)
+		default:
+			fmt.Fprintf(c.stdout, "~ %s\n  - %s\n  + %s\n", d.Key, d.Old, d.New)
+		}
+	}
+	if len(diffs) > 0 {
+		return exitFailed, nil
+	}
+	return exitOK, nil
+}
+
+func (c *cli) convert(args []string) (int, error) {
+	fs := c.newFlagSet("convert")
+	module := fs.String("module", ruleconv.ModuleFlow, "rule module: flow, circuitbreaker, hotspot or system")
+	from := fs.String("from", ruleconv.FormatJava, "source rule format: go or java")
+	to := fs.String("to", ruleconv.FormatGo, "target rule format: go or java")
+	if err := fs.Parse(args); err != nil {
+		return exitInvalid, nil
+	}
+	if fs.NArg() != 1 {
+		return exitInvalid, errors.New("exactly one rule file is expected")
+	}
+	src, err := c.readFile(fs.Arg(0))
+	if err != nil {
+		return exitInvalid, err
+	}
+	converted, err := ruleconv.Convert(*module, *from, *to, src)
+	if err != nil {
+		return exitInvalid, err
+	}
+	return exitOK, c.writeJSON(converted)
+}
+
+func (c *cli) query(args []string) (int, error) {
+	fs := c.newFlagSet("query")
+	addr := fs.String("addr", "", "address of the gRPC server with the debug service registered")
+	module := fs.String("module", "", "rule module of the rules query, all the modules if absent")
+	resource := fs.String("resource", "", "resource of the stats and breakers query, all the resources if absent")
+	timeout := fs.Duration("timeout", 3*time.Second, "timeout of the query")
+	if err := fs.Parse(args); err != nil {
+		return exitInvalid, nil
+	}
+	if len(*addr) == 0 || fs.NArg() != 1 {
+		return exitInvalid, errors.New("the address and exactly one query target are expected")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, *addr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return exitInvalid, errors.Wrapf(err, "fail to connect to %s", *addr)
+	}
+	defer conn.Close()
+	return c.queryWith(ctx, sentinelgrpc.NewDebugClient(conn), fs.Arg(0), *module, *resource)
+}
+
+func (c *cli) queryWith(ctx context.Context, client *sentinelgrpc.DebugClient, target, module, resource string) (int, error) {
+	req := &structpb.Struct{Fields: make(map[string]*structpb.Value)}
+	var call func(context.Context, *structpb.Struct, ...grpc.CallOption) (*structpb.Struct, error)
+	switch target {
+	case "rules":
+		call = client.GetEffectiveRules
+		if len(module) > 0 {
+			req.Fields["module"] = &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: module}}
+		}
+	case "stats", "breakers":
+		call = client.GetResourceStats
+		if target == "breakers" {
+			call = client.GetBreakerStates
+		}
+		if len(resource) > 0 {
+			req.Fields["resource"] = &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: resource}}
+		}
+	default:
+		return exitInvalid, errors.Errorf("unknown query target: %s", target)
+	}
+	resp, err := call(ctx, req)
+	if err != nil {
+		return exitInvalid, err
+	}
+	out, err := (&jsonpb.Marshaler{}).MarshalToString(resp)
+	if err != nil {
+		return exitInvalid, err
+	}
+	return exitOK, c.writeJSON([]byte(out))
+}
+
+func (c *cli) readFile(name string) ([]byte, error) {
+	if name == "-" {
+		return ioutil.ReadAll(c.stdin)
+	}
+	return ioutil.ReadFile(name)
+}
+
+// writeJSON writes the indented JSON to the standard output.
+func (c *cli) writeJSON(src []byte) error {
+	var out bytes.Buffer
+	if err := json.Indent(&out, src, "", "  "); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(c.stdout, out.String())
+	return err
+}
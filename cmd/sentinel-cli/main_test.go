@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sentinelgrpc "github.com/alibaba/sentinel-golang/adapter/grpc"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func writeRuleFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	assert.Nil(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sentinel-cli")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	valid := writeRuleFile(t, dir, "valid.json", `[{"resource":"abc","threshold":10}]`)
+	invalid := writeRuleFile(t, dir, "invalid.json", `[{"resource":"abc","threshold":-1}]`)
+	java := writeRuleFile(t, dir, "java.json", `[{"resource":"abc","grade":1,"count":10}]`)
+
+	runWith := func(stdin string, args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+		return code, stdout.String(), stderr.String()
+	}
+
+	t.Run("Validate", func(t *testing.T) {
+		code, out, _ := runWith("", "validate", valid)
+		assert.Equal(t, exitOK, code)
+		assert.Equal(t, "OK\n", out)
+		code, out, _ = runWith("", "validate", invalid)
+		assert.Equal(t, exitFailed, code)
+		assert.Contains(t, out, "rule[0]")
+		code, _, _ = runWith(`[{"resource":"abc","grade":1,"count":10}]`, "validate", "-format", "java", "-")
+		assert.Equal(t, exitOK, code)
+		code, _, errOut := runWith("", "validate", filepath.Join(dir, "absent.json"))
+		assert.Equal(t, exitInvalid, code)
+		assert.Contains(t, errOut, "validate:")
+	})
+
+	t.Run("Diff", func(t *testing.T) {
+		code, out, _ := runWith("", "diff", valid, valid)
+		assert.Equal(t, exitOK, code)
+		assert.Empty(t, out)
+		code, out, _ = runWith("", "diff", valid, invalid)
+		assert.Equal(t, exitFailed, code)
+		assert.Contains(t, out, "~ resource:abc#1")
+	})
+
+	t.Run("Convert", func(t *testing.T) {
+		code, out, _ := runWith("", "convert", "-from", "java", "-to", "go", java)
+		assert.Equal(t, exitOK, code)
+		assert.Contains(t, out, `"threshold": 10`)
+	})
+
+	t.Run("Usage", func(t *testing.T) {
+		code, _, errOut := runWith("")
+		assert.Equal(t, exitInvalid, code)
+		assert.Contains(t, errOut, "Usage")
+		code, _, _ = runWith("", "foo")
+		assert.Equal(t, exitInvalid, code)
+		code, _, _ = runWith("", "query", "rules")
+		assert.Equal(t, exitInvalid, code)
+	})
+}
+
+func TestQueryWith(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	sentinelgrpc.RegisterDebugService(s)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	defer s.Stop()
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	_, err = flow.LoadRules([]*flow.Rule{
+		{
+			Resource:               "cli-abc",
+			Threshold:              10,
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+		},
+	})
+	assert.Nil(t, err)
+	defer flow.ClearRules()
+
+	var stdout bytes.Buffer
+	c := &cli{stdout: &stdout, stderr: ioutil.Discard}
+	code, err := c.queryWith(context.Background(), sentinelgrpc.NewDebugClient(conn), "rules", "flow", "")
+	assert.Nil(t, err)
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout.String(), "cli-abc")
+
+	code, err = c.queryWith(context.Background(), sentinelgrpc.NewDebugClient(conn), "foo", "", "")
+	assert.NotNil(t, err)
+	assert.Equal(t, exitInvalid, code)
+}
@@ -0,0 +1,242 @@
+package anomaly
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultMinRequestAmount = 10
+	DefaultMinBlockRatio    = 0.2
+	DefaultSensitivity      = 3.0
+	DefaultMinDeviation     = 0.2
+	DefaultWarmUpSamples    = 30
+	DefaultSmoothingFactor  = 0.05
+)
+
+// Options represents the options of the block ratio anomaly detector, the zero fields take the default values.
+type Options struct {
+	// IntervalMs is the interval of sampling the block ratio of the resources, which is required.
+	IntervalMs uint32
+	// MinRequestAmount is the minimum requests per second of the resource to be sampled,
+	// as the block ratio of few requests is meaningless.
+	MinRequestAmount float64
+	// MinBlockRatio is the minimum block ratio regarded as anomalous.
+	MinBlockRatio float64
+	// Sensitivity is the number of standard deviations beyond the baseline regarded as anomalous.
+	Sensitivity float64
+	// MinDeviation is the minimum deviation of the block ratio beyond the baseline regarded as anomalous,
+	// which avoids the false alarms of the resources with stable baselines (i.e. tiny standard deviations).
+	MinDeviation float64
+	// WarmUpSamples is the number of samples to learn the baseline before raising any anomaly.
+	WarmUpSamples uint32
+	// SmoothingFactor is the EWMA smoothing factor of the baseline, valid range is (0.0, 1.0).
+	// The smaller the factor is, the longer history the baseline reflects.
+	SmoothingFactor float64
+}
+
+func (o *Options) fillDefaults() {
+	if o.MinRequestAmount <= 0 {
+		o.MinRequestAmount = DefaultMinRequestAmount
+	}
+	if o.MinBlockRatio <= 0 {
+		o.MinBlockRatio = DefaultMinBlockRatio
+	}
+	if o.Sensitivity <= 0 {
+		o.Sensitivity = DefaultSensitivity
+	}
+	if o.MinDeviation <= 0 {
+		o.MinDeviation = DefaultMinDeviation
+	}
+	if o.WarmUpSamples == 0 {
+		o.WarmUpSamples = DefaultWarmUpSamples
+	}
+	if o.SmoothingFactor <= 0 {
+		o.SmoothingFactor = DefaultSmoothingFactor
+	}
+}
+
+func (o *Options) validate() error {
+	if o.IntervalMs == 0 {
+		return errors.New("IntervalMs must be positive")
+	}
+	if o.MinBlockRatio > 1 {
+		return errors.New("MinBlockRatio must be in (0.0, 1.0]")
+	}
+	if o.SmoothingFactor >= 1 {
+		return errors.New("SmoothingFactor must be in (0.0, 1.0)")
+	}
+	return nil
+}
+
+// Event represents the anomaly (or recovery) of the block ratio of a resource.
+type Event struct {
+	Resource string
+	// Timestamp is the time (in ms) when the event is raised.
+	Timestamp uint64
+	// BlockRatio is current block ratio of the resource.
+	BlockRatio float64
+	// BaselineRatio is the baseline block ratio of the resource.
+	BaselineRatio float64
+	// RequestsPerSecond is current passed and blocked requests per second of the resource.
+	RequestsPerSecond float64
+}
+
+// Listener listens on the anomaly events of the block ratio.
+type Listener interface {
+	// OnAnomaly is triggered when the block ratio of the resource deviates sharply from the baseline.
+	OnAnomaly(e Event)
+	// OnRecovered is triggered when the block ratio of the anomalous resource comes back to the baseline.
+	OnRecovered(e Event)
+}
+
+var (
+	listeners    = make([]Listener, 0)
+	listenersMux = new(sync.RWMutex)
+
+	detectorOnce     sync.Once
+	detectorStopChan = make(chan struct{})
+)
+
+// RegisterListeners registers the listeners of the anomaly events.
+func RegisterListeners(ls ...Listener) {
+	listenersMux.Lock()
+	defer listenersMux.Unlock()
+
+	listeners = append(listeners, ls...)
+}
+
+// ClearListeners clears all the listeners of the anomaly events.
+func ClearListeners() {
+	listenersMux.Lock()
+	defer listenersMux.Unlock()
+
+	listeners = make([]Listener, 0)
+}
+
+// StartDetector starts the background task detecting the block ratio anomalies of all the resources.
+// The detector could be started only once.
+func StartDetector(opts Options) error {
+	opts.fillDefaults()
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	detectorOnce.Do(func() {
+		d := newDetector(opts)
+		ticker := time.NewTicker(time.Duration(opts.IntervalMs) * time.Millisecond)
+		go util.RunWithRecover(func() {
+			for {
+				select {
+				case <-ticker.C:
+					d.detect(util.CurrentTimeMillis())
+				case <-detectorStopChan:
+					ticker.Stop()
+					return
+				}
+			}
+		})
+		logging.Info("[BlockAnomalyDetector] Detector started", "options", opts)
+	})
+	return nil
+}
+
+// baseline is the EWMA baseline of the block ratio of a resource.
+type baseline struct {
+	mean      float64
+	variance  float64
+	samples   uint32
+	anomalous bool
+}
+
+type detector struct {
+	opts      Options
+	baselines map[string]*baseline
+}
+
+func newDetector(opts Options) *detector {
+	return &detector{
+		opts:      opts,
+		baselines: make(map[string]*baseline),
+	}
+}
+
+func (d *detector) detect(now uint64) {
+	nodes := stat.ResourceNodeList()
+	alive := make(map[string]struct{}, len(nodes))
+	for _, n := range nodes {
+		res := n.ResourceName()
+		alive[res] = struct{}{}
+		d.observe(res, n.GetQPS(base.MetricEventPass), n.GetQPS(base.MetricEventBlock), now)
+	}
+	// the baselines of the evicted resources are dropped
+	for res := range d.baselines {
+		if _, ok := alive[res]; !ok {
+			delete(d.baselines, res)
+		}
+	}
+}
+
+// observe samples the block ratio of the resource, and notifies the listeners on anomaly or recovery.
+func (d *detector) observe(res string, passQps, blockQps float64, now uint64) {
+	total := passQps + blockQps
+	if total < d.opts.MinRequestAmount {
+		return
+	}
+	ratio := blockQps / total
+	b := d.baselines[res]
+	if b == nil {
+		b = &baseline{mean: ratio}
+		d.baselines[res] = b
+	}
+	if b.samples >= d.opts.WarmUpSamples {
+		threshold := math.Max(d.opts.MinDeviation, d.opts.Sensitivity*math.Sqrt(b.variance))
+		anomalous := ratio >= d.opts.MinBlockRatio && ratio-b.mean > threshold
+		e := Event{
+			Resource:          res,
+			Timestamp:         now,
+			BlockRatio:        ratio,
+			BaselineRatio:     b.mean,
+			RequestsPerSecond: total,
+		}
+		if anomalous != b.anomalous {
+			b.anomalous = anomalous
+			notify(e, anomalous)
+		}
+		if anomalous {
+			// the anomalous samples should not pollute the baseline
+			return
+		}
+	}
+	diff := ratio - b.mean
+	incr := d.opts.SmoothingFactor * diff
+	b.mean += incr
+	b.variance = (1 - d.opts.SmoothingFactor) * (b.variance + diff*incr)
+	b.samples++
+}
+
+func notify(e Event, anomalous bool) {
+	if anomalous {
+		logging.Warn("[BlockAnomalyDetector] Block ratio anomaly detected", "resource", e.Resource,
+			"blockRatio", e.BlockRatio, "baselineRatio", e.BaselineRatio, "requestsPerSecond", e.RequestsPerSecond)
+	} else {
+		logging.Info("[BlockAnomalyDetector] Block ratio recovered", "resource", e.Resource,
+			"blockRatio", e.BlockRatio, "baselineRatio", e.BaselineRatio)
+	}
+	listenersMux.RLock()
+	defer listenersMux.RUnlock()
+
+	for _, l := range listeners {
+		if anomalous {
+			l.OnAnomaly(e)
+		} else {
+			l.OnRecovered(e)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package anomaly
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+type listenerMock struct {
+	anomalies []Event
+	recovered []Event
+}
+
+func (l *listenerMock) OnAnomaly(e Event) {
+	l.anomalies = append(l.anomalies, e)
+}
+
+func (l *listenerMock) OnRecovered(e Event) {
+	l.recovered = append(l.recovered, e)
+}
+
+func TestOptions(t *testing.T) {
+	opts := Options{}
+	opts.fillDefaults()
+	assert.Error(t, opts.validate())
+	assert.Equal(t, DefaultSensitivity, opts.Sensitivity)
+
+	opts = Options{IntervalMs: 1000, SmoothingFactor: 1}
+	opts.fillDefaults()
+	assert.Error(t, opts.validate())
+}
+
+func TestDetector_observe(t *testing.T) {
+	l := &listenerMock{}
+	RegisterListeners(l)
+	defer ClearListeners()
+
+	opts := Options{IntervalMs: 1000, WarmUpSamples: 10}
+	opts.fillDefaults()
+	d := newDetector(opts)
+	// baseline: about 5% blocked
+	for i := 0; i < 20; i++ {
+		d.observe("abc", 95, 5, uint64(i))
+	}
+	assert.InDelta(t, 0.05, d.baselines["abc"].mean, 1e-6)
+	// too few requests to be sampled
+	d.observe("abc", 0, 5, 20)
+	assert.Equal(t, 0, len(l.anomalies))
+	// slight deviation
+	d.observe("abc", 85, 15, 21)
+	assert.Equal(t, 0, len(l.anomalies))
+
+	// a bad rule rejecting 90%
+	d.observe("abc", 10, 90, 22)
+	assert.Equal(t, 1, len(l.anomalies))
+	assert.Equal(t, "abc", l.anomalies[0].Resource)
+	assert.Equal(t, 0.9, l.anomalies[0].BlockRatio)
+	assert.Equal(t, uint64(22), l.anomalies[0].Timestamp)
+	mean := d.baselines["abc"].mean
+	// raised only once, and the baseline is frozen
+	d.observe("abc", 10, 90, 23)
+	assert.Equal(t, 1, len(l.anomalies))
+	assert.Equal(t, mean, d.baselines["abc"].mean)
+
+	d.observe("abc", 95, 5, 24)
+	assert.Equal(t, 1, len(l.recovered))
+	assert.False(t, d.baselines["abc"].anomalous)
+}
+
+func TestDetector_detect(t *testing.T) {
+	opts := Options{IntervalMs: 1000}
+	opts.fillDefaults()
+	d := newDetector(opts)
+	d.baselines["evicted-res"] = &baseline{}
+
+	node := stat.GetOrCreateResourceNode("anomaly-test", base.ResTypeCommon)
+	node.AddCount(base.MetricEventPass, 100)
+	d.detect(1)
+	assert.NotNil(t, d.baselines["anomaly-test"])
+	assert.Nil(t, d.baselines["evicted-res"])
+}
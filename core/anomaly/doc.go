@@ -0,0 +1,13 @@
+// Package anomaly implements the statistical anomaly detection on the block ratio of resources.
+//
+// The detector samples the block ratio (blocked / (passed + blocked)) of each resource periodically, and learns
+// the baseline of each resource by the exponentially weighted moving average and variance. The block ratio
+// deviating sharply from the baseline (e.g. a misconfigured rule suddenly rejecting 90% of the requests)
+// raises an anomaly event, which catches the bad rule pushes minutes earlier than the user complaints.
+// The baseline is frozen while the resource is anomalous, and the recovery raises an event as well.
+//
+// The detector is optional, it's started by StartDetector or the config item Stat.BlockAnomalyDetectIntervalMs:
+//
+//	anomaly.RegisterListeners(myListener)
+//	err := anomaly.StartDetector(anomaly.Options{IntervalMs: 1000})
+package anomaly
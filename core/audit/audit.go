@@ -0,0 +1,229 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SourceAPI is the default source of the rule changes, which indicates that rules are loaded by API directly.
+	SourceAPI = "api"
+
+	DefaultCapacity = 1000
+	// DefaultFileQueueSize is the amount of the events pending to be written to the file sink,
+	// the events beyond it are dropped rather than blocking the rule updates.
+	DefaultFileQueueSize = 1024
+)
+
+// RuleChangeEvent represents an effective change of the rules of a module.
+type RuleChangeEvent struct {
+	// Timestamp is the time (in ms) when the change took effect.
+	Timestamp uint64 `json:"timestamp"`
+	// Module is the rule module, e.g. flow, circuitbreaker.
+	Module string `json:"module"`
+	// Source indicates where the rules come from, e.g. the datasource.
+	Source string `json:"source"`
+	// Actor is the operator who made the change (optional).
+	Actor   string   `json:"actor,omitempty"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+var (
+	ring     = make([]*RuleChangeEvent, 0, DefaultCapacity)
+	capacity = DefaultCapacity
+	ringMux  = new(sync.RWMutex)
+
+	fileSink *fileWriter
+	// fileDrops counts the events dropped as the file sink is too slow.
+	fileDrops = selfmetric.NewDropCounter("audit.file")
+
+	currentSource = SourceAPI
+	currentActor  string
+	// updateMux serializes the updates with source, so that the current source is bound to the update in progress.
+	updateMux = new(sync.Mutex)
+)
+
+// UpdateWithSource performs the rule update with the given source and actor, which are recorded in the audit events
+// of the rule changes made by the update. The updates with source are serialized.
+func UpdateWithSource(source, actor string, update func() error) error {
+	updateMux.Lock()
+	defer updateMux.Unlock()
+
+	ringMux.Lock()
+	currentSource, currentActor = source, actor
+	ringMux.Unlock()
+	defer func() {
+		ringMux.Lock()
+		currentSource, currentActor = SourceAPI, ""
+		ringMux.Unlock()
+	}()
+	return update()
+}
+
+// SetCapacity sets the capacity of the in-memory ring of events, the oldest events are discarded if exceeded.
+func SetCapacity(c int) error {
+	if c <= 0 {
+		return errors.New("capacity must be positive")
+	}
+	ringMux.Lock()
+	defer ringMux.Unlock()
+
+	capacity = c
+	if len(ring) > capacity {
+		ring = append(make([]*RuleChangeEvent, 0, capacity), ring[len(ring)-capacity:]...)
+	}
+	return nil
+}
+
+// SetFileSink sets the file that the events are appended to, in JSON lines format.
+// The events are written asynchronously, and dropped if the file falls behind by more than DefaultFileQueueSize events.
+// Empty path removes the file sink, the pending events of the previous file sink are written before it returns.
+func SetFileSink(path string) error {
+	var w *fileWriter
+	if len(path) > 0 {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		w = newFileWriter(f, DefaultFileQueueSize)
+	}
+	ringMux.Lock()
+	prev := fileSink
+	fileSink = w
+	ringMux.Unlock()
+
+	if prev != nil {
+		prev.close()
+	}
+	return nil
+}
+
+// fileWriter writes the events to the file in a separate goroutine.
+type fileWriter struct {
+	file  *os.File
+	queue chan []byte
+	done  chan struct{}
+}
+
+func newFileWriter(f *os.File, queueSize int) *fileWriter {
+	w := &fileWriter{
+		file:  f,
+		queue: make(chan []byte, queueSize),
+		done:  make(chan struct{}),
+	}
+	go util.RunWithRecover(w.run)
+	return w
+}
+
+func (w *fileWriter) run() {
+	defer close(w.done)
+	for line := range w.queue {
+		if _, err := w.file.Write(line); err != nil {
+			logging.Error(err, "Failed to write rule change event to audit file")
+		}
+	}
+	_ = w.file.Close()
+}
+
+// offer enqueues the line without blocking, it must not be called after close.
+func (w *fileWriter) offer(line []byte) {
+	select {
+	case w.queue <- line:
+	default:
+		fileDrops.Inc()
+	}
+}
+
+// close writes the pending lines and closes the file.
+func (w *fileWriter) close() {
+	close(w.queue)
+	<-w.done
+}
+
+// RecordRuleChange records the change from oldRules to newRules of the module, the rules are represented by their
+// string forms. Nothing will be recorded if the rules are not changed actually.
+func RecordRuleChange(module string, oldRules, newRules []string) {
+	added, removed := diff(oldRules, newRules)
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+
+	ringMux.Lock()
+	defer ringMux.Unlock()
+
+	event := &RuleChangeEvent{
+		Timestamp: util.CurrentTimeMillis(),
+		Module:    module,
+		Source:    currentSource,
+		Actor:     currentActor,
+		Added:     added,
+		Removed:   removed,
+	}
+	if len(ring) >= capacity {
+		ring = ring[1:]
+	}
+	ring = append(ring, event)
+
+	if fileSink != nil {
+		b, err := json.Marshal(event)
+		if err != nil {
+			logging.Error(err, "Failed to marshal rule change event", "event", event)
+		} else {
+			fileSink.offer(append(b, '\n'))
+		}
+	}
+}
+
+// Events returns the recorded events of the given module (all modules if empty) since the given timestamp (in ms),
+// in chronological order.
+func Events(module string, sinceMs uint64) []RuleChangeEvent {
+	ringMux.RLock()
+	defer ringMux.RUnlock()
+
+	ret := make([]RuleChangeEvent, 0)
+	for _, e := range ring {
+		if e.Timestamp < sinceMs || (len(module) > 0 && e.Module != module) {
+			continue
+		}
+		ret = append(ret, *e)
+	}
+	return ret
+}
+
+// Reset discards all the recorded events.
+func Reset() {
+	ringMux.Lock()
+	defer ringMux.Unlock()
+
+	ring = make([]*RuleChangeEvent, 0, capacity)
+}
+
+func diff(oldRules, newRules []string) (added, removed []string) {
+	counts := make(map[string]int, len(oldRules))
+	for _, r := range oldRules {
+		counts[r]++
+	}
+	added = make([]string, 0)
+	for _, r := range newRules {
+		if counts[r] > 0 {
+			counts[r]--
+			continue
+		}
+		added = append(added, r)
+	}
+	removed = make([]string, 0)
+	for _, r := range oldRules {
+		if counts[r] > 0 {
+			counts[r]--
+			removed = append(removed, r)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,77 @@
+package audit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordRuleChange(t *testing.T) {
+	defer Reset()
+
+	RecordRuleChange("flow", []string{"a", "b"}, []string{"b", "a"})
+	assert.Equal(t, 0, len(Events("", 0)))
+
+	RecordRuleChange("flow", []string{"a", "b"}, []string{"b", "c"})
+	err := UpdateWithSource("nacos", "alice", func() error {
+		RecordRuleChange("system", nil, []string{"d"})
+		return nil
+	})
+	assert.Nil(t, err)
+
+	events := Events("", 0)
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, []string{"c"}, events[0].Added)
+	assert.Equal(t, []string{"a"}, events[0].Removed)
+	assert.Equal(t, SourceAPI, events[0].Source)
+	assert.Equal(t, "nacos", events[1].Source)
+	assert.Equal(t, "alice", events[1].Actor)
+
+	assert.Equal(t, 1, len(Events("system", 0)))
+	assert.Equal(t, 0, len(Events("", events[1].Timestamp+1)))
+}
+
+func TestSetCapacity(t *testing.T) {
+	defer Reset()
+	defer SetCapacity(DefaultCapacity)
+
+	assert.NotNil(t, SetCapacity(0))
+	assert.Nil(t, SetCapacity(2))
+	for _, r := range []string{"a", "b", "c"} {
+		RecordRuleChange("flow", nil, []string{r})
+	}
+	events := Events("", 0)
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, []string{"b"}, events[0].Added)
+}
+
+func TestSetFileSink(t *testing.T) {
+	defer Reset()
+
+	dir, err := ioutil.TempDir("", "sentinel-audit")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+
+	assert.Nil(t, SetFileSink(path))
+	RecordRuleChange("flow", nil, []string{"a"})
+	assert.Nil(t, SetFileSink(""))
+
+	content, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(string(content), `"module":"flow"`))
+}
+
+func TestFileWriter_DropWhenFull(t *testing.T) {
+	// The writer is not started, so the queue is never consumed.
+	w := &fileWriter{queue: make(chan []byte, 1)}
+	drops := fileDrops.Count()
+	w.offer([]byte("a\n"))
+	w.offer([]byte("b\n"))
+	assert.Equal(t, drops+1, fileDrops.Count())
+	assert.Equal(t, 1, len(w.queue))
+}
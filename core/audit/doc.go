@@ -0,0 +1,19 @@
+// Package audit implements the audit trail of rule changes.
+//
+// Every effective rule change of the rule managers is recorded as a RuleChangeEvent (the added and removed rules),
+// which is kept in a bounded in-memory ring and optionally appended to a file sink in JSON lines format.
+// The events could be queried by Events, so that "who changed the limit and when" is answerable during incident reviews.
+//
+// The source and the actor of the rule change could be provided by UpdateWithSource:
+//
+//	err := audit.UpdateWithSource("nacos:flow-rules", "alice", func() error {
+//	    _, err := flow.LoadRules(rules)
+//	    return err
+//	})
+//
+// Otherwise the source is SourceAPI.
+//
+// Besides the changes, the effective rules of the modules registered by RegisterRuleGetter could be dumped to
+// a dedicated file periodically (see StartRuleDump and the config item Log.RuleDumpIntervalSec), one RuleSnapshot
+// per module per line with the version hash of the rules, which tells what protection was active at any point in time.
+package audit
@@ -0,0 +1,126 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+// RuleSnapshot represents the effective rules of a module at a point in time, which is a line of the rule dump file.
+type RuleSnapshot struct {
+	// Timestamp is the time (in ms) when the rules are dumped.
+	Timestamp uint64 `json:"timestamp"`
+	Module    string `json:"module"`
+	// Hash is the version hash of the rules, i.e. the hex SHA-256 of the JSON form of the rules,
+	// so that the identical rule sets have the same hash.
+	Hash  string          `json:"hash"`
+	Rules json.RawMessage `json:"rules"`
+}
+
+// RuleGetter returns the effective rules of a module, which must be JSON serializable.
+type RuleGetter func() interface{}
+
+var (
+	ruleGetters   = make(map[string]RuleGetter)
+	ruleGetterMux = new(sync.RWMutex)
+
+	ruleDumpOnce     sync.Once
+	ruleDumpStopChan = make(chan struct{})
+)
+
+// RegisterRuleGetter registers the getter of the effective rules of the module to be dumped.
+func RegisterRuleGetter(module string, getter RuleGetter) {
+	if getter == nil {
+		return
+	}
+	ruleGetterMux.Lock()
+	defer ruleGetterMux.Unlock()
+
+	ruleGetters[module] = getter
+}
+
+// SnapshotRules takes the snapshots of the effective rules of all the registered modules, sorted by the module.
+func SnapshotRules() ([]*RuleSnapshot, error) {
+	ruleGetterMux.RLock()
+	defer ruleGetterMux.RUnlock()
+
+	now := util.CurrentTimeMillis()
+	ret := make([]*RuleSnapshot, 0, len(ruleGetters))
+	for module, getter := range ruleGetters {
+		b, err := json.Marshal(getter())
+		if err != nil {
+			return nil, errors.Wrapf(err, "fail to marshal the rules of module %s", module)
+		}
+		sum := sha256.Sum256(b)
+		ret = append(ret, &RuleSnapshot{
+			Timestamp: now,
+			Module:    module,
+			Hash:      hex.EncodeToString(sum[:]),
+			Rules:     b,
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Module < ret[j].Module
+	})
+	return ret, nil
+}
+
+// DumpRules writes the snapshots of the effective rules of all the registered modules to w, in JSON lines format.
+func DumpRules(w io.Writer) error {
+	snapshots, err := SnapshotRules()
+	if err != nil {
+		return err
+	}
+	for _, s := range snapshots {
+		b, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		if _, err = w.Write(append(b, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StartRuleDump starts the background task that appends the snapshots of the effective rules to the file
+// periodically, which gives a forensic trail of what protection was active at any point in time.
+// The task could be started only once, the later calls take no effect.
+func StartRuleDump(path string, interval time.Duration) (err error) {
+	if len(path) == 0 || interval <= 0 {
+		return errors.New("the rule dump file and a positive interval are required")
+	}
+	ruleDumpOnce.Do(func() {
+		var f *os.File
+		f, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return
+		}
+		ticker := time.NewTicker(interval)
+		go util.RunWithRecover(func() {
+			defer f.Close()
+			for {
+				select {
+				case <-ticker.C:
+					if err := DumpRules(f); err != nil {
+						logging.Error(err, "[RuleDump] Failed to dump the effective rules", "file", path)
+					}
+				case <-ruleDumpStopChan:
+					ticker.Stop()
+					return
+				}
+			}
+		})
+		logging.Info("[RuleDump] Rule dump task started", "file", path, "interval", interval.String())
+	})
+	return err
+}
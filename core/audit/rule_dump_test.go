@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func resetRuleGetters() {
+	ruleGetterMux.Lock()
+	defer ruleGetterMux.Unlock()
+
+	ruleGetters = make(map[string]RuleGetter)
+}
+
+func TestDumpRules(t *testing.T) {
+	defer resetRuleGetters()
+	rules := []string{"a"}
+	RegisterRuleGetter("system", func() interface{} { return []string{} })
+	RegisterRuleGetter("flow", func() interface{} { return rules })
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, DumpRules(buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 2, len(lines))
+	s := &RuleSnapshot{}
+	assert.Nil(t, json.Unmarshal([]byte(lines[0]), s))
+	assert.Equal(t, "flow", s.Module)
+	assert.JSONEq(t, `["a"]`, string(s.Rules))
+	assert.Equal(t, 64, len(s.Hash))
+
+	// the hash changes with the rules only
+	snapshots, err := SnapshotRules()
+	assert.Nil(t, err)
+	assert.Equal(t, s.Hash, snapshots[0].Hash)
+	rules = []string{"b"}
+	snapshots, err = SnapshotRules()
+	assert.Nil(t, err)
+	assert.NotEqual(t, s.Hash, snapshots[0].Hash)
+
+	RegisterRuleGetter("bad", func() interface{} { return func() {} })
+	_, err = SnapshotRules()
+	assert.NotNil(t, err)
+}
+
+func TestStartRuleDump(t *testing.T) {
+	defer resetRuleGetters()
+	RegisterRuleGetter("flow", func() interface{} { return []string{"a"} })
+
+	dir, err := ioutil.TempDir("", "sentinel-rule-dump")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "rules.log")
+
+	assert.NotNil(t, StartRuleDump(path, 0))
+	assert.Nil(t, StartRuleDump(path, 20*time.Millisecond))
+	time.Sleep(100 * time.Millisecond)
+	content, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(string(content), `"module":"flow"`))
+}
@@ -0,0 +1,126 @@
+package base
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+// ErrAsyncEntryLeaked is the error recorded to the async entries exited by the leak detector.
+var ErrAsyncEntryLeaked = errors.New("async entry leaked without exit")
+
+// AsyncEntry is the handle that owns the exit of an entry, which could be passed across goroutines,
+// so that the RT and concurrency of the event-driven pipelines (where the completion happens on another goroutine)
+// are measured correctly. The handle must be exited exactly once, the following exits are ignored.
+type AsyncEntry struct {
+	entry     *SentinelEntry
+	createdAt uint64
+	exited    int32
+}
+
+var (
+	// asyncEntries holds the async entries in flight, for the leak detection.
+	asyncEntries       = sync.Map{}
+	asyncEntryAmount   int64
+	asyncEntryLeaked   int64
+	leakDetectorOnce   sync.Once
+	leakDetectorTicker *time.Ticker
+)
+
+// ExitAsync transfers the ownership of the exit from the entry to the returned async handle.
+// After that, the Exit of the entry itself is ignored, and the entry must be exited by the handle,
+// probably on another goroutine. Calling ExitAsync again returns the same handle.
+func (e *SentinelEntry) ExitAsync() *AsyncEntry {
+	if h := e.asyncHandle(); h != nil {
+		return h
+	}
+	h := &AsyncEntry{entry: e, createdAt: util.CurrentTimeMillis()}
+	if !atomic.CompareAndSwapPointer((*unsafe.Pointer)(unsafe.Pointer(&e.async)), nil, unsafe.Pointer(h)) {
+		return e.asyncHandle()
+	}
+	asyncEntries.Store(h, struct{}{})
+	atomic.AddInt64(&asyncEntryAmount, 1)
+	return h
+}
+
+func (e *SentinelEntry) asyncHandle() *AsyncEntry {
+	return (*AsyncEntry)(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&e.async))))
+}
+
+// Entry returns the entry owned by the handle.
+func (h *AsyncEntry) Entry() *SentinelEntry {
+	return h.entry
+}
+
+// SetError records the biz error of the entry before exiting.
+func (h *AsyncEntry) SetError(err error) {
+	h.entry.SetError(err)
+}
+
+// Exit exits the entry owned by the handle, only the first exit takes effect.
+func (h *AsyncEntry) Exit(exitOps ...ExitOption) {
+	if !atomic.CompareAndSwapInt32(&h.exited, 0, 1) {
+		return
+	}
+	asyncEntries.Delete(h)
+	atomic.AddInt64(&asyncEntryAmount, -1)
+	h.entry.exit(exitOps...)
+}
+
+// AsyncEntriesInFlight returns the amount of the async entries not exited yet.
+func AsyncEntriesInFlight() int64 {
+	return atomic.LoadInt64(&asyncEntryAmount)
+}
+
+// LeakedAsyncEntryCount returns the amount of the async entries exited by the leak detector.
+func LeakedAsyncEntryCount() int64 {
+	return atomic.LoadInt64(&asyncEntryLeaked)
+}
+
+// StartAsyncEntryLeakDetector starts the detector of the leaked async entries, i.e. the async entries
+// not exited within timeoutMs. The leaked entries are logged and exited with ErrAsyncEntryLeaked,
+// so that the concurrency of the resources is released. It takes effect only once.
+func StartAsyncEntryLeakDetector(timeoutMs uint32) {
+	if timeoutMs == 0 {
+		return
+	}
+	leakDetectorOnce.Do(func() {
+		interval := time.Duration(timeoutMs) * time.Millisecond
+		if interval > time.Minute {
+			interval = time.Minute
+		}
+		leakDetectorTicker = time.NewTicker(interval)
+		go util.RunWithRecover(func() {
+			for range leakDetectorTicker.C {
+				detectAsyncEntryLeaks(util.CurrentTimeMillis(), uint64(timeoutMs))
+			}
+		})
+	})
+}
+
+// detectAsyncEntryLeaks exits the async entries created before now-timeoutMs, and returns the amount of them.
+func detectAsyncEntryLeaks(now, timeoutMs uint64) int {
+	leaked := make([]*AsyncEntry, 0)
+	asyncEntries.Range(func(key, _ interface{}) bool {
+		h := key.(*AsyncEntry)
+		if now > h.createdAt && now-h.createdAt >= timeoutMs {
+			leaked = append(leaked, h)
+		}
+		return true
+	})
+	for _, h := range leaked {
+		if atomic.LoadInt32(&h.exited) == 1 {
+			continue
+		}
+		logging.Warn("[AsyncEntryLeakDetector] Exiting the leaked async entry", "resource", h.entry.Resource().Name(),
+			"ageMs", now-h.createdAt)
+		atomic.AddInt64(&asyncEntryLeaked, 1)
+		h.Exit(WithError(ErrAsyncEntryLeaked))
+	}
+	return len(leaked)
+}
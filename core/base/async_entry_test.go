@@ -0,0 +1,75 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type exitCountingSlot struct {
+	completed int
+	err       error
+}
+
+func (s *exitCountingSlot) OnEntryPassed(_ *EntryContext) {
+}
+
+func (s *exitCountingSlot) OnEntryBlocked(_ *EntryContext, _ *BlockError) {
+}
+
+func (s *exitCountingSlot) OnCompleted(ctx *EntryContext) {
+	s.completed++
+	s.err = ctx.Err()
+}
+
+func newAsyncTestEntry(slot *exitCountingSlot) *SentinelEntry {
+	sc := NewSlotChain()
+	sc.AddStatSlotLast(slot)
+	ctx := sc.GetPooledContext()
+	ctx.Resource = NewResourceWrapper("abc", ResTypeCommon, Inbound)
+	e := NewSentinelEntry(ctx, ctx.Resource, sc)
+	ctx.SetEntry(e)
+	return e
+}
+
+func TestAsyncEntry(t *testing.T) {
+	slot := &exitCountingSlot{}
+	e := newAsyncTestEntry(slot)
+	h := e.ExitAsync()
+	assert.True(t, h == e.ExitAsync())
+	assert.Equal(t, int64(1), AsyncEntriesInFlight())
+
+	// the exit of the entry itself is ignored after the ownership transfer
+	e.Exit()
+	assert.Equal(t, 0, slot.completed)
+
+	done := make(chan struct{})
+	go func() {
+		h.SetError(errors.New("biz error"))
+		h.Exit()
+		h.Exit()
+		close(done)
+	}()
+	<-done
+	assert.Equal(t, 1, slot.completed)
+	assert.EqualError(t, slot.err, "biz error")
+	assert.Equal(t, int64(0), AsyncEntriesInFlight())
+}
+
+func TestDetectAsyncEntryLeaks(t *testing.T) {
+	slot := &exitCountingSlot{}
+	h := newAsyncTestEntry(slot).ExitAsync()
+	leaked := LeakedAsyncEntryCount()
+
+	assert.Equal(t, 0, detectAsyncEntryLeaks(h.createdAt+500, 1000))
+	assert.Equal(t, 1, detectAsyncEntryLeaks(h.createdAt+1000, 1000))
+	assert.Equal(t, 1, slot.completed)
+	assert.Equal(t, ErrAsyncEntryLeaked, slot.err)
+	assert.Equal(t, leaked+1, LeakedAsyncEntryCount())
+	assert.Equal(t, int64(0), AsyncEntriesInFlight())
+
+	// the leaked entry is exited only once
+	h.Exit()
+	assert.Equal(t, 1, slot.completed)
+}
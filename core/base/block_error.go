@@ -1,6 +1,59 @@
 package base
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrBlocked is matched by all the errors of the blocked requests, e.g. errors.Is(err, ErrBlocked)
+// is true for every *BlockError, and for the errors wrapping a *BlockError.
+var ErrBlocked = errors.New("sentinel: blocked")
+
+// The errors of the specific block types, each of them wraps ErrBlocked.
+// A *BlockError matches the one of its block type, e.g. errors.Is(err, ErrFlowBlocked).
+var (
+	ErrFlowBlocked             error = &blockTypeError{BlockTypeFlow}
+	ErrIsolationBlocked        error = &blockTypeError{BlockTypeIsolation}
+	ErrCircuitBreakingBlocked  error = &blockTypeError{BlockTypeCircuitBreaking}
+	ErrSystemBlocked           error = &blockTypeError{BlockTypeSystemFlow}
+	ErrHotSpotParamBlocked     error = &blockTypeError{BlockTypeHotSpotParamFlow}
+	ErrQuotaBlocked            error = &blockTypeError{BlockTypeQuota}
+	ErrDefaultDenyBlocked      error = &blockTypeError{BlockTypeDefaultDeny}
+	ErrResourceOverflowBlocked error = &blockTypeError{BlockTypeResourceOverflow}
+	ErrCompositeBlocked        error = &blockTypeError{BlockTypeComposite}
+	ErrChaosBlocked            error = &blockTypeError{BlockTypeChaos}
+	ErrInternalErrorBlocked    error = &blockTypeError{BlockTypeInternalError}
+	ErrDeadlineBlocked         error = &blockTypeError{BlockTypeDeadline}
+
+	blockTypeErrors = map[BlockType]error{
+		BlockTypeFlow:             ErrFlowBlocked,
+		BlockTypeIsolation:        ErrIsolationBlocked,
+		BlockTypeCircuitBreaking:  ErrCircuitBreakingBlocked,
+		BlockTypeSystemFlow:       ErrSystemBlocked,
+		BlockTypeHotSpotParamFlow: ErrHotSpotParamBlocked,
+		BlockTypeQuota:            ErrQuotaBlocked,
+		BlockTypeDefaultDeny:      ErrDefaultDenyBlocked,
+		BlockTypeResourceOverflow: ErrResourceOverflowBlocked,
+		BlockTypeComposite:        ErrCompositeBlocked,
+		BlockTypeChaos:            ErrChaosBlocked,
+		BlockTypeInternalError:    ErrInternalErrorBlocked,
+		BlockTypeDeadline:         ErrDeadlineBlocked,
+	}
+)
+
+type blockTypeError struct {
+	blockType BlockType
+}
+
+func (e *blockTypeError) Error() string {
+	return "sentinel: blocked by " + e.blockType.String()
+}
+
+func (e *blockTypeError) Unwrap() error {
+	return ErrBlocked
+}
 
 // BlockError indicates the request was blocked by Sentinel.
 type BlockError struct {
@@ -11,6 +64,8 @@ type BlockError struct {
 	rule SentinelRule
 	// snapshotValue represents the triggered "snapshot" value
 	snapshotValue interface{}
+	// waitHint is the estimated time to wait before the request could pass, zero if unknown.
+	waitHint time.Duration
 }
 
 func (e *BlockError) BlockMsg() string {
@@ -29,12 +84,19 @@ func (e *BlockError) TriggeredValue() interface{} {
 	return e.snapshotValue
 }
 
+// WaitHint returns the estimated time to wait before the blocked request could pass if retried
+// (e.g. by the throttling flow control), or zero if unknown.
+func (e *BlockError) WaitHint() time.Duration {
+	return e.waitHint
+}
+
 func NewBlockErrorFromDeepCopy(from *BlockError) *BlockError {
 	return &BlockError{
 		blockType:     from.blockType,
 		blockMsg:      from.blockMsg,
 		rule:          from.rule,
 		snapshotValue: from.snapshotValue,
+		waitHint:      from.waitHint,
 	}
 }
 
@@ -50,9 +112,23 @@ func NewBlockErrorWithCause(blockType BlockType, blockMsg string, rule SentinelR
 	return &BlockError{blockType: blockType, blockMsg: blockMsg, rule: rule, snapshotValue: snapshot}
 }
 
+// NewBlockErrorWithWaitHint creates the BlockError with the estimated time to wait before the request could pass.
+func NewBlockErrorWithWaitHint(blockType BlockType, blockMsg string, waitHint time.Duration) *BlockError {
+	return &BlockError{blockType: blockType, blockMsg: blockMsg, waitHint: waitHint}
+}
+
 func (e *BlockError) Error() string {
 	if len(e.blockMsg) == 0 {
 		return fmt.Sprintf("SentinelBlockError: %s", e.blockType.String())
 	}
 	return fmt.Sprintf("SentinelBlockError: %s, message: %s", e.blockType.String(), e.blockMsg)
 }
+
+// Unwrap returns the error of the block type (e.g. ErrFlowBlocked), or ErrBlocked for the unknown block types,
+// so that the block errors could be checked with errors.Is instead of type switches.
+func (e *BlockError) Unwrap() error {
+	if err, ok := blockTypeErrors[e.blockType]; ok {
+		return err
+	}
+	return ErrBlocked
+}
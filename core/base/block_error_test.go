@@ -0,0 +1,32 @@
+package base
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockError_Is(t *testing.T) {
+	var err error = NewBlockErrorWithMessage(BlockTypeFlow, "flow exceeded")
+	assert.True(t, errors.Is(err, ErrBlocked))
+	assert.True(t, errors.Is(err, ErrFlowBlocked))
+	assert.False(t, errors.Is(err, ErrCircuitBreakingBlocked))
+
+	wrapped := fmt.Errorf("call downstream: %w", err)
+	assert.True(t, errors.Is(wrapped, ErrBlocked))
+	assert.True(t, errors.Is(wrapped, ErrFlowBlocked))
+	var blockErr *BlockError
+	assert.True(t, errors.As(wrapped, &blockErr))
+	assert.Equal(t, BlockTypeFlow, blockErr.BlockType())
+	assert.Equal(t, "flow exceeded", blockErr.BlockMsg())
+
+	unknown := NewBlockError(BlockType(100))
+	assert.True(t, errors.Is(unknown, ErrBlocked))
+	assert.False(t, errors.Is(unknown, ErrFlowBlocked))
+
+	assert.True(t, errors.Is(ErrSystemBlocked, ErrBlocked))
+	assert.Equal(t, "sentinel: blocked by System", ErrSystemBlocked.Error())
+	assert.False(t, errors.Is(ErrAsyncEntryLeaked, ErrBlocked))
+}
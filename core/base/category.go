@@ -0,0 +1,49 @@
+package base
+
+import "fmt"
+
+// CategoryAttachmentKey is the key of the entry attachment that carries the RequestCategory of the request.
+const CategoryAttachmentKey = "sentinel.category"
+
+// RequestCategory categorizes the requests of a resource (e.g. the read and write operations of a data service),
+// the statistics are maintained for each category separately, and the rules could target a specific category.
+type RequestCategory int32
+
+const (
+	// CategoryNone means the request is not categorized.
+	CategoryNone RequestCategory = iota
+	// CategoryRead represents the read requests.
+	CategoryRead
+	// CategoryWrite represents the write requests.
+	CategoryWrite
+)
+
+// RequestCategoryCount is the amount of the valid categories (CategoryNone excluded).
+const RequestCategoryCount = 2
+
+func (c RequestCategory) String() string {
+	switch c {
+	case CategoryNone:
+		return "None"
+	case CategoryRead:
+		return "Read"
+	case CategoryWrite:
+		return "Write"
+	default:
+		return fmt.Sprintf("%d", c)
+	}
+}
+
+// IsValid checks whether the category is one of the known categories or CategoryNone.
+func (c RequestCategory) IsValid() bool {
+	return c >= CategoryNone && c <= CategoryWrite
+}
+
+// Category returns the category of the entry carried by the attachment CategoryAttachmentKey, CategoryNone if absent.
+func (ctx *EntryContext) Category() RequestCategory {
+	if ctx.Input == nil || ctx.Input.Attachments == nil {
+		return CategoryNone
+	}
+	category, _ := ctx.Input.Attachments[CategoryAttachmentKey].(RequestCategory)
+	return category
+}
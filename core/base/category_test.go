@@ -0,0 +1,27 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntryContext_Category(t *testing.T) {
+	ctx := NewEmptyEntryContext()
+	assert.Equal(t, CategoryNone, ctx.Category())
+
+	ctx.Input = &SentinelInput{Attachments: map[interface{}]interface{}{CategoryAttachmentKey: CategoryWrite}}
+	assert.Equal(t, CategoryWrite, ctx.Category())
+
+	ctx.Input.Attachments[CategoryAttachmentKey] = "write"
+	assert.Equal(t, CategoryNone, ctx.Category(), "category of other types should be ignored")
+}
+
+func TestRequestCategory_IsValid(t *testing.T) {
+	assert.True(t, CategoryNone.IsValid())
+	assert.True(t, CategoryRead.IsValid())
+	assert.True(t, CategoryWrite.IsValid())
+	assert.False(t, RequestCategory(-1).IsValid())
+	assert.False(t, RequestCategory(3).IsValid())
+	assert.Equal(t, "Read", CategoryRead.String())
+}
@@ -3,6 +3,9 @@ package base
 // global variable
 const (
 	TotalInBoundResourceName = "__total_inbound_traffic__"
+	// OverflowResourceName is the resource that aggregates the statistics of the resources beyond
+	// the max resource amount, see stat.SetMaxResourceAmount.
+	OverflowResourceName = "__other__"
 
 	DefaultMaxResourceAmount uint32 = 10000
 
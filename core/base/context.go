@@ -1,6 +1,7 @@
 package base
 
-import "github.com/alibaba/sentinel-golang/util"
+// OriginAttachmentKey is the key of the entry attachment that carries the origin (i.e. the caller) of the request.
+const OriginAttachmentKey = "sentinel.origin"
 
 type EntryContext struct {
 	entry *SentinelEntry
@@ -9,8 +10,12 @@ type EntryContext struct {
 	err error
 	// Use to calculate RT
 	startTime uint64
+	// startTimeNs is the start time in nanoseconds, used to calculate the sub-millisecond RT
+	startTimeNs uint64
 	// the rt of this transaction
 	rt uint64
+	// rtNs is the rt of this transaction in nanoseconds
+	rtNs uint64
 
 	Resource *ResourceWrapper
 	StatNode StatNode
@@ -42,6 +47,20 @@ func (ctx *EntryContext) StartTime() uint64 {
 	return ctx.startTime
 }
 
+// StartTimeNano returns the start time of the entry in nanoseconds.
+func (ctx *EntryContext) StartTimeNano() uint64 {
+	return ctx.startTimeNs
+}
+
+// Origin returns the origin of the entry carried by the attachment OriginAttachmentKey, empty if absent.
+func (ctx *EntryContext) Origin() string {
+	if ctx.Input == nil || ctx.Input.Attachments == nil {
+		return ""
+	}
+	origin, _ := ctx.Input.Attachments[OriginAttachmentKey].(string)
+	return origin
+}
+
 func (ctx *EntryContext) IsBlocked() bool {
 	if ctx.RuleCheckResult == nil {
 		return false
@@ -55,12 +74,26 @@ func (ctx *EntryContext) PutRt(rt uint64) {
 
 func (ctx *EntryContext) Rt() uint64 {
 	if ctx.rt == 0 {
-		rt := util.CurrentTimeMillis() - ctx.StartTime()
+		rt := CurrentTimeMillisForRt() - ctx.StartTime()
 		return rt
 	}
 	return ctx.rt
 }
 
+// PutRtNanos sets the rt of the entry in nanoseconds.
+func (ctx *EntryContext) PutRtNanos(rtNs uint64) {
+	ctx.rtNs = rtNs
+}
+
+// RtNanos returns the rt of the entry in nanoseconds, which is useful for the sub-millisecond resources.
+// The resolution is as coarse as the time ticker unless the precise RT is enabled, see SetPreciseRtEnabled.
+func (ctx *EntryContext) RtNanos() uint64 {
+	if ctx.rtNs == 0 {
+		return CurrentTimeNanoForRt() - ctx.startTimeNs
+	}
+	return ctx.rtNs
+}
+
 func NewEmptyEntryContext() *EntryContext {
 	return &EntryContext{}
 }
@@ -91,7 +124,9 @@ func (ctx *EntryContext) Reset() {
 	ctx.entry = nil
 	ctx.err = nil
 	ctx.startTime = 0
+	ctx.startTimeNs = 0
 	ctx.rt = 0
+	ctx.rtNs = 0
 	ctx.Resource = nil
 	ctx.StatNode = nil
 	ctx.Input.reset()
@@ -12,3 +12,12 @@ func TestEntryContext_IsBlocked(t *testing.T) {
 	ctx.RuleCheckResult = NewTokenResultBlocked(BlockTypeUnknown)
 	assert.True(t, ctx.IsBlocked(), "context with blocked request should indicate blocked")
 }
+
+func TestEntryContext_RtNanos(t *testing.T) {
+	ctx := NewEmptyEntryContext()
+	ctx.startTimeNs = CurrentTimeNanoForRt() - 1000
+	assert.True(t, ctx.RtNanos() >= 1000)
+
+	ctx.PutRtNanos(300)
+	assert.Equal(t, uint64(300), ctx.RtNanos())
+}
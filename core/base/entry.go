@@ -1,7 +1,9 @@
 package base
 
 import (
+	"context"
 	"sync"
+	"sync/atomic"
 
 	"github.com/alibaba/sentinel-golang/logging"
 	"github.com/pkg/errors"
@@ -20,6 +22,10 @@ type SentinelEntry struct {
 	sc *SlotChain
 
 	exitCtl sync.Once
+	// exited indicates whether the entry has been exited, so the late exits never touch the recycled context.
+	exited int32
+	// async is the handle that owns the exit of the entry, see ExitAsync.
+	async *AsyncEntry
 }
 
 func NewSentinelEntry(ctx *EntryContext, rw *ResourceWrapper, sc *SlotChain) *SentinelEntry {
@@ -60,7 +66,17 @@ func WithError(err error) ExitOption {
 	}
 }
 
+// Exit exits the entry. It's ignored if the exit has been transferred to the async handle by ExitAsync,
+// in which case the entry must be exited by the handle.
 func (e *SentinelEntry) Exit(exitOps ...ExitOption) {
+	if e.asyncHandle() != nil {
+		logging.Warn("[SentinelEntry] Ignoring the exit of the entry owned by the async handle", "resource", e.Resource().Name())
+		return
+	}
+	e.exit(exitOps...)
+}
+
+func (e *SentinelEntry) exit(exitOps ...ExitOption) {
 	var options = ExitOptions{
 		err: nil,
 	}
@@ -68,13 +84,16 @@ func (e *SentinelEntry) Exit(exitOps ...ExitOption) {
 		opt(&options)
 	}
 	ctx := e.ctx
-	if ctx == nil {
+	if ctx == nil || atomic.LoadInt32(&e.exited) == 1 {
 		return
 	}
-	if options.err != nil {
-		ctx.SetError(options.err)
-	}
 	e.exitCtl.Do(func() {
+		atomic.StoreInt32(&e.exited, 1)
+		// Only the exit that wins sets the error, as the concurrent exits (e.g. by ExitOnDone) may pass
+		// the check above while the context is being recycled.
+		if options.err != nil {
+			ctx.SetError(options.err)
+		}
 		defer func() {
 			if err := recover(); err != nil {
 				logging.Error(errors.Errorf("%+v", err), "Sentinel internal panic in entry exit func")
@@ -93,3 +112,29 @@ func (e *SentinelEntry) Exit(exitOps ...ExitOption) {
 		}
 	})
 }
+
+// ExitOnDone exits the entry (or the async handle of the entry, see ExitAsync) with the error of the context
+// when the context is done before the entry is exited. It starts a goroutine watching the context until
+// the entry is exited, so it should be called at most once.
+func (e *SentinelEntry) ExitOnDone(ctx context.Context) {
+	if ctx == nil || ctx.Done() == nil {
+		return
+	}
+	exitCh := make(chan struct{})
+	e.WhenExit(func(_ *SentinelEntry, _ *EntryContext) error {
+		close(exitCh)
+		return nil
+	})
+	go func() {
+		select {
+		case <-exitCh:
+		case <-ctx.Done():
+			logging.Debug("[SentinelEntry] Exiting the entry as the context is done", "resource", e.Resource().Name(), "err", ctx.Err())
+			if h := e.asyncHandle(); h != nil {
+				h.Exit(WithError(ctx.Err()))
+			} else {
+				e.exit(WithError(ctx.Err()))
+			}
+		}
+	}()
+}
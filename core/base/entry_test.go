@@ -1,8 +1,11 @@
 package base
 
 import (
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -25,3 +28,33 @@ func TestSentinelEntry_WhenExit(t *testing.T) {
 	entry.Exit()
 	assert.True(t, flag == 1)
 }
+
+func TestSentinelEntry_ConcurrentExit(t *testing.T) {
+	sc := NewSlotChain()
+	for i := 0; i < 100; i++ {
+		ctx := sc.GetPooledContext()
+		entry := NewSentinelEntry(ctx, nil, sc)
+		exits := int32(0)
+		entry.WhenExit(func(_ *SentinelEntry, ctx *EntryContext) error {
+			atomic.AddInt32(&exits, 1)
+			assert.NotNil(t, ctx.Err())
+			return nil
+		})
+
+		start := make(chan struct{})
+		wg := &sync.WaitGroup{}
+		for j := 0; j < 4; j++ {
+			wg.Add(1)
+			go func(j int) {
+				defer wg.Done()
+				<-start
+				entry.exit(WithError(errors.Errorf("exit-%d", j)))
+			}(j)
+		}
+		close(start)
+		wg.Wait()
+		assert.Equal(t, int32(1), atomic.LoadInt32(&exits))
+		// The recycled context is never touched by the late exits.
+		assert.Nil(t, ctx.Err())
+	}
+}
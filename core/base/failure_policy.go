@@ -0,0 +1,159 @@
+package base
+
+import (
+	"path"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/pkg/errors"
+)
+
+// FailurePolicy represents the behavior of the entries when Sentinel itself errors, e.g. a slot panics.
+type FailurePolicy int32
+
+const (
+	// FailOpen lets the traffic pass on the internal errors, which is the default policy.
+	FailOpen FailurePolicy = iota
+	// FailClosed blocks the traffic on the internal errors (with BlockTypeInternalError).
+	FailClosed
+)
+
+func (p FailurePolicy) String() string {
+	switch p {
+	case FailOpen:
+		return "open"
+	case FailClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseFailurePolicy parses the failure policy from its string form, i.e. "open" or "closed".
+func ParseFailurePolicy(s string) (FailurePolicy, error) {
+	switch s {
+	case "open":
+		return FailOpen, nil
+	case "closed":
+		return FailClosed, nil
+	default:
+		return FailOpen, errors.Errorf("unknown failure policy: %s", s)
+	}
+}
+
+// InternalErrorEvent describes an internal error of Sentinel and the failure policy applied to the entry.
+type InternalErrorEvent struct {
+	Resource string
+	// Module is the module where the error occurred, named by the package of the slot, e.g. "flow".
+	Module string
+	Err    error
+	Policy FailurePolicy
+}
+
+// InternalErrorListener is notified on the internal errors. It's called synchronously on the entry path,
+// so it must be fast and should not panic.
+type InternalErrorListener func(event *InternalErrorEvent)
+
+var (
+	failurePolicy          = int32(FailOpen)
+	moduleFailurePolicies  atomic.Value // map[string]FailurePolicy
+	internalErrorListeners atomic.Value // []InternalErrorListener
+	failurePolicyMux       = new(sync.Mutex)
+)
+
+func init() {
+	moduleFailurePolicies.Store(make(map[string]FailurePolicy))
+	internalErrorListeners.Store(make([]InternalErrorListener, 0))
+}
+
+// SetFailurePolicy sets the global failure policy, which takes effect on the modules without overrides.
+func SetFailurePolicy(p FailurePolicy) {
+	atomic.StoreInt32(&failurePolicy, int32(p))
+}
+
+// SetModuleFailurePolicy overrides the failure policy of the module (e.g. "flow", "circuitbreaker").
+func SetModuleFailurePolicy(module string, p FailurePolicy) {
+	failurePolicyMux.Lock()
+	defer failurePolicyMux.Unlock()
+
+	old := moduleFailurePolicies.Load().(map[string]FailurePolicy)
+	m := make(map[string]FailurePolicy, len(old)+1)
+	for k, v := range old {
+		m[k] = v
+	}
+	m[module] = p
+	moduleFailurePolicies.Store(m)
+}
+
+// ResetFailurePolicies restores the global failure policy to FailOpen and removes the module overrides.
+func ResetFailurePolicies() {
+	failurePolicyMux.Lock()
+	defer failurePolicyMux.Unlock()
+
+	atomic.StoreInt32(&failurePolicy, int32(FailOpen))
+	moduleFailurePolicies.Store(make(map[string]FailurePolicy))
+}
+
+// FailurePolicyOf returns the effective failure policy of the module.
+func FailurePolicyOf(module string) FailurePolicy {
+	if p, ok := moduleFailurePolicies.Load().(map[string]FailurePolicy)[module]; ok {
+		return p
+	}
+	return FailurePolicy(atomic.LoadInt32(&failurePolicy))
+}
+
+// RegisterInternalErrorListener registers the listener notified on the internal errors.
+func RegisterInternalErrorListener(l InternalErrorListener) {
+	if l == nil {
+		return
+	}
+	failurePolicyMux.Lock()
+	defer failurePolicyMux.Unlock()
+
+	old := internalErrorListeners.Load().([]InternalErrorListener)
+	ls := make([]InternalErrorListener, 0, len(old)+1)
+	ls = append(ls, old...)
+	internalErrorListeners.Store(append(ls, l))
+}
+
+// ClearInternalErrorListeners removes all the internal error listeners.
+func ClearInternalErrorListeners() {
+	failurePolicyMux.Lock()
+	defer failurePolicyMux.Unlock()
+
+	internalErrorListeners.Store(make([]InternalErrorListener, 0))
+}
+
+// moduleOfSlot returns the module of the slot, i.e. the name of the package where the slot is defined.
+func moduleOfSlot(slot interface{}) string {
+	if slot == nil {
+		return ""
+	}
+	t := reflect.TypeOf(slot)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return path.Base(t.PkgPath())
+}
+
+// onInternalError applies the failure policy of the module to the entry and notifies the listeners.
+func onInternalError(ctx *EntryContext, module string, err error) FailurePolicy {
+	p := FailurePolicyOf(module)
+	reportInternalError(ctx, module, err, p)
+	return p
+}
+
+// reportInternalError logs the internal error with the failure policy applied, and notifies the listeners.
+func reportInternalError(ctx *EntryContext, module string, err error, p FailurePolicy) {
+	resource := ""
+	if ctx.Resource != nil {
+		resource = ctx.Resource.Name()
+	}
+	logging.Error(err, "Sentinel internal panic in SlotChain", "resource", resource, "module", module, "failurePolicy", p.String())
+	event := &InternalErrorEvent{Resource: resource, Module: module, Err: err, Policy: p}
+	for _, l := range internalErrorListeners.Load().([]InternalErrorListener) {
+		l(event)
+	}
+}
@@ -0,0 +1,70 @@
+package base
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseFailurePolicy(t *testing.T) {
+	p, err := ParseFailurePolicy("closed")
+	assert.Nil(t, err)
+	assert.Equal(t, FailClosed, p)
+	p, err = ParseFailurePolicy("open")
+	assert.Nil(t, err)
+	assert.Equal(t, FailOpen, p)
+	_, err = ParseFailurePolicy("half")
+	assert.NotNil(t, err)
+}
+
+func TestFailurePolicyOf(t *testing.T) {
+	defer ResetFailurePolicies()
+
+	assert.Equal(t, FailOpen, FailurePolicyOf("flow"))
+	SetFailurePolicy(FailClosed)
+	SetModuleFailurePolicy("flow", FailOpen)
+	assert.Equal(t, FailOpen, FailurePolicyOf("flow"))
+	assert.Equal(t, FailClosed, FailurePolicyOf("circuitbreaker"))
+
+	ResetFailurePolicies()
+	assert.Equal(t, FailOpen, FailurePolicyOf("circuitbreaker"))
+}
+
+func TestSlotChain_Entry_With_Panic_FailClosed(t *testing.T) {
+	defer ResetFailurePolicies()
+	defer ClearInternalErrorListeners()
+
+	events := make([]*InternalErrorEvent, 0)
+	RegisterInternalErrorListener(func(event *InternalErrorEvent) {
+		events = append(events, event)
+	})
+	// the mock slots are defined in package base
+	SetModuleFailurePolicy("base", FailClosed)
+
+	sc := NewSlotChain()
+	ctx := sc.GetPooledContext()
+	ctx.Resource = NewResourceWrapper("abc", ResTypeCommon, Inbound)
+	ctx.Input = &SentinelInput{AcquireCount: 1}
+	rbs := &badPrepareSlotMock{}
+	ssm := &statisticSlotMock{}
+	sc.AddStatPrepareSlotFirst(rbs)
+	sc.AddStatSlotFirst(ssm)
+	rbs.On("Prepare", mock.Anything).Return()
+
+	r := sc.Entry(ctx)
+	assert.NotNil(t, r)
+	assert.True(t, r.IsBlocked())
+	assert.Equal(t, BlockTypeInternalError, r.BlockError().BlockType())
+	assert.True(t, errors.Is(r.BlockError(), ErrInternalErrorBlocked))
+	assert.True(t, ctx.IsBlocked())
+	assert.NotNil(t, ctx.Err())
+	ssm.AssertNumberOfCalls(t, "OnEntryPassed", 0)
+
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, "abc", events[0].Resource)
+	assert.Equal(t, "base", events[0].Module)
+	assert.Equal(t, FailClosed, events[0].Policy)
+	assert.NotNil(t, events[0].Err)
+}
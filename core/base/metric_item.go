@@ -1,6 +1,7 @@
 package base
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -11,6 +12,14 @@ import (
 
 const metricPartSeparator = "|"
 
+// MetricItemRtHistogramBoundsMs are the upper bounds (in ms, inclusive) of the RT histogram buckets of MetricItem.
+// The last bucket of the histogram counts the RTs beyond the last bound.
+var MetricItemRtHistogramBoundsMs = []uint64{1, 5, 10, 20, 50, 100, 200, 500, 1000, 5000}
+
+// MetricItemClassificationRuntime is the classification of the runtime metric items (see stat.RuntimeMetricItems),
+// which are not traffic statistics of a resource: the sampled value is carried in PassQps.
+const MetricItemClassificationRuntime int32 = -1
+
 // MetricItem represents the data of metric log per line.
 type MetricItem struct {
 	Resource       string
@@ -24,6 +33,39 @@ type MetricItem struct {
 	AvgRt           uint64
 	OccupiedPassQps uint64
 	Concurrency     uint32
+	// AvgRtUs is the average RT in microseconds, which is useful for the sub-millisecond resources.
+	AvgRtUs uint64
+	// MinRt is the min RT of the completed requests, 0 if there are no completed requests.
+	MinRt uint64
+	// RtHistogram is the count of the completed requests of each bucket in MetricItemRtHistogramBoundsMs (optional).
+	RtHistogram []uint64
+}
+
+type metricItemRtJSON struct {
+	Avg       uint64               `json:"avg"`
+	Min       uint64               `json:"min"`
+	AvgUs     uint64               `json:"avgUs,omitempty"`
+	Histogram *rtHistogramItemJSON `json:"histogram,omitempty"`
+}
+
+type rtHistogramItemJSON struct {
+	BoundsMs []uint64 `json:"boundsMs"`
+	Counts   []uint64 `json:"counts"`
+}
+
+// metricItemJSON is the metric log line of the JSON format.
+type metricItemJSON struct {
+	Timestamp       uint64           `json:"timestamp"`
+	Time            string           `json:"time"`
+	Resource        string           `json:"resource"`
+	Classification  int32            `json:"classification"`
+	PassQps         uint64           `json:"passQps"`
+	BlockQps        uint64           `json:"blockQps"`
+	CompleteQps     uint64           `json:"completeQps"`
+	ErrorQps        uint64           `json:"errorQps"`
+	OccupiedPassQps uint64           `json:"occupiedPassQps"`
+	Concurrency     uint32           `json:"concurrency"`
+	Rt              metricItemRtJSON `json:"rt"`
 }
 
 type MetricItemRetriever interface {
@@ -45,6 +87,49 @@ func (m *MetricItem) ToFatString() (string, error) {
 	return b.String(), nil
 }
 
+// ToFatStringWithRtMicros converts the MetricItem to the fat string with the average RT in microseconds
+// appended as the last part, which keeps the positional parts compatible with ToFatString.
+func (m *MetricItem) ToFatStringWithRtMicros() (string, error) {
+	s, err := m.ToFatString()
+	if err != nil {
+		return "", err
+	}
+	return s + metricPartSeparator + strconv.FormatUint(m.AvgRtUs, 10), nil
+}
+
+// ToJSONString converts the MetricItem to a JSON object in a single line, which is the metric log line
+// of the JSON format.
+func (m *MetricItem) ToJSONString() (string, error) {
+	item := metricItemJSON{
+		Timestamp:       m.Timestamp,
+		Time:            util.FormatTimeMillis(m.Timestamp),
+		Resource:        m.Resource,
+		Classification:  m.Classification,
+		PassQps:         m.PassQps,
+		BlockQps:        m.BlockQps,
+		CompleteQps:     m.CompleteQps,
+		ErrorQps:        m.ErrorQps,
+		OccupiedPassQps: m.OccupiedPassQps,
+		Concurrency:     m.Concurrency,
+		Rt: metricItemRtJSON{
+			Avg:   m.AvgRt,
+			Min:   m.MinRt,
+			AvgUs: m.AvgRtUs,
+		},
+	}
+	if len(m.RtHistogram) > 0 {
+		item.Rt.Histogram = &rtHistogramItemJSON{
+			BoundsMs: MetricItemRtHistogramBoundsMs,
+			Counts:   m.RtHistogram,
+		}
+	}
+	b, err := json.Marshal(&item)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
 func (m *MetricItem) ToThinString() (string, error) {
 	b := strings.Builder{}
 	finalName := strings.ReplaceAll(m.Resource, "|", "_")
@@ -120,5 +205,41 @@ func MetricItemFromFatString(line string) (*MetricItem, error) {
 		}
 		item.Classification = int32(cl)
 	}
+	if len(arr) >= 12 {
+		rtUs, err := strconv.ParseUint(arr[11], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		item.AvgRtUs = rtUs
+	}
 	return item, nil
 }
+
+// MetricItemFromJSONString parses the MetricItem from the metric log line of the JSON format.
+func MetricItemFromJSONString(line string) (*MetricItem, error) {
+	if len(line) == 0 {
+		return nil, errors.New("invalid metric line: empty string")
+	}
+	item := &metricItemJSON{}
+	if err := json.Unmarshal([]byte(line), item); err != nil {
+		return nil, err
+	}
+	ret := &MetricItem{
+		Resource:        item.Resource,
+		Classification:  item.Classification,
+		Timestamp:       item.Timestamp,
+		PassQps:         item.PassQps,
+		BlockQps:        item.BlockQps,
+		CompleteQps:     item.CompleteQps,
+		ErrorQps:        item.ErrorQps,
+		AvgRt:           item.Rt.Avg,
+		AvgRtUs:         item.Rt.AvgUs,
+		OccupiedPassQps: item.OccupiedPassQps,
+		Concurrency:     item.Concurrency,
+		MinRt:           item.Rt.Min,
+	}
+	if item.Rt.Histogram != nil {
+		ret.RtHistogram = item.Rt.Histogram.Counts
+	}
+	return ret, nil
+}
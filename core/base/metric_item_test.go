@@ -1,6 +1,7 @@
 package base
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -29,3 +30,55 @@ func TestMetricItemFromFatStringIllegal(t *testing.T) {
 	_, err = MetricItemFromFatString(line2)
 	assert.Error(t, err, "Error should occur when parsing malformed line")
 }
+
+func TestMetricItemJSONString(t *testing.T) {
+	item := &MetricItem{
+		Resource:    "/foo|bar",
+		Timestamp:   1564382218000,
+		PassQps:     4,
+		BlockQps:    9,
+		CompleteQps: 3,
+		AvgRt:       25,
+		MinRt:       3,
+		Concurrency: 2,
+		RtHistogram: []uint64{0, 1, 0, 1, 1, 0, 0, 0, 0, 0, 0},
+	}
+	s, err := item.ToJSONString()
+	assert.NoError(t, err)
+	assert.Contains(t, s, `"resource":"/foo|bar"`)
+	assert.Contains(t, s, `"rt":{"avg":25,"min":3,"histogram":{"boundsMs":[1,5,10,20,50,100,200,500,1000,5000]`)
+
+	parsed, err := MetricItemFromJSONString(s)
+	assert.NoError(t, err)
+	assert.Equal(t, item, parsed)
+
+	_, err = MetricItemFromJSONString("1564382218000|2019-07-29 14:36:58|/foo/*|4|9|3|0|25|0|2|1")
+	assert.Error(t, err)
+}
+
+func TestMetricItemFatStringWithRtMicros(t *testing.T) {
+	item := &MetricItem{
+		Resource:       "foo",
+		Timestamp:      1564382218000,
+		PassQps:        4,
+		CompleteQps:    4,
+		AvgRt:          0,
+		AvgRtUs:        350,
+		Classification: 1,
+	}
+	s, err := item.ToFatStringWithRtMicros()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(s, "|1|350"))
+
+	parsed, err := MetricItemFromFatString(s)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(350), parsed.AvgRtUs)
+	assert.Equal(t, uint64(0), parsed.AvgRt)
+
+	// The lines without the RT in microseconds are still parsed.
+	s, err = item.ToFatString()
+	assert.NoError(t, err)
+	parsed, err = MetricItemFromFatString(s)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), parsed.AvgRtUs)
+}
@@ -0,0 +1,52 @@
+package base
+
+import (
+	"reflect"
+	"sort"
+)
+
+// NamespaceRules holds the rules of the namespaces (e.g. of the isolated instances, see api.New) in a rule manager,
+// except for the default namespace "" whose rules are loaded by LoadRules of the rule manager. The rules of each
+// namespace are the typed slice of the rule manager (e.g. []*flow.Rule), which replace the previous rules of the
+// namespace only. The effective rules of the rule manager are the rules of all the namespaces, and the namespaces
+// are expected to target distinct resources.
+//
+// NamespaceRules is not goroutine-safe, the rule manager should guard it with the lock serializing its rule updates.
+// The zero value is ready to use.
+type NamespaceRules struct {
+	rules map[string]interface{}
+}
+
+// Set replaces the rules of the namespace, and the namespace is removed if the rules are empty.
+func (n *NamespaceRules) Set(namespace string, rules interface{}) {
+	if rules == nil || reflect.ValueOf(rules).Len() == 0 {
+		delete(n.rules, namespace)
+		return
+	}
+	if n.rules == nil {
+		n.rules = make(map[string]interface{})
+	}
+	n.rules[namespace] = rules
+}
+
+// Len returns the number of the namespaces with rules.
+func (n *NamespaceRules) Len() int {
+	return len(n.rules)
+}
+
+// Clear removes the rules of all the namespaces.
+func (n *NamespaceRules) Clear() {
+	n.rules = nil
+}
+
+// Each visits the rules of the namespaces in the order of the namespace names.
+func (n *NamespaceRules) Each(f func(rules interface{})) {
+	namespaces := make([]string, 0, len(n.rules))
+	for ns := range n.rules {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	for _, ns := range namespaces {
+		f(n.rules[ns])
+	}
+}
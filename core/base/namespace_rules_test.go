@@ -0,0 +1,39 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceRules(t *testing.T) {
+	var n NamespaceRules
+	visit := func() []string {
+		ret := make([]string, 0)
+		n.Each(func(rules interface{}) {
+			ret = append(ret, rules.([]string)...)
+		})
+		return ret
+	}
+	assert.Equal(t, 0, n.Len())
+	assert.Equal(t, []string{}, visit())
+
+	n.Set("b", []string{"b1", "b2"})
+	n.Set("a", []string{"a1"})
+	assert.Equal(t, 2, n.Len())
+	// in the order of the namespace names
+	assert.Equal(t, []string{"a1", "b1", "b2"}, visit())
+
+	n.Set("b", []string{"b3"})
+	assert.Equal(t, []string{"a1", "b3"}, visit())
+	// the namespace is removed with empty rules
+	n.Set("a", []string{})
+	n.Set("b", nil)
+	assert.Equal(t, 0, n.Len())
+
+	n.Set("a", []string{"a1"})
+	n.Clear()
+	assert.Equal(t, 0, n.Len())
+	n.Set("a", []string{"a1"})
+	assert.Equal(t, []string{"a1"}, visit())
+}
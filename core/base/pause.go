@@ -0,0 +1,44 @@
+package base
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+// pausedUntilMs is the time (in ms) until which all the rule check slots are bypassed, see PauseRuleChecks.
+var pausedUntilMs uint64
+
+// PauseRuleChecks bypasses all the rule check slots for the given duration, and then re-enables them
+// automatically. The statistic slots still record the entries while paused. It's the break-glass switch
+// for the scenarios where the protection itself is suspected of causing an outage.
+// The later call overrides the former one, and non-positive duration resumes the rule checks immediately.
+func PauseRuleChecks(d time.Duration) {
+	if d <= 0 {
+		ResumeRuleChecks()
+		return
+	}
+	until := util.CurrentTimeMillis() + uint64(d/time.Millisecond)
+	atomic.StoreUint64(&pausedUntilMs, until)
+	logging.Warn("[SlotChain] All the rule checks are paused", "duration", d.String(), "pausedUntilMs", until)
+	time.AfterFunc(d, func() {
+		if atomic.CompareAndSwapUint64(&pausedUntilMs, until, 0) {
+			logging.Info("[SlotChain] The rule checks are resumed automatically")
+		}
+	})
+}
+
+// ResumeRuleChecks re-enables the rule check slots paused by PauseRuleChecks immediately.
+func ResumeRuleChecks() {
+	if atomic.SwapUint64(&pausedUntilMs, 0) > 0 {
+		logging.Info("[SlotChain] The rule checks are resumed")
+	}
+}
+
+// RuleChecksPaused returns whether the rule check slots are bypassed currently.
+func RuleChecksPaused() bool {
+	until := atomic.LoadUint64(&pausedUntilMs)
+	return until > 0 && util.CurrentTimeMillis() < until
+}
@@ -0,0 +1,29 @@
+package base
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPauseRuleChecks(t *testing.T) {
+	defer ResumeRuleChecks()
+
+	assert.False(t, RuleChecksPaused())
+	PauseRuleChecks(time.Hour)
+	assert.True(t, RuleChecksPaused())
+	ResumeRuleChecks()
+	assert.False(t, RuleChecksPaused())
+
+	PauseRuleChecks(50 * time.Millisecond)
+	assert.True(t, RuleChecksPaused())
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, RuleChecksPaused())
+	assert.Equal(t, uint64(0), atomic.LoadUint64(&pausedUntilMs))
+
+	PauseRuleChecks(time.Hour)
+	PauseRuleChecks(0)
+	assert.False(t, RuleChecksPaused())
+}
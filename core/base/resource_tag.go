@@ -0,0 +1,116 @@
+package base
+
+import (
+	"sync"
+)
+
+// ResourceTagListener is notified when new tags are attached to the resource.
+type ResourceTagListener func(resource string)
+
+var (
+	// resourceTags holds the tags (e.g. "tier=gold") of the resources.
+	resourceTags         = make(map[string][]string)
+	resourceTagListeners = make([]ResourceTagListener, 0)
+	resourceTagsMux      = new(sync.RWMutex)
+)
+
+// TagResource attaches the tags (e.g. "team=payment", "tier=gold") to the resource, so that the rules targeting
+// the tags take effect on the resource. The tags are accumulated and never detached, and the listeners are
+// notified only if new tags are attached.
+func TagResource(resource string, tags ...string) {
+	if len(resource) == 0 || len(tags) == 0 {
+		return
+	}
+	resourceTagsMux.RLock()
+	added := missingTags(resourceTags[resource], tags)
+	resourceTagsMux.RUnlock()
+	if len(added) == 0 {
+		return
+	}
+
+	resourceTagsMux.Lock()
+	existing := resourceTags[resource]
+	added = missingTags(existing, tags)
+	if len(added) == 0 {
+		resourceTagsMux.Unlock()
+		return
+	}
+	updated := make([]string, 0, len(existing)+len(added))
+	resourceTags[resource] = append(append(updated, existing...), added...)
+	listeners := resourceTagListeners
+	resourceTagsMux.Unlock()
+
+	for _, l := range listeners {
+		l(resource)
+	}
+}
+
+func missingTags(existing, tags []string) []string {
+	var ret []string
+	for _, t := range tags {
+		if len(t) == 0 || containsTag(existing, t) || containsTag(ret, t) {
+			continue
+		}
+		ret = append(ret, t)
+	}
+	return ret
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// ResourceTags returns the tags of the resource.
+func ResourceTags(resource string) []string {
+	resourceTagsMux.RLock()
+	defer resourceTagsMux.RUnlock()
+
+	return append([]string(nil), resourceTags[resource]...)
+}
+
+// ResourceHasTag checks whether the resource has the tag.
+func ResourceHasTag(resource, tag string) bool {
+	resourceTagsMux.RLock()
+	defer resourceTagsMux.RUnlock()
+
+	return containsTag(resourceTags[resource], tag)
+}
+
+// ResourcesWithTag returns all the resources with the tag.
+func ResourcesWithTag(tag string) []string {
+	resourceTagsMux.RLock()
+	defer resourceTagsMux.RUnlock()
+
+	ret := make([]string, 0)
+	for res, tags := range resourceTags {
+		if containsTag(tags, tag) {
+			ret = append(ret, res)
+		}
+	}
+	return ret
+}
+
+// RegisterResourceTagListener registers the listener notified when new tags are attached to resources,
+// e.g. the rule managers bind the rules targeting the tags to the resource.
+func RegisterResourceTagListener(l ResourceTagListener) {
+	if l == nil {
+		return
+	}
+	resourceTagsMux.Lock()
+	defer resourceTagsMux.Unlock()
+
+	resourceTagListeners = append(resourceTagListeners, l)
+}
+
+// ClearResourceTags detaches the tags of all the resources, which is used for testing.
+func ClearResourceTags() {
+	resourceTagsMux.Lock()
+	defer resourceTagsMux.Unlock()
+
+	resourceTags = make(map[string][]string)
+}
@@ -0,0 +1,39 @@
+package base
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagResource(t *testing.T) {
+	defer ClearResourceTags()
+
+	notified := make([]string, 0)
+	RegisterResourceTagListener(func(resource string) {
+		if resource == "tag-test-a" || resource == "tag-test-b" {
+			notified = append(notified, resource)
+		}
+	})
+
+	TagResource("tag-test-a", "tier=gold", "team=payment", "tier=gold", "")
+	assert.Equal(t, []string{"tier=gold", "team=payment"}, ResourceTags("tag-test-a"))
+	assert.Equal(t, []string{"tag-test-a"}, notified)
+
+	// no new tags, no notification
+	TagResource("tag-test-a", "team=payment")
+	assert.Equal(t, []string{"tag-test-a"}, notified)
+
+	TagResource("tag-test-b", "tier=gold")
+	TagResource("tag-test-a", "criticality=high")
+	assert.Equal(t, []string{"tag-test-a", "tag-test-b", "tag-test-a"}, notified)
+	assert.True(t, ResourceHasTag("tag-test-a", "criticality=high"))
+	assert.False(t, ResourceHasTag("tag-test-b", "criticality=high"))
+
+	res := ResourcesWithTag("tier=gold")
+	sort.Strings(res)
+	assert.Equal(t, []string{"tag-test-a", "tag-test-b"}, res)
+	assert.Empty(t, ResourcesWithTag("tier=silver"))
+	assert.Empty(t, ResourceTags("tag-test-c"))
+}
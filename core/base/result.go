@@ -2,6 +2,7 @@ package base
 
 import (
 	"fmt"
+	"time"
 )
 
 type BlockType uint8
@@ -13,6 +14,15 @@ const (
 	BlockTypeCircuitBreaking
 	BlockTypeSystemFlow
 	BlockTypeHotSpotParamFlow
+	BlockTypeQuota
+	BlockTypeDefaultDeny
+	BlockTypeResourceOverflow
+	BlockTypeComposite
+	BlockTypeChaos
+	// BlockTypeInternalError indicates the request is blocked as Sentinel itself errors under FailClosed policy.
+	BlockTypeInternalError
+	// BlockTypeDeadline indicates the request is blocked as the remaining latency budget is insufficient.
+	BlockTypeDeadline
 )
 
 func (t BlockType) String() string {
@@ -29,6 +39,20 @@ func (t BlockType) String() string {
 		return "System"
 	case BlockTypeHotSpotParamFlow:
 		return "HotSpotParamFlow"
+	case BlockTypeQuota:
+		return "Quota"
+	case BlockTypeDefaultDeny:
+		return "DefaultDeny"
+	case BlockTypeResourceOverflow:
+		return "ResourceOverflow"
+	case BlockTypeComposite:
+		return "Composite"
+	case BlockTypeChaos:
+		return "Chaos"
+	case BlockTypeInternalError:
+		return "InternalError"
+	case BlockTypeDeadline:
+		return "Deadline"
 	default:
 		return fmt.Sprintf("%d", t)
 	}
@@ -71,6 +95,7 @@ func (r *TokenResult) DeepCopyFrom(newResult *TokenResult) {
 			blockMsg:      newResult.blockErr.blockMsg,
 			rule:          newResult.blockErr.rule,
 			snapshotValue: newResult.blockErr.snapshotValue,
+			waitHint:      newResult.blockErr.waitHint,
 		}
 	} else {
 		// TODO: review the reusing logic
@@ -78,6 +103,7 @@ func (r *TokenResult) DeepCopyFrom(newResult *TokenResult) {
 		r.blockErr.blockMsg = newResult.blockErr.blockMsg
 		r.blockErr.rule = newResult.blockErr.rule
 		r.blockErr.snapshotValue = newResult.blockErr.snapshotValue
+		r.blockErr.waitHint = newResult.blockErr.waitHint
 	}
 }
 
@@ -96,6 +122,7 @@ func (r *TokenResult) ResetToBlocked(blockType BlockType) {
 		r.blockErr.blockMsg = ""
 		r.blockErr.rule = nil
 		r.blockErr.snapshotValue = nil
+		r.blockErr.waitHint = 0
 	}
 	r.waitMs = 0
 }
@@ -109,6 +136,7 @@ func (r *TokenResult) ResetToBlockedWithMessage(blockType BlockType, blockMsg st
 		r.blockErr.blockMsg = blockMsg
 		r.blockErr.rule = nil
 		r.blockErr.snapshotValue = nil
+		r.blockErr.waitHint = 0
 	}
 	r.waitMs = 0
 }
@@ -122,6 +150,7 @@ func (r *TokenResult) ResetToBlockedWithCause(blockType BlockType, blockMsg stri
 		r.blockErr.blockMsg = blockMsg
 		r.blockErr.rule = rule
 		r.blockErr.snapshotValue = snapshot
+		r.blockErr.waitHint = 0
 	}
 	r.waitMs = 0
 }
@@ -188,6 +217,16 @@ func NewTokenResultBlockedWithCause(blockType BlockType, blockMsg string, rule S
 	}
 }
 
+// NewTokenResultBlockedWithWaitHint creates the blocked TokenResult with the estimated time to wait
+// before the request could pass, see BlockError.WaitHint.
+func NewTokenResultBlockedWithWaitHint(blockType BlockType, blockMsg string, waitHint time.Duration) *TokenResult {
+	return &TokenResult{
+		status:   ResultStatusBlocked,
+		blockErr: NewBlockErrorWithWaitHint(blockType, blockMsg, waitHint),
+		waitMs:   0,
+	}
+}
+
 func NewTokenResultShouldWait(waitMs uint64) *TokenResult {
 	return &TokenResult{
 		status:   ResultStatusShouldWait,
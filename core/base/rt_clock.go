@@ -0,0 +1,37 @@
+package base
+
+import (
+	"sync/atomic"
+
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+var preciseRtEnabled int32
+
+// SetPreciseRtEnabled sets whether the response time of entries is measured by the precise time
+// rather than the cached coarse time of the time ticker (see util.StartTimeTickerWithResolution).
+// It's useful when the resolution of the time ticker is too coarse for the RT statistic.
+func SetPreciseRtEnabled(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&preciseRtEnabled, 1)
+	} else {
+		atomic.StoreInt32(&preciseRtEnabled, 0)
+	}
+}
+
+// CurrentTimeMillisForRt returns the current time (in ms) to measure the response time of entries.
+func CurrentTimeMillisForRt() uint64 {
+	if atomic.LoadInt32(&preciseRtEnabled) == 1 {
+		return util.PreciseTimeMillis()
+	}
+	return util.CurrentTimeMillis()
+}
+
+// CurrentTimeNanoForRt returns the current time (in ns) to measure the sub-millisecond response time of entries.
+// The resolution is as coarse as the time ticker unless the precise RT is enabled.
+func CurrentTimeNanoForRt() uint64 {
+	if atomic.LoadInt32(&preciseRtEnabled) == 1 {
+		return util.CurrentTimeNano()
+	}
+	return util.CoarseTimeNano()
+}
@@ -0,0 +1,77 @@
+package base
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// IndexedRuleCheckSlot is the RuleCheckSlot whose rules are all registered to the rule resource index
+// via SetRuleResourcesOf. If all the RuleCheckSlots of the slot chain are indexed, the rule checking
+// is skipped entirely for the resources without any rules, which is the fast path of the majority of
+// unprotected resources.
+type IndexedRuleCheckSlot interface {
+	RuleCheckSlot
+	// RulesIndexed indicates whether all the rules of the slot are registered to the rule resource index.
+	RulesIndexed() bool
+}
+
+// ruleResourceIndex is the immutable snapshot of the resources with rules.
+type ruleResourceIndex struct {
+	resources map[string]struct{}
+	// applyToAll indicates there are rules applying to all the resources (e.g. system rules).
+	applyToAll bool
+}
+
+type moduleRuleResources struct {
+	resources  []string
+	applyToAll bool
+}
+
+var (
+	ruleResourceIdx  atomic.Value
+	moduleResources  = make(map[string]*moduleRuleResources)
+	ruleResourcesMux = new(sync.Mutex)
+)
+
+func init() {
+	ruleResourceIdx.Store(&ruleResourceIndex{resources: make(map[string]struct{})})
+}
+
+// SetRuleResourcesOf replaces the resources that have rules of the given module (e.g. flow).
+// applyToAll indicates the module has rules applying to all the resources.
+// It should be called by the rule managers every time the rules are updated.
+func SetRuleResourcesOf(module string, resources []string, applyToAll bool) {
+	ruleResourcesMux.Lock()
+	defer ruleResourcesMux.Unlock()
+
+	moduleResources[module] = &moduleRuleResources{
+		resources:  resources,
+		applyToAll: applyToAll,
+	}
+	idx := &ruleResourceIndex{resources: make(map[string]struct{})}
+	for _, mr := range moduleResources {
+		idx.applyToAll = idx.applyToAll || mr.applyToAll
+		for _, res := range mr.resources {
+			idx.resources[res] = struct{}{}
+		}
+	}
+	ruleResourceIdx.Store(idx)
+}
+
+// ResourceHasExplicitRules checks whether there are rules of any type dedicated to the resource,
+// regardless of the rules applying to all the resources.
+func ResourceHasExplicitRules(resource string) bool {
+	idx := ruleResourceIdx.Load().(*ruleResourceIndex)
+	_, ok := idx.resources[resource]
+	return ok
+}
+
+// ResourceHasRules checks whether there may be rules of any type for the resource.
+func ResourceHasRules(resource string) bool {
+	idx := ruleResourceIdx.Load().(*ruleResourceIndex)
+	if idx.applyToAll {
+		return true
+	}
+	_, ok := idx.resources[resource]
+	return ok
+}
@@ -0,0 +1,72 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type indexedRuleCheckSlotMock struct {
+	mockRuleCheckSlot1
+}
+
+func (m *indexedRuleCheckSlotMock) RulesIndexed() bool {
+	return true
+}
+
+func TestSetRuleResourcesOf(t *testing.T) {
+	defer func() {
+		SetRuleResourcesOf("flow", nil, false)
+		SetRuleResourcesOf("isolation", nil, false)
+		SetRuleResourcesOf("system", nil, false)
+	}()
+
+	assert.False(t, ResourceHasRules("abc"))
+	SetRuleResourcesOf("flow", []string{"abc"}, false)
+	SetRuleResourcesOf("isolation", []string{"def"}, false)
+	assert.True(t, ResourceHasRules("abc"))
+	assert.True(t, ResourceHasRules("def"))
+	assert.False(t, ResourceHasRules("ghi"))
+
+	SetRuleResourcesOf("flow", nil, false)
+	assert.False(t, ResourceHasRules("abc"))
+	assert.True(t, ResourceHasRules("def"))
+
+	SetRuleResourcesOf("system", nil, true)
+	assert.True(t, ResourceHasRules("ghi"))
+}
+
+func TestSlotChain_Entry_SkipRuleChecksOfResourceWithoutRules(t *testing.T) {
+	defer SetRuleResourcesOf("flow", nil, false)
+
+	newContext := func(sc *SlotChain, res string) *EntryContext {
+		ctx := sc.GetPooledContext()
+		rw := NewResourceWrapper(res, ResTypeCommon, Inbound)
+		ctx.Resource = rw
+		ctx.SetEntry(NewSentinelEntry(ctx, rw, sc))
+		ctx.StatNode = &StatNodeMock{}
+		return ctx
+	}
+
+	sc := NewSlotChain()
+	rcs := &indexedRuleCheckSlotMock{}
+	rcs.On("Check", mock.Anything).Return(NewTokenResultPass())
+	sc.AddRuleCheckSlotLast(rcs)
+	assert.True(t, sc.ruleChecksIndexed)
+
+	sc.Entry(newContext(sc, "abc"))
+	rcs.AssertNumberOfCalls(t, "Check", 0)
+
+	SetRuleResourcesOf("flow", []string{"abc"}, false)
+	sc.Entry(newContext(sc, "abc"))
+	rcs.AssertNumberOfCalls(t, "Check", 1)
+
+	// The slot chain with unindexed rule check slots always checks the rules.
+	unindexed := &mockRuleCheckSlot1{}
+	unindexed.On("Check", mock.Anything).Return(NewTokenResultPass())
+	sc.AddRuleCheckSlotLast(unindexed)
+	assert.False(t, sc.ruleChecksIndexed)
+	sc.Entry(newContext(sc, "def"))
+	unindexed.AssertNumberOfCalls(t, "Check", 1)
+}
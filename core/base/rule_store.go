@@ -0,0 +1,104 @@
+package base
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/pkg/errors"
+)
+
+// RuleStore stores the loaded rules of the rule managers (e.g. "flow", "circuitbreaker" and "hotspot"),
+// which are encoded in JSON. The rules are saved to the store once loaded, and could be loaded from the store
+// by the other processes sharing the store (e.g. the workers of the prefork servers with the shared-memory
+// or mmap-backed store), so that all the processes enforce the same rules.
+//
+// The RuleStore must be safe for concurrent use.
+type RuleStore interface {
+	// Save stores the rules of the module, which replace the rules saved before.
+	Save(module string, rules []byte) error
+	// Load returns the rules of the module saved last, or nil if there are none.
+	Load(module string) ([]byte, error)
+}
+
+type memoryRuleStore struct {
+	mux   sync.RWMutex
+	rules map[string][]byte
+}
+
+// NewMemoryRuleStore creates the in-memory RuleStore, which is used by default.
+func NewMemoryRuleStore() RuleStore {
+	return &memoryRuleStore{rules: make(map[string][]byte)}
+}
+
+func (s *memoryRuleStore) Save(module string, rules []byte) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.rules[module] = append([]byte(nil), rules...)
+	return nil
+}
+
+func (s *memoryRuleStore) Load(module string) ([]byte, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	rules, ok := s.rules[module]
+	if !ok {
+		return nil, nil
+	}
+	return append([]byte(nil), rules...), nil
+}
+
+var (
+	ruleStore    = NewMemoryRuleStore()
+	ruleStoreMux = new(sync.RWMutex)
+)
+
+// SetRuleStore sets the RuleStore of the rule managers, nil means the default in-memory store.
+func SetRuleStore(s RuleStore) {
+	if s == nil {
+		s = NewMemoryRuleStore()
+	}
+	ruleStoreMux.Lock()
+	defer ruleStoreMux.Unlock()
+
+	ruleStore = s
+}
+
+// CurrentRuleStore returns the RuleStore of the rule managers.
+func CurrentRuleStore() RuleStore {
+	ruleStoreMux.RLock()
+	defer ruleStoreMux.RUnlock()
+
+	return ruleStore
+}
+
+// SaveRulesToStore saves the rules of the module to the current RuleStore. The failure is logged,
+// as the rules have taken effect in current process anyway.
+func SaveRulesToStore(module string, rules interface{}) {
+	data, err := json.Marshal(rules)
+	if err != nil {
+		logging.Error(err, "[RuleStore] Failed to encode the rules", "module", module)
+		return
+	}
+	if err := CurrentRuleStore().Save(module, data); err != nil {
+		logging.Error(err, "[RuleStore] Failed to save the rules", "module", module)
+	}
+}
+
+// LoadRulesFromStore decodes the rules of the module in the current RuleStore into v,
+// and returns false if there are no rules saved.
+func LoadRulesFromStore(module string, v interface{}) (bool, error) {
+	data, err := CurrentRuleStore().Load(module)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to load the rules of %s from the store", module)
+	}
+	if data == nil {
+		return false, nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, errors.Wrapf(err, "failed to decode the rules of %s from the store", module)
+	}
+	return true, nil
+}
@@ -0,0 +1,46 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingRuleStore struct{}
+
+func (s failingRuleStore) Save(string, []byte) error {
+	return errors.New("save failed")
+}
+
+func (s failingRuleStore) Load(string) ([]byte, error) {
+	return nil, errors.New("load failed")
+}
+
+func TestRuleStore(t *testing.T) {
+	defer SetRuleStore(nil)
+
+	store := NewMemoryRuleStore()
+	SetRuleStore(store)
+	assert.True(t, store == CurrentRuleStore())
+
+	var rules []map[string]interface{}
+	ok, err := LoadRulesFromStore("test", &rules)
+	assert.False(t, ok)
+	assert.NoError(t, err)
+
+	SaveRulesToStore("test", []map[string]interface{}{{"resource": "abc"}})
+	ok, err = LoadRulesFromStore("test", &rules)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"resource": "abc"}}, rules)
+
+	assert.NoError(t, store.Save("test", []byte("{")))
+	_, err = LoadRulesFromStore("test", &rules)
+	assert.Error(t, err)
+
+	SetRuleStore(failingRuleStore{})
+	SaveRulesToStore("test", rules)
+	_, err = LoadRulesFromStore("test", &rules)
+	assert.Error(t, err)
+}
@@ -2,9 +2,10 @@ package base
 
 import (
 	"sync"
+	"time"
 
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
 	"github.com/alibaba/sentinel-golang/logging"
-	"github.com/alibaba/sentinel-golang/util"
 	"github.com/pkg/errors"
 )
 
@@ -52,6 +53,9 @@ type SlotChain struct {
 	statPres   []StatPrepareSlot
 	ruleChecks []RuleCheckSlot
 	stats      []StatSlot
+	// ruleChecksIndexed indicates whether all the rule check slots are IndexedRuleCheckSlot,
+	// so that the rule checking could be skipped for the resources without rules.
+	ruleChecksIndexed bool
 	// EntryContext Pool, used for reuse EntryContext object
 	ctxPool sync.Pool
 }
@@ -81,7 +85,8 @@ func NewSlotChain() *SlotChain {
 // Get a EntryContext from EntryContext ctxPool, if ctxPool doesn't have enough EntryContext then new one.
 func (sc *SlotChain) GetPooledContext() *EntryContext {
 	ctx := sc.ctxPool.Get().(*EntryContext)
-	ctx.startTime = util.CurrentTimeMillis()
+	ctx.startTime = CurrentTimeMillisForRt()
+	ctx.startTimeNs = CurrentTimeNanoForRt()
 	return ctx
 }
 
@@ -107,10 +112,22 @@ func (sc *SlotChain) AddRuleCheckSlotFirst(s RuleCheckSlot) {
 	ns := make([]RuleCheckSlot, 0, len(sc.ruleChecks)+1)
 	ns = append(ns, s)
 	sc.ruleChecks = append(ns, sc.ruleChecks...)
+	sc.refreshRuleChecksIndexed()
 }
 
 func (sc *SlotChain) AddRuleCheckSlotLast(s RuleCheckSlot) {
 	sc.ruleChecks = append(sc.ruleChecks, s)
+	sc.refreshRuleChecksIndexed()
+}
+
+func (sc *SlotChain) refreshRuleChecksIndexed() {
+	for _, s := range sc.ruleChecks {
+		if is, ok := s.(IndexedRuleCheckSlot); !ok || !is.RulesIndexed() {
+			sc.ruleChecksIndexed = false
+			return
+		}
+	}
+	sc.ruleChecksIndexed = true
 }
 
 func (sc *SlotChain) AddStatSlotFirst(s StatSlot) {
@@ -124,32 +141,66 @@ func (sc *SlotChain) AddStatSlotLast(s StatSlot) {
 }
 
 // The entrance of slot chain
-// Return the TokenResult and nil if internal panic.
-func (sc *SlotChain) Entry(ctx *EntryContext) *TokenResult {
+// Return the TokenResult, or on internal panic, nil under FailOpen policy and the blocked result under FailClosed
+// policy (see SetFailurePolicy).
+func (sc *SlotChain) Entry(ctx *EntryContext) (ret *TokenResult) {
+	// the slot in execution, whose module determines the failure policy on panic
+	var current interface{}
+	// statPhase indicates the stat slots are in execution, the passed requests may have been recorded.
+	statPhase := false
 	// This should not happen, unless there are errors existing in Sentinel internal.
 	// If happened, need to add TokenResult in EntryContext
 	defer func() {
 		if err := recover(); err != nil {
-			logging.Error(errors.Errorf("%+v", err), "Sentinel internal panic in SlotChain")
-			ctx.SetError(errors.Errorf("%+v", err))
-			return
+			internalErr := errors.Errorf("%+v", err)
+			ctx.SetError(internalErr)
+			if statPhase {
+				// Always fail open in the stat phase, as blocking the request recorded as passed (e.g. the concurrency)
+				// skips the OnCompleted of the stat slots on exit, which leaks the statistic.
+				reportInternalError(ctx, moduleOfSlot(current), internalErr, FailOpen)
+				ret = ctx.RuleCheckResult
+				return
+			}
+			if onInternalError(ctx, moduleOfSlot(current), internalErr) == FailClosed {
+				ctx.RuleCheckResult = NewTokenResultBlockedWithMessage(BlockTypeInternalError, "sentinel internal error")
+				ret = ctx.RuleCheckResult
+			} else {
+				ret = nil
+			}
 		}
 	}()
 
+	timed := selfmetric.Enabled()
+	var begin time.Time
+
 	// execute prepare slot
 	sps := sc.statPres
 	if len(sps) > 0 {
 		for _, s := range sps {
+			if timed {
+				begin = time.Now()
+			}
+			current = s
 			s.Prepare(ctx)
+			if timed {
+				selfmetric.RecordSlotLatency(s, begin)
+			}
 		}
 	}
 
 	// execute rule based checking slot
 	rcs := sc.ruleChecks
 	var ruleCheckRet *TokenResult
-	if len(rcs) > 0 {
+	if len(rcs) > 0 && !(sc.ruleChecksIndexed && !ResourceHasRules(ctx.Resource.Name())) && !RuleChecksPaused() {
 		for _, s := range rcs {
+			if timed {
+				begin = time.Now()
+			}
+			current = s
 			sr := s.Check(ctx)
+			if timed {
+				selfmetric.RecordSlotLatency(s, begin)
+			}
 			if sr == nil {
 				// nil equals to check pass
 				continue
@@ -170,8 +221,13 @@ func (sc *SlotChain) Entry(ctx *EntryContext) *TokenResult {
 	// execute statistic slot
 	ss := sc.stats
 	ruleCheckRet = ctx.RuleCheckResult
+	statPhase = true
 	if len(ss) > 0 {
 		for _, s := range ss {
+			if timed {
+				begin = time.Now()
+			}
+			current = s
 			// indicate the result of rule based checking slot.
 			if !ruleCheckRet.IsBlocked() {
 				s.OnEntryPassed(ctx)
@@ -179,6 +235,9 @@ func (sc *SlotChain) Entry(ctx *EntryContext) *TokenResult {
 				// The block error should not be nil.
 				s.OnEntryBlocked(ctx, ruleCheckRet.blockErr)
 			}
+			if timed {
+				selfmetric.RecordSlotLatency(s, begin)
+			}
 		}
 	}
 	return ruleCheckRet
@@ -193,8 +252,16 @@ func (sc *SlotChain) exit(ctx *EntryContext) {
 	if ctx.IsBlocked() {
 		return
 	}
+	timed := selfmetric.Enabled()
+	var begin time.Time
 	for _, s := range sc.stats {
+		if timed {
+			begin = time.Now()
+		}
 		s.OnCompleted(ctx)
+		if timed {
+			selfmetric.RecordSlotLatency(s, begin)
+		}
 	}
 	// relieve the context here
 }
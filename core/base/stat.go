@@ -21,6 +21,8 @@ const (
 	MetricEventError
 	// request execute rt, unit is millisecond
 	MetricEventRt
+	// request execute rt, unit is microsecond, which is useful for the sub-millisecond resources
+	MetricEventRtMicros
 	// hack for the number of event
 	MetricEventTotal
 )
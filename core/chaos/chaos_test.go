@@ -0,0 +1,67 @@
+package chaos
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func newContext(resource string) *base.EntryContext {
+	ctx := &base.EntryContext{
+		Resource: base.NewResourceWrapper(resource, base.ResTypeCommon, base.Inbound),
+		Input: &base.SentinelInput{
+			AcquireCount: 1,
+		},
+		Data: make(map[interface{}]interface{}),
+	}
+	ctx.SetEntry(base.NewSentinelEntry(ctx, ctx.Resource, nil))
+	return ctx
+}
+
+func TestIsValidRule(t *testing.T) {
+	assert.NotNil(t, IsValidRule(nil))
+	assert.NotNil(t, IsValidRule(&Rule{DelayMs: 10}))
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc"}))
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc", ErrorRate: 1.5}))
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc", BlockRate: -1}))
+	assert.Nil(t, IsValidRule(&Rule{Resource: "abc", DelayMs: 10}))
+}
+
+func TestSlot(t *testing.T) {
+	defer ClearRules()
+	defer SetEnabled(false)
+
+	_, err := LoadRules([]*Rule{
+		{Resource: "abc", BlockRate: 1},
+		{Resource: "def", DelayMs: 20, ErrorRate: 1},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(GetRules()))
+
+	slot := &Slot{}
+	// disabled by default
+	assert.Nil(t, slot.Check(newContext("abc")))
+
+	SetEnabled(true)
+	r := slot.Check(newContext("abc"))
+	assert.True(t, r != nil && r.IsBlocked())
+	assert.Equal(t, base.BlockTypeChaos, r.BlockError().BlockType())
+
+	ctx := newContext("def")
+	start := time.Now()
+	assert.Nil(t, slot.Check(ctx))
+	assert.True(t, time.Since(start) >= 20*time.Millisecond)
+	assert.Equal(t, ErrInjectedFault, FaultOf(ctx.Entry()))
+	ctx.Entry().Exit()
+	assert.Equal(t, ErrInjectedFault, ctx.Err())
+
+	// the real error is not overridden
+	ctx = newContext("def")
+	assert.Nil(t, slot.Check(ctx))
+	realErr := errors.New("biz error")
+	ctx.Entry().Exit(base.WithError(realErr))
+	assert.Equal(t, realErr, ctx.Err())
+}
@@ -0,0 +1,30 @@
+// Package chaos provides the optional fault-injection layer for the resilience testing, so that the teams could
+// rehearse the circuit breaker and fallback behavior in staging using the same Sentinel plumbing.
+//
+// Each chaos rule configures the faults of a resource: the added latency, the rate of the forced blocks
+// (with BlockTypeChaos) and the rate of the injected errors. The injected error is recorded as the biz error
+// of the entry on exit (unless the entry has a real error), which takes effect for the circuit breakers
+// and the error statistics. The callers could also surface the injected error by FaultOf before exiting the entry.
+//
+// The fault injection is disabled by default and it's toggled by SetEnabled (e.g. via the command API),
+// so the chaos rules never take effect until explicitly enabled:
+//
+//	_, err := chaos.LoadRules([]*chaos.Rule{
+//	    {
+//	        Resource:  "some-api",
+//	        DelayMs:   200,
+//	        ErrorRate: 0.1,
+//	        BlockRate: 0.05,
+//	    },
+//	})
+//	chaos.SetEnabled(true)
+//	...
+//	e, b := sentinel.Entry("some-api")
+//	if b != nil {
+//	    // Blocked, which may be forced by the chaos rule.
+//	}
+//	if err := chaos.FaultOf(e); err != nil {
+//	    // The injected error, handle it as the real one.
+//	}
+//	e.Exit()
+package chaos
@@ -0,0 +1,34 @@
+package chaos
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Rule describes the faults injected to a resource.
+type Rule struct {
+	// ID represents the unique ID of the rule (optional).
+	ID string `json:"id,omitempty"`
+	// Resource represents the resource name.
+	Resource string `json:"resource"`
+	// DelayMs is the latency (in milliseconds) added to the passed entries, 0 means no latency.
+	DelayMs uint32 `json:"delayMs"`
+	// ErrorRate is the rate of the passed entries with the injected error, in [0, 1].
+	ErrorRate float64 `json:"errorRate"`
+	// BlockRate is the rate of the entries forced to be blocked, in [0, 1].
+	BlockRate float64 `json:"blockRate"`
+}
+
+func (r *Rule) String() string {
+	b, err := json.Marshal(r)
+	if err != nil {
+		// Return the fallback string
+		return fmt.Sprintf("{Id=%s, Resource=%s, DelayMs=%d, ErrorRate=%.2f, BlockRate=%.2f}",
+			r.ID, r.Resource, r.DelayMs, r.ErrorRate, r.BlockRate)
+	}
+	return string(b)
+}
+
+func (r *Rule) ResourceName() string {
+	return r.Resource
+}
@@ -0,0 +1,112 @@
+package chaos
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/pkg/errors"
+)
+
+var (
+	ruleMap   = make(map[string][]*Rule)
+	updateMux = new(sync.RWMutex)
+
+	enabled int32
+)
+
+// SetEnabled enables or disables the fault injection, which is disabled by default.
+func SetEnabled(e bool) {
+	if e {
+		atomic.StoreInt32(&enabled, 1)
+	} else {
+		atomic.StoreInt32(&enabled, 0)
+	}
+	logging.Info("[Chaos] Fault injection was toggled", "enabled", e)
+}
+
+// Enabled returns whether the fault injection is enabled.
+func Enabled() bool {
+	return atomic.LoadInt32(&enabled) == 1
+}
+
+// LoadRules loads the given chaos rules to the rule manager, while all previous rules will be replaced.
+func LoadRules(rules []*Rule) (bool, error) {
+	defer selfmetric.RecordRuleUpdate("chaos", time.Now())
+
+	m := make(map[string][]*Rule)
+	for _, r := range rules {
+		if err := IsValidRule(r); err != nil {
+			logging.Warn("[Chaos LoadRules] Ignoring invalid chaos rule", "rule", r, "reason", err)
+			continue
+		}
+		m[r.Resource] = append(m[r.Resource], r)
+	}
+	resources := make([]string, 0, len(m))
+	for res := range m {
+		resources = append(resources, res)
+	}
+
+	updateMux.Lock()
+	ruleMap = m
+	updateMux.Unlock()
+	base.SetRuleResourcesOf("chaos", resources, false)
+
+	if len(m) == 0 {
+		logging.Info("[ChaosRuleManager] Chaos rules were cleared")
+	} else {
+		logging.Info("[ChaosRuleManager] Chaos rules were loaded", "rules", m)
+	}
+	return true, nil
+}
+
+// ClearRules clears all the rules in chaos module.
+func ClearRules() error {
+	_, err := LoadRules(nil)
+	return err
+}
+
+// GetRules returns all the rules based on copy.
+// It doesn't take effect for chaos module if user changes the rule.
+func GetRules() []Rule {
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+
+	ret := make([]Rule, 0, len(ruleMap))
+	for _, rs := range ruleMap {
+		for _, r := range rs {
+			ret = append(ret, *r)
+		}
+	}
+	return ret
+}
+
+func getRulesOf(res string) []*Rule {
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+
+	return ruleMap[res]
+}
+
+// IsValidRule checks whether the given rule is valid.
+func IsValidRule(r *Rule) error {
+	if r == nil {
+		return errors.New("nil Rule")
+	}
+	if len(r.Resource) == 0 {
+		return errors.New("empty resource")
+	}
+	if r.ErrorRate < 0 || r.ErrorRate > 1 {
+		return errors.New("ErrorRate must be in [0, 1]")
+	}
+	if r.BlockRate < 0 || r.BlockRate > 1 {
+		return errors.New("BlockRate must be in [0, 1]")
+	}
+	if r.DelayMs == 0 && r.ErrorRate == 0 && r.BlockRate == 0 {
+		return errors.New("none of DelayMs, ErrorRate and BlockRate is set")
+	}
+	return nil
+}
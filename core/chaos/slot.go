@@ -0,0 +1,76 @@
+package chaos
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/pkg/errors"
+)
+
+// ErrInjectedFault is the error injected by the chaos rules.
+var ErrInjectedFault = errors.New("fault injected by Sentinel chaos rule")
+
+type faultKey struct{}
+
+// FaultOf returns the error injected to the entry, nil if absent.
+// It must be called before exiting the entry, as the context of the entry is recycled on exit.
+func FaultOf(entry *base.SentinelEntry) error {
+	if entry == nil || entry.Context() == nil || entry.Context().Data == nil {
+		return nil
+	}
+	err, _ := entry.Context().Data[faultKey{}].(error)
+	return err
+}
+
+// Slot injects the faults of the chaos rules to the entries if the fault injection is enabled.
+type Slot struct {
+}
+
+// RulesIndexed implements base.IndexedRuleCheckSlot, as all the rules are registered to the rule resource index.
+func (s *Slot) RulesIndexed() bool {
+	return true
+}
+
+func (s *Slot) Check(ctx *base.EntryContext) *base.TokenResult {
+	result := ctx.RuleCheckResult
+	if !Enabled() {
+		return result
+	}
+	for _, rule := range getRulesOf(ctx.Resource.Name()) {
+		if rule.BlockRate > 0 && rand.Float64() < rule.BlockRate {
+			if result == nil {
+				result = base.NewTokenResultBlockedWithCause(base.BlockTypeChaos, "chaos injected block", rule, nil)
+			} else {
+				result.ResetToBlockedWithCause(base.BlockTypeChaos, "chaos injected block", rule, nil)
+			}
+			return result
+		}
+		if rule.DelayMs > 0 {
+			time.Sleep(time.Duration(rule.DelayMs) * time.Millisecond)
+		}
+		if rule.ErrorRate > 0 && rand.Float64() < rule.ErrorRate {
+			injectFault(ctx)
+		}
+	}
+	return result
+}
+
+func injectFault(ctx *base.EntryContext) {
+	if ctx.Data == nil {
+		ctx.Data = make(map[interface{}]interface{})
+	}
+	if _, exist := ctx.Data[faultKey{}]; exist {
+		return
+	}
+	ctx.Data[faultKey{}] = ErrInjectedFault
+	if e := ctx.Entry(); e != nil {
+		// record the injected error as the biz error unless the entry has a real one
+		e.WhenExit(func(_ *base.SentinelEntry, ctx *base.EntryContext) error {
+			if ctx.Err() == nil {
+				ctx.SetError(ErrInjectedFault)
+			}
+			return nil
+		})
+	}
+}
@@ -111,6 +111,9 @@ type circuitBreakerBase struct {
 	nextRetryTimestampMs uint64
 	// state is the state machine of circuit breaker
 	state *State
+	// probing indicates whether there is an in-flight probe in half-open state,
+	// which only takes effect if the rule holds state below MinRequestAmount.
+	probing int32
 }
 
 func (b *circuitBreakerBase) BoundRule() *Rule {
@@ -146,6 +149,7 @@ func (b *circuitBreakerBase) fromClosedToOpen(snapshot interface{}) bool {
 // Return true only if current goroutine successfully accomplished the transformation.
 func (b *circuitBreakerBase) fromOpenToHalfOpen(ctx *base.EntryContext) bool {
 	if b.state.casState(Open, HalfOpen) {
+		atomic.StoreInt32(&b.probing, 1)
 		for _, listener := range stateChangeListeners {
 			listener.OnTransformToHalfOpen(Open, *b.rule)
 		}
@@ -196,6 +200,69 @@ func (b *circuitBreakerBase) fromHalfOpenToClosed() bool {
 	return false
 }
 
+// tryPass checks the state machine of circuit breaker, resetMetric is used to discard the statistic
+// before probing if the rule holds state below MinRequestAmount.
+func (b *circuitBreakerBase) tryPass(ctx *base.EntryContext, resetMetric func()) bool {
+	curStatus := b.CurrentState()
+	if curStatus == Closed {
+		return true
+	} else if curStatus == Open {
+		// switch state to half-open to probe if retry timeout
+		if b.retryTimeoutArrived() && b.fromOpenToHalfOpen(ctx) {
+			if b.rule.HoldStateBelowMinRequest {
+				// the half-open state is decided only by the probes
+				resetMetric()
+			}
+			return true
+		}
+	} else if curStatus == HalfOpen && b.rule.HoldStateBelowMinRequest {
+		// the probes are queued: only one probe is in flight at a time
+		return b.tryAcquireProbe(ctx)
+	}
+	return false
+}
+
+// tryAcquireProbe acquires the probe permission in half-open state.
+// The permission is released when the probe completes, or when the entry is blocked by other slots.
+func (b *circuitBreakerBase) tryAcquireProbe(ctx *base.EntryContext) bool {
+	if !atomic.CompareAndSwapInt32(&b.probing, 0, 1) {
+		return false
+	}
+	if entry := ctx.Entry(); entry != nil {
+		entry.WhenExit(func(entry *base.SentinelEntry, ctx *base.EntryContext) error {
+			if ctx.IsBlocked() {
+				b.releaseProbe()
+			}
+			return nil
+		})
+	}
+	return true
+}
+
+func (b *circuitBreakerBase) releaseProbe() {
+	atomic.StoreInt32(&b.probing, 0)
+}
+
+// onHalfOpenRequestComplete handles the state transformation in half-open state if the rule holds state
+// below MinRequestAmount: the breaker holds half-open until the probes reach MinRequestAmount,
+// and then transforms to open if the threshold is exceeded, otherwise to closed.
+// It returns false if the rule doesn't hold state, and the caller should handle the probe as usual.
+func (b *circuitBreakerBase) onHalfOpenRequestComplete(totalCount uint64, exceeded bool, snapshot interface{}, resetMetric func()) bool {
+	if !b.rule.HoldStateBelowMinRequest {
+		return false
+	}
+	b.releaseProbe()
+	if totalCount < b.rule.MinRequestAmount {
+		return true
+	}
+	if exceeded {
+		b.fromHalfOpenToOpen(snapshot)
+	} else if b.fromHalfOpenToClosed() {
+		resetMetric()
+	}
+	return true
+}
+
 //================================= slowRtCircuitBreaker ====================================
 type slowRtCircuitBreaker struct {
 	circuitBreakerBase
@@ -225,7 +292,7 @@ func newSlowRtCircuitBreakerWithStat(r *Rule, stat *slowRequestLeapArray) *slowR
 func newSlowRtCircuitBreaker(r *Rule) (*slowRtCircuitBreaker, error) {
 	interval := r.StatIntervalMs
 	stat := &slowRequestLeapArray{}
-	leapArray, err := sbase.NewLeapArray(1, interval, stat)
+	leapArray, err := sbase.NewLeapArray(r.getStatSlidingWindowBucketCount(), interval, stat)
 	if err != nil {
 		return nil, err
 	}
@@ -240,16 +307,7 @@ func (b *slowRtCircuitBreaker) BoundStat() interface{} {
 
 // TryPass checks circuit breaker based on state machine of circuit breaker.
 func (b *slowRtCircuitBreaker) TryPass(ctx *base.EntryContext) bool {
-	curStatus := b.CurrentState()
-	if curStatus == Closed {
-		return true
-	} else if curStatus == Open {
-		// switch state to half-open to probe if retry timeout
-		if b.retryTimeoutArrived() && b.fromOpenToHalfOpen(ctx) {
-			return true
-		}
-	}
-	return false
+	return b.tryPass(ctx, b.resetMetric)
 }
 
 func (b *slowRtCircuitBreaker) OnRequestComplete(rt uint64, err error) {
@@ -275,6 +333,9 @@ func (b *slowRtCircuitBreaker) OnRequestComplete(rt uint64, err error) {
 	if curStatus == Open {
 		return
 	} else if curStatus == HalfOpen {
+		if b.onHalfOpenRequestComplete(totalCount, slowRatio > b.maxSlowRequestRatio, slowRatio, b.resetMetric) {
+			return
+		}
 		if rt > b.maxAllowedRt {
 			// fail to probe
 			b.fromHalfOpenToOpen(1.0)
@@ -411,7 +472,7 @@ func newErrorRatioCircuitBreakerWithStat(r *Rule, stat *errorCounterLeapArray) *
 func newErrorRatioCircuitBreaker(r *Rule) (*errorRatioCircuitBreaker, error) {
 	interval := r.StatIntervalMs
 	stat := &errorCounterLeapArray{}
-	leapArray, err := sbase.NewLeapArray(1, interval, stat)
+	leapArray, err := sbase.NewLeapArray(r.getStatSlidingWindowBucketCount(), interval, stat)
 	if err != nil {
 		return nil, err
 	}
@@ -424,16 +485,7 @@ func (b *errorRatioCircuitBreaker) BoundStat() interface{} {
 }
 
 func (b *errorRatioCircuitBreaker) TryPass(ctx *base.EntryContext) bool {
-	curStatus := b.CurrentState()
-	if curStatus == Closed {
-		return true
-	} else if curStatus == Open {
-		// switch state to half-open to probe if retry timeout
-		if b.retryTimeoutArrived() && b.fromOpenToHalfOpen(ctx) {
-			return true
-		}
-	}
-	return false
+	return b.tryPass(ctx, b.resetMetric)
 }
 
 func (b *errorRatioCircuitBreaker) OnRequestComplete(rt uint64, err error) {
@@ -459,6 +511,9 @@ func (b *errorRatioCircuitBreaker) OnRequestComplete(rt uint64, err error) {
 		return
 	}
 	if curStatus == HalfOpen {
+		if b.onHalfOpenRequestComplete(totalCount, errorRatio > b.errorRatioThreshold, errorRatio, b.resetMetric) {
+			return
+		}
 		if err == nil {
 			b.fromHalfOpenToClosed()
 			b.resetMetric()
@@ -591,7 +646,7 @@ func newErrorCountCircuitBreakerWithStat(r *Rule, stat *errorCounterLeapArray) *
 func newErrorCountCircuitBreaker(r *Rule) (*errorCountCircuitBreaker, error) {
 	interval := r.StatIntervalMs
 	stat := &errorCounterLeapArray{}
-	leapArray, err := sbase.NewLeapArray(1, interval, stat)
+	leapArray, err := sbase.NewLeapArray(r.getStatSlidingWindowBucketCount(), interval, stat)
 	if err != nil {
 		return nil, err
 	}
@@ -604,16 +659,7 @@ func (b *errorCountCircuitBreaker) BoundStat() interface{} {
 }
 
 func (b *errorCountCircuitBreaker) TryPass(ctx *base.EntryContext) bool {
-	curStatus := b.CurrentState()
-	if curStatus == Closed {
-		return true
-	} else if curStatus == Open {
-		// switch state to half-open to probe if retry timeout
-		if b.retryTimeoutArrived() && b.fromOpenToHalfOpen(ctx) {
-			return true
-		}
-	}
-	return false
+	return b.tryPass(ctx, b.resetMetric)
 }
 
 func (b *errorCountCircuitBreaker) OnRequestComplete(rt uint64, err error) {
@@ -637,6 +683,9 @@ func (b *errorCountCircuitBreaker) OnRequestComplete(rt uint64, err error) {
 		return
 	}
 	if curStatus == HalfOpen {
+		if b.onHalfOpenRequestComplete(totalCount, errorCount > b.errorCountThreshold, errorCount, b.resetMetric) {
+			return
+		}
 		if err == nil {
 			b.fromHalfOpenToClosed()
 			b.resetMetric()
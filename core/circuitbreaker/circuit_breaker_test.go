@@ -1,7 +1,9 @@
 package circuitbreaker
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/alibaba/sentinel-golang/core/base"
 	"github.com/stretchr/testify/assert"
@@ -56,3 +58,69 @@ func TestStatus(t *testing.T) {
 		assert.True(t, status.casState(HalfOpen, Open))
 	})
 }
+
+func TestErrorCountCircuitBreaker_SlidingWindow(t *testing.T) {
+	r := &Rule{
+		Resource:                     "abc",
+		Strategy:                     ErrorCount,
+		RetryTimeoutMs:               100,
+		MinRequestAmount:             1,
+		StatIntervalMs:               400,
+		StatSlidingWindowBucketCount: 4,
+		Threshold:                    2,
+	}
+	b, err := newErrorCountCircuitBreaker(r)
+	assert.NoError(t, err)
+
+	testErr := errors.New("biz error")
+	b.OnRequestComplete(0, testErr)
+	b.OnRequestComplete(0, testErr)
+	assert.Equal(t, Closed, b.CurrentState())
+	// The errors 150ms ago are still in the sliding window, whatever the window boundary is.
+	time.Sleep(150 * time.Millisecond)
+	b.OnRequestComplete(0, testErr)
+	assert.Equal(t, Open, b.CurrentState())
+}
+
+func TestErrorRatioCircuitBreaker_HoldStateBelowMinRequest(t *testing.T) {
+	r := &Rule{
+		Resource:                 "abc",
+		Strategy:                 ErrorRatio,
+		RetryTimeoutMs:           50,
+		MinRequestAmount:         3,
+		HoldStateBelowMinRequest: true,
+		StatIntervalMs:           10000,
+		Threshold:                0.5,
+	}
+	b, err := newErrorRatioCircuitBreaker(r)
+	assert.NoError(t, err)
+	newCtx := func() *base.EntryContext {
+		ctx := base.NewEmptyEntryContext()
+		ctx.SetEntry(base.NewSentinelEntry(ctx, base.NewResourceWrapper("abc", base.ResTypeCommon, base.Inbound), nil))
+		return ctx
+	}
+	testErr := errors.New("biz error")
+
+	// a single failed request doesn't trip the breaker on low traffic
+	b.OnRequestComplete(0, testErr)
+	assert.Equal(t, Closed, b.CurrentState())
+	b.OnRequestComplete(0, testErr)
+	b.OnRequestComplete(0, testErr)
+	assert.Equal(t, Open, b.CurrentState())
+
+	time.Sleep(60 * time.Millisecond)
+	assert.True(t, b.TryPass(newCtx()))
+	assert.Equal(t, HalfOpen, b.CurrentState())
+	// the probes are queued
+	assert.False(t, b.TryPass(newCtx()))
+
+	// a single failed probe doesn't reopen the breaker
+	b.OnRequestComplete(0, testErr)
+	assert.Equal(t, HalfOpen, b.CurrentState())
+	assert.True(t, b.TryPass(newCtx()))
+	b.OnRequestComplete(0, nil)
+	assert.Equal(t, HalfOpen, b.CurrentState())
+	assert.True(t, b.TryPass(newCtx()))
+	b.OnRequestComplete(0, nil)
+	assert.Equal(t, Closed, b.CurrentState())
+}
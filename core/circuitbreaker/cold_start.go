@@ -0,0 +1,35 @@
+package circuitbreaker
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+var (
+	// processStartMs approximates the time when the process starts.
+	processStartMs = util.CurrentTimeMillis()
+	// coldStartSuppressionMs is the duration after the process starts, during which circuit breaking is suppressed.
+	coldStartSuppressionMs uint64
+)
+
+// SetColdStartSuppression sets the duration after the process starts, during which the circuit breakers
+// neither break nor record the completed requests, so that the startup noise (e.g. cold caches)
+// does not trip the breakers right after deploys. 0 means no suppression.
+func SetColdStartSuppression(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	atomic.StoreUint64(&coldStartSuppressionMs, uint64(d/time.Millisecond))
+}
+
+// InColdStart returns whether circuit breaking is suppressed currently because of the cold start.
+func InColdStart() bool {
+	return inColdStartAt(util.CurrentTimeMillis())
+}
+
+func inColdStartAt(now uint64) bool {
+	suppressionMs := atomic.LoadUint64(&coldStartSuppressionMs)
+	return suppressionMs > 0 && now < processStartMs+suppressionMs
+}
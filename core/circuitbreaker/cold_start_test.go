@@ -0,0 +1,60 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInColdStart(t *testing.T) {
+	defer SetColdStartSuppression(0)
+
+	assert.False(t, inColdStartAt(processStartMs))
+	SetColdStartSuppression(30 * time.Second)
+	assert.True(t, inColdStartAt(processStartMs))
+	assert.True(t, inColdStartAt(processStartMs+29999))
+	assert.False(t, inColdStartAt(processStartMs+30000))
+}
+
+func TestSlot_ColdStartSuppression(t *testing.T) {
+	defer func() {
+		SetColdStartSuppression(0)
+		_ = ClearRules()
+	}()
+	_, err, _ := LoadRules([]*Rule{{
+		Resource:         "abc",
+		Strategy:         ErrorCount,
+		RetryTimeoutMs:   10000,
+		MinRequestAmount: 1,
+		StatIntervalMs:   10000,
+		Threshold:        1,
+	}})
+	assert.NoError(t, err)
+	newCtx := func(err error) *base.EntryContext {
+		ctx := base.NewEmptyEntryContext()
+		ctx.Resource = base.NewResourceWrapper("abc", base.ResTypeCommon, base.Inbound)
+		ctx.SetError(err)
+		ctx.RuleCheckResult = base.NewTokenResultPass()
+		return ctx
+	}
+	slot, statSlot := &Slot{}, &MetricStatSlot{}
+	testErr := errors.New("biz error")
+
+	SetColdStartSuppression(time.Hour)
+	statSlot.OnCompleted(newCtx(testErr))
+	statSlot.OnCompleted(newCtx(testErr))
+	assert.Equal(t, Closed, getBreakersOfResource("abc")[0].CurrentState())
+
+	SetColdStartSuppression(0)
+	statSlot.OnCompleted(newCtx(testErr))
+	statSlot.OnCompleted(newCtx(testErr))
+	assert.Equal(t, Open, getBreakersOfResource("abc")[0].CurrentState())
+	assert.True(t, slot.Check(newCtx(nil)).IsBlocked())
+
+	// the open breaker does not break during the cold start
+	SetColdStartSuppression(time.Hour)
+	assert.False(t, slot.Check(newCtx(nil)).IsBlocked())
+}
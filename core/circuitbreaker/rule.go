@@ -48,8 +48,19 @@ type Rule struct {
 	// MinRequestAmount represents the minimum number of requests (in an active statistic time span)
 	// that can trigger circuit breaking.
 	MinRequestAmount uint64 `json:"minRequestAmount"`
+	// HoldStateBelowMinRequest indicates whether the circuit breaker holds its state while the requests
+	// in the statistic window are below MinRequestAmount, which avoids flapping on low traffic resources.
+	// If it's true, the half-open circuit breaker lets the probes pass one at a time, and neither closes nor
+	// reopens on a single probe until the probes reach MinRequestAmount.
+	HoldStateBelowMinRequest bool `json:"holdStateBelowMinRequest"`
 	// StatIntervalMs represents statistic time interval of the internal circuit breaker (in ms).
+	// It's independent of RetryTimeoutMs, so a short retry timeout doesn't force a short, noisy statistic window.
 	StatIntervalMs uint32 `json:"statIntervalMs"`
+	// StatSlidingWindowBucketCount represents the bucket count of the statistic sliding window.
+	// The statistic is a fixed window (i.e. all the counts are reset every StatIntervalMs) if it's 0 or 1,
+	// otherwise a sliding window that drops the oldest bucket every StatIntervalMs/StatSlidingWindowBucketCount.
+	// StatIntervalMs must be divisible by StatSlidingWindowBucketCount.
+	StatSlidingWindowBucketCount uint32 `json:"statSlidingWindowBucketCount"`
 	// MaxAllowedRtMs indicates that any invocation whose response time exceeds this value (in ms)
 	// will be recorded as a slow request.
 	// MaxAllowedRtMs only takes effect for SlowRequestRatio strategy
@@ -59,19 +70,31 @@ type Rule struct {
 	// for ErrorRatio, it represents the max error request ratio
 	// for ErrorCount, it represents the max error request count
 	Threshold float64 `json:"threshold"`
+	// DeploymentLabel indicates that the rule takes effect only if current process has the label (see config.AppLabels),
+	// and overrides the rules without deployment label of the same resource. Empty means the rule always takes effect.
+	DeploymentLabel string `json:"deploymentLabel,omitempty"`
 }
 
 func (r *Rule) String() string {
 	// fallback string
-	return fmt.Sprintf("{id=%s,resource=%s, strategy=%s, RetryTimeoutMs=%d, MinRequestAmount=%d, StatIntervalMs=%d, MaxAllowedRtMs=%d, Threshold=%f}",
-		r.Id, r.Resource, r.Strategy, r.RetryTimeoutMs, r.MinRequestAmount, r.StatIntervalMs, r.MaxAllowedRtMs, r.Threshold)
+	return fmt.Sprintf("{id=%s,resource=%s, strategy=%s, RetryTimeoutMs=%d, MinRequestAmount=%d, HoldStateBelowMinRequest=%t, StatIntervalMs=%d, StatSlidingWindowBucketCount=%d, MaxAllowedRtMs=%d, Threshold=%f}",
+		r.Id, r.Resource, r.Strategy, r.RetryTimeoutMs, r.MinRequestAmount, r.HoldStateBelowMinRequest, r.StatIntervalMs, r.StatSlidingWindowBucketCount, r.MaxAllowedRtMs, r.Threshold)
+}
+
+// getStatSlidingWindowBucketCount returns the bucket count of the statistic window, 1 for the fixed window.
+func (r *Rule) getStatSlidingWindowBucketCount() uint32 {
+	if r.StatSlidingWindowBucketCount == 0 {
+		return 1
+	}
+	return r.StatSlidingWindowBucketCount
 }
 
 func (r *Rule) isStatReusable(newRule *Rule) bool {
 	if newRule == nil {
 		return false
 	}
-	return r.Resource == newRule.Resource && r.Strategy == newRule.Strategy && r.StatIntervalMs == newRule.StatIntervalMs
+	return r.Resource == newRule.Resource && r.Strategy == newRule.Strategy && r.StatIntervalMs == newRule.StatIntervalMs &&
+		r.getStatSlidingWindowBucketCount() == newRule.getStatSlidingWindowBucketCount()
 }
 
 func (r *Rule) ResourceName() string {
@@ -83,7 +106,9 @@ func (r *Rule) equalsToBase(newRule *Rule) bool {
 		return false
 	}
 	return r.Resource == newRule.Resource && r.Strategy == newRule.Strategy && r.RetryTimeoutMs == newRule.RetryTimeoutMs &&
-		r.MinRequestAmount == newRule.MinRequestAmount && r.StatIntervalMs == newRule.StatIntervalMs
+		r.MinRequestAmount == newRule.MinRequestAmount && r.HoldStateBelowMinRequest == newRule.HoldStateBelowMinRequest &&
+		r.StatIntervalMs == newRule.StatIntervalMs &&
+		r.getStatSlidingWindowBucketCount() == newRule.getStatSlidingWindowBucketCount()
 }
 
 func (r *Rule) equalsTo(newRule *Rule) bool {
@@ -4,7 +4,12 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"time"
 
+	"github.com/alibaba/sentinel-golang/core/audit"
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/config"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
 	"github.com/alibaba/sentinel-golang/logging"
 	"github.com/alibaba/sentinel-golang/util"
 	"github.com/pkg/errors"
@@ -18,6 +23,8 @@ var (
 	breakerRules = make(map[string][]*Rule)
 	breakers     = make(map[string][]CircuitBreaker)
 	updateMux    = &sync.RWMutex{}
+	// updateRuleMux serializes the rule updates of all the namespaces.
+	updateRuleMux = new(sync.Mutex)
 
 	stateChangeListeners = make([]StateChangeListener, 0)
 )
@@ -102,8 +109,30 @@ func GetRules() []Rule {
 	return ret
 }
 
-// ClearRules clear all the previous rules.
+// CircuitBreakers returns all the effective circuit breakers, which is useful for inspecting the breaker states.
+// The returned slice is a copy, but the circuit breakers are shared with the rule manager.
+func CircuitBreakers() []CircuitBreaker {
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+
+	ret := make([]CircuitBreaker, 0, len(breakers))
+	for _, resCBs := range breakers {
+		ret = append(ret, resCBs...)
+	}
+	return ret
+}
+
+// CircuitBreakersOfResource returns the effective circuit breakers of the resource.
+func CircuitBreakersOfResource(resource string) []CircuitBreaker {
+	return getBreakersOfResource(resource)
+}
+
+// ClearRules clear all the previous rules, including the rules of all the namespaces.
 func ClearRules() error {
+	updateRuleMux.Lock()
+	namespaceRules.Clear()
+	updateRuleMux.Unlock()
+
 	_, err, _ := LoadRules(nil)
 	return err
 }
@@ -116,10 +145,36 @@ func ClearRules() error {
 // error: was designed to indicate whether occurs the error.
 // []*Rule: was designed to return failed rules. If there is an error, it returns input rules.
 func LoadRules(rules []*Rule) (bool, error, []*Rule) {
-	ret, err, failedRules := onRuleUpdate(rules)
+	defer selfmetric.RecordRuleUpdate("circuitbreaker", time.Now())
+
+	updateRuleMux.Lock()
+	defer updateRuleMux.Unlock()
+
+	defaultNamespaceRules = rules
+	ret, err, failedRules := onRuleUpdate(withNamespaceRules(rules))
+	if err == nil {
+		base.SaveRulesToStore("circuitbreaker", rules)
+	}
 	return ret, err, failedRules
 }
 
+// LoadRulesFromStore loads the circuit breaking rules saved in the RuleStore (see base.SetRuleStore),
+// e.g. the rules loaded by the other process sharing the store.
+// It returns false if there are no rules saved.
+func LoadRulesFromStore() (bool, error) {
+	rules := make([]*Rule, 0)
+	ok, err := base.LoadRulesFromStore("circuitbreaker", &rules)
+	if !ok || err != nil {
+		return false, err
+	}
+	updateRuleMux.Lock()
+	defer updateRuleMux.Unlock()
+
+	defaultNamespaceRules = rules
+	_, err, _ = onRuleUpdate(withNamespaceRules(rules))
+	return true, err
+}
+
 func getBreakersOfResource(resource string) []CircuitBreaker {
 	ret := make([]CircuitBreaker, 0)
 	updateMux.RLock()
@@ -171,6 +226,7 @@ func insertCbToCbMap(cb CircuitBreaker, res string, m map[string][]CircuitBreake
 // Concurrent safe to update rules
 func onRuleUpdate(rules []*Rule) (ret bool, err error, failedRules []*Rule) {
 	var start uint64
+	var oldRules []*Rule
 	newBreakerRules := make(map[string][]*Rule)
 
 	defer func() {
@@ -188,11 +244,12 @@ func onRuleUpdate(rules []*Rule) (ret bool, err error, failedRules []*Rule) {
 		}
 		logging.Debug("Time statistics(ns) for updating circuit breaker rule", "timeCost", util.CurrentTimeNano()-start)
 		logRuleUpdate(newBreakerRules)
+		audit.RecordRuleChange("circuitbreaker", ruleStringsOf(oldRules), ruleStringsOf(rulesFrom(newBreakerRules)))
 	}()
 
 	// Preset slice capacity to avoid dynamic allocation
 	failedRules = make([]*Rule, 0, len(rules))
-	for _, rule := range rules {
+	for _, rule := range filterRulesByDeploymentLabel(rules) {
 		if rule == nil {
 			continue
 		}
@@ -234,9 +291,12 @@ func onRuleUpdate(rules []*Rule) (ret bool, err error, failedRules []*Rule) {
 	}
 
 	start = util.CurrentTimeNano()
+	lockStart := time.Now()
 	updateMux.Lock()
+	selfmetric.RecordLockWait("circuitbreaker.rules", lockStart)
 	defer updateMux.Unlock()
 
+	oldRules = rulesFrom(breakerRules)
 	for res, resRules := range newBreakerRules {
 		emptyCircuitBreakerList := make([]CircuitBreaker, 0, 0)
 		for _, r := range resRules {
@@ -308,9 +368,39 @@ func onRuleUpdate(rules []*Rule) (ret bool, err error, failedRules []*Rule) {
 
 	breakerRules = toAddBreakerRules
 	breakers = newBreakers
+	resources := make([]string, 0, len(newBreakers))
+	for res := range newBreakers {
+		resources = append(resources, res)
+	}
+	base.SetRuleResourcesOf("circuitbreaker", resources, false)
 	return
 }
 
+// filterRulesByDeploymentLabel returns the rules that take effect in current process.
+// The rules with deployment label take effect only if current process has the label,
+// and override the rules without deployment label of the same resource.
+func filterRulesByDeploymentLabel(rules []*Rule) []*Rule {
+	labeledRes := make(map[string]bool)
+	for _, r := range rules {
+		if r != nil && len(r.DeploymentLabel) > 0 && config.HasAppLabel(r.DeploymentLabel) {
+			labeledRes[r.Resource] = true
+		}
+	}
+	ret := make([]*Rule, 0, len(rules))
+	for _, r := range rules {
+		if r == nil || len(r.DeploymentLabel) == 0 {
+			if r == nil || !labeledRes[r.Resource] {
+				ret = append(ret, r)
+			}
+			continue
+		}
+		if config.HasAppLabel(r.DeploymentLabel) {
+			ret = append(ret, r)
+		}
+	}
+	return ret
+}
+
 func rulesFrom(rm map[string][]*Rule) []*Rule {
 	rules := make([]*Rule, 0)
 	if len(rm) == 0 {
@@ -329,6 +419,14 @@ func rulesFrom(rm map[string][]*Rule) []*Rule {
 	return rules
 }
 
+func ruleStringsOf(rules []*Rule) []string {
+	ret := make([]string, 0, len(rules))
+	for _, r := range rules {
+		ret = append(ret, r.String())
+	}
+	return ret
+}
+
 func logRuleUpdate(m map[string][]*Rule) {
 	rs := rulesFrom(m)
 	if len(rs) == 0 {
@@ -390,6 +488,9 @@ func IsValid(r *Rule) error {
 	if r.StatIntervalMs <= 0 {
 		return errors.New("invalid StatIntervalMs")
 	}
+	if r.StatSlidingWindowBucketCount > 0 && r.StatIntervalMs%r.StatSlidingWindowBucketCount != 0 {
+		return errors.New("invalid StatSlidingWindowBucketCount: StatIntervalMs must be divisible by it")
+	}
 	if r.RetryTimeoutMs <= 0 {
 		return errors.New("invalid RetryTimeoutMs")
 	}
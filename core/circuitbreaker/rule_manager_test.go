@@ -110,6 +110,20 @@ func Test_isApplicableRule_invalid(t *testing.T) {
 			t.Errorf("RuleManager.isApplicable() = %v", got)
 		}
 	})
+	t.Run("slidingWindowBucketCount_isApplicable_false", func(t *testing.T) {
+		rule := &Rule{
+			Resource:                     "abc03",
+			Strategy:                     ErrorCount,
+			RetryTimeoutMs:               1000,
+			MinRequestAmount:             5,
+			StatIntervalMs:               1000,
+			StatSlidingWindowBucketCount: 3,
+			Threshold:                    10,
+		}
+		if got := IsValid(rule); got == nil {
+			t.Errorf("RuleManager.isApplicable() = %v", got)
+		}
+	})
 }
 
 func Test_onRuleUpdate_valid(t *testing.T) {
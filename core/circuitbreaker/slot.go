@@ -7,10 +7,15 @@ import (
 type Slot struct {
 }
 
+// RulesIndexed implements base.IndexedRuleCheckSlot, as all the rules are registered to the rule resource index.
+func (s *Slot) RulesIndexed() bool {
+	return true
+}
+
 func (b *Slot) Check(ctx *base.EntryContext) *base.TokenResult {
 	resource := ctx.Resource.Name()
 	result := ctx.RuleCheckResult
-	if len(resource) == 0 {
+	if len(resource) == 0 || !Enabled() || InColdStart() {
 		return result
 	}
 	if passed, rule := checkPass(ctx); !passed {
@@ -20,6 +20,10 @@ func (c *MetricStatSlot) OnEntryBlocked(_ *base.EntryContext, _ *base.BlockError
 }
 
 func (c *MetricStatSlot) OnCompleted(ctx *base.EntryContext) {
+	if InColdStart() {
+		// the startup noise should not trip the breakers once the suppression ends
+		return
+	}
 	res := ctx.Resource.Name()
 	err := ctx.Err()
 	rt := ctx.Rt()
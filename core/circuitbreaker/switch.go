@@ -0,0 +1,26 @@
+package circuitbreaker
+
+import (
+	"sync/atomic"
+
+	"github.com/alibaba/sentinel-golang/logging"
+)
+
+// disabled indicates whether the circuit breaking is disabled at runtime, see SetEnabled.
+var disabled int32
+
+// SetEnabled enables or disables the circuit breaking at runtime, which is enabled by default.
+// The circuit breaker slot is short-circuited while disabled, but the rules are kept.
+func SetEnabled(e bool) {
+	if e {
+		atomic.StoreInt32(&disabled, 0)
+	} else {
+		atomic.StoreInt32(&disabled, 1)
+	}
+	logging.Info("[CircuitBreaker] Circuit breaking was toggled", "enabled", e)
+}
+
+// Enabled returns whether the circuit breaking is enabled.
+func Enabled() bool {
+	return atomic.LoadInt32(&disabled) == 0
+}
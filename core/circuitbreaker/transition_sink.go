@@ -0,0 +1,264 @@
+package circuitbreaker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultTransitionBufferSize = 1024
+	DefaultWebhookTimeout       = 3 * time.Second
+)
+
+// transitionDrops counts the transition events dropped by all the TransitionSinkListeners as the buffer is full.
+var transitionDrops = selfmetric.NewDropCounter("circuitbreaker.transition")
+
+// TransitionEvent represents a state transition of the circuit breaker.
+type TransitionEvent struct {
+	// Timestamp is the time (in ms) when the transition occurred.
+	Timestamp uint64 `json:"timestamp"`
+	Resource  string `json:"resource"`
+	RuleId    string `json:"ruleId,omitempty"`
+	Strategy  string `json:"strategy"`
+	FromState string `json:"fromState"`
+	ToState   string `json:"toState"`
+	// Snapshot is the triggered value of transforming to Open, e.g. the error ratio.
+	Snapshot interface{} `json:"snapshot,omitempty"`
+}
+
+func (e *TransitionEvent) String() string {
+	if e.Snapshot != nil {
+		return fmt.Sprintf("Circuit breaker of resource %s (rule: %s, strategy: %s) transformed from %s to %s, snapshot: %v",
+			e.Resource, e.RuleId, e.Strategy, e.FromState, e.ToState, e.Snapshot)
+	}
+	return fmt.Sprintf("Circuit breaker of resource %s (rule: %s, strategy: %s) transformed from %s to %s",
+		e.Resource, e.RuleId, e.Strategy, e.FromState, e.ToState)
+}
+
+// TransitionSink persists the state transition events of circuit breakers, e.g. to a file or a notification system.
+type TransitionSink interface {
+	Persist(event *TransitionEvent) error
+}
+
+// TransitionSinkListener is a StateChangeListener that emits the state transitions to the sinks asynchronously,
+// so that slow sinks never block the state transformation of circuit breakers.
+// The events are dropped if the buffer is full.
+//
+// Here is the example code to notify the webhook when a breaker opens:
+//
+//	sink := circuitbreaker.NewWebhookTransitionSink("https://hooks.slack.com/services/xxx",
+//	    circuitbreaker.WithWebhookPayloadFunc(circuitbreaker.SlackPayload))
+//	circuitbreaker.RegisterStateChangeListeners(circuitbreaker.NewTransitionSinkListener(0, sink))
+type TransitionSinkListener struct {
+	sinks     []TransitionSink
+	events    chan *TransitionEvent
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// NewTransitionSinkListener creates a listener emitting events to the sinks, with a buffer of bufferSize events.
+// DefaultTransitionBufferSize is used if bufferSize is not positive.
+func NewTransitionSinkListener(bufferSize int, sinks ...TransitionSink) *TransitionSinkListener {
+	if bufferSize <= 0 {
+		bufferSize = DefaultTransitionBufferSize
+	}
+	l := &TransitionSinkListener{
+		sinks:  sinks,
+		events: make(chan *TransitionEvent, bufferSize),
+		closed: make(chan struct{}),
+	}
+	go util.RunWithRecover(l.run)
+	return l
+}
+
+func (l *TransitionSinkListener) run() {
+	for {
+		select {
+		case e := <-l.events:
+			l.persist(e)
+		case <-l.closed:
+			return
+		}
+	}
+}
+
+func (l *TransitionSinkListener) persist(e *TransitionEvent) {
+	for _, s := range l.sinks {
+		if err := s.Persist(e); err != nil {
+			logging.Error(err, "Failed to persist circuit breaker transition event", "event", e)
+		}
+	}
+}
+
+// Close stops emitting the events, the events remaining in the buffer are discarded.
+func (l *TransitionSinkListener) Close() {
+	l.closeOnce.Do(func() {
+		close(l.closed)
+	})
+}
+
+func (l *TransitionSinkListener) OnTransformToClosed(prev State, rule Rule) {
+	l.emit(prev, Closed, rule, nil)
+}
+
+func (l *TransitionSinkListener) OnTransformToOpen(prev State, rule Rule, snapshot interface{}) {
+	l.emit(prev, Open, rule, snapshot)
+}
+
+func (l *TransitionSinkListener) OnTransformToHalfOpen(prev State, rule Rule) {
+	l.emit(prev, HalfOpen, rule, nil)
+}
+
+func (l *TransitionSinkListener) emit(prev, cur State, rule Rule, snapshot interface{}) {
+	e := &TransitionEvent{
+		Timestamp: util.CurrentTimeMillis(),
+		Resource:  rule.Resource,
+		RuleId:    rule.Id,
+		Strategy:  rule.Strategy.String(),
+		FromState: prev.String(),
+		ToState:   cur.String(),
+		Snapshot:  snapshot,
+	}
+	select {
+	case l.events <- e:
+	default:
+		transitionDrops.Inc()
+	}
+}
+
+// fileTransitionSink appends the events to the file in JSON lines format.
+type fileTransitionSink struct {
+	mux  sync.Mutex
+	file *os.File
+}
+
+// NewFileTransitionSink creates a sink that appends the events to the file of the path in JSON lines format.
+func NewFileTransitionSink(path string) (TransitionSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to open the transition event file")
+	}
+	return &fileTransitionSink{file: f}, nil
+}
+
+func (s *fileTransitionSink) Persist(event *TransitionEvent) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	_, err = s.file.Write(append(b, '\n'))
+	return err
+}
+
+// WebhookPayloadFunc converts the event to the request body of the webhook.
+type WebhookPayloadFunc func(event *TransitionEvent) ([]byte, error)
+
+// SlackPayload converts the event to the payload of Slack incoming webhooks.
+func SlackPayload(event *TransitionEvent) ([]byte, error) {
+	return json.Marshal(map[string]string{"text": event.String()})
+}
+
+type webhookOptions struct {
+	timeout     time.Duration
+	payloadFunc WebhookPayloadFunc
+	states      []State
+}
+
+type WebhookOption func(*webhookOptions)
+
+// WithWebhookTimeout sets the timeout of each webhook request, DefaultWebhookTimeout by default.
+func WithWebhookTimeout(timeout time.Duration) WebhookOption {
+	return func(opts *webhookOptions) {
+		opts.timeout = timeout
+	}
+}
+
+// WithWebhookPayloadFunc sets the function converting the event to the request body,
+// the event is marshaled as JSON by default.
+func WithWebhookPayloadFunc(f WebhookPayloadFunc) WebhookOption {
+	return func(opts *webhookOptions) {
+		opts.payloadFunc = f
+	}
+}
+
+// WithWebhookStates notifies the webhook only when the circuit breaker transforms to the given states,
+// e.g. WithWebhookStates(circuitbreaker.Open). All the transitions are notified by default.
+func WithWebhookStates(states ...State) WebhookOption {
+	return func(opts *webhookOptions) {
+		opts.states = states
+	}
+}
+
+func evaluateWebhookOptions(opts []WebhookOption) *webhookOptions {
+	ret := &webhookOptions{
+		timeout: DefaultWebhookTimeout,
+		payloadFunc: func(event *TransitionEvent) ([]byte, error) {
+			return json.Marshal(event)
+		},
+	}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	return ret
+}
+
+// webhookTransitionSink posts the events to the webhook url.
+type webhookTransitionSink struct {
+	url    string
+	opts   *webhookOptions
+	client *http.Client
+}
+
+// NewWebhookTransitionSink creates a sink that posts the events to the webhook url.
+func NewWebhookTransitionSink(url string, opts ...WebhookOption) TransitionSink {
+	options := evaluateWebhookOptions(opts)
+	return &webhookTransitionSink{
+		url:    url,
+		opts:   options,
+		client: &http.Client{Timeout: options.timeout},
+	}
+}
+
+func (s *webhookTransitionSink) accepts(event *TransitionEvent) bool {
+	if len(s.opts.states) == 0 {
+		return true
+	}
+	for _, st := range s.opts.states {
+		if st.String() == event.ToState {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *webhookTransitionSink) Persist(event *TransitionEvent) error {
+	if !s.accepts(event) {
+		return nil
+	}
+	body, err := s.opts.payloadFunc(event)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("unexpected status code of webhook: %d", resp.StatusCode)
+	}
+	return nil
+}
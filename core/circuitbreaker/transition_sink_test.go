@@ -0,0 +1,77 @@
+package circuitbreaker
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileTransitionSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cb-transition")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "transitions.log")
+	sink, err := NewFileTransitionSink(path)
+	assert.Nil(t, err)
+
+	l := NewTransitionSinkListener(0, sink)
+	defer l.Close()
+	rule := Rule{Id: "r1", Resource: "abc", Strategy: ErrorRatio}
+	l.OnTransformToOpen(Closed, rule, 0.6)
+	l.OnTransformToHalfOpen(Open, rule)
+
+	assert.Eventually(t, func() bool {
+		content, _ := ioutil.ReadFile(path)
+		return strings.Count(string(content), "\n") == 2
+	}, time.Second, 10*time.Millisecond)
+
+	content, _ := ioutil.ReadFile(path)
+	var e TransitionEvent
+	assert.Nil(t, json.Unmarshal([]byte(strings.Split(string(content), "\n")[0]), &e))
+	assert.Equal(t, "abc", e.Resource)
+	assert.Equal(t, "Closed", e.FromState)
+	assert.Equal(t, "Open", e.ToState)
+	assert.Equal(t, 0.6, e.Snapshot)
+}
+
+func TestWebhookTransitionSink(t *testing.T) {
+	bodies := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies <- string(b)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookTransitionSink(server.URL, WithWebhookPayloadFunc(SlackPayload), WithWebhookStates(Open))
+	rule := Rule{Id: "r1", Resource: "abc", Strategy: ErrorCount}
+	assert.Nil(t, sink.Persist(&TransitionEvent{Resource: "abc", FromState: "Open", ToState: "HalfOpen"}))
+
+	l := NewTransitionSinkListener(0, sink)
+	defer l.Close()
+	l.OnTransformToClosed(HalfOpen, rule)
+	l.OnTransformToOpen(Closed, rule, 10)
+
+	select {
+	case b := <-bodies:
+		assert.True(t, strings.HasPrefix(b, `{"text":`))
+		assert.Contains(t, b, "transformed from Closed to Open")
+	case <-time.After(time.Second):
+		t.Fatal("webhook is not notified")
+	}
+	assert.Equal(t, 0, len(bodies))
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	assert.NotNil(t, NewWebhookTransitionSink(failing.URL).Persist(&TransitionEvent{Resource: "abc"}))
+}
@@ -0,0 +1,81 @@
+package cluster
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	ProtobufCodecName = "protobuf"
+	JSONCodecName     = "json"
+)
+
+// Codec serializes the messages of the cluster token protocol, i.e. TokenRequest and TokenResponse.
+type Codec interface {
+	// Name returns the unique name of the codec, which could be used to negotiate the codec with the peers.
+	Name() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+var (
+	codecs = map[string]Codec{
+		ProtobufCodecName: &protobufCodec{},
+		JSONCodecName:     &jsonCodec{},
+	}
+	codecMux = new(sync.RWMutex)
+)
+
+// RegisterCodec registers the codec, the codec with the same name will be replaced.
+func RegisterCodec(c Codec) error {
+	if c == nil || len(c.Name()) == 0 {
+		return errors.New("nil codec or empty codec name")
+	}
+	codecMux.Lock()
+	defer codecMux.Unlock()
+
+	codecs[c.Name()] = c
+	return nil
+}
+
+// GetCodec returns the registered codec of the name, or nil if not exists.
+func GetCodec(name string) Codec {
+	codecMux.RLock()
+	defer codecMux.RUnlock()
+
+	return codecs[name]
+}
+
+// DefaultCodec returns the protobuf codec.
+func DefaultCodec() Codec {
+	return GetCodec(ProtobufCodecName)
+}
+
+// SelectCodec returns the first registered codec among the preferred names, or the default codec if none
+// of them is registered.
+func SelectCodec(preferred ...string) Codec {
+	for _, name := range preferred {
+		if c := GetCodec(name); c != nil {
+			return c
+		}
+	}
+	return DefaultCodec()
+}
+
+// jsonCodec is the JSON codec of the messages.
+type jsonCodec struct {
+}
+
+func (c *jsonCodec) Name() string {
+	return JSONCodecName
+}
+
+func (c *jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (c *jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
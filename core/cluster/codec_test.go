@@ -0,0 +1,123 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	req := &TokenRequest{
+		ID:           12345,
+		RuleID:       "rule-1",
+		AcquireCount: 3,
+		Prioritized:  true,
+		Resource:     "abc",
+		Params:       []string{"p1", "", "p3"},
+	}
+	resp := &TokenResponse{
+		ID:        12345,
+		Status:    TokenStatusShouldWait,
+		Remaining: -7,
+		WaitMs:    20,
+		Message:   "wait",
+
+		Utilization:       1.25,
+		FallbackThreshold: 8,
+	}
+	for _, name := range []string{ProtobufCodecName, JSONCodecName} {
+		t.Run(name, func(t *testing.T) {
+			codec := GetCodec(name)
+			assert.NotNil(t, codec)
+
+			b, err := codec.Marshal(req)
+			assert.Nil(t, err)
+			decodedReq := &TokenRequest{}
+			assert.Nil(t, codec.Unmarshal(b, decodedReq))
+			assert.Equal(t, req, decodedReq)
+
+			b, err = codec.Marshal(resp)
+			assert.Nil(t, err)
+			decodedResp := &TokenResponse{}
+			assert.Nil(t, codec.Unmarshal(b, decodedResp))
+			assert.Equal(t, resp, decodedResp)
+		})
+	}
+}
+
+func TestProtobufCodec(t *testing.T) {
+	codec := DefaultCodec()
+	assert.Equal(t, ProtobufCodecName, codec.Name())
+
+	t.Run("WireFormat", func(t *testing.T) {
+		b, err := codec.Marshal(&TokenRequest{ID: 1, RuleID: "a", AcquireCount: 300})
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{0x08, 0x01, 0x12, 0x01, 'a', 0x18, 0xac, 0x02}, b)
+
+		b, err = codec.Marshal(&TokenResponse{})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(b))
+
+		b, err = codec.Marshal(&TokenResponse{FallbackThreshold: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{0x39, 0, 0, 0, 0, 0, 0, 0, 0x40}, b)
+	})
+
+	t.Run("UnknownFields", func(t *testing.T) {
+		// field 1 = 1, unknown field 9 (bytes), unknown field 10 (fixed32), field 2 = 1
+		b := []byte{0x08, 0x01, 0x4a, 0x02, 'x', 'y', 0x55, 0, 0, 0, 0, 0x10, 0x01}
+		resp := &TokenResponse{}
+		assert.Nil(t, codec.Unmarshal(b, resp))
+		assert.Equal(t, uint64(1), resp.ID)
+		assert.Equal(t, TokenStatusBlocked, resp.Status)
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		assert.NotNil(t, codec.Unmarshal([]byte{0x12, 0x05, 'a'}, &TokenRequest{}))
+		assert.NotNil(t, codec.Unmarshal([]byte{0x08}, &TokenRequest{}))
+	})
+
+	t.Run("UnsupportedType", func(t *testing.T) {
+		_, err := codec.Marshal("abc")
+		assert.NotNil(t, err)
+		assert.NotNil(t, codec.Unmarshal(nil, &struct{}{}))
+	})
+}
+
+type testCodec struct {
+	jsonCodec
+}
+
+func (c *testCodec) Name() string {
+	return "test"
+}
+
+func TestSelectCodec(t *testing.T) {
+	assert.NotNil(t, RegisterCodec(nil))
+	assert.Nil(t, GetCodec("test"))
+	assert.Equal(t, ProtobufCodecName, SelectCodec("test").Name())
+	assert.Equal(t, JSONCodecName, SelectCodec("test", JSONCodecName).Name())
+
+	assert.Nil(t, RegisterCodec(&testCodec{}))
+	defer func() {
+		codecMux.Lock()
+		delete(codecs, "test")
+		codecMux.Unlock()
+	}()
+	assert.Equal(t, "test", SelectCodec("test", JSONCodecName).Name())
+}
+
+func TestAttachLoadReport(t *testing.T) {
+	resp := &TokenResponse{}
+	AttachLoadReport(resp, 100, 0.5, 4)
+	assert.Equal(t, 0.5, resp.Utilization)
+	assert.Equal(t, 25.0, resp.FallbackThreshold)
+
+	// scaled down if overloaded
+	AttachLoadReport(resp, 100, 2, 4)
+	assert.Equal(t, 12.5, resp.FallbackThreshold)
+
+	resp = &TokenResponse{}
+	AttachLoadReport(resp, 100, 0.5, 0)
+	assert.Equal(t, &TokenResponse{}, resp)
+}
@@ -0,0 +1,24 @@
+// Package cluster defines the wire protocol of the cluster flow control, i.e. the token requests and responses
+// exchanged between the token clients and the token server.
+//
+// The messages are serialized by a pluggable Codec. The protobuf codec is the default, and the JSON codec
+// is the built-in fallback for the peers (e.g. sidecars or scripts) without protobuf support. Users could
+// integrate the token flow with the existing RPC infrastructure by registering their own codecs:
+//
+//	cluster.RegisterCodec(myCodec)
+//	codec := cluster.SelectCodec("my-codec", cluster.JSONCodecName)
+//	b, err := codec.Marshal(&cluster.TokenRequest{ID: 1, RuleID: "some-rule", AcquireCount: 1})
+//
+// The hotspot rules in cluster mode acquire the tokens via the TokenService registered by SetTokenService,
+// and carry the param value in TokenRequest.Params, so that the param limits are enforced globally.
+//
+// The token server could piggyback its load report on the token responses (see AttachLoadReport), including
+// the suggested local fallback threshold, which the clients use instead of the cluster threshold when falling
+// back to local checking, e.g. on TokenStatusTooManyRequests during the token server overload.
+//
+// The token server counts the acquired tokens in memory by default. To keep the cluster quotas across the token
+// server failover, back the global windows with Redis via SetWindowStore, adapting the Redis client to RedisClient:
+//
+//	store, err := cluster.NewRedisWindowStore(myRedisClient, "sentinel:")
+//	cluster.SetWindowStore(store)
+package cluster
@@ -0,0 +1,107 @@
+package cluster
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// protobufCodec is the protobuf codec of the messages, which follows the schemas documented on
+// TokenRequest and TokenResponse. The messages are converted to the wire messages below, which are
+// declared with the protobuf struct tags, so that the core is free of the generated stubs.
+type protobufCodec struct {
+}
+
+func (c *protobufCodec) Name() string {
+	return ProtobufCodecName
+}
+
+func (c *protobufCodec) Marshal(v interface{}) ([]byte, error) {
+	switch m := v.(type) {
+	case *TokenRequest:
+		return proto.Marshal(&tokenRequestMessage{
+			Id:           m.ID,
+			RuleId:       m.RuleID,
+			AcquireCount: m.AcquireCount,
+			Prioritized:  m.Prioritized,
+			Resource:     m.Resource,
+			Params:       m.Params,
+		})
+	case *TokenResponse:
+		return proto.Marshal(&tokenResponseMessage{
+			Id:                m.ID,
+			Status:            int32(m.Status),
+			Remaining:         m.Remaining,
+			WaitMs:            m.WaitMs,
+			Message:           m.Message,
+			Utilization:       m.Utilization,
+			FallbackThreshold: m.FallbackThreshold,
+		})
+	default:
+		return nil, errors.Errorf("unsupported message type of protobuf codec: %T", v)
+	}
+}
+
+func (c *protobufCodec) Unmarshal(data []byte, v interface{}) error {
+	switch m := v.(type) {
+	case *TokenRequest:
+		msg := &tokenRequestMessage{}
+		if err := proto.Unmarshal(data, msg); err != nil {
+			return errors.Wrap(err, "failed to unmarshal the token request")
+		}
+		*m = TokenRequest{
+			ID:           msg.Id,
+			RuleID:       msg.RuleId,
+			AcquireCount: msg.AcquireCount,
+			Prioritized:  msg.Prioritized,
+			Resource:     msg.Resource,
+			Params:       msg.Params,
+		}
+		return nil
+	case *TokenResponse:
+		msg := &tokenResponseMessage{}
+		if err := proto.Unmarshal(data, msg); err != nil {
+			return errors.Wrap(err, "failed to unmarshal the token response")
+		}
+		*m = TokenResponse{
+			ID:                msg.Id,
+			Status:            TokenStatus(msg.Status),
+			Remaining:         msg.Remaining,
+			WaitMs:            msg.WaitMs,
+			Message:           msg.Message,
+			Utilization:       msg.Utilization,
+			FallbackThreshold: msg.FallbackThreshold,
+		}
+		return nil
+	default:
+		return errors.Errorf("unsupported message type of protobuf codec: %T", v)
+	}
+}
+
+// tokenRequestMessage is the wire message of TokenRequest.
+type tokenRequestMessage struct {
+	Id           uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	RuleId       string   `protobuf:"bytes,2,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	AcquireCount uint32   `protobuf:"varint,3,opt,name=acquire_count,json=acquireCount,proto3" json:"acquire_count,omitempty"`
+	Prioritized  bool     `protobuf:"varint,4,opt,name=prioritized,proto3" json:"prioritized,omitempty"`
+	Resource     string   `protobuf:"bytes,5,opt,name=resource,proto3" json:"resource,omitempty"`
+	Params       []string `protobuf:"bytes,6,rep,name=params,proto3" json:"params,omitempty"`
+}
+
+func (m *tokenRequestMessage) Reset()         { *m = tokenRequestMessage{} }
+func (m *tokenRequestMessage) String() string { return proto.CompactTextString(m) }
+func (*tokenRequestMessage) ProtoMessage()    {}
+
+// tokenResponseMessage is the wire message of TokenResponse.
+type tokenResponseMessage struct {
+	Id                uint64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status            int32   `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	Remaining         int64   `protobuf:"zigzag64,3,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	WaitMs            uint32  `protobuf:"varint,4,opt,name=wait_ms,json=waitMs,proto3" json:"wait_ms,omitempty"`
+	Message           string  `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	Utilization       float64 `protobuf:"fixed64,6,opt,name=utilization,proto3" json:"utilization,omitempty"`
+	FallbackThreshold float64 `protobuf:"fixed64,7,opt,name=fallback_threshold,json=fallbackThreshold,proto3" json:"fallback_threshold,omitempty"`
+}
+
+func (m *tokenResponseMessage) Reset()         { *m = tokenResponseMessage{} }
+func (m *tokenResponseMessage) String() string { return proto.CompactTextString(m) }
+func (*tokenResponseMessage) ProtoMessage()    {}
@@ -0,0 +1,112 @@
+package cluster
+
+// TokenStatus is the status of the token response.
+type TokenStatus int32
+
+const (
+	// TokenStatusOK indicates the token is acquired.
+	TokenStatusOK TokenStatus = iota
+	// TokenStatusBlocked indicates the token is not acquired as the threshold is exceeded.
+	TokenStatusBlocked
+	// TokenStatusShouldWait indicates the token will be available after WaitMs.
+	TokenStatusShouldWait
+	// TokenStatusNoRuleExists indicates the token server has no rule of the request.
+	TokenStatusNoRuleExists
+	// TokenStatusBadRequest indicates the request is invalid.
+	TokenStatusBadRequest
+	// TokenStatusTooManyRequests indicates the token server is overloaded.
+	TokenStatusTooManyRequests
+	// TokenStatusFail indicates the token server fails to handle the request.
+	TokenStatusFail
+)
+
+func (s TokenStatus) String() string {
+	switch s {
+	case TokenStatusOK:
+		return "OK"
+	case TokenStatusBlocked:
+		return "Blocked"
+	case TokenStatusShouldWait:
+		return "ShouldWait"
+	case TokenStatusNoRuleExists:
+		return "NoRuleExists"
+	case TokenStatusBadRequest:
+		return "BadRequest"
+	case TokenStatusTooManyRequests:
+		return "TooManyRequests"
+	case TokenStatusFail:
+		return "Fail"
+	default:
+		return "Undefined"
+	}
+}
+
+// TokenRequest is the request of acquiring tokens from the token server.
+//
+// The protobuf schema of TokenRequest is:
+//
+//	message TokenRequest {
+//	    uint64 id = 1;
+//	    string rule_id = 2;
+//	    uint32 acquire_count = 3;
+//	    bool prioritized = 4;
+//	    string resource = 5;
+//	    repeated string params = 6;
+//	}
+type TokenRequest struct {
+	// ID is the unique ID of the request, which is echoed back in the response.
+	ID uint64 `json:"id"`
+	// RuleID is the ID of the cluster rule.
+	RuleID       string `json:"ruleId"`
+	AcquireCount uint32 `json:"acquireCount"`
+	Prioritized  bool   `json:"prioritized,omitempty"`
+	Resource     string `json:"resource,omitempty"`
+	// Params is the hotspot parameters of the request (optional).
+	Params []string `json:"params,omitempty"`
+}
+
+// TokenResponse is the response of the token request.
+//
+// The protobuf schema of TokenResponse is:
+//
+//	message TokenResponse {
+//	    uint64 id = 1;
+//	    int32 status = 2;
+//	    sint64 remaining = 3;
+//	    uint32 wait_ms = 4;
+//	    string message = 5;
+//	    double utilization = 6;
+//	    double fallback_threshold = 7;
+//	}
+type TokenResponse struct {
+	ID     uint64      `json:"id"`
+	Status TokenStatus `json:"status"`
+	// Remaining is the remaining tokens after the request.
+	Remaining int64 `json:"remaining"`
+	// WaitMs is the time to wait for ShouldWait status.
+	WaitMs  uint32 `json:"waitMs,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Utilization is the load report of the token server (optional), i.e. the ratio of the acquired tokens
+	// to the threshold of the rule observed in current window. Greater than 1 means the rule is overloaded.
+	Utilization float64 `json:"utilization,omitempty"`
+	// FallbackThreshold is the local threshold suggested by the token server (optional), which the client
+	// falls back to instead of the cluster threshold if the token server is overloaded or unavailable.
+	// 0 means no suggestion. See AttachLoadReport.
+	FallbackThreshold float64 `json:"fallbackThreshold,omitempty"`
+}
+
+// AttachLoadReport attaches the load report of the rule to the token response, which should be called by
+// the token server. The suggested fallback threshold is the even share of the cluster threshold among
+// the clients, scaled down by the utilization if the rule is overloaded, so that the clients falling back
+// to local checking together do not exceed the cluster threshold.
+func AttachLoadReport(resp *TokenResponse, threshold float64, utilization float64, clientCount int) {
+	if resp == nil || threshold <= 0 || clientCount <= 0 || utilization < 0 {
+		return
+	}
+	resp.Utilization = utilization
+	share := threshold / float64(clientCount)
+	if utilization > 1 {
+		share /= utilization
+	}
+	resp.FallbackThreshold = share
+}
@@ -0,0 +1,40 @@
+package cluster
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// TokenService acquires the tokens of the cluster rules from the token server.
+// The token client, which talks to the token server with the codec, should implement it and be
+// registered via SetTokenService.
+type TokenService interface {
+	RequestToken(req *TokenRequest) (*TokenResponse, error)
+}
+
+var (
+	tokenService    TokenService
+	tokenServiceMux = new(sync.RWMutex)
+	nextRequestID   uint64
+)
+
+// SetTokenService sets the token service used by the rules in cluster mode, nil to unset.
+func SetTokenService(s TokenService) {
+	tokenServiceMux.Lock()
+	defer tokenServiceMux.Unlock()
+
+	tokenService = s
+}
+
+// CurrentTokenService returns current token service, or nil if not set.
+func CurrentTokenService() TokenService {
+	tokenServiceMux.RLock()
+	defer tokenServiceMux.RUnlock()
+
+	return tokenService
+}
+
+// NextRequestID generates the unique ID of the token request in current process.
+func NextRequestID() uint64 {
+	return atomic.AddUint64(&nextRequestID, 1)
+}
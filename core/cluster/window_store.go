@@ -0,0 +1,110 @@
+package cluster
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// WindowStore stores the global window counters of the token server. By default the token server counts
+// the acquired tokens in its memory, which are lost (i.e. all cluster quotas are reset to zero) if the token
+// server restarts or fails over. The token server could back its global windows with an external store
+// (e.g. Redis, see NewRedisWindowStore) via SetWindowStore, so that the new token server continues with the
+// counters of current windows.
+type WindowStore interface {
+	// TryAcquire adds the acquire count to the counter of the key in the window starting at windowStartMs,
+	// only if the counter does not exceed maxCount after that. It returns the remaining tokens of the window
+	// and whether the tokens are acquired.
+	TryAcquire(key string, windowStartMs uint64, windowLengthMs uint64, acquire int64, maxCount int64) (int64, bool, error)
+}
+
+var (
+	windowStore    WindowStore
+	windowStoreMux = new(sync.RWMutex)
+)
+
+// SetWindowStore sets the store of the global windows of the token server, nil to count in memory.
+func SetWindowStore(s WindowStore) {
+	windowStoreMux.Lock()
+	defer windowStoreMux.Unlock()
+
+	windowStore = s
+}
+
+// CurrentWindowStore returns current store of the global windows, or nil if not set.
+func CurrentWindowStore() WindowStore {
+	windowStoreMux.RLock()
+	defer windowStoreMux.RUnlock()
+
+	return windowStore
+}
+
+// RedisClient is the minimal Redis command used by the Redis window store, so that any Redis client
+// (e.g. go-redis or redigo) could be adapted without introducing the dependency here.
+type RedisClient interface {
+	// Eval evaluates the Lua script (i.e. EVAL), and returns the reply of the script, where the integer reply
+	// is int64 and the array reply is []interface{}.
+	Eval(script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+// tryAcquireScript checks and increases the counter of the window, and sets the expiration of the counter
+// on the first acquiring of the window, all in one atomic step. It replies {1, remaining} if acquired,
+// or {0, remaining} if the counter would exceed the max count.
+const tryAcquireScript = `
+local count = tonumber(redis.call('GET', KEYS[1]) or '0')
+local acquire = tonumber(ARGV[1])
+local maxCount = tonumber(ARGV[2])
+if count + acquire > maxCount then
+	return {0, maxCount - count}
+end
+count = redis.call('INCRBY', KEYS[1], acquire)
+if count == acquire then
+	redis.call('PEXPIRE', KEYS[1], ARGV[3])
+end
+return {1, maxCount - count}
+`
+
+// redisWindowStore keeps the counter of each window as a Redis key, which expires after the window passed.
+type redisWindowStore struct {
+	client    RedisClient
+	keyPrefix string
+}
+
+// NewRedisWindowStore creates the window store backed by Redis. All the token servers sharing the same
+// Redis and key prefix share the global windows.
+func NewRedisWindowStore(client RedisClient, keyPrefix string) (WindowStore, error) {
+	if client == nil {
+		return nil, errors.New("nil redis client")
+	}
+	return &redisWindowStore{
+		client:    client,
+		keyPrefix: keyPrefix,
+	}, nil
+}
+
+func (s *redisWindowStore) TryAcquire(key string, windowStartMs uint64, windowLengthMs uint64, acquire int64, maxCount int64) (int64, bool, error) {
+	if acquire <= 0 || windowLengthMs == 0 {
+		return 0, false, errors.Errorf("invalid acquire count %d or window length %d", acquire, windowLengthMs)
+	}
+	k := s.keyPrefix + key + ":" + strconv.FormatUint(windowStartMs, 10)
+	// Keep the key a bit longer than the window for the clock skew between the token servers.
+	ttlMs := 2 * windowLengthMs
+	reply, err := s.client.Eval(tryAcquireScript, []string{k}, acquire, maxCount, ttlMs)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "failed to acquire from the window counter")
+	}
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return 0, false, errors.Errorf("unexpected reply of the window counter: %v", reply)
+	}
+	acquired, ok1 := values[0].(int64)
+	remaining, ok2 := values[1].(int64)
+	if !ok1 || !ok2 {
+		return 0, false, errors.Errorf("unexpected reply of the window counter: %v", reply)
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, acquired == 1, nil
+}
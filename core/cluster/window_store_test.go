@@ -0,0 +1,128 @@
+package cluster
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRedisClient emulates tryAcquireScript in memory.
+type fakeRedisClient struct {
+	mux    sync.Mutex
+	values map[string]int64
+	ttls   map[string]uint64
+	err    error
+}
+
+func newFakeRedisClient() *fakeRedisClient {
+	return &fakeRedisClient{values: make(map[string]int64), ttls: make(map[string]uint64)}
+}
+
+func (c *fakeRedisClient) Eval(script string, keys []string, args ...interface{}) (interface{}, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+	if script != tryAcquireScript || len(keys) != 1 || len(args) != 3 {
+		return nil, errors.New("unexpected script")
+	}
+	key, acquire, maxCount := keys[0], args[0].(int64), args[1].(int64)
+	count := c.values[key]
+	if count+acquire > maxCount {
+		return []interface{}{int64(0), maxCount - count}, nil
+	}
+	count += acquire
+	c.values[key] = count
+	if count == acquire {
+		c.ttls[key] = args[2].(uint64)
+	}
+	return []interface{}{int64(1), maxCount - count}, nil
+}
+
+type replyRedisClient struct {
+	reply interface{}
+}
+
+func (c replyRedisClient) Eval(string, []string, ...interface{}) (interface{}, error) {
+	return c.reply, nil
+}
+
+func TestRedisWindowStore(t *testing.T) {
+	_, err := NewRedisWindowStore(nil, "sentinel:")
+	assert.NotNil(t, err)
+
+	client := newFakeRedisClient()
+	store, err := NewRedisWindowStore(client, "sentinel:")
+	assert.Nil(t, err)
+
+	t.Run("TryAcquire", func(t *testing.T) {
+		remaining, ok, err := store.TryAcquire("r1:a", 1000, 1000, 2, 3)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, int64(1), remaining)
+		assert.Equal(t, uint64(2000), client.ttls["sentinel:r1:a:1000"])
+
+		remaining, ok, err = store.TryAcquire("r1:a", 1000, 1000, 2, 3)
+		assert.Nil(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, int64(1), remaining)
+		// The exceeded tokens are never counted.
+		assert.Equal(t, int64(2), client.values["sentinel:r1:a:1000"])
+
+		_, ok, _ = store.TryAcquire("r1:a", 1000, 1000, 1, 3)
+		assert.True(t, ok)
+		_, ok, _ = store.TryAcquire("r1:a", 1000, 1000, 1, 3)
+		assert.False(t, ok)
+	})
+
+	t.Run("NextWindow", func(t *testing.T) {
+		_, ok, err := store.TryAcquire("r1:a", 2000, 1000, 3, 3)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("SharedByTokenServers", func(t *testing.T) {
+		other, _ := NewRedisWindowStore(client, "sentinel:")
+		_, ok, _ := other.TryAcquire("r1:a", 2000, 1000, 1, 3)
+		assert.False(t, ok)
+	})
+
+	t.Run("InvalidArgs", func(t *testing.T) {
+		_, _, err := store.TryAcquire("r1:a", 1000, 0, 1, 3)
+		assert.NotNil(t, err)
+		_, _, err = store.TryAcquire("r1:a", 1000, 1000, 0, 3)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("UnexpectedReply", func(t *testing.T) {
+		store, _ := NewRedisWindowStore(replyRedisClient{reply: int64(1)}, "")
+		_, _, err := store.TryAcquire("r1:a", 1000, 1000, 1, 3)
+		assert.NotNil(t, err)
+		store, _ = NewRedisWindowStore(replyRedisClient{reply: []interface{}{"1", "2"}}, "")
+		_, _, err = store.TryAcquire("r1:a", 1000, 1000, 1, 3)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ClientError", func(t *testing.T) {
+		client.err = errors.New("connection refused")
+		defer func() {
+			client.err = nil
+		}()
+		_, ok, err := store.TryAcquire("r1:b", 1000, 1000, 1, 3)
+		assert.NotNil(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestSetWindowStore(t *testing.T) {
+	defer SetWindowStore(nil)
+
+	assert.Nil(t, CurrentWindowStore())
+	store, _ := NewRedisWindowStore(newFakeRedisClient(), "")
+	SetWindowStore(store)
+	assert.Equal(t, store, CurrentWindowStore())
+}
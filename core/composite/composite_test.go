@@ -0,0 +1,89 @@
+package composite
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+func newContext(node base.StatNode) *base.EntryContext {
+	return &base.EntryContext{
+		Resource: base.NewResourceWrapper("abc", base.ResTypeCommon, base.Inbound),
+		StatNode: node,
+		Input: &base.SentinelInput{
+			AcquireCount: 1,
+		},
+	}
+}
+
+func TestIsValidRule(t *testing.T) {
+	assert.NotNil(t, IsValidRule(nil))
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc"}))
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc", Logic: Logic(5), Conditions: []*Condition{{MetricType: QPS, Threshold: 10}}}))
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc", Conditions: []*Condition{{MetricType: MetricType(10), Threshold: 10}}}))
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc", Conditions: []*Condition{{MetricType: ErrorRatio, Threshold: 2}}}))
+	assert.Nil(t, IsValidRule(&Rule{Resource: "abc", Conditions: []*Condition{{MetricType: QPS, Threshold: 10}}}))
+}
+
+func TestLoadRules(t *testing.T) {
+	defer ClearRules()
+
+	_, err := LoadRules([]*Rule{
+		{Resource: "abc", Conditions: []*Condition{{MetricType: QPS, Threshold: 10}}},
+		{Resource: "abc", Logic: Or, Conditions: []*Condition{{MetricType: AvgRT, Threshold: 10}}},
+		{Resource: "def"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(GetRules()))
+	assert.Equal(t, 2, len(GetRulesOfResource("abc")))
+	assert.True(t, base.ResourceHasRules("abc"))
+	assert.False(t, base.ResourceHasRules("def"))
+}
+
+func TestSlot(t *testing.T) {
+	defer ClearRules()
+
+	_, err := LoadRules([]*Rule{
+		{
+			Resource: "abc",
+			Logic:    And,
+			Conditions: []*Condition{
+				{MetricType: QPS, Threshold: 3},
+				{MetricType: AvgRT, Threshold: 200},
+			},
+		},
+	})
+	assert.Nil(t, err)
+
+	slot := &Slot{}
+	node := stat.NewResourceNode("abc", base.ResTypeCommon)
+	node.AddCount(base.MetricEventPass, 5)
+	node.AddCount(base.MetricEventComplete, 5)
+	node.AddCount(base.MetricEventRt, 500)
+	// high QPS handled comfortably
+	assert.Nil(t, slot.Check(newContext(node)))
+
+	node.AddCount(base.MetricEventRt, 1000)
+	r := slot.Check(newContext(node))
+	assert.True(t, r != nil && r.IsBlocked())
+	assert.Equal(t, base.BlockTypeComposite, r.BlockError().BlockType())
+	assert.Equal(t, []float64{5, 300}, r.BlockError().TriggeredValue())
+
+	_, err = LoadRules([]*Rule{
+		{
+			Resource: "abc",
+			Logic:    Or,
+			Conditions: []*Condition{
+				{MetricType: Concurrency, Threshold: 10},
+				{MetricType: ErrorRatio, Threshold: 0.5},
+			},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, slot.Check(newContext(node)))
+	node.AddCount(base.MetricEventError, 3)
+	r = slot.Check(newContext(node))
+	assert.True(t, r != nil && r.IsBlocked())
+}
@@ -0,0 +1,21 @@
+// Package composite provides the composite rules, which block the entries only when multiple conditions
+// across the metrics of the resource hold together (or any of them holds).
+//
+// For example, high QPS alone does not mean the resource is overloaded if it is handled comfortably,
+// so the following rule blocks the entries only when the QPS exceeds 1000 AND the average RT exceeds 200 ms,
+// which avoids the false-positive blocks of the single-metric rules:
+//
+//	_, err := composite.LoadRules([]*composite.Rule{
+//	    {
+//	        Resource: "some-api",
+//	        Logic:    composite.And,
+//	        Conditions: []*composite.Condition{
+//	            {MetricType: composite.QPS, Threshold: 1000},
+//	            {MetricType: composite.AvgRT, Threshold: 200},
+//	        },
+//	    },
+//	})
+//
+// The conditions are evaluated on the resource-level statistic by the composite checker,
+// and the entries are blocked with BlockTypeComposite when the rule trips.
+package composite
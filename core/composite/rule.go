@@ -0,0 +1,91 @@
+package composite
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Logic indicates how the conditions of the composite rule are combined.
+type Logic int32
+
+const (
+	// And means the rule trips only when all the conditions hold.
+	And Logic = iota
+	// Or means the rule trips when any of the conditions holds.
+	Or
+)
+
+func (l Logic) String() string {
+	switch l {
+	case And:
+		return "And"
+	case Or:
+		return "Or"
+	default:
+		return "Undefined"
+	}
+}
+
+// MetricType is the metric of the resource that the condition checks.
+type MetricType int32
+
+const (
+	// QPS is the passed QPS of the resource.
+	QPS MetricType = iota
+	// AvgRT is the average response time (in milliseconds) of the resource.
+	AvgRT
+	// Concurrency is the current concurrency of the resource.
+	Concurrency
+	// ErrorRatio is the ratio of the error QPS to the completed QPS of the resource.
+	ErrorRatio
+)
+
+func (t MetricType) String() string {
+	switch t {
+	case QPS:
+		return "QPS"
+	case AvgRT:
+		return "AvgRT"
+	case Concurrency:
+		return "Concurrency"
+	case ErrorRatio:
+		return "ErrorRatio"
+	default:
+		return "Undefined"
+	}
+}
+
+// Condition holds when the current value of the metric exceeds the threshold.
+type Condition struct {
+	MetricType MetricType `json:"metricType"`
+	Threshold  float64    `json:"threshold"`
+}
+
+func (c *Condition) String() string {
+	return fmt.Sprintf("%s>%.2f", c.MetricType, c.Threshold)
+}
+
+// Rule describes the composite conditions of a resource.
+type Rule struct {
+	// ID represents the unique ID of the rule (optional).
+	ID string `json:"id,omitempty"`
+	// Resource represents the resource name.
+	Resource string `json:"resource"`
+	// Logic indicates how the conditions are combined, And by default.
+	Logic Logic `json:"logic"`
+	// Conditions are the conditions of the rule, which must not be empty.
+	Conditions []*Condition `json:"conditions"`
+}
+
+func (r *Rule) String() string {
+	b, err := json.Marshal(r)
+	if err != nil {
+		// Return the fallback string
+		return fmt.Sprintf("{Id=%s, Resource=%s, Logic=%s, Conditions=%v}", r.ID, r.Resource, r.Logic, r.Conditions)
+	}
+	return string(b)
+}
+
+func (r *Rule) ResourceName() string {
+	return r.Resource
+}
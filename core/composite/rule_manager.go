@@ -0,0 +1,115 @@
+package composite
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/pkg/errors"
+)
+
+var (
+	ruleMap   = make(map[string][]*Rule)
+	updateMux = new(sync.RWMutex)
+)
+
+// LoadRules loads the given composite rules to the rule manager, while all previous rules will be replaced.
+func LoadRules(rules []*Rule) (bool, error) {
+	defer selfmetric.RecordRuleUpdate("composite", time.Now())
+
+	m := make(map[string][]*Rule)
+	for _, r := range rules {
+		if err := IsValidRule(r); err != nil {
+			logging.Warn("[Composite LoadRules] Ignoring invalid composite rule", "rule", r, "reason", err)
+			continue
+		}
+		m[r.Resource] = append(m[r.Resource], r)
+	}
+	resources := make([]string, 0, len(m))
+	for res := range m {
+		resources = append(resources, res)
+	}
+
+	updateMux.Lock()
+	ruleMap = m
+	updateMux.Unlock()
+	base.SetRuleResourcesOf("composite", resources, false)
+
+	if len(m) == 0 {
+		logging.Info("[CompositeRuleManager] Composite rules were cleared")
+	} else {
+		logging.Info("[CompositeRuleManager] Composite rules were loaded", "rules", m)
+	}
+	return true, nil
+}
+
+// ClearRules clears all the rules in composite module.
+func ClearRules() error {
+	_, err := LoadRules(nil)
+	return err
+}
+
+// GetRules returns all the rules based on copy.
+// It doesn't take effect for composite module if user changes the rule.
+func GetRules() []Rule {
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+
+	ret := make([]Rule, 0, len(ruleMap))
+	for _, rs := range ruleMap {
+		for _, r := range rs {
+			ret = append(ret, *r)
+		}
+	}
+	return ret
+}
+
+// GetRulesOfResource returns the rules of the resource based on copy.
+func GetRulesOfResource(res string) []Rule {
+	rs := getRulesOf(res)
+	ret := make([]Rule, 0, len(rs))
+	for _, r := range rs {
+		ret = append(ret, *r)
+	}
+	return ret
+}
+
+func getRulesOf(res string) []*Rule {
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+
+	return ruleMap[res]
+}
+
+// IsValidRule checks whether the given rule is valid.
+func IsValidRule(r *Rule) error {
+	if r == nil {
+		return errors.New("nil Rule")
+	}
+	if len(r.Resource) == 0 {
+		return errors.New("empty resource")
+	}
+	if r.Logic != And && r.Logic != Or {
+		return errors.New("invalid Logic")
+	}
+	if len(r.Conditions) == 0 {
+		return errors.New("empty Conditions")
+	}
+	for _, c := range r.Conditions {
+		if c == nil {
+			return errors.New("nil Condition")
+		}
+		if c.MetricType < QPS || c.MetricType > ErrorRatio {
+			return errors.New("invalid MetricType of Condition")
+		}
+		if c.Threshold < 0 {
+			return errors.New("negative Threshold of Condition")
+		}
+		if c.MetricType == ErrorRatio && c.Threshold > 1 {
+			return errors.New("Threshold of ErrorRatio Condition must be in [0, 1]")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package composite
+
+import (
+	"github.com/alibaba/sentinel-golang/core/base"
+)
+
+// Slot is the composite checker, which checks the composite rules of the resource.
+type Slot struct {
+}
+
+// RulesIndexed implements base.IndexedRuleCheckSlot, as all the rules are registered to the rule resource index.
+func (s *Slot) RulesIndexed() bool {
+	return true
+}
+
+func (s *Slot) Check(ctx *base.EntryContext) *base.TokenResult {
+	result := ctx.RuleCheckResult
+	if ctx.StatNode == nil {
+		return result
+	}
+	for _, rule := range getRulesOf(ctx.Resource.Name()) {
+		tripped, snapshot := checkRule(rule, ctx.StatNode)
+		if !tripped {
+			continue
+		}
+		if result == nil {
+			result = base.NewTokenResultBlockedWithCause(base.BlockTypeComposite, "composite conditions met", rule, snapshot)
+		} else {
+			result.ResetToBlockedWithCause(base.BlockTypeComposite, "composite conditions met", rule, snapshot)
+		}
+		return result
+	}
+	return result
+}
+
+// checkRule evaluates the conditions of the rule, and returns whether the rule trips
+// and the metric values of the conditions as the snapshot.
+func checkRule(rule *Rule, node base.StatNode) (bool, []float64) {
+	values := make([]float64, len(rule.Conditions))
+	tripped := rule.Logic == And
+	for i, c := range rule.Conditions {
+		values[i] = metricValueOf(c.MetricType, node)
+		held := values[i] > c.Threshold
+		if rule.Logic == And {
+			tripped = tripped && held
+		} else {
+			tripped = tripped || held
+		}
+	}
+	return tripped, values
+}
+
+func metricValueOf(t MetricType, node base.StatNode) float64 {
+	switch t {
+	case QPS:
+		return node.GetQPS(base.MetricEventPass)
+	case AvgRT:
+		return node.AvgRT()
+	case Concurrency:
+		return float64(node.CurrentGoroutineNum())
+	case ErrorRatio:
+		complete := node.GetQPS(base.MetricEventComplete)
+		if complete <= 0 {
+			return 0
+		}
+		return node.GetQPS(base.MetricEventError) / complete
+	default:
+		return 0
+	}
+}
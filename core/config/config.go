@@ -1,9 +1,11 @@
 package config
 
 import (
+	"io"
 	"io/ioutil"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/alibaba/sentinel-golang/logging"
@@ -58,6 +60,9 @@ func OverrideConfigFromEnvAndInitLog() error {
 		}
 		return nil
 	}
+	if output := LogOutput(); output != nil {
+		return logging.ResetGlobalLogger(logging.NewWriterLogger(output))
+	}
 
 	logDir := LogBaseDir()
 	if len(logDir) == 0 {
@@ -107,6 +112,31 @@ func overrideItemsFromSystemEnv() error {
 		}
 	}
 
+	if labelsStr := os.Getenv(AppLabelsEnvKey); !util.IsBlank(labelsStr) {
+		labels := make([]string, 0)
+		for _, l := range strings.Split(labelsStr, ",") {
+			if l = strings.TrimSpace(l); len(l) > 0 {
+				labels = append(labels, l)
+			}
+		}
+		globalCfg.Sentinel.App.Labels = labels
+	}
+
+	if scopeStr := os.Getenv(ScopeLabelsEnvKey); !util.IsBlank(scopeStr) {
+		scope := make(map[string]string)
+		for _, kv := range strings.Split(scopeStr, ",") {
+			if kv = strings.TrimSpace(kv); len(kv) == 0 {
+				continue
+			}
+			pair := strings.SplitN(kv, "=", 2)
+			if len(pair) != 2 || len(strings.TrimSpace(pair[0])) == 0 {
+				return errors.Errorf("invalid scope label %q in %s, expect key=value", kv, ScopeLabelsEnvKey)
+			}
+			scope[strings.TrimSpace(pair[0])] = strings.TrimSpace(pair[1])
+		}
+		globalCfg.Sentinel.Datasource.ScopeLabels = scope
+	}
+
 	if addPidStr := os.Getenv(LogNamePidEnvKey); !util.IsBlank(addPidStr) {
 		addPid, err := strconv.ParseBool(addPidStr)
 		if err != nil {
@@ -119,6 +149,10 @@ func overrideItemsFromSystemEnv() error {
 	if logDir := os.Getenv(LogDirEnvKey); !util.IsBlank(logDir) {
 		globalCfg.Sentinel.Log.Dir = logDir
 	}
+
+	if instanceId := os.Getenv(LogInstanceIdEnvKey); !util.IsBlank(instanceId) {
+		globalCfg.Sentinel.Log.InstanceId = instanceId
+	}
 	return checkConfValid(&(globalCfg.Sentinel))
 }
 
@@ -138,7 +172,7 @@ func initializeLogConfig(logDir string, usePid bool) (err error) {
 
 func reconfigureRecordLogger(logBaseDir string, withPid bool) error {
 	logDir := util.AddPathSeparatorIfAbsent(logBaseDir)
-	filePath := logDir + logging.RecordLogFileName
+	filePath := logDir + LogFileNameOfInstance(logging.RecordLogFileName, LogInstanceId())
 	if withPid {
 		filePath = filePath + ".pid" + strconv.Itoa(os.Getpid())
 	}
@@ -173,14 +207,51 @@ func AppType() int32 {
 	return globalCfg.AppType()
 }
 
+func AppLabels() []string {
+	return globalCfg.AppLabels()
+}
+
+// HasAppLabel checks whether current process has the given deployment label.
+func HasAppLabel(label string) bool {
+	for _, l := range globalCfg.AppLabels() {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
 func Logger() logging.Logger {
 	return globalCfg.Logger()
 }
 
+// LogInstanceId returns the instance ID in the names of the log files.
+func LogInstanceId() string {
+	return globalCfg.LogInstanceId()
+}
+
+// LogOutput returns the writer of the record log, nil if the record log is written to the file.
+func LogOutput() io.Writer {
+	return globalCfg.LogOutput()
+}
+
+// MetricLogOutput returns the writer of the metric log, nil if the metric log is written to the files.
+func MetricLogOutput() io.Writer {
+	return globalCfg.MetricLogOutput()
+}
+
 func LogBaseDir() string {
 	return globalCfg.LogBaseDir()
 }
 
+func RuleDumpIntervalSec() uint32 {
+	return globalCfg.RuleDumpIntervalSec()
+}
+
+func RuleDumpFile() string {
+	return globalCfg.RuleDumpFile()
+}
+
 // LogUsePid returns whether the log file name contains the PID suffix.
 func LogUsePid() bool {
 	return globalCfg.LogUsePid()
@@ -198,14 +269,106 @@ func MetricLogMaxFileAmount() uint32 {
 	return globalCfg.MetricLogMaxFileAmount()
 }
 
+func MetricLogFormat() string {
+	return globalCfg.MetricLogFormat()
+}
+
+func MetricLogRtUnit() string {
+	return globalCfg.MetricLogRtUnit()
+}
+
 func SystemStatCollectIntervalMs() uint32 {
 	return globalCfg.SystemStatCollectIntervalMs()
 }
 
+func CpuUsageSmoothingFactor() float64 {
+	return globalCfg.CpuUsageSmoothingFactor()
+}
+
+func ConcurrencySampleIntervalMs() uint32 {
+	return globalCfg.ConcurrencySampleIntervalMs()
+}
+
+func MetricExportIntervalMs() uint32 {
+	return globalCfg.MetricExportIntervalMs()
+}
+
+func SelfMetricEnabled() bool {
+	return globalCfg.SelfMetricEnabled()
+}
+
+func RuntimeMetricEnabled() bool {
+	return globalCfg.RuntimeMetricEnabled()
+}
+
+func ResourceNodeIdleTtlMs() uint32 {
+	return globalCfg.ResourceNodeIdleTtlMs()
+}
+
+func AsyncEntryLeakTimeoutMs() uint32 {
+	return globalCfg.AsyncEntryLeakTimeoutMs()
+}
+
+func BlockAnomalyDetectIntervalMs() uint32 {
+	return globalCfg.BlockAnomalyDetectIntervalMs()
+}
+
+func MaxResourceAmount() uint32 {
+	return globalCfg.MaxResourceAmount()
+}
+
+func ResourceOverflowStrategy() string {
+	return globalCfg.ResourceOverflowStrategy()
+}
+
+func ShardedCounterEnabled() bool {
+	return globalCfg.ShardedCounterEnabled()
+}
+
+func InitialRulesRequired() bool {
+	return globalCfg.InitialRulesRequired()
+}
+
+func InitialRulesTimeoutMs() uint32 {
+	return globalCfg.InitialRulesTimeoutMs()
+}
+
+func DatasourceScopeLabels() map[string]string {
+	return globalCfg.DatasourceScopeLabels()
+}
+
+func ColdStartSuppressionSec() uint32 {
+	return globalCfg.ColdStartSuppressionSec()
+}
+
+func ScaleWarmUpSec() uint32 {
+	return globalCfg.ScaleWarmUpSec()
+}
+
+func ScaleWarmUpColdFactor() uint32 {
+	return globalCfg.ScaleWarmUpColdFactor()
+}
+
 func UseCacheTime() bool {
 	return globalCfg.UseCacheTime()
 }
 
+func TimeTickerResolutionMs() uint32 {
+	return globalCfg.TimeTickerResolutionMs()
+}
+
+func PreciseRt() bool {
+	return globalCfg.PreciseRt()
+}
+
+func FailurePolicy() string {
+	return globalCfg.FailurePolicy()
+}
+
+func ModuleFailurePolicies() map[string]string {
+	return globalCfg.ModuleFailurePolicies()
+}
+
 func GlobalStatisticIntervalMsTotal() uint32 {
 	return globalCfg.GlobalStatisticIntervalMsTotal()
 }
@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 const testDataBaseDir = "../../tests/testdata/config/"
@@ -58,12 +60,55 @@ func TestOverrideFromSystemEnv(t *testing.T) {
 	_ = os.Setenv(AppTypeEnvKey, "1")
 	_ = os.Setenv(LogDirEnvKey, testDataBaseDir+"sentinel.yml.2")
 	_ = os.Setenv(LogNamePidEnvKey, "true")
+	_ = os.Setenv(AppLabelsEnvKey, "canary, zone-a")
+	_ = os.Setenv(ScopeLabelsEnvKey, "zone=hz, cluster = c1")
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if err := overrideItemsFromSystemEnv(); (err != nil) != tt.wantErr {
 				t.Errorf("overrideItemsFromSystemEnv() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			assert.Equal(t, []string{"canary", "zone-a"}, AppLabels())
+			assert.True(t, HasAppLabel("canary"))
+			assert.False(t, HasAppLabel("stable"))
+			assert.Equal(t, map[string]string{"zone": "hz", "cluster": "c1"}, DatasourceScopeLabels())
 		})
 	}
 }
+
+func TestCheckValid_FailurePolicy(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Sentinel.FailurePolicy = "closed"
+	cfg.Sentinel.ModuleFailurePolicies = map[string]string{"flow": "open"}
+	assert.Nil(t, CheckValid(cfg))
+
+	cfg.Sentinel.ModuleFailurePolicies["circuitbreaker"] = "half"
+	assert.NotNil(t, CheckValid(cfg))
+
+	cfg = NewDefaultConfig()
+	cfg.Sentinel.FailurePolicy = "unknown"
+	assert.NotNil(t, CheckValid(cfg))
+}
+
+func TestCheckValid_ScaleWarmUp(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Sentinel.Flow.ScaleWarmUpSec = 60
+	assert.Nil(t, CheckValid(cfg))
+	cfg.Sentinel.Flow.ScaleWarmUpColdFactor = 1
+	assert.NotNil(t, CheckValid(cfg))
+}
+
+func TestLogFileNameOfInstance(t *testing.T) {
+	assert.Equal(t, "sentinel-record.log", LogFileNameOfInstance("sentinel-record.log", ""))
+	assert.Equal(t, "sentinel-record-i1.log", LogFileNameOfInstance("sentinel-record.log", "i1"))
+	assert.Equal(t, "sentinel-rules-10-0-0-1.log", LogFileNameOfInstance("sentinel-rules.log", "10.0.0.1"))
+
+	cfg := NewDefaultConfig()
+	cfg.Sentinel.Log.Dir = "/tmp/logs"
+	cfg.Sentinel.Log.InstanceId = "i1"
+	assert.Nil(t, CheckValid(cfg))
+	assert.Equal(t, "/tmp/logs/sentinel-rules-i1.log", cfg.RuleDumpFile())
+
+	cfg.Sentinel.Log.InstanceId = "../i1"
+	assert.NotNil(t, CheckValid(cfg))
+}
@@ -8,15 +8,41 @@ const (
 	ConfFilePathEnvKey = "SENTINEL_CONFIG_FILE_PATH"
 	AppNameEnvKey      = "SENTINEL_APP_NAME"
 	AppTypeEnvKey      = "SENTINEL_APP_TYPE"
+	AppLabelsEnvKey    = "SENTINEL_APP_LABELS"
 	LogDirEnvKey       = "SENTINEL_LOG_DIR"
 	LogNamePidEnvKey   = "SENTINEL_LOG_USE_PID"
+	// LogInstanceIdEnvKey represents the instance ID in the names of the log files, see LogConfig.InstanceId.
+	LogInstanceIdEnvKey = "SENTINEL_LOG_INSTANCE_ID"
+	// ScopeLabelsEnvKey represents the scope labels of the rules from datasources, e.g. "zone=hz,cluster=c1".
+	ScopeLabelsEnvKey = "SENTINEL_DATASOURCE_SCOPE_LABELS"
 
-	DefaultConfigFilename       = "sentinel.yml"
-	DefaultAppType        int32 = 0
+	DefaultConfigFilename         = "sentinel.yml"
+	DefaultRuleDumpFilename       = "sentinel-rules.log"
+	DefaultAppType          int32 = 0
 
 	DefaultMetricLogFlushIntervalSec   uint32 = 1
 	DefaultMetricLogSingleFileMaxSize  uint64 = 1024 * 1024 * 50
 	DefaultMetricLogMaxFileAmount      uint32 = 8
 	DefaultSystemStatCollectIntervalMs uint32 = 1000
+	DefaultConcurrencySampleIntervalMs uint32 = 0
+	DefaultMetricExportIntervalMs      uint32 = 1000
 	DefaultWarmUpColdFactor            uint32 = 3
+	DefaultInitialRulesTimeoutMs       uint32 = 10000
+	DefaultTimeTickerResolutionMs      uint32 = 1
+
+	// ResourceOverflowAggregate aggregates the statistics of the resources beyond the max resource amount
+	// into the overflow resource.
+	ResourceOverflowAggregate = "aggregate"
+	// ResourceOverflowReject rejects the entries of the resources beyond the max resource amount.
+	ResourceOverflowReject = "reject"
+
+	// MetricLogFormatText is the positional text format of the metric log, separated by "|".
+	MetricLogFormatText = "text"
+	// MetricLogFormatJSON is the structured format of the metric log, one JSON object per line.
+	MetricLogFormatJSON = "json"
+
+	// MetricLogRtUnitMs records the RT of the metric log in milliseconds.
+	MetricLogRtUnitMs = "ms"
+	// MetricLogRtUnitUs records the RT of the metric log in microseconds as well.
+	MetricLogRtUnitUs = "us"
 )
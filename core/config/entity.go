@@ -3,6 +3,9 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
 
 	"github.com/alibaba/sentinel-golang/core/base"
 	"github.com/alibaba/sentinel-golang/logging"
@@ -23,25 +26,56 @@ type SentinelConfig struct {
 		Name string
 		// Type indicates the classification of the service (e.g. web service, API gateway).
 		Type int32
+		// Labels represents the deployment labels of current process (e.g. canary),
+		// rules with a deployment label take effect only if the label is present.
+		Labels []string
 	}
 	// Log represents configuration items related to logging.
 	Log LogConfig
 	// Stat represents configuration items related to statistics.
 	Stat StatConfig
+	// Datasource represents configuration items related to the rule datasources.
+	Datasource DatasourceConfig
+	// CircuitBreaker represents configuration items related to circuit breaking.
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuitBreaker"`
+	// Flow represents configuration items related to flow control.
+	Flow FlowConfig `yaml:"flow"`
 	// UseCacheTime indicates whether to cache time(ms)
 	UseCacheTime bool `yaml:"useCacheTime"`
+	// TimeTickerResolutionMs is the interval (in ms) of refreshing the cached time if UseCacheTime is true.
+	// The coarser resolution saves more time syscalls on the hot paths, at the cost of less accurate time.
+	TimeTickerResolutionMs uint32 `yaml:"timeTickerResolutionMs"`
+	// PreciseRt indicates whether to measure the response time by the precise time rather than the cached time,
+	// which is recommended if TimeTickerResolutionMs is coarse.
+	PreciseRt bool `yaml:"preciseRt"`
+	// FailurePolicy represents the behavior when Sentinel itself errors (e.g. a slot panics),
+	// either "open" (by default, the traffic passes) or "closed" (the traffic is blocked).
+	FailurePolicy string `yaml:"failurePolicy"`
+	// ModuleFailurePolicies overrides FailurePolicy of the modules, e.g. {"circuitbreaker": "closed"}.
+	ModuleFailurePolicies map[string]string `yaml:"moduleFailurePolicies"`
 }
 
 // LogConfig represent the configuration of logging in Sentinel.
 type LogConfig struct {
 	// Logger indicates that using logger to replace default logging.
 	Logger logging.Logger
+	// Output directs the record log to the writer instead of the record log file, while Logger takes precedence.
+	Output io.Writer `yaml:"-"`
 	// Dir represents the log directory path.
 	Dir string
 	// UsePid indicates whether the filename ends with the process ID (PID).
 	UsePid bool `yaml:"usePid"`
+	// InstanceId is appended to the names of the log files (the record log, the metric log and the rule dump),
+	// so that the instances sharing the log directory (e.g. the apps or the test binaries in the same host)
+	// don't write to the same files.
+	InstanceId string `yaml:"instanceId"`
 	// Metric represents the configuration items of the metric log.
 	Metric MetricLogConfig
+	// RuleDumpIntervalSec represents the interval of dumping the effective rules with their version hashes
+	// to the rule dump file (see audit.StartRuleDump). 0 means the rule dump is disabled.
+	RuleDumpIntervalSec uint32 `yaml:"ruleDumpIntervalSec"`
+	// RuleDumpFile represents the path of the rule dump file, DefaultRuleDumpFilename in Dir by default.
+	RuleDumpFile string `yaml:"ruleDumpFile"`
 }
 
 // MetricLogConfig represents the configuration items of the metric log.
@@ -49,6 +83,17 @@ type MetricLogConfig struct {
 	SingleFileMaxSize uint64 `yaml:"singleFileMaxSize"`
 	MaxFileCount      uint32 `yaml:"maxFileCount"`
 	FlushIntervalSec  uint32 `yaml:"flushIntervalSec"`
+	// Format is the format of the metric log lines, either MetricLogFormatText (the positional text format,
+	// by default) or MetricLogFormatJSON (one JSON object per line, with the RT histogram summary).
+	Format string `yaml:"format"`
+	// RtUnit is the finest unit of the RT in the metric log, either MetricLogRtUnitMs (by default) or
+	// MetricLogRtUnitUs. The average RT in ms is always kept for backward compatibility, while the average RT
+	// in microseconds is appended as the last part of the text format lines with MetricLogRtUnitUs.
+	// The JSON format always contains both.
+	RtUnit string `yaml:"rtUnit"`
+	// Output directs the metric log lines to the writer instead of the metric log files. The lines are neither
+	// indexed nor rolled, so they are not searchable by the MetricSearcher.
+	Output io.Writer `yaml:"-"`
 }
 
 // StatConfig represents the configuration items of statistics.
@@ -62,13 +107,91 @@ type StatConfig struct {
 	MetricStatisticSampleCount uint32 `yaml:"metricStatisticSampleCount"`
 	MetricStatisticIntervalMs  uint32 `yaml:"metricStatisticIntervalMs"`
 
+	// ConcurrencySampleIntervalMs represents the interval of sampling the concurrency of resources
+	// and exporting the gauges. 0 means the sampler is disabled.
+	ConcurrencySampleIntervalMs uint32 `yaml:"concurrencySampleIntervalMs"`
+
+	// ShardedCounterEnabled indicates whether to spread the statistic writes over per-P sharded counters,
+	// which reduces the cache-line contention at very high QPS, at the cost of more memory and slower reads.
+	ShardedCounterEnabled bool `yaml:"shardedCounterEnabled"`
+
+	// MetricExportIntervalMs represents the interval of aggregating the metrics and delivering them
+	// to the registered metric exporters (see package exporter). 0 means the export task is disabled.
+	// It must not be greater than GlobalStatisticIntervalMsTotal, otherwise the metrics are lost.
+	MetricExportIntervalMs uint32 `yaml:"metricExportIntervalMs"`
+
+	// SelfMetricEnabled indicates whether to record the overhead of Sentinel itself (e.g. the latency of each slot),
+	// see package selfmetric. It costs a few time syscalls per entry, so it's disabled by default.
+	SelfMetricEnabled bool `yaml:"selfMetricEnabled"`
+
+	// RuntimeMetricEnabled indicates whether to append the sampled Go runtime metrics (goroutines, heap, GC count)
+	// to the metric logs and the exported metrics, see stat.RuntimeMetricItems.
+	RuntimeMetricEnabled bool `yaml:"runtimeMetricEnabled"`
+
+	// ResourceNodeIdleTtlMs represents the TTL of the idle resource nodes, the nodes of the resources without
+	// any traffic beyond the TTL are evicted (except for the resources with rules). 0 means never evicting.
+	ResourceNodeIdleTtlMs uint32 `yaml:"resourceNodeIdleTtlMs"`
+
+	// MaxResourceAmount represents the cap of the distinct resources with statistics, 0 means unlimited.
+	MaxResourceAmount uint32 `yaml:"maxResourceAmount"`
+	// ResourceOverflowStrategy represents the strategy of the resources beyond MaxResourceAmount,
+	// either ResourceOverflowAggregate (by default) or ResourceOverflowReject.
+	ResourceOverflowStrategy string `yaml:"resourceOverflowStrategy"`
+
+	// AsyncEntryLeakTimeoutMs represents the timeout of the async entries (see SentinelEntry.ExitAsync),
+	// the async entries not exited within the timeout are regarded as leaked, which are logged and exited
+	// with an error. 0 means the leak detection is disabled.
+	AsyncEntryLeakTimeoutMs uint32 `yaml:"asyncEntryLeakTimeoutMs"`
+
+	// BlockAnomalyDetectIntervalMs represents the interval of detecting the block ratio anomalies of the resources
+	// (see package anomaly). 0 means the detector is disabled.
+	BlockAnomalyDetectIntervalMs uint32 `yaml:"blockAnomalyDetectIntervalMs"`
+
 	System SystemStatConfig `yaml:"system"`
 }
 
+// DatasourceConfig represents the configuration items of the rule datasources.
+type DatasourceConfig struct {
+	// InitialRulesRequired indicates whether the initialization (see api.InitAndWait) fails if the datasources
+	// have not delivered their first successful rule payload within InitialRulesTimeoutMs,
+	// which prevents serving unprotected after deploys. Otherwise the initialization proceeds with a warning.
+	InitialRulesRequired bool `yaml:"initialRulesRequired"`
+	// InitialRulesTimeoutMs represents the max time of waiting for the initial rules.
+	InitialRulesTimeoutMs uint32 `yaml:"initialRulesTimeoutMs"`
+	// ScopeLabels represents the scope labels of current process (e.g. cluster and zone). One datasource payload
+	// may hold the rules of several clusters or zones, the rule with a "scope" object in the payload takes effect
+	// only if all the labels in the scope equal to the ones here.
+	ScopeLabels map[string]string `yaml:"scopeLabels"`
+}
+
+// CircuitBreakerConfig represents the configuration items of circuit breaking.
+type CircuitBreakerConfig struct {
+	// ColdStartSuppressionSec represents the duration (in seconds) after the process starts, during which
+	// the circuit breakers neither break nor record the completed requests, as the startup noise
+	// (e.g. cold caches) routinely trips the breakers right after deploys. Flow control is not affected.
+	// 0 means no suppression.
+	ColdStartSuppressionSec uint32 `yaml:"coldStartSuppressionSec"`
+}
+
+// FlowConfig represents the configuration items of flow control.
+type FlowConfig struct {
+	// ScaleWarmUpSec represents the period (in seconds) of the scale warm-up on the fresh instances: if the process
+	// is initialized within the period after it starts (e.g. an autoscaled pod), all the resources with flow rules
+	// ramp their thresholds up gradually until the end of the period (see flow.WarmUpFreshInstance).
+	// 0 means no scale warm-up.
+	ScaleWarmUpSec uint32 `yaml:"scaleWarmUpSec"`
+	// ScaleWarmUpColdFactor represents the cold factor of the scale warm-up, i.e. the thresholds start from
+	// 1/ScaleWarmUpColdFactor of the thresholds. 0 means the default warm-up cold factor.
+	ScaleWarmUpColdFactor uint32 `yaml:"scaleWarmUpColdFactor"`
+}
+
 // SystemStatConfig represents the configuration items of system statistics.
 type SystemStatConfig struct {
 	// CollectIntervalMs represents the collecting interval of the system metrics collector.
 	CollectIntervalMs uint32 `yaml:"collectIntervalMs"`
+	// CpuUsageSmoothingFactor represents the EWMA smoothing factor of the CPU usage, valid range is [0.0, 1.0).
+	// The larger the factor is, the smoother the CPU usage is. 0 means no smoothing.
+	CpuUsageSmoothingFactor float64 `yaml:"cpuUsageSmoothingFactor"`
 }
 
 // NewDefaultConfig creates a new default config entity.
@@ -77,8 +200,9 @@ func NewDefaultConfig() *Entity {
 		Version: "v1",
 		Sentinel: SentinelConfig{
 			App: struct {
-				Name string
-				Type int32
+				Name   string
+				Type   int32
+				Labels []string
 			}{
 				Name: UnknownProjectName,
 				Type: DefaultAppType,
@@ -91,6 +215,7 @@ func NewDefaultConfig() *Entity {
 					SingleFileMaxSize: DefaultMetricLogSingleFileMaxSize,
 					MaxFileCount:      DefaultMetricLogMaxFileAmount,
 					FlushIntervalSec:  DefaultMetricLogFlushIntervalSec,
+					Format:            MetricLogFormatText,
 				},
 			},
 			Stat: StatConfig{
@@ -98,11 +223,17 @@ func NewDefaultConfig() *Entity {
 				GlobalStatisticIntervalMsTotal:  base.DefaultIntervalMsTotal,
 				MetricStatisticSampleCount:      base.DefaultSampleCount,
 				MetricStatisticIntervalMs:       base.DefaultIntervalMs,
+				ConcurrencySampleIntervalMs:     DefaultConcurrencySampleIntervalMs,
+				MetricExportIntervalMs:          DefaultMetricExportIntervalMs,
 				System: SystemStatConfig{
 					CollectIntervalMs: DefaultSystemStatCollectIntervalMs,
 				},
 			},
-			UseCacheTime: true,
+			Datasource: DatasourceConfig{
+				InitialRulesTimeoutMs: DefaultInitialRulesTimeoutMs,
+			},
+			UseCacheTime:           true,
+			TimeTickerResolutionMs: DefaultTimeTickerResolutionMs,
 		},
 	}
 }
@@ -124,6 +255,19 @@ func checkConfValid(conf *SentinelConfig) error {
 	if conf.App.Name == "" {
 		return errors.New("App.Name is empty")
 	}
+	if p := conf.FailurePolicy; p != "" {
+		if _, err := base.ParseFailurePolicy(p); err != nil {
+			return errors.Wrap(err, "Illegal globalCfg: failurePolicy")
+		}
+	}
+	for module, p := range conf.ModuleFailurePolicies {
+		if _, err := base.ParseFailurePolicy(p); err != nil {
+			return errors.Wrapf(err, "Illegal globalCfg: failure policy of module %s", module)
+		}
+	}
+	if strings.ContainsAny(conf.Log.InstanceId, `/\`) {
+		return errors.Errorf("Illegal log globalCfg: instanceId %s contains the path separator", conf.Log.InstanceId)
+	}
 	mc := conf.Log.Metric
 	if mc.MaxFileCount <= 0 {
 		return errors.New("Illegal metric log globalCfg: maxFileCount <= 0")
@@ -131,10 +275,31 @@ func checkConfValid(conf *SentinelConfig) error {
 	if mc.SingleFileMaxSize <= 0 {
 		return errors.New("Illegal metric log globalCfg: singleFileMaxSize <= 0")
 	}
+	if mc.Format != "" && mc.Format != MetricLogFormatText && mc.Format != MetricLogFormatJSON {
+		return errors.Errorf("Illegal metric log globalCfg: unknown format %s", mc.Format)
+	}
+	if mc.RtUnit != "" && mc.RtUnit != MetricLogRtUnitMs && mc.RtUnit != MetricLogRtUnitUs {
+		return errors.Errorf("Illegal metric log globalCfg: unknown rtUnit %s", mc.RtUnit)
+	}
 	if err := base.CheckValidityForReuseStatistic(conf.Stat.MetricStatisticSampleCount, conf.Stat.MetricStatisticIntervalMs,
 		conf.Stat.GlobalStatisticSampleCountTotal, conf.Stat.GlobalStatisticIntervalMsTotal); err != nil {
 		return err
 	}
+	if conf.Stat.MetricExportIntervalMs > conf.Stat.GlobalStatisticIntervalMsTotal {
+		return errors.New("Illegal stat globalCfg: metricExportIntervalMs > globalStatisticIntervalMsTotal")
+	}
+	if s := conf.Stat.ResourceOverflowStrategy; s != "" && s != ResourceOverflowAggregate && s != ResourceOverflowReject {
+		return errors.Errorf("Illegal stat globalCfg: unknown resourceOverflowStrategy %s", s)
+	}
+	if ttl := conf.Stat.ResourceNodeIdleTtlMs; ttl > 0 && ttl < conf.Stat.GlobalStatisticIntervalMsTotal {
+		return errors.New("Illegal stat globalCfg: resourceNodeIdleTtlMs < globalStatisticIntervalMsTotal")
+	}
+	if f := conf.Stat.System.CpuUsageSmoothingFactor; f < 0 || f >= 1 {
+		return errors.New("Illegal system stat globalCfg: cpuUsageSmoothingFactor out of range [0.0, 1.0)")
+	}
+	if conf.Flow.ScaleWarmUpColdFactor == 1 {
+		return errors.New("Illegal flow globalCfg: scaleWarmUpColdFactor must be greater than 1")
+	}
 	return nil
 }
 
@@ -154,10 +319,38 @@ func (entity *Entity) AppType() int32 {
 	return entity.Sentinel.App.Type
 }
 
+func (entity *Entity) AppLabels() []string {
+	return entity.Sentinel.App.Labels
+}
+
 func (entity *Entity) LogBaseDir() string {
 	return entity.Sentinel.Log.Dir
 }
 
+func (entity *Entity) RuleDumpIntervalSec() uint32 {
+	return entity.Sentinel.Log.RuleDumpIntervalSec
+}
+
+func (entity *Entity) RuleDumpFile() string {
+	if len(entity.Sentinel.Log.RuleDumpFile) > 0 {
+		return entity.Sentinel.Log.RuleDumpFile
+	}
+	return filepath.Join(entity.LogBaseDir(), LogFileNameOfInstance(DefaultRuleDumpFilename, entity.LogInstanceId()))
+}
+
+// LogInstanceId returns the instance ID in the names of the log files.
+func (entity *Entity) LogInstanceId() string {
+	return entity.Sentinel.Log.InstanceId
+}
+
+func (entity *Entity) LogOutput() io.Writer {
+	return entity.Sentinel.Log.Output
+}
+
+func (entity *Entity) MetricLogOutput() io.Writer {
+	return entity.Sentinel.Log.Metric.Output
+}
+
 func (entity *Entity) Logger() logging.Logger {
 	return entity.Sentinel.Log.Logger
 }
@@ -179,14 +372,113 @@ func (entity *Entity) MetricLogMaxFileAmount() uint32 {
 	return entity.Sentinel.Log.Metric.MaxFileCount
 }
 
+// MetricLogFormat returns the format of the metric log, MetricLogFormatText if absent.
+func (entity *Entity) MetricLogFormat() string {
+	if len(entity.Sentinel.Log.Metric.Format) == 0 {
+		return MetricLogFormatText
+	}
+	return entity.Sentinel.Log.Metric.Format
+}
+
+func (entity *Entity) MetricLogRtUnit() string {
+	if len(entity.Sentinel.Log.Metric.RtUnit) == 0 {
+		return MetricLogRtUnitMs
+	}
+	return entity.Sentinel.Log.Metric.RtUnit
+}
+
 func (entity *Entity) SystemStatCollectIntervalMs() uint32 {
 	return entity.Sentinel.Stat.System.CollectIntervalMs
 }
 
+func (entity *Entity) CpuUsageSmoothingFactor() float64 {
+	return entity.Sentinel.Stat.System.CpuUsageSmoothingFactor
+}
+
+func (entity *Entity) ConcurrencySampleIntervalMs() uint32 {
+	return entity.Sentinel.Stat.ConcurrencySampleIntervalMs
+}
+
+func (entity *Entity) MetricExportIntervalMs() uint32 {
+	return entity.Sentinel.Stat.MetricExportIntervalMs
+}
+
+func (entity *Entity) SelfMetricEnabled() bool {
+	return entity.Sentinel.Stat.SelfMetricEnabled
+}
+
+func (entity *Entity) RuntimeMetricEnabled() bool {
+	return entity.Sentinel.Stat.RuntimeMetricEnabled
+}
+
+func (entity *Entity) ResourceNodeIdleTtlMs() uint32 {
+	return entity.Sentinel.Stat.ResourceNodeIdleTtlMs
+}
+
+func (entity *Entity) AsyncEntryLeakTimeoutMs() uint32 {
+	return entity.Sentinel.Stat.AsyncEntryLeakTimeoutMs
+}
+
+func (entity *Entity) BlockAnomalyDetectIntervalMs() uint32 {
+	return entity.Sentinel.Stat.BlockAnomalyDetectIntervalMs
+}
+
+func (entity *Entity) MaxResourceAmount() uint32 {
+	return entity.Sentinel.Stat.MaxResourceAmount
+}
+
+func (entity *Entity) ResourceOverflowStrategy() string {
+	return entity.Sentinel.Stat.ResourceOverflowStrategy
+}
+
+func (entity *Entity) ShardedCounterEnabled() bool {
+	return entity.Sentinel.Stat.ShardedCounterEnabled
+}
+
+func (entity *Entity) InitialRulesRequired() bool {
+	return entity.Sentinel.Datasource.InitialRulesRequired
+}
+
+func (entity *Entity) InitialRulesTimeoutMs() uint32 {
+	return entity.Sentinel.Datasource.InitialRulesTimeoutMs
+}
+
+func (entity *Entity) DatasourceScopeLabels() map[string]string {
+	return entity.Sentinel.Datasource.ScopeLabels
+}
+
+func (entity *Entity) ColdStartSuppressionSec() uint32 {
+	return entity.Sentinel.CircuitBreaker.ColdStartSuppressionSec
+}
+
+func (entity *Entity) ScaleWarmUpSec() uint32 {
+	return entity.Sentinel.Flow.ScaleWarmUpSec
+}
+
+func (entity *Entity) ScaleWarmUpColdFactor() uint32 {
+	return entity.Sentinel.Flow.ScaleWarmUpColdFactor
+}
+
 func (entity *Entity) UseCacheTime() bool {
 	return entity.Sentinel.UseCacheTime
 }
 
+func (entity *Entity) TimeTickerResolutionMs() uint32 {
+	return entity.Sentinel.TimeTickerResolutionMs
+}
+
+func (entity *Entity) PreciseRt() bool {
+	return entity.Sentinel.PreciseRt
+}
+
+func (entity *Entity) FailurePolicy() string {
+	return entity.Sentinel.FailurePolicy
+}
+
+func (entity *Entity) ModuleFailurePolicies() map[string]string {
+	return entity.Sentinel.ModuleFailurePolicies
+}
+
 func (entity *Entity) GlobalStatisticIntervalMsTotal() uint32 {
 	return entity.Sentinel.Stat.GlobalStatisticIntervalMsTotal
 }
@@ -201,3 +493,15 @@ func (entity *Entity) MetricStatisticIntervalMs() uint32 {
 func (entity *Entity) MetricStatisticSampleCount() uint32 {
 	return entity.Sentinel.Stat.MetricStatisticSampleCount
 }
+
+// LogFileNameOfInstance inserts the instance ID into the log file name before the extension,
+// e.g. "sentinel-record.log" of the instance "i1" is "sentinel-record-i1.log".
+// The dots in the instance ID are replaced by "-" so that the dot-separated parts of the file names are kept.
+func LogFileNameOfInstance(filename, instanceId string) string {
+	if len(instanceId) == 0 {
+		return filename
+	}
+	instanceId = strings.ReplaceAll(instanceId, ".", "-")
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + "-" + instanceId + ext
+}
@@ -0,0 +1,83 @@
+package deadline
+
+import (
+	"context"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+// DeadlineAttachmentKey is the key of the entry attachment that carries the deadline (the timestamp in ms,
+// see util.CurrentTimeMillis) of the latency budget of the request.
+const DeadlineAttachmentKey = "sentinel.deadline"
+
+type deadlineKey struct{}
+
+// WithBudget returns the context carrying the latency budget from now on. The earlier budget
+// of the parent context is kept, so that the nested budgets never extend the total budget.
+func WithBudget(ctx context.Context, budget time.Duration) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if budget < 0 {
+		budget = 0
+	}
+	deadline := util.CurrentTimeMillis() + uint64(budget/time.Millisecond)
+	if parent, ok := ctx.Value(deadlineKey{}).(uint64); ok && parent <= deadline {
+		return ctx
+	}
+	return context.WithValue(ctx, deadlineKey{}, deadline)
+}
+
+// DeadlineOf returns the deadline (the timestamp in ms) of the context, which is the earlier one of
+// the latency budget and the deadline of the context itself. It returns false if the context has neither.
+func DeadlineOf(ctx context.Context) (uint64, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	deadline, ok := ctx.Value(deadlineKey{}).(uint64)
+	if d, has := ctx.Deadline(); has {
+		untilMs := time.Until(d) / time.Millisecond
+		ctxDeadline := uint64(0)
+		if now := util.CurrentTimeMillis(); untilMs > 0 {
+			ctxDeadline = now + uint64(untilMs)
+		} else if now > uint64(-untilMs) {
+			ctxDeadline = now - uint64(-untilMs)
+		}
+		if !ok || ctxDeadline < deadline {
+			deadline = ctxDeadline
+		}
+		ok = true
+	}
+	return deadline, ok
+}
+
+// Remaining returns the remaining latency budget of the context, which is not positive if exhausted.
+// It returns false if the context has no budget.
+func Remaining(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := DeadlineOf(ctx)
+	if !ok {
+		return 0, false
+	}
+	return remainingOf(deadline, util.CurrentTimeMillis()), true
+}
+
+// Timeout shrinks the timeout to the remaining latency budget of the context, and the timeout is returned
+// as it is if the context has no budget. The shrunk timeout is never negative.
+func Timeout(ctx context.Context, timeout time.Duration) time.Duration {
+	remaining, ok := Remaining(ctx)
+	if !ok || remaining >= timeout {
+		return timeout
+	}
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+func remainingOf(deadline, now uint64) time.Duration {
+	if deadline >= now {
+		return time.Duration(deadline-now) * time.Millisecond
+	}
+	return -time.Duration(now-deadline) * time.Millisecond
+}
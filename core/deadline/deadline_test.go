@@ -0,0 +1,124 @@
+package deadline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func newContext(resource string, deadline uint64) *base.EntryContext {
+	ctx := &base.EntryContext{
+		Resource: base.NewResourceWrapper(resource, base.ResTypeCommon, base.Outbound),
+		Input: &base.SentinelInput{
+			AcquireCount: 1,
+			Attachments:  map[interface{}]interface{}{DeadlineAttachmentKey: deadline},
+		},
+	}
+	return ctx
+}
+
+func TestWithBudget(t *testing.T) {
+	_, ok := Remaining(context.Background())
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, Timeout(context.Background(), time.Second))
+
+	ctx := WithBudget(context.Background(), 200*time.Millisecond)
+	remaining, ok := Remaining(ctx)
+	assert.True(t, ok)
+	assert.True(t, remaining > 150*time.Millisecond && remaining <= 200*time.Millisecond)
+	assert.True(t, Timeout(ctx, time.Second) <= 200*time.Millisecond)
+	assert.Equal(t, 10*time.Millisecond, Timeout(ctx, 10*time.Millisecond))
+
+	// the nested budget never extends the parent budget
+	nested := WithBudget(ctx, time.Minute)
+	d1, _ := DeadlineOf(ctx)
+	d2, _ := DeadlineOf(nested)
+	assert.Equal(t, d1, d2)
+	nested = WithBudget(ctx, 50*time.Millisecond)
+	remaining, _ = Remaining(nested)
+	assert.True(t, remaining <= 50*time.Millisecond)
+
+	// the earlier deadline of the context takes effect
+	timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	remaining, _ = Remaining(timeoutCtx)
+	assert.True(t, remaining <= 20*time.Millisecond)
+
+	exhausted := WithBudget(context.Background(), 0)
+	remaining, ok = Remaining(exhausted)
+	assert.True(t, ok)
+	assert.True(t, remaining <= 0)
+	assert.Equal(t, time.Duration(0), Timeout(exhausted, time.Second))
+}
+
+func TestIsValidRule(t *testing.T) {
+	assert.NotNil(t, IsValidRule(nil))
+	assert.NotNil(t, IsValidRule(&Rule{MinBudgetMs: 10}))
+	assert.Nil(t, IsValidRule(&Rule{Resource: "abc"}))
+	assert.Nil(t, IsValidRule(&Rule{Resource: "abc", MinBudgetMs: 10, UseAvgRt: true}))
+}
+
+func TestLoadRules(t *testing.T) {
+	defer ClearRules()
+
+	_, err := LoadRules([]*Rule{
+		{Resource: "abc", MinBudgetMs: 10},
+		{Resource: "abc", MinBudgetMs: 20},
+		{MinBudgetMs: 10},
+	})
+	assert.Nil(t, err)
+	rules := GetRules()
+	assert.Equal(t, 1, len(rules))
+	assert.Equal(t, uint64(10), rules[0].MinBudgetMs)
+	assert.True(t, base.ResourceHasExplicitRules("abc"))
+
+	assert.Nil(t, ClearRules())
+	assert.Equal(t, 0, len(GetRules()))
+	assert.False(t, base.ResourceHasExplicitRules("abc"))
+}
+
+func TestSlot(t *testing.T) {
+	defer ClearRules()
+
+	_, err := LoadRules([]*Rule{
+		{Resource: "abc", MinBudgetMs: 50},
+		{Resource: "def", UseAvgRt: true},
+	})
+	assert.Nil(t, err)
+
+	slot := &Slot{}
+	now := util.CurrentTimeMillis()
+	// no budget carried
+	ctx := newContext("abc", now)
+	ctx.Input.Attachments = nil
+	assert.Nil(t, slot.Check(ctx))
+	// no rules of the resource
+	assert.Nil(t, slot.Check(newContext("ghi", now)))
+
+	assert.Nil(t, slot.Check(newContext("abc", now+200)))
+	r := slot.Check(newContext("abc", now+20))
+	assert.True(t, r != nil && r.IsBlocked())
+	assert.Equal(t, base.BlockTypeDeadline, r.BlockError().BlockType())
+	assert.Equal(t, "abc", r.BlockError().TriggeredRule().ResourceName())
+	// exhausted
+	r = slot.Check(newContext("abc", now-10))
+	assert.True(t, r != nil && r.IsBlocked())
+
+	node := stat.NewResourceNode("def", base.ResTypeCommon)
+	ctx = newContext("def", now+30)
+	ctx.StatNode = node
+	// no completed request yet
+	assert.Nil(t, slot.Check(ctx))
+	node.AddCount(base.MetricEventRt, 100)
+	node.AddCount(base.MetricEventComplete, 1)
+	r = slot.Check(ctx)
+	assert.True(t, r != nil && r.IsBlocked())
+	ctx = newContext("def", now+500)
+	ctx.StatNode = node
+	assert.Nil(t, slot.Check(ctx))
+}
@@ -0,0 +1,22 @@
+// Package deadline implements the latency budget propagation, i.e. the deadline propagation inside the Sentinel
+// layer: the inbound request registers its total latency budget in the context, and the nested outbound entries
+// carrying the context are checked against the remaining budget, so that the calls which can't complete in time
+// are rejected (with BlockTypeDeadline) rather than wasting the downstream capacity, and the timeouts of
+// the passed calls could be shrunk to the remaining budget.
+//
+// The budget is checked for the resources with deadline rules: the entry is rejected if the budget has
+// been exhausted, or if the remaining budget is less than MinBudgetMs of the rule, or less than the average
+// RT of the resource if UseAvgRt is set.
+// The deadline of the context (e.g. by context.WithTimeout) is honored as well, the earlier one takes effect.
+//
+//	deadline.LoadRules([]*deadline.Rule{{Resource: "query-stock", MinBudgetMs: 20}})
+//	...
+//	ctx = sentinel.WithLatencyBudget(ctx, 300*time.Millisecond)
+//	...
+//	e, b := sentinel.Entry("query-stock", sentinel.WithBudgetOf(ctx))
+//	if b != nil {
+//	    // Blocked, e.g. the remaining budget is insufficient.
+//	}
+//	callCtx, cancel := context.WithTimeout(ctx, deadline.Timeout(ctx, time.Second))
+//	defer cancel()
+package deadline
@@ -0,0 +1,33 @@
+package deadline
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Rule describes the latency budget required by the calls of a resource. The calls of the resource
+// are rejected if the latency budget they carry has been exhausted, or is less than the required.
+type Rule struct {
+	// ID represents the unique ID of the rule (optional).
+	ID string `json:"id,omitempty"`
+	// Resource represents the resource name.
+	Resource string `json:"resource"`
+	// MinBudgetMs is the minimum remaining budget (in milliseconds) to make the call, i.e. the time
+	// the call needs at least. The call is rejected if the remaining budget is less than it.
+	MinBudgetMs uint64 `json:"minBudgetMs"`
+	// UseAvgRt indicates that the remaining budget must cover the average RT of the resource as well.
+	UseAvgRt bool `json:"useAvgRt"`
+}
+
+func (r *Rule) String() string {
+	b, err := json.Marshal(r)
+	if err != nil {
+		// Return the fallback string
+		return fmt.Sprintf("{Id=%s, Resource=%s, MinBudgetMs=%d, UseAvgRt=%t}", r.ID, r.Resource, r.MinBudgetMs, r.UseAvgRt)
+	}
+	return string(b)
+}
+
+func (r *Rule) ResourceName() string {
+	return r.Resource
+}
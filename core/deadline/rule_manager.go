@@ -0,0 +1,88 @@
+package deadline
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/pkg/errors"
+)
+
+var (
+	ruleMap   = make(map[string]*Rule)
+	updateMux = new(sync.RWMutex)
+)
+
+// LoadRules loads the given deadline rules to the rule manager, while all previous rules will be replaced.
+// Only one rule takes effect for each resource, the latter one of the same resource is ignored.
+func LoadRules(rules []*Rule) (bool, error) {
+	defer selfmetric.RecordRuleUpdate("deadline", time.Now())
+
+	m := make(map[string]*Rule)
+	for _, r := range rules {
+		if err := IsValidRule(r); err != nil {
+			logging.Warn("[Deadline LoadRules] Ignoring invalid deadline rule", "rule", r, "reason", err)
+			continue
+		}
+		if _, exist := m[r.Resource]; exist {
+			logging.Warn("[Deadline LoadRules] Ignoring duplicate deadline rule of the resource", "rule", r)
+			continue
+		}
+		m[r.Resource] = r
+	}
+	resources := make([]string, 0, len(m))
+	for res := range m {
+		resources = append(resources, res)
+	}
+
+	updateMux.Lock()
+	ruleMap = m
+	updateMux.Unlock()
+	base.SetRuleResourcesOf("deadline", resources, false)
+
+	if len(m) == 0 {
+		logging.Info("[DeadlineRuleManager] Deadline rules were cleared")
+	} else {
+		logging.Info("[DeadlineRuleManager] Deadline rules were loaded", "rules", m)
+	}
+	return true, nil
+}
+
+// ClearRules clears all the rules in deadline module.
+func ClearRules() error {
+	_, err := LoadRules(nil)
+	return err
+}
+
+// GetRules returns all the rules based on copy.
+// It doesn't take effect for deadline module if user changes the rule.
+func GetRules() []Rule {
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+
+	ret := make([]Rule, 0, len(ruleMap))
+	for _, r := range ruleMap {
+		ret = append(ret, *r)
+	}
+	return ret
+}
+
+func getRuleOf(res string) *Rule {
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+
+	return ruleMap[res]
+}
+
+// IsValidRule checks whether the given rule is valid.
+func IsValidRule(r *Rule) error {
+	if r == nil {
+		return errors.New("nil Rule")
+	}
+	if len(r.Resource) == 0 {
+		return errors.New("empty resource")
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package deadline
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+// Slot rejects the entries of the resources with deadline rules whose remaining latency budget
+// (carried by the attachment DeadlineAttachmentKey) is insufficient. The entries without the budget
+// are never checked.
+type Slot struct {
+}
+
+func (s *Slot) RulesIndexed() bool {
+	return true
+}
+
+func (s *Slot) Check(ctx *base.EntryContext) *base.TokenResult {
+	result := ctx.RuleCheckResult
+	if ctx.Input == nil || ctx.Input.Attachments == nil {
+		return result
+	}
+	deadline, ok := ctx.Input.Attachments[DeadlineAttachmentKey].(uint64)
+	if !ok {
+		return result
+	}
+	rule := getRuleOf(ctx.Resource.Name())
+	if rule == nil {
+		return result
+	}
+	remaining := remainingOf(deadline, util.CurrentTimeMillis())
+	required := time.Duration(rule.MinBudgetMs) * time.Millisecond
+	if rule.UseAvgRt && ctx.StatNode != nil {
+		// the average RT is NaN if there is no completed request
+		if avgRt := ctx.StatNode.AvgRT(); avgRt > 0 && time.Duration(avgRt*float64(time.Millisecond)) > required {
+			required = time.Duration(avgRt * float64(time.Millisecond))
+		}
+	}
+	if remaining > 0 && remaining >= required {
+		return result
+	}
+	msg := fmt.Sprintf("insufficient latency budget, remaining: %v, required: %v", remaining, required)
+	if result == nil {
+		result = base.NewTokenResultBlockedWithCause(base.BlockTypeDeadline, msg, rule, remaining)
+	} else {
+		result.ResetToBlockedWithCause(base.BlockTypeDeadline, msg, rule, remaining)
+	}
+	return result
+}
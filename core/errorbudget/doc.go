@@ -0,0 +1,28 @@
+// Package errorbudget provides the error budget tracking and burn rate alerting of resources.
+//
+// Each error budget rule sets the target success rate (SLO) of a resource over a rolling window of N minutes.
+// The error budget is the fraction of requests allowed to fail, i.e. 1 - TargetSuccessRate, and the burn rate is
+// the observed error rate divided by the error budget: a burn rate of 1 consumes exactly the whole budget over
+// the window, while a burn rate of 10 consumes it ten times faster.
+//
+// The error budget never blocks any request, it's separate from circuit breaking. Instead, the registered
+// callbacks are fired once the burn rate exceeds each of the thresholds of the rule, so that the teams get early
+// warnings before the protection (e.g. circuit breaker) activates.
+//
+// Here is the example code to use the error budget:
+//
+//	errorbudget.RegisterAlertCallbacks(func(alert *errorbudget.Alert) {
+//	    // notify the on-call team
+//	})
+//	_, err := errorbudget.LoadRules([]*errorbudget.Rule{
+//	    {
+//	        Resource:           "some-api",
+//	        TargetSuccessRate:  0.999,
+//	        WindowMinutes:      60,
+//	        BurnRateThresholds: []float64{2, 10},
+//	    },
+//	})
+//
+// The errors are recorded from the completed entries with error (see base.TraceError),
+// so errorbudget.MetricStatSlot must be filled into the slot chain (it's included in the default slot chain).
+package errorbudget
@@ -0,0 +1,85 @@
+package errorbudget
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidRule(t *testing.T) {
+	assert.NotNil(t, IsValidRule(nil))
+	assert.NotNil(t, IsValidRule(&Rule{TargetSuccessRate: 0.99, WindowMinutes: 10, BurnRateThresholds: []float64{2}}))
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc", TargetSuccessRate: 1, WindowMinutes: 10, BurnRateThresholds: []float64{2}}))
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc", TargetSuccessRate: 0.99, BurnRateThresholds: []float64{2}}))
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc", TargetSuccessRate: 0.99, WindowMinutes: MaxWindowMinutes + 1, BurnRateThresholds: []float64{2}}))
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc", TargetSuccessRate: 0.99, WindowMinutes: 10}))
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc", TargetSuccessRate: 0.99, WindowMinutes: 10, BurnRateThresholds: []float64{0}}))
+	assert.Nil(t, IsValidRule(&Rule{Resource: "abc", TargetSuccessRate: 0.99, WindowMinutes: 10, BurnRateThresholds: []float64{2}}))
+}
+
+func TestLoadRules(t *testing.T) {
+	defer ClearRules()
+
+	_, err := LoadRules([]*Rule{
+		{Resource: "abc", TargetSuccessRate: 0.99, WindowMinutes: 10, BurnRateThresholds: []float64{2}},
+		{Resource: "abc", TargetSuccessRate: 0.9, WindowMinutes: 120, BurnRateThresholds: []float64{2}},
+		{Resource: "def"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(GetRules()))
+	assert.Equal(t, 2, len(StatusOf("abc")))
+	assert.Equal(t, 0, len(StatusOf("def")))
+
+	tr := getTrackersOf("abc")[0]
+	_, err = LoadRules([]*Rule{{Resource: "abc", TargetSuccessRate: 0.99, WindowMinutes: 10, BurnRateThresholds: []float64{2}}})
+	assert.Nil(t, err)
+	assert.True(t, tr == getTrackersOf("abc")[0])
+}
+
+func TestBurnRateAlert(t *testing.T) {
+	defer ClearRules()
+	defer ClearAlertCallbacks()
+
+	alerts := make([]*Alert, 0)
+	RegisterAlertCallbacks(func(alert *Alert) {
+		alerts = append(alerts, alert)
+	})
+	_, err := LoadRules([]*Rule{
+		{Resource: "abc", TargetSuccessRate: 0.9, WindowMinutes: 10, BurnRateThresholds: []float64{5, 2}, MinRequestAmount: 10},
+	})
+	assert.Nil(t, err)
+
+	slot := &MetricStatSlot{}
+	ctx := &base.EntryContext{Resource: base.NewResourceWrapper("abc", base.ResTypeCommon, base.Inbound)}
+	for i := 0; i < 7; i++ {
+		slot.OnCompleted(ctx)
+	}
+	ctx.SetError(errors.New("biz error"))
+	for i := 0; i < 3; i++ {
+		slot.OnCompleted(ctx)
+	}
+	tr := getTrackersOf("abc")[0]
+	s := StatusOf("abc")[0]
+	assert.Equal(t, int64(10), s.Total)
+	assert.Equal(t, int64(3), s.Errors)
+	assert.InDelta(t, 3.0, s.BurnRate, 1e-6)
+	assert.InDelta(t, -2.0, s.RemainingBudget, 1e-6)
+
+	tr.evaluate(util.CurrentTimeMillis())
+	assert.Equal(t, 1, len(alerts))
+	assert.Equal(t, 2.0, alerts[0].Threshold)
+	assert.Equal(t, "abc", alerts[0].Rule.Resource)
+	// the alert of the same threshold is fired only once
+	tr.evaluate(util.CurrentTimeMillis())
+	assert.Equal(t, 1, len(alerts))
+
+	for i := 0; i < 10; i++ {
+		slot.OnCompleted(ctx)
+	}
+	tr.evaluate(util.CurrentTimeMillis())
+	assert.Equal(t, 2, len(alerts))
+	assert.Equal(t, 5.0, alerts[1].Threshold)
+}
@@ -0,0 +1,61 @@
+package errorbudget
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Rule describes the error budget of a resource.
+type Rule struct {
+	// ID represents the unique ID of the rule (optional).
+	ID string `json:"id,omitempty"`
+	// Resource represents the resource name.
+	Resource string `json:"resource"`
+	// TargetSuccessRate is the target success rate of the resource, which must be in (0, 1), e.g. 0.999.
+	TargetSuccessRate float64 `json:"targetSuccessRate"`
+	// WindowMinutes is the length of the rolling window of the error budget (in minutes).
+	WindowMinutes uint32 `json:"windowMinutes"`
+	// BurnRateThresholds are the burn rates that trigger the alerts, e.g. [2, 10].
+	// The alert of each threshold is fired once when the burn rate exceeds it,
+	// and fired again only after the burn rate falls below it.
+	BurnRateThresholds []float64 `json:"burnRateThresholds"`
+	// MinRequestAmount is the minimum number of completed requests in the window to evaluate the burn rate,
+	// which avoids the false alerts on low traffic.
+	MinRequestAmount uint64 `json:"minRequestAmount"`
+}
+
+func (r *Rule) String() string {
+	b, err := json.Marshal(r)
+	if err != nil {
+		// Return the fallback string
+		return fmt.Sprintf("{Id=%s, Resource=%s, TargetSuccessRate=%.4f, WindowMinutes=%d, BurnRateThresholds=%v, MinRequestAmount=%d}",
+			r.ID, r.Resource, r.TargetSuccessRate, r.WindowMinutes, r.BurnRateThresholds, r.MinRequestAmount)
+	}
+	return string(b)
+}
+
+func (r *Rule) ResourceName() string {
+	return r.Resource
+}
+
+func (r *Rule) isEqualsTo(newRule *Rule) bool {
+	if newRule == nil {
+		return false
+	}
+	if !(r.Resource == newRule.Resource && r.TargetSuccessRate == newRule.TargetSuccessRate &&
+		r.WindowMinutes == newRule.WindowMinutes && r.MinRequestAmount == newRule.MinRequestAmount &&
+		len(r.BurnRateThresholds) == len(newRule.BurnRateThresholds)) {
+		return false
+	}
+	for i, t := range r.BurnRateThresholds {
+		if t != newRule.BurnRateThresholds[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// errorBudget returns the fraction of requests allowed to fail.
+func (r *Rule) errorBudget() float64 {
+	return 1 - r.TargetSuccessRate
+}
@@ -0,0 +1,171 @@
+package errorbudget
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+var (
+	trackerMap = make(map[string][]*tracker)
+	updateMux  = new(sync.RWMutex)
+
+	alertCallbacks = make([]AlertCallback, 0)
+)
+
+// RegisterAlertCallbacks registers the callbacks of the burn rate alerts.
+// Note: this function is not thread-safe.
+func RegisterAlertCallbacks(callbacks ...AlertCallback) {
+	for _, cb := range callbacks {
+		if cb != nil {
+			alertCallbacks = append(alertCallbacks, cb)
+		}
+	}
+}
+
+// ClearAlertCallbacks clears all the registered callbacks.
+// Note: this function is not thread-safe.
+func ClearAlertCallbacks() {
+	alertCallbacks = make([]AlertCallback, 0)
+}
+
+// LoadRules loads the given error budget rules to the rule manager, while all previous rules will be replaced.
+// The statistics of the unchanged rules are retained.
+func LoadRules(rules []*Rule) (bool, error) {
+	defer selfmetric.RecordRuleUpdate("errorbudget", time.Now())
+
+	resRules := make(map[string][]*Rule)
+	for _, r := range rules {
+		if err := IsValidRule(r); err != nil {
+			logging.Warn("Ignoring invalid error budget rule", "rule", r, "reason", err)
+			continue
+		}
+		resRules[r.Resource] = append(resRules[r.Resource], r)
+	}
+
+	m := make(map[string][]*tracker, len(resRules))
+	start := util.CurrentTimeNano()
+	updateMux.Lock()
+	defer func() {
+		updateMux.Unlock()
+		logging.Debug("time statistic(ns) for updating error budget rule", "timeCost", util.CurrentTimeNano()-start)
+		logRuleUpdate(m)
+	}()
+	for res, rs := range resRules {
+		oldTrackers := trackerMap[res]
+		trackers := make([]*tracker, 0, len(rs))
+		for _, r := range rs {
+			var reused *tracker
+			for _, old := range oldTrackers {
+				if old.rule.isEqualsTo(r) {
+					reused = old
+					break
+				}
+			}
+			if reused == nil {
+				reused = newTracker(r)
+			}
+			trackers = append(trackers, reused)
+		}
+		m[res] = trackers
+	}
+	trackerMap = m
+	return true, nil
+}
+
+// ClearRules clears all the rules in error budget module.
+func ClearRules() error {
+	_, err := LoadRules(nil)
+	return err
+}
+
+// GetRules returns all the rules based on copy.
+// It doesn't take effect for error budget module if user changes the rule.
+func GetRules() []Rule {
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+
+	ret := make([]Rule, 0, len(trackerMap))
+	for _, trackers := range trackerMap {
+		for _, t := range trackers {
+			ret = append(ret, *t.rule)
+		}
+	}
+	return ret
+}
+
+// StatusOf returns the error budget status of the rules of the resource.
+func StatusOf(resource string) []*Status {
+	trackers := getTrackersOf(resource)
+	ret := make([]*Status, 0, len(trackers))
+	for _, t := range trackers {
+		ret = append(ret, t.status())
+	}
+	return ret
+}
+
+// Statuses returns the error budget status of all the rules.
+func Statuses() []*Status {
+	updateMux.RLock()
+	trackers := make([]*tracker, 0, len(trackerMap))
+	for _, ts := range trackerMap {
+		trackers = append(trackers, ts...)
+	}
+	updateMux.RUnlock()
+
+	ret := make([]*Status, 0, len(trackers))
+	for _, t := range trackers {
+		ret = append(ret, t.status())
+	}
+	return ret
+}
+
+func getTrackersOf(resource string) []*tracker {
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+
+	return trackerMap[resource]
+}
+
+func logRuleUpdate(m map[string][]*tracker) {
+	rs := make([]*Rule, 0, len(m))
+	for _, trackers := range m {
+		for _, t := range trackers {
+			rs = append(rs, t.rule)
+		}
+	}
+	if len(rs) == 0 {
+		logging.Info("[ErrorBudgetRuleManager] Error budget rules were cleared")
+	} else {
+		logging.Info("[ErrorBudgetRuleManager] Error budget rules were loaded", "rules", rs)
+	}
+}
+
+// IsValidRule checks whether the given rule is valid.
+func IsValidRule(r *Rule) error {
+	if r == nil {
+		return errors.New("nil Rule")
+	}
+	if len(r.Resource) == 0 {
+		return errors.New("empty resource name")
+	}
+	if r.TargetSuccessRate <= 0 || r.TargetSuccessRate >= 1 {
+		return errors.New("TargetSuccessRate must be in (0, 1)")
+	}
+	if r.WindowMinutes == 0 || r.WindowMinutes > MaxWindowMinutes {
+		return errors.Errorf("WindowMinutes must be in [1, %d]", MaxWindowMinutes)
+	}
+	if len(r.BurnRateThresholds) == 0 {
+		return errors.New("empty BurnRateThresholds")
+	}
+	for _, t := range r.BurnRateThresholds {
+		if t <= 0 {
+			return errors.New("non-positive burn rate threshold")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package errorbudget
+
+import (
+	"github.com/alibaba/sentinel-golang/core/base"
+)
+
+// MetricStatSlot records the completed requests and errors for the error budget.
+// MetricStatSlot must be filled into slot chain if error budget is alive.
+type MetricStatSlot struct {
+}
+
+func (s *MetricStatSlot) OnEntryPassed(_ *base.EntryContext) {
+}
+
+func (s *MetricStatSlot) OnEntryBlocked(_ *base.EntryContext, _ *base.BlockError) {
+}
+
+func (s *MetricStatSlot) OnCompleted(ctx *base.EntryContext) {
+	for _, t := range getTrackersOf(ctx.Resource.Name()) {
+		t.onCompleted(ctx.Err())
+	}
+}
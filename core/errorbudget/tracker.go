@@ -0,0 +1,140 @@
+package errorbudget
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	sbase "github.com/alibaba/sentinel-golang/core/stat/base"
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+const (
+	// MaxWindowMinutes is the max length of the rolling window of the error budget.
+	MaxWindowMinutes = 1440
+
+	// evaluateIntervalMs is the min interval of the burn rate evaluation of a tracker.
+	evaluateIntervalMs = 1000
+)
+
+// Status is the error budget status of a rule within its rolling window.
+type Status struct {
+	Resource string `json:"resource"`
+	RuleID   string `json:"ruleId,omitempty"`
+	// Total is the number of completed requests in the window.
+	Total  int64 `json:"total"`
+	Errors int64 `json:"errors"`
+	// ErrorRate is Errors / Total.
+	ErrorRate float64 `json:"errorRate"`
+	// BurnRate is ErrorRate / (1 - TargetSuccessRate).
+	BurnRate float64 `json:"burnRate"`
+	// RemainingBudget is the fraction of the error budget not consumed in the window, which is 1 - BurnRate
+	// and could be negative if the budget is exhausted.
+	RemainingBudget float64 `json:"remainingBudget"`
+}
+
+// Alert is fired when the burn rate of a rule exceeds one of its thresholds.
+type Alert struct {
+	Status
+	// Threshold is the highest threshold that the burn rate exceeds.
+	Threshold float64
+	// Rule is copied from the tracker, any changes of it don't take effect.
+	Rule Rule
+	// Timestamp is the time of the alert in milliseconds.
+	Timestamp uint64
+}
+
+// AlertCallback is called when the burn rate of a rule exceeds one of its thresholds.
+// It's called in the goroutine that completes the entry, so it should return quickly.
+type AlertCallback func(alert *Alert)
+
+// tracker tracks the error budget of a rule.
+type tracker struct {
+	rule *Rule
+	data *sbase.BucketLeapArray
+	// thresholds are the sorted burn rate thresholds.
+	thresholds []float64
+	// level is the number of the thresholds that the burn rate exceeded at the last evaluation.
+	level        int32
+	lastEvalTime uint64
+}
+
+func newTracker(r *Rule) *tracker {
+	thresholds := make([]float64, len(r.BurnRateThresholds))
+	copy(thresholds, r.BurnRateThresholds)
+	sort.Float64s(thresholds)
+	return &tracker{
+		rule:       r,
+		data:       sbase.NewBucketLeapArray(sampleCountOf(r.WindowMinutes), r.WindowMinutes*60000),
+		thresholds: thresholds,
+	}
+}
+
+// sampleCountOf returns the bucket count of the window: 10-second buckets for the windows up to an hour,
+// and 1-minute buckets for the longer windows.
+func sampleCountOf(windowMinutes uint32) uint32 {
+	if windowMinutes <= 60 {
+		return windowMinutes * 6
+	}
+	return windowMinutes
+}
+
+func (t *tracker) onCompleted(err error) {
+	t.data.AddCount(base.MetricEventComplete, 1)
+	if err != nil {
+		t.data.AddCount(base.MetricEventError, 1)
+	}
+
+	now := util.CurrentTimeMillis()
+	last := atomic.LoadUint64(&t.lastEvalTime)
+	if now < last+evaluateIntervalMs || !atomic.CompareAndSwapUint64(&t.lastEvalTime, last, now) {
+		return
+	}
+	t.evaluate(now)
+}
+
+func (t *tracker) status() *Status {
+	total := t.data.Count(base.MetricEventComplete)
+	errs := t.data.Count(base.MetricEventError)
+	s := &Status{
+		Resource:        t.rule.Resource,
+		RuleID:          t.rule.ID,
+		Total:           total,
+		Errors:          errs,
+		RemainingBudget: 1,
+	}
+	if total > 0 {
+		s.ErrorRate = float64(errs) / float64(total)
+		s.BurnRate = s.ErrorRate / t.rule.errorBudget()
+		s.RemainingBudget = 1 - s.BurnRate
+	}
+	return s
+}
+
+// evaluate calculates the burn rate and fires the alert if the burn rate exceeds a higher threshold
+// than the last evaluation.
+func (t *tracker) evaluate(now uint64) {
+	s := t.status()
+	level := int32(0)
+	if uint64(s.Total) >= t.rule.MinRequestAmount {
+		for _, threshold := range t.thresholds {
+			if s.BurnRate <= threshold {
+				break
+			}
+			level++
+		}
+	}
+	prev := atomic.SwapInt32(&t.level, level)
+	if level <= prev {
+		return
+	}
+	alert := &Alert{
+		Status:    *s,
+		Threshold: t.thresholds[level-1],
+		Rule:      *t.rule,
+		Timestamp: now,
+	}
+	for _, cb := range alertCallbacks {
+		cb(alert)
+	}
+}
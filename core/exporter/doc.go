@@ -0,0 +1,35 @@
+// Package exporter provides the common pipeline of the metric exporters.
+//
+// The metric exporters (e.g. Prometheus, OTLP, StatsD or any customized sink) implement the MetricExporter
+// interface and are registered by RegisterExporters. The core aggregates the per-second metric items of all the
+// resources once every export interval (see config.MetricExportIntervalMs), and delivers the same batch to every
+// registered exporter, so that the exporters never scrape the stat nodes by themselves.
+//
+// Each exporter runs in its own goroutine with a bounded queue, so a slow exporter never blocks the others.
+// The exporter that fails to export backs off exponentially (up to MaxBackoff), and the batches during
+// the backoff are dropped. The lifecycle of the exporters is managed by the core: MetricExporter.Start is
+// called when the exporter is registered, and MetricExporter.Stop is called when it's unregistered.
+//
+// The exporters implementing PressureExporter receive the pressure scores (see package pressure) every export
+// interval as well, e.g. to serve them as the external metrics of the autoscalers. Only the latest scores are kept
+// for a slow exporter.
+//
+// Here is the example code to register an exporter:
+//
+//	type logExporter struct{}
+//
+//	func (e *logExporter) Name() string  { return "log" }
+//	func (e *logExporter) Start() error  { return nil }
+//	func (e *logExporter) Stop() error   { return nil }
+//	func (e *logExporter) Export(items []*base.MetricItem) error {
+//	    for _, item := range items {
+//	        fmt.Println(item.ToFatString())
+//	    }
+//	    return nil
+//	}
+//
+//	exporter.RegisterExporters(&logExporter{})
+//
+// The StreamExporter streams the metric items to the HTTP clients as the server-sent events, so the live dashboards
+// could subscribe to the per-second metrics rather than polling (see NewStreamExporter).
+package exporter
@@ -0,0 +1,197 @@
+package exporter
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/pressure"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+const exportQueueSize = 16
+
+var (
+	// MaxBackoff is the max backoff duration of the exporter that fails to export.
+	MaxBackoff = time.Minute
+
+	// exportDrops counts the batches dropped by all the exporters.
+	exportDrops = selfmetric.NewDropCounter("exporter")
+)
+
+// MetricExporter exports the metric items to the external systems.
+type MetricExporter interface {
+	// Name returns the unique name of the exporter.
+	Name() string
+	// Start is called once the exporter is registered.
+	Start() error
+	// Export exports the per-second metric items of all the resources.
+	// The items are shared among all the exporters, so they should be treated as read-only.
+	Export(items []*base.MetricItem) error
+	// Stop is called once the exporter is unregistered.
+	Stop() error
+}
+
+// PressureExporter is the optional interface of the MetricExporter that exports the pressure scores
+// (see package pressure) as well, e.g. as the external metrics of the autoscalers.
+type PressureExporter interface {
+	// ExportPressure exports the global pressure score and the pressure scores of all the resources,
+	// which is called once every export interval.
+	ExportPressure(global pressure.Score, scores []pressure.Score) error
+}
+
+// pressureBatch is the pressure scores to export.
+type pressureBatch struct {
+	global pressure.Score
+	scores []pressure.Score
+}
+
+// Status is the status of a registered exporter.
+type Status struct {
+	Name string `json:"name"`
+	// Exported is the number of the batches exported successfully.
+	Exported uint64 `json:"exported"`
+	// Failed is the number of the batches failed to export.
+	Failed uint64 `json:"failed"`
+	// Dropped is the number of the batches dropped due to the full queue or the backoff.
+	Dropped uint64 `json:"dropped"`
+	// LastError is the message of the last export error, empty if the last export succeeded.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// exporterWorker exports the batches in the queue by the exporter in a separate goroutine.
+type exporterWorker struct {
+	exporter MetricExporter
+	queue    chan []*base.MetricItem
+	// pressureQueue holds only the latest pressure scores, the stale ones are replaced.
+	pressureQueue chan *pressureBatch
+	stopCh        chan struct{}
+	stopped       sync.WaitGroup
+
+	exported uint64
+	failed   uint64
+	dropped  uint64
+
+	// The fields below are only accessed by the worker goroutine, except for lastError.
+	consecutiveFailures uint32
+	nextAttemptTime     uint64
+	lastError           atomic.Value
+}
+
+func newExporterWorker(e MetricExporter) *exporterWorker {
+	w := &exporterWorker{
+		exporter: e,
+		queue:    make(chan []*base.MetricItem, exportQueueSize),
+		stopCh:   make(chan struct{}),
+	}
+	if _, ok := e.(PressureExporter); ok {
+		w.pressureQueue = make(chan *pressureBatch, 1)
+	}
+	w.lastError.Store("")
+	return w
+}
+
+func (w *exporterWorker) start() {
+	w.stopped.Add(1)
+	go util.RunWithRecover(func() {
+		defer w.stopped.Done()
+		for {
+			select {
+			case items := <-w.queue:
+				w.export(items)
+			case batch := <-w.pressureQueue:
+				w.exportPressure(batch)
+			case <-w.stopCh:
+				return
+			}
+		}
+	})
+}
+
+func (w *exporterWorker) stop() {
+	close(w.stopCh)
+	w.stopped.Wait()
+}
+
+// offer enqueues the batch without blocking, the batch is dropped if the queue is full.
+func (w *exporterWorker) offer(items []*base.MetricItem) {
+	select {
+	case w.queue <- items:
+	default:
+		w.drop()
+	}
+}
+
+func (w *exporterWorker) drop() {
+	atomic.AddUint64(&w.dropped, 1)
+	exportDrops.Inc()
+}
+
+// offerPressure enqueues the latest pressure scores without blocking, replacing the stale ones not exported yet.
+func (w *exporterWorker) offerPressure(batch *pressureBatch) {
+	for {
+		select {
+		case w.pressureQueue <- batch:
+			return
+		default:
+		}
+		select {
+		case <-w.pressureQueue:
+		default:
+		}
+	}
+}
+
+func (w *exporterWorker) exportPressure(batch *pressureBatch) {
+	if err := w.exporter.(PressureExporter).ExportPressure(batch.global, batch.scores); err != nil {
+		w.lastError.Store(err.Error())
+		logging.Error(err, "[MetricExporter] Failed to export the pressure scores", "exporter", w.exporter.Name())
+	}
+}
+
+func (w *exporterWorker) export(items []*base.MetricItem) {
+	now := util.CurrentTimeMillis()
+	if now < w.nextAttemptTime {
+		w.drop()
+		return
+	}
+	if err := w.exporter.Export(items); err != nil {
+		atomic.AddUint64(&w.failed, 1)
+		w.lastError.Store(err.Error())
+		w.consecutiveFailures++
+		backoff := w.backoffDuration()
+		w.nextAttemptTime = now + uint64(backoff/time.Millisecond)
+		logging.Error(err, "[MetricExporter] Failed to export the metrics", "exporter", w.exporter.Name(),
+			"consecutiveFailures", w.consecutiveFailures, "backoff", backoff)
+		return
+	}
+	atomic.AddUint64(&w.exported, 1)
+	w.lastError.Store("")
+	w.consecutiveFailures = 0
+	w.nextAttemptTime = 0
+}
+
+// backoffDuration returns the exponential backoff based on the export interval.
+func (w *exporterWorker) backoffDuration() time.Duration {
+	backoff := exportInterval()
+	for i := uint32(1); i < w.consecutiveFailures && backoff < MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > MaxBackoff {
+		backoff = MaxBackoff
+	}
+	return backoff
+}
+
+func (w *exporterWorker) status() *Status {
+	return &Status{
+		Name:      w.exporter.Name(),
+		Exported:  atomic.LoadUint64(&w.exported),
+		Failed:    atomic.LoadUint64(&w.failed),
+		Dropped:   atomic.LoadUint64(&w.dropped),
+		LastError: w.lastError.Load().(string),
+	}
+}
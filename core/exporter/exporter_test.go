@@ -0,0 +1,219 @@
+package exporter
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/config"
+	"github.com/alibaba/sentinel-golang/core/pressure"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockExporter struct {
+	name     string
+	startErr error
+	mux      sync.Mutex
+	exportFn func(items []*base.MetricItem) error
+	batches  [][]*base.MetricItem
+	started  bool
+	stopped  bool
+}
+
+func (e *mockExporter) Name() string {
+	return e.name
+}
+
+func (e *mockExporter) Start() error {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.started = e.startErr == nil
+	return e.startErr
+}
+
+func (e *mockExporter) Export(items []*base.MetricItem) error {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.batches = append(e.batches, items)
+	if e.exportFn != nil {
+		return e.exportFn(items)
+	}
+	return nil
+}
+
+func (e *mockExporter) Stop() error {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.stopped = true
+	return nil
+}
+
+func (e *mockExporter) batchCount() int {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	return len(e.batches)
+}
+
+func TestRegisterExporters(t *testing.T) {
+	defer ClearExporters()
+
+	t.Run("Normal", func(t *testing.T) {
+		defer ClearExporters()
+		e1, e2 := &mockExporter{name: "e1"}, &mockExporter{name: "e2"}
+		assert.Nil(t, RegisterExporters(e1, e2))
+		assert.True(t, e1.started && e2.started)
+
+		statuses := Statuses()
+		assert.Equal(t, 2, len(statuses))
+		assert.Equal(t, "e1", statuses[0].Name)
+		assert.Equal(t, "e2", statuses[1].Name)
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		defer ClearExporters()
+		assert.Nil(t, RegisterExporters(&mockExporter{name: "e1"}))
+		dup := &mockExporter{name: "e1"}
+		assert.NotNil(t, RegisterExporters(dup))
+		assert.False(t, dup.started)
+		assert.Equal(t, 1, len(Statuses()))
+	})
+
+	t.Run("StartFailed", func(t *testing.T) {
+		defer ClearExporters()
+		assert.NotNil(t, RegisterExporters(&mockExporter{name: "e1", startErr: errors.New("start failed")}))
+		assert.Equal(t, 0, len(Statuses()))
+	})
+}
+
+func TestUnregisterExporter(t *testing.T) {
+	defer ClearExporters()
+
+	e := &mockExporter{name: "e1"}
+	assert.Nil(t, RegisterExporters(e))
+	assert.Nil(t, UnregisterExporter("e1"))
+	assert.True(t, e.stopped)
+	assert.Equal(t, 0, len(Statuses()))
+	assert.Nil(t, UnregisterExporter("e1"))
+}
+
+func TestExporterWorker_Export(t *testing.T) {
+	defer ClearExporters()
+
+	e := &mockExporter{name: "e1"}
+	assert.Nil(t, RegisterExporters(e))
+	items := []*base.MetricItem{{Resource: "abc", PassQps: 10}}
+	for _, w := range currentWorkers() {
+		w.offer(items)
+	}
+	assert.Eventually(t, func() bool {
+		return e.batchCount() == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "abc", e.batches[0][0].Resource)
+	assert.Equal(t, uint64(1), Statuses()[0].Exported)
+}
+
+func TestExporterWorker_Backoff(t *testing.T) {
+	e := &mockExporter{
+		name: "e1",
+		exportFn: func(items []*base.MetricItem) error {
+			return errors.New("sink unavailable")
+		},
+	}
+	w := newExporterWorker(e)
+	w.export(nil)
+	s := w.status()
+	assert.Equal(t, uint64(1), s.Failed)
+	assert.Equal(t, "sink unavailable", s.LastError)
+
+	// The batches during the backoff are dropped without calling the exporter.
+	w.export(nil)
+	s = w.status()
+	assert.Equal(t, uint64(1), s.Failed)
+	assert.Equal(t, uint64(1), s.Dropped)
+	assert.Equal(t, 1, e.batchCount())
+
+	w.consecutiveFailures = 3
+	assert.Equal(t, 4*exportInterval(), w.backoffDuration())
+	w.consecutiveFailures = 100
+	assert.Equal(t, MaxBackoff, w.backoffDuration())
+}
+
+func TestExporterWorker_QueueFull(t *testing.T) {
+	w := newExporterWorker(&mockExporter{name: "e1"})
+	totalDrops := exportDrops.Count()
+	for i := 0; i < exportQueueSize+2; i++ {
+		w.offer(nil)
+	}
+	assert.Equal(t, uint64(2), w.status().Dropped)
+	assert.Equal(t, totalDrops+2, exportDrops.Count())
+}
+
+type mockPressureExporter struct {
+	mockExporter
+	globals []pressure.Score
+}
+
+func (e *mockPressureExporter) ExportPressure(global pressure.Score, _ []pressure.Score) error {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.globals = append(e.globals, global)
+	return nil
+}
+
+func (e *mockPressureExporter) pressureCount() int {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	return len(e.globals)
+}
+
+func TestExportPressure(t *testing.T) {
+	defer ClearExporters()
+
+	pe := &mockPressureExporter{mockExporter: mockExporter{name: "pressure"}}
+	e := &mockExporter{name: "plain"}
+	assert.Nil(t, RegisterExporters(pe, e))
+
+	ws := currentWorkers()
+	exportPressure(ws)
+	assert.Eventually(t, func() bool {
+		return pe.pressureCount() == 1
+	}, time.Second, 10*time.Millisecond)
+	// the metric items are not affected
+	assert.Equal(t, 0, pe.batchCount())
+	assert.Equal(t, 0, e.batchCount())
+}
+
+func TestExporterWorker_OfferPressure(t *testing.T) {
+	w := newExporterWorker(&mockPressureExporter{mockExporter: mockExporter{name: "pressure"}})
+	// only the latest scores are kept before the worker starts
+	w.offerPressure(&pressureBatch{global: pressure.Score{Score: 0.1}})
+	w.offerPressure(&pressureBatch{global: pressure.Score{Score: 0.2}})
+	assert.Equal(t, 1, len(w.pressureQueue))
+	assert.Equal(t, 0.2, (<-w.pressureQueue).global.Score)
+
+	assert.Nil(t, newExporterWorker(&mockExporter{name: "plain"}).pressureQueue)
+}
+
+func TestCollectMetricItems_Runtime(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Sentinel.Stat.RuntimeMetricEnabled = true
+	config.SetDefaultConfig(cfg)
+	defer config.SetDefaultConfig(config.NewDefaultConfig())
+	atomic.StoreInt64(&lastFetchTime, -1)
+
+	runtimeItems := 0
+	for _, item := range collectMetricItems() {
+		if item.Classification != base.MetricItemClassificationRuntime {
+			continue
+		}
+		runtimeItems++
+		assert.True(t, strings.HasPrefix(item.Resource, stat.RuntimeMetricResourcePrefix))
+		assert.Equal(t, uint64(0), item.Timestamp%1000)
+	}
+	assert.Equal(t, 3, runtimeItems)
+}
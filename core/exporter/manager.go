@@ -0,0 +1,206 @@
+package exporter
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/config"
+	"github.com/alibaba/sentinel-golang/core/pressure"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+var (
+	workers   = make(map[string]*exporterWorker)
+	workerMux = new(sync.RWMutex)
+
+	intervalNs    = int64(time.Second)
+	lastFetchTime = int64(-1)
+	startOnce     sync.Once
+)
+
+// RegisterExporters starts and registers the exporters. The exporter is not registered if it fails to start
+// or the exporter with the same name has been registered.
+func RegisterExporters(exporters ...MetricExporter) error {
+	workerMux.Lock()
+	defer workerMux.Unlock()
+
+	for _, e := range exporters {
+		if e == nil {
+			continue
+		}
+		name := e.Name()
+		if _, exist := workers[name]; exist {
+			return errors.Errorf("duplicate metric exporter: %s", name)
+		}
+		if err := e.Start(); err != nil {
+			return errors.Wrapf(err, "fail to start the metric exporter: %s", name)
+		}
+		w := newExporterWorker(e)
+		w.start()
+		workers[name] = w
+		logging.Info("[MetricExporter] Metric exporter registered", "exporter", name)
+	}
+	return nil
+}
+
+// UnregisterExporter unregisters and stops the exporter of the given name.
+func UnregisterExporter(name string) error {
+	workerMux.Lock()
+	w, exist := workers[name]
+	delete(workers, name)
+	workerMux.Unlock()
+
+	if !exist {
+		return nil
+	}
+	return stopWorker(w)
+}
+
+// ClearExporters unregisters and stops all the exporters.
+func ClearExporters() {
+	workerMux.Lock()
+	ws := workers
+	workers = make(map[string]*exporterWorker)
+	workerMux.Unlock()
+
+	for _, w := range ws {
+		if err := stopWorker(w); err != nil {
+			logging.Error(err, "[MetricExporter] Failed to stop the metric exporter", "exporter", w.exporter.Name())
+		}
+	}
+}
+
+func stopWorker(w *exporterWorker) error {
+	w.stop()
+	if err := w.exporter.Stop(); err != nil {
+		return errors.Wrapf(err, "fail to stop the metric exporter: %s", w.exporter.Name())
+	}
+	logging.Info("[MetricExporter] Metric exporter unregistered", "exporter", w.exporter.Name())
+	return nil
+}
+
+// Statuses returns the status of all the registered exporters.
+func Statuses() []*Status {
+	workerMux.RLock()
+	defer workerMux.RUnlock()
+
+	ret := make([]*Status, 0, len(workers))
+	for _, w := range workers {
+		ret = append(ret, w.status())
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+// StartExportTask starts the background task that aggregates and delivers the metric items to the exporters
+// every interval, which should be less than the global statistic interval. It takes effect only once.
+func StartExportTask(interval time.Duration) {
+	startOnce.Do(func() {
+		if interval <= 0 {
+			interval = time.Second
+		}
+		atomic.StoreInt64(&intervalNs, int64(interval))
+		ticker := time.NewTicker(interval)
+		go util.RunWithRecover(func() {
+			for range ticker.C {
+				doExport()
+			}
+		})
+	})
+}
+
+func exportInterval() time.Duration {
+	return time.Duration(atomic.LoadInt64(&intervalNs))
+}
+
+func currentWorkers() []*exporterWorker {
+	workerMux.RLock()
+	defer workerMux.RUnlock()
+
+	ret := make([]*exporterWorker, 0, len(workers))
+	for _, w := range workers {
+		ret = append(ret, w)
+	}
+	return ret
+}
+
+func doExport() {
+	ws := currentWorkers()
+	if len(ws) == 0 {
+		return
+	}
+	exportPressure(ws)
+	items := collectMetricItems()
+	if len(items) == 0 {
+		return
+	}
+	for _, w := range ws {
+		w.offer(items)
+	}
+}
+
+// exportPressure delivers the pressure scores to the exporters implementing PressureExporter.
+func exportPressure(ws []*exporterWorker) {
+	var batch *pressureBatch
+	for _, w := range ws {
+		if w.pressureQueue == nil {
+			continue
+		}
+		if batch == nil {
+			scores := pressure.Scores()
+			batch = &pressureBatch{global: pressure.GlobalScoreOf(scores), scores: scores}
+		}
+		w.offerPressure(batch)
+	}
+}
+
+// collectMetricItems aggregates the per-second metric items of all the resources since the last collection.
+func collectMetricItems() []*base.MetricItem {
+	curTime := util.CurrentTimeMillis()
+	curTime = curTime - curTime%1000
+	lastFetch := atomic.LoadInt64(&lastFetchTime)
+	if int64(curTime) <= lastFetch {
+		return nil
+	}
+	atomic.StoreInt64(&lastFetchTime, int64(curTime))
+
+	predicate := func(ts uint64) bool {
+		return int64(ts) >= lastFetch && ts < curTime
+	}
+	items := make([]*base.MetricItem, 0)
+	nodes := append(stat.ResourceNodeList(), stat.InboundNode())
+	for _, node := range nodes {
+		for _, item := range node.MetricsOnCondition(predicate) {
+			if !isActiveMetricItem(item) {
+				continue
+			}
+			item.Resource = node.ResourceName()
+			item.Classification = int32(node.ResourceType())
+			items = append(items, item)
+		}
+	}
+	if config.RuntimeMetricEnabled() {
+		// align the runtime metrics to the latest complete second, as the resource metrics
+		items = append(items, stat.RuntimeMetricItems(curTime-1000)...)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Timestamp == items[j].Timestamp {
+			return items[i].Resource < items[j].Resource
+		}
+		return items[i].Timestamp < items[j].Timestamp
+	})
+	return items
+}
+
+func isActiveMetricItem(item *base.MetricItem) bool {
+	return item.PassQps > 0 || item.BlockQps > 0 || item.CompleteQps > 0 || item.ErrorQps > 0 ||
+		item.AvgRt > 0 || item.Concurrency > 0
+}
@@ -0,0 +1,215 @@
+package exporter
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+	"github.com/alibaba/sentinel-golang/logging"
+)
+
+const (
+	// DefaultStreamExporterName is the default name of the StreamExporter.
+	DefaultStreamExporterName = "stream"
+	// DefaultStreamClientBufferSize is the default number of the batches buffered for each stream client.
+	DefaultStreamClientBufferSize = 16
+	// StreamEventName is the name of the server-sent events of the metric batches.
+	StreamEventName = "metrics"
+)
+
+// streamDrops counts the batches dropped by all the slow stream clients.
+var streamDrops = selfmetric.NewDropCounter("exporter.stream")
+
+// StreamExporter streams the per-second metric items to the HTTP clients as the server-sent events (SSE),
+// e.g. for the live dashboards or the `curl -N` watching, without polling.
+// It's both a MetricExporter to register and an http.Handler to mount on the HTTP server of the application:
+//
+//	s := exporter.NewStreamExporter(exporter.DefaultStreamExporterName, 0)
+//	_ = exporter.RegisterExporters(s)
+//	http.Handle("/sentinel/metrics/stream", s)
+//
+// Each batch is sent as an event named StreamEventName, whose data is the JSON array of the metric items
+// in the JSON format of the metric log (see base.MetricItem.ToJSONString). The clients could subscribe to
+// some resources only by the query parameter "resource", e.g. "?resource=a&resource=b".
+// The batches are dropped for the slow clients rather than blocking the export.
+type StreamExporter struct {
+	name       string
+	bufferSize int
+
+	mux     sync.RWMutex
+	clients map[*streamClient]struct{}
+	stopped bool
+}
+
+type streamClient struct {
+	// resources are the subscribed resources, nil means all the resources.
+	resources map[string]struct{}
+	events    chan []byte
+	closed    chan struct{}
+}
+
+// NewStreamExporter creates a StreamExporter of the name, buffering bufferSize batches for each client.
+// DefaultStreamClientBufferSize is used if bufferSize is not positive.
+func NewStreamExporter(name string, bufferSize int) *StreamExporter {
+	if bufferSize <= 0 {
+		bufferSize = DefaultStreamClientBufferSize
+	}
+	return &StreamExporter{
+		name:       name,
+		bufferSize: bufferSize,
+		clients:    make(map[*streamClient]struct{}),
+	}
+}
+
+func (s *StreamExporter) Name() string {
+	return s.name
+}
+
+func (s *StreamExporter) Start() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.stopped = false
+	return nil
+}
+
+// Stop disconnects all the clients.
+func (s *StreamExporter) Stop() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.stopped = true
+	for c := range s.clients {
+		close(c.closed)
+		delete(s.clients, c)
+	}
+	return nil
+}
+
+func (s *StreamExporter) Export(items []*base.MetricItem) error {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	if len(s.clients) == 0 {
+		return nil
+	}
+	var all []byte
+	for c := range s.clients {
+		var event []byte
+		if c.resources == nil {
+			if all == nil {
+				all = streamEventOf(items)
+			}
+			event = all
+		} else {
+			subscribed := make([]*base.MetricItem, 0)
+			for _, item := range items {
+				if _, ok := c.resources[item.Resource]; ok {
+					subscribed = append(subscribed, item)
+				}
+			}
+			if len(subscribed) == 0 {
+				continue
+			}
+			event = streamEventOf(subscribed)
+		}
+		select {
+		case c.events <- event:
+		default:
+			streamDrops.Inc()
+		}
+	}
+	return nil
+}
+
+// ServeHTTP streams the metric batches to the client until the client disconnects or the exporter stops.
+func (s *StreamExporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	c := &streamClient{
+		events: make(chan []byte, s.bufferSize),
+		closed: make(chan struct{}),
+	}
+	if resources := r.URL.Query()["resource"]; len(resources) > 0 {
+		c.resources = make(map[string]struct{}, len(resources))
+		for _, res := range resources {
+			c.resources[res] = struct{}{}
+		}
+	}
+	if !s.addClient(c) {
+		http.Error(w, "stream exporter stopped", http.StatusServiceUnavailable)
+		return
+	}
+	defer s.removeClient(c)
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case event := <-c.events:
+			if _, err := w.Write(event); err != nil {
+				logging.Debug("[StreamExporter] Failed to write the metric event", "err", err)
+				return
+			}
+			flusher.Flush()
+		case <-c.closed:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// ClientCount returns the number of the connected clients.
+func (s *StreamExporter) ClientCount() int {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	return len(s.clients)
+}
+
+func (s *StreamExporter) addClient(c *streamClient) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.stopped {
+		return false
+	}
+	s.clients[c] = struct{}{}
+	return true
+}
+
+func (s *StreamExporter) removeClient(c *streamClient) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	delete(s.clients, c)
+}
+
+// streamEventOf formats the metric items as a server-sent event.
+func streamEventOf(items []*base.MetricItem) []byte {
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		line, err := item.ToJSONString()
+		if err != nil {
+			logging.Warn("[StreamExporter] Failed to convert MetricItem to JSON", "resourceName", item.Resource, "err", err)
+			continue
+		}
+		lines = append(lines, line)
+	}
+	b := bytes.Buffer{}
+	b.WriteString("event: " + StreamEventName + "\n")
+	b.WriteString("data: [" + strings.Join(lines, ",") + "]\n\n")
+	return b.Bytes()
+}
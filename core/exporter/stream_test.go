@@ -0,0 +1,95 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func waitForClients(t *testing.T, s *StreamExporter, n int) {
+	deadline := time.Now().Add(3 * time.Second)
+	for s.ClientCount() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expect %d stream clients, actual %d", n, s.ClientCount())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func readStreamEvent(t *testing.T, r *bufio.Reader) (string, []map[string]interface{}) {
+	var event, data string
+	for {
+		line, err := r.ReadString('\n')
+		assert.Nil(t, err)
+		line = strings.TrimRight(line, "\n")
+		if line == "" {
+			break
+		}
+		if strings.HasPrefix(line, "event: ") {
+			event = strings.TrimPrefix(line, "event: ")
+		} else if strings.HasPrefix(line, "data: ") {
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+	items := make([]map[string]interface{}, 0)
+	assert.Nil(t, json.Unmarshal([]byte(data), &items))
+	return event, items
+}
+
+func TestStreamExporter(t *testing.T) {
+	s := NewStreamExporter(DefaultStreamExporterName, 0)
+	assert.Nil(t, s.Start())
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	all, err := http.Get(server.URL)
+	assert.Nil(t, err)
+	defer all.Body.Close()
+	assert.Equal(t, "text/event-stream", all.Header.Get("Content-Type"))
+	filtered, err := http.Get(server.URL + "?resource=def")
+	assert.Nil(t, err)
+	defer filtered.Body.Close()
+	waitForClients(t, s, 2)
+
+	items := []*base.MetricItem{{Timestamp: 1000, Resource: "abc", PassQps: 1}, {Timestamp: 1000, Resource: "def", BlockQps: 2}}
+	assert.Nil(t, s.Export(items))
+
+	event, received := readStreamEvent(t, bufio.NewReader(all.Body))
+	assert.Equal(t, StreamEventName, event)
+	assert.Equal(t, 2, len(received))
+	assert.Equal(t, "abc", received[0]["resource"])
+
+	event, received = readStreamEvent(t, bufio.NewReader(filtered.Body))
+	assert.Equal(t, StreamEventName, event)
+	assert.Equal(t, 1, len(received))
+	assert.Equal(t, "def", received[0]["resource"])
+	assert.Equal(t, float64(2), received[0]["blockQps"])
+
+	// the clients are disconnected once stopped
+	assert.Nil(t, s.Stop())
+	assert.Equal(t, 0, s.ClientCount())
+	resp, err := http.Get(server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestStreamExporter_SlowClient(t *testing.T) {
+	s := NewStreamExporter("slow", 1)
+	c := &streamClient{events: make(chan []byte, 1), closed: make(chan struct{})}
+	assert.True(t, s.addClient(c))
+
+	drops := streamDrops.Count()
+	items := []*base.MetricItem{{Timestamp: 1000, Resource: "abc", PassQps: 1}}
+	assert.Nil(t, s.Export(items))
+	assert.Nil(t, s.Export(items))
+	assert.Equal(t, drops+1, streamDrops.Count())
+	assert.Equal(t, 1, len(c.events))
+}
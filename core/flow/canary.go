@@ -0,0 +1,292 @@
+package flow
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+// CanaryArmStat represents the statistic of the requests evaluated against one side of the canary.
+type CanaryArmStat struct {
+	RequestCount uint64
+	BlockedCount uint64
+	// BlockRatio is BlockedCount / RequestCount, 0 if there's no request.
+	BlockRatio float64
+}
+
+// CanaryStat represents the comparative statistic of the canary rollout of a resource.
+type CanaryStat struct {
+	Resource string
+	// Rules are the new rules rolled out.
+	Rules []Rule
+	// Percent is the percentage of the requests evaluated against the new rules.
+	Percent float64
+	// StartTime is the timestamp (in ms) when the canary started.
+	StartTime  uint64
+	BakePeriod time.Duration
+	// Baseline is the statistic of the requests evaluated against the current rules.
+	Baseline CanaryArmStat
+	// Canary is the statistic of the requests evaluated against the new rules.
+	Canary CanaryArmStat
+}
+
+type canaryArmCounter struct {
+	requestCount uint64
+	blockedCount uint64
+}
+
+func (c *canaryArmCounter) record(blocked bool) {
+	atomic.AddUint64(&c.requestCount, 1)
+	if blocked {
+		atomic.AddUint64(&c.blockedCount, 1)
+	}
+}
+
+func (c *canaryArmCounter) stat() CanaryArmStat {
+	ret := CanaryArmStat{
+		RequestCount: atomic.LoadUint64(&c.requestCount),
+		BlockedCount: atomic.LoadUint64(&c.blockedCount),
+	}
+	if ret.RequestCount > 0 {
+		ret.BlockRatio = float64(ret.BlockedCount) / float64(ret.RequestCount)
+	}
+	return ret
+}
+
+type canary struct {
+	resource   string
+	rules      []*Rule
+	tcs        []*TrafficShapingController
+	percent    float64
+	startTime  uint64
+	bakePeriod time.Duration
+	timer      *time.Timer
+
+	baseline canaryArmCounter
+	canary   canaryArmCounter
+}
+
+var (
+	canaries    = make(map[string]*canary)
+	canaryMux   = new(sync.RWMutex)
+	canaryCount int32
+)
+
+// StartCanary rolls the new rules of the resource out to the given percentage (0, 100] of the requests,
+// while the rest requests are still evaluated against the current rules of the resource. The comparative
+// block ratio of both sides could be got by CanaryStatOf, so that the risky threshold changes could be
+// validated gradually on a single instance.
+//
+// The new rules replace the current rules of the resource (see PromoteCanary) once the bake period elapses,
+// unless the canary is aborted by AbortCanary before. The canary in progress of the resource is replaced.
+func StartCanary(resource string, rules []*Rule, percent float64, bakePeriod time.Duration) error {
+	if len(resource) == 0 {
+		return errors.New("empty resource name")
+	}
+	if !(percent > 0 && percent <= 100) {
+		return errors.Errorf("invalid canary percent: %f", percent)
+	}
+	if bakePeriod <= 0 {
+		return errors.New("non-positive bake period")
+	}
+	if len(rules) == 0 {
+		return errors.New("empty canary rules")
+	}
+	tcs := make([]*TrafficShapingController, 0, len(rules))
+	for _, r := range rules {
+		if err := IsValidRule(r); err != nil {
+			return errors.Wrapf(err, "invalid canary rule: %s", r)
+		}
+		if r.Resource != resource {
+			return errors.Errorf("the resource of canary rule mismatches: %s", r.Resource)
+		}
+		tc, err := newCanaryTrafficController(r)
+		if err != nil {
+			return err
+		}
+		tcs = append(tcs, tc)
+	}
+	c := &canary{
+		resource:   resource,
+		rules:      rules,
+		tcs:        tcs,
+		percent:    percent,
+		startTime:  util.CurrentTimeMillis(),
+		bakePeriod: bakePeriod,
+	}
+
+	canaryMux.Lock()
+	defer canaryMux.Unlock()
+	if old, ok := canaries[resource]; ok {
+		old.timer.Stop()
+	} else {
+		atomic.AddInt32(&canaryCount, 1)
+	}
+	c.timer = time.AfterFunc(bakePeriod, func() {
+		if err := promoteCanary(c); err != nil {
+			logging.Error(err, "[FlowCanary] Failed to promote the canary rules after the bake period", "resource", resource)
+		}
+	})
+	canaries[resource] = c
+	refreshCanaryResources()
+	logging.Info("[FlowCanary] Canary started", "resource", resource, "percent", percent, "bakePeriod", bakePeriod, "rules", rules)
+	return nil
+}
+
+func newCanaryTrafficController(r *Rule) (*TrafficShapingController, error) {
+	tcMux.RLock()
+	generator, supported := tcGenFuncMap[trafficControllerGenKey{
+		tokenCalculateStrategy: r.TokenCalculateStrategy,
+		controlBehavior:        r.ControlBehavior,
+	}]
+	tcMux.RUnlock()
+	if !supported || generator == nil {
+		return nil, errors.Errorf("unsupported flow control strategy of canary rule: %s", r)
+	}
+	// The standalone statistic is never shared with the current rules, or the passed requests are counted twice.
+	tc, err := generator(r, nil)
+	if err != nil {
+		return nil, err
+	}
+	if tc == nil {
+		return nil, errors.Errorf("nil traffic controller of canary rule: %s", r)
+	}
+	return tc, nil
+}
+
+// PromoteCanary ends the canary of the resource, and the new rules replace the current rules of the resource.
+func PromoteCanary(resource string) error {
+	canaryMux.RLock()
+	c, ok := canaries[resource]
+	canaryMux.RUnlock()
+	if !ok {
+		return errors.Errorf("no canary of resource: %s", resource)
+	}
+	return promoteCanary(c)
+}
+
+func promoteCanary(c *canary) error {
+	if !removeCanary(c) {
+		// The canary has been aborted, promoted or replaced.
+		return nil
+	}
+	defer dispatchRuleUpdates()
+
+	updateRuleMux.Lock()
+	defer updateRuleMux.Unlock()
+
+	// The canary rules replace the rules of the resource in the default namespace, see LoadRulesOfNamespace.
+	rules := make([]*Rule, 0, len(defaultNamespaceRules)+len(c.rules))
+	for _, r := range defaultNamespaceRules {
+		if r == nil || r.Resource != c.resource {
+			rules = append(rules, r)
+		}
+	}
+	rules = append(rules, c.rules...)
+	logging.Info("[FlowCanary] Canary promoted", "resource", c.resource, "stat", c.stat())
+	return reloadDefaultRules(rules)
+}
+
+// AbortCanary ends the canary of the resource, and the current rules of the resource are kept.
+func AbortCanary(resource string) {
+	canaryMux.RLock()
+	c, ok := canaries[resource]
+	canaryMux.RUnlock()
+	if ok && removeCanary(c) {
+		logging.Info("[FlowCanary] Canary aborted", "resource", resource, "stat", c.stat())
+	}
+}
+
+// removeCanary removes the canary if it's still in progress.
+func removeCanary(c *canary) bool {
+	canaryMux.Lock()
+	defer canaryMux.Unlock()
+
+	if canaries[c.resource] != c {
+		return false
+	}
+	c.timer.Stop()
+	delete(canaries, c.resource)
+	atomic.AddInt32(&canaryCount, -1)
+	refreshCanaryResources()
+	return true
+}
+
+// refreshCanaryResources registers the resources with canaries to the rule resource index, so that
+// the canary takes effect on the resources without current rules. It must be called with canaryMux held.
+func refreshCanaryResources() {
+	resources := make([]string, 0, len(canaries))
+	for res := range canaries {
+		resources = append(resources, res)
+	}
+	base.SetRuleResourcesOf("flow-canary", resources, false)
+}
+
+// CanaryStatOf returns the statistic of the canary of the resource, and false if there's no canary in progress.
+func CanaryStatOf(resource string) (CanaryStat, bool) {
+	canaryMux.RLock()
+	c, ok := canaries[resource]
+	canaryMux.RUnlock()
+	if !ok {
+		return CanaryStat{}, false
+	}
+	return c.stat(), true
+}
+
+func (c *canary) stat() CanaryStat {
+	rules := make([]Rule, 0, len(c.rules))
+	for _, r := range c.rules {
+		rules = append(rules, *r)
+	}
+	return CanaryStat{
+		Resource:   c.resource,
+		Rules:      rules,
+		Percent:    c.percent,
+		StartTime:  c.startTime,
+		BakePeriod: c.bakePeriod,
+		Baseline:   c.baseline.stat(),
+		Canary:     c.canary.stat(),
+	}
+}
+
+func canaryOf(resource string) *canary {
+	if atomic.LoadInt32(&canaryCount) == 0 {
+		return nil
+	}
+	canaryMux.RLock()
+	defer canaryMux.RUnlock()
+
+	return canaries[resource]
+}
+
+// canaryControllersFor returns the traffic controllers which the request is evaluated against,
+// and the counter of the canary side, nil if there's no canary of the resource.
+func canaryControllersFor(resource string, category base.RequestCategory, tcs []*TrafficShapingController) ([]*TrafficShapingController, *canaryArmCounter) {
+	c := canaryOf(resource)
+	if c == nil {
+		return tcs, nil
+	}
+	if rand.Float64()*100 < c.percent {
+		return filterControllers(c.tcs, category, checkView), &c.canary
+	}
+	return tcs, &c.baseline
+}
+
+// recordCanaryPass records the passed request to the standalone statistic of the canary rules.
+func recordCanaryPass(ctx *base.EntryContext) {
+	c := canaryOf(ctx.Resource.Name())
+	if c == nil {
+		return
+	}
+	for _, tc := range c.tcs {
+		if !tc.boundStat.reuseResourceStat && tc.boundStat.writeOnlyMetric != nil {
+			tc.boundStat.writeOnlyMetric.AddCount(base.MetricEventPass, int64(tokenCountOf(ctx, tc.rule)))
+		}
+	}
+}
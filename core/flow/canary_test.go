@@ -0,0 +1,88 @@
+package flow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanary(t *testing.T) {
+	defer ClearRules()
+
+	_, err := LoadRules([]*Rule{
+		{Resource: "canary-res", TokenCalculateStrategy: Direct, ControlBehavior: Reject, Threshold: 1000, StatIntervalInMs: 20000},
+		{Resource: "canary-other", TokenCalculateStrategy: Direct, ControlBehavior: Reject, Threshold: 10},
+	})
+	assert.NoError(t, err)
+	newRules := func() []*Rule {
+		return []*Rule{{Resource: "canary-res", TokenCalculateStrategy: Direct, ControlBehavior: Reject, Threshold: 0, StatIntervalInMs: 20000}}
+	}
+
+	assert.Error(t, StartCanary("canary-res", newRules(), 0, time.Minute))
+	assert.Error(t, StartCanary("canary-res", newRules(), 50, 0))
+	assert.Error(t, StartCanary("canary-other", newRules(), 50, time.Minute))
+	assert.Error(t, StartCanary("canary-res", []*Rule{{Resource: "canary-res", Threshold: -1}}, 50, time.Minute))
+	assert.NoError(t, StartCanary("canary-res", newRules(), 50, time.Minute))
+
+	slot := &Slot{}
+	statSlot := &StandaloneStatSlot{}
+	ctx := &base.EntryContext{
+		Resource: base.NewResourceWrapper("canary-res", base.ResTypeCommon, base.Inbound),
+		StatNode: stat.GetOrCreateResourceNode("canary-res", base.ResTypeCommon),
+		Input:    &base.SentinelInput{AcquireCount: 1},
+	}
+	for i := 0; i < 200; i++ {
+		if r := slot.Check(ctx); r == nil || r.Status() != base.ResultStatusBlocked {
+			statSlot.OnEntryPassed(ctx)
+		}
+	}
+	s, ok := CanaryStatOf("canary-res")
+	assert.True(t, ok)
+	assert.Equal(t, float64(50), s.Percent)
+	assert.Equal(t, uint64(200), s.Baseline.RequestCount+s.Canary.RequestCount)
+	assert.True(t, s.Canary.RequestCount > 50 && s.Canary.RequestCount < 150)
+	assert.Equal(t, float64(1), s.Canary.BlockRatio)
+	assert.Equal(t, float64(0), s.Baseline.BlockRatio)
+
+	// The current rules are kept after the canary is aborted.
+	AbortCanary("canary-res")
+	_, ok = CanaryStatOf("canary-res")
+	assert.False(t, ok)
+	assert.Equal(t, float64(1000), GetRulesOfResource("canary-res")[0].Threshold)
+
+	// The new rules are promoted after the bake period.
+	assert.NoError(t, StartCanary("canary-res", newRules(), 10, 50*time.Millisecond))
+	assert.Eventually(t, func() bool {
+		rules := GetRulesOfResource("canary-res")
+		return len(rules) == 1 && rules[0].Threshold == 0
+	}, time.Second, 10*time.Millisecond)
+	_, ok = CanaryStatOf("canary-res")
+	assert.False(t, ok)
+	assert.Equal(t, float64(10), GetRulesOfResource("canary-other")[0].Threshold)
+	assert.Error(t, PromoteCanary("canary-res"))
+}
+
+func TestPromoteCanaryWithNamespaces(t *testing.T) {
+	defer ClearRules()
+
+	_, err := LoadRules([]*Rule{{Resource: "canary-p1", Threshold: 100}})
+	assert.NoError(t, err)
+	_, err = LoadRulesOfNamespace("canary-ns", []*Rule{{Resource: "canary-ns::p1", Threshold: 10}})
+	assert.NoError(t, err)
+
+	assert.NoError(t, StartCanary("canary-p1", []*Rule{{Resource: "canary-p1", Threshold: 5}}, 50, time.Minute))
+	assert.NoError(t, PromoteCanary("canary-p1"))
+	assert.Equal(t, float64(5), GetRulesOfResource("canary-p1")[0].Threshold)
+	// the rules of the other namespaces are not promoted into the default namespace
+	assert.Equal(t, 1, len(defaultNamespaceRules))
+
+	// the incremental updates are based on the promoted rules
+	_, err = AppendRule(&Rule{Resource: "canary-p2", Threshold: 20})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(5), GetRulesOfResource("canary-p1")[0].Threshold)
+	assert.Equal(t, float64(10), GetRulesOfResource("canary-ns::p1")[0].Threshold)
+	assert.Equal(t, 2, len(defaultNamespaceRules))
+}
@@ -0,0 +1,74 @@
+package flow
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+)
+
+// controllerView represents the purpose of a filtered slice of the traffic controllers of a resource.
+type controllerView uint8
+
+const (
+	// checkView is the controllers to check for the requests of the category.
+	checkView controllerView = iota
+	// standaloneStatView is the controllers with standalone statistic recording the requests of the category.
+	standaloneStatView
+)
+
+type controllerCacheKey struct {
+	resource string
+	category base.RequestCategory
+	view     controllerView
+}
+
+// controllerCache caches the filtered controller slices (*sync.Map of controllerCacheKey to []*TrafficShapingController),
+// so that the resources with many rules don't filter the controllers on every request.
+// It's replaced by a new one whenever tcMap is updated, which must be done with tcMux locked.
+var controllerCache atomic.Value
+
+func init() {
+	invalidateControllerCache()
+}
+
+// invalidateControllerCache discards the cached controller slices, it must be called with tcMux locked.
+func invalidateControllerCache() {
+	controllerCache.Store(new(sync.Map))
+}
+
+// cachedControllersFor returns the controllers of the resource for the given category and view,
+// which are filtered on the first call and cached until the rules are updated.
+// The returned slice is shared and must be treated as read-only.
+func cachedControllersFor(resource string, category base.RequestCategory, view controllerView) []*TrafficShapingController {
+	tcMux.RLock()
+	defer tcMux.RUnlock()
+
+	cache := controllerCache.Load().(*sync.Map)
+	key := controllerCacheKey{resource: resource, category: category, view: view}
+	if tcs, ok := cache.Load(key); ok {
+		return tcs.([]*TrafficShapingController)
+	}
+	tcs := tcMap[resource]
+	if len(tcs) == 0 {
+		// not cached, otherwise the cache grows with every resource without rules
+		return nil
+	}
+	filtered := filterControllers(tcs, category, view)
+	cache.Store(key, filtered)
+	return filtered
+}
+
+func filterControllers(tcs []*TrafficShapingController, category base.RequestCategory, view controllerView) []*TrafficShapingController {
+	ret := make([]*TrafficShapingController, 0, len(tcs))
+	for _, tc := range tcs {
+		if tc == nil || !tc.rule.matchesCategory(category) {
+			continue
+		}
+		if view == standaloneStatView && tc.boundStat.reuseResourceStat {
+			continue
+		}
+		ret = append(ret, tc)
+	}
+	return ret
+}
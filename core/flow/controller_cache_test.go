@@ -0,0 +1,45 @@
+package flow
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedControllersFor(t *testing.T) {
+	defer func() {
+		_ = ClearRules()
+	}()
+
+	_, err := LoadRules([]*Rule{
+		{Resource: "abc-cache", Threshold: 10},
+		{Resource: "abc-cache", Category: base.CategoryRead, Threshold: 5, StatIntervalInMs: 20000},
+		{Resource: "abc-cache", Category: base.CategoryWrite, Threshold: 2},
+	})
+	assert.Nil(t, err)
+
+	reads := cachedControllersFor("abc-cache", base.CategoryRead, checkView)
+	assert.Equal(t, 2, len(reads))
+	assert.Equal(t, base.CategoryNone, reads[0].BoundRule().Category)
+	assert.Equal(t, base.CategoryRead, reads[1].BoundRule().Category)
+	// the filtered slice is cached
+	assert.Equal(t, &reads[0], &cachedControllersFor("abc-cache", base.CategoryRead, checkView)[0])
+
+	assert.Equal(t, 1, len(cachedControllersFor("abc-cache", base.CategoryNone, checkView)))
+	standalone := cachedControllersFor("abc-cache", base.CategoryRead, standaloneStatView)
+	assert.Equal(t, 1, len(standalone))
+	assert.Equal(t, base.CategoryRead, standalone[0].BoundRule().Category)
+	assert.Nil(t, cachedControllersFor("abc-cache-none", base.CategoryRead, checkView))
+
+	// the cache is invalidated on rule reload
+	_, err = LoadRules([]*Rule{
+		{Resource: "abc-cache", Category: base.CategoryRead, Threshold: 5},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(cachedControllersFor("abc-cache", base.CategoryRead, checkView)))
+	assert.Equal(t, 0, len(cachedControllersFor("abc-cache", base.CategoryWrite, checkView)))
+
+	assert.Nil(t, ClearRules())
+	assert.Nil(t, cachedControllersFor("abc-cache", base.CategoryRead, checkView))
+}
@@ -0,0 +1,109 @@
+package flow
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/logging"
+)
+
+// CostFunc calculates the cost of the entry (e.g. the request size in KB), which is used as the token count
+// of the flow rules instead of the acquire count. The returned 0 means the entry is free.
+// It's invoked on every entry of the resource, so it should be cheap and never block.
+type CostFunc func(ctx *base.EntryContext) uint32
+
+// costDataKey is the key of the calculated cost in EntryContext.Data.
+type costDataKey struct{}
+
+var (
+	costFuncs    = make(map[string]CostFunc)
+	costFuncsMux = new(sync.RWMutex)
+	// costFuncCount is the number of the registered cost functions, which is the fast path of the resources
+	// without cost functions.
+	costFuncCount int32
+)
+
+// RegisterCostFunc registers the cost function of the resource, which enables the bandwidth-style limits
+// (e.g. the threshold of the flow rule means KB per second) rather than the pure request counts.
+// As the statistic of the resource node counts the requests, the flow rules of the resource with
+// the cost function always use the standalone statistic (except for the AssociatedResource rules),
+// so the existing rules of the resource are rebuilt with the statistics reset.
+func RegisterCostFunc(resource string, f CostFunc) {
+	if f == nil {
+		return
+	}
+	costFuncsMux.Lock()
+	costFuncs[resource] = f
+	atomic.StoreInt32(&costFuncCount, int32(len(costFuncs)))
+	costFuncsMux.Unlock()
+
+	rebuildRulesOfResource(resource)
+	logging.Info("[FlowCost] Cost function registered", "resource", resource)
+}
+
+// UnregisterCostFunc removes the cost function of the resource.
+func UnregisterCostFunc(resource string) {
+	costFuncsMux.Lock()
+	_, exist := costFuncs[resource]
+	delete(costFuncs, resource)
+	atomic.StoreInt32(&costFuncCount, int32(len(costFuncs)))
+	costFuncsMux.Unlock()
+
+	if exist {
+		rebuildRulesOfResource(resource)
+		logging.Info("[FlowCost] Cost function unregistered", "resource", resource)
+	}
+}
+
+func costFuncOf(resource string) CostFunc {
+	if atomic.LoadInt32(&costFuncCount) == 0 {
+		return nil
+	}
+	costFuncsMux.RLock()
+	defer costFuncsMux.RUnlock()
+
+	return costFuncs[resource]
+}
+
+func hasCostFunc(resource string) bool {
+	return costFuncOf(resource) != nil
+}
+
+// acquireCountOf returns the token count of the entry for the flow rules, i.e. the cost if the resource
+// has the cost function, otherwise the acquire count. The cost is calculated once per entry.
+func acquireCountOf(ctx *base.EntryContext) uint32 {
+	if ctx.Data != nil {
+		if cost, ok := ctx.Data[costDataKey{}].(uint32); ok {
+			return cost
+		}
+	}
+	f := costFuncOf(ctx.Resource.Name())
+	if f == nil {
+		return ctx.Input.AcquireCount
+	}
+	cost := f(ctx)
+	if ctx.Data != nil {
+		ctx.Data[costDataKey{}] = cost
+	}
+	return cost
+}
+
+// rebuildRulesOfResource rebuilds the traffic controllers of the resource without reusing the old ones,
+// through the normal rule update.
+func rebuildRulesOfResource(resource string) {
+	defer dispatchRuleUpdates()
+
+	updateRuleMux.Lock()
+	defer updateRuleMux.Unlock()
+
+	tcMux.RLock()
+	_, exist := tcMap[resource]
+	tcMux.RUnlock()
+	if !exist {
+		return
+	}
+	if err := onRuleUpdateRebuilding(withNamespaceRules(defaultNamespaceRules), map[string]struct{}{resource: {}}); err != nil {
+		logging.Error(err, "[FlowCost] Failed to rebuild the flow rules", "resource", resource)
+	}
+}
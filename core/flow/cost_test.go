@@ -0,0 +1,60 @@
+package flow
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCostFunc(t *testing.T) {
+	defer ClearRules()
+	defer UnregisterCostFunc("abc-cost")
+
+	_, err := LoadRules([]*Rule{
+		{
+			Resource:               "abc-cost",
+			TokenCalculateStrategy: Direct,
+			ControlBehavior:        Reject,
+			Threshold:              10,
+		},
+	})
+	assert.Nil(t, err)
+	assert.True(t, getTrafficControllerListFor("abc-cost")[0].boundStat.reuseResourceStat)
+	version := RulesVersion()
+
+	RegisterCostFunc("abc-cost", func(ctx *base.EntryContext) uint32 {
+		return uint32(ctx.Input.Args[0].(int))
+	})
+	tc := getTrafficControllerListFor("abc-cost")[0]
+	assert.False(t, tc.boundStat.reuseResourceStat)
+	// rebuilt through the normal rule update
+	assert.True(t, RulesVersion() > version)
+	assert.Equal(t, 1, len(GetRules()))
+
+	slot, statSlot := &Slot{}, &StandaloneStatSlot{}
+	newCtx := func(size int) *base.EntryContext {
+		return &base.EntryContext{
+			Resource: base.NewResourceWrapper("abc-cost", base.ResTypeCommon, base.Inbound),
+			StatNode: stat.GetOrCreateResourceNode("abc-cost", base.ResTypeCommon),
+			Input: &base.SentinelInput{
+				AcquireCount: 1,
+				Args:         []interface{}{size},
+			},
+			Data: make(map[interface{}]interface{}),
+		}
+	}
+	for i := 0; i < 2; i++ {
+		ctx := newCtx(4)
+		assert.Nil(t, slot.Check(ctx))
+		statSlot.OnEntryPassed(ctx)
+	}
+	assert.Equal(t, int64(8), tc.boundStat.readOnlyMetric.GetSum(base.MetricEventPass))
+	ret := slot.Check(newCtx(4))
+	assert.True(t, ret != nil && ret.IsBlocked())
+	assert.Nil(t, slot.Check(newCtx(2)))
+
+	UnregisterCostFunc("abc-cost")
+	assert.True(t, getTrafficControllerListFor("abc-cost")[0].boundStat.reuseResourceStat)
+}
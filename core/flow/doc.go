@@ -7,10 +7,79 @@
 //  1. TrafficShapingCalculator calculates the actual traffic shaping token threshold. Currently, Sentinel supports two token calculate strategy: Direct and WarmUp.
 //  2. TrafficShapingChecker performs checking logic according to current metrics and the traffic shaping strategy, then yield the token result. Currently, Sentinel supports two control behavior: Reject and Throttling.
 //
+// WarmUp combined with Throttling is served by the dedicated WarmUpThrottlingChecker, which paces the requests
+// according to the warm-up rate changing with every passed request, so the rate ramps up without bursts.
+//
 // Besides, Sentinel supports customized TrafficShapingCalculator and TrafficShapingChecker. User could call function SetTrafficShapingGenerator to register customized TrafficShapingController and call function RemoveTrafficShapingGenerator to unregister TrafficShapingController.
 // There are a few notes users need to be aware of:
 //
 //  1. The function both SetTrafficShapingGenerator and RemoveTrafficShapingGenerator is not thread safe.
 //  2. Users can not override the Sentinel supported TrafficShapingController.
 //
+// The schedulers or batch jobs could reserve the capacity in advance by Reserve, which guarantees the admission
+// within the max wait time or fails immediately. The reservation is carried by the entry attachment:
+//
+//	r, err := flow.Reserve("some-api", 1, time.Second)
+//	if err != nil {
+//	    // The capacity is unavailable, plan the work later.
+//	}
+//	e, b := sentinel.Entry("some-api", sentinel.WithAttachment(flow.ReservationAttachmentKey, r))
+//
+// The fresh instances (e.g. the autoscaled pods) could ramp their admitted traffic gradually by the scale warm-up,
+// which is triggered by the deployment tooling via TriggerScaleWarmUp, or detected by the instance age on
+// initialization if config item flow.scaleWarmUpSec is set (see WarmUpFreshInstance):
+//
+//	_ = flow.TriggerScaleWarmUp(2*time.Minute, 3) // start from 1/3 of the thresholds
+//
+// The flow rules count the requests by default. For the bandwidth-style limits, register the cost function
+// of the resource by RegisterCostFunc, whose result is used as the token count instead:
+//
+//	flow.RegisterCostFunc("upload", func(ctx *base.EntryContext) uint32 {
+//	    return uint32(ctx.Input.Args[0].(int) / 1024) // KB
+//	})
+//
+// To cap the bandwidth, use the Throughput rules whose Threshold is the bytes of payload during StatIntervalInMs
+// (e.g. bytes per second). The payload size is reported by the entry attachment PayloadBytesAttachmentKey,
+// which is attached by the HTTP adapters automatically from the Content-Length of requests:
+//
+//	e, b := sentinel.Entry("upload", sentinel.WithAttachment(flow.PayloadBytesAttachmentKey, len(payload)))
+//
+// The rules could target the tags of resources instead of the names by TargetTag, so the platform policies apply to
+// the classes of resources automatically as new resources appear. The tags are attached by the entries (or by the
+// Tags of the adapter route config), and every resource with the tag gets its own controller of the rule:
+//
+//	flow.LoadRules([]*flow.Rule{{TargetTag: "tier=gold", Threshold: 1000}})
+//	e, b := sentinel.Entry("GET:/api/orders", sentinel.WithTags("tier=gold", "team=order"))
+//
+// The read and write requests of a data service usually need different limits. The entries could be categorized
+// by api.WithCategory, and the rules with Category only take effect on the requests of the category, counted by
+// the separate statistic of the category:
+//
+//	flow.LoadRules([]*flow.Rule{
+//		{Resource: "user-db", Category: base.CategoryRead, Threshold: 5000},
+//		{Resource: "user-db", Category: base.CategoryWrite, Threshold: 500},
+//	})
+//	e, b := sentinel.Entry("user-db", sentinel.WithCategory(base.CategoryWrite))
+//
+// The risky threshold changes could be rolled out to a percentage of the requests by StartCanary first,
+// and the comparative block ratio is reported by CanaryStatOf. The new rules replace the current rules of the resource
+// after the bake period, unless the canary is aborted:
+//
+//	flow.StartCanary("some-api", []*flow.Rule{{Resource: "some-api", Threshold: 500}}, 10, 10*time.Minute)
+//	s, _ := flow.CanaryStatOf("some-api") // compare s.Canary.BlockRatio with s.Baseline.BlockRatio
+//	flow.AbortCanary("some-api")
+//
+// The rules could have a soft limit by WarningCount below the Threshold. The requests beyond it are not blocked,
+// but counted by RuleStat.NearLimitCount, and the warning events are emitted to the listeners registered
+// by RegisterWarningListeners, giving the lead time to scale before the hard rejection begins.
+//
+// Instead of filling the Rule struct by hand, the rules could be built fluently by RuleBuilder, which requires
+// the threshold to be set explicitly and reports the invalid combinations at Build:
+//
+//	rule, err := flow.NewRuleBuilder("some-api").QPS(100).Throttling(500 * time.Millisecond).Build()
+//
+// Besides replacing all the rules by LoadRules, the loaded rules could be updated individually by AppendRule,
+// UpdateRule (which replaces the rule of the same ID) and RemoveRuleOfResource. The changes of the effective rules
+// are notified to the listeners registered by RegisterRuleUpdateListener, e.g. for auditing or syncing the rules.
+//
 package flow
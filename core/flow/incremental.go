@@ -0,0 +1,112 @@
+package flow
+
+import (
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+	"github.com/pkg/errors"
+)
+
+// AppendRule appends the flow rule to the rules loaded by LoadRules, while the other rules are kept.
+// The invalid rule is rejected with the validation error (see IsValidRule). The rule is deduplicated:
+// it returns false without any update if an identical rule has been loaded, and an error if a different rule
+// has been loaded with the same ID (use UpdateRule instead).
+func AppendRule(rule *Rule) (bool, error) {
+	if err := IsValidRule(rule); err != nil {
+		return false, err
+	}
+	defer selfmetric.RecordRuleUpdate("flow", time.Now())
+	defer dispatchRuleUpdates()
+
+	updateRuleMux.Lock()
+	defer updateRuleMux.Unlock()
+
+	for _, r := range defaultNamespaceRules {
+		if isDuplicateRule(r, rule) {
+			return false, nil
+		}
+		if len(rule.ID) > 0 && r.ID == rule.ID {
+			return false, errors.Errorf("duplicate flow rule of ID %s", rule.ID)
+		}
+	}
+	rules := make([]*Rule, 0, len(defaultNamespaceRules)+1)
+	rules = append(rules, defaultNamespaceRules...)
+	return true, reloadDefaultRules(append(rules, rule))
+}
+
+// UpdateRule replaces the loaded flow rule of the same ID with the given rule, so the ID of the rule is required.
+// The invalid rule is rejected with the validation error (see IsValidRule).
+// It returns false without any update if the loaded rule is identical to the given rule,
+// and an error if no rule of the ID has been loaded.
+func UpdateRule(rule *Rule) (bool, error) {
+	if err := IsValidRule(rule); err != nil {
+		return false, err
+	}
+	if len(rule.ID) == 0 {
+		return false, errors.New("empty ID of the flow rule to update")
+	}
+	defer selfmetric.RecordRuleUpdate("flow", time.Now())
+	defer dispatchRuleUpdates()
+
+	updateRuleMux.Lock()
+	defer updateRuleMux.Unlock()
+
+	idx := -1
+	for i, r := range defaultNamespaceRules {
+		if r.ID == rule.ID {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return false, errors.Errorf("no flow rule of ID %s", rule.ID)
+	}
+	if isDuplicateRule(defaultNamespaceRules[idx], rule) {
+		return false, nil
+	}
+	rules := make([]*Rule, 0, len(defaultNamespaceRules))
+	for i, r := range defaultNamespaceRules {
+		if i == idx {
+			rules = append(rules, rule)
+		} else if r.ID != rule.ID {
+			rules = append(rules, r)
+		}
+	}
+	return true, reloadDefaultRules(rules)
+}
+
+// RemoveRuleOfResource removes the loaded flow rules of the resource, while the other rules are kept.
+// It returns false if no rule of the resource has been loaded.
+func RemoveRuleOfResource(resource string) (bool, error) {
+	defer selfmetric.RecordRuleUpdate("flow", time.Now())
+	defer dispatchRuleUpdates()
+
+	updateRuleMux.Lock()
+	defer updateRuleMux.Unlock()
+
+	rules := make([]*Rule, 0, len(defaultNamespaceRules))
+	for _, r := range defaultNamespaceRules {
+		if r == nil || r.Resource != resource {
+			rules = append(rules, r)
+		}
+	}
+	if len(rules) == len(defaultNamespaceRules) {
+		return false, nil
+	}
+	return true, reloadDefaultRules(rules)
+}
+
+// reloadDefaultRules replaces the rules loaded by LoadRules, which must be called with updateRuleMux locked.
+func reloadDefaultRules(rules []*Rule) error {
+	defaultNamespaceRules = rules
+	err := onRuleUpdate(withNamespaceRules(rules))
+	if err == nil {
+		base.SaveRulesToStore("flow", rules)
+	}
+	return err
+}
+
+func isDuplicateRule(r, rule *Rule) bool {
+	return r != nil && r.ID == rule.ID && r.DeploymentLabel == rule.DeploymentLabel && r.isEqualsTo(rule)
+}
@@ -0,0 +1,87 @@
+package flow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendRule(t *testing.T) {
+	defer func() { _ = ClearRules() }()
+
+	_, err := LoadRules([]*Rule{{ID: "1", Resource: "abc", Threshold: 10}})
+	assert.Nil(t, err)
+
+	updated, err := AppendRule(&Rule{ID: "2", Resource: "def", Threshold: 20})
+	assert.Nil(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, 1, len(getRulesOfResource("abc")))
+	assert.Equal(t, 1, len(getRulesOfResource("def")))
+
+	t.Run("Duplicate", func(t *testing.T) {
+		updated, err := AppendRule(&Rule{ID: "2", Resource: "def", Threshold: 20})
+		assert.Nil(t, err)
+		assert.False(t, updated)
+		assert.Equal(t, 1, len(getRulesOfResource("def")))
+
+		updated, err = AppendRule(&Rule{ID: "2", Resource: "def", Threshold: 30})
+		assert.NotNil(t, err)
+		assert.False(t, updated)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		updated, err := AppendRule(&Rule{Resource: "ghi", Threshold: -1})
+		assert.NotNil(t, err)
+		assert.False(t, updated)
+		updated, err = AppendRule(nil)
+		assert.NotNil(t, err)
+		assert.False(t, updated)
+	})
+}
+
+func TestUpdateRule(t *testing.T) {
+	defer func() { _ = ClearRules() }()
+
+	_, err := LoadRules([]*Rule{{ID: "1", Resource: "abc", Threshold: 10}, {ID: "2", Resource: "def", Threshold: 20}})
+	assert.Nil(t, err)
+
+	updated, err := UpdateRule(&Rule{ID: "1", Resource: "abc", Threshold: 5})
+	assert.Nil(t, err)
+	assert.True(t, updated)
+	rules := getRulesOfResource("abc")
+	assert.Equal(t, 1, len(rules))
+	assert.Equal(t, 5.0, rules[0].Threshold)
+	assert.Equal(t, 1, len(getRulesOfResource("def")))
+
+	updated, err = UpdateRule(&Rule{ID: "1", Resource: "abc", Threshold: 5})
+	assert.Nil(t, err)
+	assert.False(t, updated)
+
+	_, err = UpdateRule(&Rule{ID: "3", Resource: "abc", Threshold: 5})
+	assert.NotNil(t, err)
+	_, err = UpdateRule(&Rule{Resource: "abc", Threshold: 5})
+	assert.NotNil(t, err)
+	_, err = UpdateRule(&Rule{ID: "1", Resource: "abc", Threshold: -1})
+	assert.NotNil(t, err)
+	assert.Equal(t, 5.0, getRulesOfResource("abc")[0].Threshold)
+}
+
+func TestRemoveRuleOfResource(t *testing.T) {
+	defer func() { _ = ClearRules() }()
+
+	_, err := LoadRules([]*Rule{{Resource: "abc", Threshold: 10}, {Resource: "abc", Threshold: 20, StatIntervalInMs: 20000}, {Resource: "def", Threshold: 20}})
+	assert.Nil(t, err)
+	_, err = LoadRulesOfNamespace("ns", []*Rule{{Resource: "ns::abc", Threshold: 10}})
+	assert.Nil(t, err)
+
+	removed, err := RemoveRuleOfResource("abc")
+	assert.Nil(t, err)
+	assert.True(t, removed)
+	assert.Equal(t, 0, len(getRulesOfResource("abc")))
+	assert.Equal(t, 1, len(getRulesOfResource("def")))
+	assert.Equal(t, 1, len(getRulesOfResource("ns::abc")))
+
+	removed, err = RemoveRuleOfResource("abc")
+	assert.Nil(t, err)
+	assert.False(t, removed)
+}
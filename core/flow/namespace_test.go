@@ -0,0 +1,44 @@
+package flow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadRulesOfNamespace(t *testing.T) {
+	defer func() { _ = ClearRules() }()
+
+	_, err := LoadRulesOfNamespace("ns1", []*Rule{{Resource: "ns1::abc", Threshold: 10}})
+	assert.Nil(t, err)
+	_, err = LoadRules([]*Rule{{Resource: "abc", Threshold: 20}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(getRulesOfResource("ns1::abc")))
+	assert.Equal(t, 1, len(getRulesOfResource("abc")))
+
+	t.Run("ReplaceNamespace", func(t *testing.T) {
+		_, err := LoadRulesOfNamespace("ns1", []*Rule{{Resource: "ns1::def", Threshold: 10}})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(getRulesOfResource("ns1::abc")))
+		assert.Equal(t, 1, len(getRulesOfResource("ns1::def")))
+		assert.Equal(t, 1, len(getRulesOfResource("abc")))
+	})
+
+	t.Run("ClearNamespace", func(t *testing.T) {
+		_, err := LoadRulesOfNamespace("ns1", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(getRulesOfResource("ns1::def")))
+		assert.Equal(t, 1, len(getRulesOfResource("abc")))
+	})
+
+	t.Run("ClearRules", func(t *testing.T) {
+		_, err := LoadRulesOfNamespace("ns2", []*Rule{{Resource: "ns2::abc", Threshold: 10}})
+		assert.Nil(t, err)
+		assert.Nil(t, ClearRules())
+		assert.Equal(t, 0, len(getRulesOfResource("ns2::abc")))
+		assert.Equal(t, 0, len(getRulesOfResource("abc")))
+		_, err = LoadRules(nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(getRulesOfResource("ns2::abc")))
+	})
+}
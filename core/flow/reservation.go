@@ -0,0 +1,177 @@
+package flow
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+// ReservationAttachmentKey is the key of the entry attachment that carries the *Reservation of the entry.
+// The entry with a valid reservation waits until the reservation is ready and then passes the flow rules
+// without checking, as the capacity has already been reserved.
+const ReservationAttachmentKey = "sentinel.flow.reservation"
+
+// ReservationGracePeriod is the period that a reservation stays valid after it's ready.
+// The reservation not used within the period is cancelled automatically, which releases the reserved capacity.
+var ReservationGracePeriod = time.Second
+
+// ErrReservationUnavailable indicates the capacity can't be reserved within the max wait time.
+var ErrReservationUnavailable = errors.New("flow capacity is unavailable within the max wait time")
+
+const (
+	reservationPending int32 = iota
+	reservationConsumed
+	reservationCancelled
+)
+
+// tokenReserver is implemented by the TrafficShapingChecker that supports capacity reservation.
+type tokenReserver interface {
+	// reserve reserves acquireCount tokens within maxWaitNs, and returns the wait time (in nanoseconds)
+	// until the tokens are available.
+	reserve(acquireCount uint32, threshold float64, maxWaitNs uint64) (waitNs uint64, ok bool)
+	// onReservationConsumed is invoked when the entry with the reservation passes.
+	onReservationConsumed(acquireCount uint32, threshold float64)
+	// cancelReservation releases the reserved tokens.
+	cancelReservation(acquireCount uint32, threshold float64)
+}
+
+type reservationHold struct {
+	reserver  tokenReserver
+	threshold float64
+}
+
+// Reservation is the capacity reserved by Reserve, which guarantees the admission of the flow rules
+// once it's ready. The reservation should be carried by the entry with the attachment ReservationAttachmentKey,
+// or be cancelled if not used.
+type Reservation struct {
+	resource  string
+	count     uint32
+	readyTime uint64
+	holds     []reservationHold
+	// rulesVersion is the version of the rules that the holds belong to, the holds are stale once it changes.
+	rulesVersion uint64
+	state        int32
+	timer        *time.Timer
+}
+
+// Reserve reserves the capacity of n requests of the resource in the flow rules, the reservation becomes
+// ready (i.e. the requests are guaranteed to pass the flow rules) within maxWait, otherwise it fails immediately
+// with ErrReservationUnavailable. It allows the schedulers or batch jobs to plan work against the current capacity
+// rather than fire and get blocked.
+//
+// For the Throttling rules the requests are queued in advance, while for the Reject rules the reservation
+// succeeds only if the capacity is available right now. The rules with customized TrafficShapingChecker
+// don't support reservation. The reservation is released if the flow rules are reloaded before it's used,
+// and the entry carrying it is checked as usual.
+func Reserve(resource string, n uint32, maxWait time.Duration) (*Reservation, error) {
+	if n == 0 {
+		return nil, errors.New("reserved count must be positive")
+	}
+	if maxWait < 0 {
+		maxWait = 0
+	}
+	// The version is read before the controllers, so that the holds are never newer than the version.
+	version := RulesVersion()
+	holds := make([]reservationHold, 0)
+	waitNs := uint64(0)
+	for _, tc := range getTrafficControllerListFor(resource) {
+		reserver, ok := tc.flowChecker.(tokenReserver)
+		if !ok {
+			cancelHolds(holds, n)
+			return nil, errors.Errorf("reservation is unsupported by the flow rule: %s", tc.rule.String())
+		}
+		threshold := tc.flowCalculator.CalculateAllowedTokens(n, 0) * thresholdScaleFactorOf(resource)
+		wait, ok := reserver.reserve(n, threshold, uint64(maxWait.Nanoseconds()))
+		if !ok {
+			cancelHolds(holds, n)
+			return nil, ErrReservationUnavailable
+		}
+		holds = append(holds, reservationHold{reserver: reserver, threshold: threshold})
+		if wait > waitNs {
+			waitNs = wait
+		}
+	}
+	r := &Reservation{
+		resource:     resource,
+		count:        n,
+		readyTime:    util.CurrentTimeNano() + waitNs,
+		holds:        holds,
+		rulesVersion: version,
+		state:        reservationPending,
+	}
+	r.timer = time.AfterFunc(time.Duration(waitNs)+ReservationGracePeriod, r.Cancel)
+	return r, nil
+}
+
+func cancelHolds(holds []reservationHold, n uint32) {
+	for _, h := range holds {
+		h.reserver.cancelReservation(n, h.threshold)
+	}
+}
+
+// Resource returns the resource of the reservation.
+func (r *Reservation) Resource() string {
+	return r.resource
+}
+
+// Count returns the reserved count of requests.
+func (r *Reservation) Count() uint32 {
+	return r.count
+}
+
+// Delay returns the duration until the reservation is ready, 0 means it's ready now.
+func (r *Reservation) Delay() time.Duration {
+	now := util.CurrentTimeNano()
+	if now >= r.readyTime {
+		return 0
+	}
+	return time.Duration(r.readyTime - now)
+}
+
+// Wait blocks until the reservation is ready.
+func (r *Reservation) Wait() {
+	if d := r.Delay(); d > 0 {
+		time.Sleep(d)
+	}
+}
+
+// Cancel releases the reserved capacity if the reservation hasn't been used.
+func (r *Reservation) Cancel() {
+	if !atomic.CompareAndSwapInt32(&r.state, reservationPending, reservationCancelled) {
+		return
+	}
+	r.timer.Stop()
+	cancelHolds(r.holds, r.count)
+}
+
+// tryConsume marks the reservation as used by the entry of the resource with acquireCount.
+// If the rules have been reloaded since the reservation was made, the reserved capacity of the old controllers
+// is released and the entry falls back to the normal checking.
+func (r *Reservation) tryConsume(resource string, acquireCount uint32) bool {
+	if r.resource != resource || acquireCount > r.count {
+		return false
+	}
+	if RulesVersion() != r.rulesVersion {
+		r.Cancel()
+		return false
+	}
+	if !atomic.CompareAndSwapInt32(&r.state, reservationPending, reservationConsumed) {
+		return false
+	}
+	r.timer.Stop()
+	for _, h := range r.holds {
+		h.reserver.onReservationConsumed(r.count, h.threshold)
+	}
+	return true
+}
+
+func reservationOf(ctx *base.EntryContext) *Reservation {
+	if ctx.Input == nil || ctx.Input.Attachments == nil {
+		return nil
+	}
+	r, _ := ctx.Input.Attachments[ReservationAttachmentKey].(*Reservation)
+	return r
+}
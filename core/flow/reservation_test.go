@@ -0,0 +1,120 @@
+package flow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReserve_Reject(t *testing.T) {
+	defer ClearRules()
+	_, err := LoadRules([]*Rule{
+		{
+			Resource:               "abc-reserve",
+			TokenCalculateStrategy: Direct,
+			ControlBehavior:        Reject,
+			StatIntervalInMs:       20000,
+			Threshold:              10,
+		},
+	})
+	assert.Nil(t, err)
+
+	r, err := Reserve("abc-reserve", 8, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), r.Delay())
+	_, err = Reserve("abc-reserve", 3, time.Second)
+	assert.Equal(t, ErrReservationUnavailable, err)
+
+	slot := &Slot{}
+	newCtx := func(acquireCount uint32, reservation *Reservation) *base.EntryContext {
+		return &base.EntryContext{
+			Resource: base.NewResourceWrapper("abc-reserve", base.ResTypeCommon, base.Inbound),
+			StatNode: stat.GetOrCreateResourceNode("abc-reserve", base.ResTypeCommon),
+			Input: &base.SentinelInput{
+				AcquireCount: acquireCount,
+				Attachments:  map[interface{}]interface{}{ReservationAttachmentKey: reservation},
+			},
+		}
+	}
+	// the capacity is occupied by the reservation
+	ret := slot.Check(newCtx(3, nil))
+	assert.True(t, ret != nil && ret.IsBlocked())
+	assert.Nil(t, slot.Check(newCtx(8, r)))
+	checker := getTrafficControllerListFor("abc-reserve")[0].flowChecker.(*RejectTrafficShapingChecker)
+	assert.Equal(t, int64(0), checker.reserved)
+
+	r2, err := Reserve("abc-reserve", 10, 0)
+	assert.Nil(t, err)
+	r2.Cancel()
+	assert.Equal(t, int64(0), checker.reserved)
+	// the cancelled reservation can't be used
+	ret = slot.Check(newCtx(11, r2))
+	assert.True(t, ret != nil && ret.IsBlocked())
+}
+
+func TestReserve_Throttling(t *testing.T) {
+	defer ClearRules()
+	_, err := LoadRules([]*Rule{
+		{
+			Resource:               "abc-reserve",
+			TokenCalculateStrategy: Direct,
+			ControlBehavior:        Throttling,
+			Threshold:              10,
+			MaxQueueingTimeMs:      10,
+		},
+	})
+	assert.Nil(t, err)
+
+	r1, err := Reserve("abc-reserve", 1, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), r1.Delay())
+	_, err = Reserve("abc-reserve", 1, 50*time.Millisecond)
+	assert.Equal(t, ErrReservationUnavailable, err)
+
+	r2, err := Reserve("abc-reserve", 1, 200*time.Millisecond)
+	assert.Nil(t, err)
+	assert.True(t, r2.Delay() > 50*time.Millisecond && r2.Delay() <= 100*time.Millisecond)
+	r2.Cancel()
+	r3, err := Reserve("abc-reserve", 1, 150*time.Millisecond)
+	assert.Nil(t, err)
+	r3.Cancel()
+}
+
+func TestReserve_RulesReloaded(t *testing.T) {
+	defer ClearRules()
+	rule := &Rule{
+		Resource:               "abc-reserve",
+		TokenCalculateStrategy: Direct,
+		ControlBehavior:        Reject,
+		StatIntervalInMs:       20000,
+		Threshold:              10,
+	}
+	_, err := LoadRules([]*Rule{rule})
+	assert.Nil(t, err)
+
+	r, err := Reserve("abc-reserve", 8, 0)
+	assert.Nil(t, err)
+	oldChecker := getTrafficControllerListFor("abc-reserve")[0].flowChecker.(*RejectTrafficShapingChecker)
+	assert.Equal(t, int64(8), oldChecker.reserved)
+
+	reloaded := *rule
+	reloaded.Threshold = 5
+	_, err = LoadRules([]*Rule{&reloaded})
+	assert.Nil(t, err)
+
+	// the reservation of the old controllers is released, and the entry is checked by the new controllers
+	ctx := &base.EntryContext{
+		Resource: base.NewResourceWrapper("abc-reserve", base.ResTypeCommon, base.Inbound),
+		StatNode: stat.GetOrCreateResourceNode("abc-reserve", base.ResTypeCommon),
+		Input: &base.SentinelInput{
+			AcquireCount: 8,
+			Attachments:  map[interface{}]interface{}{ReservationAttachmentKey: r},
+		},
+	}
+	ret := (&Slot{}).Check(ctx)
+	assert.True(t, ret != nil && ret.IsBlocked())
+	assert.Equal(t, int64(0), oldChecker.reserved)
+}
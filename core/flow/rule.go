@@ -3,6 +3,8 @@ package flow
 import (
 	"encoding/json"
 	"fmt"
+
+	"github.com/alibaba/sentinel-golang/core/base"
 )
 
 // RelationStrategy indicates the flow control strategy based on the relation of invocations.
@@ -44,6 +46,32 @@ func (s TokenCalculateStrategy) String() string {
 	}
 }
 
+// WarmUpCurve indicates the shape of the threshold curve during the warm-up period.
+type WarmUpCurve int32
+
+const (
+	// TokenBucketCurve is the Guava-like token bucket curve, which is the default warm-up curve.
+	TokenBucketCurve WarmUpCurve = iota
+	// LinearCurve raises the threshold linearly from the cold threshold to the rule threshold.
+	LinearCurve
+	// ExponentialCurve raises the threshold exponentially from the cold threshold to the rule threshold,
+	// which ramps slowly at first and quickly near the end of the warm-up period.
+	ExponentialCurve
+)
+
+func (c WarmUpCurve) String() string {
+	switch c {
+	case TokenBucketCurve:
+		return "TokenBucket"
+	case LinearCurve:
+		return "Linear"
+	case ExponentialCurve:
+		return "Exponential"
+	default:
+		return "Undefined"
+	}
+}
+
 type ControlBehavior int32
 
 const (
@@ -62,12 +90,43 @@ func (s ControlBehavior) String() string {
 	}
 }
 
+// MetricType indicates what the threshold of the flow rule measures.
+type MetricType int32
+
+const (
+	// RequestCount means the threshold is the count of requests (or the cost, see RegisterCostFunc)
+	// during StatIntervalInMs, which is the default metric type.
+	RequestCount MetricType = iota
+	// Throughput means the threshold is the bytes of payload during StatIntervalInMs,
+	// e.g. bytes per second. The payload size is reported by the entry attachment PayloadBytesAttachmentKey.
+	Throughput
+)
+
+func (t MetricType) String() string {
+	switch t {
+	case RequestCount:
+		return "RequestCount"
+	case Throughput:
+		return "Throughput"
+	default:
+		return "Undefined"
+	}
+}
+
 // Rule describes the strategy of flow control, the flow control strategy is based on QPS statistic metric
 type Rule struct {
 	// ID represents the unique ID of the rule (optional).
 	ID string `json:"id,omitempty"`
 	// Resource represents the resource name.
-	Resource               string                 `json:"resource"`
+	Resource string `json:"resource"`
+	// TargetTag makes the rule target all the resources with the tag (e.g. "tier=gold", see api.WithTags)
+	// instead of a single resource, so the rule applies to the newly tagged resources automatically.
+	// Resource must be empty if TargetTag is set.
+	TargetTag string `json:"targetTag,omitempty"`
+	// Category makes the rule only take effect on the requests of the category (see api.WithCategory)
+	// and count them by the statistic of the category, e.g. the different limits for the read and write requests.
+	// CategoryNone (by default) means all the requests of the resource.
+	Category               base.RequestCategory   `json:"category,omitempty"`
 	TokenCalculateStrategy TokenCalculateStrategy `json:"tokenCalculateStrategy"`
 	ControlBehavior        ControlBehavior        `json:"controlBehavior"`
 	// Threshold means the threshold during StatIntervalInMs
@@ -76,13 +135,40 @@ type Rule struct {
 	RelationStrategy  RelationStrategy `json:"relationStrategy"`
 	RefResource       string           `json:"refResource"`
 	MaxQueueingTimeMs uint32           `json:"maxQueueingTimeMs"`
-	WarmUpPeriodSec   uint32           `json:"warmUpPeriodSec"`
-	WarmUpColdFactor  uint32           `json:"warmUpColdFactor"`
+	// MaxQueueingRequests is the max amount of requests waiting in queue for Throttling (optional).
+	// Requests beyond it will be rejected immediately, 0 means no limit.
+	MaxQueueingRequests uint32 `json:"maxQueueingRequests"`
+	WarmUpPeriodSec     uint32 `json:"warmUpPeriodSec"`
+	WarmUpColdFactor    uint32 `json:"warmUpColdFactor"`
+	// WarmUpCurve indicates the shape of the threshold curve during warm-up, TokenBucketCurve by default.
+	WarmUpCurve WarmUpCurve `json:"warmUpCurve"`
+	// ColdStartCount is the floor of the threshold during warm-up (optional).
+	// The allowed threshold will never be less than ColdStartCount, 0 means no floor.
+	ColdStartCount float64 `json:"coldStartCount"`
+	// WarmUpRestartIdleFactor enables the warm-up restart detection (optional). If the resource has been idle
+	// for longer than WarmUpRestartIdleFactor × WarmUpPeriodSec, the warm-up curve is re-entered from the coldest
+	// state, as the cold dependency (e.g. drained connection pools, empty caches) needs the gentle ramp again.
+	// 0 means disabled.
+	WarmUpRestartIdleFactor uint32 `json:"warmUpRestartIdleFactor,omitempty"`
 	// StatIntervalInMs indicates the statistic interval and it's the optional setting for flow Rule.
 	// If user doesn't set StatIntervalInMs, that means using default metric statistic of resource.
 	// If the StatIntervalInMs user specifies can not reuse the global statistic of resource,
 	// 		sentinel will generate independent statistic structure for this rule.
 	StatIntervalInMs uint32 `json:"statIntervalInMs"`
+	// DeploymentLabel indicates that the rule takes effect only if current process has the label (see config.AppLabels),
+	// and overrides the rules without deployment label of the same resource. Empty means the rule always takes effect.
+	DeploymentLabel string `json:"deploymentLabel,omitempty"`
+	// MetricType indicates what the Threshold measures, RequestCount by default.
+	// The Throughput rules always use the standalone statistic.
+	MetricType MetricType `json:"metricType,omitempty"`
+	// SharedStatKey makes the rule count the requests of all the processes on the host by the shared statistic
+	// of the key (see SetSharedStatFactory), so that the Threshold is the per-host limit rather than the per-process one,
+	// e.g. for the prefork servers. Empty means the statistic of current process.
+	SharedStatKey string `json:"sharedStatKey,omitempty"`
+	// WarningCount is the soft limit below Threshold (optional). The requests beyond it are not blocked,
+	// but the warning events are emitted (see RegisterWarningListeners) and counted as near-limit (see RuleStat),
+	// giving the lead time to scale before the hard rejection begins. 0 means disabled.
+	WarningCount float64 `json:"warningCount,omitempty"`
 }
 
 func (r *Rule) isEqualsTo(newRule *Rule) bool {
@@ -92,7 +178,10 @@ func (r *Rule) isEqualsTo(newRule *Rule) bool {
 	if !(r.Resource == newRule.Resource && r.RelationStrategy == newRule.RelationStrategy &&
 		r.RefResource == newRule.RefResource && r.StatIntervalInMs == newRule.StatIntervalInMs &&
 		r.TokenCalculateStrategy == newRule.TokenCalculateStrategy && r.ControlBehavior == newRule.ControlBehavior && r.Threshold == newRule.Threshold &&
-		r.MaxQueueingTimeMs == newRule.MaxQueueingTimeMs && r.WarmUpPeriodSec == newRule.WarmUpPeriodSec && r.WarmUpColdFactor == newRule.WarmUpColdFactor) {
+		r.MaxQueueingTimeMs == newRule.MaxQueueingTimeMs && r.MaxQueueingRequests == newRule.MaxQueueingRequests && r.WarmUpPeriodSec == newRule.WarmUpPeriodSec && r.WarmUpColdFactor == newRule.WarmUpColdFactor &&
+		r.WarmUpCurve == newRule.WarmUpCurve && r.ColdStartCount == newRule.ColdStartCount && r.MetricType == newRule.MetricType &&
+		r.WarmUpRestartIdleFactor == newRule.WarmUpRestartIdleFactor && r.TargetTag == newRule.TargetTag && r.SharedStatKey == newRule.SharedStatKey &&
+		r.WarningCount == newRule.WarningCount && r.Category == newRule.Category) {
 		return false
 	}
 	return true
@@ -103,21 +192,28 @@ func (r *Rule) isStatReusable(newRule *Rule) bool {
 		return false
 	}
 	return r.Resource == newRule.Resource && r.RelationStrategy == newRule.RelationStrategy &&
-		r.RefResource == newRule.RefResource && r.StatIntervalInMs == newRule.StatIntervalInMs
+		r.RefResource == newRule.RefResource && r.StatIntervalInMs == newRule.StatIntervalInMs && r.MetricType == newRule.MetricType &&
+		r.SharedStatKey == newRule.SharedStatKey && r.Category == newRule.Category
+}
+
+// matchesCategory checks whether the rule takes effect on the requests of the given category.
+func (r *Rule) matchesCategory(category base.RequestCategory) bool {
+	return r.Category == base.CategoryNone || r.Category == category
 }
 
 func (r *Rule) needStatistic() bool {
-	return !(r.TokenCalculateStrategy == Direct && r.ControlBehavior == Throttling)
+	// Throttling paces the requests by itself, including the WarmUpThrottlingChecker.
+	return r.ControlBehavior != Throttling
 }
 
 func (r *Rule) String() string {
 	b, err := json.Marshal(r)
 	if err != nil {
 		// Return the fallback string
-		return fmt.Sprintf("Rule{Resource=%s, TokenCalculateStrategy=%s, ControlBehavior=%s, "+
-			"Threshold=%.2f, RelationStrategy=%s, RefResource=%s, MaxQueueingTimeMs=%d, WarmUpPeriodSec=%d, WarmUpColdFactor=%d, StatIntervalInMs=%d}",
-			r.Resource, r.TokenCalculateStrategy, r.ControlBehavior, r.Threshold, r.RelationStrategy, r.RefResource,
-			r.MaxQueueingTimeMs, r.WarmUpPeriodSec, r.WarmUpColdFactor, r.StatIntervalInMs)
+		return fmt.Sprintf("Rule{Resource=%s, TargetTag=%s, TokenCalculateStrategy=%s, ControlBehavior=%s, "+
+			"Threshold=%.2f, RelationStrategy=%s, RefResource=%s, MaxQueueingTimeMs=%d, MaxQueueingRequests=%d, WarmUpPeriodSec=%d, WarmUpColdFactor=%d, WarmUpCurve=%s, ColdStartCount=%.2f, WarmUpRestartIdleFactor=%d, StatIntervalInMs=%d, MetricType=%s, WarningCount=%.2f, Category=%s}",
+			r.Resource, r.TargetTag, r.TokenCalculateStrategy, r.ControlBehavior, r.Threshold, r.RelationStrategy, r.RefResource,
+			r.MaxQueueingTimeMs, r.MaxQueueingRequests, r.WarmUpPeriodSec, r.WarmUpColdFactor, r.WarmUpCurve, r.ColdStartCount, r.WarmUpRestartIdleFactor, r.StatIntervalInMs, r.MetricType, r.WarningCount, r.Category)
 	}
 	return string(b)
 }
@@ -0,0 +1,204 @@
+package flow
+
+import (
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+
+	"github.com/pkg/errors"
+)
+
+// RuleBuilder builds the flow rule fluently, e.g.
+//
+//	rule, err := flow.NewRuleBuilder("GET:/api/users").QPS(100).Throttling(500 * time.Millisecond).Build()
+//
+// Unlike filling the Rule struct by hand, where the zero values silently mean something (e.g. a zero Threshold
+// blocks all the requests), the builder requires the threshold to be set explicitly, and reports the invalid
+// combinations (e.g. the queueing options of the Reject rules) at Build.
+type RuleBuilder struct {
+	rule Rule
+	err  error
+
+	thresholdSet bool
+	behaviorSet  bool
+	// the options only available to Throttling or WarmUp, which are checked at Build
+	throttlingOnly []string
+	warmUpOnly     []string
+}
+
+// NewRuleBuilder creates the builder of the flow rule of the resource.
+func NewRuleBuilder(resource string) *RuleBuilder {
+	return &RuleBuilder{
+		rule: Rule{
+			Resource:               resource,
+			TokenCalculateStrategy: Direct,
+			ControlBehavior:        Reject,
+			RelationStrategy:       CurrentResource,
+			MetricType:             RequestCount,
+		},
+	}
+}
+
+func (b *RuleBuilder) fail(format string, args ...interface{}) *RuleBuilder {
+	if b.err == nil {
+		b.err = errors.Errorf(format, args...)
+	}
+	return b
+}
+
+func (b *RuleBuilder) setThreshold(name string, threshold float64, interval time.Duration, metricType MetricType) *RuleBuilder {
+	if b.thresholdSet {
+		return b.fail("%s: threshold has been set", name)
+	}
+	intervalInMs, ok := durationInMs(interval)
+	if !ok || intervalInMs == 0 {
+		return b.fail("%s: interval must be a positive multiple of millisecond, got %v", name, interval)
+	}
+	b.thresholdSet = true
+	b.rule.Threshold = threshold
+	b.rule.StatIntervalInMs = intervalInMs
+	b.rule.MetricType = metricType
+	return b
+}
+
+func (b *RuleBuilder) setBehavior(name string, behavior ControlBehavior) *RuleBuilder {
+	if b.behaviorSet && b.rule.ControlBehavior != behavior {
+		return b.fail("%s: control behavior has been set to %s", name, b.rule.ControlBehavior)
+	}
+	b.behaviorSet = true
+	b.rule.ControlBehavior = behavior
+	return b
+}
+
+// ID sets the unique ID of the rule.
+func (b *RuleBuilder) ID(id string) *RuleBuilder {
+	b.rule.ID = id
+	return b
+}
+
+// QPS allows qps requests per second.
+func (b *RuleBuilder) QPS(qps float64) *RuleBuilder {
+	return b.setThreshold("QPS", qps, time.Second, RequestCount)
+}
+
+// Threshold allows threshold requests during the interval.
+func (b *RuleBuilder) Threshold(threshold float64, interval time.Duration) *RuleBuilder {
+	return b.setThreshold("Threshold", threshold, interval, RequestCount)
+}
+
+// BytesPerSecond allows bytesPerSecond bytes of payload per second, see Throughput.
+func (b *RuleBuilder) BytesPerSecond(bytesPerSecond float64) *RuleBuilder {
+	return b.setThreshold("BytesPerSecond", bytesPerSecond, time.Second, Throughput)
+}
+
+// Reject rejects the requests beyond the threshold immediately, which is the default behavior.
+func (b *RuleBuilder) Reject() *RuleBuilder {
+	return b.setBehavior("Reject", Reject)
+}
+
+// Throttling queues the requests beyond the threshold at a uniform pace, for at most maxQueueingTime.
+func (b *RuleBuilder) Throttling(maxQueueingTime time.Duration) *RuleBuilder {
+	ms, ok := durationInMs(maxQueueingTime)
+	if !ok {
+		return b.fail("Throttling: max queueing time must be a non-negative multiple of millisecond, got %v", maxQueueingTime)
+	}
+	b.rule.MaxQueueingTimeMs = ms
+	return b.setBehavior("Throttling", Throttling)
+}
+
+// MaxQueueingRequests limits the amount of requests waiting in queue, only available to Throttling.
+func (b *RuleBuilder) MaxQueueingRequests(n uint32) *RuleBuilder {
+	b.throttlingOnly = append(b.throttlingOnly, "MaxQueueingRequests")
+	b.rule.MaxQueueingRequests = n
+	return b
+}
+
+// WarmUp raises the threshold gradually from threshold/coldFactor during the period, which must be whole seconds.
+func (b *RuleBuilder) WarmUp(period time.Duration, coldFactor uint32) *RuleBuilder {
+	if period <= 0 || period%time.Second != 0 {
+		return b.fail("WarmUp: period must be a positive multiple of second, got %v", period)
+	}
+	b.rule.TokenCalculateStrategy = WarmUp
+	b.rule.WarmUpPeriodSec = uint32(period / time.Second)
+	b.rule.WarmUpColdFactor = coldFactor
+	return b
+}
+
+// WarmUpCurve sets the shape of the warm-up curve, only available to WarmUp.
+func (b *RuleBuilder) WarmUpCurve(curve WarmUpCurve) *RuleBuilder {
+	b.warmUpOnly = append(b.warmUpOnly, "WarmUpCurve")
+	b.rule.WarmUpCurve = curve
+	return b
+}
+
+// ColdStartCount sets the floor of the threshold during warm-up, only available to WarmUp.
+func (b *RuleBuilder) ColdStartCount(count float64) *RuleBuilder {
+	b.warmUpOnly = append(b.warmUpOnly, "ColdStartCount")
+	b.rule.ColdStartCount = count
+	return b
+}
+
+// WarmUpRestartIdleFactor enables the warm-up restart detection, only available to WarmUp.
+func (b *RuleBuilder) WarmUpRestartIdleFactor(factor uint32) *RuleBuilder {
+	b.warmUpOnly = append(b.warmUpOnly, "WarmUpRestartIdleFactor")
+	b.rule.WarmUpRestartIdleFactor = factor
+	return b
+}
+
+// AssociatedWith limits the resource by the statistic of the associated resource.
+func (b *RuleBuilder) AssociatedWith(refResource string) *RuleBuilder {
+	if len(refResource) == 0 {
+		return b.fail("AssociatedWith: empty ref resource")
+	}
+	b.rule.RelationStrategy = AssociatedResource
+	b.rule.RefResource = refResource
+	return b
+}
+
+// Category makes the rule only take effect on the requests of the category (e.g. base.CategoryWrite).
+func (b *RuleBuilder) Category(category base.RequestCategory) *RuleBuilder {
+	b.rule.Category = category
+	return b
+}
+
+// DeploymentLabel makes the rule take effect only if current process has the label.
+func (b *RuleBuilder) DeploymentLabel(label string) *RuleBuilder {
+	b.rule.DeploymentLabel = label
+	return b
+}
+
+// SharedStat makes the rule count the requests of all the processes on the host by the shared statistic of the key.
+func (b *RuleBuilder) SharedStat(key string) *RuleBuilder {
+	b.rule.SharedStatKey = key
+	return b
+}
+
+// Build returns the rule, or the first error of the builder calls, or the error of the invalid combinations.
+// The builder could be reused to build more rules, which are independent copies.
+func (b *RuleBuilder) Build() (*Rule, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if !b.thresholdSet {
+		return nil, errors.New("threshold is not set, call QPS, Threshold or BytesPerSecond")
+	}
+	if len(b.throttlingOnly) > 0 && b.rule.ControlBehavior != Throttling {
+		return nil, errors.Errorf("%s is only available to Throttling", b.throttlingOnly[0])
+	}
+	if len(b.warmUpOnly) > 0 && b.rule.TokenCalculateStrategy != WarmUp {
+		return nil, errors.Errorf("%s is only available to WarmUp", b.warmUpOnly[0])
+	}
+	rule := b.rule
+	if err := IsValidRule(&rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// durationInMs converts the non-negative duration of whole milliseconds to milliseconds.
+func durationInMs(d time.Duration) (uint32, bool) {
+	if d < 0 || d%time.Millisecond != 0 || d/time.Millisecond > time.Duration(^uint32(0)) {
+		return 0, false
+	}
+	return uint32(d / time.Millisecond), true
+}
@@ -0,0 +1,81 @@
+package flow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleBuilder(t *testing.T) {
+	t.Run("QPSReject", func(t *testing.T) {
+		rule, err := NewRuleBuilder("abc").ID("r1").QPS(100).Reject().Build()
+		assert.Nil(t, err)
+		assert.Equal(t, &Rule{
+			ID:                     "r1",
+			Resource:               "abc",
+			TokenCalculateStrategy: Direct,
+			ControlBehavior:        Reject,
+			Threshold:              100,
+			StatIntervalInMs:       1000,
+		}, rule)
+	})
+
+	t.Run("ThrottlingWarmUp", func(t *testing.T) {
+		rule, err := NewRuleBuilder("abc").
+			Threshold(50, 500*time.Millisecond).
+			Throttling(200 * time.Millisecond).
+			MaxQueueingRequests(10).
+			WarmUp(10*time.Second, 3).
+			WarmUpCurve(LinearCurve).
+			Build()
+		assert.Nil(t, err)
+		assert.Equal(t, float64(50), rule.Threshold)
+		assert.Equal(t, uint32(500), rule.StatIntervalInMs)
+		assert.Equal(t, Throttling, rule.ControlBehavior)
+		assert.Equal(t, uint32(200), rule.MaxQueueingTimeMs)
+		assert.Equal(t, uint32(10), rule.MaxQueueingRequests)
+		assert.Equal(t, WarmUp, rule.TokenCalculateStrategy)
+		assert.Equal(t, uint32(10), rule.WarmUpPeriodSec)
+		assert.Equal(t, uint32(3), rule.WarmUpColdFactor)
+		assert.Equal(t, LinearCurve, rule.WarmUpCurve)
+	})
+
+	t.Run("Associated", func(t *testing.T) {
+		rule, err := NewRuleBuilder("abc").QPS(10).AssociatedWith("def").Build()
+		assert.Nil(t, err)
+		assert.Equal(t, AssociatedResource, rule.RelationStrategy)
+		assert.Equal(t, "def", rule.RefResource)
+	})
+
+	t.Run("Reuse", func(t *testing.T) {
+		b := NewRuleBuilder("abc").QPS(10)
+		r1, err := b.Build()
+		assert.Nil(t, err)
+		r2, err := b.DeploymentLabel("canary").Build()
+		assert.Nil(t, err)
+		assert.Equal(t, "", r1.DeploymentLabel)
+		assert.Equal(t, "canary", r2.DeploymentLabel)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		cases := map[string]*RuleBuilder{
+			"NoThreshold":       NewRuleBuilder("abc").Reject(),
+			"ThresholdTwice":    NewRuleBuilder("abc").QPS(10).BytesPerSecond(1024),
+			"ConflictBehavior":  NewRuleBuilder("abc").QPS(10).Reject().Throttling(time.Second),
+			"InvalidInterval":   NewRuleBuilder("abc").Threshold(10, 0),
+			"SubMsQueueing":     NewRuleBuilder("abc").QPS(10).Throttling(time.Microsecond),
+			"QueueingOfReject":  NewRuleBuilder("abc").QPS(10).MaxQueueingRequests(10),
+			"CurveOfDirect":     NewRuleBuilder("abc").QPS(10).WarmUpCurve(LinearCurve),
+			"SubSecondWarmUp":   NewRuleBuilder("abc").QPS(10).WarmUp(1500*time.Millisecond, 3),
+			"EmptyRefResource":  NewRuleBuilder("abc").QPS(10).AssociatedWith(""),
+			"NegativeThreshold": NewRuleBuilder("abc").QPS(-1),
+			"EmptyResource":     NewRuleBuilder("").QPS(10),
+		}
+		for name, b := range cases {
+			rule, err := b.Build()
+			assert.Nil(t, rule, name)
+			assert.NotNil(t, err, name)
+		}
+	})
+}
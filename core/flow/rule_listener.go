@@ -0,0 +1,154 @@
+package flow
+
+import (
+	"sync"
+
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/pkg/errors"
+)
+
+// RuleUpdateListener is called with the changes of the effective flow rules once the rules are updated.
+// The rules with the same ID are compared to detect the updated rules, and the rules without ID are either
+// added or removed. The listeners are called outside the locks of the rule manager, so they could read or even
+// update the rules, but the rules passed in must be treated as read-only.
+type RuleUpdateListener func(added, removed, updated []*Rule)
+
+type ruleUpdate struct {
+	added, removed, updated []*Rule
+}
+
+var (
+	ruleUpdateListeners    = make([]RuleUpdateListener, 0)
+	ruleUpdateListenersMux = new(sync.RWMutex)
+
+	// pendingRuleUpdates are the changes to dispatch to the listeners, in the order of the updates.
+	pendingRuleUpdates     = make([]*ruleUpdate, 0)
+	dispatchingRuleUpdates bool
+	ruleUpdatesMux         = new(sync.Mutex)
+)
+
+// RegisterRuleUpdateListener registers the listener of the changes of the effective flow rules,
+// e.g. to audit the changes or to sync the rules to the dashboard.
+func RegisterRuleUpdateListener(l RuleUpdateListener) {
+	if l == nil {
+		return
+	}
+	ruleUpdateListenersMux.Lock()
+	defer ruleUpdateListenersMux.Unlock()
+
+	ruleUpdateListeners = append(ruleUpdateListeners, l)
+}
+
+// ClearRuleUpdateListeners clears all the listeners of the changes of the effective flow rules.
+func ClearRuleUpdateListeners() {
+	ruleUpdateListenersMux.Lock()
+	defer ruleUpdateListenersMux.Unlock()
+
+	ruleUpdateListeners = make([]RuleUpdateListener, 0)
+}
+
+func currentRuleUpdateListeners() []RuleUpdateListener {
+	ruleUpdateListenersMux.RLock()
+	defer ruleUpdateListenersMux.RUnlock()
+
+	return ruleUpdateListeners
+}
+
+// recordRuleUpdate diffs the effective rules and queues the changes for the listeners,
+// which is called by onRuleUpdate with the locks held. The changes are dispatched by dispatchRuleUpdates.
+func recordRuleUpdate(oldRules, newRules []*Rule) {
+	if len(currentRuleUpdateListeners()) == 0 {
+		return
+	}
+	added, removed, updated := diffRules(oldRules, newRules)
+	if len(added) == 0 && len(removed) == 0 && len(updated) == 0 {
+		return
+	}
+	ruleUpdatesMux.Lock()
+	defer ruleUpdatesMux.Unlock()
+
+	pendingRuleUpdates = append(pendingRuleUpdates, &ruleUpdate{added: added, removed: removed, updated: updated})
+}
+
+// dispatchRuleUpdates calls the listeners with the queued changes, which must be called without the locks
+// of the rule manager held, i.e. deferred before locking updateRuleMux. The changes are dispatched
+// by only one goroutine at a time so that the listeners see them in order, and the changes made by
+// the listeners themselves are dispatched after the current ones.
+func dispatchRuleUpdates() {
+	ruleUpdatesMux.Lock()
+	if dispatchingRuleUpdates {
+		ruleUpdatesMux.Unlock()
+		return
+	}
+	dispatchingRuleUpdates = true
+	for len(pendingRuleUpdates) > 0 {
+		updates := pendingRuleUpdates
+		pendingRuleUpdates = make([]*ruleUpdate, 0)
+		ruleUpdatesMux.Unlock()
+
+		listeners := currentRuleUpdateListeners()
+		for _, u := range updates {
+			for _, l := range listeners {
+				notifyRuleUpdateListener(l, u)
+			}
+		}
+		ruleUpdatesMux.Lock()
+	}
+	dispatchingRuleUpdates = false
+	ruleUpdatesMux.Unlock()
+}
+
+func notifyRuleUpdateListener(l RuleUpdateListener, u *ruleUpdate) {
+	defer func() {
+		if r := recover(); r != nil {
+			logging.Error(errors.Errorf("%+v", r), "[FlowRuleManager] Panic in the rule update listener")
+		}
+	}()
+	l(u.added, u.removed, u.updated)
+}
+
+// diffRules compares the rules of the same ID, and the identical rules without ID.
+func diffRules(oldRules, newRules []*Rule) (added, removed, updated []*Rule) {
+	added, removed, updated = make([]*Rule, 0), make([]*Rule, 0), make([]*Rule, 0)
+	oldById := make(map[string]*Rule)
+	oldWithoutId := make([]*Rule, 0)
+	for _, r := range oldRules {
+		if len(r.ID) > 0 {
+			oldById[r.ID] = r
+		} else {
+			oldWithoutId = append(oldWithoutId, r)
+		}
+	}
+	matched := make(map[*Rule]bool)
+	for _, r := range newRules {
+		if len(r.ID) > 0 {
+			old, exists := oldById[r.ID]
+			if !exists {
+				added = append(added, r)
+				continue
+			}
+			matched[old] = true
+			if !isDuplicateRule(old, r) {
+				updated = append(updated, r)
+			}
+			continue
+		}
+		found := false
+		for _, old := range oldWithoutId {
+			if !matched[old] && isDuplicateRule(old, r) {
+				matched[old] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			added = append(added, r)
+		}
+	}
+	for _, r := range oldRules {
+		if !matched[r] {
+			removed = append(removed, r)
+		}
+	}
+	return added, removed, updated
+}
@@ -0,0 +1,99 @@
+package flow
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedRuleUpdate struct {
+	added, removed, updated []*Rule
+}
+
+func TestRegisterRuleUpdateListener(t *testing.T) {
+	defer ClearRuleUpdateListeners()
+	defer func() { _ = ClearRules() }()
+
+	mux := sync.Mutex{}
+	updates := make([]recordedRuleUpdate, 0)
+	RegisterRuleUpdateListener(func(added, removed, updated []*Rule) {
+		mux.Lock()
+		defer mux.Unlock()
+		updates = append(updates, recordedRuleUpdate{added, removed, updated})
+	})
+	lastUpdate := func() recordedRuleUpdate {
+		mux.Lock()
+		defer mux.Unlock()
+		return updates[len(updates)-1]
+	}
+	updateCount := func() int {
+		mux.Lock()
+		defer mux.Unlock()
+		return len(updates)
+	}
+
+	_, err := LoadRules([]*Rule{{ID: "1", Resource: "abc", Threshold: 10}, {Resource: "def", Threshold: 10}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, updateCount())
+	assert.Equal(t, 2, len(lastUpdate().added))
+
+	t.Run("Unchanged", func(t *testing.T) {
+		_, err := LoadRules([]*Rule{{ID: "1", Resource: "abc", Threshold: 10}, {Resource: "def", Threshold: 10}})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, updateCount())
+	})
+
+	t.Run("Diff", func(t *testing.T) {
+		_, err := LoadRules([]*Rule{{ID: "1", Resource: "abc", Threshold: 20}, {Resource: "def", Threshold: 30}})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, updateCount())
+		u := lastUpdate()
+		assert.Equal(t, 1, len(u.updated))
+		assert.Equal(t, 20.0, u.updated[0].Threshold)
+		assert.Equal(t, 1, len(u.added))
+		assert.Equal(t, 30.0, u.added[0].Threshold)
+		assert.Equal(t, 1, len(u.removed))
+		assert.Equal(t, "def", u.removed[0].Resource)
+		assert.Equal(t, 10.0, u.removed[0].Threshold)
+	})
+
+	t.Run("Incremental", func(t *testing.T) {
+		_, err := RemoveRuleOfResource("def")
+		assert.Nil(t, err)
+		u := lastUpdate()
+		assert.Equal(t, 0, len(u.added))
+		assert.Equal(t, 1, len(u.removed))
+		assert.Equal(t, 0, len(u.updated))
+	})
+
+	t.Run("OutsideLock", func(t *testing.T) {
+		// the listener updating the rules must not deadlock
+		RegisterRuleUpdateListener(func(added, removed, updated []*Rule) {
+			if len(added) == 1 && added[0].Resource == "ghi" {
+				_, _ = AppendRule(&Rule{Resource: "jkl", Threshold: 10})
+			}
+		})
+		_, err := AppendRule(&Rule{Resource: "ghi", Threshold: 10})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(getRulesOfResource("jkl")))
+		u := lastUpdate()
+		assert.Equal(t, 1, len(u.added))
+		assert.Equal(t, "jkl", u.added[0].Resource)
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		assert.Nil(t, ClearRules())
+		u := lastUpdate()
+		assert.Equal(t, 3, len(u.removed))
+	})
+}
+
+func TestDiffRules(t *testing.T) {
+	r1 := &Rule{Resource: "abc", Threshold: 10}
+	r2 := &Rule{Resource: "abc", Threshold: 10}
+	added, removed, updated := diffRules([]*Rule{r1, r2}, []*Rule{{Resource: "abc", Threshold: 10}})
+	assert.Equal(t, 0, len(added))
+	assert.Equal(t, 1, len(removed))
+	assert.Equal(t, 0, len(updated))
+}
@@ -3,9 +3,13 @@ package flow
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/alibaba/sentinel-golang/core/audit"
 	"github.com/alibaba/sentinel-golang/core/base"
 	"github.com/alibaba/sentinel-golang/core/config"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
 	"github.com/alibaba/sentinel-golang/core/stat"
 	sbase "github.com/alibaba/sentinel-golang/core/stat/base"
 	"github.com/alibaba/sentinel-golang/logging"
@@ -28,9 +32,18 @@ var (
 	tcGenFuncMap = make(map[trafficControllerGenKey]TrafficControllerGenFunc)
 	tcMap        = make(TrafficControllerMap)
 	tcMux        = new(sync.RWMutex)
+	// rulesVersion increases every time the flow rules are updated.
+	rulesVersion uint64
+	// tagRules are the valid rules targeting tags, which are bound to every resource with the tag in tcMap.
+	tagRules = make([]*Rule, 0)
+	// loadedRules are the latest loaded rules, which are reloaded when the tag rules match newly tagged resources.
+	loadedRules   []*Rule
+	updateRuleMux = new(sync.Mutex)
 )
 
 func init() {
+	base.RegisterResourceTagListener(onResourceTagged)
+
 	// Initialize the traffic shaping controller generator map for existing control behaviors.
 	tcGenFuncMap[trafficControllerGenKey{
 		tokenCalculateStrategy: Direct,
@@ -67,7 +80,7 @@ func init() {
 			return nil, err
 		}
 		tsc.flowCalculator = NewDirectTrafficShapingCalculator(tsc, rule.Threshold)
-		tsc.flowChecker = NewThrottlingChecker(tsc, rule.MaxQueueingTimeMs)
+		tsc.flowChecker = NewThrottlingCheckerWithMaxQueueing(tsc, rule.MaxQueueingTimeMs, rule.MaxQueueingRequests)
 		return tsc, nil
 	}
 	tcGenFuncMap[trafficControllerGenKey{
@@ -104,8 +117,9 @@ func init() {
 		if err != nil || tsc == nil {
 			return nil, err
 		}
-		tsc.flowCalculator = NewWarmUpTrafficShapingCalculator(tsc, rule)
-		tsc.flowChecker = NewThrottlingChecker(tsc, rule.MaxQueueingTimeMs)
+		// The warm-up is applied by the pacing of the checker, on the threshold of the rule.
+		tsc.flowCalculator = NewDirectTrafficShapingCalculator(tsc, rule.Threshold)
+		tsc.flowChecker = NewWarmUpThrottlingChecker(tsc, rule)
 		return tsc, nil
 	}
 }
@@ -119,7 +133,13 @@ func logRuleUpdate(m TrafficControllerMap) {
 	}
 }
 
-func onRuleUpdate(rules []*Rule) (err error) {
+func onRuleUpdate(rules []*Rule) error {
+	return onRuleUpdateRebuilding(rules, nil)
+}
+
+// onRuleUpdateRebuilding updates the rules like onRuleUpdate, while the traffic controllers of the rebuilt
+// resources are built from scratch rather than reusing the old ones (e.g. the statistic of the resource changes).
+func onRuleUpdateRebuilding(rules []*Rule, rebuilt map[string]struct{}) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			var ok bool
@@ -130,21 +150,36 @@ func onRuleUpdate(rules []*Rule) (err error) {
 		}
 	}()
 
+	loaded := rules
+	rules = filterRulesByDeploymentLabel(rules)
+
 	resRulesMap := make(map[string][]*Rule)
+	validTagRules := make([]*Rule, 0)
 	for _, rule := range rules {
 		if err := IsValidRule(rule); err != nil {
 			logging.Warn("ignoring invalid flow rule", "rule", rule, "reason", err)
 			continue
 		}
-		resRules, exist := resRulesMap[rule.Resource]
-		if !exist {
-			resRules = make([]*Rule, 0, 1)
+		if len(rule.TargetTag) > 0 {
+			validTagRules = append(validTagRules, rule)
+			continue
+		}
+		resRulesMap[rule.Resource] = append(resRulesMap[rule.Resource], rule)
+	}
+	for _, rule := range validTagRules {
+		// bind a copy of the tag rule to every resource with the tag
+		for _, res := range base.ResourcesWithTag(rule.TargetTag) {
+			bound := *rule
+			bound.Resource = res
+			resRulesMap[res] = append(resRulesMap[res], &bound)
 		}
-		resRulesMap[rule.Resource] = append(resRules, rule)
 	}
 	m := make(TrafficControllerMap, len(resRulesMap))
 	start := util.CurrentTimeNano()
+	lockStart := time.Now()
 	tcMux.Lock()
+	selfmetric.RecordLockWait("flow.rules", lockStart)
+	oldRules := rulesFrom(tcMap)
 	defer func() {
 		tcMux.Unlock()
 		if r := recover(); r != nil {
@@ -152,19 +187,118 @@ func onRuleUpdate(rules []*Rule) (err error) {
 		}
 		logging.Debug("time statistic(ns) for updating flow rule", "timeCost", util.CurrentTimeNano()-start)
 		logRuleUpdate(m)
+		audit.RecordRuleChange("flow", ruleStringsOf(oldRules), ruleStringsOf(rulesFrom(m)))
 	}()
 	for res, rulesOfRes := range resRulesMap {
-		m[res] = buildRulesOfRes(res, rulesOfRes)
+		oldTcs := tcMap[res]
+		if _, ok := rebuilt[res]; ok {
+			oldTcs = nil
+		}
+		m[res] = buildRulesOfRes(res, rulesOfRes, oldTcs)
 	}
 	tcMap = m
+	invalidateControllerCache()
+	recordRuleUpdate(effectiveRulesOf(oldRules, tagRules), effectiveRulesOf(rulesFrom(m), validTagRules))
+	tagRules = validTagRules
+	loadedRules = loaded
+	resources := make([]string, 0, len(m))
+	refResources := make([]string, 0)
+	for res, tcs := range m {
+		resources = append(resources, res)
+		for _, tc := range tcs {
+			if tc.rule.RelationStrategy == AssociatedResource {
+				refResources = append(refResources, tc.rule.RefResource)
+			}
+		}
+	}
+	base.SetRuleResourcesOf("flow", resources, false)
+	// The statistics of the associated resources are referenced by the flow rules.
+	stat.SetPinnedResourcesOf("flow", refResources)
+	atomic.AddUint64(&rulesVersion, 1)
 	return nil
 }
 
 // LoadRules loads the given flow rules to the rule manager, while all previous rules will be replaced.
 func LoadRules(rules []*Rule) (bool, error) {
+	defer selfmetric.RecordRuleUpdate("flow", time.Now())
+	defer dispatchRuleUpdates()
+
+	updateRuleMux.Lock()
+	defer updateRuleMux.Unlock()
+
 	// TODO: rethink the design
-	err := onRuleUpdate(rules)
-	return true, err
+	return true, reloadDefaultRules(rules)
+}
+
+// LoadRulesFromStore loads the flow rules saved in the RuleStore (see base.SetRuleStore),
+// e.g. the rules loaded by the other process sharing the store.
+// It returns false if there are no rules saved.
+func LoadRulesFromStore() (bool, error) {
+	defer dispatchRuleUpdates()
+
+	updateRuleMux.Lock()
+	defer updateRuleMux.Unlock()
+
+	rules := make([]*Rule, 0)
+	ok, err := base.LoadRulesFromStore("flow", &rules)
+	if !ok || err != nil {
+		return false, err
+	}
+	defaultNamespaceRules = rules
+	return true, onRuleUpdate(withNamespaceRules(rules))
+}
+
+// onResourceTagged reloads the latest loaded rules if any tag rule matches the newly tagged resource,
+// so that the tag rules are bound to the resource.
+func onResourceTagged(resource string) {
+	defer dispatchRuleUpdates()
+
+	updateRuleMux.Lock()
+	defer updateRuleMux.Unlock()
+
+	tcMux.RLock()
+	matched := false
+	for _, r := range tagRules {
+		if base.ResourceHasTag(resource, r.TargetTag) && !isTagRuleBoundTo(r, tcMap[resource]) {
+			matched = true
+			break
+		}
+	}
+	rules := loadedRules
+	tcMux.RUnlock()
+	if !matched {
+		return
+	}
+	if err := onRuleUpdate(rules); err != nil {
+		logging.Error(err, "[FlowRuleManager] Failed to bind the tag rules to the newly tagged resource", "resource", resource)
+	}
+}
+
+func isTagRuleBoundTo(r *Rule, tcs []*TrafficShapingController) bool {
+	for _, tc := range tcs {
+		if tc.BoundRule().TargetTag == r.TargetTag {
+			return true
+		}
+	}
+	return false
+}
+
+// RulesVersion returns the version of the effective flow rules, which increases every time the rules are updated.
+// It could be used to detect the changes of flow rules cheaply.
+func RulesVersion() uint64 {
+	return atomic.LoadUint64(&rulesVersion)
+}
+
+// effectiveRulesOf returns the rules of the controllers, with the copies bound to the tagged resources replaced
+// by the tag rules themselves.
+func effectiveRulesOf(tcRules []*Rule, tagRules []*Rule) []*Rule {
+	rules := make([]*Rule, 0, len(tcRules)+len(tagRules))
+	for _, r := range tcRules {
+		if len(r.TargetTag) == 0 {
+			rules = append(rules, r)
+		}
+	}
+	return append(rules, tagRules...)
 }
 
 // getRules returns all the rules。Any changes of rules take effect for flow module
@@ -173,7 +307,7 @@ func getRules() []*Rule {
 	tcMux.RLock()
 	defer tcMux.RUnlock()
 
-	return rulesFrom(tcMap)
+	return effectiveRulesOf(rulesFrom(tcMap), tagRules)
 }
 
 // getRulesOfResource returns specific resource's rules。Any changes of rules take effect for flow module
@@ -215,12 +349,48 @@ func GetRulesOfResource(res string) []Rule {
 	return ret
 }
 
-// ClearRules clears all the rules in flow module.
+// ClearRules clears all the rules in flow module, including the rules of all the namespaces.
 func ClearRules() error {
+	updateRuleMux.Lock()
+	namespaceRules.Clear()
+	updateRuleMux.Unlock()
+
 	_, err := LoadRules(nil)
 	return err
 }
 
+// filterRulesByDeploymentLabel returns the rules that take effect in current process.
+// The rules with deployment label take effect only if current process has the label,
+// and override the rules without deployment label of the same resource (or the same target tag).
+func filterRulesByDeploymentLabel(rules []*Rule) []*Rule {
+	labeledRes := make(map[string]bool)
+	for _, r := range rules {
+		if r != nil && len(r.DeploymentLabel) > 0 && config.HasAppLabel(r.DeploymentLabel) {
+			labeledRes[ruleTargetOf(r)] = true
+		}
+	}
+	ret := make([]*Rule, 0, len(rules))
+	for _, r := range rules {
+		if r == nil || len(r.DeploymentLabel) == 0 {
+			if r == nil || !labeledRes[ruleTargetOf(r)] {
+				ret = append(ret, r)
+			}
+			continue
+		}
+		if config.HasAppLabel(r.DeploymentLabel) {
+			ret = append(ret, r)
+		}
+	}
+	return ret
+}
+
+func ruleTargetOf(r *Rule) string {
+	if len(r.TargetTag) > 0 {
+		return "tag:" + r.TargetTag
+	}
+	return r.Resource
+}
+
 func rulesFrom(m TrafficControllerMap) []*Rule {
 	rules := make([]*Rule, 0)
 	if len(m) == 0 {
@@ -239,7 +409,18 @@ func rulesFrom(m TrafficControllerMap) []*Rule {
 	return rules
 }
 
+func ruleStringsOf(rules []*Rule) []string {
+	ret := make([]string, 0, len(rules))
+	for _, r := range rules {
+		ret = append(ret, r.String())
+	}
+	return ret
+}
+
 func generateStatFor(rule *Rule) (*standaloneStatistic, error) {
+	if len(rule.SharedStatKey) > 0 {
+		return generateSharedStatFor(rule)
+	}
 	intervalInMs := rule.StatIntervalInMs
 
 	var retStat standaloneStatistic
@@ -251,9 +432,19 @@ func generateStatFor(rule *Rule) (*standaloneStatistic, error) {
 	} else {
 		resNode = stat.GetOrCreateResourceNode(rule.Resource, base.ResTypeCommon)
 	}
-	if intervalInMs == 0 || intervalInMs == config.MetricStatisticIntervalMs() {
+	statNode := &resNode.BaseStatNode
+	if rule.Category != base.CategoryNone {
+		// use the statistic of the category
+		statNode = resNode.GetOrCreateCategoryNode(rule.Category)
+	}
+	// The statistic of the resource node counts the requests rather than the cost or the payload bytes.
+	costWeighted := rule.MetricType == Throughput || (hasCostFunc(rule.Resource) && rule.RelationStrategy != AssociatedResource)
+	if costWeighted && intervalInMs == 0 {
+		intervalInMs = config.MetricStatisticIntervalMs()
+	}
+	if !costWeighted && (intervalInMs == 0 || intervalInMs == config.MetricStatisticIntervalMs()) {
 		// default case, use the resource's default statistic
-		readStat := resNode.DefaultMetric()
+		readStat := statNode.DefaultMetric()
 		retStat.reuseResourceStat = true
 		retStat.readOnlyMetric = readStat
 		retStat.writeOnlyMetric = nil
@@ -274,9 +465,12 @@ func generateStatFor(rule *Rule) (*standaloneStatistic, error) {
 		}
 	}
 	err := base.CheckValidityForReuseStatistic(sampleCount, intervalInMs, config.GlobalStatisticSampleCountTotal(), config.GlobalStatisticIntervalMsTotal())
+	if err == nil && costWeighted {
+		err = base.GlobalStatisticNonReusableError
+	}
 	if err == nil {
 		// global statistic reusable
-		readStat, e := resNode.GenerateReadStat(sampleCount, intervalInMs)
+		readStat, e := statNode.GenerateReadStat(sampleCount, intervalInMs)
 		if e != nil {
 			return nil, e
 		}
@@ -287,6 +481,11 @@ func generateStatFor(rule *Rule) (*standaloneStatistic, error) {
 	} else if err == base.GlobalStatisticNonReusableError {
 		logging.Info("flow rule couldn't reuse global statistic and will generate independent statistic", "rule", rule)
 		retStat.reuseResourceStat = false
+		if tiered := newTieredStatFor(intervalInMs); tiered != nil {
+			retStat.readOnlyMetric = tiered
+			retStat.writeOnlyMetric = tiered
+			return &retStat, nil
+		}
 		realLeapArray := sbase.NewBucketLeapArray(sampleCount, intervalInMs)
 		metricStat, e := sbase.NewSlidingWindowMetric(sampleCount, intervalInMs, realLeapArray)
 		if e != nil {
@@ -299,6 +498,22 @@ func generateStatFor(rule *Rule) (*standaloneStatistic, error) {
 	return nil, errors.Wrapf(err, "fail to new standalone statistic because of invalid StatIntervalInMs in flow.Rule, StatIntervalInMs: %d", intervalInMs)
 }
 
+// newTieredStatFor generates a tiered statistic for an interval longer than the global statistic,
+// which keeps the recent global-statistic-long part in fine buckets and the rest in buckets as long as
+// the global statistic interval. It returns nil if the interval can not be split into such tiers.
+func newTieredStatFor(intervalInMs uint32) *sbase.TieredWindowMetric {
+	fineIntervalInMs := config.GlobalStatisticIntervalMsTotal()
+	if intervalInMs <= fineIntervalInMs || intervalInMs%fineIntervalInMs != 0 {
+		return nil
+	}
+	tiered, err := sbase.NewTieredWindowMetric(intervalInMs, intervalInMs/fineIntervalInMs, fineIntervalInMs, config.GlobalStatisticSampleCountTotal())
+	if err != nil {
+		logging.Warn("[FlowRuleManager] Failed to generate tiered statistic, fallback to single bucket statistic", "intervalInMs", intervalInMs, "err", err)
+		return nil
+	}
+	return tiered
+}
+
 // SetTrafficShapingGenerator sets the traffic controller generator for the given TokenCalculateStrategy and ControlBehavior.
 // Note that modifying the generator of default control strategy is not allowed.
 func SetTrafficShapingGenerator(tokenCalculateStrategy TokenCalculateStrategy, controlBehavior ControlBehavior, generator TrafficControllerGenFunc) error {
@@ -339,6 +554,15 @@ func RemoveTrafficShapingGenerator(tokenCalculateStrategy TokenCalculateStrategy
 	return nil
 }
 
+// TrafficControllersFor returns the effective TrafficShapingControllers of the given resource based on copy.
+// The returned controllers are expected to be read-only, e.g. querying the current threshold.
+func TrafficControllersFor(resource string) []*TrafficShapingController {
+	tcs := getTrafficControllerListFor(resource)
+	ret := make([]*TrafficShapingController, len(tcs))
+	copy(ret, tcs)
+	return ret
+}
+
 func getTrafficControllerListFor(name string) []*TrafficShapingController {
 	tcMux.RLock()
 	defer tcMux.RUnlock()
@@ -354,7 +578,8 @@ func calculateReuseIndexFor(r *Rule, oldResCbs []*TrafficShapingController) (equ
 
 	for idx, oldTc := range oldResCbs {
 		oldRule := oldTc.BoundRule()
-		if oldRule.isEqualsTo(r) {
+		// The ID is compared as well, so that the effective rules reflect the new ID.
+		if oldRule.ID == r.ID && oldRule.isEqualsTo(r) {
 			// break if there is equivalent rule
 			equalIdx = idx
 			break
@@ -372,19 +597,21 @@ func calculateReuseIndexFor(r *Rule, oldResCbs []*TrafficShapingController) (equ
 	return equalIdx, reuseStatIdx
 }
 
-// buildRulesOfRes builds TrafficShapingController slice from rules. the resource of rules must be equals to res
-func buildRulesOfRes(res string, rulesOfRes []*Rule) []*TrafficShapingController {
+// buildRulesOfRes builds TrafficShapingController slice from rules. the resource of rules must be equals to res.
+// The old controller of the unchanged rule is reused as it is, so that the state of the controller
+// (e.g. the warm-up tokens and the throttling pacing) survives the rule refreshes from the datasources.
+// The old controllers of the changed rules donate their statistics to the new controllers if reusable.
+func buildRulesOfRes(res string, rulesOfRes []*Rule, oldTcs []*TrafficShapingController) []*TrafficShapingController {
 	newTcsOfRes := make([]*TrafficShapingController, 0, len(rulesOfRes))
-	emptyTcs := make([]*TrafficShapingController, 0, 0)
+	// The old controllers not reused yet. It's a copy, as the slice in tcMap may be being iterated
+	// by the concurrent rule checks.
+	oldResTcs := make([]*TrafficShapingController, 0, len(oldTcs))
+	oldResTcs = append(oldResTcs, oldTcs...)
 	for _, rule := range rulesOfRes {
 		if res != rule.Resource {
 			logging.Error(errors.Errorf("unmatched resource name, expect: %s, actual: %s", res, rule.Resource), "FlowManager: unmatched resource name ", "rule", rule)
 			continue
 		}
-		oldResTcs, exist := tcMap[res]
-		if !exist {
-			oldResTcs = emptyTcs
-		}
 
 		equalIdx, reuseStatIdx := calculateReuseIndexFor(rule, oldResTcs)
 
@@ -394,7 +621,7 @@ func buildRulesOfRes(res string, rulesOfRes []*Rule) []*TrafficShapingController
 			equalOldTc := oldResTcs[equalIdx]
 			newTcsOfRes = append(newTcsOfRes, equalOldTc)
 			// remove old cb from oldResCbs
-			tcMap[res] = append(oldResTcs[:equalIdx], oldResTcs[equalIdx+1:]...)
+			oldResTcs = append(oldResTcs[:equalIdx], oldResTcs[equalIdx+1:]...)
 			continue
 		}
 
@@ -420,49 +647,102 @@ func buildRulesOfRes(res string, rulesOfRes []*Rule) []*TrafficShapingController
 		}
 		if reuseStatIdx >= 0 {
 			// remove old cb from oldResCbs
-			tcMap[res] = append(oldResTcs[:reuseStatIdx], oldResTcs[reuseStatIdx+1:]...)
+			oldResTcs = append(oldResTcs[:reuseStatIdx], oldResTcs[reuseStatIdx+1:]...)
 		}
 		newTcsOfRes = append(newTcsOfRes, tc)
 	}
 	return newTcsOfRes
 }
 
-// IsValidRule checks whether the given Rule is valid.
+// IsValidRule checks whether the given Rule is valid. All the violated constraints are reported
+// by the returned error, which is ValidationErrors, e.g.
+//
+//	if errs, ok := flow.IsValidRule(rule).(flow.ValidationErrors); ok {
+//	    for _, e := range errs {
+//	        fmt.Println(e.Field, e.Constraint, e.Message)
+//	    }
+//	}
 func IsValidRule(rule *Rule) error {
 	if rule == nil {
-		return errors.New("nil Rule")
+		return ValidationErrors{{Constraint: ConstraintRequired, Message: "nil Rule"}}
+	}
+	v := &ruleValidator{}
+	if rule.Resource == "" && rule.TargetTag == "" {
+		v.add("Resource", ConstraintRequired, "empty resource name")
+	}
+	if rule.Resource != "" && rule.TargetTag != "" {
+		v.add("TargetTag", ConstraintExclusive, "Resource and TargetTag are exclusive")
 	}
-	if rule.Resource == "" {
-		return errors.New("empty resource name")
+	if !rule.Category.IsValid() {
+		v.add("Category", ConstraintEnum, "invalid Category")
 	}
 	if rule.Threshold < 0 {
-		return errors.New("negative threshold")
+		v.add("Threshold", ConstraintRange, "negative threshold")
 	}
 	if int32(rule.TokenCalculateStrategy) < 0 {
-		return errors.New("invalid token calculate strategy")
+		v.add("TokenCalculateStrategy", ConstraintEnum, "invalid token calculate strategy")
 	}
 	if int32(rule.ControlBehavior) < 0 {
-		return errors.New("invalid control behavior")
+		v.add("ControlBehavior", ConstraintEnum, "invalid control behavior")
 	}
 	if !(rule.RelationStrategy >= CurrentResource && rule.RelationStrategy <= AssociatedResource) {
-		return errors.New("invalid relation strategy")
+		v.add("RelationStrategy", ConstraintEnum, "invalid relation strategy")
 	}
-	if rule.RelationStrategy == AssociatedResource && rule.RefResource == "" {
-		return errors.New("Bad flow rule: invalid relation strategy")
+	if rule.RelationStrategy == AssociatedResource {
+		if rule.RefResource == "" {
+			v.add("RefResource", ConstraintRequired, "empty RefResource of AssociatedResource relation strategy")
+		} else if rule.RefResource == rule.Resource {
+			v.add("RefResource", ConstraintDiffers, "RefResource must differ from Resource")
+		}
 	}
 	if rule.TokenCalculateStrategy == WarmUp {
 		if rule.WarmUpPeriodSec <= 0 {
-			return errors.New("invalid WarmUpPeriodSec")
+			v.add("WarmUpPeriodSec", ConstraintRange, "invalid WarmUpPeriodSec")
 		}
+		// 0 means the default cold factor (config.DefaultWarmUpColdFactor)
 		if rule.WarmUpColdFactor == 1 {
-			return errors.New("WarmUpColdFactor must be great than 1")
+			v.add("WarmUpColdFactor", ConstraintRange, "WarmUpColdFactor must be greater than 1, or 0 for the default")
 		}
+		if !(rule.WarmUpCurve >= TokenBucketCurve && rule.WarmUpCurve <= ExponentialCurve) {
+			v.add("WarmUpCurve", ConstraintEnum, "invalid WarmUpCurve")
+		}
+		if rule.ColdStartCount < 0 {
+			v.add("ColdStartCount", ConstraintRange, "negative ColdStartCount")
+		}
+	}
+	if rule.ControlBehavior == Throttling {
+		if rule.MaxQueueingTimeMs == 0 {
+			v.add("MaxQueueingTimeMs", ConstraintRequired, "invalid MaxQueueingTimeMs")
+		}
+	} else if rule.MaxQueueingTimeMs > 0 || rule.MaxQueueingRequests > 0 {
+		// The queueing fields are ignored rather than rejected, as the rules from the dashboard always carry them.
+		logging.Warn("[FlowRuleManager] MaxQueueingTimeMs and MaxQueueingRequests are only meaningful with Throttling control behavior, ignored",
+			"resource", rule.Resource, "controlBehavior", rule.ControlBehavior)
+	}
+	if !(rule.MetricType >= RequestCount && rule.MetricType <= Throughput) {
+		v.add("MetricType", ConstraintEnum, "invalid MetricType")
+	}
+	if rule.MetricType == Throughput && rule.RelationStrategy != CurrentResource {
+		v.add("RelationStrategy", ConstraintDependsOn, "Throughput rule only supports CurrentResource relation strategy")
 	}
-	if rule.ControlBehavior == Throttling && rule.MaxQueueingTimeMs == 0 {
-		return errors.New("invalid MaxQueueingTimeMs")
+	if len(rule.SharedStatKey) > 0 && rule.RelationStrategy != CurrentResource {
+		v.add("RelationStrategy", ConstraintDependsOn, "the rule with SharedStatKey only supports CurrentResource relation strategy")
+	}
+	if len(rule.SharedStatKey) > 0 && rule.ControlBehavior == Throttling {
+		v.add("ControlBehavior", ConstraintDependsOn, "the rule with SharedStatKey doesn't support Throttling control behavior")
+	}
+	if rule.WarningCount < 0 {
+		v.add("WarningCount", ConstraintRange, "negative WarningCount")
+	} else if rule.WarningCount > 0 {
+		if rule.WarningCount >= rule.Threshold {
+			v.add("WarningCount", ConstraintRange, "WarningCount must be less than Threshold")
+		}
+		if !rule.needStatistic() {
+			v.add("WarningCount", ConstraintDependsOn, "WarningCount is not supported by the Direct Throttling rule without statistic")
+		}
 	}
 	if rule.StatIntervalInMs > config.GlobalStatisticIntervalMsTotal()*60 {
-		return errors.New("StatIntervalInMs must be less than 10 minutes")
+		v.add("StatIntervalInMs", ConstraintRange, "StatIntervalInMs must be less than 10 minutes")
 	}
-	return nil
+	return v.err()
 }
@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/config"
 	"github.com/alibaba/sentinel-golang/core/stat"
 	sbase "github.com/alibaba/sentinel-golang/core/stat/base"
 	"github.com/stretchr/testify/assert"
@@ -219,6 +221,27 @@ func Test_generateStatFor(t *testing.T) {
 			t.Fatal(err)
 		}
 		assert.True(t, boundStat.reuseResourceStat == false && boundStat.writeOnlyMetric != nil)
+		_, succ := boundStat.readOnlyMetric.(*sbase.TieredWindowMetric)
+		assert.True(t, succ)
+	})
+
+	t.Run("generateStatFor_standalone_stat_non_tiered", func(t *testing.T) {
+		r1 := &Rule{
+			Resource:               "abc",
+			TokenCalculateStrategy: Direct,
+			ControlBehavior:        Reject,
+			StatIntervalInMs:       15000,
+			Threshold:              100,
+			RelationStrategy:       CurrentResource,
+		}
+		// 15000ms could not be split into 10000ms long coarse buckets
+		boundStat, err := generateStatFor(r1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, boundStat.reuseResourceStat == false && boundStat.writeOnlyMetric != nil)
+		_, succ := boundStat.readOnlyMetric.(*sbase.SlidingWindowMetric)
+		assert.True(t, succ)
 	})
 }
 
@@ -240,7 +263,7 @@ func Test_buildRulesOfRes(t *testing.T) {
 			MaxQueueingTimeMs:      10,
 		}
 		assert.True(t, len(tcMap["abc1"]) == 0)
-		tcs := buildRulesOfRes("abc1", []*Rule{r1, r2})
+		tcs := buildRulesOfRes("abc1", []*Rule{r1, r2}, tcMap["abc1"])
 		assert.True(t, len(tcs) == 2)
 		assert.True(t, tcs[0].BoundRule() == r1)
 		assert.True(t, tcs[1].BoundRule() == r2)
@@ -357,7 +380,7 @@ func Test_buildRulesOfRes(t *testing.T) {
 			MaxQueueingTimeMs:      10,
 			StatIntervalInMs:       50000,
 		}
-		tcs := buildRulesOfRes("abc1", []*Rule{r12, r22, r32, r42})
+		tcs := buildRulesOfRes("abc1", []*Rule{r12, r22, r32, r42}, tcMap["abc1"])
 		assert.True(t, len(tcs) == 4)
 		assert.True(t, tcs[0].BoundRule() == r12)
 		assert.True(t, tcs[1].BoundRule() == r22)
@@ -373,3 +396,172 @@ func Test_buildRulesOfRes(t *testing.T) {
 		assert.True(t, tcs[3].boundStat == stat4)
 	})
 }
+
+func TestTrafficControllersFor(t *testing.T) {
+	defer ClearRules()
+
+	_, err := LoadRules([]*Rule{
+		{
+			Resource:               "abc-query",
+			TokenCalculateStrategy: Direct,
+			ControlBehavior:        Reject,
+			Threshold:              100,
+		},
+		{
+			Resource:               "abc-query",
+			TokenCalculateStrategy: WarmUp,
+			ControlBehavior:        Reject,
+			Threshold:              300,
+			WarmUpPeriodSec:        10,
+			WarmUpColdFactor:       3,
+		},
+	})
+	assert.NoError(t, err)
+
+	tcs := TrafficControllersFor("abc-query")
+	assert.Equal(t, 2, len(tcs))
+	assert.Equal(t, float64(100), tcs[0].StaticThreshold())
+	assert.Equal(t, float64(100), tcs[0].CurrentThreshold())
+	assert.Equal(t, int64(0), tcs[0].CurrentPassCount())
+	assert.Equal(t, float64(300), tcs[1].StaticThreshold())
+	// The warm-up controller is in the cold state, so the current threshold is lower than the static one.
+	assert.True(t, tcs[1].CurrentThreshold() < 300)
+
+	assert.Equal(t, 0, len(TrafficControllersFor("not-exist")))
+}
+
+func TestLoadRulesWithDeploymentLabel(t *testing.T) {
+	defer ClearRules()
+
+	rules := []*Rule{
+		{Resource: "abc", TokenCalculateStrategy: Direct, ControlBehavior: Reject, Threshold: 100},
+		{Resource: "abc", TokenCalculateStrategy: Direct, ControlBehavior: Reject, Threshold: 10, DeploymentLabel: "canary"},
+		{Resource: "def", TokenCalculateStrategy: Direct, ControlBehavior: Reject, Threshold: 100},
+		{Resource: "def", TokenCalculateStrategy: Direct, ControlBehavior: Reject, Threshold: 200, DeploymentLabel: "blue"},
+	}
+	_, err := LoadRules(rules)
+	assert.NoError(t, err)
+	// The labeled rules don't take effect for the process without labels.
+	assert.Equal(t, float64(100), GetRulesOfResource("abc")[0].Threshold)
+	assert.Equal(t, 1, len(GetRulesOfResource("def")))
+
+	cfg := config.NewDefaultConfig()
+	cfg.Sentinel.App.Labels = []string{"canary"}
+	config.SetDefaultConfig(cfg)
+	defer config.SetDefaultConfig(config.NewDefaultConfig())
+
+	_, err = LoadRules(rules)
+	assert.NoError(t, err)
+	// The canary rule overrides the unlabeled rule of the same resource.
+	abcRules := GetRulesOfResource("abc")
+	assert.Equal(t, 1, len(abcRules))
+	assert.Equal(t, float64(10), abcRules[0].Threshold)
+	defRules := GetRulesOfResource("def")
+	assert.Equal(t, 1, len(defRules))
+	assert.Equal(t, float64(100), defRules[0].Threshold)
+}
+
+func TestLoadRulesWithTargetTag(t *testing.T) {
+	defer base.ClearResourceTags()
+	defer ClearRules()
+
+	base.TagResource("tag-res-a", "tier=gold")
+	_, err := LoadRules([]*Rule{
+		{Resource: "tag-res-c", TokenCalculateStrategy: Direct, ControlBehavior: Reject, Threshold: 100},
+		{TargetTag: "tier=gold", TokenCalculateStrategy: Direct, ControlBehavior: Reject, Threshold: 10},
+		{Resource: "tag-res-d", TargetTag: "tier=gold", TokenCalculateStrategy: Direct, ControlBehavior: Reject, Threshold: 10},
+	})
+	assert.NoError(t, err)
+
+	aRules := GetRulesOfResource("tag-res-a")
+	assert.Equal(t, 1, len(aRules))
+	assert.Equal(t, float64(10), aRules[0].Threshold)
+	assert.Equal(t, "tier=gold", aRules[0].TargetTag)
+	assert.Equal(t, 0, len(GetRulesOfResource("tag-res-b")))
+	assert.Equal(t, 0, len(GetRulesOfResource("tag-res-d")))
+	aTc := TrafficControllersFor("tag-res-a")[0]
+
+	// The tag rule is bound to the newly tagged resource automatically.
+	base.TagResource("tag-res-b", "tier=gold")
+	bRules := GetRulesOfResource("tag-res-b")
+	assert.Equal(t, 1, len(bRules))
+	assert.Equal(t, float64(10), bRules[0].Threshold)
+	// The controllers of the other resources are kept.
+	assert.True(t, aTc == TrafficControllersFor("tag-res-a")[0])
+
+	// GetRules returns the tag rule itself rather than the bound copies.
+	rules := GetRules()
+	assert.Equal(t, 2, len(rules))
+	tagRuleCount := 0
+	for _, r := range rules {
+		if r.TargetTag == "tier=gold" {
+			tagRuleCount++
+			assert.Equal(t, "", r.Resource)
+		}
+	}
+	assert.Equal(t, 1, tagRuleCount)
+}
+
+func TestLoadRulesFromStore(t *testing.T) {
+	defer base.SetRuleStore(nil)
+	defer ClearRules()
+
+	store := base.NewMemoryRuleStore()
+	base.SetRuleStore(store)
+	ok, err := LoadRulesFromStore()
+	assert.False(t, ok)
+	assert.NoError(t, err)
+
+	_, err = LoadRules([]*Rule{{Resource: "store-res", TokenCalculateStrategy: Direct, ControlBehavior: Reject, Threshold: 10}})
+	assert.NoError(t, err)
+	saved, err := store.Load("flow")
+	assert.NoError(t, err)
+	assert.Contains(t, string(saved), `"resource":"store-res"`)
+	// the rules saved by the other process sharing the store
+	assert.NoError(t, store.Save("flow", []byte(`[{"resource": "store-res", "threshold": 20}]`)))
+
+	ok, err = LoadRulesFromStore()
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	rules := GetRulesOfResource("store-res")
+	assert.Equal(t, 1, len(rules))
+	assert.Equal(t, float64(20), rules[0].Threshold)
+}
+
+func TestLoadRulesPreservesControllers(t *testing.T) {
+	defer func() { _ = ClearRules() }()
+
+	newRules := func() []*Rule {
+		// the datasources deliver the new rule instances of the same content every refresh
+		return []*Rule{
+			{ID: "1", Resource: "abc", Threshold: 10, TokenCalculateStrategy: WarmUp, WarmUpPeriodSec: 10, WarmUpColdFactor: 3},
+			{ID: "2", Resource: "abc", Threshold: 10, ControlBehavior: Throttling, MaxQueueingTimeMs: 10, StatIntervalInMs: 1000},
+		}
+	}
+	_, err := LoadRules(newRules())
+	assert.Nil(t, err)
+	oldTcs := getTrafficControllerListFor("abc")
+	assert.Equal(t, 2, len(oldTcs))
+	oldTc1, oldTc2 := oldTcs[0], oldTcs[1]
+
+	_, err = LoadRules(newRules())
+	assert.Nil(t, err)
+	tcs := getTrafficControllerListFor("abc")
+	assert.Equal(t, 2, len(tcs))
+	assert.True(t, tcs[0] == oldTc1)
+	assert.True(t, tcs[1] == oldTc2)
+	// the controllers in use are never mutated
+	assert.True(t, oldTcs[0] == oldTc1 && oldTcs[1] == oldTc2)
+
+	t.Run("IdChanged", func(t *testing.T) {
+		rules := newRules()
+		rules[1].ID = "3"
+		_, err := LoadRules(rules)
+		assert.Nil(t, err)
+		tcs := getTrafficControllerListFor("abc")
+		assert.True(t, tcs[0] == oldTc1)
+		assert.Equal(t, "3", tcs[1].BoundRule().ID)
+		// the statistic is reused
+		assert.True(t, tcs[1].boundStat == oldTc2.boundStat)
+	})
+}
@@ -0,0 +1,63 @@
+package flow
+
+import (
+	"sync/atomic"
+
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+// RuleStat represents the hit statistic of a flow rule since it was loaded.
+type RuleStat struct {
+	Rule Rule
+	// EvaluatedCount is the number of times the rule was checked.
+	EvaluatedCount uint64
+	// BlockedCount is the number of requests blocked by the rule.
+	BlockedCount uint64
+	// LastTriggeredTime is the timestamp (in ms) of the latest block caused by the rule, 0 if the rule was never triggered.
+	LastTriggeredTime uint64
+	// NearLimitCount is the number of passed requests beyond the WarningCount of the rule.
+	NearLimitCount uint64
+}
+
+// ruleHitCounter records the hit statistic of the rule bound to a TrafficShapingController.
+type ruleHitCounter struct {
+	evaluatedCount    uint64
+	blockedCount      uint64
+	lastTriggeredTime uint64
+	nearLimitCount    uint64
+	// lastWarningTime is the timestamp (in ms) of the latest warning event, which limits the events
+	// to at most once per statistic interval.
+	lastWarningTime uint64
+}
+
+func (c *ruleHitCounter) record(blocked bool) {
+	atomic.AddUint64(&c.evaluatedCount, 1)
+	if blocked {
+		atomic.AddUint64(&c.blockedCount, 1)
+		atomic.StoreUint64(&c.lastTriggeredTime, util.CurrentTimeMillis())
+	}
+}
+
+// RuleStats returns the hit statistics of all the effective flow rules,
+// so that unused or over-aggressive rules could be identified.
+func RuleStats() []RuleStat {
+	tcMux.RLock()
+	defer tcMux.RUnlock()
+
+	ret := make([]RuleStat, 0)
+	for _, tcs := range tcMap {
+		for _, tc := range tcs {
+			if tc == nil || tc.BoundRule() == nil {
+				continue
+			}
+			ret = append(ret, RuleStat{
+				Rule:              *tc.BoundRule(),
+				EvaluatedCount:    atomic.LoadUint64(&tc.hitCounter.evaluatedCount),
+				BlockedCount:      atomic.LoadUint64(&tc.hitCounter.blockedCount),
+				LastTriggeredTime: atomic.LoadUint64(&tc.hitCounter.lastTriggeredTime),
+				NearLimitCount:    atomic.LoadUint64(&tc.hitCounter.nearLimitCount),
+			})
+		}
+	}
+	return ret
+}
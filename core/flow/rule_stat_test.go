@@ -0,0 +1,49 @@
+package flow
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleStats(t *testing.T) {
+	defer ClearRules()
+
+	slot := &Slot{}
+	statSlot := &StandaloneStatSlot{}
+	res := base.NewResourceWrapper("abc-rule-stat", base.ResTypeCommon, base.Inbound)
+	resNode := stat.GetOrCreateResourceNode("abc-rule-stat", base.ResTypeCommon)
+	ctx := &base.EntryContext{
+		Resource: res,
+		StatNode: resNode,
+		Input: &base.SentinelInput{
+			AcquireCount: 1,
+		},
+	}
+
+	_, err := LoadRules([]*Rule{
+		{
+			Resource:               "abc-rule-stat",
+			TokenCalculateStrategy: Direct,
+			ControlBehavior:        Reject,
+			StatIntervalInMs:       20000,
+			Threshold:              5,
+		},
+	})
+	assert.Nil(t, err)
+
+	for i := 0; i < 8; i++ {
+		if ret := slot.Check(ctx); ret == nil {
+			statSlot.OnEntryPassed(ctx)
+		}
+	}
+
+	stats := RuleStats()
+	assert.Equal(t, 1, len(stats))
+	assert.Equal(t, "abc-rule-stat", stats[0].Rule.Resource)
+	assert.Equal(t, uint64(8), stats[0].EvaluatedCount)
+	assert.Equal(t, uint64(3), stats[0].BlockedCount)
+	assert.True(t, stats[0].LastTriggeredTime > 0)
+}
@@ -0,0 +1,75 @@
+package flow
+
+import (
+	"strings"
+)
+
+// Constraint is the kind of the rule constraint violated.
+type Constraint string
+
+const (
+	// ConstraintRequired means the field must be set.
+	ConstraintRequired Constraint = "required"
+	// ConstraintRange means the value of the field is out of range.
+	ConstraintRange Constraint = "range"
+	// ConstraintEnum means the value of the field is not one of the defined constants.
+	ConstraintEnum Constraint = "enum"
+	// ConstraintExclusive means the field must not be set together with another field.
+	ConstraintExclusive Constraint = "exclusive"
+	// ConstraintDiffers means the field must differ from another field.
+	ConstraintDiffers Constraint = "differs"
+	// ConstraintDependsOn means the field (or its value) is only meaningful with certain values of other fields.
+	ConstraintDependsOn Constraint = "dependsOn"
+)
+
+// FieldError is a violated constraint of the rule field.
+type FieldError struct {
+	// Field is the name of the Rule field, empty if it's about the whole rule.
+	Field      string
+	Constraint Constraint
+	Message    string
+}
+
+func (e *FieldError) Error() string {
+	if len(e.Field) == 0 {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// ValidationErrors is the error returned by IsValidRule, which holds all the violated constraints of the rule.
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// HasField returns true if the field violates any constraint.
+func (e ValidationErrors) HasField(field string) bool {
+	for _, fe := range e {
+		if fe.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+type ruleValidator struct {
+	errs ValidationErrors
+}
+
+func (v *ruleValidator) add(field string, constraint Constraint, message string) {
+	v.errs = append(v.errs, &FieldError{Field: field, Constraint: constraint, Message: message})
+}
+
+// err returns nil (rather than the empty ValidationErrors) if the rule is valid.
+func (v *ruleValidator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
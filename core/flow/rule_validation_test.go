@@ -0,0 +1,67 @@
+package flow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidRule_ValidationErrors(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		assert.Nil(t, IsValidRule(&Rule{Resource: "abc", Threshold: 10}))
+	})
+
+	t.Run("AllViolations", func(t *testing.T) {
+		err := IsValidRule(&Rule{
+			Threshold:         -1,
+			ControlBehavior:   Reject,
+			MaxQueueingTimeMs: 500,
+		})
+		errs, ok := err.(ValidationErrors)
+		assert.True(t, ok)
+		assert.Equal(t, 2, len(errs))
+		assert.Equal(t, &FieldError{Field: "Resource", Constraint: ConstraintRequired, Message: "empty resource name"}, errs[0])
+		assert.True(t, errs.HasField("Threshold"))
+		assert.False(t, errs.HasField("MaxQueueingTimeMs"))
+		assert.False(t, errs.HasField("WarmUpColdFactor"))
+		assert.Equal(t, "Resource: empty resource name; Threshold: negative threshold", err.Error())
+	})
+
+	t.Run("CrossField", func(t *testing.T) {
+		cases := []struct {
+			rule       *Rule
+			field      string
+			constraint Constraint
+		}{
+			{&Rule{Resource: "abc", RelationStrategy: AssociatedResource, RefResource: "abc"}, "RefResource", ConstraintDiffers},
+			{&Rule{Resource: "abc", RelationStrategy: AssociatedResource}, "RefResource", ConstraintRequired},
+			{&Rule{Resource: "abc", TokenCalculateStrategy: WarmUp, WarmUpPeriodSec: 10, WarmUpColdFactor: 1}, "WarmUpColdFactor", ConstraintRange},
+			{&Rule{Resource: "abc", ControlBehavior: Throttling}, "MaxQueueingTimeMs", ConstraintRequired},
+			{&Rule{Resource: "abc", TargetTag: "tier=gold"}, "TargetTag", ConstraintExclusive},
+		}
+		for _, c := range cases {
+			errs, ok := IsValidRule(c.rule).(ValidationErrors)
+			assert.True(t, ok, c.field)
+			assert.Equal(t, 1, len(errs), c.field)
+			assert.Equal(t, c.field, errs[0].Field)
+			assert.Equal(t, c.constraint, errs[0].Constraint)
+		}
+	})
+
+	t.Run("QueueingFieldsIgnored", func(t *testing.T) {
+		// e.g. the rules from the dashboard always carry maxQueueingTimeMs
+		assert.Nil(t, IsValidRule(&Rule{Resource: "abc", Threshold: 10, MaxQueueingTimeMs: 500}))
+		assert.Nil(t, IsValidRule(&Rule{Resource: "abc", Threshold: 10, MaxQueueingRequests: 10}))
+		assert.Nil(t, IsValidRule(&Rule{Resource: "abc", Threshold: 10, TokenCalculateStrategy: WarmUp, WarmUpPeriodSec: 10, MaxQueueingTimeMs: 500}))
+	})
+
+	t.Run("DefaultColdFactor", func(t *testing.T) {
+		assert.Nil(t, IsValidRule(&Rule{Resource: "abc", Threshold: 10, TokenCalculateStrategy: WarmUp, WarmUpPeriodSec: 10}))
+	})
+
+	t.Run("NilRule", func(t *testing.T) {
+		errs, ok := IsValidRule(nil).(ValidationErrors)
+		assert.True(t, ok)
+		assert.Equal(t, "nil Rule", errs.Error())
+	})
+}
@@ -0,0 +1,116 @@
+package flow
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/config"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+// scaleWarmUp represents the warm-up of all the resources triggered by the scale event, during which
+// the effective thresholds ramp up linearly from 1/coldFactor of the thresholds to the full thresholds.
+type scaleWarmUp struct {
+	startMs    uint64
+	periodMs   uint64
+	coldFactor uint32
+}
+
+func (w *scaleWarmUp) finished(now uint64) bool {
+	return now >= w.startMs+w.periodMs
+}
+
+// progressAt returns the progress of the warm-up, 0 when the warm-up starts and 1 when finished.
+func (w *scaleWarmUp) progressAt(now uint64) float64 {
+	if now <= w.startMs {
+		return 0
+	}
+	if w.finished(now) {
+		return 1
+	}
+	return float64(now-w.startMs) / float64(w.periodMs)
+}
+
+func (w *scaleWarmUp) factorAt(now uint64) float64 {
+	coldRatio := 1.0 / float64(w.coldFactor)
+	return coldRatio + (1-coldRatio)*w.progressAt(now)
+}
+
+var (
+	// processStartMs approximates the time when the process (i.e. the instance) starts.
+	processStartMs = util.CurrentTimeMillis()
+	// activeScaleWarmUp is the scale warm-up in progress, guarded by scalingMux.
+	activeScaleWarmUp *scaleWarmUp
+)
+
+// TriggerScaleWarmUp is the hook of the scale events (e.g. called by the deployment tooling after the instance
+// is scaled up), which puts all the resources with flow rules into the warm-up mode for the period: the effective
+// thresholds start from 1/coldFactor of the thresholds and ramp up linearly to the full thresholds, so that the fresh
+// instances admit the traffic gradually. The ramp multiplies the effective thresholds like ScaleThresholds, so that
+// it works with any control behavior and follows the threshold changes during the warm-up. coldFactor 0 means
+// config.DefaultWarmUpColdFactor. The latest trigger overrides the previous one.
+func TriggerScaleWarmUp(period time.Duration, coldFactor uint32) error {
+	return startScaleWarmUp(util.CurrentTimeMillis(), period, coldFactor)
+}
+
+// WarmUpFreshInstance triggers the scale warm-up (see TriggerScaleWarmUp) if the instance is fresh, i.e. its age
+// since the process starts is less than the period. The ramp is regarded as started when the process starts,
+// so the instance only warms up for the rest of the period. It returns whether the warm-up is triggered.
+func WarmUpFreshInstance(period time.Duration, coldFactor uint32) bool {
+	if period < time.Millisecond {
+		return false
+	}
+	if util.CurrentTimeMillis() >= processStartMs+uint64(period/time.Millisecond) {
+		return false
+	}
+	return startScaleWarmUp(processStartMs, period, coldFactor) == nil
+}
+
+func startScaleWarmUp(startMs uint64, period time.Duration, coldFactor uint32) error {
+	if period < time.Millisecond {
+		return errors.New("the period of scale warm-up must be at least 1ms")
+	}
+	if coldFactor == 0 {
+		coldFactor = config.DefaultWarmUpColdFactor
+	}
+	if coldFactor <= 1 {
+		return errors.New("the cold factor of scale warm-up must be greater than 1")
+	}
+	scalingMux.Lock()
+	defer scalingMux.Unlock()
+
+	activeScaleWarmUp = &scaleWarmUp{
+		startMs:    startMs,
+		periodMs:   uint64(period / time.Millisecond),
+		coldFactor: coldFactor,
+	}
+	atomic.StoreInt32(&scalingActive, 1)
+	logging.Info("[FlowScaleWarmUp] Scale warm-up of all resources was triggered", "period", period, "coldFactor", coldFactor)
+	return nil
+}
+
+// StopScaleWarmUp stops the scale warm-up in progress immediately, and the full thresholds take effect.
+func StopScaleWarmUp() {
+	scalingMux.Lock()
+	defer scalingMux.Unlock()
+
+	activeScaleWarmUp = nil
+	if globalScaling == nil && len(patternScalings) == 0 {
+		atomic.StoreInt32(&scalingActive, 0)
+	}
+}
+
+// ScaleWarmUpProgress returns the progress of the scale warm-up in progress, 0 when the warm-up starts
+// and approaching 1 when about to finish. It returns false if there is no scale warm-up in progress.
+func ScaleWarmUpProgress() (float64, bool) {
+	now := util.CurrentTimeMillis()
+	scalingMux.RLock()
+	defer scalingMux.RUnlock()
+
+	if activeScaleWarmUp == nil || activeScaleWarmUp.finished(now) {
+		return 0, false
+	}
+	return activeScaleWarmUp.progressAt(now), true
+}
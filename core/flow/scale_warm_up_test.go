@@ -0,0 +1,90 @@
+package flow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScaleWarmUp_FactorAt(t *testing.T) {
+	w := &scaleWarmUp{startMs: 1000, periodMs: 1000, coldFactor: 4}
+	assert.InDelta(t, 0.25, w.factorAt(500), 0.0001)
+	assert.InDelta(t, 0.25, w.factorAt(1000), 0.0001)
+	assert.InDelta(t, 0.625, w.factorAt(1500), 0.0001)
+	assert.InDelta(t, 1, w.factorAt(2000), 0.0001)
+	assert.False(t, w.finished(1999))
+	assert.True(t, w.finished(2000))
+}
+
+func TestTriggerScaleWarmUp(t *testing.T) {
+	defer StopScaleWarmUp()
+
+	assert.Error(t, TriggerScaleWarmUp(0, 3))
+	assert.Error(t, TriggerScaleWarmUp(time.Second, 1))
+	_, ok := ScaleWarmUpProgress()
+	assert.False(t, ok)
+
+	assert.NoError(t, TriggerScaleWarmUp(100*time.Millisecond, 0))
+	progress, ok := ScaleWarmUpProgress()
+	assert.True(t, ok)
+	assert.True(t, progress < 0.5)
+	factor := thresholdScaleFactorOf("abc")
+	assert.True(t, factor >= 1.0/3 && factor < 0.7, factor)
+
+	// combined with the threshold scaling
+	assert.NoError(t, ScaleThresholds(0.5, time.Second))
+	assert.True(t, thresholdScaleFactorOf("abc") < 0.35)
+	ResetThresholdScaling()
+	assert.True(t, thresholdScaleFactorOf("abc") < 0.7)
+
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, float64(1), thresholdScaleFactorOf("abc"))
+	assert.Equal(t, int32(0), scalingActive)
+	_, ok = ScaleWarmUpProgress()
+	assert.False(t, ok)
+
+	assert.NoError(t, TriggerScaleWarmUp(time.Second, 3))
+	StopScaleWarmUp()
+	assert.Equal(t, float64(1), thresholdScaleFactorOf("abc"))
+}
+
+func TestWarmUpFreshInstance(t *testing.T) {
+	defer StopScaleWarmUp()
+
+	oldStart := processStartMs
+	defer func() {
+		processStartMs = oldStart
+	}()
+
+	processStartMs = util.CurrentTimeMillis() - 10000
+	assert.False(t, WarmUpFreshInstance(5*time.Second, 3))
+	_, ok := ScaleWarmUpProgress()
+	assert.False(t, ok)
+
+	// the instance has been up for half of the period
+	assert.True(t, WarmUpFreshInstance(20*time.Second, 3))
+	progress, ok := ScaleWarmUpProgress()
+	assert.True(t, ok)
+	assert.InDelta(t, 0.5, progress, 0.05)
+}
+
+func TestTrafficShapingController_CurrentThresholdWithScaleWarmUp(t *testing.T) {
+	defer ClearRules()
+	defer StopScaleWarmUp()
+
+	_, err := LoadRules([]*Rule{
+		{
+			Resource:               "abc-scale-warm-up",
+			TokenCalculateStrategy: Direct,
+			ControlBehavior:        Reject,
+			Threshold:              90,
+		},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, TriggerScaleWarmUp(time.Minute, 3))
+
+	tc := TrafficControllersFor("abc-scale-warm-up")[0]
+	assert.InDelta(t, 30, tc.CurrentThreshold(), 1)
+}
@@ -0,0 +1,65 @@
+package flow
+
+import (
+	"sync"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/config"
+	"github.com/pkg/errors"
+)
+
+// SharedStat is the statistic shared by all the processes on the host, e.g. backed by the shared memory.
+type SharedStat interface {
+	base.ReadStat
+	base.WriteStat
+}
+
+// SharedStatFactory returns the SharedStat of the key with the given sample count and interval.
+// The processes on the host get the same statistic with the same key.
+type SharedStatFactory func(key string, sampleCount, intervalInMs uint32) (SharedStat, error)
+
+var (
+	sharedStatFactory    SharedStatFactory
+	sharedStatFactoryMux = new(sync.RWMutex)
+)
+
+// SetSharedStatFactory sets the factory of the statistic for the rules with SharedStatKey,
+// which must be set before loading such rules. nil means no shared statistic backend.
+func SetSharedStatFactory(f SharedStatFactory) {
+	sharedStatFactoryMux.Lock()
+	defer sharedStatFactoryMux.Unlock()
+
+	sharedStatFactory = f
+}
+
+func currentSharedStatFactory() SharedStatFactory {
+	sharedStatFactoryMux.RLock()
+	defer sharedStatFactoryMux.RUnlock()
+
+	return sharedStatFactory
+}
+
+// generateSharedStatFor generates the standalone statistic of the rule backed by the shared statistic.
+func generateSharedStatFor(rule *Rule) (*standaloneStatistic, error) {
+	factory := currentSharedStatFactory()
+	if factory == nil {
+		return nil, errors.Errorf("no shared statistic factory for the rule with SharedStatKey: %s", rule.SharedStatKey)
+	}
+	intervalInMs := rule.StatIntervalInMs
+	if intervalInMs == 0 {
+		intervalInMs = config.MetricStatisticIntervalMs()
+	}
+	sampleCount := uint32(1)
+	if bucketLength := config.GlobalStatisticBucketLengthInMs(); intervalInMs > bucketLength && intervalInMs%bucketLength == 0 {
+		sampleCount = intervalInMs / bucketLength
+	}
+	s, err := factory(rule.SharedStatKey, sampleCount, intervalInMs)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to generate the shared statistic of key: %s", rule.SharedStatKey)
+	}
+	return &standaloneStatistic{
+		reuseResourceStat: false,
+		readOnlyMetric:    s,
+		writeOnlyMetric:   s,
+	}, nil
+}
@@ -0,0 +1,34 @@
+package flow
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_generateSharedStatFor(t *testing.T) {
+	defer SetSharedStatFactory(nil)
+
+	rule := &Rule{Resource: "abc", TokenCalculateStrategy: Direct, ControlBehavior: Reject, Threshold: 10, SharedStatKey: "abc"}
+	_, err := generateSharedStatFor(rule)
+	assert.Error(t, err)
+
+	var gotSampleCount, gotIntervalInMs uint32
+	SetSharedStatFactory(func(key string, sampleCount, intervalInMs uint32) (SharedStat, error) {
+		gotSampleCount, gotIntervalInMs = sampleCount, intervalInMs
+		return nil, errors.New("test")
+	})
+	_, err = generateSharedStatFor(rule)
+	assert.Error(t, err)
+	assert.Equal(t, uint32(2), gotSampleCount)
+	assert.Equal(t, uint32(1000), gotIntervalInMs)
+
+	rule.ControlBehavior = Throttling
+	rule.MaxQueueingTimeMs = 10
+	assert.Error(t, IsValidRule(rule))
+	rule.ControlBehavior = Reject
+	rule.RelationStrategy = AssociatedResource
+	rule.RefResource = "def"
+	assert.Error(t, IsValidRule(rule))
+}
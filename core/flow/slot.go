@@ -12,20 +12,45 @@ import (
 type Slot struct {
 }
 
+// RulesIndexed implements base.IndexedRuleCheckSlot, as all the rules are registered to the rule resource index.
+func (s *Slot) RulesIndexed() bool {
+	return true
+}
+
 func (s *Slot) Check(ctx *base.EntryContext) *base.TokenResult {
+	if !Enabled() {
+		return ctx.RuleCheckResult
+	}
 	res := ctx.Resource.Name()
-	tcs := getTrafficControllerListFor(res)
 	result := ctx.RuleCheckResult
 
-	// Check rules in order
+	if r := reservationOf(ctx); r != nil && r.tryConsume(res, ctx.Input.AcquireCount) {
+		// The capacity has been reserved, wait until the reservation is ready.
+		r.Wait()
+		return result
+	}
+
+	tcs, arm := canaryControllersFor(res, ctx.Category(), cachedControllersFor(res, ctx.Category(), checkView))
+	r := checkTrafficControllers(ctx, res, tcs)
+	if arm != nil {
+		arm.record(r != nil && r.Status() == base.ResultStatusBlocked)
+	}
+	return r
+}
+
+func checkTrafficControllers(ctx *base.EntryContext, res string, tcs []*TrafficShapingController) *base.TokenResult {
+	result := ctx.RuleCheckResult
+	// Check rules in order, the controllers have been filtered by the category of the request.
 	for _, tc := range tcs {
 		if tc == nil {
 			logging.Warn("nil traffic controller found", "resourceName", res)
 			continue
 		}
-		r := canPassCheck(tc, ctx.StatNode, ctx.Input.AcquireCount)
+		r := canPassCheck(tc, ctx.StatNode, tokenCountOf(ctx, tc.rule))
+		tc.hitCounter.record(r != nil && r.Status() == base.ResultStatusBlocked)
 		if r == nil {
 			// nil means pass
+			checkWarning(tc, ctx.Input.AcquireCount)
 			continue
 		}
 		if r.Status() == base.ResultStatusBlocked {
@@ -35,6 +60,9 @@ func (s *Slot) Check(ctx *base.EntryContext) *base.TokenResult {
 			if waitMs := r.WaitMs(); waitMs > 0 {
 				// Handle waiting action.
 				time.Sleep(time.Duration(waitMs) * time.Millisecond)
+				if qt, ok := tc.flowChecker.(queueingTracker); ok {
+					qt.onQueueingFinished()
+				}
 			}
 			continue
 		}
@@ -52,7 +80,16 @@ func canPassCheckWithFlag(tc *TrafficShapingController, node base.StatNode, acqu
 
 func selectNodeByRelStrategy(rule *Rule, node base.StatNode) base.StatNode {
 	if rule.RelationStrategy == AssociatedResource {
-		return stat.GetResourceNode(rule.RefResource)
+		refNode := stat.GetResourceNode(rule.RefResource)
+		if rule.Category != base.CategoryNone && refNode != nil {
+			return refNode.GetOrCreateCategoryNode(rule.Category)
+		}
+		return refNode
+	}
+	if rule.Category != base.CategoryNone {
+		if resNode, ok := node.(*stat.ResourceNode); ok && resNode != nil {
+			return resNode.GetOrCreateCategoryNode(rule.Category)
+		}
 	}
 	return node
 }
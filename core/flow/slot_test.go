@@ -52,3 +52,50 @@ func Test_FlowSlot_StandaloneStat(t *testing.T) {
 	}
 	assert.True(t, getTrafficControllerListFor("abc")[0].boundStat.readOnlyMetric.GetSum(base.MetricEventPass) == 50)
 }
+
+func Test_FlowSlot_Category(t *testing.T) {
+	defer func() {
+		_ = ClearRules()
+		stat.ResetResourceNodeMap()
+	}()
+
+	slot := &Slot{}
+	statSlot := &stat.Slot{}
+	resNode := stat.GetOrCreateResourceNode("abc-category", base.ResTypeCommon)
+	newCtx := func(category base.RequestCategory) *base.EntryContext {
+		return &base.EntryContext{
+			Resource: base.NewResourceWrapper("abc-category", base.ResTypeCommon, base.Outbound),
+			StatNode: resNode,
+			Input: &base.SentinelInput{
+				AcquireCount: 1,
+				Attachments:  map[interface{}]interface{}{base.CategoryAttachmentKey: category},
+			},
+		}
+	}
+	_, err := LoadRules([]*Rule{
+		{Resource: "abc-category", Category: base.CategoryRead, Threshold: 5},
+		{Resource: "abc-category", Category: base.CategoryWrite, Threshold: 2},
+	})
+	assert.Nil(t, err)
+
+	for i := 0; i < 2; i++ {
+		ctx := newCtx(base.CategoryWrite)
+		assert.Nil(t, slot.Check(ctx))
+		statSlot.OnEntryPassed(ctx)
+	}
+	// the write requests exceed the limit of the write category
+	ret := slot.Check(newCtx(base.CategoryWrite))
+	assert.True(t, ret != nil && ret.IsBlocked())
+	// while the read requests are limited separately
+	for i := 0; i < 5; i++ {
+		ctx := newCtx(base.CategoryRead)
+		assert.Nil(t, slot.Check(ctx))
+		statSlot.OnEntryPassed(ctx)
+	}
+	ret = slot.Check(newCtx(base.CategoryRead))
+	assert.True(t, ret != nil && ret.IsBlocked())
+	// the uncategorized requests are not limited by the category rules
+	assert.Nil(t, slot.Check(newCtx(base.CategoryNone)))
+
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc-category", Category: base.RequestCategory(5), Threshold: 5}))
+}
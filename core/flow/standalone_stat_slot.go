@@ -11,15 +11,14 @@ type StandaloneStatSlot struct {
 
 func (s StandaloneStatSlot) OnEntryPassed(ctx *base.EntryContext) {
 	res := ctx.Resource.Name()
-	for _, tc := range getTrafficControllerListFor(res) {
-		if !tc.boundStat.reuseResourceStat {
-			if tc.boundStat.writeOnlyMetric != nil {
-				tc.boundStat.writeOnlyMetric.AddCount(base.MetricEventPass, int64(ctx.Input.AcquireCount))
-			} else {
-				logging.Error(errors.New("nil independent write statistic"), "flow module: nil statistic for traffic control", "rule", tc.rule)
-			}
+	for _, tc := range cachedControllersFor(res, ctx.Category(), standaloneStatView) {
+		if tc.boundStat.writeOnlyMetric != nil {
+			tc.boundStat.writeOnlyMetric.AddCount(base.MetricEventPass, int64(tokenCountOf(ctx, tc.rule)))
+		} else {
+			logging.Error(errors.New("nil independent write statistic"), "flow module: nil statistic for traffic control", "rule", tc.rule)
 		}
 	}
+	recordCanaryPass(ctx)
 }
 
 func (s StandaloneStatSlot) OnEntryBlocked(ctx *base.EntryContext, blockError *base.BlockError) {
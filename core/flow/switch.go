@@ -0,0 +1,26 @@
+package flow
+
+import (
+	"sync/atomic"
+
+	"github.com/alibaba/sentinel-golang/logging"
+)
+
+// disabled indicates whether the flow control is disabled at runtime, see SetEnabled.
+var disabled int32
+
+// SetEnabled enables or disables the flow control at runtime, which is enabled by default.
+// The flow slot is short-circuited while disabled, but the rules are kept.
+func SetEnabled(e bool) {
+	if e {
+		atomic.StoreInt32(&disabled, 0)
+	} else {
+		atomic.StoreInt32(&disabled, 1)
+	}
+	logging.Info("[Flow] Flow control was toggled", "enabled", e)
+}
+
+// Enabled returns whether the flow control is enabled.
+func Enabled() bool {
+	return atomic.LoadInt32(&disabled) == 0
+}
@@ -1,6 +1,8 @@
 package flow
 
 import (
+	"sync/atomic"
+
 	"github.com/alibaba/sentinel-golang/core/base"
 )
 
@@ -27,6 +29,8 @@ func (d *DirectTrafficShapingCalculator) BoundOwner() *TrafficShapingController
 type RejectTrafficShapingChecker struct {
 	owner *TrafficShapingController
 	rule  *Rule
+	// reserved is the amount of the reserved requests not passed yet, see Reserve.
+	reserved int64
 }
 
 func NewRejectTrafficShapingChecker(owner *TrafficShapingController, rule *Rule) *RejectTrafficShapingChecker {
@@ -46,8 +50,34 @@ func (d *RejectTrafficShapingChecker) DoCheck(resStat base.StatNode, acquireCoun
 		return nil
 	}
 	curCount := float64(metricReadonlyStat.GetSum(base.MetricEventPass))
-	if curCount+float64(acquireCount) > threshold {
+	if curCount+float64(atomic.LoadInt64(&d.reserved))+float64(acquireCount) > threshold {
 		return base.NewTokenResultBlockedWithCause(base.BlockTypeFlow, "", d.rule, curCount)
 	}
 	return nil
 }
+
+func (d *RejectTrafficShapingChecker) reserve(acquireCount uint32, threshold float64, _ uint64) (uint64, bool) {
+	metricReadonlyStat := d.BoundOwner().boundStat.readOnlyMetric
+	if metricReadonlyStat == nil {
+		return 0, true
+	}
+	curCount := float64(metricReadonlyStat.GetSum(base.MetricEventPass))
+	for {
+		reserved := atomic.LoadInt64(&d.reserved)
+		// The capacity released in the future is unpredictable, so the reservation succeeds only if it's available now.
+		if curCount+float64(reserved)+float64(acquireCount) > threshold {
+			return 0, false
+		}
+		if atomic.CompareAndSwapInt64(&d.reserved, reserved, reserved+int64(acquireCount)) {
+			return 0, true
+		}
+	}
+}
+
+func (d *RejectTrafficShapingChecker) onReservationConsumed(acquireCount uint32, _ float64) {
+	atomic.AddInt64(&d.reserved, -int64(acquireCount))
+}
+
+func (d *RejectTrafficShapingChecker) cancelReservation(acquireCount uint32, _ float64) {
+	atomic.AddInt64(&d.reserved, -int64(acquireCount))
+}
@@ -6,25 +6,114 @@ import (
 	"time"
 
 	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/config"
+	sbase "github.com/alibaba/sentinel-golang/core/stat/base"
 	"github.com/alibaba/sentinel-golang/util"
 )
 
 const nanoUnitOffset = time.Second / time.Nanosecond
 
+// queueingTracker is implemented by the TrafficShapingChecker that keeps track of the requests waiting in queue.
+// Every result of ShouldWait with positive wait time yielded by the checker occupies a queueing slot,
+// and onQueueingFinished must be invoked to release the slot once the request finishes waiting.
+type queueingTracker interface {
+	onQueueingFinished()
+}
+
 // ThrottlingChecker limits the time interval between two requests.
 type ThrottlingChecker struct {
 	owner             *TrafficShapingController
 	maxQueueingTimeNs uint64
-	lastPassedTime    uint64
+	// maxQueueingRequests is the max amount of requests waiting in queue, 0 means no limit.
+	maxQueueingRequests int64
+	queueingCount       int64
+	lastPassedTime      uint64
+	// queueingDelayStat records the queueing delay of the passed requests in the recent statistic interval,
+	// the MetricEventPass is the amount of passed requests and the MetricEventRt is the sum of delay in microseconds.
+	queueingDelayStat *sbase.BucketLeapArray
+}
+
+// ThrottlingDiagnostics is the diagnostics of the ThrottlingChecker, which tells why the requests are delayed.
+type ThrottlingDiagnostics struct {
+	// QueueHeadTime is the timestamp (in ms) when the latest admitted request passes, the new requests line up behind it.
+	// It's in the past if there's nothing queued.
+	QueueHeadTime uint64
+	// QueueingDelay is how long the latest admitted request is going to wait from now on.
+	QueueingDelay time.Duration
+	// QueueingCount is the amount of requests waiting in queue currently.
+	QueueingCount int64
+	// AvgQueueingDelay is the average queueing delay of the passed requests in the recent statistic interval
+	// (see config.MetricStatisticIntervalMs), including the requests passed without waiting.
+	AvgQueueingDelay time.Duration
+	// PassedCount is the amount of passed requests in the recent statistic interval.
+	PassedCount int64
+	// MaxQueueingTime is the max time a request could wait in queue.
+	MaxQueueingTime time.Duration
 }
 
 func NewThrottlingChecker(owner *TrafficShapingController, timeoutMs uint32) *ThrottlingChecker {
+	return NewThrottlingCheckerWithMaxQueueing(owner, timeoutMs, 0)
+}
+
+// NewThrottlingCheckerWithMaxQueueing creates a ThrottlingChecker that rejects requests immediately
+// once there are already maxQueueingRequests requests waiting in queue.
+func NewThrottlingCheckerWithMaxQueueing(owner *TrafficShapingController, timeoutMs uint32, maxQueueingRequests uint32) *ThrottlingChecker {
 	return &ThrottlingChecker{
-		owner:             owner,
-		maxQueueingTimeNs: uint64(timeoutMs) * util.UnixTimeUnitOffset,
-		lastPassedTime:    0,
+		owner:               owner,
+		maxQueueingTimeNs:   uint64(timeoutMs) * util.UnixTimeUnitOffset,
+		maxQueueingRequests: int64(maxQueueingRequests),
+		queueingCount:       0,
+		lastPassedTime:      0,
+		queueingDelayStat:   sbase.NewBucketLeapArray(config.MetricStatisticSampleCount(), config.MetricStatisticIntervalMs()),
 	}
 }
+
+// Diagnostics returns the diagnostics of the queueing of current checker.
+func (c *ThrottlingChecker) Diagnostics() ThrottlingDiagnostics {
+	passed := c.queueingDelayStat.Count(base.MetricEventPass)
+	ret := ThrottlingDiagnostics{
+		QueueHeadTime:   atomic.LoadUint64(&c.lastPassedTime) / util.UnixTimeUnitOffset,
+		QueueingDelay:   c.QueueingDelay(),
+		QueueingCount:   c.QueueingCount(),
+		PassedCount:     passed,
+		MaxQueueingTime: c.MaxQueueingTime(),
+	}
+	if passed > 0 {
+		ret.AvgQueueingDelay = time.Duration(c.queueingDelayStat.Count(base.MetricEventRt)/passed) * time.Microsecond
+	}
+	return ret
+}
+
+// recordQueueingDelay records the queueing delay of the passed request.
+func (c *ThrottlingChecker) recordQueueingDelay(delayNs uint64) {
+	c.queueingDelayStat.AddCount(base.MetricEventPass, 1)
+	if delayUs := int64(delayNs / uint64(time.Microsecond)); delayUs > 0 {
+		c.queueingDelayStat.AddCount(base.MetricEventRt, delayUs)
+	}
+}
+
+// QueueingCount returns the amount of requests waiting in queue currently.
+func (c *ThrottlingChecker) QueueingCount() int64 {
+	return atomic.LoadInt64(&c.queueingCount)
+}
+
+// QueueingDelay returns how long the last queued request is going to wait from now on, 0 if nothing is queued.
+func (c *ThrottlingChecker) QueueingDelay() time.Duration {
+	last, now := atomic.LoadUint64(&c.lastPassedTime), util.CurrentTimeNano()
+	if last <= now {
+		return 0
+	}
+	return time.Duration(last - now)
+}
+
+// MaxQueueingTime returns the max time a request could wait in queue.
+func (c *ThrottlingChecker) MaxQueueingTime() time.Duration {
+	return time.Duration(c.maxQueueingTimeNs)
+}
+
+func (c *ThrottlingChecker) onQueueingFinished() {
+	atomic.AddInt64(&c.queueingCount, -1)
+}
 func (c *ThrottlingChecker) BoundOwner() *TrafficShapingController {
 	return c.owner
 }
@@ -47,11 +136,12 @@ func (c *ThrottlingChecker) DoCheck(_ base.StatNode, acquireCount uint32, thresh
 	if expectedTime <= curNano {
 		// Contention may exist here, but it's okay.
 		atomic.StoreUint64(&c.lastPassedTime, curNano)
+		c.recordQueueingDelay(0)
 		return nil
 	}
 	estimatedQueueingDuration := atomic.LoadUint64(&c.lastPassedTime) + interval - util.CurrentTimeNano()
 	if estimatedQueueingDuration > c.maxQueueingTimeNs {
-		return base.NewTokenResultBlocked(base.BlockTypeFlow)
+		return c.blockedWithWaitHint(estimatedQueueingDuration - c.maxQueueingTimeNs)
 	}
 
 	oldTime := atomic.AddUint64(&c.lastPassedTime, interval)
@@ -59,11 +149,60 @@ func (c *ThrottlingChecker) DoCheck(_ base.StatNode, acquireCount uint32, thresh
 	if estimatedQueueingDuration > c.maxQueueingTimeNs {
 		// Subtract the interval.
 		atomic.AddUint64(&c.lastPassedTime, ^(interval - 1))
-		return base.NewTokenResultBlocked(base.BlockTypeFlow)
+		return c.blockedWithWaitHint(estimatedQueueingDuration - c.maxQueueingTimeNs)
 	}
-	if estimatedQueueingDuration > 0 {
-		return base.NewTokenResultShouldWait(estimatedQueueingDuration / util.UnixTimeUnitOffset)
-	} else {
+	waitMs := estimatedQueueingDuration / util.UnixTimeUnitOffset
+	if estimatedQueueingDuration <= 0 || waitMs == 0 {
+		c.recordQueueingDelay(0)
 		return base.NewTokenResultShouldWait(0)
 	}
+	if queueing := atomic.AddInt64(&c.queueingCount, 1); c.maxQueueingRequests > 0 && queueing > c.maxQueueingRequests {
+		atomic.AddInt64(&c.queueingCount, -1)
+		atomic.AddUint64(&c.lastPassedTime, ^(interval - 1))
+		// the queue is full, the request could be queued after the current queue drains
+		return c.blockedWithWaitHint(estimatedQueueingDuration)
+	}
+	c.recordQueueingDelay(estimatedQueueingDuration)
+	return base.NewTokenResultShouldWait(waitMs)
+}
+
+// blockedWithWaitHint blocks the request with the estimated time (in nanoseconds) to wait before
+// the request could be queued within the max queueing time.
+func (c *ThrottlingChecker) blockedWithWaitHint(waitNs uint64) *base.TokenResult {
+	return base.NewTokenResultBlockedWithWaitHint(base.BlockTypeFlow, "throttling queueing timeout", time.Duration(waitNs))
+}
+
+func (c *ThrottlingChecker) reserve(acquireCount uint32, threshold float64, maxWaitNs uint64) (uint64, bool) {
+	if threshold <= 0 {
+		return 0, false
+	}
+	curNano := util.CurrentTimeNano()
+	interval := uint64(math.Ceil(float64(acquireCount) / threshold * float64(nanoUnitOffset)))
+	if atomic.LoadUint64(&c.lastPassedTime)+interval <= curNano {
+		atomic.StoreUint64(&c.lastPassedTime, curNano)
+		return 0, true
+	}
+	// The reserved requests occupy the queue in advance, regardless of the max queueing time.
+	expectedTime := atomic.AddUint64(&c.lastPassedTime, interval)
+	waitNs := expectedTime - curNano
+	if expectedTime < curNano {
+		waitNs = 0
+	}
+	if waitNs > maxWaitNs {
+		atomic.AddUint64(&c.lastPassedTime, ^(interval - 1))
+		return 0, false
+	}
+	return waitNs, true
+}
+
+func (c *ThrottlingChecker) onReservationConsumed(uint32, float64) {
+	// The pass time of the reserved requests has been occupied, nothing to release.
+}
+
+func (c *ThrottlingChecker) cancelReservation(acquireCount uint32, threshold float64) {
+	if threshold <= 0 {
+		return
+	}
+	interval := uint64(math.Ceil(float64(acquireCount) / threshold * float64(nanoUnitOffset)))
+	atomic.AddUint64(&c.lastPassedTime, ^(interval - 1))
 }
@@ -75,3 +75,66 @@ func TestThrottlingChecker_DoCheckQueueingParallel(t *testing.T) {
 	// Non-strict mode may not be strictly accurate, so here we tolerate a delta.
 	assert.InEpsilon(t, qps, waitCount, 1)
 }
+
+func TestThrottlingChecker_DoCheckMaxQueueingRequests(t *testing.T) {
+	tc := NewThrottlingCheckerWithMaxQueueing(nil, 10000, 2)
+	var qps float64 = 5
+
+	assert.True(t, tc.DoCheck(nil, 1, qps) == nil)
+	assert.True(t, tc.DoCheck(nil, 1, qps).Status() == base.ResultStatusShouldWait)
+	assert.True(t, tc.DoCheck(nil, 1, qps).Status() == base.ResultStatusShouldWait)
+	assert.Equal(t, int64(2), tc.QueueingCount())
+	// Exceeds the max queueing requests, though the queueing time is still acceptable.
+	assert.True(t, tc.DoCheck(nil, 1, qps).IsBlocked())
+	assert.Equal(t, int64(2), tc.QueueingCount())
+
+	tc.onQueueingFinished()
+	assert.True(t, tc.DoCheck(nil, 1, qps).Status() == base.ResultStatusShouldWait)
+	assert.Equal(t, int64(2), tc.QueueingCount())
+}
+
+func TestThrottlingChecker_WaitHint(t *testing.T) {
+	tc := NewThrottlingChecker(nil, 0)
+	var qps float64 = 5
+
+	assert.True(t, tc.DoCheck(nil, 1, qps) == nil)
+	ret := tc.DoCheck(nil, 1, qps)
+	assert.True(t, ret.IsBlocked())
+	hint := ret.BlockError().WaitHint()
+	assert.True(t, hint > 150*time.Millisecond && hint <= 200*time.Millisecond, hint)
+
+	time.Sleep(hint + 10*time.Millisecond)
+	assert.True(t, tc.DoCheck(nil, 1, qps) == nil)
+
+	// no hint if the threshold is zero
+	assert.Equal(t, time.Duration(0), tc.DoCheck(nil, 1, 0).BlockError().WaitHint())
+}
+
+func TestThrottlingChecker_Diagnostics(t *testing.T) {
+	tc := NewThrottlingChecker(nil, 1000)
+	var qps float64 = 10
+
+	d := tc.Diagnostics()
+	assert.Equal(t, int64(0), d.PassedCount)
+	assert.Equal(t, time.Duration(0), d.AvgQueueingDelay)
+	assert.Equal(t, time.Second, d.MaxQueueingTime)
+
+	// The first request passes directly, the following ones wait 100ms, 200ms in order.
+	assert.True(t, tc.DoCheck(nil, 1, qps) == nil)
+	assert.True(t, tc.DoCheck(nil, 1, qps).Status() == base.ResultStatusShouldWait)
+	assert.True(t, tc.DoCheck(nil, 1, qps).Status() == base.ResultStatusShouldWait)
+
+	d = tc.Diagnostics()
+	assert.Equal(t, int64(3), d.PassedCount)
+	assert.Equal(t, int64(2), d.QueueingCount)
+	assert.True(t, d.AvgQueueingDelay > 90*time.Millisecond && d.AvgQueueingDelay <= 100*time.Millisecond, d.AvgQueueingDelay)
+	assert.True(t, d.QueueingDelay > 180*time.Millisecond && d.QueueingDelay <= 200*time.Millisecond, d.QueueingDelay)
+	assert.True(t, d.QueueHeadTime > uint64(time.Now().UnixNano()/int64(time.Millisecond)))
+
+	c := &TrafficShapingController{flowChecker: tc}
+	cd, ok := c.ThrottlingDiagnostics()
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), cd.PassedCount)
+	_, ok = (&TrafficShapingController{flowChecker: NewRejectTrafficShapingChecker(nil, nil)}).ThrottlingDiagnostics()
+	assert.False(t, ok)
+}
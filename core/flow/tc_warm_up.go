@@ -15,11 +15,16 @@ type WarmUpTrafficShapingCalculator struct {
 	threshold         float64
 	warmUpPeriodInSec uint32
 	coldFactor        uint32
+	curve             WarmUpCurve
+	coldStartCount    float64
 	warningToken      uint64
 	maxToken          uint64
 	slope             float64
 	storedTokens      int64
 	lastFilledTime    uint64
+	// restartIdleMs is the idle duration to restart the warm-up, 0 means disabled.
+	restartIdleMs  uint64
+	lastActiveTime uint64
 }
 
 func (c *WarmUpTrafficShapingCalculator) BoundOwner() *TrafficShapingController {
@@ -42,18 +47,24 @@ func NewWarmUpTrafficShapingCalculator(owner *TrafficShapingController, rule *Ru
 		owner:             owner,
 		warmUpPeriodInSec: rule.WarmUpPeriodSec,
 		coldFactor:        rule.WarmUpColdFactor,
+		curve:             rule.WarmUpCurve,
+		coldStartCount:    rule.ColdStartCount,
 		warningToken:      warningToken,
 		maxToken:          maxToken,
 		slope:             slope,
 		threshold:         rule.Threshold,
 		storedTokens:      0,
 		lastFilledTime:    0,
+		restartIdleMs:     uint64(rule.WarmUpRestartIdleFactor) * uint64(rule.WarmUpPeriodSec) * 1000,
 	}
 
 	return warmUpTrafficShapingCalculator
 }
 
 func (c *WarmUpTrafficShapingCalculator) CalculateAllowedTokens(_ uint32, _ int32) float64 {
+	if c.restartIdleMs > 0 {
+		c.checkRestart(util.CurrentTimeMillis())
+	}
 	metricReadonlyStat := c.BoundOwner().boundStat.readOnlyMetric
 	previousQps := metricReadonlyStat.GetPreviousQPS(base.MetricEventPass)
 	c.syncToken(previousQps)
@@ -64,13 +75,64 @@ func (c *WarmUpTrafficShapingCalculator) CalculateAllowedTokens(_ uint32, _ int3
 	}
 	if restToken >= int64(c.warningToken) {
 		aboveToken := restToken - int64(c.warningToken)
-		warningQps := math.Nextafter(1.0/(float64(aboveToken)*c.slope+1.0/c.threshold), math.MaxFloat64)
-		return warningQps
+		return c.applyColdStartFloor(c.calculateWarningQps(aboveToken))
 	} else {
 		return c.threshold
 	}
 }
 
+// calculateWarningQps calculates the allowed QPS according to the warm-up curve,
+// while aboveToken is the amount of stored tokens above the warning line.
+func (c *WarmUpTrafficShapingCalculator) calculateWarningQps(aboveToken int64) float64 {
+	switch c.curve {
+	case LinearCurve, ExponentialCurve:
+		if c.threshold <= 0 {
+			return 0
+		}
+		coldQps := c.threshold / float64(c.coldFactor)
+		// progress is 0 when the system is coldest and 1 when warmed up.
+		progress := 1.0
+		if c.maxToken > c.warningToken {
+			progress = 1.0 - float64(aboveToken)/float64(c.maxToken-c.warningToken)
+		}
+		if progress < 0 {
+			progress = 0
+		}
+		if c.curve == LinearCurve {
+			return coldQps + (c.threshold-coldQps)*progress
+		}
+		return coldQps * math.Pow(c.threshold/coldQps, progress)
+	default:
+		return math.Nextafter(1.0/(float64(aboveToken)*c.slope+1.0/c.threshold), math.MaxFloat64)
+	}
+}
+
+func (c *WarmUpTrafficShapingCalculator) applyColdStartFloor(qps float64) float64 {
+	if c.coldStartCount <= 0 || qps >= c.coldStartCount {
+		return qps
+	}
+	if c.coldStartCount > c.threshold {
+		return c.threshold
+	}
+	return c.coldStartCount
+}
+
+// checkRestart re-enters the warm-up curve from the coldest state (i.e. fills up the stored tokens)
+// if the resource has been idle for longer than restartIdleMs.
+func (c *WarmUpTrafficShapingCalculator) checkRestart(now uint64) {
+	last := atomic.SwapUint64(&c.lastActiveTime, now)
+	if last == 0 || now < last || now-last < c.restartIdleMs {
+		return
+	}
+	atomic.StoreInt64(&c.storedTokens, int64(c.maxToken))
+	atomic.StoreUint64(&c.lastFilledTime, now-now%1000)
+	var res string
+	if c.owner != nil {
+		res = c.owner.rule.Resource
+	}
+	logging.Info("[WarmUpTrafficShapingCalculator] Restart warm-up after idle", "resource", res, "idleMs", now-last)
+}
+
 func (c *WarmUpTrafficShapingCalculator) syncToken(passQps float64) {
 	currentTime := util.CurrentTimeMillis()
 	currentTime = currentTime - currentTime%1000
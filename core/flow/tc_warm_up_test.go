@@ -0,0 +1,78 @@
+package flow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarmUpTrafficShapingCalculator_Curves(t *testing.T) {
+	newCalculator := func(curve WarmUpCurve, coldStartCount float64) *WarmUpTrafficShapingCalculator {
+		return NewWarmUpTrafficShapingCalculator(nil, &Rule{
+			Resource:               "abc",
+			TokenCalculateStrategy: WarmUp,
+			Threshold:              100,
+			WarmUpPeriodSec:        10,
+			WarmUpColdFactor:       4,
+			WarmUpCurve:            curve,
+			ColdStartCount:         coldStartCount,
+		}).(*WarmUpTrafficShapingCalculator)
+	}
+
+	t.Run("TokenBucket", func(t *testing.T) {
+		c := newCalculator(TokenBucketCurve, 0)
+		aboveMax := int64(c.maxToken - c.warningToken)
+		assert.InDelta(t, 100, c.calculateWarningQps(0), 0.01)
+		assert.InDelta(t, 25, c.calculateWarningQps(aboveMax), 0.5)
+	})
+
+	t.Run("Linear", func(t *testing.T) {
+		c := newCalculator(LinearCurve, 0)
+		aboveMax := int64(c.maxToken - c.warningToken)
+		assert.InDelta(t, 100, c.calculateWarningQps(0), 0.01)
+		assert.InDelta(t, 25, c.calculateWarningQps(aboveMax), 0.01)
+		assert.InDelta(t, 62.5, c.calculateWarningQps(aboveMax/2), 0.5)
+	})
+
+	t.Run("Exponential", func(t *testing.T) {
+		c := newCalculator(ExponentialCurve, 0)
+		aboveMax := int64(c.maxToken - c.warningToken)
+		assert.InDelta(t, 100, c.calculateWarningQps(0), 0.01)
+		assert.InDelta(t, 25, c.calculateWarningQps(aboveMax), 0.01)
+		assert.InDelta(t, 50, c.calculateWarningQps(aboveMax/2), 0.5)
+	})
+
+	t.Run("ColdStartCount", func(t *testing.T) {
+		c := newCalculator(LinearCurve, 40)
+		assert.Equal(t, float64(40), c.applyColdStartFloor(25))
+		assert.Equal(t, float64(60), c.applyColdStartFloor(60))
+
+		c = newCalculator(LinearCurve, 200)
+		assert.Equal(t, float64(100), c.applyColdStartFloor(25))
+	})
+}
+
+func TestWarmUpTrafficShapingCalculator_Restart(t *testing.T) {
+	c := NewWarmUpTrafficShapingCalculator(nil, &Rule{
+		Resource:                "abc",
+		TokenCalculateStrategy:  WarmUp,
+		Threshold:               100,
+		WarmUpPeriodSec:         10,
+		WarmUpColdFactor:        4,
+		WarmUpRestartIdleFactor: 3,
+	}).(*WarmUpTrafficShapingCalculator)
+	assert.Equal(t, uint64(30000), c.restartIdleMs)
+
+	now := uint64(1600000000000)
+	c.checkRestart(now)
+	assert.Equal(t, int64(0), c.storedTokens)
+
+	// warmed up and active
+	c.checkRestart(now + 20000)
+	assert.Equal(t, int64(0), c.storedTokens)
+
+	// idle for longer than 3 × warm-up period
+	c.checkRestart(now + 60000)
+	assert.Equal(t, int64(c.maxToken), c.storedTokens)
+	assert.Equal(t, now+60000, c.lastFilledTime)
+}
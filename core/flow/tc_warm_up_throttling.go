@@ -0,0 +1,217 @@
+package flow
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/config"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+// WarmUpThrottlingChecker is the dedicated checker of WarmUp+Throttling, which paces the requests according to
+// the warm-up rate changing with every passed request, rather than composing the warm-up threshold
+// (which is only updated by the pass QPS of the previous second) with the constant-interval throttling.
+//
+// It keeps the stored tokens like WarmUpTrafficShapingCalculator: the tokens are filled up (i.e. the coldest state)
+// while idle, and each passed request takes one token. The interval charged to the request is the reciprocal of
+// the warm-up rate at the position of the token on the warm-up curve, so the rate ramps up smoothly from
+// threshold/WarmUpColdFactor to the threshold in WarmUpPeriodSec under the saturated traffic, without bursts.
+//
+// The queueing (max queueing time, max queueing requests and the diagnostics) works the same as ThrottlingChecker.
+type WarmUpThrottlingChecker struct {
+	*ThrottlingChecker
+
+	warmUpPeriodInSec uint32
+	coldFactor        uint32
+	curve             WarmUpCurve
+	coldStartCount    float64
+	// restartIdleNs is the idle duration to restart the warm-up, 0 means disabled.
+	restartIdleNs uint64
+
+	mux sync.Mutex
+	// threshold is the threshold of the current warm-up state, the state is rescaled once the threshold changes.
+	threshold    float64
+	warningToken float64
+	maxToken     float64
+	storedTokens float64
+	// nextFreeTime is the time (in ns) when the next request could pass, which has been charged with the intervals
+	// of the passed requests.
+	nextFreeTime uint64
+}
+
+func NewWarmUpThrottlingChecker(owner *TrafficShapingController, rule *Rule) *WarmUpThrottlingChecker {
+	coldFactor := rule.WarmUpColdFactor
+	if coldFactor <= 1 {
+		coldFactor = config.DefaultWarmUpColdFactor
+	}
+	return &WarmUpThrottlingChecker{
+		ThrottlingChecker: NewThrottlingCheckerWithMaxQueueing(owner, rule.MaxQueueingTimeMs, rule.MaxQueueingRequests),
+		warmUpPeriodInSec: rule.WarmUpPeriodSec,
+		coldFactor:        coldFactor,
+		curve:             rule.WarmUpCurve,
+		coldStartCount:    rule.ColdStartCount,
+		restartIdleNs:     uint64(rule.WarmUpRestartIdleFactor) * uint64(rule.WarmUpPeriodSec) * uint64(nanoUnitOffset),
+	}
+}
+
+func (c *WarmUpThrottlingChecker) DoCheck(_ base.StatNode, acquireCount uint32, threshold float64) *base.TokenResult {
+	// Pass when acquire count is less or equal than 0.
+	if acquireCount <= 0 {
+		return nil
+	}
+	if threshold <= 0 {
+		return base.NewTokenResultBlocked(base.BlockTypeFlow)
+	}
+
+	c.mux.Lock()
+	now := util.CurrentTimeNano()
+	c.sync(now, threshold)
+	waitNs := uint64(0)
+	if c.nextFreeTime > now {
+		waitNs = c.nextFreeTime - now
+	}
+	if waitNs > c.maxQueueingTimeNs {
+		c.mux.Unlock()
+		return c.blockedWithWaitHint(waitNs - c.maxQueueingTimeNs)
+	}
+	waitMs := waitNs / util.UnixTimeUnitOffset
+	queueing := waitMs > 0
+	if queueing {
+		if q := atomic.AddInt64(&c.queueingCount, 1); c.maxQueueingRequests > 0 && q > c.maxQueueingRequests {
+			atomic.AddInt64(&c.queueingCount, -1)
+			c.mux.Unlock()
+			// the queue is full, the request could be queued after the current queue drains
+			return c.blockedWithWaitHint(waitNs)
+		}
+	}
+	c.nextFreeTime = now + waitNs + c.take(float64(acquireCount))
+	atomic.StoreUint64(&c.lastPassedTime, now+waitNs)
+	c.mux.Unlock()
+
+	c.recordQueueingDelay(waitNs)
+	if waitNs == 0 {
+		return nil
+	}
+	return base.NewTokenResultShouldWait(waitMs)
+}
+
+// CurrentRate returns the warm-up rate (per second) of the next request, which is the effective threshold right now.
+func (c *WarmUpThrottlingChecker) CurrentRate(threshold float64) float64 {
+	if threshold <= 0 {
+		return 0
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.sync(util.CurrentTimeNano(), threshold)
+	return c.rateAt(c.storedTokens)
+}
+
+// sync rescales the warm-up state to the threshold, and fills the tokens for the idle time since nextFreeTime.
+// The warm-up restarts from the coldest state if the idle time reaches restartIdleNs.
+func (c *WarmUpThrottlingChecker) sync(now uint64, threshold float64) {
+	if threshold != c.threshold {
+		oldMaxToken := c.maxToken
+		c.threshold = threshold
+		c.warningToken = float64(c.warmUpPeriodInSec) * threshold / float64(c.coldFactor-1)
+		c.maxToken = c.warningToken + 2*float64(c.warmUpPeriodInSec)*threshold/float64(1+c.coldFactor)
+		if oldMaxToken > 0 {
+			c.storedTokens = c.storedTokens * c.maxToken / oldMaxToken
+		} else {
+			// start from the coldest state
+			c.storedTokens = c.maxToken
+		}
+	}
+	if now > c.nextFreeTime {
+		if idle := now - c.nextFreeTime; c.restartIdleNs > 0 && c.nextFreeTime > 0 && idle >= c.restartIdleNs {
+			c.storedTokens = c.maxToken
+			var res string
+			if c.owner != nil {
+				res = c.owner.rule.Resource
+			}
+			logging.Info("[WarmUpThrottlingChecker] Restart warm-up after idle", "resource", res, "idleMs", idle/util.UnixTimeUnitOffset)
+		} else if c.nextFreeTime > 0 {
+			c.storedTokens = math.Min(c.maxToken, c.storedTokens+float64(idle)*threshold/float64(nanoUnitOffset))
+		}
+		c.nextFreeTime = now
+	}
+}
+
+// take takes the tokens for the passed request, and returns the interval (in ns) charged to the request.
+func (c *WarmUpThrottlingChecker) take(count float64) uint64 {
+	intervalNs := float64(0)
+	if above := c.storedTokens - c.warningToken; above > 0 {
+		takeAbove := math.Min(above, count)
+		// the average interval of the tokens taken, which is exact for the interval linear to the tokens
+		intervalNs += takeAbove * float64(nanoUnitOffset) / c.rateAt(c.storedTokens-takeAbove/2)
+		count -= takeAbove
+		c.storedTokens -= takeAbove
+	}
+	if count > 0 {
+		intervalNs += count * float64(nanoUnitOffset) / c.threshold
+		c.storedTokens = math.Max(0, c.storedTokens-count)
+	}
+	return uint64(math.Ceil(intervalNs))
+}
+
+// rateAt returns the warm-up rate (per second) when the amount of stored tokens is storedTokens.
+func (c *WarmUpThrottlingChecker) rateAt(storedTokens float64) float64 {
+	if storedTokens <= c.warningToken || c.maxToken <= c.warningToken {
+		return c.threshold
+	}
+	// progress is 0 when the system is coldest and 1 when warmed up.
+	progress := 1 - math.Min(1, (storedTokens-c.warningToken)/(c.maxToken-c.warningToken))
+	coldRate := c.threshold / float64(c.coldFactor)
+	var rate float64
+	switch c.curve {
+	case LinearCurve:
+		rate = coldRate + (c.threshold-coldRate)*progress
+	case ExponentialCurve:
+		rate = coldRate * math.Pow(c.threshold/coldRate, progress)
+	default:
+		// the interval is linear to the stored tokens
+		rate = 1 / (1/coldRate + (1/c.threshold-1/coldRate)*progress)
+	}
+	if c.coldStartCount > 0 && rate < c.coldStartCount {
+		rate = math.Min(c.coldStartCount, c.threshold)
+	}
+	return rate
+}
+
+func (c *WarmUpThrottlingChecker) reserve(acquireCount uint32, threshold float64, maxWaitNs uint64) (uint64, bool) {
+	if threshold <= 0 {
+		return 0, false
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	now := util.CurrentTimeNano()
+	c.sync(now, threshold)
+	waitNs := c.nextFreeTime - now
+	// The reserved requests occupy the queue in advance, regardless of the max queueing time.
+	if waitNs > maxWaitNs {
+		return 0, false
+	}
+	c.nextFreeTime += c.take(float64(acquireCount))
+	atomic.StoreUint64(&c.lastPassedTime, now+waitNs)
+	return waitNs, true
+}
+
+// cancelReservation returns the tokens taken by the reservation, while only the interval of the threshold is
+// released, as the interval actually charged is unknown. It errs on the side of the colder state.
+func (c *WarmUpThrottlingChecker) cancelReservation(acquireCount uint32, threshold float64) {
+	if threshold <= 0 {
+		return
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.storedTokens = math.Min(c.maxToken, c.storedTokens+float64(acquireCount))
+	interval := uint64(math.Ceil(float64(acquireCount) / threshold * float64(nanoUnitOffset)))
+	if c.nextFreeTime > interval {
+		c.nextFreeTime -= interval
+	}
+}
@@ -0,0 +1,170 @@
+package flow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func newWarmUpThrottlingChecker(curve WarmUpCurve, maxQueueingTimeMs uint32) *WarmUpThrottlingChecker {
+	return NewWarmUpThrottlingChecker(nil, &Rule{
+		Resource:               "abc",
+		TokenCalculateStrategy: WarmUp,
+		ControlBehavior:        Throttling,
+		Threshold:              100,
+		WarmUpPeriodSec:        1,
+		WarmUpColdFactor:       3,
+		WarmUpCurve:            curve,
+		MaxQueueingTimeMs:      maxQueueingTimeMs,
+	})
+}
+
+func TestWarmUpThrottlingChecker_Pacing(t *testing.T) {
+	for _, curve := range []WarmUpCurve{TokenBucketCurve, LinearCurve, ExponentialCurve} {
+		c := newWarmUpThrottlingChecker(curve, 1000)
+		c.sync(1, 100)
+		assert.InDelta(t, 100.0/3, c.rateAt(c.storedTokens), 0.01)
+
+		coldInterval := float64(time.Second) / (100.0 / 3)
+		stableInterval := float64(10 * time.Millisecond)
+		last := uint64(coldInterval) + 1
+		total := uint64(0)
+		for c.storedTokens > c.warningToken {
+			interval := c.take(1)
+			// the interval shrinks smoothly with every request, no burst
+			assert.True(t, interval <= last, curve)
+			assert.True(t, float64(interval) >= stableInterval, curve)
+			last = interval
+			total += interval
+		}
+		assert.InDelta(t, stableInterval, float64(c.take(1)), 1, curve)
+		if curve == TokenBucketCurve {
+			// the tokens above the warning line are taken in the warm-up period under the saturated traffic
+			assert.InEpsilon(t, float64(time.Second), float64(total), 0.05)
+		}
+	}
+}
+
+func TestWarmUpThrottlingChecker_CoolDown(t *testing.T) {
+	c := newWarmUpThrottlingChecker(TokenBucketCurve, 1000)
+	c.sync(1, 100)
+	for c.storedTokens > c.warningToken {
+		c.nextFreeTime += c.take(1)
+	}
+	warmedUp := c.storedTokens
+
+	// fills the tokens while idle
+	c.sync(c.nextFreeTime+uint64(100*time.Millisecond), 100)
+	assert.InDelta(t, warmedUp+10, c.storedTokens, 0.01)
+	c.sync(c.nextFreeTime+uint64(time.Hour), 100)
+	assert.Equal(t, c.maxToken, c.storedTokens)
+
+	// rescales the state once the threshold changes
+	c.sync(c.nextFreeTime, 200)
+	assert.Equal(t, c.maxToken, c.storedTokens)
+	assert.InDelta(t, 200.0/3, c.rateAt(c.storedTokens), 0.01)
+}
+
+func TestWarmUpThrottlingChecker_RestartAfterIdle(t *testing.T) {
+	newChecker := func(restartIdleFactor uint32) *WarmUpThrottlingChecker {
+		return NewWarmUpThrottlingChecker(nil, &Rule{
+			Resource:                "abc",
+			TokenCalculateStrategy:  WarmUp,
+			ControlBehavior:         Throttling,
+			Threshold:               100,
+			WarmUpPeriodSec:         1,
+			WarmUpColdFactor:        2,
+			WarmUpRestartIdleFactor: restartIdleFactor,
+			MaxQueueingTimeMs:       1000,
+		})
+	}
+	drain := func(c *WarmUpThrottlingChecker) {
+		c.sync(1, 100)
+		for c.storedTokens > 0 {
+			c.nextFreeTime += c.take(1)
+		}
+	}
+
+	// the tokens filled in the idle time are below the warning line, so it stays warmed up
+	c := newChecker(0)
+	drain(c)
+	c.sync(c.nextFreeTime+uint64(time.Second), 100)
+	assert.InDelta(t, 100, c.storedTokens, 0.01)
+	assert.Equal(t, 100.0, c.rateAt(c.storedTokens))
+
+	// restarts from the coldest state after idle for WarmUpRestartIdleFactor × WarmUpPeriodSec
+	c = newChecker(1)
+	drain(c)
+	c.sync(c.nextFreeTime+uint64(999*time.Millisecond), 100)
+	assert.InDelta(t, 99.9, c.storedTokens, 0.01)
+	drain(c)
+	c.sync(c.nextFreeTime+uint64(time.Second), 100)
+	assert.Equal(t, c.maxToken, c.storedTokens)
+	assert.InDelta(t, 50, c.rateAt(c.storedTokens), 0.01)
+}
+
+func TestWarmUpThrottlingChecker_DoCheck(t *testing.T) {
+	c := newWarmUpThrottlingChecker(TokenBucketCurve, 50)
+
+	assert.Nil(t, c.DoCheck(nil, 1, 100))
+	// paced by the cold rate
+	r := c.DoCheck(nil, 1, 100)
+	assert.Equal(t, base.ResultStatusShouldWait, r.Status())
+	assert.InDelta(t, 30, r.WaitMs(), 2)
+	assert.Equal(t, int64(1), c.QueueingCount())
+	c.onQueueingFinished()
+
+	// exceeds the max queueing time
+	r = c.DoCheck(nil, 1, 100)
+	assert.True(t, r.IsBlocked())
+	assert.True(t, r.BlockError().WaitHint() > 0)
+	assert.True(t, c.DoCheck(nil, 1, 0).IsBlocked())
+	assert.Nil(t, c.DoCheck(nil, 0, 100))
+
+	d := c.Diagnostics()
+	assert.Equal(t, int64(2), d.PassedCount)
+	assert.Equal(t, 50*time.Millisecond, d.MaxQueueingTime)
+}
+
+func TestWarmUpThrottlingChecker_Reservation(t *testing.T) {
+	c := newWarmUpThrottlingChecker(TokenBucketCurve, 0)
+
+	wait, ok := c.reserve(1, 100, uint64(time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, uint64(0), wait)
+	stored := c.storedTokens
+	wait, ok = c.reserve(1, 100, uint64(time.Second))
+	assert.True(t, ok)
+	assert.True(t, wait > 0)
+	_, ok = c.reserve(1, 100, 0)
+	assert.False(t, ok)
+
+	c.cancelReservation(1, 100)
+	assert.Equal(t, stored, c.storedTokens)
+}
+
+func TestWarmUpThrottlingController(t *testing.T) {
+	defer ClearRules()
+
+	_, err := LoadRules([]*Rule{
+		{
+			Resource:               "abc-warm-up-throttling",
+			TokenCalculateStrategy: WarmUp,
+			ControlBehavior:        Throttling,
+			Threshold:              90,
+			WarmUpPeriodSec:        10,
+			WarmUpColdFactor:       3,
+			MaxQueueingTimeMs:      100,
+		},
+	})
+	assert.NoError(t, err)
+
+	tc := TrafficControllersFor("abc-warm-up-throttling")[0]
+	_, ok := tc.FlowChecker().(*WarmUpThrottlingChecker)
+	assert.True(t, ok)
+	assert.InDelta(t, 30, tc.CurrentThreshold(), 0.01)
+	_, ok = tc.ThrottlingDiagnostics()
+	assert.True(t, ok)
+}
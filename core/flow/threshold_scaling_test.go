@@ -0,0 +1,55 @@
+package flow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScaleThresholds(t *testing.T) {
+	defer ResetThresholdScaling()
+
+	assert.Error(t, ScaleThresholds(-1, time.Second))
+	assert.Error(t, ScaleThresholds(0.5, 0))
+	assert.Error(t, ScaleThresholdsOf("[", 0.5, time.Second))
+	assert.Equal(t, float64(1), thresholdScaleFactorOf("abc"))
+
+	assert.NoError(t, ScaleThresholds(0.5, time.Second))
+	assert.NoError(t, ScaleThresholdsOf("^abc", 0.8, time.Second))
+	assert.InDelta(t, 0.4, thresholdScaleFactorOf("abc-1"), 0.0001)
+	assert.InDelta(t, 0.5, thresholdScaleFactorOf("def"), 0.0001)
+
+	ResetThresholdScaling()
+	assert.Equal(t, float64(1), thresholdScaleFactorOf("abc-1"))
+}
+
+func TestScaleThresholdsExpiry(t *testing.T) {
+	defer ResetThresholdScaling()
+
+	assert.NoError(t, ScaleThresholds(0.5, 50*time.Millisecond))
+	assert.InDelta(t, 0.5, thresholdScaleFactorOf("abc"), 0.0001)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, float64(1), thresholdScaleFactorOf("abc"))
+	assert.Equal(t, int32(0), scalingActive)
+}
+
+func TestTrafficShapingController_CurrentThresholdWithScaling(t *testing.T) {
+	defer ClearRules()
+	defer ResetThresholdScaling()
+
+	_, err := LoadRules([]*Rule{
+		{
+			Resource:               "abc-scaling",
+			TokenCalculateStrategy: Direct,
+			ControlBehavior:        Reject,
+			Threshold:              100,
+		},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, ScaleThresholdsOf("abc-scaling", 0.3, time.Second))
+
+	tc := TrafficControllersFor("abc-scaling")[0]
+	assert.Equal(t, float64(100), tc.StaticThreshold())
+	assert.InDelta(t, 30, tc.CurrentThreshold(), 0.0001)
+}
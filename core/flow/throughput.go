@@ -0,0 +1,44 @@
+package flow
+
+import (
+	"github.com/alibaba/sentinel-golang/core/base"
+)
+
+// PayloadBytesAttachmentKey is the key of the entry attachment that carries the payload size (in bytes)
+// of the request, which is the token count of the Throughput rules. The value could be int, int64, uint32 or uint64.
+// The adapters report the payload size automatically if available (e.g. the Content-Length of HTTP requests).
+const PayloadBytesAttachmentKey = "sentinel.flow.payloadBytes"
+
+// payloadBytesOf returns the payload size of the entry reported by the attachment, 0 if absent.
+func payloadBytesOf(ctx *base.EntryContext) uint32 {
+	if ctx.Input == nil || ctx.Input.Attachments == nil {
+		return 0
+	}
+	var size uint64
+	switch v := ctx.Input.Attachments[PayloadBytesAttachmentKey].(type) {
+	case int:
+		if v > 0 {
+			size = uint64(v)
+		}
+	case int64:
+		if v > 0 {
+			size = uint64(v)
+		}
+	case uint32:
+		size = uint64(v)
+	case uint64:
+		size = v
+	}
+	if size > uint64(^uint32(0)) {
+		return ^uint32(0)
+	}
+	return uint32(size)
+}
+
+// tokenCountOf returns the token count of the entry for the rule.
+func tokenCountOf(ctx *base.EntryContext, rule *Rule) uint32 {
+	if rule.MetricType == Throughput {
+		return payloadBytesOf(ctx)
+	}
+	return acquireCountOf(ctx)
+}
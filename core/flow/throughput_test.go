@@ -0,0 +1,67 @@
+package flow
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThroughputRule(t *testing.T) {
+	defer ClearRules()
+
+	_, err := LoadRules([]*Rule{
+		{
+			Resource:               "abc-throughput",
+			TokenCalculateStrategy: Direct,
+			ControlBehavior:        Reject,
+			Threshold:              1000,
+			MetricType:             Throughput,
+		},
+	})
+	assert.Nil(t, err)
+	tc := getTrafficControllerListFor("abc-throughput")[0]
+	assert.False(t, tc.boundStat.reuseResourceStat)
+
+	slot, statSlot := &Slot{}, &StandaloneStatSlot{}
+	newCtx := func(size interface{}) *base.EntryContext {
+		return &base.EntryContext{
+			Resource: base.NewResourceWrapper("abc-throughput", base.ResTypeCommon, base.Inbound),
+			StatNode: stat.GetOrCreateResourceNode("abc-throughput", base.ResTypeCommon),
+			Input: &base.SentinelInput{
+				AcquireCount: 1,
+				Attachments:  map[interface{}]interface{}{PayloadBytesAttachmentKey: size},
+			},
+			Data: make(map[interface{}]interface{}),
+		}
+	}
+	for _, size := range []interface{}{400, int64(400)} {
+		ctx := newCtx(size)
+		assert.Nil(t, slot.Check(ctx))
+		statSlot.OnEntryPassed(ctx)
+	}
+	assert.Equal(t, int64(800), tc.boundStat.readOnlyMetric.GetSum(base.MetricEventPass))
+	ret := slot.Check(newCtx(uint64(400)))
+	assert.True(t, ret != nil && ret.IsBlocked())
+	assert.Nil(t, slot.Check(newCtx(uint32(200))))
+}
+
+func TestIsValidRule_Throughput(t *testing.T) {
+	rule := &Rule{
+		Resource:               "abc",
+		TokenCalculateStrategy: Direct,
+		ControlBehavior:        Reject,
+		Threshold:              1000,
+		MetricType:             Throughput,
+	}
+	assert.Nil(t, IsValidRule(rule))
+
+	rule.RelationStrategy = AssociatedResource
+	rule.RefResource = "def"
+	assert.NotNil(t, IsValidRule(rule))
+
+	rule.RelationStrategy = CurrentResource
+	rule.MetricType = MetricType(10)
+	assert.NotNil(t, IsValidRule(rule))
+}
@@ -24,11 +24,12 @@ type standaloneStatistic struct {
 	reuseResourceStat bool
 	// readOnlyMetric is the readonly metric statistic.
 	// if reuseResourceStat is true, it would be the reused SlidingWindowMetric
-	// if reuseResourceStat is false, it would be the BucketLeapArray
+	// if reuseResourceStat is false, it would be the BucketLeapArray,
+	// or the TieredWindowMetric for an interval longer than the global statistic
 	readOnlyMetric base.ReadStat
 	// writeOnlyMetric is the write only metric statistic.
 	// if reuseResourceStat is true, it would be nil
-	// if reuseResourceStat is false, it would be the BucketLeapArray
+	// if reuseResourceStat is false, it would be the BucketLeapArray or the TieredWindowMetric
 	writeOnlyMetric base.WriteStat
 }
 
@@ -39,6 +40,8 @@ type TrafficShapingController struct {
 	rule *Rule
 	// boundStat is the statistic of current TrafficShapingController
 	boundStat standaloneStatistic
+	// hitCounter records how many times the bound rule was evaluated and triggered
+	hitCounter ruleHitCounter
 }
 
 func NewTrafficShapingController(rule *Rule, boundStat *standaloneStatistic) (*TrafficShapingController, error) {
@@ -57,7 +60,44 @@ func (t *TrafficShapingController) FlowCalculator() TrafficShapingCalculator {
 	return t.flowCalculator
 }
 
+// StaticThreshold returns the threshold configured in the bound rule.
+func (t *TrafficShapingController) StaticThreshold() float64 {
+	return t.rule.Threshold
+}
+
+// CurrentThreshold returns the effective threshold right now, which is calculated by the TrafficShapingCalculator
+// and scaled by the active threshold scalings.
+// e.g. for WarmUp, it's the dynamic threshold adjusted by the warm-up state.
+func (t *TrafficShapingController) CurrentThreshold() float64 {
+	threshold := t.flowCalculator.CalculateAllowedTokens(1, 0) * thresholdScaleFactorOf(t.rule.Resource)
+	if c, ok := t.flowChecker.(*WarmUpThrottlingChecker); ok {
+		return c.CurrentRate(threshold)
+	}
+	return threshold
+}
+
+// CurrentPassCount returns the amount of passed requests in the current statistic interval of the controller.
+func (t *TrafficShapingController) CurrentPassCount() int64 {
+	if t.boundStat.readOnlyMetric == nil {
+		return 0
+	}
+	return t.boundStat.readOnlyMetric.GetSum(base.MetricEventPass)
+}
+
+// ThrottlingDiagnostics returns the queueing diagnostics of the Throttling controller (including WarmUp+Throttling),
+// false if the controller is not Throttling.
+func (t *TrafficShapingController) ThrottlingDiagnostics() (ThrottlingDiagnostics, bool) {
+	switch c := t.flowChecker.(type) {
+	case *ThrottlingChecker:
+		return c.Diagnostics(), true
+	case *WarmUpThrottlingChecker:
+		return c.Diagnostics(), true
+	default:
+		return ThrottlingDiagnostics{}, false
+	}
+}
+
 func (t *TrafficShapingController) PerformChecking(resStat base.StatNode, acquireCount uint32, flag int32) *base.TokenResult {
-	allowedTokens := t.flowCalculator.CalculateAllowedTokens(acquireCount, flag)
+	allowedTokens := t.flowCalculator.CalculateAllowedTokens(acquireCount, flag) * thresholdScaleFactorOf(t.rule.Resource)
 	return t.flowChecker.DoCheck(resStat, acquireCount, allowedTokens)
 }
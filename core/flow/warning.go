@@ -0,0 +1,84 @@
+package flow
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/alibaba/sentinel-golang/core/config"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+// WarningEvent is emitted when the passed requests of the flow rule cross the WarningCount.
+type WarningEvent struct {
+	Rule *Rule
+	// PassCount is the amount of passed requests in current statistic interval, including the triggering request.
+	PassCount float64
+	// Timestamp is the time (in ms) when the event was emitted.
+	Timestamp uint64
+}
+
+// WarningListener listens on the warning events of the flow rules with WarningCount.
+// The listeners are called synchronously in the request path, so they should return quickly.
+type WarningListener interface {
+	OnWarning(e WarningEvent)
+}
+
+var (
+	warningListeners    = make([]WarningListener, 0)
+	warningListenersMux = new(sync.RWMutex)
+)
+
+// RegisterWarningListeners registers the listeners of the warning events. The events of a rule are emitted
+// at most once per statistic interval of the rule, while every request beyond the WarningCount is counted
+// by RuleStat.NearLimitCount.
+func RegisterWarningListeners(ls ...WarningListener) {
+	warningListenersMux.Lock()
+	defer warningListenersMux.Unlock()
+
+	warningListeners = append(warningListeners, ls...)
+}
+
+// ClearWarningListeners clears all the listeners of the warning events.
+func ClearWarningListeners() {
+	warningListenersMux.Lock()
+	defer warningListenersMux.Unlock()
+
+	warningListeners = make([]WarningListener, 0)
+}
+
+// checkWarning records the near-limit request and emits the warning event if the passed request
+// makes the pass count cross the WarningCount of the rule.
+func checkWarning(tc *TrafficShapingController, acquireCount uint32) {
+	rule := tc.rule
+	if rule.WarningCount <= 0 || tc.boundStat.readOnlyMetric == nil {
+		return
+	}
+	passCount := float64(tc.CurrentPassCount()) + float64(acquireCount)
+	if passCount <= rule.WarningCount*thresholdScaleFactorOf(rule.Resource) {
+		return
+	}
+	atomic.AddUint64(&tc.hitCounter.nearLimitCount, 1)
+
+	now := util.CurrentTimeMillis()
+	interval := uint64(rule.StatIntervalInMs)
+	if interval == 0 {
+		interval = uint64(config.MetricStatisticIntervalMs())
+	}
+	last := atomic.LoadUint64(&tc.hitCounter.lastWarningTime)
+	if now < last+interval || !atomic.CompareAndSwapUint64(&tc.hitCounter.lastWarningTime, last, now) {
+		return
+	}
+	logging.Warn("[FlowWarning] The passed requests are beyond the WarningCount of the flow rule", "rule", rule, "passCount", passCount)
+
+	e := WarningEvent{
+		Rule:      rule,
+		PassCount: passCount,
+		Timestamp: now,
+	}
+	warningListenersMux.RLock()
+	defer warningListenersMux.RUnlock()
+	for _, l := range warningListeners {
+		l.OnWarning(e)
+	}
+}
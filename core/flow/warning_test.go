@@ -0,0 +1,83 @@
+package flow
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+type warningRecorder struct {
+	mux    sync.Mutex
+	events []WarningEvent
+}
+
+func (r *warningRecorder) OnWarning(e WarningEvent) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.events = append(r.events, e)
+}
+
+func TestWarningCount(t *testing.T) {
+	recorder := &warningRecorder{}
+	RegisterWarningListeners(recorder)
+	defer ClearWarningListeners()
+	defer ClearRules()
+
+	_, err := LoadRules([]*Rule{
+		{
+			Resource:               "warning-abc",
+			TokenCalculateStrategy: Direct,
+			ControlBehavior:        Reject,
+			// use the standalone statistic written by StandaloneStatSlot
+			StatIntervalInMs: 20000,
+			Threshold:        10,
+			WarningCount:     5,
+		},
+	})
+	assert.Nil(t, err)
+
+	slot := &Slot{}
+	statSlot := &StandaloneStatSlot{}
+	ctx := &base.EntryContext{
+		Resource: base.NewResourceWrapper("warning-abc", base.ResTypeCommon, base.Inbound),
+		StatNode: stat.GetOrCreateResourceNode("warning-abc", base.ResTypeCommon),
+		Input: &base.SentinelInput{
+			AcquireCount: 1,
+		},
+	}
+	passed := 0
+	for i := 0; i < 12; i++ {
+		if r := slot.Check(ctx); r == nil || r.IsPass() {
+			statSlot.OnEntryPassed(ctx)
+			passed++
+		}
+	}
+	// the requests beyond WarningCount are not blocked
+	assert.Equal(t, 10, passed)
+
+	stats := RuleStats()
+	assert.Equal(t, 1, len(stats))
+	// the 6th to 10th passed requests are near the limit
+	assert.Equal(t, uint64(5), stats[0].NearLimitCount)
+	// the events are emitted at most once per statistic interval
+	assert.Equal(t, 1, len(recorder.events))
+	assert.Equal(t, float64(6), recorder.events[0].PassCount)
+	assert.Equal(t, "warning-abc", recorder.events[0].Rule.Resource)
+}
+
+func TestWarningCount_Validation(t *testing.T) {
+	rule := &Rule{Resource: "abc", Threshold: 10, WarningCount: 10}
+	errs, ok := IsValidRule(rule).(ValidationErrors)
+	assert.True(t, ok)
+	assert.True(t, errs.HasField("WarningCount"))
+
+	rule = &Rule{Resource: "abc", Threshold: 10, WarningCount: 5, ControlBehavior: Throttling, MaxQueueingTimeMs: 10}
+	errs, ok = IsValidRule(rule).(ValidationErrors)
+	assert.True(t, ok)
+	assert.Equal(t, ConstraintDependsOn, errs[0].Constraint)
+
+	assert.Nil(t, IsValidRule(&Rule{Resource: "abc", Threshold: 10, WarningCount: 5}))
+}
@@ -0,0 +1,202 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/pkg/errors"
+)
+
+// MatchStrategy indicates how the pattern matches the value.
+type MatchStrategy int32
+
+const (
+	// MatchExact matches the value equal to the pattern.
+	MatchExact MatchStrategy = iota
+	// MatchPrefix matches the value with the pattern as the prefix.
+	MatchPrefix
+	// MatchRegex matches the value by the pattern as the regular expression, which must match the whole value.
+	MatchRegex
+)
+
+func (s MatchStrategy) String() string {
+	switch s {
+	case MatchExact:
+		return "Exact"
+	case MatchPrefix:
+		return "Prefix"
+	case MatchRegex:
+		return "Regex"
+	default:
+		return "Undefined"
+	}
+}
+
+// matcher is the compiled pattern of a MatchStrategy.
+type matcher struct {
+	strategy MatchStrategy
+	pattern  string
+	regex    *regexp.Regexp
+}
+
+func newMatcher(pattern string, strategy MatchStrategy) (*matcher, error) {
+	m := &matcher{strategy: strategy, pattern: pattern}
+	switch strategy {
+	case MatchExact, MatchPrefix:
+	case MatchRegex:
+		regex, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid regex pattern: %s", pattern)
+		}
+		m.regex = regex
+	default:
+		return nil, errors.Errorf("invalid match strategy: %d", strategy)
+	}
+	return m, nil
+}
+
+func (m *matcher) matches(value string) bool {
+	switch m.strategy {
+	case MatchExact:
+		return value == m.pattern
+	case MatchPrefix:
+		return strings.HasPrefix(value, m.pattern)
+	case MatchRegex:
+		return m.regex.MatchString(value)
+	default:
+		return false
+	}
+}
+
+// ApiPredicate matches the paths of the requests.
+type ApiPredicate struct {
+	Pattern       string        `json:"pattern"`
+	MatchStrategy MatchStrategy `json:"matchStrategy"`
+}
+
+// ApiDefinition groups the routes into the named API by the path predicates,
+// the requests matching any of the predicates belong to the API.
+type ApiDefinition struct {
+	// Name is the name of the API, which is the resource name of the gateway rules with ResourceModeApiName.
+	Name       string          `json:"name"`
+	Predicates []*ApiPredicate `json:"predicates"`
+}
+
+func (d *ApiDefinition) String() string {
+	b, err := json.Marshal(d)
+	if err != nil {
+		// Return the fallback string
+		return fmt.Sprintf("ApiDefinition{Name=%s, Predicates=%v}", d.Name, d.Predicates)
+	}
+	return string(b)
+}
+
+type compiledApi struct {
+	def      *ApiDefinition
+	matchers []*matcher
+}
+
+func (a *compiledApi) matches(path string) bool {
+	for _, m := range a.matchers {
+		if m.matches(path) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	apis   = make([]*compiledApi, 0)
+	apiMux = &sync.RWMutex{}
+)
+
+// LoadApiDefinitions replaces the API definitions with the given ones. The invalid definitions and
+// the duplicate definitions of the same name are ignored.
+func LoadApiDefinitions(defs []*ApiDefinition) (bool, error) {
+	newApis := make([]*compiledApi, 0, len(defs))
+	names := make(map[string]struct{}, len(defs))
+	for _, d := range defs {
+		api, err := compileApiDefinition(d)
+		if err != nil {
+			logging.Warn("[GatewayApiManager] Ignoring invalid API definition", "definition", d, "reason", err.Error())
+			continue
+		}
+		if _, exist := names[d.Name]; exist {
+			logging.Warn("[GatewayApiManager] Ignoring duplicate API definition", "definition", d)
+			continue
+		}
+		names[d.Name] = struct{}{}
+		newApis = append(newApis, api)
+	}
+
+	apiMux.Lock()
+	apis = newApis
+	apiMux.Unlock()
+
+	if len(newApis) == 0 {
+		logging.Info("[GatewayApiManager] API definitions were cleared")
+	} else {
+		logging.Info("[GatewayApiManager] API definitions were loaded", "definitions", defs)
+	}
+	return true, nil
+}
+
+// ClearApiDefinitions clears all the API definitions.
+func ClearApiDefinitions() error {
+	_, err := LoadApiDefinitions(nil)
+	return err
+}
+
+// GetApiDefinitions returns all the API definitions based on copy.
+func GetApiDefinitions() []ApiDefinition {
+	apiMux.RLock()
+	defer apiMux.RUnlock()
+
+	ret := make([]ApiDefinition, 0, len(apis))
+	for _, a := range apis {
+		ret = append(ret, *a.def)
+	}
+	return ret
+}
+
+// MatchingApis returns the names of the APIs that the path belongs to, in the order of the definitions.
+func MatchingApis(path string) []string {
+	apiMux.RLock()
+	defer apiMux.RUnlock()
+
+	var ret []string
+	for _, a := range apis {
+		if a.matches(path) {
+			ret = append(ret, a.def.Name)
+		}
+	}
+	return ret
+}
+
+func compileApiDefinition(d *ApiDefinition) (*compiledApi, error) {
+	if d == nil {
+		return nil, errors.New("nil ApiDefinition")
+	}
+	if len(d.Name) == 0 {
+		return nil, errors.New("empty API name")
+	}
+	if len(d.Predicates) == 0 {
+		return nil, errors.New("empty predicates")
+	}
+	api := &compiledApi{def: d, matchers: make([]*matcher, 0, len(d.Predicates))}
+	for _, p := range d.Predicates {
+		if p == nil || len(p.Pattern) == 0 {
+			return nil, errors.New("empty predicate pattern")
+		}
+		m, err := newMatcher(p.Pattern, p.MatchStrategy)
+		if err != nil {
+			return nil, err
+		}
+		api.matchers = append(api.matchers, m)
+	}
+	return api, nil
+}
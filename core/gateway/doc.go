@@ -0,0 +1,39 @@
+// Package gateway provides the flow control for the API gateways.
+//
+// The routes of the gateway are grouped into the named APIs by the ApiDefinition with the path predicates
+// (exact, prefix or regex). The gateway rules target either the route ID or the API name (see ResourceMode),
+// and could count the requests by the param of the requests (see ParamItem), e.g. the client IP, the host,
+// a header, a URL query param or a cookie, so that each value of the param is limited separately.
+//
+// The gateway guards each request by the entry of the route, and the entries of the APIs the path belongs to
+// (see MatchingApis), with the *Request carried by the attachment RequestAttachmentKey:
+//
+//	gateway.LoadApiDefinitions([]*gateway.ApiDefinition{
+//		{Name: "user-api", Predicates: []*gateway.ApiPredicate{{Pattern: "/users/", MatchStrategy: gateway.MatchPrefix}}},
+//	})
+//	gateway.LoadRules([]*gateway.Rule{
+//		{Resource: "user-api", ResourceMode: gateway.ResourceModeApiName, Threshold: 10,
+//			ParamItem: &gateway.ParamItem{ParseStrategy: gateway.ParamClientIP}},
+//	})
+//	...
+//	req := gateway.NewHTTPRequest(r, clientIP)
+//	for _, api := range gateway.MatchingApis(req.Path) {
+//		e, b := sentinel.Entry(api, sentinel.WithTrafficType(base.Inbound),
+//			sentinel.WithResourceType(base.ResTypeAPIGateway), sentinel.WithAttachment(gateway.RequestAttachmentKey, req))
+//		if b != nil {
+//			// Blocked, 10 requests per second of each client IP are allowed for user-api.
+//		}
+//		defer e.Exit()
+//	}
+//
+// The ParamItem could have a Pattern (exact, prefix or regex) as the condition of applying the rule, e.g. only
+// the requests with the header "X-Channel: partner" are limited to 50 QPS:
+//
+//	gateway.LoadRules([]*gateway.Rule{
+//		{Resource: "order-route", Threshold: 50, ParamItem: &gateway.ParamItem{
+//			ParseStrategy: gateway.ParamHeader, FieldName: "X-Channel", Pattern: "partner", MatchStrategy: gateway.MatchExact}},
+//	})
+//
+// The gateway rules are enforced by the hotspot param flow controllers, so the blocked requests are blocked
+// with BlockTypeHotSpotParamFlow.
+package gateway
@@ -0,0 +1,168 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/hotspot"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchingApis(t *testing.T) {
+	defer func() { _ = ClearApiDefinitions() }()
+
+	_, err := LoadApiDefinitions([]*ApiDefinition{
+		{Name: "user-api", Predicates: []*ApiPredicate{
+			{Pattern: "/users", MatchStrategy: MatchExact},
+			{Pattern: "/users/", MatchStrategy: MatchPrefix},
+		}},
+		{Name: "order-api", Predicates: []*ApiPredicate{{Pattern: `/orders/\d+`, MatchStrategy: MatchRegex}}},
+		{Name: "all-api", Predicates: []*ApiPredicate{{Pattern: "/", MatchStrategy: MatchPrefix}}},
+		{Name: "bad-api", Predicates: []*ApiPredicate{{Pattern: "(", MatchStrategy: MatchRegex}}},
+		{Name: "user-api", Predicates: []*ApiPredicate{{Pattern: "/dup", MatchStrategy: MatchExact}}},
+		{Name: "empty-api"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(GetApiDefinitions()))
+
+	assert.Equal(t, []string{"user-api", "all-api"}, MatchingApis("/users"))
+	assert.Equal(t, []string{"user-api", "all-api"}, MatchingApis("/users/1"))
+	assert.Equal(t, []string{"order-api", "all-api"}, MatchingApis("/orders/12"))
+	assert.Equal(t, []string{"all-api"}, MatchingApis("/orders/12/items"))
+	assert.Equal(t, []string{"all-api"}, MatchingApis("/dup"))
+	assert.Nil(t, MatchingApis("users"))
+}
+
+func TestIsValidRule(t *testing.T) {
+	assert.Nil(t, IsValidRule(&Rule{Resource: "abc", Threshold: 10}))
+	assert.Nil(t, IsValidRule(&Rule{Resource: "abc", Threshold: 10, ParamItem: &ParamItem{ParseStrategy: ParamClientIP}}))
+	assert.NotNil(t, IsValidRule(nil))
+	assert.NotNil(t, IsValidRule(&Rule{Threshold: 10}))
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc", Threshold: -1}))
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc", ResourceMode: ResourceMode(5)}))
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc", ControlBehavior: hotspot.ControlBehavior(5)}))
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc", ParamItem: &ParamItem{ParseStrategy: ParamHeader}}))
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc", ParamItem: &ParamItem{ParseStrategy: ParseStrategy(9)}}))
+}
+
+func newContext(res string, req *Request) *base.EntryContext {
+	ctx := base.NewEmptyEntryContext()
+	ctx.Resource = base.NewResourceWrapper(res, base.ResTypeAPIGateway, base.Inbound)
+	ctx.Input = &base.SentinelInput{AcquireCount: 1}
+	if req != nil {
+		ctx.Input.Attachments = map[interface{}]interface{}{RequestAttachmentKey: req}
+	}
+	return ctx
+}
+
+func TestSlot_Check(t *testing.T) {
+	defer func() { _ = ClearRules() }()
+
+	_, err := LoadRules([]*Rule{
+		{Resource: "user-api", ResourceMode: ResourceModeApiName, Threshold: 1, IntervalSec: 10,
+			ParamItem: &ParamItem{ParseStrategy: ParamClientIP}},
+		{Resource: "user-api", ResourceMode: ResourceModeApiName, Threshold: 3, IntervalSec: 10},
+		{Resource: "invalid", Threshold: -1},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(GetRulesOfResource("user-api")))
+	assert.Equal(t, 2, len(GetRules()))
+
+	s := &Slot{}
+	assert.Nil(t, s.Check(newContext("user-api", &Request{ClientIP: "10.0.0.1"})))
+	// the param of each client IP is limited separately
+	assert.True(t, s.Check(newContext("user-api", &Request{ClientIP: "10.0.0.1"})).IsBlocked())
+	assert.Nil(t, s.Check(newContext("user-api", &Request{ClientIP: "10.0.0.2"})))
+	// the requests without the client IP are only limited by the rule without ParamItem
+	assert.Nil(t, s.Check(newContext("user-api", nil)))
+	assert.True(t, s.Check(newContext("user-api", nil)).IsBlocked())
+	assert.Nil(t, s.Check(newContext("other-api", nil)))
+
+	// the controllers of the unchanged rules are kept
+	tcs := getTrafficControllersFor("user-api")
+	_, err = LoadRules([]*Rule{
+		{Resource: "user-api", ResourceMode: ResourceModeApiName, Threshold: 1, IntervalSec: 10,
+			ParamItem: &ParamItem{ParseStrategy: ParamClientIP}},
+	})
+	assert.Nil(t, err)
+	assert.True(t, tcs[0] == getTrafficControllersFor("user-api")[0])
+	assert.True(t, s.Check(newContext("user-api", &Request{ClientIP: "10.0.0.1"})).IsBlocked())
+}
+
+func TestNewHTTPRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "http://example.com/users/1?tenant=a", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set("X-Channel", "partner")
+	r.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+
+	req := NewHTTPRequest(r, "")
+	assert.Equal(t, "/users/1", req.Path)
+	assert.Equal(t, "example.com", req.Host)
+	assert.Equal(t, "10.0.0.1", req.ClientIP)
+
+	for _, c := range []struct {
+		item *ParamItem
+		want string
+	}{
+		{&ParamItem{ParseStrategy: ParamClientIP}, "10.0.0.1"},
+		{&ParamItem{ParseStrategy: ParamHost}, "example.com"},
+		{&ParamItem{ParseStrategy: ParamHeader, FieldName: "X-Channel"}, "partner"},
+		{&ParamItem{ParseStrategy: ParamURLParam, FieldName: "tenant"}, "a"},
+		{&ParamItem{ParseStrategy: ParamCookie, FieldName: "session"}, "s1"},
+	} {
+		v, ok := parseParam(c.item, req)
+		assert.True(t, ok)
+		assert.Equal(t, c.want, v)
+	}
+	_, ok := parseParam(&ParamItem{ParseStrategy: ParamCookie, FieldName: "absent"}, req)
+	assert.False(t, ok)
+
+	assert.Equal(t, "192.168.0.1", NewHTTPRequest(r, "192.168.0.1").ClientIP)
+}
+
+func TestSlot_CheckParamPattern(t *testing.T) {
+	defer func() { _ = ClearRules() }()
+
+	_, err := LoadRules([]*Rule{
+		{Resource: "order-route", Threshold: 1, IntervalSec: 10, ParamItem: &ParamItem{
+			ParseStrategy: ParamHeader, FieldName: "X-Channel", Pattern: "partner", MatchStrategy: MatchExact}},
+		{Resource: "order-route", Threshold: 1, IntervalSec: 10, ParamItem: &ParamItem{
+			ParseStrategy: ParamURLParam, FieldName: "tenant", Pattern: `vip-\d+`, MatchStrategy: MatchRegex}},
+		{Resource: "order-route", Threshold: 1, ParamItem: &ParamItem{
+			ParseStrategy: ParamURLParam, FieldName: "tenant", Pattern: "(", MatchStrategy: MatchRegex}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(GetRulesOfResource("order-route")))
+
+	newRequest := func(channel, tenant string) *Request {
+		return &Request{
+			Header: func(name string) string {
+				if name == "X-Channel" {
+					return channel
+				}
+				return ""
+			},
+			Query: func(name string) string {
+				if name == "tenant" {
+					return tenant
+				}
+				return ""
+			},
+		}
+	}
+	s := &Slot{}
+	assert.Nil(t, s.Check(newContext("order-route", newRequest("partner", ""))))
+	assert.True(t, s.Check(newContext("order-route", newRequest("partner", ""))).IsBlocked())
+	// the requests of the other channels are not limited
+	assert.Nil(t, s.Check(newContext("order-route", newRequest("direct", ""))))
+	assert.Nil(t, s.Check(newContext("order-route", newRequest("direct", ""))))
+
+	assert.Nil(t, s.Check(newContext("order-route", newRequest("", "vip-1"))))
+	assert.True(t, s.Check(newContext("order-route", newRequest("", "vip-1"))).IsBlocked())
+	// each matched value is counted separately
+	assert.Nil(t, s.Check(newContext("order-route", newRequest("", "vip-2"))))
+	assert.Nil(t, s.Check(newContext("order-route", newRequest("", "vip-x"))))
+	assert.Nil(t, s.Check(newContext("order-route", newRequest("", "vip-x"))))
+}
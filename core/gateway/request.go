@@ -0,0 +1,61 @@
+package gateway
+
+import (
+	"net"
+	"net/http"
+)
+
+// RequestAttachmentKey is the key of the entry attachment that carries the *Request, from which the params
+// of the gateway rules are parsed.
+const RequestAttachmentKey = "sentinel.gateway.request"
+
+// Request is the view of the request that the gateway rules parse the params from,
+// so that the gateway rules could be applied to the requests of any gateway framework.
+type Request struct {
+	// Path is the path of the request, which is matched by the API definitions.
+	Path string
+	// Host is the host of the request.
+	Host string
+	// ClientIP is the IP of the client, e.g. resolved from the proxy headers by the gateway.
+	ClientIP string
+	// Header returns the value of the header, nil means no header.
+	Header func(name string) string
+	// Query returns the value of the URL query param, nil means no query param.
+	Query func(name string) string
+	// Cookie returns the value of the cookie, nil means no cookie.
+	Cookie func(name string) string
+}
+
+// NewHTTPRequest creates the Request of the *http.Request. The clientIP is taken from the remote address
+// of the request if empty.
+func NewHTTPRequest(r *http.Request, clientIP string) *Request {
+	if len(clientIP) == 0 {
+		clientIP = r.RemoteAddr
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			clientIP = host
+		}
+	}
+	query := r.URL.Query()
+	return &Request{
+		Path:     r.URL.Path,
+		Host:     r.Host,
+		ClientIP: clientIP,
+		Header:   r.Header.Get,
+		Query:    query.Get,
+		Cookie: func(name string) string {
+			c, err := r.Cookie(name)
+			if err != nil {
+				return ""
+			}
+			return c.Value
+		},
+	}
+}
+
+func requestOf(attachments map[interface{}]interface{}) *Request {
+	if attachments == nil {
+		return nil
+	}
+	req, _ := attachments[RequestAttachmentKey].(*Request)
+	return req
+}
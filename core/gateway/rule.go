@@ -0,0 +1,128 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/alibaba/sentinel-golang/core/hotspot"
+)
+
+// ResourceMode indicates what the resource of the gateway rule is.
+type ResourceMode int32
+
+const (
+	// ResourceModeRouteID means the resource is the ID of the route of the gateway.
+	ResourceModeRouteID ResourceMode = iota
+	// ResourceModeApiName means the resource is the name of the API (see ApiDefinition).
+	ResourceModeApiName
+)
+
+func (m ResourceMode) String() string {
+	switch m {
+	case ResourceModeRouteID:
+		return "RouteID"
+	case ResourceModeApiName:
+		return "ApiName"
+	default:
+		return "Undefined"
+	}
+}
+
+// ParseStrategy indicates where the param of the request is parsed from.
+type ParseStrategy int32
+
+const (
+	// ParamClientIP parses the IP of the client.
+	ParamClientIP ParseStrategy = iota
+	// ParamHost parses the host of the request.
+	ParamHost
+	// ParamHeader parses the header of the FieldName.
+	ParamHeader
+	// ParamURLParam parses the URL query param of the FieldName.
+	ParamURLParam
+	// ParamCookie parses the cookie of the FieldName.
+	ParamCookie
+)
+
+func (s ParseStrategy) String() string {
+	switch s {
+	case ParamClientIP:
+		return "ClientIP"
+	case ParamHost:
+		return "Host"
+	case ParamHeader:
+		return "Header"
+	case ParamURLParam:
+		return "URLParam"
+	case ParamCookie:
+		return "Cookie"
+	default:
+		return "Undefined"
+	}
+}
+
+// ParamItem describes the param of the request that the gateway rule counts by,
+// e.g. limiting the requests of each client IP separately.
+type ParamItem struct {
+	ParseStrategy ParseStrategy `json:"parseStrategy"`
+	// FieldName is the name of the header, the URL query param or the cookie.
+	FieldName string `json:"fieldName,omitempty"`
+	// Pattern is the condition of applying the rule (optional), the rule only takes effect on the requests
+	// whose param matches the Pattern by the MatchStrategy, e.g. only the requests with "X-Channel: partner".
+	// Each matched value of the param is still counted separately. Empty means all the values.
+	Pattern       string        `json:"pattern,omitempty"`
+	MatchStrategy MatchStrategy `json:"matchStrategy,omitempty"`
+}
+
+// Rule describes the flow control of the gateway route or API.
+type Rule struct {
+	// ID represents the unique ID of the rule (optional).
+	ID string `json:"id,omitempty"`
+	// Resource is the route ID or the API name, according to the ResourceMode.
+	Resource     string       `json:"resource"`
+	ResourceMode ResourceMode `json:"resourceMode"`
+	// Threshold is the amount of the allowed requests during IntervalSec.
+	// If ParamItem is set, it's the threshold of each value of the param.
+	Threshold float64 `json:"threshold"`
+	// IntervalSec is the statistic interval in seconds, 1 by default.
+	IntervalSec     int64                   `json:"intervalSec,omitempty"`
+	ControlBehavior hotspot.ControlBehavior `json:"controlBehavior"`
+	// BurstCount is the extra requests allowed in burst, only take effect in Reject ControlBehavior.
+	BurstCount int64 `json:"burstCount,omitempty"`
+	// MaxQueueingTimeMs only take effect in Throttling ControlBehavior.
+	MaxQueueingTimeMs int64 `json:"maxQueueingTimeMs,omitempty"`
+	// ParamItem makes the rule count the requests by the param of the requests, nil means counting all the requests.
+	ParamItem *ParamItem `json:"paramItem,omitempty"`
+}
+
+func (r *Rule) String() string {
+	b, err := json.Marshal(r)
+	if err != nil {
+		// Return the fallback string
+		return fmt.Sprintf("Rule{Resource=%s, ResourceMode=%s, Threshold=%.2f, IntervalSec=%d, ControlBehavior=%s, BurstCount=%d, MaxQueueingTimeMs=%d, ParamItem=%+v}",
+			r.Resource, r.ResourceMode, r.Threshold, r.IntervalSec, r.ControlBehavior, r.BurstCount, r.MaxQueueingTimeMs, r.ParamItem)
+	}
+	return string(b)
+}
+
+func (r *Rule) ResourceName() string {
+	return r.Resource
+}
+
+// toHotspotRule converts the gateway rule into the hotspot rule which counts by the parsed param.
+func (r *Rule) toHotspotRule() *hotspot.Rule {
+	intervalSec := r.IntervalSec
+	if intervalSec <= 0 {
+		intervalSec = 1
+	}
+	return &hotspot.Rule{
+		ID:                r.ID,
+		Resource:          r.Resource,
+		MetricType:        hotspot.QPS,
+		ControlBehavior:   r.ControlBehavior,
+		Threshold:         r.Threshold,
+		BurstCount:        r.BurstCount,
+		MaxQueueingTimeMs: r.MaxQueueingTimeMs,
+		DurationInSec:     intervalSec,
+	}
+}
@@ -0,0 +1,162 @@
+package gateway
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/hotspot"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/pkg/errors"
+)
+
+// trafficController is the traffic controller of a gateway rule.
+type trafficController struct {
+	rule *Rule
+	tc   hotspot.TrafficShapingController
+	// paramMatcher is the compiled Pattern of the ParamItem, nil means all the values of the param.
+	paramMatcher *matcher
+}
+
+var (
+	tcMap = make(map[string][]*trafficController)
+	tcMux = &sync.RWMutex{}
+)
+
+// LoadRules replaces all the gateway rules with the given rules. The invalid rules are ignored.
+// The controllers of the rules unchanged are kept, so that their statistics are not reset.
+func LoadRules(rules []*Rule) (bool, error) {
+	defer selfmetric.RecordRuleUpdate("gateway", time.Now())
+
+	tcMux.Lock()
+	defer tcMux.Unlock()
+
+	m := make(map[string][]*trafficController, len(rules))
+	for _, r := range rules {
+		if err := IsValidRule(r); err != nil {
+			logging.Warn("[GatewayRuleManager] Ignoring invalid gateway rule", "rule", r, "reason", err.Error())
+			continue
+		}
+		tc := findController(tcMap[r.Resource], r)
+		if tc == nil {
+			htc, err := hotspot.NewTrafficShapingController(r.toHotspotRule())
+			if err != nil {
+				logging.Warn("[GatewayRuleManager] Ignoring gateway rule failed to generate the controller", "rule", r, "reason", err.Error())
+				continue
+			}
+			tc = &trafficController{rule: r, tc: htc}
+			if r.ParamItem != nil && len(r.ParamItem.Pattern) > 0 {
+				// The pattern has been checked by IsValidRule.
+				tc.paramMatcher, _ = newMatcher(r.ParamItem.Pattern, r.ParamItem.MatchStrategy)
+			}
+		}
+		m[r.Resource] = append(m[r.Resource], tc)
+	}
+	tcMap = m
+
+	resources := make([]string, 0, len(m))
+	for res := range m {
+		resources = append(resources, res)
+	}
+	base.SetRuleResourcesOf("gateway", resources, false)
+
+	if len(m) == 0 {
+		logging.Info("[GatewayRuleManager] Gateway rules were cleared")
+	} else {
+		logging.Info("[GatewayRuleManager] Gateway rules were loaded", "rules", rules)
+	}
+	return true, nil
+}
+
+// ClearRules clears all the gateway rules.
+func ClearRules() error {
+	_, err := LoadRules(nil)
+	return err
+}
+
+// GetRules returns all the gateway rules based on copy.
+func GetRules() []Rule {
+	tcMux.RLock()
+	defer tcMux.RUnlock()
+
+	ret := make([]Rule, 0)
+	for _, tcs := range tcMap {
+		for _, tc := range tcs {
+			ret = append(ret, *tc.rule)
+		}
+	}
+	return ret
+}
+
+// GetRulesOfResource returns the gateway rules of the route or the API based on copy.
+func GetRulesOfResource(res string) []Rule {
+	tcMux.RLock()
+	defer tcMux.RUnlock()
+
+	tcs := tcMap[res]
+	ret := make([]Rule, 0, len(tcs))
+	for _, tc := range tcs {
+		ret = append(ret, *tc.rule)
+	}
+	return ret
+}
+
+func getTrafficControllersFor(res string) []*trafficController {
+	tcMux.RLock()
+	defer tcMux.RUnlock()
+
+	return tcMap[res]
+}
+
+func findController(tcs []*trafficController, r *Rule) *trafficController {
+	for _, tc := range tcs {
+		if reflect.DeepEqual(tc.rule, r) {
+			return tc
+		}
+	}
+	return nil
+}
+
+// IsValidRule checks whether the given gateway rule is valid.
+func IsValidRule(r *Rule) error {
+	if r == nil {
+		return errors.New("nil Rule")
+	}
+	if len(r.Resource) == 0 {
+		return errors.New("empty resource")
+	}
+	if r.ResourceMode != ResourceModeRouteID && r.ResourceMode != ResourceModeApiName {
+		return errors.New("invalid resource mode")
+	}
+	if r.Threshold < 0 {
+		return errors.New("negative threshold")
+	}
+	if r.IntervalSec < 0 {
+		return errors.New("negative IntervalSec")
+	}
+	if r.ControlBehavior != hotspot.Reject && r.ControlBehavior != hotspot.Throttling {
+		return errors.New("invalid control behavior")
+	}
+	if r.BurstCount < 0 {
+		return errors.New("negative BurstCount")
+	}
+	if r.MaxQueueingTimeMs < 0 {
+		return errors.New("negative MaxQueueingTimeMs")
+	}
+	if p := r.ParamItem; p != nil {
+		if p.ParseStrategy < ParamClientIP || p.ParseStrategy > ParamCookie {
+			return errors.New("invalid parse strategy of ParamItem")
+		}
+		if p.ParseStrategy >= ParamHeader && len(p.FieldName) == 0 {
+			return errors.Errorf("empty FieldName of ParamItem with %s parse strategy", p.ParseStrategy)
+		}
+		if len(p.Pattern) > 0 {
+			if _, err := newMatcher(p.Pattern, p.MatchStrategy); err != nil {
+				return errors.Wrap(err, "invalid Pattern of ParamItem")
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package gateway
+
+import (
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+)
+
+// defaultParam is the param of the rules without ParamItem, so that all the requests are counted together.
+const defaultParam = "$D"
+
+// Slot checks the gateway rules of the route or API, with the params parsed from the *Request
+// carried by the attachment RequestAttachmentKey.
+type Slot struct {
+}
+
+// RulesIndexed implements base.IndexedRuleCheckSlot, as all the rules are registered to the rule resource index.
+func (s *Slot) RulesIndexed() bool {
+	return true
+}
+
+func (s *Slot) Check(ctx *base.EntryContext) *base.TokenResult {
+	result := ctx.RuleCheckResult
+	tcs := getTrafficControllersFor(ctx.Resource.Name())
+	if len(tcs) == 0 {
+		return result
+	}
+	req := requestOf(ctx.Input.Attachments)
+	for _, tc := range tcs {
+		param, ok := parseParam(tc.rule.ParamItem, req)
+		if !ok || (tc.paramMatcher != nil && !tc.paramMatcher.matches(param)) {
+			continue
+		}
+		r := tc.tc.PerformChecking(param, int64(ctx.Input.AcquireCount))
+		if r == nil {
+			continue
+		}
+		if r.Status() == base.ResultStatusBlocked {
+			return r
+		}
+		if r.Status() == base.ResultStatusShouldWait {
+			if waitMs := r.WaitMs(); waitMs > 0 {
+				// Handle waiting action.
+				time.Sleep(time.Duration(waitMs) * time.Millisecond)
+			}
+			continue
+		}
+	}
+	return result
+}
+
+// parseParam parses the param of the request by the ParamItem, and returns false if the request doesn't have it.
+func parseParam(item *ParamItem, req *Request) (string, bool) {
+	if item == nil {
+		return defaultParam, true
+	}
+	if req == nil {
+		return "", false
+	}
+	var value string
+	switch item.ParseStrategy {
+	case ParamClientIP:
+		value = req.ClientIP
+	case ParamHost:
+		value = req.Host
+	case ParamHeader:
+		value = valueOf(req.Header, item.FieldName)
+	case ParamURLParam:
+		value = valueOf(req.Query, item.FieldName)
+	case ParamCookie:
+		value = valueOf(req.Cookie, item.FieldName)
+	}
+	return value, len(value) > 0
+}
+
+func valueOf(getter func(string) string, name string) string {
+	if getter == nil {
+		return ""
+	}
+	return getter(name)
+}
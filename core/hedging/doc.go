@@ -0,0 +1,15 @@
+// Package hedging provides the request hedging helper gated by Sentinel statistics.
+//
+// Hedging issues a second attempt for an outbound call if the first attempt doesn't complete within
+// the hedging delay (the current p95 RT of the resource by default), and returns the result of the attempt
+// that completes first. To avoid hedging storms, the hedged attempt is issued only when:
+//
+//  1. the current p95 RT of the resource exceeds the configured RT threshold, and
+//  2. the concurrency of in-flight hedged attempts of the resource is under the budget.
+//
+// The p95 RT is calculated from the RT histogram of the calls made through Do in a sliding window.
+//
+//	ret, err := hedging.Do(ctx, "some-service", func(ctx context.Context) (interface{}, error) {
+//	    return callSomeService(ctx)
+//	}, hedging.WithRtThresholdMs(50), hedging.WithMaxConcurrentHedges(10))
+package hedging
@@ -0,0 +1,143 @@
+package hedging
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+type options struct {
+	rtThresholdMs       uint64
+	maxConcurrentHedges int32
+	delay               time.Duration
+}
+
+// Option configures the hedging of Do.
+type Option func(*options)
+
+// WithRtThresholdMs sets the RT threshold, hedging is enabled only when the p95 RT of the resource exceeds it.
+func WithRtThresholdMs(rtThresholdMs uint64) Option {
+	return func(opts *options) {
+		opts.rtThresholdMs = rtThresholdMs
+	}
+}
+
+// WithMaxConcurrentHedges sets the max amount of in-flight hedged attempts of the resource, 1 by default.
+func WithMaxConcurrentHedges(maxConcurrentHedges int32) Option {
+	return func(opts *options) {
+		opts.maxConcurrentHedges = maxConcurrentHedges
+	}
+}
+
+// WithDelay sets the fixed hedging delay, which is the current p95 RT of the resource by default.
+func WithDelay(delay time.Duration) Option {
+	return func(opts *options) {
+		opts.delay = delay
+	}
+}
+
+func evaluateOptions(opts []Option) *options {
+	optCopy := &options{
+		maxConcurrentHedges: 1,
+	}
+	for _, opt := range opts {
+		opt(optCopy)
+	}
+	return optCopy
+}
+
+var (
+	hedgingConcurrency = make(map[string]*int32)
+	concurrencyMux     = new(sync.Mutex)
+)
+
+func concurrencyOf(resource string) *int32 {
+	concurrencyMux.Lock()
+	defer concurrencyMux.Unlock()
+
+	c, exist := hedgingConcurrency[resource]
+	if !exist {
+		c = new(int32)
+		hedgingConcurrency[resource] = c
+	}
+	return c
+}
+
+type attemptResult struct {
+	value interface{}
+	err   error
+}
+
+// Do invokes fn and issues a hedged attempt if the first attempt doesn't complete within the hedging delay,
+// as long as the p95 RT of the resource exceeds the RT threshold and the hedging concurrency budget allows.
+// The result of the attempt that completes first is returned, and the context of the other attempt is cancelled.
+func Do(ctx context.Context, resource string, fn func(ctx context.Context) (interface{}, error), opts ...Option) (interface{}, error) {
+	options := evaluateOptions(opts)
+	histogram := getOrCreateHistogram(resource)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan attemptResult, 2)
+	attempt := func(onFinished func()) {
+		defer func() {
+			if onFinished != nil {
+				onFinished()
+			}
+		}()
+		start := util.CurrentTimeMillis()
+		v, err := fn(ctx)
+		if err == nil {
+			histogram.record(util.CurrentTimeMillis() - start)
+		}
+		results <- attemptResult{value: v, err: err}
+	}
+	go attempt(nil)
+
+	delay, hedgeEnabled := hedgingDelay(resource, options)
+	if !hedgeEnabled {
+		r := <-results
+		return r.value, r.err
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case r := <-results:
+		return r.value, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+	}
+
+	concurrency := concurrencyOf(resource)
+	if atomic.AddInt32(concurrency, 1) > options.maxConcurrentHedges {
+		// Exceeds the hedging budget, so wait for the first attempt.
+		atomic.AddInt32(concurrency, -1)
+		r := <-results
+		return r.value, r.err
+	}
+	go attempt(func() {
+		atomic.AddInt32(concurrency, -1)
+	})
+
+	r := <-results
+	if r.err != nil {
+		// Give the other attempt a chance if the first completed one failed.
+		r = <-results
+	}
+	return r.value, r.err
+}
+
+func hedgingDelay(resource string, options *options) (time.Duration, bool) {
+	p95, ok := P95RtOf(resource)
+	if !ok || p95 <= options.rtThresholdMs {
+		return 0, false
+	}
+	if options.delay > 0 {
+		return options.delay, true
+	}
+	return time.Duration(p95) * time.Millisecond, true
+}
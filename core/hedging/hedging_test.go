@@ -0,0 +1,68 @@
+package hedging
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRtHistogram_Percentile(t *testing.T) {
+	h := newRtHistogram()
+	_, ok := h.percentile(0.95)
+	assert.False(t, ok)
+
+	for i := 0; i < 95; i++ {
+		h.record(3)
+	}
+	for i := 0; i < 5; i++ {
+		h.record(150)
+	}
+	p95, ok := h.percentile(0.95)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(5), p95)
+	p99, _ := h.percentile(0.99)
+	assert.Equal(t, uint64(200), p99)
+}
+
+func TestDo(t *testing.T) {
+	resource := "abc-hedging"
+	h := getOrCreateHistogram(resource)
+
+	var calls int32
+	fn := func(ctx context.Context) (interface{}, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// The first attempt is slow.
+			select {
+			case <-time.After(500 * time.Millisecond):
+			case <-ctx.Done():
+			}
+			return "slow", nil
+		}
+		return "fast", nil
+	}
+
+	// No RT recorded, so hedging is disabled.
+	v, err := Do(context.Background(), resource, fn, WithDelay(10*time.Millisecond))
+	assert.Nil(t, err)
+	assert.Equal(t, "slow", v)
+
+	for i := 0; i < 100; i++ {
+		h.record(100)
+	}
+	atomic.StoreInt32(&calls, 0)
+	// p95 RT doesn't exceed the threshold, so hedging is disabled.
+	v, _ = Do(context.Background(), resource, fn, WithRtThresholdMs(500), WithDelay(10*time.Millisecond))
+	assert.Equal(t, "slow", v)
+
+	atomic.StoreInt32(&calls, 0)
+	v, _ = Do(context.Background(), resource, fn, WithRtThresholdMs(50), WithDelay(10*time.Millisecond))
+	assert.Equal(t, "fast", v)
+
+	atomic.StoreInt32(&calls, 0)
+	// The hedging budget is exhausted.
+	v, _ = Do(context.Background(), resource, fn, WithRtThresholdMs(50), WithDelay(10*time.Millisecond), WithMaxConcurrentHedges(0))
+	assert.Equal(t, "slow", v)
+}
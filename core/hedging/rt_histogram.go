@@ -0,0 +1,141 @@
+package hedging
+
+import (
+	"sync"
+	"sync/atomic"
+
+	sbase "github.com/alibaba/sentinel-golang/core/stat/base"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/pkg/errors"
+)
+
+const (
+	histogramSampleCount uint32 = 10
+	histogramIntervalMs  uint32 = 10000
+)
+
+// rtBounds are the upper bounds (in ms) of the RT histogram buckets, while the last bucket is unbounded.
+var rtBounds = []uint64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000, 10000}
+
+type rtCounter struct {
+	counts []uint64
+}
+
+func newRtCounter() *rtCounter {
+	return &rtCounter{counts: make([]uint64, len(rtBounds)+1)}
+}
+
+// rtHistogram records the RT distribution of a resource in a sliding window.
+type rtHistogram struct {
+	data *sbase.LeapArray
+}
+
+func newRtHistogram() *rtHistogram {
+	h := &rtHistogram{}
+	leapArray, err := sbase.NewLeapArray(histogramSampleCount, histogramIntervalMs, h)
+	if err != nil {
+		// Should never happen as the parameters are constant.
+		panic(err)
+	}
+	h.data = leapArray
+	return h
+}
+
+func (h *rtHistogram) NewEmptyBucket() interface{} {
+	return newRtCounter()
+}
+
+func (h *rtHistogram) ResetBucketTo(bw *sbase.BucketWrap, startTime uint64) *sbase.BucketWrap {
+	atomic.StoreUint64(&bw.BucketStart, startTime)
+	bw.Value.Store(newRtCounter())
+	return bw
+}
+
+func (h *rtHistogram) record(rt uint64) {
+	curBucket, err := h.data.CurrentBucket(h)
+	if err != nil || curBucket == nil {
+		logging.Error(errors.Errorf("failed to get current bucket: %+v", err), "Failed to record RT for hedging")
+		return
+	}
+	counter, ok := curBucket.Value.Load().(*rtCounter)
+	if !ok {
+		return
+	}
+	idx := len(rtBounds)
+	for i, bound := range rtBounds {
+		if rt <= bound {
+			idx = i
+			break
+		}
+	}
+	atomic.AddUint64(&counter.counts[idx], 1)
+}
+
+// percentile returns the upper bound of the bucket where the p-th percentile RT (p in (0, 1]) falls,
+// and false if there is no RT recorded.
+func (h *rtHistogram) percentile(p float64) (uint64, bool) {
+	counts := make([]uint64, len(rtBounds)+1)
+	total := uint64(0)
+	for _, bw := range h.data.Values() {
+		counter, ok := bw.Value.Load().(*rtCounter)
+		if !ok {
+			continue
+		}
+		for i := range counts {
+			c := atomic.LoadUint64(&counter.counts[i])
+			counts[i] += c
+			total += c
+		}
+	}
+	if total == 0 {
+		return 0, false
+	}
+	target := p * float64(total)
+	cumulative := uint64(0)
+	for i, c := range counts {
+		cumulative += c
+		if float64(cumulative) >= target {
+			if i < len(rtBounds) {
+				return rtBounds[i], true
+			}
+			break
+		}
+	}
+	// Falls in the unbounded bucket.
+	return rtBounds[len(rtBounds)-1] * 2, true
+}
+
+var (
+	histogramMap = make(map[string]*rtHistogram)
+	histogramMux = new(sync.RWMutex)
+)
+
+func getOrCreateHistogram(resource string) *rtHistogram {
+	histogramMux.RLock()
+	h, exist := histogramMap[resource]
+	histogramMux.RUnlock()
+	if exist {
+		return h
+	}
+
+	histogramMux.Lock()
+	defer histogramMux.Unlock()
+	if h, exist = histogramMap[resource]; exist {
+		return h
+	}
+	h = newRtHistogram()
+	histogramMap[resource] = h
+	return h
+}
+
+// P95RtOf returns the current p95 RT (in ms, approximated by the histogram bucket bound) of the calls
+// of the resource made through Do, and false if there is no RT recorded.
+func P95RtOf(resource string) (uint64, bool) {
+	histogramMux.RLock()
+	h, exist := histogramMap[resource]
+	histogramMux.RUnlock()
+	if !exist {
+		return 0, false
+	}
+	return h.percentile(0.95)
+}
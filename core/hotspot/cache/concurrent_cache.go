@@ -29,4 +29,8 @@ type ConcurrentCounterCache interface {
 
 	// Purge clears all cache entries.
 	Purge()
+
+	// Evictions returns the number of entries evicted due to the capacity or expiration,
+	// which indicates whether the capacity of the cache is the bottleneck.
+	Evictions() uint64
 }
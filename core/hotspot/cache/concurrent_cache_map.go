@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// counterCache is the non-thread safe cache backing the concurrent cache map.
+type counterCache interface {
+	Add(key, value interface{})
+	AddIfAbsent(key interface{}, value interface{}) (priorValue interface{})
+	Get(key interface{}) (value interface{}, isFound bool)
+	Remove(key interface{}) (isFound bool)
+	Contains(key interface{}) (ok bool)
+	Keys() []interface{}
+	Len() int
+	Purge()
+	Evictions() uint64
+}
+
+// lockedCacheMap is the thread-safe ConcurrentCounterCache guarded by a mutex.
+// Note that the read operations of LFU and TTL caches update the frequency or the access time,
+// so the mutex is always exclusive except for Contains, Keys, Len and Evictions.
+type lockedCacheMap struct {
+	c    counterCache
+	lock *sync.RWMutex
+}
+
+func (m *lockedCacheMap) Add(key interface{}, value *int64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.c.Add(key, value)
+}
+
+func (m *lockedCacheMap) AddIfAbsent(key interface{}, value *int64) (priorValue *int64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	val := m.c.AddIfAbsent(key, value)
+	if val == nil {
+		return nil
+	}
+	return val.(*int64)
+}
+
+func (m *lockedCacheMap) Get(key interface{}) (value *int64, isFound bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	val, found := m.c.Get(key)
+	if found {
+		return val.(*int64), true
+	}
+	return nil, false
+}
+
+func (m *lockedCacheMap) Remove(key interface{}) (isFound bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.c.Remove(key)
+}
+
+func (m *lockedCacheMap) Contains(key interface{}) (ok bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.c.Contains(key)
+}
+
+func (m *lockedCacheMap) Keys() []interface{} {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.c.Keys()
+}
+
+func (m *lockedCacheMap) Len() int {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.c.Len()
+}
+
+func (m *lockedCacheMap) Purge() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.c.Purge()
+}
+
+func (m *lockedCacheMap) Evictions() uint64 {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.c.Evictions()
+}
+
+// NewLFUCacheMap creates the thread-safe ConcurrentCounterCache using LFU strategy.
+func NewLFUCacheMap(size int) ConcurrentCounterCache {
+	lfu, err := NewLFU(size)
+	if err != nil {
+		return nil
+	}
+	return &lockedCacheMap{
+		c:    lfu,
+		lock: new(sync.RWMutex),
+	}
+}
+
+// NewTTLCacheMap creates the thread-safe ConcurrentCounterCache whose entries expire after ttl since the last access.
+func NewTTLCacheMap(size int, ttl time.Duration) ConcurrentCounterCache {
+	c, err := NewTTL(size, ttl)
+	if err != nil {
+		return nil
+	}
+	return &lockedCacheMap{
+		c:    c,
+		lock: new(sync.RWMutex),
+	}
+}
@@ -76,6 +76,13 @@ func (c *LruCacheMap) Purge() {
 	c.lru.Purge()
 }
 
+func (c *LruCacheMap) Evictions() uint64 {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.lru.Evictions()
+}
+
 func NewLRUCacheMap(size int) ConcurrentCounterCache {
 	lru, err := NewLRU(size, nil)
 	if err != nil {
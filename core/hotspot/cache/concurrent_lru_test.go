@@ -94,3 +94,13 @@ func Test_concurrentLruCounterCacheMap_Remove(t *testing.T) {
 		assert.True(t, existed == false && val == nil)
 	})
 }
+
+func Test_concurrentLruCounterCacheMap_Evictions(t *testing.T) {
+	c := NewLRUCacheMap(100)
+	for i := 1; i <= 150; i++ {
+		val := int64(i)
+		c.Add(strconv.Itoa(i), &val)
+	}
+	c.Remove("150")
+	assert.Equal(t, uint64(50), c.Evictions())
+}
@@ -0,0 +1,161 @@
+package cache
+
+import (
+	"container/list"
+
+	"github.com/pkg/errors"
+)
+
+// LFU implements a non-thread safe fixed size LFU cache. It evicts the least frequently used item,
+// and the least recently used one among the items of the same frequency. Compared with LRU, the
+// frequently accessed items are not flushed out by the bursts of long-tail keys.
+type LFU struct {
+	size int
+	// freqList holds the *lfuFreqNode in ascending order of frequency.
+	freqList  *list.List
+	items     map[interface{}]*lfuEntry
+	evictions uint64
+}
+
+type lfuFreqNode struct {
+	freq uint64
+	// entries holds the *lfuEntry of the frequency, from newest to oldest.
+	entries *list.List
+}
+
+type lfuEntry struct {
+	key      interface{}
+	value    interface{}
+	freqElem *list.Element
+	elem     *list.Element
+}
+
+// NewLFU constructs an LFU of the given size.
+func NewLFU(size int) (*LFU, error) {
+	if size <= 0 {
+		return nil, errors.New("Must provide a positive size")
+	}
+	return &LFU{
+		size:     size,
+		freqList: list.New(),
+		items:    make(map[interface{}]*lfuEntry),
+	}, nil
+}
+
+// Purge is used to completely clear the cache.
+func (c *LFU) Purge() {
+	c.items = make(map[interface{}]*lfuEntry)
+	c.freqList.Init()
+}
+
+func (c *LFU) Add(key, value interface{}) {
+	if ent, ok := c.items[key]; ok {
+		c.increment(ent)
+		ent.value = value
+		return
+	}
+	c.addNew(key, value)
+}
+
+// AddIfAbsent adds item only if key is not existed.
+func (c *LFU) AddIfAbsent(key interface{}, value interface{}) (priorValue interface{}) {
+	if ent, ok := c.items[key]; ok {
+		c.increment(ent)
+		return ent.value
+	}
+	c.addNew(key, value)
+	return nil
+}
+
+// Get looks up a key's value from the cache.
+func (c *LFU) Get(key interface{}) (value interface{}, isFound bool) {
+	if ent, ok := c.items[key]; ok {
+		c.increment(ent)
+		return ent.value, true
+	}
+	return nil, false
+}
+
+// Contains checks if a key is in the cache, without updating the frequency.
+func (c *LFU) Contains(key interface{}) (ok bool) {
+	_, ok = c.items[key]
+	return ok
+}
+
+// Remove removes the provided key from the cache, returning if the key was contained.
+func (c *LFU) Remove(key interface{}) (isFound bool) {
+	if ent, ok := c.items[key]; ok {
+		c.removeEntry(ent)
+		return true
+	}
+	return false
+}
+
+// Keys returns a slice of the keys in the cache, from the least frequently used to the most frequently used.
+func (c *LFU) Keys() []interface{} {
+	keys := make([]interface{}, 0, len(c.items))
+	for fe := c.freqList.Front(); fe != nil; fe = fe.Next() {
+		for e := fe.Value.(*lfuFreqNode).entries.Back(); e != nil; e = e.Prev() {
+			keys = append(keys, e.Value.(*lfuEntry).key)
+		}
+	}
+	return keys
+}
+
+// Len returns the number of items in the cache.
+func (c *LFU) Len() int {
+	return len(c.items)
+}
+
+// Evictions returns the number of items evicted due to the size.
+func (c *LFU) Evictions() uint64 {
+	return c.evictions
+}
+
+func (c *LFU) addNew(key, value interface{}) {
+	if len(c.items) >= c.size {
+		c.evict()
+	}
+	fe := c.freqList.Front()
+	if fe == nil || fe.Value.(*lfuFreqNode).freq != 1 {
+		fe = c.freqList.PushFront(&lfuFreqNode{freq: 1, entries: list.New()})
+	}
+	ent := &lfuEntry{key: key, value: value, freqElem: fe}
+	ent.elem = fe.Value.(*lfuFreqNode).entries.PushFront(ent)
+	c.items[key] = ent
+}
+
+func (c *LFU) increment(ent *lfuEntry) {
+	cur := ent.freqElem
+	node := cur.Value.(*lfuFreqNode)
+	next := cur.Next()
+	if next == nil || next.Value.(*lfuFreqNode).freq != node.freq+1 {
+		next = c.freqList.InsertAfter(&lfuFreqNode{freq: node.freq + 1, entries: list.New()}, cur)
+	}
+	node.entries.Remove(ent.elem)
+	if node.entries.Len() == 0 {
+		c.freqList.Remove(cur)
+	}
+	ent.freqElem = next
+	ent.elem = next.Value.(*lfuFreqNode).entries.PushFront(ent)
+}
+
+func (c *LFU) evict() {
+	fe := c.freqList.Front()
+	if fe == nil {
+		return
+	}
+	if e := fe.Value.(*lfuFreqNode).entries.Back(); e != nil {
+		c.removeEntry(e.Value.(*lfuEntry))
+		c.evictions++
+	}
+}
+
+func (c *LFU) removeEntry(ent *lfuEntry) {
+	node := ent.freqElem.Value.(*lfuFreqNode)
+	node.entries.Remove(ent.elem)
+	if node.entries.Len() == 0 {
+		c.freqList.Remove(ent.freqElem)
+	}
+	delete(c.items, ent.key)
+}
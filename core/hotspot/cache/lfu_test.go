@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLFU(t *testing.T) {
+	c, err := NewLFU(3)
+	assert.Nil(t, err)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Add("c", 3)
+	// a: 3, b: 2, c: 1
+	c.Get("a")
+	c.Get("a")
+	c.Get("b")
+	assert.Equal(t, []interface{}{"c", "b", "a"}, c.Keys())
+
+	// c is the least frequently used one.
+	assert.Nil(t, c.AddIfAbsent("d", 4))
+	assert.False(t, c.Contains("c"))
+	assert.Equal(t, uint64(1), c.Evictions())
+	assert.Equal(t, 3, c.Len())
+
+	// d and e have the same frequency, d is older.
+	c.Add("e", 5)
+	assert.False(t, c.Contains("d"))
+	assert.True(t, c.Contains("a"))
+	assert.Equal(t, 2, c.AddIfAbsent("b", 20))
+	v, ok := c.Get("e")
+	assert.True(t, ok)
+	assert.Equal(t, 5, v)
+
+	assert.True(t, c.Remove("e"))
+	assert.False(t, c.Remove("e"))
+	assert.Equal(t, 2, c.Len())
+	c.Purge()
+	assert.Equal(t, 0, c.Len())
+	assert.Equal(t, 0, len(c.Keys()))
+
+	_, err = NewLFU(0)
+	assert.NotNil(t, err)
+}
+
+func TestLFUCacheMap_LongTailKeys(t *testing.T) {
+	c := NewLFUCacheMap(10)
+	for i := 0; i < 5; i++ {
+		v := int64(i)
+		c.Add("hot-"+strconv.Itoa(i), &v)
+		c.Get("hot-" + strconv.Itoa(i))
+	}
+	for i := 0; i < 100; i++ {
+		v := int64(i)
+		c.AddIfAbsent("tail-"+strconv.Itoa(i), &v)
+	}
+	for i := 0; i < 5; i++ {
+		assert.True(t, c.Contains("hot-"+strconv.Itoa(i)))
+	}
+	assert.Equal(t, 10, c.Len())
+	assert.Equal(t, uint64(95), c.Evictions())
+}
+
+func TestTTLCacheMap(t *testing.T) {
+	c := NewTTLCacheMap(3, 50*time.Millisecond)
+	for i := 1; i <= 4; i++ {
+		v := int64(i)
+		c.Add(strconv.Itoa(i), &v)
+	}
+	assert.False(t, c.Contains("1"))
+	assert.Equal(t, uint64(1), c.Evictions())
+	val, ok := c.Get("2")
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), *val)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, c.Contains("2"))
+	assert.Equal(t, 0, len(c.Keys()))
+	_, ok = c.Get("2")
+	assert.False(t, ok)
+
+	v := int64(5)
+	assert.Nil(t, c.AddIfAbsent("3", &v))
+	assert.Equal(t, 1, c.Len())
+	assert.Equal(t, uint64(4), c.Evictions())
+
+	assert.Nil(t, NewTTLCacheMap(3, 0))
+}
@@ -15,6 +15,7 @@ type LRU struct {
 	evictList *list.List
 	items     map[interface{}]*list.Element
 	onEvict   EvictCallback
+	evictions uint64
 }
 
 // entry is used to hold a value in the evictList
@@ -180,11 +181,17 @@ func (c *LRU) Resize(size int) (evicted int) {
 	return diff
 }
 
+// Evictions returns the number of items evicted due to the size.
+func (c *LRU) Evictions() uint64 {
+	return c.evictions
+}
+
 // removeOldest removes the oldest item from the cache.
 func (c *LRU) removeOldest() {
 	ent := c.evictList.Back()
 	if ent != nil {
 		c.removeElement(ent)
+		c.evictions++
 	}
 }
 
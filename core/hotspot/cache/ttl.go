@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+// maxExpiredScanCount is the max count of expired items removed in one write operation,
+// which bounds the latency of the write operations.
+const maxExpiredScanCount = 16
+
+// TTL implements a non-thread safe fixed size cache whose items expire after ttl since the last access.
+// The expired items are removed lazily, and the least recently used item is evicted if the cache is full.
+// It suits the long-tail keys that are accessed only once in a while.
+type TTL struct {
+	lru   *LRU
+	ttlMs uint64
+	// expirations is the number of items removed due to the expiration.
+	expirations uint64
+}
+
+type ttlItem struct {
+	value          interface{}
+	lastAccessTime uint64
+}
+
+// NewTTL constructs a TTL cache of the given size and ttl.
+func NewTTL(size int, ttl time.Duration) (*TTL, error) {
+	if ttl <= 0 {
+		return nil, errors.New("Must provide a positive ttl")
+	}
+	lru, err := NewLRU(size, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &TTL{
+		lru:   lru,
+		ttlMs: uint64(ttl.Milliseconds()),
+	}, nil
+}
+
+func (c *TTL) expired(item *ttlItem, now uint64) bool {
+	return now > item.lastAccessTime && now-item.lastAccessTime > c.ttlMs
+}
+
+// Purge is used to completely clear the cache.
+func (c *TTL) Purge() {
+	c.lru.Purge()
+}
+
+func (c *TTL) Add(key, value interface{}) {
+	now := util.CurrentTimeMillis()
+	c.removeExpired(now)
+	c.lru.Add(key, &ttlItem{value: value, lastAccessTime: now})
+}
+
+// AddIfAbsent adds item only if key is not existed or expired.
+func (c *TTL) AddIfAbsent(key interface{}, value interface{}) (priorValue interface{}) {
+	now := util.CurrentTimeMillis()
+	c.removeExpired(now)
+	if v, ok := c.getAlive(key, now); ok {
+		return v
+	}
+	c.lru.Add(key, &ttlItem{value: value, lastAccessTime: now})
+	return nil
+}
+
+// Get looks up a key's value from the cache, the expired items are regarded as not found.
+func (c *TTL) Get(key interface{}) (value interface{}, isFound bool) {
+	return c.getAlive(key, util.CurrentTimeMillis())
+}
+
+func (c *TTL) getAlive(key interface{}, now uint64) (value interface{}, isFound bool) {
+	v, ok := c.lru.Get(key)
+	if !ok {
+		return nil, false
+	}
+	item := v.(*ttlItem)
+	if c.expired(item, now) {
+		c.lru.Remove(key)
+		c.expirations++
+		return nil, false
+	}
+	item.lastAccessTime = now
+	return item.value, true
+}
+
+// Contains checks if a key is in the cache and not expired, without updating the recent-ness.
+func (c *TTL) Contains(key interface{}) (ok bool) {
+	v, ok := c.lru.Peek(key)
+	return ok && !c.expired(v.(*ttlItem), util.CurrentTimeMillis())
+}
+
+// Remove removes the provided key from the cache, returning if the key was contained.
+func (c *TTL) Remove(key interface{}) (isFound bool) {
+	return c.lru.Remove(key)
+}
+
+// Keys returns a slice of the keys not expired in the cache, from oldest to newest.
+func (c *TTL) Keys() []interface{} {
+	now := util.CurrentTimeMillis()
+	keys := make([]interface{}, 0, c.lru.Len())
+	for _, k := range c.lru.Keys() {
+		if v, ok := c.lru.Peek(k); ok && !c.expired(v.(*ttlItem), now) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// Len returns the number of items in the cache, including the expired items not removed yet.
+func (c *TTL) Len() int {
+	return c.lru.Len()
+}
+
+// Evictions returns the number of items evicted due to the size or expiration.
+func (c *TTL) Evictions() uint64 {
+	return c.lru.Evictions() + c.expirations
+}
+
+// removeExpired removes the expired items from the oldest.
+func (c *TTL) removeExpired(now uint64) {
+	for i := 0; i < maxExpiredScanCount; i++ {
+		_, v, ok := c.lru.GetOldest()
+		if !ok || !c.expired(v.(*ttlItem), now) {
+			return
+		}
+		c.lru.RemoveOldest()
+		c.expirations++
+	}
+}
@@ -0,0 +1,205 @@
+package hotspot
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/cluster"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+// fallbackControllers holds the local controllers with the fallback thresholds suggested by the token server,
+// keyed by the rule ID.
+var fallbackControllers sync.Map
+
+type fallbackController struct {
+	rule      *Rule
+	threshold float64
+	tc        TrafficShapingController
+}
+
+// canPassClusterCheck acquires the token of the param from the token server.
+func canPassClusterCheck(tc TrafficShapingController, arg interface{}, acquire int64) *base.TokenResult {
+	rule := tc.BoundRule()
+	svc := cluster.CurrentTokenService()
+	if svc == nil {
+		return fallbackToLocalOrPass(tc, arg, acquire)
+	}
+	resp, err := svc.RequestToken(&cluster.TokenRequest{
+		ID:           cluster.NextRequestID(),
+		RuleID:       rule.ID,
+		AcquireCount: uint32(acquire),
+		Resource:     rule.Resource,
+		Params:       []string{fmt.Sprint(arg)},
+	})
+	if err != nil {
+		logging.Warn("[HotSpot canPassClusterCheck] Failed to request token from token server", "ruleId", rule.ID, "err", err.Error())
+		return fallbackToLocalOrPass(tc, arg, acquire)
+	}
+	if resp.FallbackThreshold > 0 {
+		updateFallbackController(rule, resp.FallbackThreshold)
+	}
+	switch resp.Status {
+	case cluster.TokenStatusOK:
+		return nil
+	case cluster.TokenStatusBlocked:
+		return base.NewTokenResultBlockedWithCause(base.BlockTypeHotSpotParamFlow, fmt.Sprintf("arg=%v", arg), rule, nil)
+	case cluster.TokenStatusShouldWait:
+		return base.NewTokenResultShouldWait(uint64(resp.WaitMs))
+	default:
+		logging.Warn("[HotSpot canPassClusterCheck] Unexpected token status from token server", "ruleId", rule.ID, "status", resp.Status.String())
+		return fallbackToLocalOrPass(tc, arg, acquire)
+	}
+}
+
+func fallbackToLocalOrPass(tc TrafficShapingController, arg interface{}, acquire int64) *base.TokenResult {
+	rule := tc.BoundRule()
+	if !rule.ClusterFallbackToLocal {
+		return nil
+	}
+	if v, ok := fallbackControllers.Load(rule.ID); ok {
+		if fc := v.(*fallbackController); fc.rule == rule {
+			return canPassLocalCheck(fc.tc, arg, acquire)
+		}
+	}
+	return canPassLocalCheck(tc, arg, acquire)
+}
+
+// updateFallbackController keeps the local controller with the fallback threshold suggested by the token server,
+// which takes effect instead of the cluster threshold of the rule when falling back to local checking.
+func updateFallbackController(rule *Rule, threshold float64) {
+	if !rule.ClusterFallbackToLocal {
+		return
+	}
+	if v, ok := fallbackControllers.Load(rule.ID); ok {
+		if fc := v.(*fallbackController); fc.rule == rule && fc.threshold == threshold {
+			return
+		}
+	}
+	tcMux.RLock()
+	generator, supported := tcGenFuncMap[rule.ControlBehavior]
+	tcMux.RUnlock()
+	if !supported {
+		return
+	}
+	localRule := *rule
+	localRule.Threshold = threshold
+	localRule.ClusterMode = false
+	fallbackControllers.Store(rule.ID, &fallbackController{
+		rule:      rule,
+		threshold: threshold,
+		tc:        generator(&localRule, nil),
+	})
+	logging.Info("[HotSpot] Fallback threshold suggested by token server is updated", "ruleId", rule.ID, "threshold", threshold)
+}
+
+// HandleClusterTokenRequest handles the token request of the hotspot rules in cluster mode, which should be
+// called by the token server. The rules in cluster mode are loaded on the token server as usual, and checked
+// locally on the token server with the param value carried in the request.
+func HandleClusterTokenRequest(req *cluster.TokenRequest) *cluster.TokenResponse {
+	resp := &cluster.TokenResponse{ID: req.ID}
+	if len(req.RuleID) == 0 || len(req.Params) == 0 || req.AcquireCount == 0 {
+		resp.Status = cluster.TokenStatusBadRequest
+		return resp
+	}
+	tc := findClusterTrafficController(req.Resource, req.RuleID)
+	if tc == nil {
+		resp.Status = cluster.TokenStatusNoRuleExists
+		return resp
+	}
+	arg := parseClusterParam(tc.BoundRule(), req.Params[0])
+	if store := cluster.CurrentWindowStore(); store != nil {
+		if c, ok := tc.(*rejectTrafficShapingController); ok && c.metricType == QPS {
+			if acquireFromWindowStore(store, c, arg, req, resp) {
+				return resp
+			}
+		}
+	}
+	r := canPassLocalCheck(tc, arg, int64(req.AcquireCount))
+	switch {
+	case r == nil || r.IsPass():
+		resp.Status = cluster.TokenStatusOK
+	case r.IsBlocked():
+		resp.Status = cluster.TokenStatusBlocked
+		resp.Message = r.BlockError().BlockMsg()
+	default:
+		resp.Status = cluster.TokenStatusShouldWait
+		resp.WaitMs = uint32(r.WaitMs())
+	}
+	return resp
+}
+
+// acquireFromWindowStore acquires the tokens of the param from the global window in the window store, so that
+// the counters survive the token server failover. It returns false if the store fails, in which case the request
+// is checked with the in-memory counters instead.
+func acquireFromWindowStore(store cluster.WindowStore, c *rejectTrafficShapingController, arg interface{}, req *cluster.TokenRequest, resp *cluster.TokenResponse) bool {
+	tokenCount := int64(c.threshold)
+	if val, existed := c.specificThresholdOf(arg); existed {
+		tokenCount = val
+	}
+	if tokenCount <= 0 || c.durationInSec <= 0 {
+		resp.Status = cluster.TokenStatusBlocked
+		resp.Message = fmt.Sprintf("arg=%v", arg)
+		return true
+	}
+	windowLengthMs := uint64(c.durationInSec * 1000)
+	now := util.CurrentTimeMillis()
+	remaining, ok, err := store.TryAcquire(req.RuleID+":"+req.Params[0], now-now%windowLengthMs, windowLengthMs,
+		int64(req.AcquireCount), tokenCount+c.burstCount)
+	if err != nil {
+		logging.Warn("[HotSpot acquireFromWindowStore] Failed to acquire tokens from window store, check in memory instead", "ruleId", req.RuleID, "err", err.Error())
+		return false
+	}
+	resp.Remaining = remaining
+	if ok {
+		resp.Status = cluster.TokenStatusOK
+	} else {
+		resp.Status = cluster.TokenStatusBlocked
+		resp.Message = fmt.Sprintf("arg=%v", arg)
+	}
+	return true
+}
+
+func findClusterTrafficController(res, ruleID string) TrafficShapingController {
+	tcMux.RLock()
+	defer tcMux.RUnlock()
+
+	for resource, tcs := range tcMap {
+		if len(res) > 0 && resource != res {
+			continue
+		}
+		for _, tc := range tcs {
+			if r := tc.BoundRule(); r.ClusterMode && r.ID == ruleID {
+				return tc
+			}
+		}
+	}
+	return nil
+}
+
+// parseClusterParam parses the param value in the token request as the kind of the specific items of the rule,
+// so that the specific thresholds take effect on the token server.
+func parseClusterParam(r *Rule, param string) interface{} {
+	for _, item := range r.SpecificItems {
+		switch item.ValKind {
+		case KindInt:
+			if v, err := strconv.Atoi(param); err == nil {
+				return v
+			}
+		case KindBool:
+			if v, err := strconv.ParseBool(param); err == nil {
+				return v
+			}
+		case KindFloat64:
+			if v, err := strconv.ParseFloat(param, 64); err == nil {
+				if v, err = strconv.ParseFloat(fmt.Sprintf("%.5f", v), 64); err == nil {
+					return v
+				}
+			}
+		}
+	}
+	return param
+}
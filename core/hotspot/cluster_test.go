@@ -0,0 +1,241 @@
+package hotspot
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/cluster"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// inProcessTokenService serves the token requests with HandleClusterTokenRequest in current process.
+type inProcessTokenService struct {
+	err      error
+	requests []*cluster.TokenRequest
+}
+
+func (s *inProcessTokenService) RequestToken(req *cluster.TokenRequest) (*cluster.TokenResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	codec := cluster.DefaultCodec()
+	b, err := codec.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	decoded := &cluster.TokenRequest{}
+	if err := codec.Unmarshal(b, decoded); err != nil {
+		return nil, err
+	}
+	s.requests = append(s.requests, decoded)
+	return HandleClusterTokenRequest(decoded), nil
+}
+
+func TestClusterMode(t *testing.T) {
+	defer func() {
+		cluster.SetTokenService(nil)
+		_ = ClearRules()
+	}()
+	_, err := LoadRules([]*Rule{
+		{
+			ID:              "cluster-1",
+			Resource:        "abc",
+			MetricType:      QPS,
+			ControlBehavior: Reject,
+			ParamIndex:      0,
+			Threshold:       2,
+			DurationInSec:   10,
+			SpecificItems:   []SpecificValue{{ValKind: KindInt, ValStr: "100", Threshold: 1}},
+			ClusterMode:     true,
+		},
+	})
+	assert.Nil(t, err)
+
+	svc := &inProcessTokenService{}
+	cluster.SetTokenService(svc)
+	slot := &Slot{}
+	check := func(arg interface{}) *base.TokenResult {
+		ctx := base.NewEmptyEntryContext()
+		ctx.Resource = base.NewResourceWrapper("abc", base.ResTypeCommon, base.Inbound)
+		ctx.Input = &base.SentinelInput{AcquireCount: 1, Args: []interface{}{arg}}
+		ctx.RuleCheckResult = base.NewTokenResultPass()
+		return slot.Check(ctx)
+	}
+
+	t.Run("ParamCarriedInRequest", func(t *testing.T) {
+		assert.True(t, check("user-a").IsPass())
+		assert.True(t, check("user-a").IsPass())
+		r := check("user-a")
+		assert.True(t, r.IsBlocked())
+		assert.Equal(t, base.BlockTypeHotSpotParamFlow, r.BlockError().BlockType())
+		assert.True(t, check("user-b").IsPass())
+
+		last := svc.requests[len(svc.requests)-1]
+		assert.Equal(t, "cluster-1", last.RuleID)
+		assert.Equal(t, []string{"user-b"}, last.Params)
+	})
+
+	t.Run("SpecificItem", func(t *testing.T) {
+		assert.True(t, check(100).IsPass())
+		assert.True(t, check(100).IsBlocked())
+	})
+
+	t.Run("TokenServerUnavailable", func(t *testing.T) {
+		svc.err = errors.New("connection refused")
+		defer func() {
+			svc.err = nil
+		}()
+		// Pass without fallback.
+		assert.True(t, check("user-a").IsPass())
+	})
+
+	t.Run("HandleBadRequest", func(t *testing.T) {
+		resp := HandleClusterTokenRequest(&cluster.TokenRequest{ID: 1, RuleID: "cluster-1", AcquireCount: 1})
+		assert.Equal(t, cluster.TokenStatusBadRequest, resp.Status)
+		assert.Equal(t, uint64(1), resp.ID)
+		resp = HandleClusterTokenRequest(&cluster.TokenRequest{ID: 2, RuleID: "not-exist", AcquireCount: 1, Params: []string{"a"}})
+		assert.Equal(t, cluster.TokenStatusNoRuleExists, resp.Status)
+	})
+}
+
+// memoryRedisClient is the in-memory RedisClient shared by the token servers in the tests,
+// which emulates the acquiring script of the Redis window store.
+type memoryRedisClient struct {
+	mux    sync.Mutex
+	values map[string]int64
+	err    error
+}
+
+func (c *memoryRedisClient) Eval(_ string, keys []string, args ...interface{}) (interface{}, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+	key, acquire, maxCount := keys[0], args[0].(int64), args[1].(int64)
+	if c.values[key]+acquire > maxCount {
+		return []interface{}{int64(0), maxCount - c.values[key]}, nil
+	}
+	c.values[key] += acquire
+	return []interface{}{int64(1), maxCount - c.values[key]}, nil
+}
+
+func TestClusterModeWithWindowStore(t *testing.T) {
+	defer func() {
+		cluster.SetWindowStore(nil)
+		_ = ClearRules()
+	}()
+	rule := &Rule{
+		ID:              "cluster-3",
+		Resource:        "abc",
+		MetricType:      QPS,
+		ControlBehavior: Reject,
+		Threshold:       2,
+		DurationInSec:   100,
+		ClusterMode:     true,
+	}
+	_, err := LoadRules([]*Rule{rule})
+	assert.Nil(t, err)
+
+	client := &memoryRedisClient{values: make(map[string]int64)}
+	store, err := cluster.NewRedisWindowStore(client, "sentinel:")
+	assert.Nil(t, err)
+	cluster.SetWindowStore(store)
+	handle := func() *cluster.TokenResponse {
+		return HandleClusterTokenRequest(&cluster.TokenRequest{ID: 1, RuleID: "cluster-3", AcquireCount: 1, Params: []string{"a"}})
+	}
+
+	resp := handle()
+	assert.Equal(t, cluster.TokenStatusOK, resp.Status)
+	assert.Equal(t, int64(1), resp.Remaining)
+
+	// Fail over to a new token server, which reloads the rules with the empty in-memory counters.
+	_ = ClearRules()
+	_, err = LoadRules([]*Rule{rule})
+	assert.Nil(t, err)
+	assert.Equal(t, cluster.TokenStatusOK, handle().Status)
+	assert.Equal(t, cluster.TokenStatusBlocked, handle().Status)
+
+	t.Run("StoreUnavailable", func(t *testing.T) {
+		client.err = errors.New("connection refused")
+		defer func() {
+			client.err = nil
+		}()
+		// Check with the in-memory counters instead.
+		assert.Equal(t, cluster.TokenStatusOK, handle().Status)
+	})
+}
+
+func TestClusterModeFallbackToLocal(t *testing.T) {
+	defer func() {
+		_ = ClearRules()
+	}()
+	rule := &Rule{
+		ID:                     "cluster-2",
+		Resource:               "abc",
+		MetricType:             QPS,
+		ControlBehavior:        Reject,
+		Threshold:              1,
+		DurationInSec:          10,
+		ClusterMode:            true,
+		ClusterFallbackToLocal: true,
+	}
+	_, err := LoadRules([]*Rule{rule})
+	assert.Nil(t, err)
+
+	tcs := getTrafficControllersFor("abc")
+	assert.Equal(t, 1, len(tcs))
+	// No token service, so check locally.
+	assert.Nil(t, canPassCheck(tcs[0], "a", 1))
+	assert.True(t, canPassCheck(tcs[0], "a", 1).IsBlocked())
+}
+
+func TestIsValidRuleOfClusterMode(t *testing.T) {
+	rule := &Rule{Resource: "abc", MetricType: QPS, Threshold: 1, DurationInSec: 1, ClusterMode: true}
+	assert.NotNil(t, IsValidRule(rule))
+	rule.ID = "r1"
+	assert.Nil(t, IsValidRule(rule))
+	rule.MetricType = Concurrency
+	assert.NotNil(t, IsValidRule(rule))
+}
+
+// overloadedTokenService responds TooManyRequests with the suggested fallback threshold.
+type overloadedTokenService struct {
+}
+
+func (s *overloadedTokenService) RequestToken(req *cluster.TokenRequest) (*cluster.TokenResponse, error) {
+	resp := &cluster.TokenResponse{ID: req.ID, Status: cluster.TokenStatusTooManyRequests}
+	cluster.AttachLoadReport(resp, 10, 2, 5)
+	return resp, nil
+}
+
+func TestClusterModeSuggestedFallbackThreshold(t *testing.T) {
+	defer func() {
+		cluster.SetTokenService(nil)
+		_ = ClearRules()
+	}()
+	rule := &Rule{
+		ID:                     "cluster-3",
+		Resource:               "abc",
+		MetricType:             QPS,
+		ControlBehavior:        Reject,
+		Threshold:              10,
+		DurationInSec:          10,
+		ClusterMode:            true,
+		ClusterFallbackToLocal: true,
+	}
+	_, err := LoadRules([]*Rule{rule})
+	assert.Nil(t, err)
+	cluster.SetTokenService(&overloadedTokenService{})
+
+	tcs := getTrafficControllersFor("abc")
+	assert.Equal(t, 1, len(tcs))
+	// The suggested threshold is 10 / 5 / 2 = 1 rather than the cluster threshold 10.
+	assert.Nil(t, canPassCheck(tcs[0], "a", 1))
+	assert.True(t, canPassCheck(tcs[0], "a", 1).IsBlocked())
+	// The cluster threshold is not affected.
+	assert.Equal(t, 10.0, tcs[0].BoundRule().Threshold)
+}
@@ -0,0 +1,44 @@
+package hotspot
+
+import (
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+)
+
+var (
+	// namespaceRules holds the rules of the namespaces except for the default one, see LoadRulesOfNamespace.
+	namespaceRules base.NamespaceRules
+	// defaultNamespaceRules are the rules loaded by LoadRules.
+	defaultNamespaceRules []*Rule
+)
+
+// LoadRulesOfNamespace loads the hotspot rules of the namespace, which replace the previous rules of the namespace only.
+// LoadRules loads the rules of the default namespace "", see base.NamespaceRules.
+func LoadRulesOfNamespace(namespace string, rules []*Rule) (bool, error) {
+	if len(namespace) == 0 {
+		return LoadRules(rules)
+	}
+	defer selfmetric.RecordRuleUpdate("hotspot", time.Now())
+
+	updateRuleMux.Lock()
+	defer updateRuleMux.Unlock()
+
+	namespaceRules.Set(namespace, rules)
+	return true, onRuleUpdate(withNamespaceRules(defaultNamespaceRules))
+}
+
+// withNamespaceRules appends the rules of all the namespaces to the rules of the default namespace.
+// It must be called with updateRuleMux locked.
+func withNamespaceRules(rules []*Rule) []*Rule {
+	if namespaceRules.Len() == 0 {
+		return rules
+	}
+	ret := make([]*Rule, 0, len(rules))
+	ret = append(ret, rules...)
+	namespaceRules.Each(func(rs interface{}) {
+		ret = append(ret, rs.([]*Rule)...)
+	})
+	return ret
+}
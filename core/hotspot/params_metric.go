@@ -19,3 +19,61 @@ type ParamsMetric struct {
 	// ConcurrencyCounter records the real-time concurrency.
 	ConcurrencyCounter cache.ConcurrentCounterCache
 }
+
+// Evictions returns the number of params evicted from the counters of the token statistic,
+// i.e. RuleTimeCounter and RuleTokenCounter.
+func (m *ParamsMetric) Evictions() uint64 {
+	var ret uint64
+	for _, c := range []cache.ConcurrentCounterCache{m.RuleTimeCounter, m.RuleTokenCounter} {
+		if c != nil {
+			ret += c.Evictions()
+		}
+	}
+	return ret
+}
+
+// MetricCacheStat is the statistic of the param metric caches of a rule. The growing Evictions with
+// Size reaching the capacity indicates the cache capacity is the bottleneck, where the ParamsMaxCapacity
+// should be enlarged, or another eviction policy should be chosen.
+type MetricCacheStat struct {
+	RuleID         string         `json:"ruleId"`
+	Resource       string         `json:"resource"`
+	EvictionPolicy EvictionPolicy `json:"evictionPolicy"`
+	// Size is the current number of params in the token statistic.
+	Size int `json:"size"`
+	// Evictions is the number of params evicted from the token statistic.
+	Evictions uint64 `json:"evictions"`
+	// ConcurrencyEvictions is the number of params evicted from the concurrency statistic.
+	ConcurrencyEvictions uint64 `json:"concurrencyEvictions"`
+}
+
+// GetMetricCacheStats returns the param metric cache statistics of all the rules.
+func GetMetricCacheStats() []MetricCacheStat {
+	tcMux.RLock()
+	defer tcMux.RUnlock()
+
+	ret := make([]MetricCacheStat, 0)
+	for _, tcs := range tcMap {
+		for _, tc := range tcs {
+			m := tc.BoundMetric()
+			if m == nil {
+				continue
+			}
+			r := tc.BoundRule()
+			stat := MetricCacheStat{
+				RuleID:         r.ID,
+				Resource:       r.Resource,
+				EvictionPolicy: r.EvictionPolicy,
+				Evictions:      m.Evictions(),
+			}
+			if m.RuleTokenCounter != nil {
+				stat.Size = m.RuleTokenCounter.Len()
+			}
+			if m.ConcurrencyCounter != nil {
+				stat.ConcurrencyEvictions = m.ConcurrencyCounter.Evictions()
+			}
+			ret = append(ret, stat)
+		}
+	}
+	return ret
+}
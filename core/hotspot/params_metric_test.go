@@ -0,0 +1,54 @@
+package hotspot
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/hotspot/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvictionPolicy(t *testing.T) {
+	defer func() {
+		_ = ClearRules()
+	}()
+	_, err := LoadRules([]*Rule{
+		{ID: "lru", Resource: "abc", MetricType: QPS, Threshold: 10, DurationInSec: 1, ParamsMaxCapacity: 10},
+		{ID: "lfu", Resource: "abc", MetricType: QPS, Threshold: 10, DurationInSec: 1, ParamsMaxCapacity: 10, EvictionPolicy: EvictionLFU},
+		{ID: "ttl", Resource: "abc", MetricType: QPS, Threshold: 10, DurationInSec: 1, ParamsMaxCapacity: 10, EvictionPolicy: EvictionTTL},
+	})
+	assert.Nil(t, err)
+
+	tcs := getTrafficControllersFor("abc")
+	assert.Equal(t, 3, len(tcs))
+	for _, tc := range tcs {
+		for i := 0; i < 15; i++ {
+			tc.PerformChecking(strconv.Itoa(i), 1)
+		}
+	}
+	stats := GetMetricCacheStats()
+	assert.Equal(t, 3, len(stats))
+	for _, s := range stats {
+		assert.Equal(t, 10, s.Size)
+		// Both the time counter and token counter evict 5 params.
+		assert.Equal(t, uint64(10), s.Evictions, s.RuleID)
+		assert.Equal(t, s.RuleID, strings.ToLower(s.EvictionPolicy.String()))
+	}
+
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc", EvictionPolicy: EvictionTTL + 1}))
+	assert.NotNil(t, IsValidRule(&Rule{Resource: "abc", EvictionPolicy: EvictionTTL, ParamsTTLInSec: -1}))
+}
+
+func TestParamsMetric_Evictions(t *testing.T) {
+	m := &ParamsMetric{
+		RuleTimeCounter:  cache.NewLRUCacheMap(1),
+		RuleTokenCounter: cache.NewLFUCacheMap(1),
+	}
+	for i := 0; i < 3; i++ {
+		v := int64(i)
+		m.RuleTimeCounter.Add(i, &v)
+		m.RuleTokenCounter.Add(i, &v)
+	}
+	assert.Equal(t, uint64(4), m.Evictions())
+}
@@ -49,6 +49,33 @@ func (t MetricType) String() string {
 	}
 }
 
+// EvictionPolicy represents the eviction policy of the param metric caches when the capacity is exceeded.
+type EvictionPolicy int8
+
+const (
+	// EvictionLRU evicts the least recently used param.
+	EvictionLRU EvictionPolicy = iota
+	// EvictionLFU evicts the least frequently used param, which keeps the hot params from being flushed out
+	// by the long-tail params.
+	EvictionLFU
+	// EvictionTTL expires the params not accessed for ParamsTTLInSec, and evicts the least recently used param
+	// if the capacity is still exceeded.
+	EvictionTTL
+)
+
+func (p EvictionPolicy) String() string {
+	switch p {
+	case EvictionLRU:
+		return "LRU"
+	case EvictionLFU:
+		return "LFU"
+	case EvictionTTL:
+		return "TTL"
+	default:
+		return strconv.Itoa(int(p))
+	}
+}
+
 // ParamKind represents the Param kind.
 type ParamKind int
 
@@ -107,8 +134,27 @@ type Rule struct {
 	DurationInSec int64 `json:"durationInSec"`
 	// ParamsMaxCapacity is the max capacity of cache statistic
 	ParamsMaxCapacity int64 `json:"paramsMaxCapacity"`
+	// EvictionPolicy is the eviction policy of cache statistic, LRU by default.
+	EvictionPolicy EvictionPolicy `json:"evictionPolicy,omitempty"`
+	// ParamsTTLInSec is the expiration of the params since the last access, only take effect in EvictionTTL policy.
+	// DurationInSec is used if not set, as the param not accessed in a statistic interval has all tokens refilled.
+	ParamsTTLInSec int64 `json:"paramsTtlInSec,omitempty"`
 	// SpecificItems indicates the special threshold for specific value
 	SpecificItems []SpecificValue `json:"specificItems"`
+	// TierThresholds indicates the thresholds per tier (e.g. "free": 10, "pro": 100) for the TieredParam args,
+	// e.g. the API keys resolved to the plans. The specific items of the key take precedence over the tier thresholds,
+	// and the Threshold takes effect for the tiers absent from it.
+	TierThresholds map[string]int64 `json:"tierThresholds,omitempty"`
+	// DeploymentLabel indicates that the rule takes effect only if current process has the label (see config.AppLabels),
+	// and overrides the rules without deployment label of the same resource. Empty means the rule always takes effect.
+	DeploymentLabel string `json:"deploymentLabel,omitempty"`
+	// ClusterMode indicates the rule is enforced globally by the token server (see cluster.SetTokenService),
+	// e.g. the per-user limits across all replicas of the gateway. The ID is required in cluster mode,
+	// which identifies the rule on the token server.
+	ClusterMode bool `json:"clusterMode,omitempty"`
+	// ClusterFallbackToLocal indicates whether to fall back to the local checking if the token server is
+	// unavailable. Otherwise the request passes.
+	ClusterFallbackToLocal bool `json:"clusterFallbackToLocal,omitempty"`
 }
 
 func (r *Rule) String() string {
@@ -121,12 +167,15 @@ func (r *Rule) ResourceName() string {
 
 // IsStatReusable checks whether current rule is "statistically" equal to the given rule.
 func (r *Rule) IsStatReusable(newRule *Rule) bool {
-	return r.Resource == newRule.Resource && r.ControlBehavior == newRule.ControlBehavior && r.ParamsMaxCapacity == newRule.ParamsMaxCapacity && r.DurationInSec == newRule.DurationInSec
+	return r.Resource == newRule.Resource && r.ControlBehavior == newRule.ControlBehavior && r.ParamsMaxCapacity == newRule.ParamsMaxCapacity && r.DurationInSec == newRule.DurationInSec &&
+		r.EvictionPolicy == newRule.EvictionPolicy && r.ParamsTTLInSec == newRule.ParamsTTLInSec
 }
 
 // Equals checks whether current rule is consistent with the given rule.
 func (r *Rule) Equals(newRule *Rule) bool {
-	baseCheck := r.Resource == newRule.Resource && r.MetricType == newRule.MetricType && r.ControlBehavior == newRule.ControlBehavior && r.ParamsMaxCapacity == newRule.ParamsMaxCapacity && r.ParamIndex == newRule.ParamIndex && r.Threshold == newRule.Threshold && r.DurationInSec == newRule.DurationInSec && reflect.DeepEqual(r.SpecificItems, newRule.SpecificItems)
+	baseCheck := r.Resource == newRule.Resource && r.MetricType == newRule.MetricType && r.ControlBehavior == newRule.ControlBehavior && r.ParamsMaxCapacity == newRule.ParamsMaxCapacity && r.ParamIndex == newRule.ParamIndex && r.Threshold == newRule.Threshold && r.DurationInSec == newRule.DurationInSec && reflect.DeepEqual(r.SpecificItems, newRule.SpecificItems) &&
+		reflect.DeepEqual(r.TierThresholds, newRule.TierThresholds) && r.EvictionPolicy == newRule.EvictionPolicy && r.ParamsTTLInSec == newRule.ParamsTTLInSec &&
+		r.ClusterMode == newRule.ClusterMode && r.ClusterFallbackToLocal == newRule.ClusterFallbackToLocal && (!r.ClusterMode || r.ID == newRule.ID)
 	if !baseCheck {
 		return false
 	}
@@ -3,7 +3,12 @@ package hotspot
 import (
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/alibaba/sentinel-golang/core/audit"
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/config"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
 	"github.com/alibaba/sentinel-golang/logging"
 	"github.com/alibaba/sentinel-golang/util"
 	"github.com/pkg/errors"
@@ -19,6 +24,8 @@ var (
 	tcGenFuncMap = make(map[ControlBehavior]TrafficControllerGenFunc)
 	tcMap        = make(trafficControllerMap)
 	tcMux        = new(sync.RWMutex)
+	// updateRuleMux serializes the rule updates of all the namespaces.
+	updateRuleMux = new(sync.Mutex)
 )
 
 func init() {
@@ -63,10 +70,35 @@ func getTrafficControllersFor(res string) []TrafficShapingController {
 // bool: indicates whether the internal map has been changed;
 // error: indicates whether occurs the error.
 func LoadRules(rules []*Rule) (bool, error) {
-	err := onRuleUpdate(rules)
+	defer selfmetric.RecordRuleUpdate("hotspot", time.Now())
+
+	updateRuleMux.Lock()
+	defer updateRuleMux.Unlock()
+
+	defaultNamespaceRules = rules
+	err := onRuleUpdate(withNamespaceRules(rules))
+	if err == nil {
+		base.SaveRulesToStore("hotspot", rules)
+	}
 	return true, err
 }
 
+// LoadRulesFromStore loads the hotspot rules saved in the RuleStore (see base.SetRuleStore),
+// e.g. the rules loaded by the other process sharing the store.
+// It returns false if there are no rules saved.
+func LoadRulesFromStore() (bool, error) {
+	rules := make([]*Rule, 0)
+	ok, err := base.LoadRulesFromStore("hotspot", &rules)
+	if !ok || err != nil {
+		return false, err
+	}
+	updateRuleMux.Lock()
+	defer updateRuleMux.Unlock()
+
+	defaultNamespaceRules = rules
+	return true, onRuleUpdate(withNamespaceRules(rules))
+}
+
 // GetRules returns all the rules based on copy.
 // It doesn't take effect for hotspot module if user changes the rule.
 // GetRules need to compete hotspot module's global lock and the high performance losses of copy,
@@ -99,8 +131,12 @@ func GetRulesOfResource(res string) []Rule {
 	return ret
 }
 
-// ClearRules clears all parameter flow rules.
+// ClearRules clears all parameter flow rules, including the rules of all the namespaces.
 func ClearRules() error {
+	updateRuleMux.Lock()
+	namespaceRules.Clear()
+	updateRuleMux.Unlock()
+
 	_, err := LoadRules(nil)
 	return err
 }
@@ -116,6 +152,7 @@ func onRuleUpdate(rules []*Rule) (err error) {
 		}
 	}()
 
+	rules = filterRulesByDeploymentLabel(rules)
 	newRuleMap := make(map[string][]*Rule)
 	for _, r := range rules {
 		if err := IsValidRule(r); err != nil {
@@ -137,7 +174,10 @@ func onRuleUpdate(rules []*Rule) (err error) {
 	}
 
 	start := util.CurrentTimeNano()
+	lockStart := time.Now()
 	tcMux.Lock()
+	selfmetric.RecordLockWait("hotspot.rules", lockStart)
+	oldRules := rulesFrom(tcMap)
 	defer func() {
 		tcMux.Unlock()
 		if r := recover(); r != nil {
@@ -145,6 +185,7 @@ func onRuleUpdate(rules []*Rule) (err error) {
 		}
 		logging.Debug("time statistic(ns) for updating hotspot rule", "timeCost", util.CurrentTimeNano()-start)
 		logRuleUpdate(m)
+		audit.RecordRuleChange("hotspot", ruleStringsOf(oldRules), ruleStringsOf(rulesFrom(m)))
 	}()
 
 	for res, resRules := range newRuleMap {
@@ -191,6 +232,11 @@ func onRuleUpdate(rules []*Rule) (err error) {
 		}
 	}
 	tcMap = m
+	resources := make([]string, 0, len(m))
+	for res := range m {
+		resources = append(resources, res)
+	}
+	base.SetRuleResourcesOf("hotspot", resources, false)
 
 	return nil
 }
@@ -204,6 +250,31 @@ func logRuleUpdate(m trafficControllerMap) {
 	}
 }
 
+// filterRulesByDeploymentLabel returns the rules that take effect in current process.
+// The rules with deployment label take effect only if current process has the label,
+// and override the rules without deployment label of the same resource.
+func filterRulesByDeploymentLabel(rules []*Rule) []*Rule {
+	labeledRes := make(map[string]bool)
+	for _, r := range rules {
+		if r != nil && len(r.DeploymentLabel) > 0 && config.HasAppLabel(r.DeploymentLabel) {
+			labeledRes[r.Resource] = true
+		}
+	}
+	ret := make([]*Rule, 0, len(rules))
+	for _, r := range rules {
+		if r == nil || len(r.DeploymentLabel) == 0 {
+			if r == nil || !labeledRes[r.Resource] {
+				ret = append(ret, r)
+			}
+			continue
+		}
+		if config.HasAppLabel(r.DeploymentLabel) {
+			ret = append(ret, r)
+		}
+	}
+	return ret
+}
+
 func rulesFrom(m trafficControllerMap) []*Rule {
 	rules := make([]*Rule, 0)
 	if len(m) == 0 {
@@ -222,6 +293,14 @@ func rulesFrom(m trafficControllerMap) []*Rule {
 	return rules
 }
 
+func ruleStringsOf(rules []*Rule) []string {
+	ret := make([]string, 0, len(rules))
+	for _, r := range rules {
+		ret = append(ret, r.String())
+	}
+	return ret
+}
+
 func calculateReuseIndexFor(r *Rule, oldResTcs []TrafficShapingController) (equalIdx, reuseStatIdx int) {
 	// the index of equivalent rule in old traffic shaping controller slice
 	equalIdx = -1
@@ -280,6 +359,25 @@ func IsValidRule(rule *Rule) error {
 	if rule.DurationInSec < 0 {
 		return errors.New("invalid duration")
 	}
+	if rule.EvictionPolicy < EvictionLRU || rule.EvictionPolicy > EvictionTTL {
+		return errors.New("invalid eviction policy")
+	}
+	if rule.ParamsTTLInSec < 0 {
+		return errors.New("invalid ParamsTTLInSec")
+	}
+	for tier, threshold := range rule.TierThresholds {
+		if threshold < 0 {
+			return errors.Errorf("negative threshold of tier: %s", tier)
+		}
+	}
+	if rule.ClusterMode {
+		if len(rule.ID) == 0 {
+			return errors.New("empty rule ID in cluster mode")
+		}
+		if rule.MetricType != QPS {
+			return errors.New("only QPS metric type is supported in cluster mode")
+		}
+	}
 	return checkControlBehaviorField(rule)
 }
 
@@ -326,3 +424,23 @@ func RemoveTrafficShapingGenerator(cb ControlBehavior) error {
 	delete(tcGenFuncMap, cb)
 	return nil
 }
+
+// NewTrafficShapingController creates the standalone TrafficShapingController of the rule, which is not managed
+// by the rule manager, e.g. for the modules converting their own rules into the hotspot rules (see gateway).
+// The controller is checked by PerformChecking with the arg directly, rather than the args of the entry.
+func NewTrafficShapingController(r *Rule) (TrafficShapingController, error) {
+	if err := IsValidRule(r); err != nil {
+		return nil, err
+	}
+	tcMux.RLock()
+	generator, supported := tcGenFuncMap[r.ControlBehavior]
+	tcMux.RUnlock()
+	if !supported {
+		return nil, errors.Errorf("unsupported control behavior: %v", r.ControlBehavior)
+	}
+	tc := generator(r, nil)
+	if tc == nil {
+		return nil, errors.New("bad generated traffic controller")
+	}
+	return tc, nil
+}
@@ -302,3 +302,23 @@ func Test_onRuleUpdate(t *testing.T) {
 
 	tcMap = make(trafficControllerMap)
 }
+
+func TestNewTrafficShapingController(t *testing.T) {
+	tc, err := NewTrafficShapingController(&Rule{
+		Resource:          "abc",
+		MetricType:        QPS,
+		ControlBehavior:   Reject,
+		Threshold:         2,
+		DurationInSec:     1,
+		ParamsMaxCapacity: 100,
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, tc.PerformChecking("a", 1))
+	assert.Nil(t, tc.PerformChecking("a", 1))
+	assert.True(t, tc.PerformChecking("a", 1).IsBlocked())
+	assert.Nil(t, tc.PerformChecking("b", 1))
+	assert.Equal(t, 0, len(getTrafficControllersFor("abc")), "the standalone controller should not be managed")
+
+	_, err = NewTrafficShapingController(&Rule{Resource: "abc", MetricType: QPS, Threshold: 2, ControlBehavior: ControlBehavior(10)})
+	assert.NotNil(t, err)
+}
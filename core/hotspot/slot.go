@@ -12,6 +12,11 @@ import (
 type Slot struct {
 }
 
+// RulesIndexed implements base.IndexedRuleCheckSlot, as all the rules are registered to the rule resource index.
+func (s *Slot) RulesIndexed() bool {
+	return true
+}
+
 // matchArg matches the arg from args based on TrafficShapingController
 // return nil if match failed.
 func matchArg(tc TrafficShapingController, args []interface{}) interface{} {
@@ -91,6 +96,9 @@ func (s *Slot) Check(ctx *base.EntryContext) *base.TokenResult {
 	acquire := int64(ctx.Input.AcquireCount)
 
 	result := ctx.RuleCheckResult
+	if !Enabled() {
+		return result
+	}
 	tcs := getTrafficControllersFor(res)
 	if len(tcs) == 0 {
 		return result
@@ -120,6 +128,9 @@ func (s *Slot) Check(ctx *base.EntryContext) *base.TokenResult {
 }
 
 func canPassCheck(tc TrafficShapingController, arg interface{}, acquire int64) *base.TokenResult {
+	if tc.BoundRule().ClusterMode {
+		return canPassClusterCheck(tc, arg, acquire)
+	}
 	return canPassLocalCheck(tc, arg, acquire)
 }
 
@@ -0,0 +1,26 @@
+package hotspot
+
+import (
+	"sync/atomic"
+
+	"github.com/alibaba/sentinel-golang/logging"
+)
+
+// disabled indicates whether the hotspot param flow control is disabled at runtime, see SetEnabled.
+var disabled int32
+
+// SetEnabled enables or disables the hotspot param flow control at runtime, which is enabled by default.
+// The hotspot slot is short-circuited while disabled, but the rules are kept.
+func SetEnabled(e bool) {
+	if e {
+		atomic.StoreInt32(&disabled, 0)
+	} else {
+		atomic.StoreInt32(&disabled, 1)
+	}
+	logging.Info("[HotSpot] Hotspot param flow control was toggled", "enabled", e)
+}
+
+// Enabled returns whether the hotspot param flow control is enabled.
+func Enabled() bool {
+	return atomic.LoadInt32(&disabled) == 0
+}
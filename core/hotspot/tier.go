@@ -0,0 +1,14 @@
+package hotspot
+
+// TieredParam is the param of the callers with plans (e.g. the API key resolved to the plan "pro"),
+// whose threshold is the threshold of its tier in Rule.TierThresholds. The params are counted per key,
+// e.g. the callers of the plan "free" are limited to 10 QPS respectively by the tier threshold "free": 10.
+type TieredParam struct {
+	Key  string
+	Tier string
+}
+
+// String returns the key, which is used as the param of the cluster checking and the block message.
+func (p TieredParam) String() string {
+	return p.Key
+}
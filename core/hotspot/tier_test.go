@@ -0,0 +1,44 @@
+package hotspot
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTieredParam(t *testing.T) {
+	r := &Rule{
+		Resource:        "abc",
+		MetricType:      QPS,
+		ControlBehavior: Reject,
+		Threshold:       1,
+		DurationInSec:   1,
+		SpecificItems:   []SpecificValue{{ValKind: KindString, ValStr: "vip-key", Threshold: 5}},
+		TierThresholds:  map[string]int64{"free": 2, "pro": 3},
+	}
+	assert.NoError(t, IsValidRule(r))
+	tc := tcGenFuncMap[Reject](r, nil)
+
+	passed := func(arg interface{}, times int) int {
+		ret := 0
+		for i := 0; i < times; i++ {
+			if res := tc.PerformChecking(arg, 1); res == nil || res.Status() != base.ResultStatusBlocked {
+				ret++
+			}
+		}
+		return ret
+	}
+	assert.Equal(t, 2, passed(TieredParam{Key: "a", Tier: "free"}, 10))
+	// the keys are counted respectively
+	assert.Equal(t, 2, passed(TieredParam{Key: "b", Tier: "free"}, 10))
+	assert.Equal(t, 3, passed(TieredParam{Key: "c", Tier: "pro"}, 10))
+	// the specific item of the key takes precedence
+	assert.Equal(t, 5, passed(TieredParam{Key: "vip-key", Tier: "free"}, 10))
+	// the rule threshold takes effect for the unknown tiers
+	assert.Equal(t, 1, passed(TieredParam{Key: "d", Tier: "unknown"}, 10))
+	assert.Equal(t, "a", TieredParam{Key: "a", Tier: "free"}.String())
+
+	r.TierThresholds["free"] = -1
+	assert.Error(t, IsValidRule(r))
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 	"runtime"
 	"sync/atomic"
+	"time"
 
 	"github.com/alibaba/sentinel-golang/core/base"
 	"github.com/alibaba/sentinel-golang/core/hotspot/cache"
@@ -30,7 +31,9 @@ type baseTrafficShapingController struct {
 	paramIndex    int
 	threshold     float64
 	specificItems map[interface{}]int64
-	durationInSec int64
+	// tierThresholds are the thresholds per tier of the TieredParam args
+	tierThresholds map[string]int64
+	durationInSec  int64
 
 	metric *ParamsMetric
 }
@@ -38,14 +41,15 @@ type baseTrafficShapingController struct {
 func newBaseTrafficShapingControllerWithMetric(r *Rule, metric *ParamsMetric) *baseTrafficShapingController {
 	specificItems := parseSpecificItems(r.SpecificItems)
 	return &baseTrafficShapingController{
-		r:             r,
-		res:           r.Resource,
-		metricType:    r.MetricType,
-		paramIndex:    r.ParamIndex,
-		threshold:     r.Threshold,
-		specificItems: specificItems,
-		durationInSec: r.DurationInSec,
-		metric:        metric,
+		r:              r,
+		res:            r.Resource,
+		metricType:     r.MetricType,
+		paramIndex:     r.ParamIndex,
+		threshold:      r.Threshold,
+		specificItems:  specificItems,
+		tierThresholds: r.TierThresholds,
+		durationInSec:  r.DurationInSec,
+		metric:         metric,
 	}
 }
 
@@ -62,19 +66,37 @@ func newBaseTrafficShapingController(r *Rule) *baseTrafficShapingController {
 		size = ParamsMaxCapacity
 	}
 	metric := &ParamsMetric{
-		RuleTimeCounter:    cache.NewLRUCacheMap(size),
-		RuleTokenCounter:   cache.NewLRUCacheMap(size),
+		RuleTimeCounter:    newParamsCacheMap(r, size),
+		RuleTokenCounter:   newParamsCacheMap(r, size),
 		ConcurrencyCounter: cache.NewLRUCacheMap(ConcurrencyMaxCount),
 	}
 	return newBaseTrafficShapingControllerWithMetric(r, metric)
 }
 
+// newParamsCacheMap creates the cache of the param counters with the eviction policy of the rule.
+func newParamsCacheMap(r *Rule, size int) cache.ConcurrentCounterCache {
+	switch r.EvictionPolicy {
+	case EvictionLFU:
+		return cache.NewLFUCacheMap(size)
+	case EvictionTTL:
+		ttlInSec := r.ParamsTTLInSec
+		if ttlInSec <= 0 {
+			ttlInSec = r.DurationInSec
+		}
+		if ttlInSec <= 0 {
+			ttlInSec = 1
+		}
+		return cache.NewTTLCacheMap(size, time.Duration(ttlInSec)*time.Second)
+	default:
+		return cache.NewLRUCacheMap(size)
+	}
+}
+
 func (c *baseTrafficShapingController) BoundMetric() *ParamsMetric {
 	return c.metric
 }
 
 func (c *baseTrafficShapingController) performCheckingForConcurrencyMetric(arg interface{}) *base.TokenResult {
-	specificItem := c.specificItems
 	initConcurrency := new(int64)
 	*initConcurrency = 0
 	concurrencyPtr := c.metric.ConcurrencyCounter.AddIfAbsent(arg, initConcurrency)
@@ -84,7 +106,7 @@ func (c *baseTrafficShapingController) performCheckingForConcurrencyMetric(arg i
 	}
 	concurrency := atomic.LoadInt64(concurrencyPtr)
 	concurrency++
-	if specificConcurrency, existed := specificItem[arg]; existed {
+	if specificConcurrency, existed := c.specificThresholdOf(arg); existed {
 		if concurrency <= specificConcurrency {
 			return nil
 		}
@@ -111,6 +133,22 @@ type throttlingTrafficShapingController struct {
 	maxQueueingTimeMs int64
 }
 
+// specificThresholdOf returns the threshold of the specific item (or the tier of the TieredParam) of the arg.
+func (c *baseTrafficShapingController) specificThresholdOf(arg interface{}) (int64, bool) {
+	if val, existed := c.specificItems[arg]; existed {
+		return val, true
+	}
+	p, ok := arg.(TieredParam)
+	if !ok {
+		return 0, false
+	}
+	if val, existed := c.specificItems[p.Key]; existed {
+		return val, true
+	}
+	val, existed := c.tierThresholds[p.Tier]
+	return val, existed
+}
+
 func (c *baseTrafficShapingController) BoundRule() *Rule {
 	return c.r
 }
@@ -139,7 +177,7 @@ func (c *rejectTrafficShapingController) PerformChecking(arg interface{}, acquir
 
 	// calculate available token
 	tokenCount := int64(c.threshold)
-	val, existed := c.specificItems[arg]
+	val, existed := c.specificThresholdOf(arg)
 	if existed {
 		tokenCount = val
 	}
@@ -234,7 +272,7 @@ func (c *throttlingTrafficShapingController) PerformChecking(arg interface{}, ac
 
 	// calculate available token
 	tokenCount := int64(c.threshold)
-	val, existed := c.specificItems[arg]
+	val, existed := c.specificThresholdOf(arg)
 	if existed {
 		tokenCount = val
 	}
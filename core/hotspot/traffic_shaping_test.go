@@ -62,6 +62,11 @@ func (c *counterCacheMock) Purge() {
 	return
 }
 
+func (c *counterCacheMock) Evictions() uint64 {
+	arg := c.Called()
+	return arg.Get(0).(uint64)
+}
+
 func Test_baseTrafficShapingController_performCheckingForConcurrencyMetric(t *testing.T) {
 	t.Run("Test_baseTrafficShapingController_performCheckingForConcurrencyMetric", func(t *testing.T) {
 		goCounter := &counterCacheMock{}
@@ -29,6 +29,9 @@ type Rule struct {
 	Resource   string     `json:"resource"`
 	MetricType MetricType `json:"metricType"`
 	Threshold  uint32     `json:"threshold"`
+	// DeploymentLabel indicates that the rule takes effect only if current process has the label (see config.AppLabels),
+	// and overrides the rules without deployment label of the same resource. Empty means the rule always takes effect.
+	DeploymentLabel string `json:"deploymentLabel,omitempty"`
 }
 
 func (r *Rule) String() string {
@@ -2,7 +2,12 @@ package isolation
 
 import (
 	"sync"
+	"time"
 
+	"github.com/alibaba/sentinel-golang/core/audit"
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/config"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
 	"github.com/alibaba/sentinel-golang/logging"
 	"github.com/alibaba/sentinel-golang/util"
 	"github.com/pkg/errors"
@@ -11,15 +16,27 @@ import (
 var (
 	ruleMap = make(map[string][]*Rule)
 	rwMux   = &sync.RWMutex{}
+	// updateRuleMux serializes the rule updates of all the namespaces.
+	updateRuleMux = new(sync.Mutex)
 )
 
 // LoadRules loads the given isolation rules to the rule manager, while all previous rules will be replaced.
 func LoadRules(rules []*Rule) (updated bool, err error) {
+	defer selfmetric.RecordRuleUpdate("isolation", time.Now())
+
+	updateRuleMux.Lock()
+	defer updateRuleMux.Unlock()
+
+	defaultNamespaceRules = rules
+	return onRuleUpdate(withNamespaceRules(rules))
+}
+
+func onRuleUpdate(rules []*Rule) (updated bool, err error) {
 	updated = true
 	err = nil
 
 	m := make(map[string][]*Rule)
-	for _, r := range rules {
+	for _, r := range filterRulesByDeploymentLabel(rules) {
 		if e := IsValid(r); e != nil {
 			logging.Error(e, "invalid isolation rule.", "rule", r)
 			continue
@@ -33,17 +50,28 @@ func LoadRules(rules []*Rule) (updated bool, err error) {
 
 	start := util.CurrentTimeNano()
 	rwMux.Lock()
+	oldRules := rulesFrom(ruleMap)
 	defer func() {
 		rwMux.Unlock()
 		logging.Debug("time statistic(ns) for updating isolation rule", "timeCost", util.CurrentTimeNano()-start)
 		logRuleUpdate(m)
+		audit.RecordRuleChange("isolation", ruleStringsOf(oldRules), ruleStringsOf(rulesFrom(m)))
 	}()
 	ruleMap = m
+	resources := make([]string, 0, len(m))
+	for res := range m {
+		resources = append(resources, res)
+	}
+	base.SetRuleResourcesOf("isolation", resources, false)
 	return
 }
 
-// ClearRules clears all the rules in isolation module.
+// ClearRules clears all the rules in isolation module, including the rules of all the namespaces.
 func ClearRules() error {
+	updateRuleMux.Lock()
+	namespaceRules.Clear()
+	updateRuleMux.Unlock()
+
 	_, err := LoadRules(nil)
 	return err
 }
@@ -96,6 +124,31 @@ func getRulesOfResource(res string) []*Rule {
 	return ret
 }
 
+// filterRulesByDeploymentLabel returns the rules that take effect in current process.
+// The rules with deployment label take effect only if current process has the label,
+// and override the rules without deployment label of the same resource.
+func filterRulesByDeploymentLabel(rules []*Rule) []*Rule {
+	labeledRes := make(map[string]bool)
+	for _, r := range rules {
+		if r != nil && len(r.DeploymentLabel) > 0 && config.HasAppLabel(r.DeploymentLabel) {
+			labeledRes[r.Resource] = true
+		}
+	}
+	ret := make([]*Rule, 0, len(rules))
+	for _, r := range rules {
+		if r == nil || len(r.DeploymentLabel) == 0 {
+			if r == nil || !labeledRes[r.Resource] {
+				ret = append(ret, r)
+			}
+			continue
+		}
+		if config.HasAppLabel(r.DeploymentLabel) {
+			ret = append(ret, r)
+		}
+	}
+	return ret
+}
+
 func rulesFrom(m map[string][]*Rule) []*Rule {
 	rules := make([]*Rule, 0)
 	if len(m) == 0 {
@@ -111,6 +164,14 @@ func rulesFrom(m map[string][]*Rule) []*Rule {
 	return rules
 }
 
+func ruleStringsOf(rules []*Rule) []string {
+	ret := make([]string, 0, len(rules))
+	for _, r := range rules {
+		ret = append(ret, r.String())
+	}
+	return ret
+}
+
 func logRuleUpdate(m map[string][]*Rule) {
 	rs := rulesFrom(m)
 	if len(rs) == 0 {
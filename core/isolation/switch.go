@@ -0,0 +1,26 @@
+package isolation
+
+import (
+	"sync/atomic"
+
+	"github.com/alibaba/sentinel-golang/logging"
+)
+
+// disabled indicates whether the concurrency isolation is disabled at runtime, see SetEnabled.
+var disabled int32
+
+// SetEnabled enables or disables the concurrency isolation at runtime, which is enabled by default.
+// The isolation slot is short-circuited while disabled, but the rules are kept.
+func SetEnabled(e bool) {
+	if e {
+		atomic.StoreInt32(&disabled, 0)
+	} else {
+		atomic.StoreInt32(&disabled, 1)
+	}
+	logging.Info("[Isolation] Concurrency isolation was toggled", "enabled", e)
+}
+
+// Enabled returns whether the concurrency isolation is enabled.
+func Enabled() bool {
+	return atomic.LoadInt32(&disabled) == 0
+}
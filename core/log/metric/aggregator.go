@@ -7,6 +7,7 @@ import (
 
 	"github.com/alibaba/sentinel-golang/core/base"
 	"github.com/alibaba/sentinel-golang/core/config"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
 	"github.com/alibaba/sentinel-golang/core/stat"
 	"github.com/alibaba/sentinel-golang/logging"
 	"github.com/alibaba/sentinel-golang/util"
@@ -26,6 +27,8 @@ var (
 
 	metricWriter MetricLogWriter
 	initOnce     sync.Once
+
+	metricLogDrops = selfmetric.NewDropCounter("metricLog")
 )
 
 func InitTask() (err error) {
@@ -35,11 +38,17 @@ func InitTask() (err error) {
 			return
 		}
 
-		metricWriter, err = NewDefaultMetricLogWriter(config.MetricLogSingleFileMaxSize(), config.MetricLogMaxFileAmount())
+		if output := config.MetricLogOutput(); output != nil {
+			metricWriter, err = NewOutputMetricLogWriter(output)
+		} else {
+			metricWriter, err = NewDefaultMetricLogWriter(config.MetricLogSingleFileMaxSize(), config.MetricLogMaxFileAmount())
+		}
 		if err != nil {
 			logging.Error(err, "Failed to initialize the MetricLogWriter")
 			return
 		}
+		// The RT histogram is only written in the JSON format.
+		setRtHistogramEnabled(config.MetricLogFormat() == config.MetricLogFormatJSON)
 
 		// Schedule the log flushing task
 		go util.RunWithRecover(writeTaskLoop)
@@ -98,12 +107,20 @@ func doAggregate() {
 	}
 	// Aggregate for inbound entrance node.
 	aggregateIntoMap(maps, currentMetricItems(stat.InboundNode(), curTime), stat.InboundNode())
+	if config.RuntimeMetricEnabled() {
+		aggregateRuntimeMetrics(maps, curTime)
+	}
 
 	// Update current last fetch timestamp.
 	lastFetchTime = int64(curTime)
 
 	if len(maps) > 0 {
-		writeChan <- maps
+		// Drop the metrics rather than piling up the aggregating task if the writer is too slow.
+		select {
+		case writeChan <- maps:
+		default:
+			metricLogDrops.Inc()
+		}
 	}
 }
 
@@ -111,6 +128,7 @@ func aggregateIntoMap(mm metricTimeMap, metrics map[uint64]*base.MetricItem, nod
 	for t, item := range metrics {
 		item.Resource = node.ResourceName()
 		item.Classification = int32(node.ResourceType())
+		item.RtHistogram = rtHistogramOf(item.Resource, t)
 		items, exists := mm[t]
 		if exists {
 			mm[t] = append(items, item)
@@ -120,6 +138,13 @@ func aggregateIntoMap(mm metricTimeMap, metrics map[uint64]*base.MetricItem, nod
 	}
 }
 
+// aggregateRuntimeMetrics appends the sampled runtime metrics to the last complete second before curTime,
+// i.e. the same time bucket as the latest resource metrics.
+func aggregateRuntimeMetrics(mm metricTimeMap, curTime uint64) {
+	ts := curTime - 1000
+	mm[ts] = append(mm[ts], stat.RuntimeMetricItems(ts)...)
+}
+
 func isActiveMetricItem(item *base.MetricItem) bool {
 	return item.PassQps > 0 || item.BlockQps > 0 || item.CompleteQps > 0 || item.ErrorQps > 0 ||
 		item.AvgRt > 0 || item.Concurrency > 0
@@ -252,3 +252,18 @@ func Test_currentMetricItems(t *testing.T) {
 		})
 	}
 }
+
+func Test_aggregateRuntimeMetrics(t *testing.T) {
+	mm := make(metricTimeMap)
+	mm[1581959013000] = []*base.MetricItem{{Resource: defaultTestResourceName, Timestamp: 1581959013000, PassQps: 1}}
+	aggregateRuntimeMetrics(mm, 1581959014000)
+
+	assert.Equal(t, 1, len(mm))
+	items := mm[1581959013000]
+	assert.Equal(t, 4, len(items))
+	assert.Equal(t, defaultTestResourceName, items[0].Resource)
+	for _, item := range items[1:] {
+		assert.Equal(t, base.MetricItemClassificationRuntime, item.Classification)
+		assert.Equal(t, uint64(1581959013000), item.Timestamp)
+	}
+}
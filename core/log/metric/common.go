@@ -41,8 +41,17 @@ type MetricSearcher interface {
 
 // Generate the metric file name from the service name.
 func FormMetricFileName(serviceName string, withPid bool) string {
+	return FormMetricFileNameOfInstance(serviceName, "", withPid)
+}
+
+// FormMetricFileNameOfInstance generates the metric file name from the service name and the instance ID,
+// e.g. "app-i1-metrics.log" of the service "app" and the instance "i1".
+func FormMetricFileNameOfInstance(serviceName, instanceId string, withPid bool) string {
 	dot := "."
 	separator := "-"
+	if len(instanceId) > 0 {
+		serviceName = serviceName + separator + instanceId
+	}
 	if strings.Contains(serviceName, dot) {
 		serviceName = strings.ReplaceAll(serviceName, dot, separator)
 	}
@@ -25,6 +25,12 @@ func TestFormMetricFileName(t *testing.T) {
 	}
 }
 
+func TestFormMetricFileNameOfInstance(t *testing.T) {
+	assert.Equal(t, "foo-test-metrics.log", FormMetricFileNameOfInstance("foo.test", "", false))
+	assert.Equal(t, "foo-test-i1-metrics.log", FormMetricFileNameOfInstance("foo.test", "i1", false))
+	assert.Equal(t, "foo-test-10-0-0-1-metrics.log", FormMetricFileNameOfInstance("foo-test", "10.0.0.1", false))
+}
+
 func Test_filenameMatches(t *testing.T) {
 	type args struct {
 		filename     string
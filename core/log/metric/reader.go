@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/alibaba/sentinel-golang/core/base"
 	"github.com/alibaba/sentinel-golang/logging"
@@ -104,7 +105,7 @@ func (r *defaultMetricLogReader) readMetricsInOneFile(filename string, offset ui
 			}
 			return nil, false, errors.Wrap(err, "error when reading lines from file")
 		}
-		item, err := base.MetricItemFromFatString(line)
+		item, err := metricItemFromLine(line)
 		if err != nil {
 			logging.Error(err, "Failed to convert MetricItem to string")
 			continue
@@ -138,7 +139,7 @@ func (r *defaultMetricLogReader) readMetricsInOneFileByEndTime(filename string,
 			}
 			return nil, false, errors.Wrap(err, "error when reading lines from file")
 		}
-		item, err := base.MetricItemFromFatString(line)
+		item, err := metricItemFromLine(line)
 		if err != nil {
 			logging.Error(err, "Invalid line of metric file", "fileLine", line)
 			continue
@@ -200,3 +201,11 @@ func openFileAndSeekTo(filename string, offset uint64) (*os.File, error) {
 func newDefaultMetricLogReader() MetricLogReader {
 	return &defaultMetricLogReader{}
 }
+
+// metricItemFromLine parses the metric log line of either the text format or the JSON format.
+func metricItemFromLine(line string) (*base.MetricItem, error) {
+	if strings.HasPrefix(line, "{") {
+		return base.MetricItemFromJSONString(line)
+	}
+	return base.MetricItemFromFatString(line)
+}
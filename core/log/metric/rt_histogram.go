@@ -0,0 +1,135 @@
+package metric
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	sbase "github.com/alibaba/sentinel-golang/core/stat/base"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+const (
+	// The RT histograms of the last few seconds are retained for the metric log aggregation,
+	// with one bucket per second.
+	rtHistogramSampleCount = 5
+	rtHistogramIntervalMs  = 5000
+)
+
+var (
+	rtHistogramEnabled int32
+	rtHistograms       sync.Map
+)
+
+type rtHistogramBucket struct {
+	counts []int64
+}
+
+func newRtHistogramBucket() *rtHistogramBucket {
+	return &rtHistogramBucket{counts: make([]int64, len(base.MetricItemRtHistogramBoundsMs)+1)}
+}
+
+func (b *rtHistogramBucket) add(rt uint64) {
+	idx := len(base.MetricItemRtHistogramBoundsMs)
+	for i, bound := range base.MetricItemRtHistogramBoundsMs {
+		if rt <= bound {
+			idx = i
+			break
+		}
+	}
+	atomic.AddInt64(&b.counts[idx], 1)
+}
+
+// rtHistogram records the per-second RT histograms of a resource.
+type rtHistogram struct {
+	data *sbase.LeapArray
+}
+
+func newRtHistogram() (*rtHistogram, error) {
+	h := &rtHistogram{}
+	data, err := sbase.NewLeapArray(rtHistogramSampleCount, rtHistogramIntervalMs, h)
+	if err != nil {
+		return nil, err
+	}
+	h.data = data
+	return h, nil
+}
+
+func (h *rtHistogram) NewEmptyBucket() interface{} {
+	return newRtHistogramBucket()
+}
+
+func (h *rtHistogram) ResetBucketTo(bw *sbase.BucketWrap, startTime uint64) *sbase.BucketWrap {
+	atomic.StoreUint64(&bw.BucketStart, startTime)
+	bw.Value.Store(newRtHistogramBucket())
+	return bw
+}
+
+func (h *rtHistogram) add(rt uint64) {
+	bw, err := h.data.CurrentBucket(h)
+	if err != nil || bw == nil {
+		return
+	}
+	if b, ok := bw.Value.Load().(*rtHistogramBucket); ok {
+		b.add(rt)
+	}
+}
+
+// countsOf returns the histogram of the second starting at ts, nil if absent.
+func (h *rtHistogram) countsOf(ts uint64) []uint64 {
+	bws := h.data.ValuesConditional(util.CurrentTimeMillis(), func(start uint64) bool {
+		return start == ts
+	})
+	for _, bw := range bws {
+		b, ok := bw.Value.Load().(*rtHistogramBucket)
+		if !ok {
+			continue
+		}
+		ret := make([]uint64, len(b.counts))
+		for i := range b.counts {
+			ret[i] = uint64(atomic.LoadInt64(&b.counts[i]))
+		}
+		return ret
+	}
+	return nil
+}
+
+func setRtHistogramEnabled(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&rtHistogramEnabled, 1)
+	} else {
+		atomic.StoreInt32(&rtHistogramEnabled, 0)
+	}
+}
+
+// IsRtHistogramEnabled indicates whether the RT histogram is recorded, i.e. the metric log is of the JSON format.
+func IsRtHistogramEnabled() bool {
+	return atomic.LoadInt32(&rtHistogramEnabled) == 1
+}
+
+// RecordRt records the RT of a completed request of the resource to the RT histogram, which is written to
+// the metric log of the JSON format. It's a no-op unless the metric log is of the JSON format.
+func RecordRt(resource string, rt uint64) {
+	if !IsRtHistogramEnabled() {
+		return
+	}
+	h, ok := rtHistograms.Load(resource)
+	if !ok {
+		nh, err := newRtHistogram()
+		if err != nil {
+			logging.Error(err, "Failed to create RT histogram", "resource", resource)
+			return
+		}
+		h, _ = rtHistograms.LoadOrStore(resource, nh)
+	}
+	h.(*rtHistogram).add(rt)
+}
+
+func rtHistogramOf(resource string, ts uint64) []uint64 {
+	h, ok := rtHistograms.Load(resource)
+	if !ok {
+		return nil
+	}
+	return h.(*rtHistogram).countsOf(ts)
+}
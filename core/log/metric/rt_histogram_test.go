@@ -0,0 +1,40 @@
+package metric
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordRt(t *testing.T) {
+	RecordRt("abc-rt", 3)
+	assert.Nil(t, rtHistogramOf("abc-rt", 0))
+
+	setRtHistogramEnabled(true)
+	defer setRtHistogramEnabled(false)
+
+	now := util.CurrentTimeMillis()
+	RecordRt("abc-rt", 0)
+	RecordRt("abc-rt", 3)
+	RecordRt("abc-rt", 5)
+	RecordRt("abc-rt", 100000)
+	counts := rtHistogramOf("abc-rt", now-now%1000)
+	if util.CurrentTimeMillis()/1000 != now/1000 {
+		// The second rolled over during recording.
+		return
+	}
+	assert.Equal(t, []uint64{1, 2, 0, 0, 0, 0, 0, 0, 0, 0, 1}, counts)
+}
+
+func TestMetricItemFromLine(t *testing.T) {
+	item, err := metricItemFromLine("1564382218000|2019-07-29 14:36:58|/foo/*|4|9|3|0|25|0|2|1")
+	assert.NoError(t, err)
+	assert.Equal(t, "/foo/*", item.Resource)
+
+	item, err = metricItemFromLine(`{"timestamp":1564382218000,"resource":"/foo/*","passQps":4,"rt":{"avg":25,"min":1}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "/foo/*", item.Resource)
+	assert.Equal(t, uint64(4), item.PassQps)
+	assert.Equal(t, uint64(25), item.AvgRt)
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -33,6 +34,11 @@ type DefaultMetricLogWriter struct {
 	metricOut *bufio.Writer
 	idxOut    *bufio.Writer
 
+	// format is the format of the metric log lines, see config.MetricLogFormatText and config.MetricLogFormatJSON.
+	format string
+	// rtUnit is the finest unit of the RT, see config.MetricLogRtUnitMs and config.MetricLogRtUnitUs.
+	rtUnit string
+
 	mux *sync.RWMutex
 }
 
@@ -102,8 +108,26 @@ func (d *DefaultMetricLogWriter) Close() error {
 }
 
 func (d *DefaultMetricLogWriter) writeItemsAndFlush(items []*base.MetricItem) error {
+	if err := writeItems(d.metricOut, items, d.format, d.rtUnit); err != nil {
+		return nil
+	}
+	return d.metricOut.Flush()
+}
+
+// writeItems writes the metric items in the format, one item per line.
+func writeItems(out io.Writer, items []*base.MetricItem, format, rtUnit string) error {
 	for _, item := range items {
-		s, err := item.ToFatString()
+		var (
+			s   string
+			err error
+		)
+		if format == config.MetricLogFormatJSON {
+			s, err = item.ToJSONString()
+		} else if rtUnit == config.MetricLogRtUnitUs {
+			s, err = item.ToFatStringWithRtMicros()
+		} else {
+			s, err = item.ToFatString()
+		}
 		if err != nil {
 			logging.Warn("Failed to convert MetricItem to string", "resourceName", item.Resource, "err", err)
 			continue
@@ -111,12 +135,11 @@ func (d *DefaultMetricLogWriter) writeItemsAndFlush(items []*base.MetricItem) er
 
 		// Append the LF line separator.
 		bs := []byte(s + "\n")
-		_, err = d.metricOut.Write(bs)
-		if err != nil {
-			return nil
+		if _, err = out.Write(bs); err != nil {
+			return err
 		}
 	}
-	return d.metricOut.Flush()
+	return nil
 }
 
 func (d *DefaultMetricLogWriter) rollFileIfSizeExceeded(time uint64) error {
@@ -287,7 +310,7 @@ func NewDefaultMetricLogWriterOfApp(maxSize uint64, maxFileAmount uint32, appNam
 		logDir = config.GetDefaultLogDir()
 	}
 	baseDir := util.AddPathSeparatorIfAbsent(logDir)
-	baseFilename := FormMetricFileName(appName, config.LogUsePid())
+	baseFilename := FormMetricFileNameOfInstance(appName, config.LogInstanceId(), config.LogUsePid())
 
 	writer := &DefaultMetricLogWriter{
 		maxSingleSize:     maxSize,
@@ -297,7 +320,53 @@ func NewDefaultMetricLogWriterOfApp(maxSize uint64, maxFileAmount uint32, appNam
 		baseDir:           baseDir,
 		baseFilename:      baseFilename,
 		mux:               new(sync.RWMutex),
+		format:            config.MetricLogFormat(),
+		rtUnit:            config.MetricLogRtUnit(),
 	}
 	err := writer.initialize()
 	return writer, err
 }
+
+// OutputMetricLogWriter writes the metric items to the io.Writer (e.g. the stdout or the log collector)
+// rather than the metric log files, see config.MetricLogConfig.Output.
+type OutputMetricLogWriter struct {
+	out    io.Writer
+	format string
+	rtUnit string
+
+	mux *sync.Mutex
+}
+
+func (w *OutputMetricLogWriter) Write(ts uint64, items []*base.MetricItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	if ts <= 0 {
+		return errors.New(fmt.Sprintf("%s: %d", "Invalid timestamp: ", ts))
+	}
+	// Update all metric items to the given timestamp.
+	for _, item := range items {
+		item.Timestamp = ts
+	}
+
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	if err := writeItems(w.out, items, w.format, w.rtUnit); err != nil {
+		return errors.Wrap(err, "failed to write metric items")
+	}
+	return nil
+}
+
+// NewOutputMetricLogWriter creates the MetricLogWriter writing to the io.Writer, in the configured format.
+func NewOutputMetricLogWriter(out io.Writer) (MetricLogWriter, error) {
+	if out == nil {
+		return nil, errors.New("nil output")
+	}
+	return &OutputMetricLogWriter{
+		out:    out,
+		format: config.MetricLogFormat(),
+		rtUnit: config.MetricLogRtUnit(),
+		mux:    new(sync.Mutex),
+	}, nil
+}
@@ -0,0 +1,29 @@
+package metric
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputMetricLogWriter_Write(t *testing.T) {
+	_, err := NewOutputMetricLogWriter(nil)
+	assert.NotNil(t, err)
+
+	buf := &bytes.Buffer{}
+	w, err := NewOutputMetricLogWriter(buf)
+	assert.Nil(t, err)
+	assert.Nil(t, w.Write(1000, nil))
+	assert.NotNil(t, w.Write(0, []*base.MetricItem{{Resource: "abc"}}))
+
+	err = w.Write(2000, []*base.MetricItem{{Resource: "abc", PassQps: 1}, {Resource: "def", BlockQps: 2}})
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 2, len(lines))
+	assert.True(t, strings.HasPrefix(lines[0], "2000|"))
+	assert.Contains(t, lines[0], "|abc|1|")
+	assert.Contains(t, lines[1], "|def|")
+}
@@ -2,6 +2,8 @@ package log
 
 import (
 	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/log/metric"
+	"github.com/alibaba/sentinel-golang/core/stat"
 )
 
 type Slot struct {
@@ -15,6 +17,14 @@ func (s *Slot) OnEntryBlocked(ctx *base.EntryContext, blockError *base.BlockErro
 	// TODO: write sentinel-block.log here
 }
 
-func (s *Slot) OnCompleted(_ *base.EntryContext) {
-
+func (s *Slot) OnCompleted(ctx *base.EntryContext) {
+	// Record the RT histogram of the metric log (only for the JSON format).
+	if !metric.IsRtHistogramEnabled() {
+		return
+	}
+	rt := ctx.Rt()
+	metric.RecordRt(ctx.Resource.Name(), rt)
+	if ctx.Resource.FlowType() == base.Inbound {
+		metric.RecordRt(stat.InboundNode().ResourceName(), rt)
+	}
 }
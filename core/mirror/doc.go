@@ -0,0 +1,17 @@
+// Package mirror forwards a sample of the blocked requests to a sink (e.g. an HTTP endpoint or a channel)
+// for the offline analysis of who is being throttled and whether the limits are mis-set.
+//
+// Only the metadata of the blocked requests is mirrored (see BlockedRequest), never the arguments or the bodies.
+// The mirror itself is strictly rate limited by Config.MaxPerSecond after sampling by Config.SampleRate,
+// and the requests are buffered and sent to the sink in batches by a background goroutine, so the slow sink
+// never blocks the entries. The requests are dropped when the buffer is full, which is counted by Stats.
+//
+// Here is the example code to mirror 10% of the blocked requests, at most 20 requests per second:
+//
+//	err := mirror.Start(mirror.NewHTTPSink("http://analysis.internal/blocked", nil), mirror.Config{
+//		SampleRate:   0.1,
+//		MaxPerSecond: 20,
+//	})
+//	...
+//	defer mirror.Stop()
+package mirror
@@ -0,0 +1,288 @@
+package mirror
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultBufferSize    = 1024
+	DefaultBatchSize     = 100
+	DefaultFlushInterval = time.Second
+)
+
+// BlockedRequest is the metadata of a blocked request.
+type BlockedRequest struct {
+	// Timestamp is the time (in ms) when the request was blocked.
+	Timestamp    uint64            `json:"timestamp"`
+	Resource     string            `json:"resource"`
+	ResourceType base.ResourceType `json:"resourceType"`
+	TrafficType  string            `json:"trafficType"`
+	BlockType    string            `json:"blockType"`
+	BlockMsg     string            `json:"blockMsg,omitempty"`
+	// Rule is the string of the triggered rule, empty if absent.
+	Rule         string `json:"rule,omitempty"`
+	Origin       string `json:"origin,omitempty"`
+	Category     string `json:"category,omitempty"`
+	AcquireCount uint32 `json:"acquireCount"`
+}
+
+// Sink receives the mirrored requests in batches, which is called by a single goroutine.
+type Sink interface {
+	Mirror(reqs []*BlockedRequest) error
+}
+
+// Config is the config of the mirror.
+type Config struct {
+	// SampleRate is the ratio of the blocked requests mirrored, in (0, 1].
+	SampleRate float64
+	// MaxPerSecond is the max amount of the mirrored requests per second, which must be positive.
+	MaxPerSecond uint32
+	// BufferSize is the capacity of the requests waiting to be sent, DefaultBufferSize if 0.
+	BufferSize int
+	// BatchSize is the max amount of the requests sent to the sink at a time, DefaultBatchSize if 0.
+	BatchSize int
+	// FlushInterval is the max time the requests wait in the buffer, DefaultFlushInterval if 0.
+	FlushInterval time.Duration
+}
+
+// Stats is the statistics of the mirror since it starts.
+type Stats struct {
+	// Mirrored is the amount of the requests sent to the sink successfully.
+	Mirrored uint64 `json:"mirrored"`
+	// Failed is the amount of the requests failed to be sent to the sink.
+	Failed uint64 `json:"failed"`
+	// SampledOut is the amount of the blocked requests not sampled.
+	SampledOut uint64 `json:"sampledOut"`
+	// RateLimited is the amount of the sampled requests beyond MaxPerSecond.
+	RateLimited uint64 `json:"rateLimited"`
+	// Dropped is the amount of the requests dropped as the buffer is full.
+	Dropped uint64 `json:"dropped"`
+}
+
+type mirror struct {
+	sink    Sink
+	cfg     Config
+	limiter *limiter
+	queue   chan *BlockedRequest
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+
+	mirrored    uint64
+	failed      uint64
+	sampledOut  uint64
+	rateLimited uint64
+	dropped     uint64
+}
+
+var (
+	current atomic.Value // *mirror
+	// lifecycleMux serializes Start and Stop.
+	lifecycleMux = new(sync.Mutex)
+
+	mirrorDrops = selfmetric.NewDropCounter("mirror")
+)
+
+// Start starts mirroring the blocked requests to the sink, the mirror started previously is stopped.
+func Start(sink Sink, cfg Config) error {
+	if sink == nil {
+		return errors.New("nil Sink")
+	}
+	if !(cfg.SampleRate > 0 && cfg.SampleRate <= 1) {
+		return errors.Errorf("SampleRate must be in (0, 1], got %v", cfg.SampleRate)
+	}
+	if cfg.MaxPerSecond == 0 {
+		return errors.New("MaxPerSecond must be positive")
+	}
+	if cfg.BufferSize < 0 || cfg.BatchSize < 0 || cfg.FlushInterval < 0 {
+		return errors.New("negative BufferSize, BatchSize or FlushInterval")
+	}
+	if cfg.BufferSize == 0 {
+		cfg.BufferSize = DefaultBufferSize
+	}
+	if cfg.BatchSize == 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.FlushInterval == 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+
+	lifecycleMux.Lock()
+	defer lifecycleMux.Unlock()
+
+	stopCurrent()
+	m := &mirror{
+		sink:    sink,
+		cfg:     cfg,
+		limiter: &limiter{maxPerSecond: cfg.MaxPerSecond},
+		queue:   make(chan *BlockedRequest, cfg.BufferSize),
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+	go util.RunWithRecover(m.run)
+	current.Store(m)
+	logging.Info("[Mirror] Blocked request mirroring started", "sampleRate", cfg.SampleRate, "maxPerSecond", cfg.MaxPerSecond)
+	return nil
+}
+
+// Stop stops mirroring, the requests in the buffer are sent to the sink before it returns.
+func Stop() {
+	lifecycleMux.Lock()
+	defer lifecycleMux.Unlock()
+
+	stopCurrent()
+}
+
+func stopCurrent() {
+	m := currentMirror()
+	if m == nil {
+		return
+	}
+	current.Store((*mirror)(nil))
+	close(m.stopCh)
+	<-m.doneCh
+	logging.Info("[Mirror] Blocked request mirroring stopped", "stats", m.stats())
+}
+
+func currentMirror() *mirror {
+	m, _ := current.Load().(*mirror)
+	return m
+}
+
+// CurrentStats returns the statistics of the running mirror, false if the mirror is not started.
+func CurrentStats() (Stats, bool) {
+	m := currentMirror()
+	if m == nil {
+		return Stats{}, false
+	}
+	return m.stats(), true
+}
+
+func (m *mirror) stats() Stats {
+	return Stats{
+		Mirrored:    atomic.LoadUint64(&m.mirrored),
+		Failed:      atomic.LoadUint64(&m.failed),
+		SampledOut:  atomic.LoadUint64(&m.sampledOut),
+		RateLimited: atomic.LoadUint64(&m.rateLimited),
+		Dropped:     atomic.LoadUint64(&m.dropped),
+	}
+}
+
+// offer samples and rate limits the blocked request, and enqueues it without blocking.
+func (m *mirror) offer(ctx *base.EntryContext, blockError *base.BlockError) {
+	if m.cfg.SampleRate < 1 && rand.Float64() >= m.cfg.SampleRate {
+		atomic.AddUint64(&m.sampledOut, 1)
+		return
+	}
+	now := util.CurrentTimeMillis()
+	if !m.limiter.tryAcquire(now) {
+		atomic.AddUint64(&m.rateLimited, 1)
+		return
+	}
+	select {
+	case m.queue <- newBlockedRequest(ctx, blockError, now):
+	default:
+		atomic.AddUint64(&m.dropped, 1)
+		mirrorDrops.Inc()
+	}
+}
+
+func (m *mirror) run() {
+	defer close(m.doneCh)
+
+	ticker := time.NewTicker(m.cfg.FlushInterval)
+	defer ticker.Stop()
+	batch := make([]*BlockedRequest, 0, m.cfg.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := m.sink.Mirror(batch); err != nil {
+			atomic.AddUint64(&m.failed, uint64(len(batch)))
+			logging.Warn("[Mirror] Failed to mirror the blocked requests", "count", len(batch), "reason", err.Error())
+		} else {
+			atomic.AddUint64(&m.mirrored, uint64(len(batch)))
+		}
+		// the sink may hold the batch
+		batch = make([]*BlockedRequest, 0, m.cfg.BatchSize)
+	}
+	for {
+		select {
+		case r := <-m.queue:
+			batch = append(batch, r)
+			if len(batch) >= m.cfg.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-m.stopCh:
+			for {
+				select {
+				case r := <-m.queue:
+					batch = append(batch, r)
+					if len(batch) >= m.cfg.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func newBlockedRequest(ctx *base.EntryContext, blockError *base.BlockError, now uint64) *BlockedRequest {
+	r := &BlockedRequest{
+		Timestamp:    now,
+		Resource:     ctx.Resource.Name(),
+		ResourceType: ctx.Resource.Classification(),
+		TrafficType:  ctx.Resource.FlowType().String(),
+		Origin:       ctx.Origin(),
+	}
+	if ctx.Input != nil {
+		r.AcquireCount = ctx.Input.AcquireCount
+	}
+	if c := ctx.Category(); c != base.CategoryNone {
+		r.Category = c.String()
+	}
+	if blockError != nil {
+		r.BlockType = blockError.BlockType().String()
+		r.BlockMsg = blockError.BlockMsg()
+		if rule := blockError.TriggeredRule(); rule != nil {
+			r.Rule = rule.String()
+		}
+	}
+	return r
+}
+
+// limiter limits the amount of the mirrored requests per second strictly.
+type limiter struct {
+	mux          sync.Mutex
+	maxPerSecond uint32
+	second       uint64
+	count        uint32
+}
+
+func (l *limiter) tryAcquire(nowMs uint64) bool {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	if sec := nowMs / 1000; sec != l.second {
+		l.second = sec
+		l.count = 0
+	}
+	if l.count >= l.maxPerSecond {
+		return false
+	}
+	l.count++
+	return true
+}
@@ -0,0 +1,130 @@
+package mirror
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRule struct{}
+
+func (r *testRule) String() string       { return "test-rule" }
+func (r *testRule) ResourceName() string { return "abc" }
+
+func blockOnce(s *Slot) {
+	ctx := base.NewEmptyEntryContext()
+	ctx.Resource = base.NewResourceWrapper("abc", base.ResTypeWeb, base.Inbound)
+	ctx.Input = &base.SentinelInput{
+		AcquireCount: 1,
+		Args:         []interface{}{"secret"},
+		Attachments:  map[interface{}]interface{}{base.OriginAttachmentKey: "caller-a"},
+	}
+	s.OnEntryBlocked(ctx, base.NewBlockErrorWithCause(base.BlockTypeFlow, "flow exceeded", &testRule{}, nil))
+}
+
+func TestStart_Invalid(t *testing.T) {
+	ch := make(chan *BlockedRequest, 1)
+	assert.Error(t, Start(nil, Config{SampleRate: 1, MaxPerSecond: 1}))
+	assert.Error(t, Start(NewChannelSink(ch), Config{SampleRate: 0, MaxPerSecond: 1}))
+	assert.Error(t, Start(NewChannelSink(ch), Config{SampleRate: 1.5, MaxPerSecond: 1}))
+	assert.Error(t, Start(NewChannelSink(ch), Config{SampleRate: 1}))
+	_, started := CurrentStats()
+	assert.False(t, started)
+}
+
+func TestMirror_ChannelSink(t *testing.T) {
+	ch := make(chan *BlockedRequest, 100)
+	assert.NoError(t, Start(NewChannelSink(ch), Config{SampleRate: 1, MaxPerSecond: 100, FlushInterval: 10 * time.Millisecond}))
+
+	s := &Slot{}
+	for i := 0; i < 10; i++ {
+		blockOnce(s)
+	}
+	stats, started := CurrentStats()
+	assert.True(t, started)
+	Stop()
+	assert.Equal(t, 10, len(ch))
+	r := <-ch
+	assert.Equal(t, "abc", r.Resource)
+	assert.Equal(t, base.ResTypeWeb, r.ResourceType)
+	assert.Equal(t, "Inbound", r.TrafficType)
+	assert.Equal(t, "FlowControl", r.BlockType)
+	assert.Equal(t, "flow exceeded", r.BlockMsg)
+	assert.Equal(t, "test-rule", r.Rule)
+	assert.Equal(t, "caller-a", r.Origin)
+	assert.Equal(t, uint32(1), r.AcquireCount)
+	assert.Equal(t, uint64(0), stats.RateLimited)
+
+	// nothing is mirrored after stopped
+	blockOnce(s)
+	assert.Equal(t, 9, len(ch))
+	_, started = CurrentStats()
+	assert.False(t, started)
+}
+
+func TestMirror_Backpressure(t *testing.T) {
+	release := make(chan struct{})
+	sink := sinkFunc(func(reqs []*BlockedRequest) error {
+		<-release
+		return nil
+	})
+	assert.NoError(t, Start(sink, Config{SampleRate: 1, MaxPerSecond: 1000, BufferSize: 2, BatchSize: 1}))
+
+	s := &Slot{}
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 20; i++ {
+			blockOnce(s)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the slow sink should never block the entries")
+	}
+	stats, _ := CurrentStats()
+	assert.True(t, stats.Dropped >= 17, stats)
+	close(release)
+	Stop()
+}
+
+func TestLimiter(t *testing.T) {
+	l := &limiter{maxPerSecond: 2}
+	assert.True(t, l.tryAcquire(1000))
+	assert.True(t, l.tryAcquire(1500))
+	assert.False(t, l.tryAcquire(1999))
+	assert.True(t, l.tryAcquire(2000))
+}
+
+func TestHTTPSink(t *testing.T) {
+	var received []*BlockedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	err := NewHTTPSink(server.URL, nil).Mirror([]*BlockedRequest{{Resource: "abc", BlockType: "FlowControl"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(received))
+	assert.Equal(t, "abc", received[0].Resource)
+
+	assert.Error(t, NewHTTPSink(server.URL+"/%", nil).Mirror(nil))
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	assert.Error(t, NewHTTPSink(failing.URL, nil).Mirror(nil))
+}
+
+type sinkFunc func(reqs []*BlockedRequest) error
+
+func (f sinkFunc) Mirror(reqs []*BlockedRequest) error {
+	return f(reqs)
+}
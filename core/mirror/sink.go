@@ -0,0 +1,67 @@
+package mirror
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultHTTPTimeout is the timeout of the HTTPSink without the client specified.
+const DefaultHTTPTimeout = 3 * time.Second
+
+type channelSink struct {
+	ch chan<- *BlockedRequest
+}
+
+// NewChannelSink creates the Sink sending the mirrored requests to the channel. The requests are dropped
+// (and counted as failed) if the channel is full, so the consumer never blocks the mirror.
+func NewChannelSink(ch chan<- *BlockedRequest) Sink {
+	return &channelSink{ch: ch}
+}
+
+func (s *channelSink) Mirror(reqs []*BlockedRequest) error {
+	for i, r := range reqs {
+		select {
+		case s.ch <- r:
+		default:
+			return errors.Errorf("channel is full, %d of %d requests are dropped", len(reqs)-i, len(reqs))
+		}
+	}
+	return nil
+}
+
+type httpSink struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewHTTPSink creates the Sink posting the mirrored requests to the endpoint as the JSON array.
+// The client with DefaultHTTPTimeout is used if the client is nil.
+func NewHTTPSink(endpoint string, client *http.Client) Sink {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultHTTPTimeout}
+	}
+	return &httpSink{endpoint: endpoint, client: client}
+}
+
+func (s *httpSink) Mirror(reqs []*BlockedRequest) error {
+	body, err := json.Marshal(reqs)
+	if err != nil {
+		return errors.Wrap(err, "fail to marshal the mirrored requests")
+	}
+	resp, err := s.client.Post(s.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "fail to post the mirrored requests")
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("unexpected status of the mirror endpoint: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package mirror
+
+import (
+	"github.com/alibaba/sentinel-golang/core/base"
+)
+
+// Slot mirrors the blocked requests if the mirror is started.
+type Slot struct {
+}
+
+func (s *Slot) OnEntryPassed(_ *base.EntryContext) {
+}
+
+func (s *Slot) OnEntryBlocked(ctx *base.EntryContext, blockError *base.BlockError) {
+	if m := currentMirror(); m != nil {
+		m.offer(ctx, blockError)
+	}
+}
+
+func (s *Slot) OnCompleted(_ *base.EntryContext) {
+}
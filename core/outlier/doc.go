@@ -0,0 +1,15 @@
+// Package outlier implements the outlier endpoint ejection for client-side load balancing.
+//
+// Outlier ejection is the circuit breaking at endpoint (e.g. host:port) granularity rather than resource granularity.
+// The outlier module tracks the error and RT statistics of each endpoint of outbound resources, and ejects
+// the endpoints whose error ratio exceeds the threshold. Ejected endpoints enter probation after the ejection
+// duration: the first completed request decides whether the endpoint recovers or is ejected again (with a longer duration).
+// The endpoints without completed requests for 10 statistic intervals (after the ejection ends) are evicted,
+// so that the endpoints of the replaced instances don't accumulate.
+//
+// The endpoint of an entry is carried by the attachment with EndpointAttachmentKey:
+//
+//	e, b := api.Entry("some-service", api.WithAttachment(outlier.EndpointAttachmentKey, "10.0.0.1:8080"))
+//
+// Client load balancers could skip the unhealthy endpoints by outlier.IsHealthy(endpoint).
+package outlier
@@ -0,0 +1,160 @@
+package outlier
+
+import (
+	"sync"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	sbase "github.com/alibaba/sentinel-golang/core/stat/base"
+)
+
+// endpointIdleWindows is the number of the statistic intervals, after which the idle endpoint is evicted,
+// so that the endpoints of the scaled-in or replaced instances don't accumulate.
+const endpointIdleWindows = 10
+
+// endpointStat holds the statistic and the ejection state of an endpoint.
+type endpointStat struct {
+	stat *sbase.BucketLeapArray
+
+	// ejectionCount is the number of continuous ejections, 0 means the endpoint is healthy.
+	ejectionCount uint32
+	// ejectedUntil is the timestamp (in ms) when the ejection ends and the probation begins.
+	ejectedUntil uint64
+	// lastCompleteTime is the timestamp (in ms) of the last completed request of the endpoint.
+	lastCompleteTime uint64
+}
+
+// isIdle checks whether the endpoint has no completed requests for idleMs since the last request
+// or the end of the ejection, whichever is later.
+func (s *endpointStat) isIdle(now uint64, idleMs uint64) bool {
+	last := s.lastCompleteTime
+	if s.ejectedUntil > last {
+		last = s.ejectedUntil
+	}
+	return now > last && now-last >= idleMs
+}
+
+func (s *endpointStat) isEjected(now uint64) bool {
+	return s.ejectionCount > 0 && now < s.ejectedUntil
+}
+
+func (s *endpointStat) inProbation(now uint64) bool {
+	return s.ejectionCount > 0 && now >= s.ejectedUntil
+}
+
+// resourceOutlier tracks the endpoints of a resource according to the bound rule.
+type resourceOutlier struct {
+	rule *Rule
+
+	mux       sync.Mutex
+	endpoints map[string]*endpointStat
+	// lastEvictTime is the timestamp (in ms) of the last eviction of the idle endpoints.
+	lastEvictTime uint64
+}
+
+func newResourceOutlier(rule *Rule) *resourceOutlier {
+	return &resourceOutlier{
+		rule:      rule,
+		endpoints: make(map[string]*endpointStat),
+	}
+}
+
+func (o *resourceOutlier) newEndpointStat() *sbase.BucketLeapArray {
+	return sbase.NewBucketLeapArray(1, o.rule.StatIntervalMs)
+}
+
+func (o *resourceOutlier) onRequestComplete(endpoint string, rt uint64, err error, now uint64) {
+	failed := err != nil || (o.rule.MaxAllowedRtMs > 0 && rt > o.rule.MaxAllowedRtMs)
+
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	o.evictIdleEndpoints(now)
+	es, exist := o.endpoints[endpoint]
+	if !exist {
+		es = &endpointStat{stat: o.newEndpointStat()}
+		o.endpoints[endpoint] = es
+	}
+	es.lastCompleteTime = now
+	if es.isEjected(now) {
+		// The requests sent before the ejection are ignored.
+		return
+	}
+	if es.inProbation(now) {
+		// The first completed request during probation decides whether the endpoint recovers.
+		if failed {
+			o.eject(es, now)
+		} else {
+			es.ejectionCount = 0
+			es.stat = o.newEndpointStat()
+		}
+		return
+	}
+
+	es.stat.AddCount(base.MetricEventComplete, 1)
+	if failed {
+		es.stat.AddCount(base.MetricEventError, 1)
+	}
+	total := es.stat.Count(base.MetricEventComplete)
+	if total <= 0 || uint64(total) < o.rule.MinRequestAmount {
+		return
+	}
+	errorRatio := float64(es.stat.Count(base.MetricEventError)) / float64(total)
+	if errorRatio > o.rule.MaxErrorRatio && o.canEject(now) {
+		o.eject(es, now)
+	}
+}
+
+// evictIdleEndpoints evicts the endpoints idle for endpointIdleWindows statistic intervals, which runs at most
+// once per statistic interval. The caller must hold the lock.
+func (o *resourceOutlier) evictIdleEndpoints(now uint64) {
+	intervalMs := uint64(o.rule.StatIntervalMs)
+	if now < o.lastEvictTime+intervalMs {
+		return
+	}
+	o.lastEvictTime = now
+	idleMs := intervalMs * endpointIdleWindows
+	for endpoint, es := range o.endpoints {
+		if es.isIdle(now, idleMs) {
+			delete(o.endpoints, endpoint)
+		}
+	}
+}
+
+// eject ejects the endpoint, the caller must hold the lock.
+func (o *resourceOutlier) eject(es *endpointStat, now uint64) {
+	es.ejectionCount++
+	es.ejectedUntil = now + o.rule.ejectionDurationMs(es.ejectionCount)
+	es.stat = o.newEndpointStat()
+}
+
+// canEject checks whether one more endpoint could be ejected, the caller must hold the lock.
+func (o *resourceOutlier) canEject(now uint64) bool {
+	ejected := 0
+	for _, es := range o.endpoints {
+		if es.isEjected(now) {
+			ejected++
+		}
+	}
+	return float64(ejected+1)/float64(len(o.endpoints)) <= o.rule.maxEjectionPercent()
+}
+
+func (o *resourceOutlier) isEjected(endpoint string, now uint64) bool {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	es, exist := o.endpoints[endpoint]
+	return exist && es.isEjected(now)
+}
+
+func (o *resourceOutlier) ejectedEndpoints(now uint64) []string {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	ret := make([]string, 0)
+	for endpoint, es := range o.endpoints {
+		if es.isEjected(now) {
+			ret = append(ret, endpoint)
+		}
+	}
+	return ret
+}
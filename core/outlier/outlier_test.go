@@ -0,0 +1,102 @@
+package outlier
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadRules(t *testing.T) {
+	defer ClearRules()
+
+	r := &Rule{
+		Resource:       "abc",
+		MaxErrorRatio:  0.5,
+		StatIntervalMs: 10000,
+		BaseEjectionMs: 1000,
+	}
+	_, err := LoadRules([]*Rule{r, {Resource: "abc", MaxErrorRatio: 0.1, StatIntervalMs: 1000, BaseEjectionMs: 1000}, {Resource: "def"}})
+	assert.Nil(t, err)
+	rules := GetRules()
+	assert.Equal(t, 1, len(rules))
+	assert.Equal(t, 0.5, rules[0].MaxErrorRatio)
+
+	o := getOutlierOf("abc")
+	_, err = LoadRules([]*Rule{{Resource: "abc", MaxErrorRatio: 0.5, StatIntervalMs: 10000, BaseEjectionMs: 1000}})
+	assert.Nil(t, err)
+	assert.True(t, o == getOutlierOf("abc"), "the outlier of unchanged rule should be reused")
+}
+
+func TestResourceOutlier_Ejection(t *testing.T) {
+	o := newResourceOutlier(&Rule{
+		Resource:           "abc",
+		MaxErrorRatio:      0.5,
+		MaxAllowedRtMs:     100,
+		MinRequestAmount:   4,
+		StatIntervalMs:     10000,
+		BaseEjectionMs:     1000,
+		MaxEjectionPercent: 0.5,
+	})
+	bizErr := errors.New("biz error")
+	now := uint64(100000)
+
+	o.onRequestComplete("host-a", 10, nil, now)
+	o.onRequestComplete("host-b", 10, nil, now)
+	for i := 0; i < 3; i++ {
+		o.onRequestComplete("host-a", 10, bizErr, now)
+		o.onRequestComplete("host-b", 200, nil, now)
+	}
+	// host-a is ejected, while host-b couldn't be ejected due to MaxEjectionPercent.
+	assert.True(t, o.isEjected("host-a", now))
+	assert.False(t, o.isEjected("host-b", now))
+	assert.Equal(t, []string{"host-a"}, o.ejectedEndpoints(now))
+
+	// Probation fails, so host-a is ejected again with a longer duration.
+	now += 1000
+	assert.False(t, o.isEjected("host-a", now))
+	o.onRequestComplete("host-a", 10, bizErr, now)
+	assert.True(t, o.isEjected("host-a", now+1500))
+
+	// Probation succeeds, so host-a recovers.
+	now += 2000
+	o.onRequestComplete("host-a", 10, nil, now)
+	assert.False(t, o.isEjected("host-a", now))
+	assert.Equal(t, uint32(0), o.endpoints["host-a"].ejectionCount)
+}
+
+func TestResourceOutlier_EvictIdleEndpoints(t *testing.T) {
+	o := newResourceOutlier(&Rule{
+		Resource:           "abc",
+		MaxErrorRatio:      0.5,
+		MinRequestAmount:   1,
+		StatIntervalMs:     1000,
+		BaseEjectionMs:     100000,
+		MaxEjectionPercent: 1,
+	})
+	now := uint64(1000000)
+	o.onRequestComplete("host-a", 10, nil, now)
+	o.onRequestComplete("host-b", 10, errors.New("biz error"), now)
+	assert.True(t, o.isEjected("host-b", now))
+	assert.Equal(t, 2, len(o.endpoints))
+
+	now += 1000 * endpointIdleWindows
+	o.onRequestComplete("host-c", 10, nil, now)
+	// host-a is idle, while host-b is still ejected.
+	assert.Equal(t, 2, len(o.endpoints))
+	assert.True(t, o.isEjected("host-b", now))
+
+	// Evicted after the ejection ends and stays idle.
+	now += 100000 + 1000*endpointIdleWindows
+	o.onRequestComplete("host-c", 10, nil, now)
+	assert.Equal(t, 1, len(o.endpoints))
+	assert.False(t, o.isEjected("host-b", now))
+
+	// Many distinct endpoints come and go.
+	for i := 0; i < 1000; i++ {
+		now += 100
+		o.onRequestComplete(fmt.Sprintf("host-%d", i), 10, nil, now)
+	}
+	assert.True(t, len(o.endpoints) <= 10*endpointIdleWindows+2)
+}
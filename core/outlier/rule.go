@@ -0,0 +1,72 @@
+package outlier
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Rule describes the outlier ejection strategy of the endpoints of a resource.
+type Rule struct {
+	// ID represents the unique ID of the rule (optional).
+	ID string `json:"id,omitempty"`
+	// Resource represents the outbound resource whose endpoints are tracked.
+	Resource string `json:"resource"`
+	// MaxErrorRatio is the threshold of error ratio of an endpoint, the valid range is [0.0, 1.0].
+	// The endpoint will be ejected if its error ratio exceeds MaxErrorRatio.
+	MaxErrorRatio float64 `json:"maxErrorRatio"`
+	// MaxAllowedRtMs indicates that a request whose RT exceeds it will be regarded as an error (optional).
+	// 0 means RT is not taken into account.
+	MaxAllowedRtMs uint64 `json:"maxAllowedRtMs"`
+	// MinRequestAmount represents the minimum number of requests (in an active statistic time span)
+	// that can trigger the ejection.
+	MinRequestAmount uint64 `json:"minRequestAmount"`
+	// StatIntervalMs represents statistic time interval of the endpoint statistic.
+	StatIntervalMs uint32 `json:"statIntervalMs"`
+	// BaseEjectionMs is the ejection duration of the first ejection.
+	// The duration is multiplied by the number of continuous ejections of the endpoint.
+	BaseEjectionMs uint32 `json:"baseEjectionMs"`
+	// MaxEjectionMs is the upper bound of the ejection duration (optional), 0 means no limit.
+	MaxEjectionMs uint32 `json:"maxEjectionMs"`
+	// MaxEjectionPercent is the max percentage of the ejected endpoints of the resource, the valid range is (0.0, 1.0].
+	// It prevents all the endpoints from being ejected. 0 means 1.0.
+	MaxEjectionPercent float64 `json:"maxEjectionPercent"`
+}
+
+func (r *Rule) String() string {
+	b, err := json.Marshal(r)
+	if err != nil {
+		// Return the fallback string
+		return fmt.Sprintf("{Id=%s, Resource=%s, MaxErrorRatio=%.2f, MaxAllowedRtMs=%d, MinRequestAmount=%d, StatIntervalMs=%d, "+
+			"BaseEjectionMs=%d, MaxEjectionMs=%d, MaxEjectionPercent=%.2f}", r.ID, r.Resource, r.MaxErrorRatio, r.MaxAllowedRtMs,
+			r.MinRequestAmount, r.StatIntervalMs, r.BaseEjectionMs, r.MaxEjectionMs, r.MaxEjectionPercent)
+	}
+	return string(b)
+}
+
+func (r *Rule) ResourceName() string {
+	return r.Resource
+}
+
+func (r *Rule) isEqualsTo(newRule *Rule) bool {
+	if newRule == nil {
+		return false
+	}
+	return r.Resource == newRule.Resource && r.MaxErrorRatio == newRule.MaxErrorRatio && r.MaxAllowedRtMs == newRule.MaxAllowedRtMs &&
+		r.MinRequestAmount == newRule.MinRequestAmount && r.StatIntervalMs == newRule.StatIntervalMs && r.BaseEjectionMs == newRule.BaseEjectionMs &&
+		r.MaxEjectionMs == newRule.MaxEjectionMs && r.MaxEjectionPercent == newRule.MaxEjectionPercent
+}
+
+func (r *Rule) ejectionDurationMs(ejectionCount uint32) uint64 {
+	d := uint64(r.BaseEjectionMs) * uint64(ejectionCount)
+	if r.MaxEjectionMs > 0 && d > uint64(r.MaxEjectionMs) {
+		return uint64(r.MaxEjectionMs)
+	}
+	return d
+}
+
+func (r *Rule) maxEjectionPercent() float64 {
+	if r.MaxEjectionPercent <= 0 {
+		return 1.0
+	}
+	return r.MaxEjectionPercent
+}
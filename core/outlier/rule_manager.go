@@ -0,0 +1,152 @@
+package outlier
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+var (
+	outlierMap = make(map[string]*resourceOutlier)
+	updateMux  = new(sync.RWMutex)
+)
+
+// LoadRules loads the given outlier rules to the rule manager, while all previous rules will be replaced.
+// Only one rule is allowed for each resource, and the latter rules of the same resource will be ignored.
+// The endpoint statistics of the resource are retained if the rule is unchanged.
+func LoadRules(rules []*Rule) (bool, error) {
+	defer selfmetric.RecordRuleUpdate("outlier", time.Now())
+
+	resRuleMap := make(map[string]*Rule)
+	for _, r := range rules {
+		if err := IsValidRule(r); err != nil {
+			logging.Warn("Ignoring invalid outlier rule", "rule", r, "reason", err)
+			continue
+		}
+		if _, exist := resRuleMap[r.Resource]; exist {
+			logging.Warn("Ignoring duplicate outlier rule of the resource", "rule", r)
+			continue
+		}
+		resRuleMap[r.Resource] = r
+	}
+
+	m := make(map[string]*resourceOutlier, len(resRuleMap))
+	start := util.CurrentTimeNano()
+	updateMux.Lock()
+	defer func() {
+		updateMux.Unlock()
+		logging.Debug("time statistic(ns) for updating outlier rule", "timeCost", util.CurrentTimeNano()-start)
+		logRuleUpdate(m)
+	}()
+	for res, r := range resRuleMap {
+		if old, exist := outlierMap[res]; exist && old.rule.isEqualsTo(r) {
+			m[res] = old
+		} else {
+			m[res] = newResourceOutlier(r)
+		}
+	}
+	outlierMap = m
+	return true, nil
+}
+
+// ClearRules clears all the rules in outlier module.
+func ClearRules() error {
+	_, err := LoadRules(nil)
+	return err
+}
+
+// GetRules returns all the rules based on copy.
+// It doesn't take effect for outlier module if user changes the rule.
+func GetRules() []Rule {
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+
+	ret := make([]Rule, 0, len(outlierMap))
+	for _, o := range outlierMap {
+		ret = append(ret, *o.rule)
+	}
+	return ret
+}
+
+// IsHealthy checks whether the endpoint is not ejected by any resource.
+func IsHealthy(endpoint string) bool {
+	now := util.CurrentTimeMillis()
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+
+	for _, o := range outlierMap {
+		if o.isEjected(endpoint, now) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsHealthyOf checks whether the endpoint is not ejected by the given resource.
+func IsHealthyOf(resource, endpoint string) bool {
+	o := getOutlierOf(resource)
+	return o == nil || !o.isEjected(endpoint, util.CurrentTimeMillis())
+}
+
+// EjectedEndpointsOf returns the endpoints currently ejected by the given resource.
+func EjectedEndpointsOf(resource string) []string {
+	o := getOutlierOf(resource)
+	if o == nil {
+		return make([]string, 0)
+	}
+	return o.ejectedEndpoints(util.CurrentTimeMillis())
+}
+
+// OnRequestComplete records the completed request of the endpoint. It's useful for the client load balancers
+// that don't go through Sentinel entries, otherwise the MetricStatSlot records the requests automatically.
+func OnRequestComplete(resource, endpoint string, rt uint64, err error) {
+	if o := getOutlierOf(resource); o != nil {
+		o.onRequestComplete(endpoint, rt, err, util.CurrentTimeMillis())
+	}
+}
+
+func getOutlierOf(resource string) *resourceOutlier {
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+
+	return outlierMap[resource]
+}
+
+func logRuleUpdate(m map[string]*resourceOutlier) {
+	if len(m) == 0 {
+		logging.Info("[OutlierRuleManager] Outlier rules were cleared")
+		return
+	}
+	rules := make([]*Rule, 0, len(m))
+	for _, o := range m {
+		rules = append(rules, o.rule)
+	}
+	logging.Info("[OutlierRuleManager] Outlier rules were loaded", "rules", rules)
+}
+
+// IsValidRule checks whether the given Rule is valid.
+func IsValidRule(r *Rule) error {
+	if r == nil {
+		return errors.New("nil Rule")
+	}
+	if len(r.Resource) == 0 {
+		return errors.New("empty resource name")
+	}
+	if r.MaxErrorRatio < 0 || r.MaxErrorRatio > 1 {
+		return errors.New("invalid MaxErrorRatio, valid range is [0.0, 1.0]")
+	}
+	if r.StatIntervalMs == 0 {
+		return errors.New("invalid StatIntervalMs")
+	}
+	if r.BaseEjectionMs == 0 {
+		return errors.New("invalid BaseEjectionMs")
+	}
+	if r.MaxEjectionPercent < 0 || r.MaxEjectionPercent > 1 {
+		return errors.New("invalid MaxEjectionPercent, valid range is (0.0, 1.0]")
+	}
+	return nil
+}
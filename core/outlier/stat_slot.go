@@ -0,0 +1,34 @@
+package outlier
+
+import (
+	"github.com/alibaba/sentinel-golang/core/base"
+)
+
+// EndpointAttachmentKey is the key of the entry attachment that carries the endpoint (e.g. host:port) of the request.
+const EndpointAttachmentKey = "sentinel.outlier.endpoint"
+
+// MetricStatSlot records the endpoint metrics for outlier ejection on invocation completed.
+// MetricStatSlot must be filled into slot chain if outlier ejection is alive.
+type MetricStatSlot struct {
+}
+
+func (s *MetricStatSlot) OnEntryPassed(_ *base.EntryContext) {
+	// Do nothing
+	return
+}
+
+func (s *MetricStatSlot) OnEntryBlocked(_ *base.EntryContext, _ *base.BlockError) {
+	// Do nothing
+	return
+}
+
+func (s *MetricStatSlot) OnCompleted(ctx *base.EntryContext) {
+	if ctx.Input == nil || ctx.Input.Attachments == nil {
+		return
+	}
+	endpoint, ok := ctx.Input.Attachments[EndpointAttachmentKey].(string)
+	if !ok || len(endpoint) == 0 {
+		return
+	}
+	OnRequestComplete(ctx.Resource.Name(), endpoint, ctx.Rt(), ctx.Err())
+}
@@ -0,0 +1,17 @@
+// Package policy provides the default policy for the resources that don't match any rules.
+//
+// Sentinel allows the requests of the resources without rules by default. For security-minded gateways that must
+// fail closed, the default policy could be set to Deny globally or for a namespace (i.e. the resources with
+// the name prefix), in which case only the resources in the allowlist or with dedicated rules (e.g. flow rules)
+// are allowed, and the others are blocked with BlockTypeDefaultDeny.
+//
+// Here is the example code to deny the unmatched resources of the "gateway:" namespace:
+//
+//	_, err := policy.LoadRules([]*policy.Rule{
+//	    {
+//	        Namespace: "gateway:",
+//	        Default:   policy.Deny,
+//	        Allowlist: []string{"gateway:/health", "gateway:/public/*"},
+//	    },
+//	})
+package policy
@@ -0,0 +1,52 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func check(res string) *base.TokenResult {
+	return (&Slot{}).Check(&base.EntryContext{
+		Resource: base.NewResourceWrapper(res, base.ResTypeCommon, base.Inbound),
+	})
+}
+
+func TestIsValidRule(t *testing.T) {
+	assert.NotNil(t, IsValidRule(nil))
+	assert.NotNil(t, IsValidRule(&Rule{Default: 2}))
+	assert.Nil(t, IsValidRule(&Rule{Namespace: "a", Default: Deny}))
+}
+
+func TestSlot(t *testing.T) {
+	defer ClearRules()
+
+	assert.Nil(t, check("gateway:/api"))
+	assert.False(t, base.ResourceHasRules("gateway:/api"))
+
+	_, err := LoadRules([]*Rule{
+		{Namespace: "gateway:", Default: Deny, Allowlist: []string{"gateway:/health", "gateway:/public/*"}},
+		{Namespace: "gateway:/internal/", Default: Allow},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(GetRules()))
+	// The fast path is disabled if any resource may be denied.
+	assert.True(t, base.ResourceHasRules("gateway:/api"))
+
+	r := check("gateway:/api")
+	assert.True(t, r.IsBlocked())
+	assert.Equal(t, base.BlockTypeDefaultDeny, r.BlockError().BlockType())
+	assert.Nil(t, check("gateway:/health"))
+	assert.Nil(t, check("gateway:/public/docs"))
+	assert.Nil(t, check("gateway:/internal/metrics"))
+	assert.Nil(t, check("other"))
+
+	// The resources with dedicated rules are allowed.
+	base.SetRuleResourcesOf("flow", []string{"gateway:/api"}, false)
+	defer base.SetRuleResourcesOf("flow", nil, false)
+	assert.Nil(t, check("gateway:/api"))
+
+	assert.Nil(t, ClearRules())
+	assert.False(t, base.ResourceHasRules("gateway:/other"))
+}
@@ -0,0 +1,68 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Decision is the default decision for the resources that don't match any rules.
+type Decision int32
+
+const (
+	// Allow passes the requests of the unmatched resources.
+	Allow Decision = iota
+	// Deny blocks the requests of the unmatched resources, unless they're in the allowlist.
+	Deny
+)
+
+func (d Decision) String() string {
+	switch d {
+	case Allow:
+		return "Allow"
+	case Deny:
+		return "Deny"
+	default:
+		return "Undefined"
+	}
+}
+
+// Rule describes the default policy of the resources in the namespace.
+type Rule struct {
+	// ID represents the unique ID of the rule (optional).
+	ID string `json:"id,omitempty"`
+	// Namespace is the prefix of the resource names that the policy applies to, empty means the global policy.
+	// If multiple namespaces match a resource, the longest one takes effect.
+	Namespace string `json:"namespace"`
+	// Default is the decision for the unmatched resources.
+	Default Decision `json:"default"`
+	// Allowlist is the resources that are always allowed for Deny policy.
+	// The item ending with "*" matches the resources with the prefix.
+	Allowlist []string `json:"allowlist"`
+}
+
+func (r *Rule) String() string {
+	b, err := json.Marshal(r)
+	if err != nil {
+		// Return the fallback string
+		return fmt.Sprintf("{Id=%s, Namespace=%s, Default=%s, Allowlist=%v}", r.ID, r.Namespace, r.Default, r.Allowlist)
+	}
+	return string(b)
+}
+
+func (r *Rule) ResourceName() string {
+	return r.Namespace
+}
+
+func (r *Rule) isAllowlisted(resource string) bool {
+	for _, item := range r.Allowlist {
+		if strings.HasSuffix(item, "*") {
+			if strings.HasPrefix(resource, strings.TrimSuffix(item, "*")) {
+				return true
+			}
+		} else if item == resource {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package policy
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ruleMap is the map of namespace to the policy rule.
+	ruleMap = make(map[string]*Rule)
+	rwMux   = &sync.RWMutex{}
+)
+
+// LoadRules loads the given policy rules to the rule manager, while all previous rules will be replaced.
+// Only one rule is allowed for each namespace, and the latter rules of the same namespace will be ignored.
+func LoadRules(rules []*Rule) (bool, error) {
+	defer selfmetric.RecordRuleUpdate("policy", time.Now())
+
+	m := make(map[string]*Rule, len(rules))
+	for _, r := range rules {
+		if err := IsValidRule(r); err != nil {
+			logging.Warn("Ignoring invalid policy rule", "rule", r, "reason", err)
+			continue
+		}
+		if _, exist := m[r.Namespace]; exist {
+			logging.Warn("Ignoring duplicate policy rule of the namespace", "rule", r)
+			continue
+		}
+		m[r.Namespace] = r
+	}
+
+	start := util.CurrentTimeNano()
+	rwMux.Lock()
+	defer func() {
+		rwMux.Unlock()
+		logging.Debug("time statistic(ns) for updating policy rule", "timeCost", util.CurrentTimeNano()-start)
+		logRuleUpdate(m)
+	}()
+	ruleMap = m
+	hasDeny := false
+	for _, r := range m {
+		hasDeny = hasDeny || r.Default == Deny
+	}
+	// The rule checking of the resources without rules couldn't be skipped if any resource may be denied.
+	base.SetRuleResourcesOf("policy", nil, hasDeny)
+	return true, nil
+}
+
+// ClearRules clears all the rules in policy module, which means allowing all the unmatched resources.
+func ClearRules() error {
+	_, err := LoadRules(nil)
+	return err
+}
+
+// GetRules returns all the rules based on copy.
+// It doesn't take effect for policy module if user changes the rule.
+func GetRules() []Rule {
+	rwMux.RLock()
+	defer rwMux.RUnlock()
+
+	ret := make([]Rule, 0, len(ruleMap))
+	for _, r := range ruleMap {
+		ret = append(ret, *r)
+	}
+	return ret
+}
+
+// ruleOf returns the rule of the longest namespace matching the resource, or nil if there is no such rule.
+func ruleOf(resource string) *Rule {
+	rwMux.RLock()
+	defer rwMux.RUnlock()
+
+	var ret *Rule
+	for ns, r := range ruleMap {
+		if strings.HasPrefix(resource, ns) && (ret == nil || len(ns) > len(ret.Namespace)) {
+			ret = r
+		}
+	}
+	return ret
+}
+
+func logRuleUpdate(m map[string]*Rule) {
+	rs := make([]*Rule, 0, len(m))
+	for _, r := range m {
+		rs = append(rs, r)
+	}
+	if len(rs) == 0 {
+		logging.Info("[PolicyRuleManager] Policy rules were cleared")
+	} else {
+		logging.Info("[PolicyRuleManager] Policy rules were loaded", "rules", rs)
+	}
+}
+
+// IsValidRule checks whether the given rule is valid.
+func IsValidRule(r *Rule) error {
+	if r == nil {
+		return errors.New("nil Rule")
+	}
+	if r.Default != Allow && r.Default != Deny {
+		return errors.New("invalid default decision")
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package policy
+
+import (
+	"github.com/alibaba/sentinel-golang/core/base"
+)
+
+// Slot applies the default policy to the resources that don't match any rules.
+// Slot should be the last rule check slot.
+type Slot struct {
+}
+
+// RulesIndexed implements base.IndexedRuleCheckSlot, as Deny policy is registered to the rule resource index
+// as the rule applying to all the resources.
+func (s *Slot) RulesIndexed() bool {
+	return true
+}
+
+func (s *Slot) Check(ctx *base.EntryContext) *base.TokenResult {
+	result := ctx.RuleCheckResult
+	res := ctx.Resource.Name()
+	r := ruleOf(res)
+	if r == nil || r.Default == Allow || r.isAllowlisted(res) || base.ResourceHasExplicitRules(res) {
+		return result
+	}
+	if result == nil {
+		result = base.NewTokenResultBlockedWithCause(base.BlockTypeDefaultDeny, "denied by default policy", r, nil)
+	} else {
+		result.ResetToBlockedWithCause(base.BlockTypeDefaultDeny, "denied by default policy", r, nil)
+	}
+	return result
+}
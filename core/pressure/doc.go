@@ -0,0 +1,21 @@
+// Package pressure computes the pressure score of the resources, which combines the utilization against the limits,
+// the queueing delay and the shed rate into a single number, so that it could be consumed by the autoscalers
+// (e.g. as the external metric of Kubernetes HPA or KEDA) for the load-aware autoscaling.
+//
+// The score of a resource is:
+//
+//	Score = max(Utilization, QueueingRatio) + ShedRate
+//
+// where:
+//
+//  1. Utilization is the max ratio of the passed requests to the effective threshold among the flow rules
+//     of the resource, in the statistic interval of each rule;
+//  2. QueueingRatio is the max ratio of the current queueing delay to the max queueing time among the
+//     Throttling flow rules of the resource;
+//  3. ShedRate is the ratio of the blocked requests to all the requests of the resource in the last second.
+//
+// So the score below 1 means the resource is within its limits, and the score above 1 means the requests are being
+// shed (or queued to the max) and more capacity is needed, e.g. the HPA target could be 0.8 to scale out
+// before the limits are hit. The global score is the max score of all the resources, as the hottest resource
+// drives the scaling.
+package pressure
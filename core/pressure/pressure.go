@@ -0,0 +1,105 @@
+package pressure
+
+import (
+	"math"
+	"sort"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/stat"
+)
+
+// Score is the pressure score of a resource, or the global pressure score if Resource is empty.
+type Score struct {
+	Resource      string  `json:"resource,omitempty"`
+	Score         float64 `json:"score"`
+	Utilization   float64 `json:"utilization"`
+	QueueingRatio float64 `json:"queueingRatio"`
+	ShedRate      float64 `json:"shedRate"`
+}
+
+// ResourceScore returns the pressure score of the resource, false if there are neither flow rules
+// nor statistics of the resource.
+func ResourceScore(resource string) (Score, bool) {
+	tcs := flow.TrafficControllersFor(resource)
+	node := stat.GetResourceNode(resource)
+	if len(tcs) == 0 && node == nil {
+		return Score{}, false
+	}
+	s := Score{Resource: resource}
+	for _, tc := range tcs {
+		s.Utilization = math.Max(s.Utilization, utilizationOf(tc))
+		s.QueueingRatio = math.Max(s.QueueingRatio, queueingRatioOf(tc))
+	}
+	if node != nil {
+		s.ShedRate = shedRateOf(node)
+	}
+	s.Score = math.Max(s.Utilization, s.QueueingRatio) + s.ShedRate
+	return s, true
+}
+
+// Scores returns the pressure scores of all the resources with flow rules or statistics, ordered by the resources.
+func Scores() []Score {
+	resources := make(map[string]struct{})
+	for _, r := range flow.GetRules() {
+		if len(r.Resource) > 0 {
+			resources[r.Resource] = struct{}{}
+		}
+	}
+	for _, node := range stat.ResourceNodeList() {
+		resources[node.ResourceName()] = struct{}{}
+	}
+	ret := make([]Score, 0, len(resources))
+	for res := range resources {
+		if s, ok := ResourceScore(res); ok {
+			ret = append(ret, s)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Resource < ret[j].Resource
+	})
+	return ret
+}
+
+// GlobalScore returns the global pressure score, whose Score, Utilization and QueueingRatio are the max of
+// all the resources, and ShedRate is the shed rate of all the inbound requests.
+func GlobalScore() Score {
+	return GlobalScoreOf(Scores())
+}
+
+// GlobalScoreOf returns the global pressure score of the resource scores, see GlobalScore.
+func GlobalScoreOf(scores []Score) Score {
+	g := Score{}
+	for _, s := range scores {
+		g.Score = math.Max(g.Score, s.Score)
+		g.Utilization = math.Max(g.Utilization, s.Utilization)
+		g.QueueingRatio = math.Max(g.QueueingRatio, s.QueueingRatio)
+	}
+	g.ShedRate = shedRateOf(stat.InboundNode())
+	return g
+}
+
+func utilizationOf(tc *flow.TrafficShapingController) float64 {
+	threshold := tc.CurrentThreshold()
+	if threshold <= 0 {
+		return 0
+	}
+	return float64(tc.CurrentPassCount()) / threshold
+}
+
+func queueingRatioOf(tc *flow.TrafficShapingController) float64 {
+	d, ok := tc.ThrottlingDiagnostics()
+	if !ok || d.MaxQueueingTime <= 0 {
+		return 0
+	}
+	return math.Min(float64(d.QueueingDelay)/float64(d.MaxQueueingTime), 1)
+}
+
+func shedRateOf(node base.StatNode) float64 {
+	pass := node.GetQPS(base.MetricEventPass)
+	block := node.GetQPS(base.MetricEventBlock)
+	if pass+block <= 0 {
+		return 0
+	}
+	return block / (pass + block)
+}
@@ -0,0 +1,75 @@
+package pressure
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceScore(t *testing.T) {
+	defer flow.ClearRules()
+	_, err := flow.LoadRules([]*flow.Rule{
+		{
+			Resource:               "pressure-abc",
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			Threshold:              10,
+			// use the standalone statistic written by StandaloneStatSlot
+			StatIntervalInMs: 20000,
+		},
+		{
+			Resource:               "pressure-def",
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Throttling,
+			Threshold:              1,
+			MaxQueueingTimeMs:      10000,
+		},
+	})
+	assert.Nil(t, err)
+
+	node := stat.GetOrCreateResourceNode("pressure-abc", base.ResTypeCommon)
+	ctx := &base.EntryContext{
+		Resource: base.NewResourceWrapper("pressure-abc", base.ResTypeCommon, base.Inbound),
+		StatNode: node,
+		Input:    &base.SentinelInput{AcquireCount: 5},
+	}
+	(&flow.StandaloneStatSlot{}).OnEntryPassed(ctx)
+	node.AddCount(base.MetricEventPass, 3)
+	node.AddCount(base.MetricEventBlock, 1)
+
+	s, ok := ResourceScore("pressure-abc")
+	assert.True(t, ok)
+	assert.InDelta(t, 0.5, s.Utilization, 0.001)
+	assert.Equal(t, float64(0), s.QueueingRatio)
+	assert.InDelta(t, 0.25, s.ShedRate, 0.001)
+	assert.InDelta(t, 0.75, s.Score, 0.001)
+
+	// queue up 3 requests at 1 QPS, the last of which waits for about 2s of the 10s max queueing time
+	c := flow.TrafficControllersFor("pressure-def")[0].FlowChecker().(*flow.ThrottlingChecker)
+	for i := 0; i < 3; i++ {
+		c.DoCheck(nil, 1, 1)
+	}
+	s, ok = ResourceScore("pressure-def")
+	assert.True(t, ok)
+	assert.InDelta(t, 0.2, s.QueueingRatio, 0.05)
+	assert.InDelta(t, 0.2, s.Score, 0.05)
+
+	_, ok = ResourceScore("pressure-absent")
+	assert.False(t, ok)
+
+	scores := Scores()
+	resources := make([]string, 0, len(scores))
+	for _, s := range scores {
+		resources = append(resources, s.Resource)
+	}
+	assert.Contains(t, resources, "pressure-abc")
+	assert.Contains(t, resources, "pressure-def")
+
+	g := GlobalScore()
+	assert.Equal(t, "", g.Resource)
+	assert.InDelta(t, 0.75, g.Score, 0.001)
+	assert.InDelta(t, 0.5, g.Utilization, 0.001)
+}
@@ -0,0 +1,26 @@
+// Package quota provides the per-tenant quota management and usage accounting.
+//
+// The tenant of an entry is carried by the entry attachment with the key TenantAttachmentKey. Each quota rule
+// assigns the QPS and/or concurrency quota to a tenant, for a specific resource or all the resources
+// (if the Resource of the rule is empty). The entries beyond the quota are blocked with BlockTypeQuota.
+//
+// The usage (passed, blocked and completed requests) of the tenants with quota rules is recorded over the
+// last minute, which could be queried by UsageOf and Usages for billing or monitoring purposes.
+//
+// Here is the example code to use the quota:
+//
+//	_, err := quota.LoadRules([]*quota.Rule{
+//	    {
+//	        Tenant:           "tenant-a",
+//	        QpsLimit:         100,
+//	        ConcurrencyLimit: 10,
+//	    },
+//	})
+//	...
+//	e, b := sentinel.Entry("some-api", sentinel.WithAttachment(quota.TenantAttachmentKey, "tenant-a"))
+//	if b != nil {
+//	    // Blocked, the quota of tenant-a may be exhausted.
+//	}
+//	...
+//	usage := quota.UsageOf("tenant-a", 10000) // usage of the last 10 seconds.
+package quota
@@ -0,0 +1,87 @@
+package quota
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func newContext(resource, tenant string) *base.EntryContext {
+	return &base.EntryContext{
+		Resource: base.NewResourceWrapper(resource, base.ResTypeCommon, base.Inbound),
+		Input: &base.SentinelInput{
+			AcquireCount: 1,
+			Attachments:  map[interface{}]interface{}{TenantAttachmentKey: tenant},
+		},
+	}
+}
+
+func TestIsValidRule(t *testing.T) {
+	assert.NotNil(t, IsValidRule(nil))
+	assert.NotNil(t, IsValidRule(&Rule{QpsLimit: 10}))
+	assert.NotNil(t, IsValidRule(&Rule{Tenant: "a"}))
+	assert.NotNil(t, IsValidRule(&Rule{Tenant: "a", QpsLimit: -1}))
+	assert.Nil(t, IsValidRule(&Rule{Tenant: "a", ConcurrencyLimit: 1}))
+}
+
+func TestLoadRules(t *testing.T) {
+	defer ClearRules()
+
+	_, err := LoadRules([]*Rule{
+		{Tenant: "a", QpsLimit: 10},
+		{Tenant: "a", Resource: "abc", ConcurrencyLimit: 1},
+		{Tenant: "b"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(GetRules()))
+	assert.Equal(t, 2, len(GetRulesOfTenant("a")))
+	assert.Nil(t, UsageOf("b", 1000))
+
+	tracker := getTrackersOf("a")[0]
+	_, err = LoadRules([]*Rule{{Tenant: "a", QpsLimit: 10}})
+	assert.Nil(t, err)
+	assert.True(t, tracker == getTrackersOf("a")[0])
+}
+
+func TestSlot(t *testing.T) {
+	defer ClearRules()
+
+	_, err := LoadRules([]*Rule{
+		{Tenant: "a", QpsLimit: 3},
+		{Tenant: "a", Resource: "abc", ConcurrencyLimit: 1},
+	})
+	assert.Nil(t, err)
+
+	slot, statSlot := &Slot{}, &MetricStatSlot{}
+	ctx := newContext("abc", "a")
+	assert.Nil(t, slot.Check(ctx))
+	statSlot.OnEntryPassed(ctx)
+
+	// The concurrency quota of resource abc is exhausted.
+	r := slot.Check(newContext("abc", "a"))
+	assert.True(t, r.IsBlocked())
+	assert.Equal(t, base.BlockTypeQuota, r.BlockError().BlockType())
+	statSlot.OnEntryBlocked(newContext("abc", "a"), r.BlockError())
+	statSlot.OnCompleted(ctx)
+
+	// The QPS quota is shared among the resources.
+	for i := 0; i < 2; i++ {
+		ctx = newContext("def", "a")
+		assert.Nil(t, slot.Check(ctx))
+		statSlot.OnEntryPassed(ctx)
+		statSlot.OnCompleted(ctx)
+	}
+	assert.True(t, slot.Check(newContext("def", "a")).IsBlocked())
+	// Other tenants are not limited.
+	assert.Nil(t, slot.Check(newContext("def", "b")))
+	assert.Nil(t, slot.Check(newContext("def", "")))
+
+	usage := UsageOf("a", 10000)
+	assert.Equal(t, "a", usage.Tenant)
+	assert.Equal(t, int64(3), usage.Passed)
+	assert.Equal(t, int64(1), usage.Blocked)
+	assert.Equal(t, int64(3), usage.Completed)
+	assert.Equal(t, int64(0), usage.Concurrency)
+	assert.Equal(t, 1, len(Usages(UsageIntervalInMs)))
+}
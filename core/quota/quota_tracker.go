@@ -0,0 +1,118 @@
+package quota
+
+import (
+	"sync/atomic"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	sbase "github.com/alibaba/sentinel-golang/core/stat/base"
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+const (
+	qpsSampleCount  = 2
+	qpsIntervalInMs = 1000
+
+	// UsageIntervalInMs is the longest time window of usage accounting.
+	UsageIntervalInMs = 60000
+	usageSampleCount  = 60
+)
+
+// quotaTracker tracks the consumption of the quota rule.
+type quotaTracker struct {
+	rule        *Rule
+	qps         *sbase.BucketLeapArray
+	concurrency int64
+}
+
+func newQuotaTracker(r *Rule) *quotaTracker {
+	return &quotaTracker{
+		rule: r,
+		qps:  sbase.NewBucketLeapArray(qpsSampleCount, qpsIntervalInMs),
+	}
+}
+
+// tryPass checks whether the acquireCount requests could pass within the quota.
+func (t *quotaTracker) tryPass(acquireCount uint32) (bool, interface{}) {
+	if t.rule.ConcurrencyLimit > 0 {
+		if cur := atomic.LoadInt64(&t.concurrency); cur+1 > t.rule.ConcurrencyLimit {
+			return false, cur
+		}
+	}
+	if t.rule.QpsLimit > 0 {
+		cur := float64(t.qps.Count(base.MetricEventPass)) / t.qps.GetIntervalInSecond()
+		if cur+float64(acquireCount) > t.rule.QpsLimit {
+			return false, cur
+		}
+	}
+	return true, nil
+}
+
+func (t *quotaTracker) onPassed(acquireCount uint32) {
+	t.qps.AddCount(base.MetricEventPass, int64(acquireCount))
+	atomic.AddInt64(&t.concurrency, 1)
+}
+
+func (t *quotaTracker) onCompleted() {
+	atomic.AddInt64(&t.concurrency, -1)
+}
+
+// Usage is the consumption of a tenant within a time window.
+type Usage struct {
+	Tenant string `json:"tenant"`
+	// WindowMs is the time window of the usage.
+	WindowMs  uint32 `json:"windowMs"`
+	Passed    int64  `json:"passed"`
+	Blocked   int64  `json:"blocked"`
+	Completed int64  `json:"completed"`
+	// Concurrency is the current concurrency of the tenant.
+	Concurrency int64 `json:"concurrency"`
+}
+
+// tenantUsage records the usage of a tenant over the last UsageIntervalInMs.
+type tenantUsage struct {
+	tenant      string
+	data        *sbase.BucketLeapArray
+	concurrency int64
+}
+
+func newTenantUsage(tenant string) *tenantUsage {
+	return &tenantUsage{
+		tenant: tenant,
+		data:   sbase.NewBucketLeapArray(usageSampleCount, UsageIntervalInMs),
+	}
+}
+
+func (u *tenantUsage) add(event base.MetricEvent, count int64) {
+	u.data.AddCount(event, count)
+	switch event {
+	case base.MetricEventPass:
+		atomic.AddInt64(&u.concurrency, 1)
+	case base.MetricEventComplete:
+		atomic.AddInt64(&u.concurrency, -1)
+	}
+}
+
+func (u *tenantUsage) usageOf(windowMs uint32) *Usage {
+	now := util.CurrentTimeMillis()
+	ret := &Usage{
+		Tenant:      u.tenant,
+		WindowMs:    windowMs,
+		Concurrency: atomic.LoadInt64(&u.concurrency),
+	}
+	bucketLengthInMs := uint64(u.data.BucketLengthInMs())
+	// The current bucket is always included.
+	startTime := now - now%bucketLengthInMs + bucketLengthInMs - uint64(windowMs)
+	buckets := u.data.ValuesConditional(now, func(ts uint64) bool {
+		return ts >= startTime
+	})
+	for _, bw := range buckets {
+		mb, ok := bw.Value.Load().(*sbase.MetricBucket)
+		if !ok {
+			continue
+		}
+		ret.Passed += mb.Get(base.MetricEventPass)
+		ret.Blocked += mb.Get(base.MetricEventBlock)
+		ret.Completed += mb.Get(base.MetricEventComplete)
+	}
+	return ret
+}
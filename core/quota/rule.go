@@ -0,0 +1,47 @@
+package quota
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Rule assigns the quota of a tenant.
+type Rule struct {
+	// ID represents the unique ID of the rule (optional).
+	ID string `json:"id,omitempty"`
+	// Tenant is the tenant that the quota is assigned to.
+	Tenant string `json:"tenant"`
+	// Resource is the resource that the quota applies to, empty means all the resources of the tenant,
+	// in which case the quota is shared among the resources.
+	Resource string `json:"resource,omitempty"`
+	// QpsLimit is the max QPS of the tenant, 0 means no limit.
+	QpsLimit float64 `json:"qpsLimit"`
+	// ConcurrencyLimit is the max concurrency of the tenant, 0 means no limit.
+	ConcurrencyLimit int64 `json:"concurrencyLimit"`
+}
+
+func (r *Rule) String() string {
+	b, err := json.Marshal(r)
+	if err != nil {
+		// Return the fallback string
+		return fmt.Sprintf("{Id=%s, Tenant=%s, Resource=%s, QpsLimit=%.2f, ConcurrencyLimit=%d}",
+			r.ID, r.Tenant, r.Resource, r.QpsLimit, r.ConcurrencyLimit)
+	}
+	return string(b)
+}
+
+func (r *Rule) ResourceName() string {
+	return r.Resource
+}
+
+func (r *Rule) isEqualsTo(newRule *Rule) bool {
+	if newRule == nil {
+		return false
+	}
+	return r.Tenant == newRule.Tenant && r.Resource == newRule.Resource && r.QpsLimit == newRule.QpsLimit &&
+		r.ConcurrencyLimit == newRule.ConcurrencyLimit
+}
+
+func (r *Rule) appliesTo(resource string) bool {
+	return len(r.Resource) == 0 || r.Resource == resource
+}
@@ -0,0 +1,213 @@
+package quota
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+var (
+	// trackerMap is the map of tenant to the quota trackers.
+	trackerMap = make(map[string][]*quotaTracker)
+	usageMap   = make(map[string]*tenantUsage)
+	updateMux  = new(sync.RWMutex)
+)
+
+// LoadRules loads the given quota rules to the rule manager, while all previous rules will be replaced.
+// The consumption of the unchanged rules and the usage of the remaining tenants are retained.
+func LoadRules(rules []*Rule) (bool, error) {
+	defer selfmetric.RecordRuleUpdate("quota", time.Now())
+
+	tenantRules := make(map[string][]*Rule)
+	for _, r := range rules {
+		if err := IsValidRule(r); err != nil {
+			logging.Warn("Ignoring invalid quota rule", "rule", r, "reason", err)
+			continue
+		}
+		tenantRules[r.Tenant] = append(tenantRules[r.Tenant], r)
+	}
+
+	m := make(map[string][]*quotaTracker, len(tenantRules))
+	um := make(map[string]*tenantUsage, len(tenantRules))
+	start := util.CurrentTimeNano()
+	updateMux.Lock()
+	defer func() {
+		updateMux.Unlock()
+		logging.Debug("time statistic(ns) for updating quota rule", "timeCost", util.CurrentTimeNano()-start)
+		logRuleUpdate(m)
+	}()
+	for tenant, rs := range tenantRules {
+		oldTrackers := trackerMap[tenant]
+		trackers := make([]*quotaTracker, 0, len(rs))
+		for _, r := range rs {
+			var reused *quotaTracker
+			for _, old := range oldTrackers {
+				if old.rule.isEqualsTo(r) {
+					reused = old
+					break
+				}
+			}
+			if reused == nil {
+				reused = newQuotaTracker(r)
+			}
+			trackers = append(trackers, reused)
+		}
+		m[tenant] = trackers
+		if u, exist := usageMap[tenant]; exist {
+			um[tenant] = u
+		} else {
+			um[tenant] = newTenantUsage(tenant)
+		}
+	}
+	trackerMap = m
+	resources, applyToAll := make([]string, 0), false
+	for _, trackers := range m {
+		for _, t := range trackers {
+			if len(t.rule.Resource) == 0 {
+				applyToAll = true
+			} else {
+				resources = append(resources, t.rule.Resource)
+			}
+		}
+	}
+	base.SetRuleResourcesOf("quota", resources, applyToAll)
+	usageMap = um
+	return true, nil
+}
+
+// ClearRules clears all the rules in quota module.
+func ClearRules() error {
+	_, err := LoadRules(nil)
+	return err
+}
+
+// GetRules returns all the rules based on copy.
+// It doesn't take effect for quota module if user changes the rule.
+func GetRules() []Rule {
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+
+	ret := make([]Rule, 0, len(trackerMap))
+	for _, trackers := range trackerMap {
+		for _, t := range trackers {
+			ret = append(ret, *t.rule)
+		}
+	}
+	return ret
+}
+
+// GetRulesOfTenant returns the rules of the tenant based on copy.
+func GetRulesOfTenant(tenant string) []Rule {
+	trackers := getTrackersOf(tenant)
+	ret := make([]Rule, 0, len(trackers))
+	for _, t := range trackers {
+		ret = append(ret, *t.rule)
+	}
+	return ret
+}
+
+// UsageOf returns the usage of the tenant within the last windowMs, which is rounded to the statistic buckets
+// (1 second) and at most UsageIntervalInMs. It returns nil if the tenant has no quota rules.
+func UsageOf(tenant string, windowMs uint32) *Usage {
+	updateMux.RLock()
+	u := usageMap[tenant]
+	updateMux.RUnlock()
+	if u == nil {
+		return nil
+	}
+	return u.usageOf(normalizeWindow(windowMs))
+}
+
+// Usages returns the usages of all the tenants with quota rules within the last windowMs.
+func Usages(windowMs uint32) []*Usage {
+	updateMux.RLock()
+	usages := make([]*tenantUsage, 0, len(usageMap))
+	for _, u := range usageMap {
+		usages = append(usages, u)
+	}
+	updateMux.RUnlock()
+
+	windowMs = normalizeWindow(windowMs)
+	ret := make([]*Usage, 0, len(usages))
+	for _, u := range usages {
+		ret = append(ret, u.usageOf(windowMs))
+	}
+	return ret
+}
+
+func normalizeWindow(windowMs uint32) uint32 {
+	bucketLengthInMs := uint32(UsageIntervalInMs / usageSampleCount)
+	if windowMs < bucketLengthInMs {
+		return bucketLengthInMs
+	}
+	if windowMs > UsageIntervalInMs {
+		return UsageIntervalInMs
+	}
+	return windowMs
+}
+
+func getTrackersOf(tenant string) []*quotaTracker {
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+
+	return trackerMap[tenant]
+}
+
+func getUsageOf(tenant string) *tenantUsage {
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+
+	return usageMap[tenant]
+}
+
+// checkPass checks the quota of the tenant for the resource.
+func checkPass(tenant, resource string, acquireCount uint32) (bool, *Rule, interface{}) {
+	for _, t := range getTrackersOf(tenant) {
+		if !t.rule.appliesTo(resource) {
+			continue
+		}
+		if passed, snapshot := t.tryPass(acquireCount); !passed {
+			return false, t.rule, snapshot
+		}
+	}
+	return true, nil, nil
+}
+
+func logRuleUpdate(m map[string][]*quotaTracker) {
+	rs := make([]*Rule, 0, len(m))
+	for _, trackers := range m {
+		for _, t := range trackers {
+			rs = append(rs, t.rule)
+		}
+	}
+	if len(rs) == 0 {
+		logging.Info("[QuotaRuleManager] Quota rules were cleared")
+	} else {
+		logging.Info("[QuotaRuleManager] Quota rules were loaded", "rules", rs)
+	}
+}
+
+// IsValidRule checks whether the given rule is valid.
+func IsValidRule(r *Rule) error {
+	if r == nil {
+		return errors.New("nil Rule")
+	}
+	if len(r.Tenant) == 0 {
+		return errors.New("empty tenant")
+	}
+	if r.QpsLimit < 0 {
+		return errors.New("negative QpsLimit")
+	}
+	if r.ConcurrencyLimit < 0 {
+		return errors.New("negative ConcurrencyLimit")
+	}
+	if r.QpsLimit == 0 && r.ConcurrencyLimit == 0 {
+		return errors.New("neither QpsLimit nor ConcurrencyLimit is set")
+	}
+	return nil
+}
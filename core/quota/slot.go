@@ -0,0 +1,88 @@
+package quota
+
+import (
+	"github.com/alibaba/sentinel-golang/core/base"
+)
+
+// TenantAttachmentKey is the key of the entry attachment that carries the tenant of the request.
+const TenantAttachmentKey = "sentinel.quota.tenant"
+
+func tenantOf(ctx *base.EntryContext) string {
+	if ctx.Input == nil || ctx.Input.Attachments == nil {
+		return ""
+	}
+	tenant, _ := ctx.Input.Attachments[TenantAttachmentKey].(string)
+	return tenant
+}
+
+// Slot checks the quota of the tenant of the entry.
+type Slot struct {
+}
+
+// RulesIndexed implements base.IndexedRuleCheckSlot, as all the rules are registered to the rule resource index.
+func (s *Slot) RulesIndexed() bool {
+	return true
+}
+
+func (s *Slot) Check(ctx *base.EntryContext) *base.TokenResult {
+	result := ctx.RuleCheckResult
+	tenant := tenantOf(ctx)
+	if len(tenant) == 0 {
+		return result
+	}
+	if passed, rule, snapshot := checkPass(tenant, ctx.Resource.Name(), ctx.Input.AcquireCount); !passed {
+		if result == nil {
+			result = base.NewTokenResultBlockedWithCause(base.BlockTypeQuota, "quota exceeded", rule, snapshot)
+		} else {
+			result.ResetToBlockedWithCause(base.BlockTypeQuota, "quota exceeded", rule, snapshot)
+		}
+	}
+	return result
+}
+
+// MetricStatSlot records the quota consumption and the usage of the tenant.
+// MetricStatSlot must be filled into slot chain if quota is alive.
+type MetricStatSlot struct {
+}
+
+func (s *MetricStatSlot) OnEntryPassed(ctx *base.EntryContext) {
+	tenant := tenantOf(ctx)
+	if len(tenant) == 0 {
+		return
+	}
+	res := ctx.Resource.Name()
+	for _, t := range getTrackersOf(tenant) {
+		if t.rule.appliesTo(res) {
+			t.onPassed(ctx.Input.AcquireCount)
+		}
+	}
+	if u := getUsageOf(tenant); u != nil {
+		u.add(base.MetricEventPass, int64(ctx.Input.AcquireCount))
+	}
+}
+
+func (s *MetricStatSlot) OnEntryBlocked(ctx *base.EntryContext, _ *base.BlockError) {
+	tenant := tenantOf(ctx)
+	if len(tenant) == 0 {
+		return
+	}
+	if u := getUsageOf(tenant); u != nil {
+		u.add(base.MetricEventBlock, int64(ctx.Input.AcquireCount))
+	}
+}
+
+func (s *MetricStatSlot) OnCompleted(ctx *base.EntryContext) {
+	tenant := tenantOf(ctx)
+	if len(tenant) == 0 {
+		return
+	}
+	res := ctx.Resource.Name()
+	for _, t := range getTrackersOf(tenant) {
+		if t.rule.appliesTo(res) {
+			t.onCompleted()
+		}
+	}
+	if u := getUsageOf(tenant); u != nil {
+		u.add(base.MetricEventComplete, int64(ctx.Input.AcquireCount))
+	}
+}
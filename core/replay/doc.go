@@ -0,0 +1,25 @@
+// Package replay provides the record-and-replay of the production traffic profiles, so that the rule changes
+// could be validated against the real traffic shape (e.g. yesterday's traffic) before being rolled out.
+//
+// The recorder samples the per-second request counts (passed and blocked, i.e. the offered load)
+// and the RT distributions of the resources into a compact text file:
+//
+//	err := replay.StartRecording("/tmp/traffic.profile", "some-api")
+//	...
+//	err = replay.StopRecording()
+//
+// The RecordSlot must be filled into the slot chain for recording, which is included in the default slot chain.
+//
+// The replayer feeds the recorded profile into the entries under a virtual clock (see util.SetClock),
+// which simulates the traffic second by second with the recorded RT, and reports the passed and blocked
+// requests under current rules:
+//
+//	profile, err := replay.LoadProfile("/tmp/traffic.profile")
+//	...
+//	results := replay.Replay(profile, func(res string) (*base.SentinelEntry, *base.BlockError) {
+//	    return sentinel.Entry(res, sentinel.WithTrafficType(base.Inbound))
+//	})
+//
+// Note that the replay moves the virtual clock forward and the clock is reset to the real clock afterwards,
+// so it must run in a dedicated validation process (or test) rather than the process serving the real traffic.
+package replay
@@ -0,0 +1,171 @@
+package replay
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RtBucketBoundsMs are the upper bounds (inclusive, in milliseconds) of the RT histogram buckets.
+// The last bucket of the histogram holds the RT beyond the last bound.
+var RtBucketBoundsMs = []uint64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000}
+
+const profileHeader = "# sentinel traffic profile v1"
+
+// Sample is the traffic of a resource in one second.
+type Sample struct {
+	// Timestamp is the Unix timestamp in seconds.
+	Timestamp uint64
+	Resource  string
+	// Requests is the amount of the offered requests, i.e. passed and blocked.
+	Requests uint64
+	// RtHistogram is the amount of the completed requests in the RT buckets, see RtBucketBoundsMs.
+	RtHistogram []uint64
+}
+
+// Profile is the recorded traffic profile, whose samples are sorted by the timestamp and the resource.
+type Profile struct {
+	Samples []*Sample
+}
+
+func newRtHistogram() []uint64 {
+	return make([]uint64, len(RtBucketBoundsMs)+1)
+}
+
+func rtBucketOf(rtMs uint64) int {
+	return sort.Search(len(RtBucketBoundsMs), func(i int) bool {
+		return rtMs <= RtBucketBoundsMs[i]
+	})
+}
+
+// rtOfBucket returns the representative RT of the bucket, i.e. the upper bound.
+func rtOfBucket(idx int) uint64 {
+	if idx < len(RtBucketBoundsMs) {
+		return RtBucketBoundsMs[idx]
+	}
+	return RtBucketBoundsMs[len(RtBucketBoundsMs)-1] * 2
+}
+
+// format formats the sample as a line: timestamp|resource|requests|histogram.
+func (s *Sample) format() string {
+	hist := make([]string, len(s.RtHistogram))
+	for i, c := range s.RtHistogram {
+		hist[i] = strconv.FormatUint(c, 10)
+	}
+	return fmt.Sprintf("%d|%s|%d|%s", s.Timestamp, s.Resource, s.Requests, strings.Join(hist, ","))
+}
+
+func parseSample(line string) (*Sample, error) {
+	// The resource name may contain "|", so the fields are split from both ends.
+	first, last := strings.Index(line, "|"), strings.LastIndex(line, "|")
+	if first < 0 || first == last {
+		return nil, errors.Errorf("invalid sample line: %s", line)
+	}
+	mid := strings.LastIndex(line[:last], "|")
+	if mid <= first {
+		return nil, errors.Errorf("invalid sample line: %s", line)
+	}
+	ts, err := strconv.ParseUint(line[:first], 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid timestamp of sample line: %s", line)
+	}
+	requests, err := strconv.ParseUint(line[mid+1:last], 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid requests of sample line: %s", line)
+	}
+	hist := newRtHistogram()
+	counts := strings.Split(line[last+1:], ",")
+	if len(counts) != len(hist) {
+		return nil, errors.Errorf("invalid RT histogram of sample line: %s", line)
+	}
+	for i, c := range counts {
+		if hist[i], err = strconv.ParseUint(c, 10, 64); err != nil {
+			return nil, errors.Wrapf(err, "invalid RT histogram of sample line: %s", line)
+		}
+	}
+	return &Sample{Timestamp: ts, Resource: line[first+1 : mid], Requests: requests, RtHistogram: hist}, nil
+}
+
+// merge adds the traffic of the other sample of the same second and resource.
+func (s *Sample) merge(other *Sample) {
+	s.Requests += other.Requests
+	for i := range s.RtHistogram {
+		s.RtHistogram[i] += other.RtHistogram[i]
+	}
+}
+
+// LoadProfile loads the traffic profile from the file.
+func LoadProfile(filePath string) (*Profile, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to open the profile: %s", filePath)
+	}
+	defer f.Close()
+	return ReadProfile(f)
+}
+
+// ReadProfile reads the traffic profile from the reader. The samples of the same second
+// and resource (e.g. the late completed requests) are merged.
+func ReadProfile(r io.Reader) (*Profile, error) {
+	type sampleKey struct {
+		ts  uint64
+		res string
+	}
+	samples := make(map[sampleKey]*Sample)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		s, err := parseSample(line)
+		if err != nil {
+			return nil, err
+		}
+		key := sampleKey{ts: s.Timestamp, res: s.Resource}
+		if old, exist := samples[key]; exist {
+			old.merge(s)
+		} else {
+			samples[key] = s
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "fail to read the profile")
+	}
+	p := &Profile{Samples: make([]*Sample, 0, len(samples))}
+	for _, s := range samples {
+		p.Samples = append(p.Samples, s)
+	}
+	sort.Slice(p.Samples, func(i, j int) bool {
+		if p.Samples[i].Timestamp != p.Samples[j].Timestamp {
+			return p.Samples[i].Timestamp < p.Samples[j].Timestamp
+		}
+		return p.Samples[i].Resource < p.Samples[j].Resource
+	})
+	return p, nil
+}
+
+// WriteTo writes the traffic profile to the writer.
+func (p *Profile) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	var total int64
+	n, err := bw.WriteString(profileHeader + "\n")
+	total += int64(n)
+	if err != nil {
+		return total, err
+	}
+	for _, s := range p.Samples {
+		n, err = bw.WriteString(s.format() + "\n")
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, bw.Flush()
+}
@@ -0,0 +1,192 @@
+package replay
+
+import (
+	"bufio"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+// flushDelaySec is the delay of flushing the samples, so that the requests completed later are still counted
+// in the samples of their start second.
+const flushDelaySec = 2
+
+type sampleKey struct {
+	ts  uint64
+	res string
+}
+
+// recorder aggregates the per-second samples of the resources and flushes them to the profile file.
+type recorder struct {
+	file      *os.File
+	writer    *bufio.Writer
+	resources map[string]struct{}
+
+	mux     sync.Mutex
+	samples map[sampleKey]*Sample
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+type recorderHolder struct {
+	r *recorder
+}
+
+var (
+	activeRecorder atomic.Value
+	recorderMux    = new(sync.Mutex)
+)
+
+func init() {
+	activeRecorder.Store(&recorderHolder{})
+}
+
+func currentRecorder() *recorder {
+	return activeRecorder.Load().(*recorderHolder).r
+}
+
+// StartRecording starts recording the traffic profile of the resources (all the resources if empty) to the file.
+// Only one recording is allowed at the same time.
+func StartRecording(filePath string, resources ...string) error {
+	recorderMux.Lock()
+	defer recorderMux.Unlock()
+
+	if currentRecorder() != nil {
+		return errors.New("the traffic profile is being recorded")
+	}
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return errors.Wrapf(err, "fail to open the profile file: %s", filePath)
+	}
+	r := &recorder{
+		file:      f,
+		writer:    bufio.NewWriter(f),
+		resources: make(map[string]struct{}, len(resources)),
+		samples:   make(map[sampleKey]*Sample),
+		stopCh:    make(chan struct{}),
+		doneCh:    make(chan struct{}),
+	}
+	for _, res := range resources {
+		r.resources[res] = struct{}{}
+	}
+	if _, err := r.writer.WriteString(profileHeader + "\n"); err != nil {
+		_ = f.Close()
+		return errors.Wrapf(err, "fail to write the profile file: %s", filePath)
+	}
+	activeRecorder.Store(&recorderHolder{r: r})
+	go util.RunWithRecover(r.flushLoop)
+	logging.Info("[Replay] Start recording the traffic profile", "file", filePath, "resources", resources)
+	return nil
+}
+
+// StopRecording stops current recording, and flushes all the samples to the file.
+func StopRecording() error {
+	recorderMux.Lock()
+	defer recorderMux.Unlock()
+
+	r := currentRecorder()
+	if r == nil {
+		return nil
+	}
+	activeRecorder.Store(&recorderHolder{})
+	close(r.stopCh)
+	<-r.doneCh
+	err := r.flush(^uint64(0))
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	logging.Info("[Replay] Stop recording the traffic profile", "file", r.file.Name())
+	return err
+}
+
+func (r *recorder) flushLoop() {
+	defer close(r.doneCh)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stopCh:
+			return
+		case <-ticker.C:
+			if err := r.flush(util.CurrentTimeMillis()/1000 - flushDelaySec); err != nil {
+				logging.Error(err, "[Replay] Fail to flush the traffic profile")
+			}
+		}
+	}
+}
+
+// flush writes the samples before the given second to the file.
+func (r *recorder) flush(beforeSec uint64) error {
+	r.mux.Lock()
+	flushed := make([]*Sample, 0)
+	for key, s := range r.samples {
+		if key.ts < beforeSec {
+			flushed = append(flushed, s)
+			delete(r.samples, key)
+		}
+	}
+	r.mux.Unlock()
+
+	for _, s := range flushed {
+		if _, err := r.writer.WriteString(s.format() + "\n"); err != nil {
+			return err
+		}
+	}
+	return r.writer.Flush()
+}
+
+func (r *recorder) record(ctx *base.EntryContext, requests uint64, rtMs int64) {
+	res := ctx.Resource.Name()
+	if len(r.resources) > 0 {
+		if _, ok := r.resources[res]; !ok {
+			return
+		}
+	}
+	key := sampleKey{ts: ctx.StartTime() / 1000, res: res}
+	if key.ts == 0 {
+		key.ts = util.CurrentTimeMillis() / 1000
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	s, exist := r.samples[key]
+	if !exist {
+		s = &Sample{Timestamp: key.ts, Resource: res, RtHistogram: newRtHistogram()}
+		r.samples[key] = s
+	}
+	s.Requests += requests
+	if rtMs >= 0 {
+		s.RtHistogram[rtBucketOf(uint64(rtMs))]++
+	}
+}
+
+// RecordSlot records the traffic of the resources if the recording is started.
+// RecordSlot must be filled into slot chain for recording.
+type RecordSlot struct {
+}
+
+func (s *RecordSlot) OnEntryPassed(ctx *base.EntryContext) {
+	if r := currentRecorder(); r != nil {
+		r.record(ctx, 1, -1)
+	}
+}
+
+func (s *RecordSlot) OnEntryBlocked(ctx *base.EntryContext, _ *base.BlockError) {
+	if r := currentRecorder(); r != nil {
+		r.record(ctx, 1, -1)
+	}
+}
+
+func (s *RecordSlot) OnCompleted(ctx *base.EntryContext) {
+	if r := currentRecorder(); r != nil {
+		r.record(ctx, 0, int64(ctx.Rt()))
+	}
+}
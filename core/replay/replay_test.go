@@ -0,0 +1,88 @@
+package replay
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfile_ReadWrite(t *testing.T) {
+	hist := newRtHistogram()
+	hist[rtBucketOf(3)] = 2
+	hist[rtBucketOf(10000)] = 1
+	p := &Profile{Samples: []*Sample{
+		{Timestamp: 100, Resource: "a|b", Requests: 5, RtHistogram: hist},
+		{Timestamp: 101, Resource: "abc", Requests: 1, RtHistogram: newRtHistogram()},
+	}}
+	buf := &bytes.Buffer{}
+	_, err := p.WriteTo(buf)
+	assert.Nil(t, err)
+	// the late completed requests of the same second are merged
+	buf.WriteString("100|a|b|0|0,0,1,0,0,0,0,0,0,0,0,0,0\n")
+
+	loaded, err := ReadProfile(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(loaded.Samples))
+	assert.Equal(t, "a|b", loaded.Samples[0].Resource)
+	assert.Equal(t, uint64(5), loaded.Samples[0].Requests)
+	assert.Equal(t, uint64(3), loaded.Samples[0].RtHistogram[2])
+	assert.Equal(t, uint64(1), loaded.Samples[0].RtHistogram[len(RtBucketBoundsMs)])
+	assert.Equal(t, p.Samples[1], loaded.Samples[1])
+
+	_, err = ReadProfile(bytes.NewBufferString("100|abc|1|0,0\n"))
+	assert.NotNil(t, err)
+	_, err = ReadProfile(bytes.NewBufferString("abc\n"))
+	assert.NotNil(t, err)
+}
+
+func TestSampleRt(t *testing.T) {
+	hist := newRtHistogram()
+	hist[rtBucketOf(5)] = 3
+	hist[rtBucketOf(100)] = 1
+	s := &Sample{Requests: 8, RtHistogram: hist}
+	rts := make([]uint64, 0, 8)
+	for k := uint64(0); k < 8; k++ {
+		rts = append(rts, sampleRt(s, k))
+	}
+	assert.Equal(t, []uint64{5, 5, 5, 5, 5, 5, 100, 100}, rts)
+	assert.Equal(t, uint64(0), sampleRt(&Sample{Requests: 1, RtHistogram: newRtHistogram()}, 0))
+}
+
+func TestRecording(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sentinel-replay")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "traffic.profile")
+
+	assert.Nil(t, StartRecording(file, "abc"))
+	assert.NotNil(t, StartRecording(file))
+
+	slot := &RecordSlot{}
+	newCtx := func(res string, rt uint64) *base.EntryContext {
+		ctx := &base.EntryContext{Resource: base.NewResourceWrapper(res, base.ResTypeCommon, base.Inbound)}
+		ctx.PutRt(rt)
+		return ctx
+	}
+	for i := 0; i < 3; i++ {
+		ctx := newCtx("abc", 30)
+		slot.OnEntryPassed(ctx)
+		slot.OnCompleted(ctx)
+	}
+	slot.OnEntryBlocked(newCtx("abc", 0), nil)
+	slot.OnEntryPassed(newCtx("def", 0))
+	assert.Nil(t, StopRecording())
+	// not recorded after stopped
+	slot.OnEntryPassed(newCtx("abc", 0))
+
+	p, err := LoadProfile(file)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(p.Samples))
+	assert.Equal(t, "abc", p.Samples[0].Resource)
+	assert.Equal(t, uint64(4), p.Samples[0].Requests)
+	assert.Equal(t, uint64(3), p.Samples[0].RtHistogram[rtBucketOf(30)])
+}
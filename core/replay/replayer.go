@@ -0,0 +1,173 @@
+package replay
+
+import (
+	"container/heap"
+	"sync/atomic"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+// EntryFunc creates the entry of the resource for the replay, e.g. based on api.Entry.
+type EntryFunc func(resource string) (*base.SentinelEntry, *base.BlockError)
+
+// Result is the replay result of a sample.
+type Result struct {
+	// Timestamp is the Unix timestamp in seconds of the recorded sample.
+	Timestamp uint64
+	Resource  string
+	Requests  uint64
+	Passed    uint64
+	Blocked   uint64
+}
+
+// replayClock is the virtual clock driven by the replayer.
+type replayClock struct {
+	nowMs uint64
+}
+
+func (c *replayClock) CurrentTimeMillis() uint64 {
+	return atomic.LoadUint64(&c.nowMs)
+}
+
+func (c *replayClock) CurrentTimeNano() uint64 {
+	return atomic.LoadUint64(&c.nowMs) * util.UnixTimeUnitOffset
+}
+
+func (c *replayClock) set(nowMs uint64) {
+	atomic.StoreUint64(&c.nowMs, nowMs)
+}
+
+// pendingExit is the passed entry waiting for completion.
+type pendingExit struct {
+	atMs  uint64
+	entry *base.SentinelEntry
+}
+
+// pendingExits is the min-heap of the pending exits ordered by the completion time.
+type pendingExits []*pendingExit
+
+func (h pendingExits) Len() int            { return len(h) }
+func (h pendingExits) Less(i, j int) bool  { return h[i].atMs < h[j].atMs }
+func (h pendingExits) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *pendingExits) Push(x interface{}) { *h = append(*h, x.(*pendingExit)) }
+func (h *pendingExits) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// replayer simulates the entries and their completions in time order under the virtual clock,
+// so the concurrency and the RT of the resources are reproduced as well.
+type replayer struct {
+	clock   *replayClock
+	entryFn EntryFunc
+	pending pendingExits
+}
+
+// exitUntil exits the pending entries completed no later than the given time.
+func (r *replayer) exitUntil(nowMs uint64) {
+	for len(r.pending) > 0 && r.pending[0].atMs <= nowMs {
+		p := heap.Pop(&r.pending).(*pendingExit)
+		r.clock.set(p.atMs)
+		p.entry.Exit()
+	}
+}
+
+// Replay feeds the traffic profile into the entries created by entryFn second by second under the virtual clock,
+// and returns the results of all the samples. The timeline of the profile is shifted to start from now,
+// and the requests of each sample are spread evenly in its second, which complete with the recorded RT distribution.
+// The clock is reset to the real clock after the replay.
+func Replay(p *Profile, entryFn EntryFunc) []*Result {
+	results := make([]*Result, 0, len(p.Samples))
+	if len(p.Samples) == 0 {
+		return results
+	}
+	startMs := util.CurrentTimeMillis()
+	startMs = startMs - startMs%1000 + 1000
+	offsetMs := startMs - p.Samples[0].Timestamp*1000
+
+	r := &replayer{clock: &replayClock{nowMs: startMs}, entryFn: entryFn}
+	util.SetClock(r.clock)
+	defer util.SetClock(nil)
+
+	// The samples of the same second are interleaved, as the real traffic of different resources.
+	for i := 0; i < len(p.Samples); {
+		j := i
+		for j < len(p.Samples) && p.Samples[j].Timestamp == p.Samples[i].Timestamp {
+			j++
+		}
+		results = append(results, r.replaySecond(p.Samples[i:j], p.Samples[i].Timestamp*1000+offsetMs)...)
+		i = j
+	}
+	r.exitUntil(^uint64(0))
+	return results
+}
+
+func (r *replayer) replaySecond(samples []*Sample, secondStartMs uint64) []*Result {
+	type cursor struct {
+		sample *Sample
+		result *Result
+		// done is the amount of replayed requests
+		done uint64
+	}
+	cursors := make([]*cursor, len(samples))
+	var total uint64
+	for i, s := range samples {
+		cursors[i] = &cursor{
+			sample: s,
+			result: &Result{Timestamp: s.Timestamp, Resource: s.Resource, Requests: s.Requests},
+		}
+		total += s.Requests
+	}
+	var n uint64
+	for n < total {
+		for _, c := range cursors {
+			if c.done >= c.sample.Requests {
+				continue
+			}
+			now := secondStartMs + n*1000/total
+			r.exitUntil(now)
+			r.clock.set(now)
+			e, b := r.entryFn(c.sample.Resource)
+			if b != nil {
+				c.result.Blocked++
+			} else {
+				c.result.Passed++
+				if e != nil {
+					heap.Push(&r.pending, &pendingExit{atMs: now + sampleRt(c.sample, c.done), entry: e})
+				}
+			}
+			c.done++
+			n++
+		}
+	}
+	results := make([]*Result, len(cursors))
+	for i, c := range cursors {
+		results[i] = c.result
+	}
+	return results
+}
+
+// sampleRt returns the RT of the k-th request of the sample according to the recorded RT distribution.
+func sampleRt(s *Sample, k uint64) uint64 {
+	var completed uint64
+	for _, c := range s.RtHistogram {
+		completed += c
+	}
+	if completed == 0 {
+		return 0
+	}
+	// the quantile of the k-th request
+	target := (k%s.Requests)*completed/s.Requests + 1
+	var cumulative uint64
+	for i, c := range s.RtHistogram {
+		cumulative += c
+		if cumulative >= target {
+			return rtOfBucket(i)
+		}
+	}
+	return rtOfBucket(len(s.RtHistogram) - 1)
+}
@@ -0,0 +1,95 @@
+package retry
+
+import (
+	"sync/atomic"
+
+	sbase "github.com/alibaba/sentinel-golang/core/stat/base"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/pkg/errors"
+)
+
+const budgetSampleCount uint32 = 10
+
+type retryCounter struct {
+	requestCount uint64
+	retryCount   uint64
+}
+
+// budget is the retry budget of a resource bound to the rule.
+type budget struct {
+	rule *Rule
+	data *sbase.LeapArray
+}
+
+func newBudget(r *Rule) (*budget, error) {
+	sampleCount := budgetSampleCount
+	if r.StatIntervalMs%sampleCount != 0 {
+		sampleCount = 1
+	}
+	b := &budget{rule: r}
+	leapArray, err := sbase.NewLeapArray(sampleCount, r.StatIntervalMs, b)
+	if err != nil {
+		return nil, err
+	}
+	b.data = leapArray
+	return b, nil
+}
+
+func (b *budget) NewEmptyBucket() interface{} {
+	return &retryCounter{}
+}
+
+func (b *budget) ResetBucketTo(bw *sbase.BucketWrap, startTime uint64) *sbase.BucketWrap {
+	atomic.StoreUint64(&bw.BucketStart, startTime)
+	bw.Value.Store(&retryCounter{})
+	return bw
+}
+
+func (b *budget) currentCounter() *retryCounter {
+	curBucket, err := b.data.CurrentBucket(b)
+	if err != nil {
+		logging.Error(err, "Failed to get current bucket of retry budget")
+		return nil
+	}
+	if curBucket == nil {
+		logging.Error(errors.New("Current bucket is nil"), "")
+		return nil
+	}
+	counter, ok := curBucket.Value.Load().(*retryCounter)
+	if !ok {
+		logging.Error(errors.New("Bucket data type error"), "")
+		return nil
+	}
+	return counter
+}
+
+func (b *budget) sum() (requests uint64, retries uint64) {
+	for _, bw := range b.data.Values() {
+		counter, ok := bw.Value.Load().(*retryCounter)
+		if !ok {
+			continue
+		}
+		requests += atomic.LoadUint64(&counter.requestCount)
+		retries += atomic.LoadUint64(&counter.retryCount)
+	}
+	return
+}
+
+func (b *budget) onRequest() {
+	if c := b.currentCounter(); c != nil {
+		atomic.AddUint64(&c.requestCount, 1)
+	}
+}
+
+// tryAcquire checks whether a retry is allowed by the budget, and records the retry if allowed.
+func (b *budget) tryAcquire() bool {
+	requests, retries := b.sum()
+	allowed := b.rule.MaxRetryRatio * float64(requests)
+	if float64(retries+1) > allowed && retries+1 > b.rule.MinRetryAmount {
+		return false
+	}
+	if c := b.currentCounter(); c != nil {
+		atomic.AddUint64(&c.retryCount, 1)
+	}
+	return true
+}
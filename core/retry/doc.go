@@ -0,0 +1,12 @@
+// Package retry implements the per-resource retry budget.
+//
+// A retry budget limits the ratio of retries to the original requests of a resource over a sliding window,
+// so retries amplify the load only within a safe envelope during partial outages. MinRetryAmount retries
+// are always allowed in the window, so that resources with little traffic could still retry.
+//
+// DoWithRetry consults the retry budget before each retry:
+//
+//	err := retry.DoWithRetry("some-service", func() error {
+//	    return callSomeService()
+//	}, retry.WithMaxAttempts(3), retry.WithBackoff(10*time.Millisecond))
+package retry
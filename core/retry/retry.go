@@ -0,0 +1,84 @@
+package retry
+
+import (
+	"time"
+)
+
+// RecordRequest records an original (non-retry) request of the resource, which earns the retry budget.
+func RecordRequest(resource string) {
+	if b := getBudgetOf(resource); b != nil {
+		b.onRequest()
+	}
+}
+
+// TryAcquireRetry checks whether a retry of the resource is allowed by the retry budget,
+// and the retry will be recorded if allowed. It always returns true if there is no rule for the resource.
+func TryAcquireRetry(resource string) bool {
+	b := getBudgetOf(resource)
+	if b == nil {
+		return true
+	}
+	return b.tryAcquire()
+}
+
+type options struct {
+	maxAttempts uint32
+	backoff     time.Duration
+	retryable   func(error) bool
+}
+
+// Option configures DoWithRetry.
+type Option func(*options)
+
+// WithMaxAttempts sets the max attempts (including the first one), 3 by default.
+func WithMaxAttempts(maxAttempts uint32) Option {
+	return func(opts *options) {
+		opts.maxAttempts = maxAttempts
+	}
+}
+
+// WithBackoff sets the waiting duration before each retry, no waiting by default.
+func WithBackoff(backoff time.Duration) Option {
+	return func(opts *options) {
+		opts.backoff = backoff
+	}
+}
+
+// WithRetryable sets the function that determines whether an error is retryable, all errors are retryable by default.
+func WithRetryable(retryable func(error) bool) Option {
+	return func(opts *options) {
+		opts.retryable = retryable
+	}
+}
+
+func evaluateOptions(opts []Option) *options {
+	optCopy := &options{
+		maxAttempts: 3,
+	}
+	for _, opt := range opts {
+		opt(optCopy)
+	}
+	return optCopy
+}
+
+// DoWithRetry invokes fn, and retries it on error as long as the max attempts is not reached
+// and the retry budget of the resource allows. It returns the error of the last attempt.
+func DoWithRetry(resource string, fn func() error, opts ...Option) error {
+	options := evaluateOptions(opts)
+	RecordRequest(resource)
+
+	err := fn()
+	for attempt := uint32(1); err != nil && attempt < options.maxAttempts; attempt++ {
+		if options.retryable != nil && !options.retryable(err) {
+			return err
+		}
+		if !TryAcquireRetry(resource) {
+			return err
+		}
+		if options.backoff > 0 {
+			time.Sleep(options.backoff)
+		}
+		err = fn()
+	}
+	return err
+}
@@ -0,0 +1,80 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTryAcquireRetry(t *testing.T) {
+	defer ClearRules()
+
+	assert.True(t, TryAcquireRetry("abc"))
+
+	_, err := LoadRules([]*Rule{
+		{
+			Resource:       "abc",
+			MaxRetryRatio:  0.2,
+			MinRetryAmount: 1,
+			StatIntervalMs: 10000,
+		},
+	})
+	assert.Nil(t, err)
+
+	// MinRetryAmount is always allowed.
+	assert.True(t, TryAcquireRetry("abc"))
+	assert.False(t, TryAcquireRetry("abc"))
+
+	for i := 0; i < 10; i++ {
+		RecordRequest("abc")
+	}
+	// 10 requests earn 2 retries.
+	assert.True(t, TryAcquireRetry("abc"))
+	assert.False(t, TryAcquireRetry("abc"))
+}
+
+func TestDoWithRetry(t *testing.T) {
+	defer ClearRules()
+
+	_, err := LoadRules([]*Rule{
+		{
+			Resource:       "abc",
+			MaxRetryRatio:  0.1,
+			MinRetryAmount: 2,
+			StatIntervalMs: 10000,
+		},
+	})
+	assert.Nil(t, err)
+
+	bizErr := errors.New("biz error")
+	calls := 0
+	err = DoWithRetry("abc", func() error {
+		calls++
+		return bizErr
+	}, WithMaxAttempts(5))
+	assert.Equal(t, bizErr, err)
+	// The first call and 2 retries allowed by the budget.
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = DoWithRetry("def", func() error {
+		calls++
+		if calls < 2 {
+			return bizErr
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, calls)
+
+	calls = 0
+	err = DoWithRetry("def", func() error {
+		calls++
+		return bizErr
+	}, WithRetryable(func(error) bool {
+		return false
+	}))
+	assert.Equal(t, bizErr, err)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,42 @@
+package retry
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Rule describes the retry budget of a resource.
+type Rule struct {
+	// ID represents the unique ID of the rule (optional).
+	ID string `json:"id,omitempty"`
+	// Resource represents the resource name.
+	Resource string `json:"resource"`
+	// MaxRetryRatio is the max ratio of retries to the original requests in the statistic interval, e.g. 0.2.
+	MaxRetryRatio float64 `json:"maxRetryRatio"`
+	// MinRetryAmount is the amount of retries always allowed in the statistic interval regardless of the ratio.
+	MinRetryAmount uint64 `json:"minRetryAmount"`
+	// StatIntervalMs represents the statistic interval of the retry budget.
+	StatIntervalMs uint32 `json:"statIntervalMs"`
+}
+
+func (r *Rule) String() string {
+	b, err := json.Marshal(r)
+	if err != nil {
+		// Return the fallback string
+		return fmt.Sprintf("{Id=%s, Resource=%s, MaxRetryRatio=%.2f, MinRetryAmount=%d, StatIntervalMs=%d}",
+			r.ID, r.Resource, r.MaxRetryRatio, r.MinRetryAmount, r.StatIntervalMs)
+	}
+	return string(b)
+}
+
+func (r *Rule) ResourceName() string {
+	return r.Resource
+}
+
+func (r *Rule) isEqualsTo(newRule *Rule) bool {
+	if newRule == nil {
+		return false
+	}
+	return r.Resource == newRule.Resource && r.MaxRetryRatio == newRule.MaxRetryRatio &&
+		r.MinRetryAmount == newRule.MinRetryAmount && r.StatIntervalMs == newRule.StatIntervalMs
+}
@@ -0,0 +1,113 @@
+package retry
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+var (
+	budgetMap = make(map[string]*budget)
+	updateMux = new(sync.RWMutex)
+)
+
+// LoadRules loads the given retry budget rules to the rule manager, while all previous rules will be replaced.
+// Only one rule is allowed for each resource, and the latter rules of the same resource will be ignored.
+func LoadRules(rules []*Rule) (bool, error) {
+	defer selfmetric.RecordRuleUpdate("retry", time.Now())
+
+	resRuleMap := make(map[string]*Rule)
+	for _, r := range rules {
+		if err := IsValidRule(r); err != nil {
+			logging.Warn("Ignoring invalid retry rule", "rule", r, "reason", err)
+			continue
+		}
+		if _, exist := resRuleMap[r.Resource]; exist {
+			logging.Warn("Ignoring duplicate retry rule of the resource", "rule", r)
+			continue
+		}
+		resRuleMap[r.Resource] = r
+	}
+
+	m := make(map[string]*budget, len(resRuleMap))
+	start := util.CurrentTimeNano()
+	updateMux.Lock()
+	defer func() {
+		updateMux.Unlock()
+		logging.Debug("time statistic(ns) for updating retry rule", "timeCost", util.CurrentTimeNano()-start)
+		logRuleUpdate(m)
+	}()
+	for res, r := range resRuleMap {
+		if old, exist := budgetMap[res]; exist && old.rule.isEqualsTo(r) {
+			m[res] = old
+			continue
+		}
+		b, err := newBudget(r)
+		if err != nil {
+			logging.Warn("Ignoring the retry rule due to failure of creating budget", "rule", r, "err", err)
+			continue
+		}
+		m[res] = b
+	}
+	budgetMap = m
+	return true, nil
+}
+
+// ClearRules clears all the rules in retry module.
+func ClearRules() error {
+	_, err := LoadRules(nil)
+	return err
+}
+
+// GetRules returns all the rules based on copy.
+// It doesn't take effect for retry module if user changes the rule.
+func GetRules() []Rule {
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+
+	ret := make([]Rule, 0, len(budgetMap))
+	for _, b := range budgetMap {
+		ret = append(ret, *b.rule)
+	}
+	return ret
+}
+
+func getBudgetOf(resource string) *budget {
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+
+	return budgetMap[resource]
+}
+
+func logRuleUpdate(m map[string]*budget) {
+	if len(m) == 0 {
+		logging.Info("[RetryRuleManager] Retry rules were cleared")
+		return
+	}
+	rules := make([]*Rule, 0, len(m))
+	for _, b := range m {
+		rules = append(rules, b.rule)
+	}
+	logging.Info("[RetryRuleManager] Retry rules were loaded", "rules", rules)
+}
+
+// IsValidRule checks whether the given Rule is valid.
+func IsValidRule(r *Rule) error {
+	if r == nil {
+		return errors.New("nil Rule")
+	}
+	if len(r.Resource) == 0 {
+		return errors.New("empty resource name")
+	}
+	if r.MaxRetryRatio < 0 {
+		return errors.New("negative MaxRetryRatio")
+	}
+	if r.StatIntervalMs == 0 {
+		return errors.New("invalid StatIntervalMs")
+	}
+	return nil
+}
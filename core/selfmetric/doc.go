@@ -0,0 +1,16 @@
+// Package selfmetric provides the instrumentation of Sentinel's own overhead, so that the cost of protection
+// could be quantified and the regressions after upgrades could be spotted.
+//
+// The self metrics include:
+//
+//  1. the latency of each slot in the slot chain (category CategorySlot, named by the type of the slot);
+//  2. the duration of the rule updates of each module (category CategoryRuleUpdate, e.g. "flow");
+//  3. the wait time of the internal locks (category CategoryLockWait, e.g. "flow.rules");
+//  4. the gauges registered by the modules, e.g. the estimated memory used by the statistic structures.
+//  5. the drop counters of the asynchronous components (e.g. "exporter.dropped"), which drop the items
+//     rather than blocking the entry processing when their consumers are slow, see DropCounter.
+//
+// The timers are disabled by default (see config.SelfMetricEnabled), as measuring the latency of every slot
+// costs a few time syscalls per entry. The gauges are evaluated only when the snapshot is taken.
+// Use TakeSnapshot to get the current self metrics and export them to the monitoring system.
+package selfmetric
@@ -0,0 +1,49 @@
+package selfmetric
+
+import (
+	"sync/atomic"
+
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+// DropWarnIntervalMs is the minimal interval (in ms) between the warnings of the drops of a DropCounter,
+// so that a slow consumer doesn't flood the logs as well.
+const DropWarnIntervalMs = 10000
+
+// DropCounter counts the items dropped by an internal asynchronous component (e.g. the metric exporters)
+// when its consumer is too slow, which degrades by dropping instead of blocking the entry processing.
+// The count is exposed as the gauge "<component>.dropped".
+type DropCounter struct {
+	component  string
+	count      uint64
+	lastWarnMs uint64
+}
+
+// NewDropCounter creates the DropCounter of the component and registers its gauge.
+// It should be created once per component, e.g. as a package variable.
+func NewDropCounter(component string) *DropCounter {
+	c := &DropCounter{component: component}
+	RegisterGauge(component+".dropped", func() float64 {
+		return float64(c.Count())
+	})
+	return c
+}
+
+// Inc counts a dropped item. A warning with the total count is logged at most once per DropWarnIntervalMs.
+func (c *DropCounter) Inc() {
+	total := atomic.AddUint64(&c.count, 1)
+	now := util.CurrentTimeMillis()
+	last := atomic.LoadUint64(&c.lastWarnMs)
+	if last != 0 && now < last+DropWarnIntervalMs {
+		return
+	}
+	if atomic.CompareAndSwapUint64(&c.lastWarnMs, last, now) {
+		logging.Warn("[SelfMetric] Dropped items as the consumer is too slow", "component", c.component, "totalDropped", total)
+	}
+}
+
+// Count returns the total count of the dropped items.
+func (c *DropCounter) Count() uint64 {
+	return atomic.LoadUint64(&c.count)
+}
@@ -0,0 +1,211 @@
+package selfmetric
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// CategorySlot is the category of the slot latency timers, named by the type of the slot.
+	CategorySlot = "slot"
+	// CategoryRuleUpdate is the category of the rule update duration timers, named by the rule module.
+	CategoryRuleUpdate = "ruleUpdate"
+	// CategoryLockWait is the category of the lock wait time timers, named by the lock.
+	CategoryLockWait = "lockWait"
+)
+
+var enabled int32
+
+// SetEnabled sets whether to record the self metric timers.
+func SetEnabled(e bool) {
+	if e {
+		atomic.StoreInt32(&enabled, 1)
+	} else {
+		atomic.StoreInt32(&enabled, 0)
+	}
+}
+
+// Enabled indicates whether the self metric timers are recorded.
+func Enabled() bool {
+	return atomic.LoadInt32(&enabled) == 1
+}
+
+// Timer accumulates the count, total and max of the observed durations.
+type Timer struct {
+	category string
+	name     string
+	count    uint64
+	totalNs  uint64
+	maxNs    uint64
+}
+
+func (t *Timer) record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	ns := uint64(d)
+	atomic.AddUint64(&t.count, 1)
+	atomic.AddUint64(&t.totalNs, ns)
+	for {
+		max := atomic.LoadUint64(&t.maxNs)
+		if ns <= max || atomic.CompareAndSwapUint64(&t.maxNs, max, ns) {
+			return
+		}
+	}
+}
+
+func (t *Timer) snapshot() *TimerSnapshot {
+	s := &TimerSnapshot{
+		Category: t.category,
+		Name:     t.name,
+		Count:    atomic.LoadUint64(&t.count),
+		TotalNs:  atomic.LoadUint64(&t.totalNs),
+		MaxNs:    atomic.LoadUint64(&t.maxNs),
+	}
+	if s.Count > 0 {
+		s.AvgNs = float64(s.TotalNs) / float64(s.Count)
+	}
+	return s
+}
+
+type timerKey struct {
+	category string
+	name     string
+}
+
+var (
+	timers sync.Map
+	// slotTimers caches the timers of the slots by the type of the slot, which saves building the names.
+	slotTimers sync.Map
+)
+
+func timerOf(category, name string) *Timer {
+	key := timerKey{category: category, name: name}
+	if t, ok := timers.Load(key); ok {
+		return t.(*Timer)
+	}
+	t, _ := timers.LoadOrStore(key, &Timer{category: category, name: name})
+	return t.(*Timer)
+}
+
+// RecordSlotLatency records the latency of a single invocation of the slot started at the given time.
+func RecordSlotLatency(slot interface{}, start time.Time) {
+	if !Enabled() || slot == nil {
+		return
+	}
+	typ := reflect.TypeOf(slot)
+	t, ok := slotTimers.Load(typ)
+	if !ok {
+		t, _ = slotTimers.LoadOrStore(typ, timerOf(CategorySlot, typ.String()))
+	}
+	t.(*Timer).record(time.Since(start))
+}
+
+// RecordRuleUpdate records the duration of updating the rules of the module (e.g. flow) started at the given time.
+// It's convenient to be deferred at the beginning of the rule update:
+//
+//	defer selfmetric.RecordRuleUpdate("flow", time.Now())
+func RecordRuleUpdate(module string, start time.Time) {
+	if !Enabled() {
+		return
+	}
+	timerOf(CategoryRuleUpdate, module).record(time.Since(start))
+}
+
+// RecordLockWait records the time waiting for the lock since the given time, it should be called
+// right after the lock is acquired.
+func RecordLockWait(lock string, start time.Time) {
+	if !Enabled() {
+		return
+	}
+	timerOf(CategoryLockWait, lock).record(time.Since(start))
+}
+
+// ResetTimers clears all the timers.
+func ResetTimers() {
+	timers.Range(func(key, _ interface{}) bool {
+		timers.Delete(key)
+		return true
+	})
+	slotTimers.Range(func(key, _ interface{}) bool {
+		slotTimers.Delete(key)
+		return true
+	})
+}
+
+// GaugeFunc returns the current value of the gauge.
+type GaugeFunc func() float64
+
+var (
+	gauges    = make(map[string]GaugeFunc)
+	gaugesMux = new(sync.RWMutex)
+)
+
+// RegisterGauge registers the gauge of the given name, the gauge of the same name is replaced.
+func RegisterGauge(name string, f GaugeFunc) {
+	if f == nil {
+		return
+	}
+	gaugesMux.Lock()
+	defer gaugesMux.Unlock()
+
+	gauges[name] = f
+}
+
+// TimerSnapshot is the snapshot of a self metric timer.
+type TimerSnapshot struct {
+	Category string  `json:"category"`
+	Name     string  `json:"name"`
+	Count    uint64  `json:"count"`
+	TotalNs  uint64  `json:"totalNs"`
+	MaxNs    uint64  `json:"maxNs"`
+	AvgNs    float64 `json:"avgNs"`
+}
+
+// GaugeSnapshot is the snapshot of a self metric gauge.
+type GaugeSnapshot struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// Snapshot is the snapshot of all the self metrics.
+type Snapshot struct {
+	Timers []*TimerSnapshot `json:"timers"`
+	Gauges []*GaugeSnapshot `json:"gauges"`
+}
+
+// TakeSnapshot returns the current self metrics, the timers are cumulative since they are created.
+func TakeSnapshot() *Snapshot {
+	ret := &Snapshot{
+		Timers: make([]*TimerSnapshot, 0),
+		Gauges: make([]*GaugeSnapshot, 0),
+	}
+	timers.Range(func(_, t interface{}) bool {
+		ret.Timers = append(ret.Timers, t.(*Timer).snapshot())
+		return true
+	})
+	sort.Slice(ret.Timers, func(i, j int) bool {
+		if ret.Timers[i].Category == ret.Timers[j].Category {
+			return ret.Timers[i].Name < ret.Timers[j].Name
+		}
+		return ret.Timers[i].Category < ret.Timers[j].Category
+	})
+
+	gaugesMux.RLock()
+	for name, f := range gauges {
+		v := f()
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		ret.Gauges = append(ret.Gauges, &GaugeSnapshot{Name: name, Value: v})
+	}
+	gaugesMux.RUnlock()
+	sort.Slice(ret.Gauges, func(i, j int) bool {
+		return ret.Gauges[i].Name < ret.Gauges[j].Name
+	})
+	return ret
+}
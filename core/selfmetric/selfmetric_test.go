@@ -0,0 +1,84 @@
+package selfmetric
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testSlot struct{}
+
+func TestRecordTimers(t *testing.T) {
+	defer ResetTimers()
+
+	t.Run("Disabled", func(t *testing.T) {
+		SetEnabled(false)
+		RecordSlotLatency(&testSlot{}, time.Now())
+		RecordRuleUpdate("flow", time.Now())
+		RecordLockWait("flow.rules", time.Now())
+		assert.Equal(t, 0, len(TakeSnapshot().Timers))
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		SetEnabled(true)
+		defer SetEnabled(false)
+
+		RecordSlotLatency(&testSlot{}, time.Now().Add(-2*time.Millisecond))
+		RecordSlotLatency(&testSlot{}, time.Now().Add(-4*time.Millisecond))
+		RecordRuleUpdate("flow", time.Now())
+		RecordLockWait("flow.rules", time.Now())
+
+		timers := TakeSnapshot().Timers
+		assert.Equal(t, 3, len(timers))
+		assert.Equal(t, CategoryLockWait, timers[0].Category)
+		assert.Equal(t, "flow.rules", timers[0].Name)
+		assert.Equal(t, CategoryRuleUpdate, timers[1].Category)
+		assert.Equal(t, "flow", timers[1].Name)
+
+		slot := timers[2]
+		assert.Equal(t, CategorySlot, slot.Category)
+		assert.Equal(t, "*selfmetric.testSlot", slot.Name)
+		assert.Equal(t, uint64(2), slot.Count)
+		assert.True(t, slot.MaxNs >= uint64(4*time.Millisecond))
+		assert.True(t, slot.AvgNs >= float64(3*time.Millisecond))
+	})
+}
+
+func TestRegisterGauge(t *testing.T) {
+	RegisterGauge("test.gauge", func() float64 {
+		return 42
+	})
+	RegisterGauge("test.nan", func() float64 {
+		return math.NaN()
+	})
+	defer func() {
+		gaugesMux.Lock()
+		delete(gauges, "test.gauge")
+		delete(gauges, "test.nan")
+		gaugesMux.Unlock()
+	}()
+
+	snapshot := TakeSnapshot()
+	assert.Equal(t, 1, len(snapshot.Gauges))
+	assert.Equal(t, "test.gauge", snapshot.Gauges[0].Name)
+	assert.Equal(t, float64(42), snapshot.Gauges[0].Value)
+}
+
+func TestDropCounter(t *testing.T) {
+	c := NewDropCounter("test.queue")
+	for i := 0; i < 3; i++ {
+		c.Inc()
+	}
+	assert.Equal(t, uint64(3), c.Count())
+
+	found := false
+	for _, g := range TakeSnapshot().Gauges {
+		if g.Name == "test.queue.dropped" {
+			found = true
+			assert.Equal(t, float64(3), g.Value)
+		}
+	}
+	assert.True(t, found)
+}
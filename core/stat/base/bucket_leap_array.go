@@ -3,6 +3,7 @@ package base
 import (
 	"fmt"
 	"sync/atomic"
+	"unsafe"
 
 	"github.com/alibaba/sentinel-golang/core/base"
 	"github.com/alibaba/sentinel-golang/logging"
@@ -165,3 +166,12 @@ func (bla *BucketLeapArray) MinRt() int64 {
 	}
 	return ret
 }
+
+// EstimatedMemoryBytes estimates the memory used by the leap array and its buckets.
+func (bla *BucketLeapArray) EstimatedMemoryBytes() int64 {
+	bucketSize := unsafe.Sizeof(BucketWrap{}) + unsafe.Sizeof(uintptr(0)) + unsafe.Sizeof(MetricBucket{})
+	if isShardedCounterEnabled() {
+		bucketSize += unsafe.Sizeof(shardedCounter{}) + uintptr(shardCount)*unsafe.Sizeof(counterShard{})
+	}
+	return int64(unsafe.Sizeof(*bla) + unsafe.Sizeof(AtomicBucketWrapArray{}) + uintptr(bla.data.sampleCount)*bucketSize)
+}
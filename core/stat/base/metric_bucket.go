@@ -13,12 +13,17 @@ type MetricBucket struct {
 	// Value of statistic
 	counter [base.MetricEventTotal]int64
 	minRt   int64
+	// sharded is the sharded counters used instead of counter if present, see SetShardedCounterEnabled.
+	sharded *shardedCounter
 }
 
 func NewMetricBucket() *MetricBucket {
 	mb := &MetricBucket{
 		minRt: base.DefaultStatisticMaxRt,
 	}
+	if isShardedCounterEnabled() {
+		mb.sharded = newShardedCounter()
+	}
 	return mb
 }
 
@@ -35,6 +40,10 @@ func (mb *MetricBucket) Add(event base.MetricEvent, count int64) {
 }
 
 func (mb *MetricBucket) addCount(event base.MetricEvent, count int64) {
+	if mb.sharded != nil {
+		mb.sharded.add(event, count)
+		return
+	}
 	atomic.AddInt64(&mb.counter[event], count)
 }
 
@@ -43,6 +52,9 @@ func (mb *MetricBucket) Get(event base.MetricEvent) int64 {
 	if event >= base.MetricEventTotal || event < 0 {
 		panic(fmt.Sprintf("Unknown metric event: %v", event))
 	}
+	if mb.sharded != nil {
+		return mb.sharded.get(event)
+	}
 	return atomic.LoadInt64(&mb.counter[event])
 }
 
@@ -50,6 +62,13 @@ func (mb *MetricBucket) reset() {
 	for i := 0; i < int(base.MetricEventTotal); i++ {
 		atomic.StoreInt64(&mb.counter[i], 0)
 	}
+	if mb.sharded != nil {
+		for i := range mb.sharded.shards {
+			for j := 0; j < int(base.MetricEventTotal); j++ {
+				atomic.StoreInt64(&mb.sharded.shards[i].counter[j], 0)
+			}
+		}
+	}
 	atomic.StoreInt64(&mb.minRt, base.DefaultStatisticMaxRt)
 }
 
@@ -12,7 +12,8 @@ func Test_metricBucket_MemSize(t *testing.T) {
 	mb := NewMetricBucket()
 	t.Log("mb:", mb)
 	size := unsafe.Sizeof(*mb)
-	if size != 48 {
+	// 6 counters, minRt and the pointer to the sharded counters.
+	if size != 64 {
 		t.Error("unexpect memory size of MetricBucket")
 	}
 }
@@ -112,3 +113,56 @@ func Test_metricBucket_Concurrent(t *testing.T) {
 		t.Error("unexpect count MetricEventRt")
 	}
 }
+
+func Test_metricBucket_Sharded(t *testing.T) {
+	SetShardedCounterEnabled(true)
+	defer SetShardedCounterEnabled(false)
+
+	mb := NewMetricBucket()
+	if mb.sharded == nil {
+		t.Fatal("sharded counter is not enabled")
+	}
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				mb.Add(base.MetricEventPass, 1)
+				mb.AddRt(10)
+			}
+		}()
+	}
+	wg.Wait()
+	if mb.Get(base.MetricEventPass) != 10000 || mb.Get(base.MetricEventRt) != 100000 {
+		t.Error("unexpect count of sharded counter")
+	}
+	if mb.MinRt() != 10 {
+		t.Error("unexpect min rt of sharded counter")
+	}
+	mb.reset()
+	if mb.Get(base.MetricEventPass) != 0 {
+		t.Error("unexpect count after reset")
+	}
+}
+
+func Benchmark_metricBucket_Add(b *testing.B) {
+	mb := NewMetricBucket()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			mb.Add(base.MetricEventPass, 1)
+		}
+	})
+}
+
+func Benchmark_metricBucket_ShardedAdd(b *testing.B) {
+	SetShardedCounterEnabled(true)
+	defer SetShardedCounterEnabled(false)
+
+	mb := NewMetricBucket()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			mb.Add(base.MetricEventPass, 1)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package base
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+)
+
+const cacheLineSize = 64
+
+// counterShard is a set of event counters padded to occupy whole cache lines,
+// so that the writes on different shards never contend on the same cache line.
+type counterShard struct {
+	counter [base.MetricEventTotal]int64
+	_       [cacheLineSize - (unsafe.Sizeof([base.MetricEventTotal]int64{}) % cacheLineSize)]byte
+}
+
+// shardedCounter spreads the writes of the event counters over multiple shards, and aggregates the shards on read.
+// It's designed for very high QPS (e.g. >500k entries/sec), where the atomic adds on the shared counters of
+// a bucket cause heavy cache-line contention, at the cost of more memory and slower reads.
+type shardedCounter struct {
+	shards []counterShard
+}
+
+func newShardedCounter() *shardedCounter {
+	return &shardedCounter{
+		shards: make([]counterShard, shardCount),
+	}
+}
+
+func (c *shardedCounter) add(event base.MetricEvent, count int64) {
+	t := shardTokenPool.Get().(*shardToken)
+	atomic.AddInt64(&c.shards[t.idx].counter[event], count)
+	shardTokenPool.Put(t)
+}
+
+func (c *shardedCounter) get(event base.MetricEvent) int64 {
+	var sum int64
+	for i := range c.shards {
+		sum += atomic.LoadInt64(&c.shards[i].counter[event])
+	}
+	return sum
+}
+
+// shardToken is the shard index cached in sync.Pool. As sync.Pool caches the objects per P (processor),
+// the goroutines running on the same P tend to get the same shard, which is the cheapest way to
+// approximate per-P counters without the runtime internals.
+type shardToken struct {
+	idx uint32
+}
+
+var (
+	shardedCounterEnabled int32
+	shardCount            = uint32(runtime.GOMAXPROCS(0))
+	nextShardIdx          uint32
+	shardTokenPool        = sync.Pool{
+		New: func() interface{} {
+			return &shardToken{idx: (atomic.AddUint32(&nextShardIdx, 1) - 1) % shardCount}
+		},
+	}
+)
+
+// SetShardedCounterEnabled sets whether the newly created metric buckets use the sharded (per-P) counters.
+// As the buckets are recreated on rollover, the change takes effect within one statistic interval.
+func SetShardedCounterEnabled(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&shardedCounterEnabled, 1)
+	} else {
+		atomic.StoreInt32(&shardedCounterEnabled, 0)
+	}
+}
+
+func isShardedCounterEnabled() bool {
+	return atomic.LoadInt32(&shardedCounterEnabled) == 1
+}
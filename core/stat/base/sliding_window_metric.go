@@ -153,6 +153,11 @@ func (m *SlidingWindowMetric) AvgRT() float64 {
 	return float64(m.GetSum(base.MetricEventRt)) / float64(m.GetSum(base.MetricEventComplete))
 }
 
+// AvgRTMicros returns the average RT in microseconds.
+func (m *SlidingWindowMetric) AvgRTMicros() float64 {
+	return float64(m.GetSum(base.MetricEventRtMicros)) / float64(m.GetSum(base.MetricEventComplete))
+}
+
 // SecondMetricsOnCondition aggregates metric items by second on condition that
 // the startTime of the statistic buckets satisfies the time predicate.
 func (m *SlidingWindowMetric) SecondMetricsOnCondition(predicate base.TimePredicate) []*base.MetricItem {
@@ -185,7 +190,8 @@ func (m *SlidingWindowMetric) SecondMetricsOnCondition(predicate base.TimePredic
 // to the single MetricItem.
 func (m *SlidingWindowMetric) metricItemFromBuckets(ts uint64, ws []*BucketWrap) *base.MetricItem {
 	item := &base.MetricItem{Timestamp: ts}
-	var allRt int64 = 0
+	var allRt, allRtUs int64 = 0, 0
+	minRt := base.DefaultStatisticMaxRt
 	for _, w := range ws {
 		mi := w.Value.Load()
 		if mi == nil {
@@ -200,13 +206,21 @@ func (m *SlidingWindowMetric) metricItemFromBuckets(ts uint64, ws []*BucketWrap)
 		item.PassQps += uint64(mb.Get(base.MetricEventPass))
 		item.BlockQps += uint64(mb.Get(base.MetricEventBlock))
 		item.ErrorQps += uint64(mb.Get(base.MetricEventError))
-		item.CompleteQps += uint64(mb.Get(base.MetricEventComplete))
+		completeQps := mb.Get(base.MetricEventComplete)
+		item.CompleteQps += uint64(completeQps)
 		allRt += mb.Get(base.MetricEventRt)
+		allRtUs += mb.Get(base.MetricEventRtMicros)
+		if v := mb.MinRt(); completeQps > 0 && v < minRt {
+			minRt = v
+		}
 	}
 	if item.CompleteQps > 0 {
 		item.AvgRt = uint64(allRt) / item.CompleteQps
+		item.AvgRtUs = uint64(allRtUs) / item.CompleteQps
+		item.MinRt = uint64(minRt)
 	} else {
 		item.AvgRt = uint64(allRt)
+		item.AvgRtUs = uint64(allRtUs)
 	}
 	return item
 }
@@ -232,8 +246,11 @@ func (m *SlidingWindowMetric) metricItemFromBucket(w *BucketWrap) *base.MetricIt
 	}
 	if completeQps > 0 {
 		item.AvgRt = uint64(mb.Get(base.MetricEventRt) / completeQps)
+		item.AvgRtUs = uint64(mb.Get(base.MetricEventRtMicros) / completeQps)
+		item.MinRt = uint64(mb.MinRt())
 	} else {
 		item.AvgRt = uint64(mb.Get(base.MetricEventRt))
+		item.AvgRtUs = uint64(mb.Get(base.MetricEventRtMicros))
 	}
 	return item
 }
@@ -0,0 +1,171 @@
+package base
+
+import (
+	"reflect"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+// TieredWindowMetric is a sliding window statistic for long intervals (e.g. several minutes).
+// It keeps two tiers of buckets: a fine-grained tier covering only the most recent part of
+// the window, and a coarse-grained tier covering the whole window. Recent data is read from
+// the fine tier so that it stays precise, while older data is read from the coarse tier
+// so that a long window only costs a few buckets.
+//
+// Every write goes to both tiers. When summing, the part of the coarse tier overlapped by
+// the fine tier is excluded, so that nothing is counted twice.
+//
+// TieredWindowMetric owns its storage and supports both read and write operations.
+type TieredWindowMetric struct {
+	intervalInMs uint32
+	fine         *BucketLeapArray
+	coarse       *BucketLeapArray
+}
+
+// NewTieredWindowMetric creates a TieredWindowMetric covering intervalInMs with coarseSampleCount buckets,
+// of which the most recent fineIntervalInMs is additionally recorded in fineSampleCount buckets.
+// The coarse bucket length must be a multiple of the fine bucket length, and the fine interval
+// must be shorter than the whole interval.
+func NewTieredWindowMetric(intervalInMs, coarseSampleCount, fineIntervalInMs, fineSampleCount uint32) (*TieredWindowMetric, error) {
+	if intervalInMs == 0 || coarseSampleCount == 0 || fineIntervalInMs == 0 || fineSampleCount == 0 {
+		return nil, errors.New("interval and sample count of each tier must be positive")
+	}
+	if intervalInMs%coarseSampleCount != 0 || fineIntervalInMs%fineSampleCount != 0 {
+		return nil, errors.New("interval of each tier must be divisible by its sample count")
+	}
+	if fineIntervalInMs >= intervalInMs {
+		return nil, errors.Errorf("fine interval %d must be shorter than the whole interval %d", fineIntervalInMs, intervalInMs)
+	}
+	coarseBucketLength := intervalInMs / coarseSampleCount
+	fineBucketLength := fineIntervalInMs / fineSampleCount
+	if coarseBucketLength%fineBucketLength != 0 {
+		return nil, errors.Errorf("coarse bucket length %d must be a multiple of fine bucket length %d", coarseBucketLength, fineBucketLength)
+	}
+	return &TieredWindowMetric{
+		intervalInMs: intervalInMs,
+		fine:         NewBucketLeapArray(fineSampleCount, fineIntervalInMs),
+		coarse:       NewBucketLeapArray(coarseSampleCount, intervalInMs),
+	}, nil
+}
+
+func (m *TieredWindowMetric) AddCount(event base.MetricEvent, count int64) {
+	m.addCountWithTime(util.CurrentTimeMillis(), event, count)
+}
+
+func (m *TieredWindowMetric) addCountWithTime(now uint64, event base.MetricEvent, count int64) {
+	m.fine.addCountWithTime(now, event, count)
+	m.coarse.addCountWithTime(now, event, count)
+}
+
+func (m *TieredWindowMetric) getIntervalInSecond() float64 {
+	return float64(m.intervalInMs) / 1000.0
+}
+
+func (m *TieredWindowMetric) GetSum(event base.MetricEvent) int64 {
+	return m.getSumWithTime(util.CurrentTimeMillis(), event)
+}
+
+func (m *TieredWindowMetric) getSumWithTime(now uint64, event base.MetricEvent) int64 {
+	fineLength := uint64(m.fine.BucketLengthInMs())
+	coarseLength := uint64(m.coarse.BucketLengthInMs())
+
+	fineEnd := calculateStartTime(now, m.fine.BucketLengthInMs())
+	fineStart := fineEnd + fineLength - uint64(m.fine.IntervalInMs())
+	coarseEnd := calculateStartTime(now, m.coarse.BucketLengthInMs())
+	coarseStart := coarseEnd + coarseLength - uint64(m.intervalInMs)
+	// the coarse bucket which the oldest fine bucket falls in
+	boundary := calculateStartTime(fineStart, m.coarse.BucketLengthInMs())
+
+	ret := bucketsSum(event, m.fine.ValuesConditional(now, func(ws uint64) bool {
+		return ws >= fineStart && ws <= fineEnd
+	}))
+	ret += bucketsSum(event, m.coarse.ValuesConditional(now, func(ws uint64) bool {
+		return ws >= coarseStart && ws < boundary
+	}))
+	if boundary < fineStart && boundary >= coarseStart {
+		// The boundary coarse bucket is partially covered by the fine tier,
+		// only take the part older than the fine tier.
+		straddle := bucketsSum(event, m.coarse.ValuesConditional(now, func(ws uint64) bool {
+			return ws == boundary
+		}))
+		covered := bucketsSum(event, m.fine.ValuesConditional(now, func(ws uint64) bool {
+			return ws >= fineStart && ws < boundary+coarseLength
+		}))
+		if straddle > covered {
+			ret += straddle - covered
+		}
+	}
+	return ret
+}
+
+func (m *TieredWindowMetric) GetQPS(event base.MetricEvent) float64 {
+	return m.getQPSWithTime(util.CurrentTimeMillis(), event)
+}
+
+func (m *TieredWindowMetric) GetPreviousQPS(event base.MetricEvent) float64 {
+	return m.getQPSWithTime(util.CurrentTimeMillis()-uint64(m.fine.BucketLengthInMs()), event)
+}
+
+func (m *TieredWindowMetric) getQPSWithTime(now uint64, event base.MetricEvent) float64 {
+	return float64(m.getSumWithTime(now, event)) / m.getIntervalInSecond()
+}
+
+func (m *TieredWindowMetric) MinRT() float64 {
+	now := util.CurrentTimeMillis()
+	coarseLength := uint64(m.coarse.BucketLengthInMs())
+	coarseStart := calculateStartTime(now, m.coarse.BucketLengthInMs()) + coarseLength - uint64(m.intervalInMs)
+	minRt := base.DefaultStatisticMaxRt
+	// the coarse tier has seen every write, so the fine tier is not needed here
+	for _, w := range m.coarse.ValuesConditional(now, func(ws uint64) bool {
+		return ws >= coarseStart
+	}) {
+		counter, ok := metricBucketOf(w)
+		if !ok {
+			continue
+		}
+		if v := counter.MinRt(); v < minRt {
+			minRt = v
+		}
+	}
+	if minRt < 1 {
+		minRt = 1
+	}
+	return float64(minRt)
+}
+
+func (m *TieredWindowMetric) AvgRT() float64 {
+	now := util.CurrentTimeMillis()
+	return float64(m.getSumWithTime(now, base.MetricEventRt)) / float64(m.getSumWithTime(now, base.MetricEventComplete))
+}
+
+// EstimatedMemoryBytes estimates the memory used by both tiers.
+func (m *TieredWindowMetric) EstimatedMemoryBytes() int64 {
+	return m.fine.EstimatedMemoryBytes() + m.coarse.EstimatedMemoryBytes()
+}
+
+func metricBucketOf(w *BucketWrap) (*MetricBucket, bool) {
+	mb := w.Value.Load()
+	if mb == nil {
+		logging.Error(errors.New("nil BucketWrap"), "Illegal state: current bucket Value is nil")
+		return nil, false
+	}
+	counter, ok := mb.(*MetricBucket)
+	if !ok {
+		logging.Error(errors.New("type assert failed"), "Fail to do type assert, expect: MetricBucket", "type", reflect.TypeOf(mb).Name())
+		return nil, false
+	}
+	return counter, true
+}
+
+func bucketsSum(event base.MetricEvent, values []*BucketWrap) int64 {
+	ret := int64(0)
+	for _, w := range values {
+		if counter, ok := metricBucketOf(w); ok {
+			ret += counter.Get(event)
+		}
+	}
+	return ret
+}
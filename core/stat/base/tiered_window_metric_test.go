@@ -0,0 +1,51 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTieredWindowMetric(t *testing.T) {
+	_, err := NewTieredWindowMetric(300000, 30, 10000, 20)
+	assert.NoError(t, err)
+	_, err = NewTieredWindowMetric(10000, 1, 10000, 20)
+	assert.Error(t, err)
+	_, err = NewTieredWindowMetric(300000, 7, 10000, 20)
+	assert.Error(t, err)
+	// coarse bucket 1500ms is not a multiple of fine bucket 1000ms
+	_, err = NewTieredWindowMetric(300000, 200, 10000, 10)
+	assert.Error(t, err)
+}
+
+func TestTieredWindowMetric_getSumWithTime(t *testing.T) {
+	// whole: 60000ms, 6 coarse buckets of 10000ms; fine: 10000ms, 10 fine buckets of 1000ms
+	m, err := NewTieredWindowMetric(60000, 6, 10000, 10)
+	assert.NoError(t, err)
+	start := calculateStartTime(util.CurrentTimeMillis(), 60000) + 60000
+	for i := uint64(0); i < 60; i++ {
+		m.addCountWithTime(start+i*1000, base.MetricEventPass, 1)
+	}
+	now := start + 59000
+	assert.Equal(t, int64(60), m.getSumWithTime(now, base.MetricEventPass))
+
+	// fine window starts in the middle of a coarse bucket
+	now = start + 65500
+	m.addCountWithTime(now, base.MetricEventPass, 1)
+	// coarse buckets since 10000ms (40), the part of the 50000ms coarse bucket older than
+	// the fine tier (6), and the fine buckets since 56000ms (4 old ones and the new one)
+	assert.Equal(t, int64(51), m.getSumWithTime(now, base.MetricEventPass))
+
+	// all expired
+	now = start + 125000
+	assert.Equal(t, int64(0), m.getSumWithTime(now, base.MetricEventPass))
+}
+
+func TestTieredWindowMetric_EstimatedMemoryBytes(t *testing.T) {
+	tiered, err := NewTieredWindowMetric(300000, 30, 10000, 20)
+	assert.NoError(t, err)
+	flat := NewBucketLeapArray(600, 300000)
+	assert.True(t, tiered.EstimatedMemoryBytes() < flat.EstimatedMemoryBytes())
+}
@@ -2,6 +2,7 @@ package stat
 
 import (
 	"sync/atomic"
+	"unsafe"
 
 	"github.com/alibaba/sentinel-golang/core/base"
 	"github.com/alibaba/sentinel-golang/core/config"
@@ -30,6 +31,11 @@ func NewBaseStatNode(sampleCount uint32, intervalInMs uint32) *BaseStatNode {
 	}
 }
 
+// EstimatedMemoryBytes estimates the memory used by the statistic structures of the node.
+func (n *BaseStatNode) EstimatedMemoryBytes() int64 {
+	return int64(unsafe.Sizeof(*n)) + n.arr.EstimatedMemoryBytes()
+}
+
 func (n *BaseStatNode) MetricsOnCondition(predicate base.TimePredicate) []*base.MetricItem {
 	return n.metric.SecondMetricsOnCondition(predicate)
 }
@@ -62,6 +68,15 @@ func (n *BaseStatNode) AvgRT() float64 {
 	return float64(n.metric.GetSum(base.MetricEventRt) / complete)
 }
 
+// AvgRTMicros returns the average RT in microseconds, which is useful for the sub-millisecond resources.
+func (n *BaseStatNode) AvgRTMicros() float64 {
+	complete := n.metric.GetSum(base.MetricEventComplete)
+	if complete <= 0 {
+		return float64(0)
+	}
+	return float64(n.metric.GetSum(base.MetricEventRtMicros)) / float64(complete)
+}
+
 func (n *BaseStatNode) MinRT() float64 {
 	return float64(n.metric.MinRT())
 }
@@ -0,0 +1,119 @@
+package stat
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+// ConcurrencyGauge represents the sampled concurrency (the number of in-flight requests) of a resource.
+type ConcurrencyGauge struct {
+	Resource    string
+	Timestamp   uint64
+	Concurrency int32
+}
+
+// ConcurrencyGaugeExporter is the sink of the periodically sampled concurrency gauges.
+// ExportConcurrencyGauges is invoked in the sampler goroutine, so the implementation should not block for long.
+type ConcurrencyGaugeExporter interface {
+	ExportConcurrencyGauges(gauges []*ConcurrencyGauge) error
+}
+
+var (
+	gaugeExporters   = make([]ConcurrencyGaugeExporter, 0)
+	gaugeExportersMu = new(sync.RWMutex)
+
+	gaugeSamplerMux = new(sync.Mutex)
+	// gaugeSamplerStopChan is the stop channel of the running sampler, nil if the sampler is not running.
+	gaugeSamplerStopChan chan struct{}
+)
+
+// RegisterConcurrencyGaugeExporters registers the exporters that receive the sampled concurrency gauges.
+func RegisterConcurrencyGaugeExporters(exporters ...ConcurrencyGaugeExporter) {
+	gaugeExportersMu.Lock()
+	defer gaugeExportersMu.Unlock()
+
+	gaugeExporters = append(gaugeExporters, exporters...)
+}
+
+// ClearConcurrencyGaugeExporters removes all registered concurrency gauge exporters.
+func ClearConcurrencyGaugeExporters() {
+	gaugeExportersMu.Lock()
+	defer gaugeExportersMu.Unlock()
+
+	gaugeExporters = make([]ConcurrencyGaugeExporter, 0)
+}
+
+// CurrentConcurrencyGauges takes a snapshot of the current concurrency of all resources.
+func CurrentConcurrencyGauges() []*ConcurrencyGauge {
+	now := util.CurrentTimeMillis()
+	nodes := ResourceNodeList()
+	gauges := make([]*ConcurrencyGauge, 0, len(nodes))
+	for _, n := range nodes {
+		gauges = append(gauges, &ConcurrencyGauge{
+			Resource:    n.ResourceName(),
+			Timestamp:   now,
+			Concurrency: n.CurrentGoroutineNum(),
+		})
+	}
+	return gauges
+}
+
+// InitConcurrencyGaugeSampler starts the background task that samples the concurrency of all resources
+// every intervalMs and pushes the gauges to the registered exporters. It's a no-op if the sampler is running.
+func InitConcurrencyGaugeSampler(intervalMs uint32) {
+	if intervalMs == 0 {
+		return
+	}
+	gaugeSamplerMux.Lock()
+	defer gaugeSamplerMux.Unlock()
+
+	if gaugeSamplerStopChan != nil {
+		return
+	}
+	stopChan := make(chan struct{})
+	gaugeSamplerStopChan = stopChan
+	ticker := time.NewTicker(time.Duration(intervalMs) * time.Millisecond)
+	go util.RunWithRecover(func() {
+		for {
+			select {
+			case <-ticker.C:
+				sampleAndExportConcurrencyGauges()
+			case <-stopChan:
+				ticker.Stop()
+				return
+			}
+		}
+	})
+}
+
+// StopConcurrencyGaugeSampler stops the running sampler, which could be started again by InitConcurrencyGaugeSampler.
+func StopConcurrencyGaugeSampler() {
+	gaugeSamplerMux.Lock()
+	defer gaugeSamplerMux.Unlock()
+
+	if gaugeSamplerStopChan == nil {
+		return
+	}
+	close(gaugeSamplerStopChan)
+	gaugeSamplerStopChan = nil
+}
+
+func sampleAndExportConcurrencyGauges() {
+	gaugeExportersMu.RLock()
+	exporters := make([]ConcurrencyGaugeExporter, len(gaugeExporters))
+	copy(exporters, gaugeExporters)
+	gaugeExportersMu.RUnlock()
+
+	if len(exporters) == 0 {
+		return
+	}
+	gauges := CurrentConcurrencyGauges()
+	for _, e := range exporters {
+		if err := e.ExportConcurrencyGauges(gauges); err != nil {
+			logging.Error(err, "Failed to export concurrency gauges")
+		}
+	}
+}
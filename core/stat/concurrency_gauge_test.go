@@ -0,0 +1,92 @@
+package stat
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockGaugeExporter struct {
+	gauges []*ConcurrencyGauge
+}
+
+func (e *mockGaugeExporter) ExportConcurrencyGauges(gauges []*ConcurrencyGauge) error {
+	e.gauges = gauges
+	return nil
+}
+
+func TestSampleAndExportConcurrencyGauges(t *testing.T) {
+	ResetResourceNodeMap()
+	defer ResetResourceNodeMap()
+	defer ClearConcurrencyGaugeExporters()
+
+	n := GetOrCreateResourceNode("abc", base.ResTypeCommon)
+	n.IncreaseGoroutineNum()
+	n.IncreaseGoroutineNum()
+
+	e := &mockGaugeExporter{}
+	RegisterConcurrencyGaugeExporters(e)
+	sampleAndExportConcurrencyGauges()
+
+	assert.Equal(t, 1, len(e.gauges))
+	assert.Equal(t, "abc", e.gauges[0].Resource)
+	assert.Equal(t, int32(2), e.gauges[0].Concurrency)
+	assert.True(t, e.gauges[0].Timestamp > 0)
+}
+
+type countingGaugeExporter struct {
+	mux   sync.Mutex
+	count int
+}
+
+func (e *countingGaugeExporter) ExportConcurrencyGauges([]*ConcurrencyGauge) error {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	e.count++
+	return nil
+}
+
+func (e *countingGaugeExporter) exportedCount() int {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	return e.count
+}
+
+func TestConcurrencyGaugeSamplerStop(t *testing.T) {
+	defer ClearConcurrencyGaugeExporters()
+	defer StopConcurrencyGaugeSampler()
+
+	e := &countingGaugeExporter{}
+	RegisterConcurrencyGaugeExporters(e)
+
+	// Disabled by 0 interval.
+	InitConcurrencyGaugeSampler(0)
+	assert.Nil(t, gaugeSamplerStopChan)
+
+	InitConcurrencyGaugeSampler(10)
+	assert.NotNil(t, gaugeSamplerStopChan)
+	assert.Eventually(t, func() bool {
+		return e.exportedCount() > 0
+	}, time.Second, 5*time.Millisecond)
+
+	StopConcurrencyGaugeSampler()
+	assert.Nil(t, gaugeSamplerStopChan)
+	// Wait for the tick in progress (if any).
+	time.Sleep(20 * time.Millisecond)
+	stopped := e.exportedCount()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, stopped, e.exportedCount())
+	// Stopping twice is a no-op.
+	StopConcurrencyGaugeSampler()
+
+	// Could be restarted after stopped.
+	InitConcurrencyGaugeSampler(10)
+	assert.Eventually(t, func() bool {
+		return e.exportedCount() > stopped
+	}, time.Second, 5*time.Millisecond)
+}
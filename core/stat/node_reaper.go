@@ -0,0 +1,115 @@
+package stat
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+// maxReapIntervalMs is the max interval of checking the idle resource nodes.
+const maxReapIntervalMs = 60 * 1000
+
+var (
+	pinnedResources    = make(map[string]map[string]struct{})
+	pinnedResourcesMux = new(sync.RWMutex)
+
+	reaperOnce     sync.Once
+	reaperStopChan = make(chan struct{})
+)
+
+// SetPinnedResourcesOf replaces the resources pinned by the given module (e.g. flow), whose resource nodes are
+// referenced by the module and must never be evicted by the reaper, even if they have no rules.
+// The resources with rules are always protected, so they don't need to be pinned.
+func SetPinnedResourcesOf(module string, resources []string) {
+	pinnedResourcesMux.Lock()
+	defer pinnedResourcesMux.Unlock()
+
+	m := make(map[string]struct{}, len(resources))
+	for _, res := range resources {
+		m[res] = struct{}{}
+	}
+	pinnedResources[module] = m
+}
+
+func isResourcePinned(resource string) bool {
+	pinnedResourcesMux.RLock()
+	defer pinnedResourcesMux.RUnlock()
+
+	for _, m := range pinnedResources {
+		if _, ok := m[resource]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// InitResourceNodeReaper starts the background task that evicts the resource nodes idle beyond idleTtlMs,
+// which prevents the stat nodes of the high-cardinality dynamic resources from leaking memory.
+// The nodes of the resources with rules, the pinned resources and the in-flight resources are never evicted.
+// The evicted node is recreated once the resource is accessed again, with the statistics reset.
+func InitResourceNodeReaper(idleTtlMs uint32) {
+	if idleTtlMs == 0 {
+		return
+	}
+	reaperOnce.Do(func() {
+		intervalMs := idleTtlMs
+		if intervalMs > maxReapIntervalMs {
+			intervalMs = maxReapIntervalMs
+		}
+		ticker := time.NewTicker(time.Duration(intervalMs) * time.Millisecond)
+		go util.RunWithRecover(func() {
+			for {
+				select {
+				case <-ticker.C:
+					evicted := reapIdleResourceNodes(util.CurrentTimeMillis(), uint64(idleTtlMs))
+					if len(evicted) > 0 {
+						logging.Info("[ResourceNodeReaper] Evicted idle resource nodes", "count", len(evicted), "idleTtlMs", idleTtlMs)
+						logging.Debug("[ResourceNodeReaper] Evicted idle resource nodes", "resources", evicted)
+					}
+				case <-reaperStopChan:
+					ticker.Stop()
+					return
+				}
+			}
+		})
+	})
+}
+
+func isResourceNodeEvictable(node *ResourceNode, now uint64, idleTtlMs uint64) bool {
+	if node.CurrentGoroutineNum() > 0 || now < node.LastAccessTime()+idleTtlMs {
+		return false
+	}
+	res := node.ResourceName()
+	return !base.ResourceHasExplicitRules(res) && !isResourcePinned(res)
+}
+
+// reapIdleResourceNodes evicts the resource nodes idle beyond idleTtlMs and returns the evicted resources.
+func reapIdleResourceNodes(now uint64, idleTtlMs uint64) []string {
+	candidates := make([]string, 0)
+	for _, node := range ResourceNodeList() {
+		if isResourceNodeEvictable(node, now, idleTtlMs) {
+			candidates = append(candidates, node.ResourceName())
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	rnsMux.Lock()
+	defer rnsMux.Unlock()
+
+	evicted := make([]string, 0, len(candidates))
+	for _, res := range candidates {
+		// Double check as the node may be accessed after the scan.
+		node, ok := resNodeMap[res]
+		if !ok || !isResourceNodeEvictable(node, now, idleTtlMs) {
+			continue
+		}
+		delete(resNodeMap, res)
+		evicted = append(evicted, res)
+	}
+	return evicted
+}
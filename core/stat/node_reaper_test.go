@@ -0,0 +1,47 @@
+package stat
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReapIdleResourceNodes(t *testing.T) {
+	ResetResourceNodeMap()
+	defer ResetResourceNodeMap()
+	defer base.SetRuleResourcesOf("test", nil, false)
+	defer SetPinnedResourcesOf("test", nil)
+
+	now := util.CurrentTimeMillis()
+	idle := GetOrCreateResourceNode("idle", base.ResTypeCommon)
+	active := GetOrCreateResourceNode("active", base.ResTypeCommon)
+	inFlight := GetOrCreateResourceNode("inFlight", base.ResTypeCommon)
+	withRules := GetOrCreateResourceNode("withRules", base.ResTypeCommon)
+	pinned := GetOrCreateResourceNode("pinned", base.ResTypeCommon)
+	for _, n := range []*ResourceNode{idle, inFlight, withRules, pinned} {
+		n.lastAccessTime = now - 10000
+	}
+	inFlight.IncreaseGoroutineNum()
+	base.SetRuleResourcesOf("test", []string{"withRules"}, false)
+	SetPinnedResourcesOf("test", []string{"pinned"})
+
+	evicted := reapIdleResourceNodes(now, 5000)
+	assert.Equal(t, []string{"idle"}, evicted)
+	assert.Nil(t, GetResourceNode("idle"))
+	assert.Equal(t, active, GetResourceNode("active"))
+	assert.Equal(t, inFlight, GetResourceNode("inFlight"))
+	assert.Equal(t, withRules, GetResourceNode("withRules"))
+	assert.Equal(t, pinned, GetResourceNode("pinned"))
+
+	// The evicted node is recreated on access.
+	assert.NotEqual(t, idle, GetOrCreateResourceNode("idle", base.ResTypeCommon))
+}
+
+func TestResourceNode_Touch(t *testing.T) {
+	n := NewResourceNode("abc", base.ResTypeCommon)
+	n.lastAccessTime = 0
+	n.touch()
+	assert.True(t, n.LastAccessTime() > 0)
+}
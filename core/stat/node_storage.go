@@ -1,9 +1,13 @@
 package stat
 
 import (
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
 	"github.com/alibaba/sentinel-golang/logging"
 )
 
@@ -14,8 +18,83 @@ var (
 
 	resNodeMap = make(ResourceNodeMap)
 	rnsMux     = new(sync.RWMutex)
+
+	// maxResourceAmount is the cap of the distinct resources, 0 means unlimited.
+	maxResourceAmount uint32
+	overflowStrategy  int32
+	overflowWarned    int32
+)
+
+// ResourceOverflowStrategy is the strategy of the resources beyond the max resource amount.
+type ResourceOverflowStrategy int32
+
+const (
+	// OverflowAggregate aggregates the statistics of the overflowed resources into the resource
+	// base.OverflowResourceName.
+	OverflowAggregate ResourceOverflowStrategy = iota
+	// OverflowReject rejects creating the entries of the overflowed resources with BlockTypeResourceOverflow.
+	OverflowReject
 )
 
+func (s ResourceOverflowStrategy) String() string {
+	switch s {
+	case OverflowAggregate:
+		return "Aggregate"
+	case OverflowReject:
+		return "Reject"
+	default:
+		return strconv.Itoa(int(s))
+	}
+}
+
+// SetMaxResourceAmount sets the cap of the distinct resources with statistics and the overflow strategy,
+// which protects the stat layer from unbounded memory when the resource names accidentally include IDs.
+// 0 means unlimited. The existing resource nodes are retained.
+func SetMaxResourceAmount(max uint32, strategy ResourceOverflowStrategy) {
+	atomic.StoreInt32(&overflowStrategy, int32(strategy))
+	atomic.StoreUint32(&maxResourceAmount, max)
+	atomic.StoreInt32(&overflowWarned, 0)
+}
+
+// IsResourceOverflowRejected checks whether the entry of the resource should be rejected
+// as the amount of resources exceeds the cap with OverflowReject strategy.
+func IsResourceOverflowRejected(resource string) bool {
+	max := atomic.LoadUint32(&maxResourceAmount)
+	if max == 0 || ResourceOverflowStrategy(atomic.LoadInt32(&overflowStrategy)) != OverflowReject {
+		return false
+	}
+	rnsMux.RLock()
+	defer rnsMux.RUnlock()
+
+	if _, ok := resNodeMap[resource]; ok {
+		return false
+	}
+	return resourceAmountOf(resNodeMap) >= int(max)
+}
+
+// resourceAmountOf returns the amount of the resources except for the overflow resource.
+func resourceAmountOf(m ResourceNodeMap) int {
+	if _, ok := m[base.OverflowResourceName]; ok {
+		return len(m) - 1
+	}
+	return len(m)
+}
+
+func init() {
+	selfmetric.RegisterGauge("stat.resourceNodeCount", func() float64 {
+		rnsMux.RLock()
+		defer rnsMux.RUnlock()
+		return float64(len(resNodeMap))
+	})
+	selfmetric.RegisterGauge("stat.memoryBytes", func() float64 {
+		total := inboundNode.EstimatedMemoryBytes()
+		for _, n := range ResourceNodeList() {
+			total += n.EstimatedMemoryBytes()
+		}
+		return float64(total)
+	})
+}
+
 // InboundNode returns the global inbound statistic node.
 func InboundNode() *ResourceNode {
 	return inboundNode
@@ -45,15 +124,25 @@ func GetOrCreateResourceNode(resource string, resourceType base.ResourceType) *R
 	if node != nil {
 		return node
 	}
+	lockStart := time.Now()
 	rnsMux.Lock()
 	defer rnsMux.Unlock()
+	selfmetric.RecordLockWait("stat.resourceNodes", lockStart)
 
 	node = resNodeMap[resource]
 	if node != nil {
 		return node
 	}
 
-	if len(resNodeMap) >= int(base.DefaultMaxResourceAmount) {
+	if max := atomic.LoadUint32(&maxResourceAmount); max > 0 {
+		if resourceAmountOf(resNodeMap) >= int(max) {
+			if atomic.CompareAndSwapInt32(&overflowWarned, 0, 1) {
+				logging.Warn("Resource amount reaches the max, the statistics of the new resources are aggregated into the overflow resource",
+					"maxResourceAmount", max, "overflowResource", base.OverflowResourceName, "resource", resource)
+			}
+			return overflowNodeOf(resourceType)
+		}
+	} else if len(resNodeMap) >= int(base.DefaultMaxResourceAmount) {
 		logging.Warn("Resource amount exceeds the threshold", "maxResourceAmount", base.DefaultMaxResourceAmount)
 	}
 	node = NewResourceNode(resource, resourceType)
@@ -61,6 +150,16 @@ func GetOrCreateResourceNode(resource string, resourceType base.ResourceType) *R
 	return node
 }
 
+// overflowNodeOf returns the node of the overflow resource, it must be called with rnsMux locked.
+func overflowNodeOf(resourceType base.ResourceType) *ResourceNode {
+	node := resNodeMap[base.OverflowResourceName]
+	if node == nil {
+		node = NewResourceNode(base.OverflowResourceName, resourceType)
+		resNodeMap[base.OverflowResourceName] = node
+	}
+	return node
+}
+
 func ResetResourceNodeMap() {
 	rnsMux.Lock()
 	defer rnsMux.Unlock()
@@ -0,0 +1,42 @@
+package stat
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOrCreateResourceNode_MaxResourceAmount(t *testing.T) {
+	defer ResetResourceNodeMap()
+	defer SetMaxResourceAmount(0, OverflowAggregate)
+
+	t.Run("Aggregate", func(t *testing.T) {
+		ResetResourceNodeMap()
+		SetMaxResourceAmount(2, OverflowAggregate)
+
+		n1 := GetOrCreateResourceNode("res1", base.ResTypeCommon)
+		n2 := GetOrCreateResourceNode("res2", base.ResTypeCommon)
+		assert.Equal(t, "res1", n1.ResourceName())
+		assert.Equal(t, "res2", n2.ResourceName())
+
+		n3 := GetOrCreateResourceNode("res3", base.ResTypeCommon)
+		n4 := GetOrCreateResourceNode("res4", base.ResTypeCommon)
+		assert.Equal(t, base.OverflowResourceName, n3.ResourceName())
+		assert.Equal(t, n3, n4)
+		assert.Equal(t, n1, GetOrCreateResourceNode("res1", base.ResTypeCommon))
+		assert.Nil(t, GetResourceNode("res3"))
+		assert.Equal(t, 3, len(ResourceNodeList()))
+		assert.False(t, IsResourceOverflowRejected("res3"))
+	})
+
+	t.Run("Reject", func(t *testing.T) {
+		ResetResourceNodeMap()
+		SetMaxResourceAmount(1, OverflowReject)
+
+		assert.False(t, IsResourceOverflowRejected("res1"))
+		GetOrCreateResourceNode("res1", base.ResTypeCommon)
+		assert.False(t, IsResourceOverflowRejected("res1"))
+		assert.True(t, IsResourceOverflowRejected("res2"))
+	})
+}
@@ -1,8 +1,12 @@
 package stat
 
 import (
+	"sync"
+	"sync/atomic"
+
 	"github.com/alibaba/sentinel-golang/core/base"
 	"github.com/alibaba/sentinel-golang/core/config"
+	"github.com/alibaba/sentinel-golang/util"
 )
 
 type ResourceNode struct {
@@ -10,14 +14,22 @@ type ResourceNode struct {
 
 	resourceName string
 	resourceType base.ResourceType
+	// lastAccessTime is the last time (in ms) the resource is accessed, in seconds precision.
+	lastAccessTime uint64
+
+	// categoryNodes holds the *BaseStatNode of each base.RequestCategory (indexed by category-1),
+	// which is created on the first request of the category.
+	categoryNodes [base.RequestCategoryCount]atomic.Value
+	categoryMux   sync.Mutex
 }
 
 // NewResourceNode creates a new resource node with given name and classification.
 func NewResourceNode(resourceName string, resourceType base.ResourceType) *ResourceNode {
 	return &ResourceNode{
-		BaseStatNode: *NewBaseStatNode(config.MetricStatisticSampleCount(), config.MetricStatisticIntervalMs()),
-		resourceName: resourceName,
-		resourceType: resourceType,
+		BaseStatNode:   *NewBaseStatNode(config.MetricStatisticSampleCount(), config.MetricStatisticIntervalMs()),
+		resourceName:   resourceName,
+		resourceType:   resourceType,
+		lastAccessTime: util.CurrentTimeMillis(),
 	}
 }
 
@@ -28,3 +40,57 @@ func (n *ResourceNode) ResourceType() base.ResourceType {
 func (n *ResourceNode) ResourceName() string {
 	return n.resourceName
 }
+
+// LastAccessTime returns the last time (in ms) the resource is accessed, in seconds precision.
+func (n *ResourceNode) LastAccessTime() uint64 {
+	return atomic.LoadUint64(&n.lastAccessTime)
+}
+
+// CategoryNode returns the statistic node of the requests of the given category,
+// nil if the category is CategoryNone or there's no request of the category yet.
+func (n *ResourceNode) CategoryNode(category base.RequestCategory) *BaseStatNode {
+	if category == base.CategoryNone || !category.IsValid() {
+		return nil
+	}
+	node, _ := n.categoryNodes[category-1].Load().(*BaseStatNode)
+	return node
+}
+
+// GetOrCreateCategoryNode returns the statistic node of the requests of the given category,
+// nil if the category is CategoryNone.
+func (n *ResourceNode) GetOrCreateCategoryNode(category base.RequestCategory) *BaseStatNode {
+	if category == base.CategoryNone || !category.IsValid() {
+		return nil
+	}
+	if node := n.CategoryNode(category); node != nil {
+		return node
+	}
+	n.categoryMux.Lock()
+	defer n.categoryMux.Unlock()
+	if node := n.CategoryNode(category); node != nil {
+		return node
+	}
+	node := NewBaseStatNode(config.MetricStatisticSampleCount(), config.MetricStatisticIntervalMs())
+	n.categoryNodes[category-1].Store(node)
+	return node
+}
+
+// EstimatedMemoryBytes estimates the memory used by the statistic structures of the node, including the category nodes.
+func (n *ResourceNode) EstimatedMemoryBytes() int64 {
+	total := n.BaseStatNode.EstimatedMemoryBytes()
+	for c := base.CategoryRead; c <= base.CategoryWrite; c++ {
+		if node := n.CategoryNode(c); node != nil {
+			total += node.EstimatedMemoryBytes()
+		}
+	}
+	return total
+}
+
+// touch refreshes the last access time. The time is updated at most once per second,
+// which avoids writing the shared cache line on every entry.
+func (n *ResourceNode) touch() {
+	now := util.CurrentTimeMillis()
+	if now >= atomic.LoadUint64(&n.lastAccessTime)+1000 {
+		atomic.StoreUint64(&n.lastAccessTime, now)
+	}
+}
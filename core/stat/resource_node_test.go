@@ -0,0 +1,56 @@
+package stat
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceNode_CategoryNode(t *testing.T) {
+	node := NewResourceNode("abc", base.ResTypeCommon)
+	assert.Nil(t, node.CategoryNode(base.CategoryRead))
+	assert.Nil(t, node.GetOrCreateCategoryNode(base.CategoryNone))
+	assert.Nil(t, node.GetOrCreateCategoryNode(base.RequestCategory(10)))
+
+	read := node.GetOrCreateCategoryNode(base.CategoryRead)
+	assert.NotNil(t, read)
+	assert.True(t, read == node.CategoryNode(base.CategoryRead))
+	assert.True(t, read == node.GetOrCreateCategoryNode(base.CategoryRead))
+	assert.Nil(t, node.CategoryNode(base.CategoryWrite))
+	assert.Equal(t, node.BaseStatNode.EstimatedMemoryBytes()+read.EstimatedMemoryBytes(), node.EstimatedMemoryBytes())
+}
+
+func TestSlot_RecordCategory(t *testing.T) {
+	node := NewResourceNode("abc", base.ResTypeCommon)
+	newCtx := func(category base.RequestCategory) *base.EntryContext {
+		ctx := base.NewEmptyEntryContext()
+		ctx.Resource = base.NewResourceWrapper("abc", base.ResTypeCommon, base.Outbound)
+		ctx.StatNode = node
+		ctx.Input = &base.SentinelInput{AcquireCount: 1}
+		if category != base.CategoryNone {
+			ctx.Input.Attachments = map[interface{}]interface{}{base.CategoryAttachmentKey: category}
+		}
+		return ctx
+	}
+	s := &Slot{}
+	for i := 0; i < 3; i++ {
+		ctx := newCtx(base.CategoryRead)
+		s.OnEntryPassed(ctx)
+		s.OnCompleted(ctx)
+	}
+	s.OnEntryBlocked(newCtx(base.CategoryWrite), nil)
+	ctx := newCtx(base.CategoryNone)
+	s.OnEntryPassed(ctx)
+	s.OnCompleted(ctx)
+
+	assert.Equal(t, int64(4), node.GetSum(base.MetricEventPass))
+	assert.Equal(t, int64(1), node.GetSum(base.MetricEventBlock))
+	read := node.CategoryNode(base.CategoryRead)
+	assert.Equal(t, int64(3), read.GetSum(base.MetricEventPass))
+	assert.Equal(t, int64(3), read.GetSum(base.MetricEventComplete))
+	assert.Equal(t, int32(0), read.CurrentGoroutineNum())
+	write := node.CategoryNode(base.CategoryWrite)
+	assert.Equal(t, int64(0), write.GetSum(base.MetricEventPass))
+	assert.Equal(t, int64(1), write.GetSum(base.MetricEventBlock))
+}
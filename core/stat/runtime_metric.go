@@ -0,0 +1,42 @@
+package stat
+
+import (
+	"runtime"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+)
+
+const (
+	// RuntimeMetricResourcePrefix is the prefix of the resource names of the runtime metric items.
+	RuntimeMetricResourcePrefix = "__runtime__:"
+
+	RuntimeMetricGoroutines     = RuntimeMetricResourcePrefix + "goroutines"
+	RuntimeMetricHeapAllocBytes = RuntimeMetricResourcePrefix + "heap_alloc_bytes"
+	RuntimeMetricGcCount        = RuntimeMetricResourcePrefix + "gc_count"
+)
+
+// RuntimeMetricItems samples the Go runtime metrics and returns them as metric items of the given timestamp,
+// so that they can be written along with the resource metrics of the same time bucket.
+// Each item has the classification base.MetricItemClassificationRuntime and carries the sampled value in PassQps.
+// The GC count is the cumulative count since the process started.
+//
+// It calls runtime.ReadMemStats, which stops the world for a short while, so it should only be called
+// by the periodic tasks rather than on the request path.
+func RuntimeMetricItems(timestamp uint64) []*base.MetricItem {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return []*base.MetricItem{
+		newRuntimeMetricItem(RuntimeMetricGoroutines, timestamp, uint64(runtime.NumGoroutine())),
+		newRuntimeMetricItem(RuntimeMetricHeapAllocBytes, timestamp, ms.HeapAlloc),
+		newRuntimeMetricItem(RuntimeMetricGcCount, timestamp, uint64(ms.NumGC)),
+	}
+}
+
+func newRuntimeMetricItem(resource string, timestamp uint64, value uint64) *base.MetricItem {
+	return &base.MetricItem{
+		Resource:       resource,
+		Classification: base.MetricItemClassificationRuntime,
+		Timestamp:      timestamp,
+		PassQps:        value,
+	}
+}
@@ -0,0 +1,25 @@
+package stat
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuntimeMetricItems(t *testing.T) {
+	runtime.GC()
+	items := RuntimeMetricItems(1581959013000)
+	assert.Equal(t, 3, len(items))
+
+	byName := make(map[string]*base.MetricItem, len(items))
+	for _, item := range items {
+		assert.Equal(t, uint64(1581959013000), item.Timestamp)
+		assert.Equal(t, base.MetricItemClassificationRuntime, item.Classification)
+		byName[item.Resource] = item
+	}
+	assert.True(t, byName[RuntimeMetricGoroutines].PassQps > 0)
+	assert.True(t, byName[RuntimeMetricHeapAllocBytes].PassQps > 0)
+	assert.True(t, byName[RuntimeMetricGcCount].PassQps > 0)
+}
@@ -9,6 +9,7 @@ type ResourceNodePrepareSlot struct {
 
 func (s *ResourceNodePrepareSlot) Prepare(ctx *base.EntryContext) {
 	node := GetOrCreateResourceNode(ctx.Resource.Name(), ctx.Resource.Classification())
+	node.touch()
 	// Set the resource node to the context.
 	ctx.StatNode = node
 }
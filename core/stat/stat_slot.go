@@ -2,7 +2,6 @@ package stat
 
 import (
 	"github.com/alibaba/sentinel-golang/core/base"
-	"github.com/alibaba/sentinel-golang/util"
 )
 
 type Slot struct {
@@ -10,6 +9,9 @@ type Slot struct {
 
 func (s *Slot) OnEntryPassed(ctx *base.EntryContext) {
 	s.recordPassFor(ctx.StatNode, ctx.Input.AcquireCount)
+	if cn := categoryNodeOf(ctx); cn != nil {
+		s.recordPassFor(cn, ctx.Input.AcquireCount)
+	}
 	if ctx.Resource.FlowType() == base.Inbound {
 		s.recordPassFor(InboundNode(), ctx.Input.AcquireCount)
 	}
@@ -17,18 +19,39 @@ func (s *Slot) OnEntryPassed(ctx *base.EntryContext) {
 
 func (s *Slot) OnEntryBlocked(ctx *base.EntryContext, blockError *base.BlockError) {
 	s.recordBlockFor(ctx.StatNode, ctx.Input.AcquireCount)
+	if cn := categoryNodeOf(ctx); cn != nil {
+		s.recordBlockFor(cn, ctx.Input.AcquireCount)
+	}
 	if ctx.Resource.FlowType() == base.Inbound {
 		s.recordBlockFor(InboundNode(), ctx.Input.AcquireCount)
 	}
 }
 
 func (s *Slot) OnCompleted(ctx *base.EntryContext) {
-	rt := util.CurrentTimeMillis() - ctx.StartTime()
+	rt := base.CurrentTimeMillisForRt() - ctx.StartTime()
+	rtNs := base.CurrentTimeNanoForRt() - ctx.StartTimeNano()
 	ctx.PutRt(rt)
-	s.recordCompleteFor(ctx.StatNode, ctx.Input.AcquireCount, rt, ctx.Err())
+	ctx.PutRtNanos(rtNs)
+	s.recordCompleteFor(ctx.StatNode, ctx.Input.AcquireCount, rt, rtNs, ctx.Err())
+	if cn := categoryNodeOf(ctx); cn != nil {
+		s.recordCompleteFor(cn, ctx.Input.AcquireCount, rt, rtNs, ctx.Err())
+	}
 	if ctx.Resource.FlowType() == base.Inbound {
-		s.recordCompleteFor(InboundNode(), ctx.Input.AcquireCount, rt, ctx.Err())
+		s.recordCompleteFor(InboundNode(), ctx.Input.AcquireCount, rt, rtNs, ctx.Err())
+	}
+}
+
+// categoryNodeOf returns the statistic node of the category of the entry, nil if the entry is not categorized.
+func categoryNodeOf(ctx *base.EntryContext) *BaseStatNode {
+	category := ctx.Category()
+	if category == base.CategoryNone {
+		return nil
+	}
+	node, ok := ctx.StatNode.(*ResourceNode)
+	if !ok || node == nil {
+		return nil
 	}
+	return node.GetOrCreateCategoryNode(category)
 }
 
 func (s *Slot) recordPassFor(sn base.StatNode, count uint32) {
@@ -46,7 +69,7 @@ func (s *Slot) recordBlockFor(sn base.StatNode, count uint32) {
 	sn.AddCount(base.MetricEventBlock, int64(count))
 }
 
-func (s *Slot) recordCompleteFor(sn base.StatNode, count uint32, rt uint64, err error) {
+func (s *Slot) recordCompleteFor(sn base.StatNode, count uint32, rt uint64, rtNs uint64, err error) {
 	if sn == nil {
 		return
 	}
@@ -54,6 +77,7 @@ func (s *Slot) recordCompleteFor(sn base.StatNode, count uint32, rt uint64, err
 		sn.AddCount(base.MetricEventError, int64(count))
 	}
 	sn.AddCount(base.MetricEventRt, int64(rt))
+	sn.AddCount(base.MetricEventRtMicros, int64(rtNs/1000))
 	sn.AddCount(base.MetricEventComplete, int64(count))
 	sn.DecreaseGoroutineNum()
 }
@@ -56,11 +56,27 @@ func (t AdaptiveStrategy) String() string {
 	}
 }
 
+// ExemptAttachmentKey is the key of the entry attachment that marks the entry exempt from all system rules.
+// e.g. api.Entry(res, api.WithTrafficType(base.Inbound), api.WithAttachment(system.ExemptAttachmentKey, true))
+const ExemptAttachmentKey = "sentinel.system.exempt"
+
 type Rule struct {
 	ID           string           `json:"id,omitempty"`
 	MetricType   MetricType       `json:"metricType"`
 	TriggerCount float64          `json:"triggerCount"`
 	Strategy     AdaptiveStrategy `json:"strategy"`
+	// ExemptResources represents the resources that will never be blocked by the rule,
+	// e.g. health checks and admin endpoints.
+	ExemptResources []string `json:"exemptResources,omitempty"`
+}
+
+func (r *Rule) isExempt(res string) bool {
+	for _, exempt := range r.ExemptResources {
+		if exempt == res {
+			return true
+		}
+	}
+	return false
 }
 
 func (r *Rule) String() string {
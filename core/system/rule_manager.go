@@ -2,7 +2,11 @@ package system
 
 import (
 	"sync"
+	"time"
 
+	"github.com/alibaba/sentinel-golang/core/audit"
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/selfmetric"
 	"github.com/alibaba/sentinel-golang/logging"
 	"github.com/alibaba/sentinel-golang/util"
 	"github.com/pkg/errors"
@@ -50,6 +54,8 @@ func getRules() []*Rule {
 
 // LoadRules loads given system rules to the rule manager, while all previous rules will be replaced.
 func LoadRules(rules []*Rule) (bool, error) {
+	defer selfmetric.RecordRuleUpdate("system", time.Now())
+
 	m := buildRuleMap(rules)
 
 	if err := onRuleUpdate(m); err != nil {
@@ -69,9 +75,11 @@ func ClearRules() error {
 func onRuleUpdate(r RuleMap) error {
 	start := util.CurrentTimeNano()
 	ruleMapMux.Lock()
+	oldRules := rulesFrom(ruleMap)
 	defer func() {
 		ruleMapMux.Unlock()
 		logging.Debug("time statistic(ns) for updating system rule", "timeCost", util.CurrentTimeNano()-start)
+		audit.RecordRuleChange("system", ruleStringsOf(oldRules), ruleStringsOf(rulesFrom(r)))
 		if len(r) > 0 {
 			logging.Info("[SystemRuleManager] System rules loaded", "rules", r)
 		} else {
@@ -79,9 +87,26 @@ func onRuleUpdate(r RuleMap) error {
 		}
 	}()
 	ruleMap = r
+	// System rules apply to all the inbound resources.
+	base.SetRuleResourcesOf("system", nil, len(r) > 0)
 	return nil
 }
 
+func rulesFrom(m RuleMap) []*Rule {
+	rules := make([]*Rule, 0)
+	for _, rs := range m {
+		rules = append(rules, rs...)
+	}
+	return rules
+}
+func ruleStringsOf(rules []*Rule) []string {
+	ret := make([]string, 0, len(rules))
+	for _, r := range rules {
+		ret = append(ret, r.String())
+	}
+	return ret
+}
+
 func buildRuleMap(rules []*Rule) RuleMap {
 	m := make(RuleMap)
 
@@ -8,13 +8,28 @@ import (
 type AdaptiveSlot struct {
 }
 
+// RulesIndexed implements base.IndexedRuleCheckSlot, as all the rules are registered to the rule resource index.
+func (s *AdaptiveSlot) RulesIndexed() bool {
+	return true
+}
+
 func (s *AdaptiveSlot) Check(ctx *base.EntryContext) *base.TokenResult {
 	if ctx == nil || ctx.Resource == nil || ctx.Resource.FlowType() != base.Inbound {
 		return nil
 	}
+	if !Enabled() {
+		return ctx.RuleCheckResult
+	}
+	if isExemptEntry(ctx) {
+		return nil
+	}
 	rules := getRules()
 	result := ctx.RuleCheckResult
+	res := ctx.Resource.Name()
 	for _, rule := range rules {
+		if rule.isExempt(res) {
+			continue
+		}
 		passed, snapshotValue := s.doCheckRule(rule)
 		if passed {
 			continue
@@ -29,6 +44,14 @@ func (s *AdaptiveSlot) Check(ctx *base.EntryContext) *base.TokenResult {
 	return result
 }
 
+func isExemptEntry(ctx *base.EntryContext) bool {
+	if ctx.Input == nil || ctx.Input.Attachments == nil {
+		return false
+	}
+	exempt, ok := ctx.Input.Attachments[ExemptAttachmentKey].(bool)
+	return ok && exempt
+}
+
 func (s *AdaptiveSlot) doCheckRule(rule *Rule) (bool, float64) {
 	threshold := rule.TriggerCount
 	switch rule.MetricType {
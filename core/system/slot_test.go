@@ -109,3 +109,38 @@ func TestDoCheckRuleDefault(t *testing.T) {
 	assert.Equal(t, true, isOK)
 	assert.Equal(t, float64(0), v)
 }
+
+func TestCheckExemption(t *testing.T) {
+	var sas *AdaptiveSlot
+	_, err := LoadRules([]*Rule{
+		{
+			MetricType:      Concurrency,
+			TriggerCount:    0.5,
+			ExemptResources: []string{"health"},
+		},
+	})
+	assert.Nil(t, err)
+	defer ClearRules()
+	stat.InboundNode().IncreaseGoroutineNum()
+	defer stat.InboundNode().DecreaseGoroutineNum()
+
+	t.Run("NotExempt", func(t *testing.T) {
+		rw := base.NewResourceWrapper("test", base.ResTypeCommon, base.Inbound)
+		r := sas.Check(&base.EntryContext{Resource: rw, Input: &base.SentinelInput{}})
+		assert.True(t, r != nil && r.IsBlocked())
+	})
+
+	t.Run("ExemptResource", func(t *testing.T) {
+		rw := base.NewResourceWrapper("health", base.ResTypeCommon, base.Inbound)
+		r := sas.Check(&base.EntryContext{Resource: rw, Input: &base.SentinelInput{}})
+		assert.True(t, r == nil || r.IsPass())
+	})
+
+	t.Run("ExemptAttachment", func(t *testing.T) {
+		rw := base.NewResourceWrapper("test", base.ResTypeCommon, base.Inbound)
+		r := sas.Check(&base.EntryContext{Resource: rw, Input: &base.SentinelInput{
+			Attachments: map[interface{}]interface{}{ExemptAttachmentKey: true},
+		}})
+		assert.True(t, r == nil || r.IsPass())
+	})
+}
@@ -0,0 +1,26 @@
+package system
+
+import (
+	"sync/atomic"
+
+	"github.com/alibaba/sentinel-golang/logging"
+)
+
+// disabled indicates whether the system adaptive protection is disabled at runtime, see SetEnabled.
+var disabled int32
+
+// SetEnabled enables or disables the system adaptive protection at runtime, which is enabled by default.
+// The system adaptive slot is short-circuited while disabled, but the rules are kept.
+func SetEnabled(e bool) {
+	if e {
+		atomic.StoreInt32(&disabled, 0)
+	} else {
+		atomic.StoreInt32(&disabled, 1)
+	}
+	logging.Info("[System] System adaptive protection was toggled", "enabled", e)
+}
+
+// Enabled returns whether the system adaptive protection is enabled.
+func Enabled() bool {
+	return atomic.LoadInt32(&disabled) == 0
+}
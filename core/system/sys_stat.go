@@ -8,6 +8,7 @@ import (
 
 	"github.com/alibaba/sentinel-golang/logging"
 	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
 	"github.com/shirou/gopsutil/cpu"
 	"github.com/shirou/gopsutil/load"
 )
@@ -16,10 +17,18 @@ const (
 	notRetrievedValue float64 = -1
 )
 
+// CpuUsageFunc retrieves the pre-computed CPU usage, and the valid range of the usage is [0.0, 1.0].
+type CpuUsageFunc func() (float64, error)
+
 var (
 	currentLoad     atomic.Value
 	currentCpuUsage atomic.Value
 
+	// cpuUsageSmoothingFactor is the bits (math.Float64bits) of the EWMA smoothing factor of CPU usage,
+	// 0 means no smoothing.
+	cpuUsageSmoothingFactor uint64
+	customCpuUsageFunc      atomic.Value
+
 	prevCpuStat *cpu.TimesStat
 	initOnce    sync.Once
 
@@ -29,6 +38,25 @@ var (
 func init() {
 	currentLoad.Store(notRetrievedValue)
 	currentCpuUsage.Store(notRetrievedValue)
+	customCpuUsageFunc.Store(CpuUsageFunc(nil))
+}
+
+// SetCpuUsageSmoothingFactor sets the EWMA smoothing factor of the CPU usage, the valid range is [0.0, 1.0).
+// The smoothed usage is calculated by: factor * previous + (1 - factor) * current,
+// so the larger the factor is, the smoother the usage is. 0 means no smoothing (the raw sample is used).
+// It should be set before the collector starts.
+func SetCpuUsageSmoothingFactor(factor float64) error {
+	if factor < 0 || factor >= 1 {
+		return errors.Errorf("invalid CPU usage smoothing factor: %f, valid range is [0.0, 1.0)", factor)
+	}
+	atomic.StoreUint64(&cpuUsageSmoothingFactor, math.Float64bits(factor))
+	return nil
+}
+
+// SetCustomCpuUsage sets the function that provides pre-computed CPU usage, which replaces the default CPU usage
+// retrieval of the collector. Setting nil restores the default retrieval.
+func SetCustomCpuUsage(fn CpuUsageFunc) {
+	customCpuUsageFunc.Store(fn)
 }
 
 func InitCollector(intervalMs uint32) {
@@ -55,20 +83,29 @@ func InitCollector(intervalMs uint32) {
 }
 
 func retrieveAndUpdateSystemStat() {
-	cpuStats, err := cpu.Times(false)
-	if err != nil {
-		logging.Warn("Failed to retrieve current CPU usage", "err", err)
+	if fn, ok := customCpuUsageFunc.Load().(CpuUsageFunc); ok && fn != nil {
+		usage, err := fn()
+		if err != nil {
+			logging.Warn("Failed to retrieve current CPU usage from custom function", "err", err)
+		} else {
+			storeCpuUsage(math.Min(1.0, math.Max(0.0, usage)))
+		}
+	} else {
+		cpuStats, err := cpu.Times(false)
+		if err != nil {
+			logging.Warn("Failed to retrieve current CPU usage", "err", err)
+		}
+		if len(cpuStats) > 0 {
+			curCpuStat := &cpuStats[0]
+			recordCpuUsage(prevCpuStat, curCpuStat)
+			// Cache the latest CPU stat info.
+			prevCpuStat = curCpuStat
+		}
 	}
 	loadStat, err := load.Avg()
 	if err != nil {
 		logging.Warn("Failed to retrieve current system load", "err", err)
 	}
-	if len(cpuStats) > 0 {
-		curCpuStat := &cpuStats[0]
-		recordCpuUsage(prevCpuStat, curCpuStat)
-		// Cache the latest CPU stat info.
-		prevCpuStat = curCpuStat
-	}
 	if loadStat != nil {
 		currentLoad.Store(loadStat.Load1)
 	}
@@ -90,8 +127,18 @@ func recordCpuUsage(prev, curCpuStat *cpu.TimesStat) {
 			cpuUsage = math.Max(0.0, cpuUsage)
 			cpuUsage = math.Min(1.0, cpuUsage)
 		}
-		currentCpuUsage.Store(cpuUsage)
+		storeCpuUsage(cpuUsage)
+	}
+}
+
+// storeCpuUsage stores the CPU usage sample with EWMA smoothing.
+func storeCpuUsage(usage float64) {
+	prev := CurrentCpuUsage()
+	factor := math.Float64frombits(atomic.LoadUint64(&cpuUsageSmoothingFactor))
+	if factor > 0 && prev != notRetrievedValue {
+		usage = factor*prev + (1-factor)*usage
 	}
+	currentCpuUsage.Store(usage)
 }
 
 func calculateTotalCpuTick(stat *cpu.TimesStat) float64 {
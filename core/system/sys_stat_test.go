@@ -70,3 +70,33 @@ func TestCurrentCpuUsage(t *testing.T) {
 	cpuUsage = CurrentCpuUsage()
 	assert.Equal(t, v, cpuUsage)
 }
+
+func TestStoreCpuUsageWithSmoothing(t *testing.T) {
+	defer currentCpuUsage.Store(notRetrievedValue)
+	defer SetCpuUsageSmoothingFactor(0)
+
+	assert.NotNil(t, SetCpuUsageSmoothingFactor(1))
+	assert.Nil(t, SetCpuUsageSmoothingFactor(0.5))
+
+	storeCpuUsage(0.8)
+	assert.InEpsilon(t, 0.8, CurrentCpuUsage(), 0.001)
+	storeCpuUsage(0.2)
+	assert.InEpsilon(t, 0.5, CurrentCpuUsage(), 0.001)
+}
+
+func TestSetCustomCpuUsage(t *testing.T) {
+	defer currentCpuUsage.Store(notRetrievedValue)
+	defer SetCustomCpuUsage(nil)
+
+	SetCustomCpuUsage(func() (float64, error) {
+		return 0.66, nil
+	})
+	retrieveAndUpdateSystemStat()
+	assert.InEpsilon(t, 0.66, CurrentCpuUsage(), 0.001)
+
+	SetCustomCpuUsage(func() (float64, error) {
+		return 1.5, nil
+	})
+	retrieveAndUpdateSystemStat()
+	assert.Equal(t, 1.0, CurrentCpuUsage())
+}
@@ -0,0 +1,285 @@
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/hotspot"
+	"github.com/alibaba/sentinel-golang/core/isolation"
+	"github.com/alibaba/sentinel-golang/core/system"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// APIVersionV1 is the version of the bundle format.
+	APIVersionV1 = "sentinel/v1"
+	// Kind is the kind of the bundle document.
+	Kind = "RuleBundle"
+
+	ModuleFlow           = "flow"
+	ModuleCircuitBreaker = "circuitBreaker"
+	ModuleHotspot        = "hotspot"
+	ModuleIsolation      = "isolation"
+	ModuleSystem         = "system"
+)
+
+// Bundle is the declarative rule bundle.
+type Bundle struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Metadata   Metadata `json:"metadata"`
+	Spec       Spec     `json:"spec"`
+}
+
+// Metadata is the metadata of the bundle, which doesn't affect the rules.
+type Metadata struct {
+	// Name is the name of the bundle, e.g. the service name, which is required.
+	Name string `json:"name"`
+	// Revision is the revision of the bundle, e.g. the commit ID of the policy repository.
+	Revision    string            `json:"revision,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Spec holds the rules of all the modules.
+type Spec struct {
+	Flow           []*flow.Rule           `json:"flow,omitempty"`
+	CircuitBreaker []*circuitbreaker.Rule `json:"circuitBreaker,omitempty"`
+	Hotspot        []*hotspot.Rule        `json:"hotspot,omitempty"`
+	Isolation      []*isolation.Rule      `json:"isolation,omitempty"`
+	System         []*system.Rule         `json:"system,omitempty"`
+}
+
+// RuleError is the validation error of the bundle. Module and Index locate the invalid rule,
+// and Module is empty for the errors of the bundle itself (e.g. the metadata).
+type RuleError struct {
+	Module string
+	Index  int
+	Err    error
+}
+
+func (e RuleError) Error() string {
+	if len(e.Module) == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("spec.%s[%d]: %s", e.Module, e.Index, e.Err.Error())
+}
+
+// Parse parses the bundle in YAML (or JSON, as a subset of YAML). The unknown fields are rejected.
+func Parse(src []byte) (*Bundle, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(src, &doc); err != nil {
+		return nil, errors.Wrap(err, "invalid YAML of the rule bundle")
+	}
+	normalized, err := normalizeYAML(doc)
+	if err != nil {
+		return nil, err
+	}
+	// Decode via JSON so that the json tags and the JSON unmarshalers of the rules apply.
+	j, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid rule bundle")
+	}
+	dec := json.NewDecoder(bytes.NewReader(j))
+	dec.DisallowUnknownFields()
+	b := &Bundle{}
+	if err := dec.Decode(b); err != nil {
+		return nil, errors.Wrap(err, "invalid rule bundle")
+	}
+	if b.APIVersion != APIVersionV1 {
+		return nil, errors.Errorf("unsupported apiVersion of the rule bundle: %q", b.APIVersion)
+	}
+	if b.Kind != Kind {
+		return nil, errors.Errorf("unsupported kind of the rule bundle: %q", b.Kind)
+	}
+	return b, nil
+}
+
+// ParseFile parses the bundle file, see Parse.
+func ParseFile(path string) (*Bundle, error) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(src)
+}
+
+// Marshal marshals the bundle to YAML.
+func Marshal(b *Bundle) ([]byte, error) {
+	j, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	// Unmarshal the JSON into yaml.MapSlice to keep the order of the fields.
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(j, &doc); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(doc)
+}
+
+// Validate validates the bundle and all the rules in it, and returns the errors of all the invalid parts.
+// The rule IDs must be unique in each module.
+func Validate(b *Bundle) []RuleError {
+	if b == nil {
+		return []RuleError{{Err: errors.New("nil rule bundle")}}
+	}
+	ret := make([]RuleError, 0)
+	if b.APIVersion != APIVersionV1 {
+		ret = append(ret, RuleError{Err: errors.Errorf("unsupported apiVersion: %q", b.APIVersion)})
+	}
+	if b.Kind != Kind {
+		ret = append(ret, RuleError{Err: errors.Errorf("unsupported kind: %q", b.Kind)})
+	}
+	if len(b.Metadata.Name) == 0 {
+		ret = append(ret, RuleError{Err: errors.New("empty metadata.name")})
+	}
+	for k := range b.Metadata.Labels {
+		if len(k) == 0 {
+			ret = append(ret, RuleError{Err: errors.New("empty key of metadata.labels")})
+		}
+	}
+
+	s := b.Spec
+	ids := newIdChecker()
+	for i, r := range s.Flow {
+		ret = appendRuleError(ret, ModuleFlow, i, flow.IsValidRule(r))
+		if r != nil {
+			ret = appendRuleError(ret, ModuleFlow, i, ids.check(ModuleFlow, r.ID))
+		}
+	}
+	for i, r := range s.CircuitBreaker {
+		ret = appendRuleError(ret, ModuleCircuitBreaker, i, circuitbreaker.IsValid(r))
+		if r != nil {
+			ret = appendRuleError(ret, ModuleCircuitBreaker, i, ids.check(ModuleCircuitBreaker, r.Id))
+		}
+	}
+	for i, r := range s.Hotspot {
+		ret = appendRuleError(ret, ModuleHotspot, i, hotspot.IsValidRule(r))
+		if r != nil {
+			ret = appendRuleError(ret, ModuleHotspot, i, ids.check(ModuleHotspot, r.ID))
+		}
+	}
+	for i, r := range s.Isolation {
+		ret = appendRuleError(ret, ModuleIsolation, i, isolation.IsValid(r))
+		if r != nil {
+			ret = appendRuleError(ret, ModuleIsolation, i, ids.check(ModuleIsolation, r.ID))
+		}
+	}
+	for i, r := range s.System {
+		ret = appendRuleError(ret, ModuleSystem, i, system.IsValidSystemRule(r))
+		if r != nil {
+			ret = appendRuleError(ret, ModuleSystem, i, ids.check(ModuleSystem, r.ID))
+		}
+	}
+	return ret
+}
+
+// Load validates the bundle and loads all the rules in it, which replace the previous rules of all the modules
+// (the modules absent from the spec are cleared). Nothing is loaded if the bundle is invalid.
+func Load(b *Bundle) error {
+	if ruleErrs := Validate(b); len(ruleErrs) > 0 {
+		var err error
+		for _, e := range ruleErrs {
+			err = multierr.Append(err, e)
+		}
+		return errors.Wrap(err, "invalid rule bundle")
+	}
+	var err error
+	if _, e := flow.LoadRules(b.Spec.Flow); e != nil {
+		err = multierr.Append(err, errors.Wrap(e, "fail to load the flow rules"))
+	}
+	if _, e, _ := circuitbreaker.LoadRules(b.Spec.CircuitBreaker); e != nil {
+		err = multierr.Append(err, errors.Wrap(e, "fail to load the circuit breaking rules"))
+	}
+	if _, e := hotspot.LoadRules(b.Spec.Hotspot); e != nil {
+		err = multierr.Append(err, errors.Wrap(e, "fail to load the hotspot rules"))
+	}
+	if _, e := isolation.LoadRules(b.Spec.Isolation); e != nil {
+		err = multierr.Append(err, errors.Wrap(e, "fail to load the isolation rules"))
+	}
+	if _, e := system.LoadRules(b.Spec.System); e != nil {
+		err = multierr.Append(err, errors.Wrap(e, "fail to load the system rules"))
+	}
+	if err == nil {
+		logging.Info("[RuleBundle] Rule bundle loaded", "name", b.Metadata.Name, "revision", b.Metadata.Revision)
+	}
+	return err
+}
+
+// LoadFile parses and loads the bundle file, see Load.
+func LoadFile(path string) error {
+	b, err := ParseFile(path)
+	if err != nil {
+		return err
+	}
+	return Load(b)
+}
+
+func appendRuleError(errs []RuleError, module string, index int, err error) []RuleError {
+	if err == nil {
+		return errs
+	}
+	return append(errs, RuleError{Module: module, Index: index, Err: err})
+}
+
+type idChecker map[string]map[string]struct{}
+
+func newIdChecker() idChecker {
+	return make(idChecker)
+}
+
+func (c idChecker) check(module, id string) error {
+	if len(id) == 0 {
+		return nil
+	}
+	ids, ok := c[module]
+	if !ok {
+		ids = make(map[string]struct{})
+		c[module] = ids
+	}
+	if _, dup := ids[id]; dup {
+		return errors.Errorf("duplicate rule ID %s", id)
+	}
+	ids[id] = struct{}{}
+	return nil
+}
+
+// normalizeYAML converts the maps decoded by yaml.v2 (map[interface{}]interface{}) to map[string]interface{},
+// so that they could be marshaled to JSON.
+func normalizeYAML(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			ks, ok := k.(string)
+			if !ok {
+				return nil, errors.Errorf("non-string key %v in the rule bundle", k)
+			}
+			nv, err := normalizeYAML(val)
+			if err != nil {
+				return nil, err
+			}
+			m[ks] = nv
+		}
+		return m, nil
+	case []interface{}:
+		for i, val := range t {
+			nv, err := normalizeYAML(val)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = nv
+		}
+		return t, nil
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,122 @@
+package bundle
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/system"
+	"github.com/stretchr/testify/assert"
+)
+
+const testBundle = `
+apiVersion: sentinel/v1
+kind: RuleBundle
+metadata:
+  name: order-service
+  revision: "42"
+  labels:
+    team: order
+spec:
+  flow:
+    - id: "1"
+      resource: createOrder
+      threshold: 100
+      statIntervalInMs: 1000
+  circuitBreaker:
+    - id: "2"
+      resource: queryStock
+      strategy: 1
+      retryTimeoutMs: 3000
+      minRequestAmount: 10
+      statIntervalMs: 1000
+      threshold: 0.5
+`
+
+func TestParse(t *testing.T) {
+	b, err := Parse([]byte(testBundle))
+	assert.Nil(t, err)
+	assert.Equal(t, "order-service", b.Metadata.Name)
+	assert.Equal(t, "42", b.Metadata.Revision)
+	assert.Equal(t, map[string]string{"team": "order"}, b.Metadata.Labels)
+	assert.Equal(t, 1, len(b.Spec.Flow))
+	assert.Equal(t, "createOrder", b.Spec.Flow[0].Resource)
+	assert.Equal(t, 100.0, b.Spec.Flow[0].Threshold)
+	assert.Equal(t, 1, len(b.Spec.CircuitBreaker))
+	assert.Equal(t, circuitbreaker.ErrorRatio, b.Spec.CircuitBreaker[0].Strategy)
+	assert.Equal(t, 0, len(Validate(b)))
+
+	t.Run("UnknownField", func(t *testing.T) {
+		_, err := Parse([]byte("apiVersion: sentinel/v1\nkind: RuleBundle\nspec:\n  flow:\n    - resource: a\n      treshold: 10\n"))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("UnsupportedVersion", func(t *testing.T) {
+		_, err := Parse([]byte("apiVersion: sentinel/v2\nkind: RuleBundle\n"))
+		assert.NotNil(t, err)
+		_, err = Parse([]byte("apiVersion: sentinel/v1\nkind: Rules\n"))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		out, err := Marshal(b)
+		assert.Nil(t, err)
+		b2, err := Parse(out)
+		assert.Nil(t, err)
+		assert.Equal(t, b, b2)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	b := &Bundle{
+		APIVersion: APIVersionV1,
+		Kind:       Kind,
+		Spec: Spec{
+			Flow:   []*flow.Rule{{ID: "1", Resource: "a", Threshold: 10}, {ID: "1", Resource: "b", Threshold: -1}},
+			System: []*system.Rule{{MetricType: system.Load, TriggerCount: -1}},
+		},
+	}
+	errs := Validate(b)
+	// the empty name, the invalid flow rule, the duplicate ID and the invalid system rule
+	assert.Equal(t, 4, len(errs))
+	assert.Equal(t, "", errs[0].Module)
+	assert.Equal(t, ModuleFlow, errs[1].Module)
+	assert.Equal(t, 1, errs[1].Index)
+	assert.Contains(t, errs[2].Error(), "duplicate rule ID 1")
+	assert.Equal(t, ModuleSystem, errs[3].Module)
+}
+
+func TestLoad(t *testing.T) {
+	defer func() {
+		_ = flow.ClearRules()
+		_ = circuitbreaker.ClearRules()
+	}()
+
+	_, err := flow.LoadRules([]*flow.Rule{{Resource: "previous", Threshold: 10}})
+	assert.Nil(t, err)
+
+	dir, err := ioutil.TempDir("", "bundle")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "rules.yaml")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(testBundle), 0644))
+
+	assert.Nil(t, LoadFile(path))
+	assert.Equal(t, 1, len(flow.GetRules()))
+	assert.Equal(t, "createOrder", flow.GetRules()[0].Resource)
+	assert.Equal(t, 1, len(circuitbreaker.GetRules()))
+
+	t.Run("Invalid", func(t *testing.T) {
+		b, err := Parse([]byte(testBundle))
+		assert.Nil(t, err)
+		b.Spec.Flow = append(b.Spec.Flow, &flow.Rule{Resource: "invalid", Threshold: -1})
+		b.Spec.CircuitBreaker = nil
+		assert.NotNil(t, Load(b))
+		// nothing is loaded
+		assert.Equal(t, 1, len(flow.GetRules()))
+		assert.Equal(t, 1, len(circuitbreaker.GetRules()))
+	})
+}
@@ -0,0 +1,41 @@
+// Package bundle provides the declarative rule bundle, a single versioned YAML document holding all types of
+// the rules with the metadata, so that the GitOps pipelines (e.g. Terraform or Argo CD) could manage the policy
+// of Sentinel as one reviewed artifact.
+//
+// Here is an example of the bundle:
+//
+//	apiVersion: sentinel/v1
+//	kind: RuleBundle
+//	metadata:
+//	  name: order-service
+//	  revision: "42"
+//	  labels:
+//	    team: order
+//	spec:
+//	  flow:
+//	    - id: "1"
+//	      resource: createOrder
+//	      threshold: 100
+//	      statIntervalInMs: 1000
+//	  circuitBreaker:
+//	    - id: "2"
+//	      resource: queryStock
+//	      strategy: 1
+//	      retryTimeoutMs: 3000
+//	      minRequestAmount: 10
+//	      statIntervalMs: 1000
+//	      threshold: 0.5
+//
+// The rules in the spec use the same fields as the JSON rules of the modules (e.g. flow.Rule), and the unknown
+// fields are rejected so that the typos don't pass the review silently. Validate checks the whole bundle
+// before anything is loaded, and Load replaces all the rules of the bundled modules, which means the modules
+// absent from the spec are cleared as well:
+//
+//	b, err := bundle.ParseFile("sentinel-rules.yaml")
+//	if err != nil {
+//	    // handle the error
+//	}
+//	if err = bundle.Load(b); err != nil {
+//	    // the invalid bundle is not loaded at all
+//	}
+package bundle
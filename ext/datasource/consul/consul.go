@@ -81,6 +81,7 @@ func (c *consulDataSource) Initialize() error {
 	if !c.isInitialized.CompareAndSet(false, true) {
 		return errors.New("consul datasource had been initialized")
 	}
+	datasource.Register("consul:"+c.propertyKey, c)
 	if err := c.doReadAndUpdate(); err != nil {
 		// Failed to read default should't block initialization
 		logging.Error(err, "[Consul] Failed to read initial data for key", "propertyKey", c.propertyKey)
@@ -123,12 +124,19 @@ func (c *consulDataSource) doUpdate(src []byte) (err error) {
 func (c *consulDataSource) doReadAndUpdate() (err error) {
 	src, err := c.ReadSource()
 	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			// The consul server is still reachable if the key doesn't exist.
+			c.SetConnected(errors.Is(err, ErrKeyDoesNotExist))
+			c.ReportError(err)
+		}
 		return err
 	}
+	c.SetConnected(true)
 	return c.doUpdate(src)
 }
 
 func (c *consulDataSource) Close() error {
+	datasource.Deregister(c)
 	if c.cancel != nil {
 		c.cancel()
 	}
@@ -22,12 +22,16 @@ type DataSource interface {
 	// return error if initialize failed;
 	// once initialized, listener should recover all panic and error.
 	Initialize() error
+	// Health returns the health status of the datasource, i.e. whether it's connected,
+	// the last update time and the last error.
+	Health() DataSourceStatus
 	// Close the data source.
 	io.Closer
 }
 
 type Base struct {
 	handlers []PropertyHandler
+	health   healthTracker
 }
 
 func (b *Base) Handle(src []byte) (err error) {
@@ -38,9 +42,12 @@ func (b *Base) Handle(src []byte) (err error) {
 		}
 	}
 	if err == nil {
+		b.reportUpdated()
 		return nil
 	}
-	return Error{code: HandleSourceError, desc: fmt.Sprintf("%+v", err)}
+	err = Error{code: HandleSourceError, desc: fmt.Sprintf("%+v", err)}
+	b.ReportError(err)
+	return err
 }
 
 // return idx if existed, else return -1
@@ -9,6 +9,14 @@ const (
 	HandleSourceError   = 3
 )
 
+// The errors of each code, an Error matches the one of its code with errors.Is,
+// e.g. errors.Is(err, ErrUpdateProperty).
+var (
+	ErrConvertSource  = NewError(ConvertSourceError, "fail to convert the source")
+	ErrUpdateProperty = NewError(UpdatePropertyError, "fail to update the property")
+	ErrHandleSource   = NewError(HandleSourceError, "fail to handle the source")
+)
+
 func NewError(code Code, desc string) Error {
 	return Error{
 		code: code,
@@ -16,9 +24,19 @@ func NewError(code Code, desc string) Error {
 	}
 }
 
+// NewErrorWithCause creates an Error wrapping the cause, which could be retrieved with errors.Unwrap.
+func NewErrorWithCause(code Code, desc string, cause error) Error {
+	return Error{
+		code:  code,
+		desc:  desc,
+		cause: cause,
+	}
+}
+
 type Error struct {
-	code Code
-	desc string
+	code  Code
+	desc  string
+	cause error
 }
 
 func (e Error) Code() Code {
@@ -28,3 +46,14 @@ func (e Error) Code() Code {
 func (e Error) Error() string {
 	return e.desc
 }
+
+// Unwrap returns the cause of the error, if any.
+func (e Error) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether the target is an Error of the same code.
+func (e Error) Is(target error) bool {
+	t, ok := target.(Error)
+	return ok && t.code == e.code
+}
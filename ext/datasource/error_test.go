@@ -0,0 +1,29 @@
+package datasource
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_Is(t *testing.T) {
+	cause := errors.New("invalid json")
+	err := NewErrorWithCause(ConvertSourceError, "convert failed", cause)
+	assert.True(t, errors.Is(err, ErrConvertSource))
+	assert.False(t, errors.Is(err, ErrUpdateProperty))
+	assert.True(t, errors.Is(err, cause))
+	assert.Equal(t, cause, errors.Unwrap(err))
+
+	wrapped := fmt.Errorf("handle source: %w", err)
+	assert.True(t, errors.Is(wrapped, ErrConvertSource))
+	var dsErr Error
+	assert.True(t, errors.As(wrapped, &dsErr))
+	assert.Equal(t, Code(ConvertSourceError), dsErr.Code())
+	assert.Equal(t, "convert failed", dsErr.Error())
+
+	updateErr := FlowRulesUpdater("not rules")
+	assert.True(t, errors.Is(updateErr, ErrUpdateProperty))
+	assert.Nil(t, errors.Unwrap(updateErr))
+}
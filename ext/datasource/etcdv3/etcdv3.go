@@ -40,6 +40,7 @@ func NewDatasource(client *clientv3.Client, key string, handlers ...datasource.P
 }
 
 func (s *Etcdv3DataSource) Initialize() error {
+	datasource.Register("etcdv3:"+s.propertyKey, s)
 	err := s.doReadAndUpdate()
 	if err != nil {
 		logging.Error(err, "Fail to update data for key when execute Initialize function", "propertyKey", s.propertyKey)
@@ -67,8 +68,11 @@ func (s *Etcdv3DataSource) ReadSource() ([]byte, error) {
 func (s *Etcdv3DataSource) doReadAndUpdate() error {
 	src, err := s.ReadSource()
 	if err != nil {
+		s.SetConnected(false)
+		s.ReportError(err)
 		return err
 	}
+	s.SetConnected(true)
 	return s.Handle(src)
 }
 
@@ -81,6 +85,8 @@ func (s *Etcdv3DataSource) processWatchResponse(resp *clientv3.WatchResponse) {
 	}
 
 	if err := resp.Err(); err != nil {
+		s.SetConnected(false)
+		s.ReportError(err)
 		logging.Error(err, "Watch on etcd endpoints occur error", "endpointd", s.client.Endpoints())
 		return
 	}
@@ -127,6 +133,7 @@ func (s *Etcdv3DataSource) watch() {
 
 func (s *Etcdv3DataSource) Close() error {
 	// stop to watch property key.
+	datasource.Deregister(s)
 	s.closed.Set(true)
 	s.cancel()
 
@@ -48,6 +48,7 @@ func (s *RefreshableFileDataSource) Initialize() error {
 	if !s.isInitialized.CompareAndSet(false, true) {
 		return nil
 	}
+	datasource.Register("file:"+s.sourceFilePath, s)
 
 	err := s.doReadAndUpdate()
 	if err != nil {
@@ -78,12 +79,14 @@ func (s *RefreshableFileDataSource) Initialize() error {
 
 				if ev.Op&fsnotify.Remove == fsnotify.Remove || ev.Op&fsnotify.Rename == fsnotify.Rename {
 					logging.Warn("The file source was removed or renamed.", "sourceFilePath", s.sourceFilePath)
+					s.SetConnected(false)
 					updateErr := s.Handle(nil)
 					if updateErr != nil {
 						logging.Error(updateErr, "Fail to update nil property")
 					}
 				}
 			case err := <-s.watcher.Errors:
+				s.ReportError(err)
 				logging.Error(err, "Watch err on file", "sourceFilePath", s.sourceFilePath)
 			case <-s.closeChan:
 				return
@@ -97,12 +100,16 @@ func (s *RefreshableFileDataSource) doReadAndUpdate() (err error) {
 	src, err := s.ReadSource()
 	if err != nil {
 		err = errors.Errorf("Fail to read source, err: %+v", err)
+		s.SetConnected(false)
+		s.ReportError(err)
 		return err
 	}
+	s.SetConnected(true)
 	return s.Handle(src)
 }
 
 func (s *RefreshableFileDataSource) Close() error {
+	datasource.Deregister(s)
 	s.closeChan <- struct{}{}
 	logging.Info("The RefreshableFileDataSource for file had been closed.", "sourceFilePath", s.sourceFilePath)
 	return nil
@@ -0,0 +1,165 @@
+package datasource
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+// DataSourceStatus is the health status of a datasource.
+type DataSourceStatus struct {
+	// Name is the name of the datasource registered by Register, only available in OverallHealth.
+	Name string
+	// Connected indicates whether the last reading from the source succeeded.
+	Connected bool
+	// LastUpdateTime is the time (in ms) when the property was updated successfully, 0 if never updated.
+	// Note that some datasources (e.g. file) only read the source on change, so the LastUpdateTime
+	// doesn't advance if the rules are not changed.
+	LastUpdateTime uint64
+	// LastError is the last error of reading or handling the source, nil if never failed.
+	LastError error
+	// LastErrorTime is the time (in ms) when LastError occurred.
+	LastErrorTime uint64
+}
+
+// healthTracker tracks the health status of the datasource, which is embedded in Base.
+type healthTracker struct {
+	mux    sync.RWMutex
+	status DataSourceStatus
+}
+
+// Health returns the health status of the datasource.
+func (b *Base) Health() DataSourceStatus {
+	b.health.mux.RLock()
+	defer b.health.mux.RUnlock()
+
+	return b.health.status
+}
+
+// SetConnected sets whether the datasource is connected to the source,
+// which should be called by the datasources on reading the source.
+func (b *Base) SetConnected(connected bool) {
+	b.health.mux.Lock()
+	defer b.health.mux.Unlock()
+
+	b.health.status.Connected = connected
+}
+
+// ReportError records the error of reading or handling the source.
+func (b *Base) ReportError(err error) {
+	if err == nil {
+		return
+	}
+	b.health.mux.Lock()
+	defer b.health.mux.Unlock()
+
+	b.health.status.LastError = err
+	b.health.status.LastErrorTime = util.CurrentTimeMillis()
+}
+
+func (b *Base) reportUpdated() {
+	b.health.mux.Lock()
+	defer b.health.mux.Unlock()
+
+	b.health.status.LastUpdateTime = util.CurrentTimeMillis()
+}
+
+var (
+	dataSources   = make(map[DataSource]string)
+	dataSourceMux = new(sync.RWMutex)
+)
+
+// Register registers the datasource with the name to the health aggregation of OverallHealth.
+// The built-in datasources register themselves on Initialize, and deregister on Close.
+func Register(name string, ds DataSource) {
+	if ds == nil {
+		return
+	}
+	dataSourceMux.Lock()
+	defer dataSourceMux.Unlock()
+
+	dataSources[ds] = name
+}
+
+// Deregister removes the datasource from the health aggregation.
+func Deregister(ds DataSource) {
+	dataSourceMux.Lock()
+	defer dataSourceMux.Unlock()
+
+	delete(dataSources, ds)
+}
+
+// OverallStatus is the aggregated health status of all the registered datasources.
+type OverallStatus struct {
+	// Connected indicates whether all the datasources are connected.
+	Connected bool
+	// LastUpdateTime is the oldest LastUpdateTime of the datasources, 0 if any datasource never updated.
+	LastUpdateTime uint64
+	// Sources is the health status of each datasource, sorted by name.
+	Sources []DataSourceStatus
+}
+
+// IsStale checks whether the rules of any datasource have not been updated within maxStaleness,
+// so that the readiness checks could refuse to serve with the stale rules, e.g.
+//
+//	if h := datasource.OverallHealth(); !h.Connected || h.IsStale(10*time.Minute) {
+//	    // not ready
+//	}
+func (s *OverallStatus) IsStale(maxStaleness time.Duration) bool {
+	if s.LastUpdateTime == 0 {
+		return len(s.Sources) > 0
+	}
+	return util.CurrentTimeMillis()-s.LastUpdateTime > uint64(maxStaleness.Milliseconds())
+}
+
+// OverallHealth aggregates the health status of all the registered datasources.
+func OverallHealth() *OverallStatus {
+	dataSourceMux.RLock()
+	defer dataSourceMux.RUnlock()
+
+	ret := &OverallStatus{
+		Connected: true,
+		Sources:   make([]DataSourceStatus, 0, len(dataSources)),
+	}
+	for ds, name := range dataSources {
+		status := ds.Health()
+		status.Name = name
+		ret.Sources = append(ret.Sources, status)
+		ret.Connected = ret.Connected && status.Connected
+	}
+	sort.Slice(ret.Sources, func(i, j int) bool {
+		return ret.Sources[i].Name < ret.Sources[j].Name
+	})
+	for i, status := range ret.Sources {
+		if i == 0 || status.LastUpdateTime < ret.LastUpdateTime {
+			ret.LastUpdateTime = status.LastUpdateTime
+		}
+	}
+	return ret
+}
+
+const initialRulesCheckInterval = 10 * time.Millisecond
+
+// WaitForInitialRules blocks until all the registered datasources have delivered their first successful
+// rule payload, or returns error if the timeout elapses.
+func WaitForInitialRules(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		pending := make([]string, 0)
+		for _, status := range OverallHealth().Sources {
+			if status.LastUpdateTime == 0 {
+				pending = append(pending, status.Name)
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("timeout waiting for the initial rules of datasources: %v", pending)
+		}
+		time.Sleep(initialRulesCheckInterval)
+	}
+}
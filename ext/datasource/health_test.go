@@ -0,0 +1,93 @@
+package datasource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type healthTestDataSource struct {
+	Base
+}
+
+func (s *healthTestDataSource) ReadSource() ([]byte, error) {
+	return nil, nil
+}
+
+func (s *healthTestDataSource) Initialize() error {
+	return nil
+}
+
+func (s *healthTestDataSource) Close() error {
+	return nil
+}
+
+func TestBase_Health(t *testing.T) {
+	h := &MockPropertyHandler{}
+	h.On("Handle", mock.Anything).Return(nil).Once()
+	h.On("Handle", mock.Anything).Return(errors.New("invalid rules"))
+
+	ds := &healthTestDataSource{}
+	ds.AddPropertyHandler(h)
+	status := ds.Health()
+	assert.False(t, status.Connected)
+	assert.Equal(t, uint64(0), status.LastUpdateTime)
+
+	ds.SetConnected(true)
+	assert.Nil(t, ds.Handle([]byte("[]")))
+	status = ds.Health()
+	assert.True(t, status.Connected)
+	assert.True(t, status.LastUpdateTime > 0)
+	assert.Nil(t, status.LastError)
+
+	assert.NotNil(t, ds.Handle([]byte("[]")))
+	status = ds.Health()
+	assert.NotNil(t, status.LastError)
+	assert.True(t, status.LastErrorTime > 0)
+}
+
+func TestOverallHealth(t *testing.T) {
+	ds1, ds2 := &healthTestDataSource{}, &healthTestDataSource{}
+	Register("ds2", ds2)
+	Register("ds1", ds1)
+	defer Deregister(ds1)
+	defer Deregister(ds2)
+
+	overall := OverallHealth()
+	assert.False(t, overall.Connected)
+	assert.True(t, overall.IsStale(time.Hour))
+	assert.Equal(t, 2, len(overall.Sources))
+	assert.Equal(t, "ds1", overall.Sources[0].Name)
+
+	for _, ds := range []*healthTestDataSource{ds1, ds2} {
+		ds.SetConnected(true)
+		assert.Nil(t, ds.Handle(nil))
+	}
+	overall = OverallHealth()
+	assert.True(t, overall.Connected)
+	assert.False(t, overall.IsStale(time.Hour))
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, overall.IsStale(10*time.Millisecond))
+
+	Deregister(ds1)
+	Deregister(ds2)
+	overall = OverallHealth()
+	assert.True(t, overall.Connected)
+	assert.False(t, overall.IsStale(time.Millisecond))
+}
+
+func TestWaitForInitialRules(t *testing.T) {
+	ds := &healthTestDataSource{}
+	Register("ds", ds)
+	defer Deregister(ds)
+
+	assert.NotNil(t, WaitForInitialRules(20*time.Millisecond))
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = ds.Handle(nil)
+	}()
+	assert.Nil(t, WaitForInitialRules(time.Second))
+}
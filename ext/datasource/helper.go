@@ -52,13 +52,14 @@ func FlowRulesUpdater(data interface{}) error {
 		return nil
 	}
 	return Error{
-		code: UpdatePropertyError,
-		desc: fmt.Sprintf("%+v", err),
+		code:  UpdatePropertyError,
+		desc:  fmt.Sprintf("%+v", err),
+		cause: err,
 	}
 }
 
-func NewFlowRulesHandler(converter PropertyConverter) PropertyHandler {
-	return NewDefaultPropertyHandler(converter, FlowRulesUpdater)
+func NewFlowRulesHandler(converter PropertyConverter, opts ...PropertyHandlerOption) PropertyHandler {
+	return NewDefaultPropertyHandler(converter, FlowRulesUpdater, opts...)
 }
 
 // SystemRuleJsonArrayParser provide JSON  as the default serialization for list of system.Rule
@@ -96,13 +97,14 @@ func SystemRulesUpdater(data interface{}) error {
 		return nil
 	}
 	return Error{
-		code: UpdatePropertyError,
-		desc: fmt.Sprintf("%+v", err),
+		code:  UpdatePropertyError,
+		desc:  fmt.Sprintf("%+v", err),
+		cause: err,
 	}
 }
 
-func NewSystemRulesHandler(converter PropertyConverter) *DefaultPropertyHandler {
-	return NewDefaultPropertyHandler(converter, SystemRulesUpdater)
+func NewSystemRulesHandler(converter PropertyConverter, opts ...PropertyHandlerOption) *DefaultPropertyHandler {
+	return NewDefaultPropertyHandler(converter, SystemRulesUpdater, opts...)
 }
 
 func CircuitBreakerRuleJsonArrayParser(src []byte) (interface{}, error) {
@@ -135,13 +137,14 @@ func CircuitBreakerRulesUpdater(data interface{}) error {
 		return nil
 	}
 	return Error{
-		code: UpdatePropertyError,
-		desc: fmt.Sprintf("%+v", err),
+		code:  UpdatePropertyError,
+		desc:  fmt.Sprintf("%+v", err),
+		cause: err,
 	}
 }
 
-func NewCircuitBreakerRulesHandler(converter PropertyConverter) *DefaultPropertyHandler {
-	return NewDefaultPropertyHandler(converter, CircuitBreakerRulesUpdater)
+func NewCircuitBreakerRulesHandler(converter PropertyConverter, opts ...PropertyHandlerOption) *DefaultPropertyHandler {
+	return NewDefaultPropertyHandler(converter, CircuitBreakerRulesUpdater, opts...)
 }
 
 // HotSpotParamRuleJsonArrayParser decodes list of param flow rules from JSON bytes.
@@ -182,11 +185,12 @@ func HotSpotParamRulesUpdater(data interface{}) error {
 		return nil
 	}
 	return Error{
-		code: UpdatePropertyError,
-		desc: fmt.Sprintf("%+v", err),
+		code:  UpdatePropertyError,
+		desc:  fmt.Sprintf("%+v", err),
+		cause: err,
 	}
 }
 
-func NewHotSpotParamRulesHandler(converter PropertyConverter) PropertyHandler {
-	return NewDefaultPropertyHandler(converter, HotSpotParamRulesUpdater)
+func NewHotSpotParamRulesHandler(converter PropertyConverter, opts ...PropertyHandlerOption) PropertyHandler {
+	return NewDefaultPropertyHandler(converter, HotSpotParamRulesUpdater, opts...)
 }
@@ -0,0 +1,185 @@
+package datasource
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/logging"
+)
+
+// MergeStrategy is the strategy of resolving the conflicts of the rules from multiple sources.
+// The sources are ranked by the priority (the higher one takes precedence), and then by the name.
+type MergeStrategy uint8
+
+const (
+	// MergeByID keeps the rule of the source of the highest precedence among the rules of the same ID,
+	// and keeps all the rules without ID, e.g. the team overrides of some baseline rules.
+	MergeByID MergeStrategy = iota
+	// MergeByResource keeps all the rules of a resource (or a target tag) from the source of the highest precedence
+	// supplying the rules of it, and drops the rules of the resource from the other sources,
+	// e.g. the team rules of a resource replace the baseline rules of the resource entirely.
+	MergeByResource
+)
+
+func (s MergeStrategy) String() string {
+	switch s {
+	case MergeByID:
+		return "MergeByID"
+	case MergeByResource:
+		return "MergeByResource"
+	default:
+		return "Undefined"
+	}
+}
+
+type mergeSource struct {
+	name     string
+	priority int
+	rules    []*flow.Rule
+}
+
+// FlowRulesMerger merges the flow rules from multiple datasources (e.g. the platform baseline and the team
+// overrides) by the MergeStrategy, and loads the merged rules, rather than the rules of the datasources
+// replacing each other (i.e. the last writer wins).
+//
+// Here is the example code to merge the rules of two datasources:
+//
+//	m := datasource.NewFlowRulesMerger(datasource.MergeByID)
+//	baseline.AddPropertyHandler(m.Handler("baseline", 0, datasource.FlowRuleJsonArrayParser))
+//	team.AddPropertyHandler(m.Handler("team", 10, datasource.FlowRuleJsonArrayParser))
+type FlowRulesMerger struct {
+	strategy MergeStrategy
+	// updater loads the merged rules, flow.LoadRules by default.
+	updater func(rules []*flow.Rule) (bool, error)
+
+	mux     sync.Mutex
+	sources map[string]*mergeSource
+}
+
+// NewFlowRulesMerger creates the merger of the flow rules with the strategy.
+func NewFlowRulesMerger(strategy MergeStrategy) *FlowRulesMerger {
+	return &FlowRulesMerger{
+		strategy: strategy,
+		updater:  flow.LoadRules,
+		sources:  make(map[string]*mergeSource),
+	}
+}
+
+// Handler creates the PropertyHandler of the source, whose rules are merged with the rules of the other sources.
+// The higher priority takes precedence on conflicts.
+func (m *FlowRulesMerger) Handler(source string, priority int, converter PropertyConverter, opts ...PropertyHandlerOption) PropertyHandler {
+	return NewDefaultPropertyHandler(converter, func(data interface{}) error {
+		rules, ok := flowRulesOf(data)
+		if !ok {
+			return Error{
+				code: UpdatePropertyError,
+				desc: fmt.Sprintf("Fail to type assert data to []flow.Rule or []*flow.Rule, in fact, data: %+v", data),
+			}
+		}
+		return m.Update(source, priority, rules)
+	}, opts...)
+}
+
+// Update replaces the rules of the source and loads the merged rules. The empty rules remove the source.
+func (m *FlowRulesMerger) Update(source string, priority int, rules []*flow.Rule) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if len(rules) == 0 {
+		delete(m.sources, source)
+	} else {
+		m.sources[source] = &mergeSource{name: source, priority: priority, rules: rules}
+	}
+	merged := m.merge()
+	succ, err := m.updater(merged)
+	if succ && err == nil {
+		return nil
+	}
+	return Error{
+		code:  UpdatePropertyError,
+		desc:  fmt.Sprintf("%+v", err),
+		cause: err,
+	}
+}
+
+// MergedRules returns the current merged rules.
+func (m *FlowRulesMerger) MergedRules() []*flow.Rule {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	return m.merge()
+}
+
+// merge merges the rules of the sources, which must be called with mux locked.
+func (m *FlowRulesMerger) merge() []*flow.Rule {
+	sources := make([]*mergeSource, 0, len(m.sources))
+	for _, s := range m.sources {
+		sources = append(sources, s)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i].priority == sources[j].priority {
+			return sources[i].name < sources[j].name
+		}
+		return sources[i].priority > sources[j].priority
+	})
+
+	ret := make([]*flow.Rule, 0)
+	// owners are the sources owning the rule IDs (MergeByID) or the resources (MergeByResource).
+	owners := make(map[string]string)
+	for _, s := range sources {
+		owned := make(map[string]struct{})
+		for _, r := range s.rules {
+			if r == nil {
+				continue
+			}
+			key := m.conflictKeyOf(r)
+			if len(key) == 0 {
+				ret = append(ret, r)
+				continue
+			}
+			if owner, exists := owners[key]; exists && owner != s.name {
+				logging.Debug("[FlowRulesMerger] Rule overridden by the source of higher precedence",
+					"strategy", m.strategy.String(), "source", s.name, "overriddenBy", owner, "rule", r)
+				continue
+			}
+			owned[key] = struct{}{}
+			ret = append(ret, r)
+		}
+		for key := range owned {
+			owners[key] = s.name
+		}
+	}
+	return ret
+}
+
+// conflictKeyOf returns the key identifying the conflicting rules, empty if the rule never conflicts.
+func (m *FlowRulesMerger) conflictKeyOf(r *flow.Rule) string {
+	switch m.strategy {
+	case MergeByResource:
+		if len(r.TargetTag) > 0 {
+			return "tag:" + r.TargetTag
+		}
+		return "resource:" + r.Resource
+	default:
+		return r.ID
+	}
+}
+
+func flowRulesOf(data interface{}) ([]*flow.Rule, bool) {
+	if data == nil {
+		return nil, true
+	}
+	if val, ok := data.([]flow.Rule); ok {
+		rules := make([]*flow.Rule, 0, len(val))
+		for i := range val {
+			rules = append(rules, &val[i])
+		}
+		return rules, true
+	}
+	if val, ok := data.([]*flow.Rule); ok {
+		return val, true
+	}
+	return nil, false
+}
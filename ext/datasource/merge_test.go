@@ -0,0 +1,90 @@
+package datasource
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestFlowRulesMerger(strategy MergeStrategy) (*FlowRulesMerger, *[]*flow.Rule) {
+	loaded := make([]*flow.Rule, 0)
+	m := NewFlowRulesMerger(strategy)
+	m.updater = func(rules []*flow.Rule) (bool, error) {
+		loaded = rules
+		return true, nil
+	}
+	return m, &loaded
+}
+
+func TestFlowRulesMerger_MergeByID(t *testing.T) {
+	m, loaded := newTestFlowRulesMerger(MergeByID)
+
+	assert.Nil(t, m.Update("baseline", 0, []*flow.Rule{
+		{ID: "1", Resource: "a", Threshold: 100},
+		{ID: "2", Resource: "b", Threshold: 100},
+		{Resource: "c", Threshold: 100},
+	}))
+	assert.Equal(t, 3, len(*loaded))
+
+	assert.Nil(t, m.Update("team", 10, []*flow.Rule{
+		{ID: "1", Resource: "a", Threshold: 10},
+		{Resource: "c", Threshold: 10},
+	}))
+	rules := *loaded
+	assert.Equal(t, 4, len(rules))
+	// the rules of the team take precedence
+	assert.Equal(t, "1", rules[0].ID)
+	assert.Equal(t, 10.0, rules[0].Threshold)
+	assert.Equal(t, "c", rules[1].Resource)
+	assert.Equal(t, "2", rules[2].ID)
+	assert.Equal(t, "c", rules[3].Resource)
+
+	// removing the team source restores the baseline
+	assert.Nil(t, m.Update("team", 10, nil))
+	assert.Equal(t, 3, len(*loaded))
+	assert.Equal(t, 100.0, (*loaded)[0].Threshold)
+}
+
+func TestFlowRulesMerger_MergeByResource(t *testing.T) {
+	m, loaded := newTestFlowRulesMerger(MergeByResource)
+
+	assert.Nil(t, m.Update("baseline", 0, []*flow.Rule{
+		{Resource: "a", Threshold: 100},
+		{Resource: "a", Threshold: 1000, StatIntervalInMs: 10000},
+		{Resource: "b", Threshold: 100},
+		{TargetTag: "tag", Threshold: 100},
+	}))
+	assert.Nil(t, m.Update("team", 10, []*flow.Rule{
+		{Resource: "a", Threshold: 10},
+		{TargetTag: "tag", Threshold: 10},
+	}))
+	rules := *loaded
+	assert.Equal(t, 3, len(rules))
+	assert.Equal(t, 10.0, rules[0].Threshold)
+	assert.Equal(t, 10.0, rules[1].Threshold)
+	assert.Equal(t, "b", rules[2].Resource)
+
+	t.Run("SamePriority", func(t *testing.T) {
+		// the sources of the same priority are ranked by the name
+		assert.Nil(t, m.Update("another", 10, []*flow.Rule{{Resource: "a", Threshold: 1}}))
+		assert.Equal(t, 1.0, m.MergedRules()[0].Threshold)
+		assert.Equal(t, 3, len(m.MergedRules()))
+	})
+}
+
+func TestFlowRulesMerger_Handler(t *testing.T) {
+	m, loaded := newTestFlowRulesMerger(MergeByID)
+	h := m.Handler("baseline", 0, FlowRuleJsonArrayParser)
+	assert.Nil(t, h.Handle([]byte(`[{"id": "1", "resource": "a", "threshold": 100}]`)))
+	assert.Equal(t, 1, len(*loaded))
+
+	t.Run("UpdateError", func(t *testing.T) {
+		m.updater = func(rules []*flow.Rule) (bool, error) {
+			return false, errors.New("load error")
+		}
+		err := m.Update("team", 10, []*flow.Rule{{ID: "1", Resource: "a", Threshold: 10}})
+		assert.True(t, errors.Is(err, ErrUpdateProperty))
+	})
+}
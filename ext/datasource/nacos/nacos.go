@@ -42,10 +42,14 @@ func (s *NacosDataSource) Initialize() error {
 	if !s.isInitialized.CompareAndSet(false, true) {
 		return nil
 	}
+	datasource.Register("nacos:"+s.group+"/"+s.dataId, s)
 	data, err := s.ReadSource()
 	if err != nil {
+		s.SetConnected(false)
+		s.ReportError(err)
 		return err
 	}
+	s.SetConnected(true)
 	if err = s.doUpdate(data); err != nil {
 		return err
 	}
@@ -79,6 +83,7 @@ func (s *NacosDataSource) listen(client config_client.IConfigClient) (err error)
 		Group:  s.group,
 		OnChange: func(namespace, group, dataId, data string) {
 			logging.Info("receive listened property", "namespace", namespace, "group", group, "dataId", dataId, "data", data)
+			s.SetConnected(true)
 			err := s.doUpdate([]byte(data))
 			if err != nil {
 				logging.Error(err, "fail to update data source")
@@ -93,6 +98,7 @@ func (s *NacosDataSource) listen(client config_client.IConfigClient) (err error)
 }
 
 func (s *NacosDataSource) Close() error {
+	datasource.Deregister(s)
 	err := s.client.CancelListenConfig(vo.ConfigParam{
 		DataId: s.dataId,
 		Group:  s.group,
@@ -2,6 +2,8 @@ package datasource
 
 import (
 	"reflect"
+	"sync"
+	"time"
 
 	"github.com/alibaba/sentinel-golang/logging"
 	"github.com/pkg/errors"
@@ -34,6 +36,45 @@ type DefaultPropertyHandler struct {
 
 	converter PropertyConverter
 	updater   PropertyUpdater
+
+	// debounce and minUpdateInterval coalesce rapid successive updates, see WithDebounce and WithMinUpdateInterval.
+	debounce          time.Duration
+	minUpdateInterval time.Duration
+
+	mux             sync.Mutex
+	pendingProperty interface{}
+	pendingTimer    *time.Timer
+	// pendingVersion identifies the latest scheduled timer, as the stopped timer may have fired already.
+	pendingVersion uint64
+	lastUpdateTime time.Time
+
+	// scopeLabels filters the rules in the payload, see WithScopeLabels.
+	scopeLabels    map[string]string
+	scopeLabelsSet bool
+
+	// scheduledTimer applies the rules of the scheduled payload at scheduledApplyAt, see scheduledPayload.
+	scheduledTimer   *time.Timer
+	scheduledApplyAt time.Time
+	scheduledVersion uint64
+}
+
+// PropertyHandlerOption represents the option of DefaultPropertyHandler.
+type PropertyHandlerOption func(h *DefaultPropertyHandler)
+
+// WithDebounce delays the update until no new property arrives for the given duration,
+// so that only the latest property of rapid successive updates (e.g. config center flapping) takes effect.
+func WithDebounce(d time.Duration) PropertyHandlerOption {
+	return func(h *DefaultPropertyHandler) {
+		h.debounce = d
+	}
+}
+
+// WithMinUpdateInterval limits the updates to at most once per the given interval.
+// The property arriving within the interval is deferred to the end of the interval, and overridden by newer ones.
+func WithMinUpdateInterval(interval time.Duration) PropertyHandlerOption {
+	return func(h *DefaultPropertyHandler) {
+		h.minUpdateInterval = interval
+	}
 }
 
 func (h *DefaultPropertyHandler) isPropertyConsistent(src interface{}) bool {
@@ -52,21 +93,89 @@ func (h *DefaultPropertyHandler) Handle(src []byte) error {
 			logging.Error(errors.Errorf("%+v", err), "Unexpected panic", "err")
 		}
 	}()
+	src, applyAt, err := unwrapScheduledPayload(src)
+	if err != nil {
+		return NewErrorWithCause(ConvertSourceError, err.Error(), err)
+	}
+	src = filterByScope(src, h.currentScopeLabels())
 	// convert to target property
 	realProperty, err := h.converter(src)
 	if err != nil {
 		return err
 	}
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	h.cancelScheduled()
+	if time.Until(applyAt) > 0 {
+		h.scheduleApplyAt(realProperty, applyAt)
+		return nil
+	}
 	isConsistent := h.isPropertyConsistent(realProperty)
 	if isConsistent {
 		return nil
 	}
-	return h.updater(realProperty)
+	if h.debounce <= 0 && h.minUpdateInterval <= 0 {
+		return h.updater(realProperty)
+	}
+	return h.scheduleUpdate(realProperty)
 }
 
-func NewDefaultPropertyHandler(converter PropertyConverter, updater PropertyUpdater) *DefaultPropertyHandler {
-	return &DefaultPropertyHandler{
+// scheduleUpdate updates the property now if allowed, otherwise defers it. It must be called with the lock held.
+func (h *DefaultPropertyHandler) scheduleUpdate(property interface{}) error {
+	delay := h.debounce
+	if wait := time.Until(h.lastUpdateTime.Add(h.minUpdateInterval)); wait > delay {
+		delay = wait
+	}
+	if delay <= 0 && h.pendingTimer == nil {
+		h.lastUpdateTime = time.Now()
+		return h.updater(property)
+	}
+
+	h.pendingProperty = property
+	if h.pendingTimer != nil {
+		if h.debounce <= 0 {
+			// The pending update is already scheduled at the end of the interval.
+			return nil
+		}
+		h.pendingTimer.Stop()
+	}
+	h.pendingVersion++
+	version := h.pendingVersion
+	h.pendingTimer = time.AfterFunc(delay, func() {
+		h.flushPending(version)
+	})
+	return nil
+}
+
+func (h *DefaultPropertyHandler) flushPending(version uint64) {
+	defer func() {
+		if err := recover(); err != nil {
+			logging.Error(errors.Errorf("%+v", err), "Unexpected panic when flushing pending property")
+		}
+	}()
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if version != h.pendingVersion || h.pendingTimer == nil {
+		return
+	}
+	property := h.pendingProperty
+	h.pendingProperty = nil
+	h.pendingTimer = nil
+	h.lastUpdateTime = time.Now()
+	if err := h.updater(property); err != nil {
+		logging.Error(err, "Fail to update the deferred property")
+	}
+}
+
+func NewDefaultPropertyHandler(converter PropertyConverter, updater PropertyUpdater, opts ...PropertyHandlerOption) *DefaultPropertyHandler {
+	h := &DefaultPropertyHandler{
 		converter: converter,
 		updater:   updater,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
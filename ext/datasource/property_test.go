@@ -3,7 +3,9 @@ package datasource
 import (
 	"encoding/json"
 	"io/ioutil"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/alibaba/sentinel-golang/core/system"
 	"github.com/pkg/errors"
@@ -73,3 +75,57 @@ func TestSinglePropertyHandler_isPropertyConsistent(t *testing.T) {
 	isConsistent = h.isPropertyConsistent(ret3)
 	assert.True(t, isConsistent == false, "Fail to execute isPropertyConsistent.")
 }
+
+type recordingUpdater struct {
+	mux     sync.Mutex
+	updates []interface{}
+}
+
+func (u *recordingUpdater) update(data interface{}) error {
+	u.mux.Lock()
+	defer u.mux.Unlock()
+	u.updates = append(u.updates, data)
+	return nil
+}
+
+func (u *recordingUpdater) snapshot() []interface{} {
+	u.mux.Lock()
+	defer u.mux.Unlock()
+	return append([]interface{}{}, u.updates...)
+}
+
+func stringConverter(src []byte) (interface{}, error) {
+	return string(src), nil
+}
+
+func TestDefaultPropertyHandler_Debounce(t *testing.T) {
+	u := &recordingUpdater{}
+	h := NewDefaultPropertyHandler(stringConverter, u.update, WithDebounce(50*time.Millisecond))
+	for _, src := range []string{"a", "b", "c"} {
+		assert.Nil(t, h.Handle([]byte(src)))
+	}
+	assert.Equal(t, 0, len(u.snapshot()))
+
+	assert.Eventually(t, func() bool {
+		return len(u.snapshot()) == 1
+	}, time.Second, 10*time.Millisecond)
+	time.Sleep(80 * time.Millisecond)
+	assert.Equal(t, []interface{}{"c"}, u.snapshot())
+}
+
+func TestDefaultPropertyHandler_MinUpdateInterval(t *testing.T) {
+	u := &recordingUpdater{}
+	h := NewDefaultPropertyHandler(stringConverter, u.update, WithMinUpdateInterval(100*time.Millisecond))
+	assert.Nil(t, h.Handle([]byte("a")))
+	assert.Equal(t, []interface{}{"a"}, u.snapshot())
+
+	// The updates within the interval are coalesced.
+	assert.Nil(t, h.Handle([]byte("b")))
+	assert.Nil(t, h.Handle([]byte("c")))
+	assert.Equal(t, 1, len(u.snapshot()))
+
+	assert.Eventually(t, func() bool {
+		return len(u.snapshot()) == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []interface{}{"a", "c"}, u.snapshot())
+}
@@ -0,0 +1,121 @@
+package datasource
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/pkg/errors"
+)
+
+// scheduledPayload is the envelope of the rules applied at the given time, e.g.
+//
+//	{"applyAt": "2026-11-11T00:00:00+08:00", "rules": [{"resource": "abc", "threshold": 1000}]}
+//
+// so that the limit changes could be pushed to the fleet in advance and take effect at the same time
+// (e.g. raise the limits at the start of a sale). The applyAt is either the RFC 3339 time or the Unix
+// timestamp in milliseconds. The rules are applied immediately if the time has passed.
+//
+// Only the latest payload takes effect: the pending scheduled rules are discarded once a newer payload
+// (scheduled or not) arrives.
+type scheduledPayload struct {
+	ApplyAt json.RawMessage `json:"applyAt"`
+	Rules   json.RawMessage `json:"rules"`
+}
+
+// unwrapScheduledPayload returns the rules and the apply time of the scheduled payload,
+// or the payload itself and zero time if it's not scheduled.
+func unwrapScheduledPayload(src []byte) ([]byte, time.Time, error) {
+	trimmed := bytes.TrimSpace(src)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return src, time.Time{}, nil
+	}
+	var p scheduledPayload
+	if err := json.Unmarshal(trimmed, &p); err != nil || len(p.ApplyAt) == 0 || len(p.Rules) == 0 {
+		return src, time.Time{}, nil
+	}
+	applyAt, err := parseApplyAt(p.ApplyAt)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return p.Rules, applyAt, nil
+}
+
+func parseApplyAt(raw json.RawMessage) (time.Time, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return time.Time{}, errors.Wrapf(err, "invalid applyAt: %s", s)
+		}
+		return t, nil
+	}
+	ms, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || ms <= 0 {
+		return time.Time{}, errors.Errorf("invalid applyAt: %s", string(raw))
+	}
+	return time.Unix(0, ms*int64(time.Millisecond)), nil
+}
+
+// scheduleApplyAt updates the property at the given time. It must be called with the lock held.
+func (h *DefaultPropertyHandler) scheduleApplyAt(property interface{}, applyAt time.Time) {
+	if h.pendingTimer != nil {
+		// the deferred older property is overridden as well
+		h.pendingTimer.Stop()
+		h.pendingTimer = nil
+		h.pendingProperty = nil
+		h.pendingVersion++
+	}
+	h.scheduledVersion++
+	version := h.scheduledVersion
+	h.scheduledApplyAt = applyAt
+	h.scheduledTimer = time.AfterFunc(time.Until(applyAt), func() {
+		h.flushScheduled(version, property)
+	})
+	logging.Info("[Datasource] The rules are scheduled to apply", "applyAt", applyAt.Format(time.RFC3339))
+}
+
+// cancelScheduled discards the pending scheduled property. It must be called with the lock held.
+func (h *DefaultPropertyHandler) cancelScheduled() {
+	if h.scheduledTimer == nil {
+		return
+	}
+	h.scheduledTimer.Stop()
+	h.scheduledTimer = nil
+	h.scheduledApplyAt = time.Time{}
+	h.scheduledVersion++
+	logging.Info("[Datasource] The pending scheduled rules are discarded by the newer payload")
+}
+
+func (h *DefaultPropertyHandler) flushScheduled(version uint64, property interface{}) {
+	defer func() {
+		if err := recover(); err != nil {
+			logging.Error(errors.Errorf("%+v", err), "Unexpected panic when applying the scheduled property")
+		}
+	}()
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if version != h.scheduledVersion || h.scheduledTimer == nil {
+		return
+	}
+	h.scheduledTimer = nil
+	h.scheduledApplyAt = time.Time{}
+	if h.isPropertyConsistent(property) {
+		return
+	}
+	h.lastUpdateTime = time.Now()
+	if err := h.updater(property); err != nil {
+		logging.Error(err, "Fail to update the scheduled property")
+	}
+}
+
+// ScheduledApplyAt returns the apply time of the pending scheduled rules, and false if there's none.
+func (h *DefaultPropertyHandler) ScheduledApplyAt() (time.Time, bool) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	return h.scheduledApplyAt, h.scheduledTimer != nil
+}
@@ -0,0 +1,72 @@
+package datasource
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnwrapScheduledPayload(t *testing.T) {
+	src := []byte(`[{"resource": "abc"}]`)
+	rules, applyAt, err := unwrapScheduledPayload(src)
+	assert.NoError(t, err)
+	assert.Equal(t, src, rules)
+	assert.True(t, applyAt.IsZero())
+
+	// the objects without applyAt are not scheduled
+	src = []byte(`{"resource": "abc"}`)
+	rules, applyAt, err = unwrapScheduledPayload(src)
+	assert.NoError(t, err)
+	assert.Equal(t, src, rules)
+	assert.True(t, applyAt.IsZero())
+
+	rules, applyAt, err = unwrapScheduledPayload([]byte(`{"applyAt": "2026-11-11T00:00:00+08:00", "rules": [{"resource": "abc"}]}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"resource": "abc"}]`, string(rules))
+	assert.True(t, applyAt.Equal(time.Date(2026, 11, 10, 16, 0, 0, 0, time.UTC)))
+
+	_, applyAt, err = unwrapScheduledPayload([]byte(`{"applyAt": 1700000000000, "rules": []}`))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1700000000000), applyAt.UnixNano()/int64(time.Millisecond))
+
+	_, _, err = unwrapScheduledPayload([]byte(`{"applyAt": "tomorrow", "rules": []}`))
+	assert.Error(t, err)
+	_, _, err = unwrapScheduledPayload([]byte(`{"applyAt": -1, "rules": []}`))
+	assert.Error(t, err)
+}
+
+func scheduledPayloadOf(rules string, applyAt time.Time) []byte {
+	return []byte(fmt.Sprintf(`{"applyAt": %d, "rules": %s}`, applyAt.UnixNano()/int64(time.Millisecond), rules))
+}
+
+func TestDefaultPropertyHandler_ScheduledPayload(t *testing.T) {
+	u := &recordingUpdater{}
+	h := NewDefaultPropertyHandler(stringConverter, u.update)
+	assert.Nil(t, h.Handle([]byte(`["a"]`)))
+
+	applyAt := time.Now().Add(100 * time.Millisecond)
+	assert.Nil(t, h.Handle(scheduledPayloadOf(`["b"]`, applyAt)))
+	at, scheduled := h.ScheduledApplyAt()
+	assert.True(t, scheduled)
+	assert.Equal(t, applyAt.UnixNano()/int64(time.Millisecond), at.UnixNano()/int64(time.Millisecond))
+	assert.Equal(t, []interface{}{`["a"]`}, u.snapshot())
+
+	assert.Eventually(t, func() bool {
+		return len(u.snapshot()) == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []interface{}{`["a"]`, `["b"]`}, u.snapshot())
+	_, scheduled = h.ScheduledApplyAt()
+	assert.False(t, scheduled)
+
+	// the passed time is applied immediately
+	assert.Nil(t, h.Handle(scheduledPayloadOf(`["c"]`, time.Now().Add(-time.Second))))
+	assert.Equal(t, []interface{}{`["a"]`, `["b"]`, `["c"]`}, u.snapshot())
+
+	// the newer payload discards the pending scheduled one
+	assert.Nil(t, h.Handle(scheduledPayloadOf(`["d"]`, time.Now().Add(50*time.Millisecond))))
+	assert.Nil(t, h.Handle([]byte(`["e"]`)))
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, []interface{}{`["a"]`, `["b"]`, `["c"]`, `["e"]`}, u.snapshot())
+}
@@ -0,0 +1,77 @@
+package datasource
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/alibaba/sentinel-golang/core/config"
+	"github.com/alibaba/sentinel-golang/logging"
+)
+
+// RuleScopeKey is the key of the scope object of the rules in a JSON array payload, e.g.
+//
+//	[{"resource": "abc", "threshold": 10, "scope": {"zone": "hz"}}, {"resource": "abc", "threshold": 20}]
+//
+// so that one payload can hold the rules of several clusters or zones. The rule with a scope takes effect
+// only if all the labels in the scope equal to the scope labels of current process, while the rule
+// without any scope takes effect everywhere.
+const RuleScopeKey = "scope"
+
+// WithScopeLabels sets the scope labels used to filter the rules in the payload, see RuleScopeKey.
+// The labels from config.DatasourceScopeLabels are used by default.
+func WithScopeLabels(labels map[string]string) PropertyHandlerOption {
+	return func(h *DefaultPropertyHandler) {
+		h.scopeLabels = labels
+		h.scopeLabelsSet = true
+	}
+}
+
+func (h *DefaultPropertyHandler) currentScopeLabels() map[string]string {
+	if h.scopeLabelsSet {
+		return h.scopeLabels
+	}
+	return config.DatasourceScopeLabels()
+}
+
+type scopedElement struct {
+	Scope map[string]string `json:"scope"`
+}
+
+// filterByScope drops the elements of the JSON array payload out of the scope of the given labels.
+// The payload which is not a JSON array is returned as it is, and left to the converter.
+func filterByScope(src []byte, labels map[string]string) []byte {
+	trimmed := bytes.TrimSpace(src)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		return src
+	}
+	elements := make([]json.RawMessage, 0)
+	if err := json.Unmarshal(trimmed, &elements); err != nil {
+		return src
+	}
+	kept := make([]json.RawMessage, 0, len(elements))
+	for _, e := range elements {
+		var se scopedElement
+		if err := json.Unmarshal(e, &se); err == nil && !inScope(se.Scope, labels) {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	if len(kept) == len(elements) {
+		return src
+	}
+	logging.Debug("[Datasource] Rules out of the scope are dropped", "dropped", len(elements)-len(kept), "scopeLabels", labels)
+	ret, err := json.Marshal(kept)
+	if err != nil {
+		return src
+	}
+	return ret
+}
+
+func inScope(scope, labels map[string]string) bool {
+	for k, v := range scope {
+		if l, ok := labels[k]; !ok || l != v {
+			return false
+		}
+	}
+	return true
+}
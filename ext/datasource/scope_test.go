@@ -0,0 +1,47 @@
+package datasource
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterByScope(t *testing.T) {
+	labels := map[string]string{"zone": "hz", "cluster": "c1"}
+
+	t.Run("NotJsonArray", func(t *testing.T) {
+		src := []byte(`{"resource": "abc", "scope": {"zone": "sh"}}`)
+		assert.Equal(t, src, filterByScope(src, labels))
+		src = []byte(`[{"resource": "abc"`)
+		assert.Equal(t, src, filterByScope(src, labels))
+	})
+
+	t.Run("AllInScope", func(t *testing.T) {
+		src := []byte(`[{"resource": "abc"}, {"resource": "abc", "scope": {"zone": "hz"}}]`)
+		assert.Equal(t, src, filterByScope(src, labels))
+	})
+
+	t.Run("Filtered", func(t *testing.T) {
+		src := []byte(`[{"resource": "a"}, {"resource": "b", "scope": {"zone": "hz", "cluster": "c1"}},
+			{"resource": "c", "scope": {"zone": "sh"}}, {"resource": "d", "scope": {"zone": "hz", "cluster": "c2"}},
+			{"resource": "e", "scope": {"idc": "x"}}]`)
+		assert.JSONEq(t, `[{"resource": "a"}, {"resource": "b", "scope": {"zone": "hz", "cluster": "c1"}}]`,
+			string(filterByScope(src, labels)))
+		// the process without scope labels takes only the rules without scope
+		assert.JSONEq(t, `[{"resource": "a"}]`, string(filterByScope(src, nil)))
+	})
+}
+
+func TestDefaultPropertyHandler_WithScopeLabels(t *testing.T) {
+	var loaded []*flow.Rule
+	h := NewDefaultPropertyHandler(FlowRuleJsonArrayParser, func(data interface{}) error {
+		loaded = data.([]*flow.Rule)
+		return nil
+	}, WithScopeLabels(map[string]string{"zone": "hz"}))
+	err := h.Handle([]byte(`[{"resource": "abc", "threshold": 10, "scope": {"zone": "hz"}},
+		{"resource": "abc", "threshold": 20, "scope": {"zone": "sh"}}]`))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(loaded))
+	assert.Equal(t, 10.0, loaded[0].Threshold)
+}
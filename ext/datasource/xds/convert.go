@@ -0,0 +1,109 @@
+package xds
+
+import (
+	"encoding/json"
+
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	anypb "github.com/golang/protobuf/ptypes/any"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/pkg/errors"
+)
+
+const (
+	// TypedExtensionConfigTypeURL is the type URL of the ECDS resources.
+	TypedExtensionConfigTypeURL = "type.googleapis.com/envoy.config.core.v3.TypedExtensionConfig"
+	// LocalRateLimitTypeURL is the type URL of the Envoy local rate limit filter config,
+	// which is mapped onto a flow rule.
+	LocalRateLimitTypeURL = "type.googleapis.com/envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit"
+	// TypedStructTypeURL is the type URL of the opaque config carrying the Sentinel rules.
+	TypedStructTypeURL = "type.googleapis.com/xds.type.v3.TypedStruct"
+	// UdpaTypedStructTypeURL is the type URL of the deprecated TypedStruct.
+	UdpaTypedStructTypeURL = "type.googleapis.com/udpa.type.v1.TypedStruct"
+	// StructTypeURL is the type URL of google.protobuf.Struct carrying the Sentinel rules.
+	StructTypeURL = "type.googleapis.com/google.protobuf.Struct"
+
+	// RulesField is the field of the struct config that holds the JSON array of the Sentinel rules.
+	RulesField = "rules"
+)
+
+// sourceOf converts the typed config of the extension config to the source of the property handlers,
+// i.e. the JSON array of the Sentinel rules.
+func sourceOf(typedConfig *anypb.Any) ([]byte, error) {
+	if typedConfig == nil {
+		return nil, nil
+	}
+	switch typedConfig.TypeUrl {
+	case LocalRateLimitTypeURL:
+		cfg := &localRateLimit{}
+		if err := proto.Unmarshal(typedConfig.Value, cfg); err != nil {
+			return nil, errors.Wrap(err, "fail to unmarshal LocalRateLimit")
+		}
+		return localRateLimitSourceOf(cfg)
+	case TypedStructTypeURL, UdpaTypedStructTypeURL:
+		cfg := &typedStruct{}
+		if err := proto.Unmarshal(typedConfig.Value, cfg); err != nil {
+			return nil, errors.Wrap(err, "fail to unmarshal TypedStruct")
+		}
+		return structSourceOf(cfg.Value)
+	case StructTypeURL:
+		cfg := &structpb.Struct{}
+		if err := proto.Unmarshal(typedConfig.Value, cfg); err != nil {
+			return nil, errors.Wrap(err, "fail to unmarshal Struct")
+		}
+		return structSourceOf(cfg)
+	default:
+		return nil, errors.Errorf("unsupported typed config: %s", typedConfig.TypeUrl)
+	}
+}
+
+// localRateLimitSourceOf maps the token bucket of the local rate limit config onto a flow rule,
+// which allows TokensPerFill requests of the resource StatPrefix within each FillInterval.
+// MaxTokens (i.e. the burst) is not supported by the sliding window statistic, and is ignored.
+func localRateLimitSourceOf(cfg *localRateLimit) ([]byte, error) {
+	if len(cfg.StatPrefix) == 0 {
+		return nil, errors.New("empty stat_prefix of LocalRateLimit")
+	}
+	bucket := cfg.TokenBucket
+	if bucket == nil || bucket.FillInterval == nil {
+		return nil, errors.New("empty fill_interval of LocalRateLimit")
+	}
+	intervalInMs := bucket.FillInterval.Seconds*1000 + int64(bucket.FillInterval.Nanos)/1000000
+	if intervalInMs <= 0 {
+		return nil, errors.Errorf("invalid fill_interval of LocalRateLimit: %v", bucket.FillInterval)
+	}
+	tokensPerFill := uint32(1)
+	if bucket.TokensPerFill != nil {
+		tokensPerFill = bucket.TokensPerFill.Value
+	}
+	return json.Marshal([]*flow.Rule{
+		{
+			Resource:               cfg.StatPrefix,
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			Threshold:              float64(tokensPerFill),
+			StatIntervalInMs:       uint32(intervalInMs),
+		},
+	})
+}
+
+// structSourceOf returns the JSON array of the RulesField of the struct config, so that the rules of any module
+// could be delivered, as long as the property handlers parse them.
+func structSourceOf(cfg *structpb.Struct) ([]byte, error) {
+	if cfg == nil || cfg.Fields == nil {
+		return nil, nil
+	}
+	rules, ok := cfg.Fields[RulesField]
+	if !ok {
+		return nil, nil
+	}
+	if rules.GetListValue() == nil {
+		return nil, errors.Errorf("field %s of the struct config must be a list", RulesField)
+	}
+	src, err := (&jsonpb.Marshaler{}).MarshalToString(rules)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to marshal field %s of the struct config", RulesField)
+	}
+	return []byte(src), nil
+}
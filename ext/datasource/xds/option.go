@@ -0,0 +1,51 @@
+package xds
+
+import (
+	"time"
+
+	"github.com/alibaba/sentinel-golang/ext/datasource"
+)
+
+type (
+	options struct {
+		nodeId           string
+		nodeCluster      string
+		retryInterval    time.Duration
+		propertyHandlers []datasource.PropertyHandler
+	}
+
+	Option func(*options)
+)
+
+// WithNode sets the node identifier and cluster of current process sent to the management server.
+func WithNode(id, cluster string) Option {
+	return func(opts *options) {
+		opts.nodeId = id
+		opts.nodeCluster = cluster
+	}
+}
+
+// WithRetryInterval sets the interval to re-subscribe after the stream broke, 1s by default.
+func WithRetryInterval(interval time.Duration) Option {
+	return func(opts *options) {
+		opts.retryInterval = interval
+	}
+}
+
+// WithPropertyHandlers injects property handlers
+func WithPropertyHandlers(handlers ...datasource.PropertyHandler) Option {
+	return func(opts *options) {
+		opts.propertyHandlers = append(opts.propertyHandlers, handlers...)
+	}
+}
+
+func evaluateOptions(opts []Option) *options {
+	optCopy := &options{
+		retryInterval:    time.Second,
+		propertyHandlers: make([]datasource.PropertyHandler, 0),
+	}
+	for _, o := range opts {
+		o(optCopy)
+	}
+	return optCopy
+}
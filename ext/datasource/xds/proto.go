@@ -0,0 +1,100 @@
+package xds
+
+import (
+	"github.com/golang/protobuf/proto"
+	anypb "github.com/golang/protobuf/ptypes/any"
+	durationpb "github.com/golang/protobuf/ptypes/duration"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	wrapperspb "github.com/golang/protobuf/ptypes/wrappers"
+)
+
+// The messages below are the wire-compatible subset of the xDS protocol (envoy/service/discovery/v3,
+// envoy/config/core/v3 and the rate limit extensions) that the data source makes use of, so that the
+// generated stubs of go-control-plane are not needed. The other fields are skipped when unmarshalling.
+
+// node is envoy.config.core.v3.Node, which identifies current process to the management server.
+type node struct {
+	Id      string `protobuf:"bytes,1,opt,name=id,proto3"`
+	Cluster string `protobuf:"bytes,2,opt,name=cluster,proto3"`
+}
+
+func (m *node) Reset()         { *m = node{} }
+func (m *node) String() string { return proto.CompactTextString(m) }
+func (*node) ProtoMessage()    {}
+
+// rpcStatus is google.rpc.Status, which carries the error detail of a NACK.
+type rpcStatus struct {
+	Code    int32  `protobuf:"varint,1,opt,name=code,proto3"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3"`
+}
+
+func (m *rpcStatus) Reset()         { *m = rpcStatus{} }
+func (m *rpcStatus) String() string { return proto.CompactTextString(m) }
+func (*rpcStatus) ProtoMessage()    {}
+
+// discoveryRequest is envoy.service.discovery.v3.DiscoveryRequest.
+type discoveryRequest struct {
+	VersionInfo   string     `protobuf:"bytes,1,opt,name=version_info,json=versionInfo,proto3"`
+	Node          *node      `protobuf:"bytes,2,opt,name=node,proto3"`
+	ResourceNames []string   `protobuf:"bytes,3,rep,name=resource_names,json=resourceNames,proto3"`
+	TypeUrl       string     `protobuf:"bytes,4,opt,name=type_url,json=typeUrl,proto3"`
+	ResponseNonce string     `protobuf:"bytes,5,opt,name=response_nonce,json=responseNonce,proto3"`
+	ErrorDetail   *rpcStatus `protobuf:"bytes,6,opt,name=error_detail,json=errorDetail,proto3"`
+}
+
+func (m *discoveryRequest) Reset()         { *m = discoveryRequest{} }
+func (m *discoveryRequest) String() string { return proto.CompactTextString(m) }
+func (*discoveryRequest) ProtoMessage()    {}
+
+// discoveryResponse is envoy.service.discovery.v3.DiscoveryResponse.
+type discoveryResponse struct {
+	VersionInfo string       `protobuf:"bytes,1,opt,name=version_info,json=versionInfo,proto3"`
+	Resources   []*anypb.Any `protobuf:"bytes,2,rep,name=resources,proto3"`
+	TypeUrl     string       `protobuf:"bytes,4,opt,name=type_url,json=typeUrl,proto3"`
+	Nonce       string       `protobuf:"bytes,5,opt,name=nonce,proto3"`
+}
+
+func (m *discoveryResponse) Reset()         { *m = discoveryResponse{} }
+func (m *discoveryResponse) String() string { return proto.CompactTextString(m) }
+func (*discoveryResponse) ProtoMessage()    {}
+
+// typedExtensionConfig is envoy.config.core.v3.TypedExtensionConfig, the resource of ECDS.
+type typedExtensionConfig struct {
+	Name        string     `protobuf:"bytes,1,opt,name=name,proto3"`
+	TypedConfig *anypb.Any `protobuf:"bytes,2,opt,name=typed_config,json=typedConfig,proto3"`
+}
+
+func (m *typedExtensionConfig) Reset()         { *m = typedExtensionConfig{} }
+func (m *typedExtensionConfig) String() string { return proto.CompactTextString(m) }
+func (*typedExtensionConfig) ProtoMessage()    {}
+
+// typedStruct is xds.type.v3.TypedStruct (or the deprecated udpa.type.v1.TypedStruct).
+type typedStruct struct {
+	TypeUrl string           `protobuf:"bytes,1,opt,name=type_url,json=typeUrl,proto3"`
+	Value   *structpb.Struct `protobuf:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *typedStruct) Reset()         { *m = typedStruct{} }
+func (m *typedStruct) String() string { return proto.CompactTextString(m) }
+func (*typedStruct) ProtoMessage()    {}
+
+// localRateLimit is envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit.
+type localRateLimit struct {
+	StatPrefix  string       `protobuf:"bytes,1,opt,name=stat_prefix,json=statPrefix,proto3"`
+	TokenBucket *tokenBucket `protobuf:"bytes,3,opt,name=token_bucket,json=tokenBucket,proto3"`
+}
+
+func (m *localRateLimit) Reset()         { *m = localRateLimit{} }
+func (m *localRateLimit) String() string { return proto.CompactTextString(m) }
+func (*localRateLimit) ProtoMessage()    {}
+
+// tokenBucket is envoy.type.v3.TokenBucket.
+type tokenBucket struct {
+	MaxTokens     uint32                  `protobuf:"varint,1,opt,name=max_tokens,json=maxTokens,proto3"`
+	TokensPerFill *wrapperspb.UInt32Value `protobuf:"bytes,2,opt,name=tokens_per_fill,json=tokensPerFill,proto3"`
+	FillInterval  *durationpb.Duration    `protobuf:"bytes,3,opt,name=fill_interval,json=fillInterval,proto3"`
+}
+
+func (m *tokenBucket) Reset()         { *m = tokenBucket{} }
+func (m *tokenBucket) String() string { return proto.CompactTextString(m) }
+func (*tokenBucket) ProtoMessage()    {}
@@ -0,0 +1,200 @@
+package xds
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/ext/datasource"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// ExtensionConfigDiscoveryServiceName is the full name of the xDS extension config discovery service (ECDS).
+	ExtensionConfigDiscoveryServiceName = "envoy.service.extension.v3.ExtensionConfigDiscoveryService"
+
+	streamExtensionConfigsMethod = "/" + ExtensionConfigDiscoveryServiceName + "/StreamExtensionConfigs"
+)
+
+var (
+	ErrNilClientConn      = errors.New("nil gRPC client connection")
+	ErrEmptyExtensionName = errors.New("empty extension config name")
+
+	ecdsStreamDesc = grpc.StreamDesc{
+		StreamName:    "StreamExtensionConfigs",
+		ServerStreams: true,
+		ClientStreams: true,
+	}
+)
+
+type xdsDataSource struct {
+	datasource.Base
+	extensionName string
+	cc            *grpc.ClientConn
+	options       *options
+	isInitialized util.AtomicBool
+	ctx           context.Context
+	cancel        context.CancelFunc
+
+	mux sync.RWMutex
+	// lastSource is the source of the last accepted extension config.
+	lastSource []byte
+	// lastVersion is the version of the last accepted response, which is sent back in the NACK.
+	lastVersion string
+}
+
+// NewDatasource creates a data source subscribing the extension config named extensionName via ECDS
+// of the management server connected by cc. The typed config of the extension config is converted
+// to the JSON array of the Sentinel rules before handed to the property handlers:
+//
+//  1. the Envoy local rate limit filter config (LocalRateLimitTypeURL) is mapped onto a flow rule
+//     of the resource stat_prefix;
+//  2. the TypedStruct or Struct config carries the JSON array of the Sentinel rules in RulesField.
+//
+// The rejected config is NACKed to the management server with the error detail.
+func NewDatasource(cc *grpc.ClientConn, extensionName string, opts ...Option) (datasource.DataSource, error) {
+	if cc == nil {
+		return nil, ErrNilClientConn
+	}
+	if len(extensionName) == 0 {
+		return nil, ErrEmptyExtensionName
+	}
+	options := evaluateOptions(opts)
+	ctx, cancel := context.WithCancel(context.Background())
+	ds := &xdsDataSource{
+		extensionName: extensionName,
+		cc:            cc,
+		options:       options,
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+	for _, h := range options.propertyHandlers {
+		ds.AddPropertyHandler(h)
+	}
+	return ds, nil
+}
+
+// ReadSource returns the source of the last accepted extension config.
+func (s *xdsDataSource) ReadSource() ([]byte, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.lastSource, nil
+}
+
+// Initialize implement datasource.DataSource interface
+func (s *xdsDataSource) Initialize() error {
+	if !s.isInitialized.CompareAndSet(false, true) {
+		return errors.New("xDS datasource had been initialized")
+	}
+	datasource.Register("xds:"+s.extensionName, s)
+	go util.RunWithRecover(s.watch)
+	return nil
+}
+
+func (s *xdsDataSource) watch() {
+	logging.Info("[xDS] xDS data source is subscribing extension config", "extensionName", s.extensionName)
+	for {
+		err := s.subscribe()
+		if s.ctx.Err() != nil {
+			return
+		}
+		s.SetConnected(false)
+		s.ReportError(err)
+		logging.Warn("[xDS] Extension config stream broke, will re-subscribe later", "extensionName", s.extensionName, "err", err)
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(s.options.retryInterval):
+		}
+	}
+}
+
+// subscribe opens the ECDS stream and handles the responses until the stream breaks.
+func (s *xdsDataSource) subscribe() error {
+	stream, err := s.cc.NewStream(s.ctx, &ecdsStreamDesc, streamExtensionConfigsMethod)
+	if err != nil {
+		return err
+	}
+	s.mux.RLock()
+	req := &discoveryRequest{
+		VersionInfo: s.lastVersion,
+		Node: &node{
+			Id:      s.options.nodeId,
+			Cluster: s.options.nodeCluster,
+		},
+		ResourceNames: []string{s.extensionName},
+		TypeUrl:       TypedExtensionConfigTypeURL,
+	}
+	s.mux.RUnlock()
+	if err := stream.SendMsg(req); err != nil {
+		return err
+	}
+	for {
+		resp := &discoveryResponse{}
+		if err := stream.RecvMsg(resp); err != nil {
+			return err
+		}
+		s.SetConnected(true)
+		// the node is only needed in the first request of the stream
+		req = &discoveryRequest{
+			ResourceNames: []string{s.extensionName},
+			TypeUrl:       TypedExtensionConfigTypeURL,
+			ResponseNonce: resp.Nonce,
+		}
+		if err := s.onResponse(resp); err != nil {
+			logging.Error(err, "[xDS] Fail to apply extension config, NACK", "extensionName", s.extensionName, "version", resp.VersionInfo)
+			req.ErrorDetail = &rpcStatus{
+				Code:    int32(codes.InvalidArgument),
+				Message: err.Error(),
+			}
+		}
+		s.mux.RLock()
+		req.VersionInfo = s.lastVersion
+		s.mux.RUnlock()
+		if err := stream.SendMsg(req); err != nil {
+			return err
+		}
+	}
+}
+
+// onResponse applies the extension config of the response, the absence of which clears the rules.
+func (s *xdsDataSource) onResponse(resp *discoveryResponse) error {
+	var src []byte
+	for _, resource := range resp.Resources {
+		if resource == nil || resource.TypeUrl != TypedExtensionConfigTypeURL {
+			continue
+		}
+		cfg := &typedExtensionConfig{}
+		if err := proto.Unmarshal(resource.Value, cfg); err != nil {
+			return errors.Wrap(err, "fail to unmarshal TypedExtensionConfig")
+		}
+		if cfg.Name != s.extensionName {
+			continue
+		}
+		var err error
+		if src, err = sourceOf(cfg.TypedConfig); err != nil {
+			return err
+		}
+		break
+	}
+	if err := s.Handle(src); err != nil {
+		return err
+	}
+	s.mux.Lock()
+	s.lastSource = src
+	s.lastVersion = resp.VersionInfo
+	s.mux.Unlock()
+	return nil
+}
+
+func (s *xdsDataSource) Close() error {
+	datasource.Deregister(s)
+	s.cancel()
+	logging.Info("[xDS] xDS data source has been closed", "extensionName", s.extensionName)
+	return nil
+}
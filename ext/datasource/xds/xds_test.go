@@ -0,0 +1,219 @@
+package xds
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/system"
+	"github.com/alibaba/sentinel-golang/ext/datasource"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	anypb "github.com/golang/protobuf/ptypes/any"
+	durationpb "github.com/golang/protobuf/ptypes/duration"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	wrapperspb "github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeManagementServer serves the ECDS stream, sending the responses and collecting the requests.
+type fakeManagementServer struct {
+	responses chan *discoveryResponse
+	requests  chan *discoveryRequest
+}
+
+func (s *fakeManagementServer) stream(_ interface{}, stream grpc.ServerStream) error {
+	go func() {
+		for {
+			req := &discoveryRequest{}
+			if err := stream.RecvMsg(req); err != nil {
+				return
+			}
+			s.requests <- req
+		}
+	}()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case resp := <-s.responses:
+			if err := stream.SendMsg(resp); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func newFakeManagementServer(t *testing.T) (*fakeManagementServer, *grpc.ClientConn, func()) {
+	fake := &fakeManagementServer{
+		responses: make(chan *discoveryResponse, 10),
+		requests:  make(chan *discoveryRequest, 10),
+	}
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: ExtensionConfigDiscoveryServiceName,
+		HandlerType: (*interface{})(nil),
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName:    "StreamExtensionConfigs",
+				Handler:       fake.stream,
+				ServerStreams: true,
+				ClientStreams: true,
+			},
+		},
+	}, fake)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	assert.Nil(t, err)
+	return fake, conn, func() {
+		_ = conn.Close()
+		s.Stop()
+	}
+}
+
+func toAny(t *testing.T, typeURL string, m proto.Message) *anypb.Any {
+	b, err := proto.Marshal(m)
+	assert.Nil(t, err)
+	return &anypb.Any{TypeUrl: typeURL, Value: b}
+}
+
+func responseOf(t *testing.T, version, nonce, name string, typedConfig *anypb.Any) *discoveryResponse {
+	return &discoveryResponse{
+		VersionInfo: version,
+		Resources:   []*anypb.Any{toAny(t, TypedExtensionConfigTypeURL, &typedExtensionConfig{Name: name, TypedConfig: typedConfig})},
+		TypeUrl:     TypedExtensionConfigTypeURL,
+		Nonce:       nonce,
+	}
+}
+
+func receive(t *testing.T, requests chan *discoveryRequest) *discoveryRequest {
+	select {
+	case req := <-requests:
+		return req
+	case <-time.After(3 * time.Second):
+		t.Fatal("no discovery request received")
+		return nil
+	}
+}
+
+func TestXdsDataSource(t *testing.T) {
+	fake, conn, stop := newFakeManagementServer(t)
+	defer stop()
+	defer flow.ClearRules()
+
+	ds, err := NewDatasource(conn, "sentinel-ratelimit", WithNode("node-1", "cluster-a"),
+		WithPropertyHandlers(datasource.NewFlowRulesHandler(datasource.FlowRuleJsonArrayParser)))
+	assert.Nil(t, err)
+	assert.Nil(t, ds.Initialize())
+	defer ds.Close()
+
+	req := receive(t, fake.requests)
+	assert.Equal(t, []string{"sentinel-ratelimit"}, req.ResourceNames)
+	assert.Equal(t, TypedExtensionConfigTypeURL, req.TypeUrl)
+	assert.Equal(t, "node-1", req.Node.Id)
+	assert.Equal(t, "cluster-a", req.Node.Cluster)
+
+	t.Run("LocalRateLimit", func(t *testing.T) {
+		fake.responses <- responseOf(t, "v1", "n1", "sentinel-ratelimit", toAny(t, LocalRateLimitTypeURL, &localRateLimit{
+			StatPrefix: "abc",
+			TokenBucket: &tokenBucket{
+				MaxTokens:     10,
+				TokensPerFill: &wrapperspb.UInt32Value{Value: 10},
+				FillInterval:  &durationpb.Duration{Seconds: 2},
+			},
+		}))
+		ack := receive(t, fake.requests)
+		assert.Equal(t, "v1", ack.VersionInfo)
+		assert.Equal(t, "n1", ack.ResponseNonce)
+		assert.Nil(t, ack.ErrorDetail)
+
+		rules := flow.GetRulesOfResource("abc")
+		assert.Equal(t, 1, len(rules))
+		assert.Equal(t, float64(10), rules[0].Threshold)
+		assert.Equal(t, uint32(2000), rules[0].StatIntervalInMs)
+		assert.True(t, ds.Health().Connected)
+	})
+
+	t.Run("Nack", func(t *testing.T) {
+		fake.responses <- responseOf(t, "v2", "n2", "sentinel-ratelimit", toAny(t, LocalRateLimitTypeURL, &localRateLimit{StatPrefix: "abc"}))
+		nack := receive(t, fake.requests)
+		// the version of the last accepted config
+		assert.Equal(t, "v1", nack.VersionInfo)
+		assert.Equal(t, "n2", nack.ResponseNonce)
+		assert.NotNil(t, nack.ErrorDetail)
+		assert.Equal(t, 1, len(flow.GetRulesOfResource("abc")))
+	})
+
+	t.Run("TypedStruct", func(t *testing.T) {
+		value := &structpb.Struct{}
+		assert.Nil(t, jsonpb.UnmarshalString(`{"rules":[{"resource":"def","threshold":5,"statIntervalInMs":1000}]}`, value))
+		fake.responses <- responseOf(t, "v3", "n3", "sentinel-ratelimit", toAny(t, TypedStructTypeURL, &typedStruct{Value: value}))
+		ack := receive(t, fake.requests)
+		assert.Equal(t, "v3", ack.VersionInfo)
+		assert.Nil(t, ack.ErrorDetail)
+		assert.Equal(t, 0, len(flow.GetRulesOfResource("abc")))
+		rules := flow.GetRulesOfResource("def")
+		assert.Equal(t, 1, len(rules))
+		assert.Equal(t, float64(5), rules[0].Threshold)
+
+		src, err := ds.ReadSource()
+		assert.Nil(t, err)
+		assert.Contains(t, string(src), `"def"`)
+	})
+
+	t.Run("Removed", func(t *testing.T) {
+		fake.responses <- &discoveryResponse{VersionInfo: "v4", TypeUrl: TypedExtensionConfigTypeURL, Nonce: "n4"}
+		ack := receive(t, fake.requests)
+		assert.Equal(t, "v4", ack.VersionInfo)
+		assert.Nil(t, ack.ErrorDetail)
+		assert.Equal(t, 0, len(flow.GetRules()))
+	})
+}
+
+func TestSourceOf(t *testing.T) {
+	t.Run("Unsupported", func(t *testing.T) {
+		_, err := sourceOf(&anypb.Any{TypeUrl: "type.googleapis.com/foo.Bar"})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Struct", func(t *testing.T) {
+		value := &structpb.Struct{}
+		assert.Nil(t, jsonpb.UnmarshalString(`{"rules":[{"metricType":0,"triggerCount":0.8,"strategy":3}]}`, value))
+		src, err := sourceOf(toAny(t, StructTypeURL, value))
+		assert.Nil(t, err)
+		parsed, err := datasource.SystemRuleJsonArrayParser(src)
+		assert.Nil(t, err)
+		rules := parsed.([]*system.Rule)
+		assert.Equal(t, 1, len(rules))
+		assert.Equal(t, 0.8, rules[0].TriggerCount)
+	})
+
+	t.Run("InvalidRules", func(t *testing.T) {
+		value := &structpb.Struct{}
+		assert.Nil(t, jsonpb.UnmarshalString(`{"rules":"abc"}`, value))
+		_, err := sourceOf(toAny(t, StructTypeURL, value))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("DefaultTokensPerFill", func(t *testing.T) {
+		src, err := sourceOf(toAny(t, LocalRateLimitTypeURL, &localRateLimit{
+			StatPrefix:  "abc",
+			TokenBucket: &tokenBucket{FillInterval: &durationpb.Duration{Nanos: 500000000}},
+		}))
+		assert.Nil(t, err)
+		parsed, err := datasource.FlowRuleJsonArrayParser(src)
+		assert.Nil(t, err)
+		rules := parsed.([]*flow.Rule)
+		assert.Equal(t, float64(1), rules[0].Threshold)
+		assert.Equal(t, uint32(500), rules[0].StatIntervalInMs)
+	})
+}
@@ -0,0 +1,110 @@
+package ruleconv
+
+import (
+	"encoding/json"
+
+	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/pkg/errors"
+)
+
+const (
+	javaDegradeGradeRt             = 0
+	javaDegradeGradeExceptionRatio = 1
+	javaDegradeGradeExceptionCount = 2
+)
+
+// JavaDegradeRule is the DegradeRule of Sentinel Java.
+type JavaDegradeRule struct {
+	ID       *int64 `json:"id,omitempty"`
+	Resource string `json:"resource"`
+	LimitApp string `json:"limitApp,omitempty"`
+	// Grade is the circuit breaking strategy, 0 for slow request ratio, 1 for exception ratio
+	// and 2 for exception count.
+	Grade int32 `json:"grade"`
+	// Count is the max allowed RT (in ms) for slow request ratio strategy, otherwise the threshold.
+	Count float64 `json:"count"`
+	// TimeWindow is the recovery timeout in seconds.
+	TimeWindow         int32   `json:"timeWindow"`
+	MinRequestAmount   int32   `json:"minRequestAmount"`
+	SlowRatioThreshold float64 `json:"slowRatioThreshold"`
+	StatIntervalMs     int32   `json:"statIntervalMs"`
+}
+
+// CircuitBreakerRulesFromJava converts the JSON array of Java degrade rules to the Go circuit breaker rules.
+func CircuitBreakerRulesFromJava(src []byte) ([]*circuitbreaker.Rule, error) {
+	javaRules := make([]*JavaDegradeRule, 0)
+	if err := unmarshalJavaRules(src, &javaRules); err != nil {
+		return nil, err
+	}
+	rules := make([]*circuitbreaker.Rule, 0, len(javaRules))
+	for _, jr := range javaRules {
+		if err := checkJavaLimitApp(jr.LimitApp); err != nil {
+			return nil, errors.Wrapf(err, "fail to convert Java degrade rule of resource %s", jr.Resource)
+		}
+		r := &circuitbreaker.Rule{
+			Id:               javaIDToGo(jr.ID),
+			Resource:         jr.Resource,
+			RetryTimeoutMs:   uint32(jr.TimeWindow) * 1000,
+			MinRequestAmount: uint64(jr.MinRequestAmount),
+			StatIntervalMs:   uint32(jr.StatIntervalMs),
+			Threshold:        jr.Count,
+		}
+		switch jr.Grade {
+		case javaDegradeGradeRt:
+			r.Strategy = circuitbreaker.SlowRequestRatio
+			r.MaxAllowedRtMs = uint64(jr.Count)
+			r.Threshold = jr.SlowRatioThreshold
+		case javaDegradeGradeExceptionRatio:
+			r.Strategy = circuitbreaker.ErrorRatio
+		case javaDegradeGradeExceptionCount:
+			r.Strategy = circuitbreaker.ErrorCount
+		default:
+			return nil, errors.Errorf("fail to convert Java degrade rule of resource %s: unsupported grade: %d", jr.Resource, jr.Grade)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// CircuitBreakerRulesToJava converts the Go circuit breaker rules to the JSON array of Java degrade rules.
+func CircuitBreakerRulesToJava(rules []*circuitbreaker.Rule) ([]byte, error) {
+	javaRules := make([]*JavaDegradeRule, 0, len(rules))
+	for _, r := range rules {
+		jr := &JavaDegradeRule{
+			ID:       goIDToJava(r.Id),
+			Resource: r.Resource,
+			LimitApp: javaDefaultLimitApp,
+			Count:    r.Threshold,
+			// The time window of Java rules is in seconds, so round up.
+			TimeWindow:       int32((r.RetryTimeoutMs + 999) / 1000),
+			MinRequestAmount: int32(r.MinRequestAmount),
+			StatIntervalMs:   int32(r.StatIntervalMs),
+		}
+		switch r.Strategy {
+		case circuitbreaker.SlowRequestRatio:
+			jr.Grade = javaDegradeGradeRt
+			jr.Count = float64(r.MaxAllowedRtMs)
+			jr.SlowRatioThreshold = r.Threshold
+		case circuitbreaker.ErrorRatio:
+			jr.Grade = javaDegradeGradeExceptionRatio
+		case circuitbreaker.ErrorCount:
+			jr.Grade = javaDegradeGradeExceptionCount
+		default:
+			return nil, errors.Errorf("fail to convert circuit breaker rule of resource %s: unsupported strategy: %s", r.Resource, r.Strategy)
+		}
+		if len(r.DeploymentLabel) > 0 {
+			logging.Warn("[ruleconv] The fields only available in Go are dropped on converting to Java", "rule", r)
+		}
+		javaRules = append(javaRules, jr)
+	}
+	return json.Marshal(javaRules)
+}
+
+// JavaCircuitBreakerRulesParser parses the JSON array of Java degrade rules as []*circuitbreaker.Rule,
+// which could be used as the datasource.PropertyConverter of circuit breaker rules.
+func JavaCircuitBreakerRulesParser(src []byte) (interface{}, error) {
+	return javaParser(src, func(src []byte) (interface{}, error) {
+		return CircuitBreakerRulesFromJava(src)
+	})
+}
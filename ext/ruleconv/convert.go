@@ -0,0 +1,135 @@
+package ruleconv
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/hotspot"
+	"github.com/alibaba/sentinel-golang/core/system"
+	"github.com/pkg/errors"
+)
+
+const (
+	FormatGo   = "go"
+	FormatJava = "java"
+
+	ModuleFlow           = "flow"
+	ModuleCircuitBreaker = "circuitbreaker"
+	ModuleHotspot        = "hotspot"
+	ModuleSystem         = "system"
+)
+
+// javaDefaultLimitApp is the limitApp of Java rules that applies to all the callers.
+const javaDefaultLimitApp = "default"
+
+type converter struct {
+	// newGoRules creates the pointer to the slice of Go rules.
+	newGoRules func() interface{}
+	fromJava   func(src []byte) (interface{}, error)
+	toJava     func(goRules interface{}) ([]byte, error)
+}
+
+var converters = map[string]converter{
+	ModuleFlow: {
+		newGoRules: func() interface{} { return &[]*flow.Rule{} },
+		fromJava:   func(src []byte) (interface{}, error) { return FlowRulesFromJava(src) },
+		toJava:     func(rules interface{}) ([]byte, error) { return FlowRulesToJava(*rules.(*[]*flow.Rule)) },
+	},
+	ModuleCircuitBreaker: {
+		newGoRules: func() interface{} { return &[]*circuitbreaker.Rule{} },
+		fromJava:   func(src []byte) (interface{}, error) { return CircuitBreakerRulesFromJava(src) },
+		toJava: func(rules interface{}) ([]byte, error) {
+			return CircuitBreakerRulesToJava(*rules.(*[]*circuitbreaker.Rule))
+		},
+	},
+	ModuleHotspot: {
+		newGoRules: func() interface{} { return &[]*hotspot.Rule{} },
+		fromJava:   func(src []byte) (interface{}, error) { return HotspotRulesFromJava(src) },
+		toJava:     func(rules interface{}) ([]byte, error) { return HotspotRulesToJava(*rules.(*[]*hotspot.Rule)) },
+	},
+	ModuleSystem: {
+		newGoRules: func() interface{} { return &[]*system.Rule{} },
+		fromJava:   func(src []byte) (interface{}, error) { return SystemRulesFromJava(src) },
+		toJava:     func(rules interface{}) ([]byte, error) { return SystemRulesToJava(*rules.(*[]*system.Rule)) },
+	},
+}
+
+// Convert converts the JSON array of the rules of the module (e.g. ModuleFlow) from one format to another,
+// the format is either FormatGo or FormatJava.
+func Convert(module, from, to string, src []byte) ([]byte, error) {
+	c, ok := converters[module]
+	if !ok {
+		return nil, errors.Errorf("unsupported rule module: %s", module)
+	}
+	if !isValidFormat(from) || !isValidFormat(to) {
+		return nil, errors.Errorf("unsupported rule format: %s -> %s", from, to)
+	}
+	if from == FormatJava {
+		rules, err := c.fromJava(src)
+		if err != nil {
+			return nil, err
+		}
+		if to == FormatGo {
+			return json.Marshal(rules)
+		}
+		// Normalize the Java rules by a round trip.
+		src, err = json.Marshal(rules)
+		if err != nil {
+			return nil, err
+		}
+	}
+	rules := c.newGoRules()
+	if err := json.Unmarshal(src, rules); err != nil {
+		return nil, errors.Wrap(err, "invalid Go rules")
+	}
+	if to == FormatJava {
+		return c.toJava(rules)
+	}
+	return json.Marshal(rules)
+}
+
+func isValidFormat(format string) bool {
+	return format == FormatGo || format == FormatJava
+}
+
+// javaIDToGo converts the numeric ID of Java rules to the ID of Go rules.
+func javaIDToGo(id *int64) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatInt(*id, 10)
+}
+
+// goIDToJava converts the ID of Go rules to Java, the non-numeric IDs are dropped.
+func goIDToJava(id string) *int64 {
+	if v, err := strconv.ParseInt(id, 10, 64); err == nil {
+		return &v
+	}
+	return nil
+}
+
+func checkJavaLimitApp(limitApp string) error {
+	if len(limitApp) > 0 && limitApp != javaDefaultLimitApp {
+		return errors.Errorf("unsupported limitApp: %s", limitApp)
+	}
+	return nil
+}
+
+// unmarshalJavaRules unmarshals the JSON array of Java rules, the empty source means no rules.
+func unmarshalJavaRules(src []byte, v interface{}) error {
+	if len(src) == 0 {
+		return nil
+	}
+	return errors.Wrap(json.Unmarshal(src, v), "invalid Java rules")
+}
+
+// javaParser wraps the conversion from Java rules as the datasource.PropertyConverter,
+// which returns nil for the empty source so that the rules are cleared.
+func javaParser(src []byte, fromJava func(src []byte) (interface{}, error)) (interface{}, error) {
+	if len(src) == 0 {
+		return nil, nil
+	}
+	return fromJava(src)
+}
@@ -0,0 +1,23 @@
+// Package ruleconv converts the rules between the Go-native rule JSON and the rule JSON of Sentinel Java
+// (i.e. the rules pushed by Sentinel dashboard), which helps the migration of mixed Java/Go deployments
+// sharing the same rule sources.
+//
+// The rule modules are mapped as follows:
+//
+//	Go               Java
+//	flow             FlowRule
+//	circuitbreaker   DegradeRule
+//	hotspot          ParamFlowRule
+//	system           SystemRule
+//
+// The conversion fails if the semantics of the rule can't be preserved (e.g. the Java flow rules of CHAIN strategy),
+// while the optional tuning fields only available in Go (e.g. flow.Rule.WarmUpCurve) are dropped with a warning
+// on converting to Java.
+//
+// The Java*Parser functions could be used as the datasource.PropertyConverter to load the Java rules directly:
+//
+//	h := datasource.NewFlowRulesHandler(ruleconv.JavaFlowRulesParser)
+//
+// Validate and Diff help inspect the rule files of either format before they are pushed to the rule sources,
+// which are also available via the sentinel-cli tool (see cmd/sentinel-cli).
+package ruleconv
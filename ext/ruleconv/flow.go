@@ -0,0 +1,182 @@
+package ruleconv
+
+import (
+	"encoding/json"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/pkg/errors"
+)
+
+const (
+	javaFlowGradeThread = 0
+	javaFlowGradeQps    = 1
+
+	javaFlowStrategyDirect = 0
+	javaFlowStrategyRelate = 1
+
+	javaControlBehaviorDefault           = 0
+	javaControlBehaviorWarmUp            = 1
+	javaControlBehaviorRateLimiter       = 2
+	javaControlBehaviorWarmUpRateLimiter = 3
+)
+
+// JavaFlowRule is the FlowRule of Sentinel Java.
+type JavaFlowRule struct {
+	ID       *int64 `json:"id,omitempty"`
+	Resource string `json:"resource"`
+	LimitApp string `json:"limitApp,omitempty"`
+	// Grade is the metric type, 0 for thread count and 1 for QPS.
+	Grade int32   `json:"grade"`
+	Count float64 `json:"count"`
+	// Strategy is the relation strategy, 0 for direct, 1 for relate and 2 for chain.
+	Strategy    int32  `json:"strategy"`
+	RefResource string `json:"refResource,omitempty"`
+	// ControlBehavior is 0 for default, 1 for warm up, 2 for rate limiter and 3 for warm up rate limiter.
+	ControlBehavior   int32 `json:"controlBehavior"`
+	WarmUpPeriodSec   int32 `json:"warmUpPeriodSec"`
+	MaxQueueingTimeMs int32 `json:"maxQueueingTimeMs"`
+	ClusterMode       bool  `json:"clusterMode"`
+}
+
+// FlowRulesFromJava converts the JSON array of Java flow rules to the Go flow rules.
+func FlowRulesFromJava(src []byte) ([]*flow.Rule, error) {
+	javaRules := make([]*JavaFlowRule, 0)
+	if err := unmarshalJavaRules(src, &javaRules); err != nil {
+		return nil, err
+	}
+	rules := make([]*flow.Rule, 0, len(javaRules))
+	for _, jr := range javaRules {
+		r, err := flowRuleFromJava(jr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fail to convert Java flow rule of resource %s", jr.Resource)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+func flowRuleFromJava(jr *JavaFlowRule) (*flow.Rule, error) {
+	if err := checkJavaLimitApp(jr.LimitApp); err != nil {
+		return nil, err
+	}
+	if jr.Grade != javaFlowGradeQps {
+		return nil, errors.Errorf("unsupported grade: %d, use isolation rules for thread count", jr.Grade)
+	}
+	if jr.ClusterMode {
+		return nil, errors.New("cluster mode is not supported")
+	}
+	r := &flow.Rule{
+		ID:                javaIDToGo(jr.ID),
+		Resource:          jr.Resource,
+		Threshold:         jr.Count,
+		MaxQueueingTimeMs: uint32(jr.MaxQueueingTimeMs),
+		WarmUpPeriodSec:   uint32(jr.WarmUpPeriodSec),
+	}
+	switch jr.Strategy {
+	case javaFlowStrategyDirect:
+		r.RelationStrategy = flow.CurrentResource
+	case javaFlowStrategyRelate:
+		r.RelationStrategy = flow.AssociatedResource
+		r.RefResource = jr.RefResource
+	default:
+		return nil, errors.Errorf("unsupported strategy: %d", jr.Strategy)
+	}
+	switch jr.ControlBehavior {
+	case javaControlBehaviorDefault:
+		r.TokenCalculateStrategy, r.ControlBehavior = flow.Direct, flow.Reject
+	case javaControlBehaviorWarmUp:
+		r.TokenCalculateStrategy, r.ControlBehavior = flow.WarmUp, flow.Reject
+	case javaControlBehaviorRateLimiter:
+		r.TokenCalculateStrategy, r.ControlBehavior = flow.Direct, flow.Throttling
+	case javaControlBehaviorWarmUpRateLimiter:
+		r.TokenCalculateStrategy, r.ControlBehavior = flow.WarmUp, flow.Throttling
+	default:
+		return nil, errors.Errorf("unsupported control behavior: %d", jr.ControlBehavior)
+	}
+	if r.ControlBehavior == flow.Reject {
+		r.MaxQueueingTimeMs = 0
+	}
+	if r.TokenCalculateStrategy == flow.Direct {
+		r.WarmUpPeriodSec = 0
+	}
+	return r, nil
+}
+
+// FlowRulesToJava converts the Go flow rules to the JSON array of Java flow rules.
+func FlowRulesToJava(rules []*flow.Rule) ([]byte, error) {
+	javaRules := make([]*JavaFlowRule, 0, len(rules))
+	for _, r := range rules {
+		jr, err := flowRuleToJava(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fail to convert flow rule of resource %s", r.Resource)
+		}
+		javaRules = append(javaRules, jr)
+	}
+	return json.Marshal(javaRules)
+}
+
+func flowRuleToJava(r *flow.Rule) (*JavaFlowRule, error) {
+	if r.MetricType != flow.RequestCount {
+		return nil, errors.Errorf("unsupported metric type: %s", r.MetricType)
+	}
+	if len(r.TargetTag) > 0 {
+		return nil, errors.Errorf("unsupported target tag: %s", r.TargetTag)
+	}
+	if r.Category != base.CategoryNone {
+		return nil, errors.Errorf("unsupported category: %s", r.Category)
+	}
+	if len(r.SharedStatKey) > 0 {
+		// The limit shared by the processes on the host can't be expressed by the per-process Java rule.
+		return nil, errors.Errorf("unsupported shared stat key: %s", r.SharedStatKey)
+	}
+	jr := &JavaFlowRule{
+		ID:                goIDToJava(r.ID),
+		Resource:          r.Resource,
+		LimitApp:          javaDefaultLimitApp,
+		Grade:             javaFlowGradeQps,
+		Count:             r.Threshold,
+		MaxQueueingTimeMs: int32(r.MaxQueueingTimeMs),
+		WarmUpPeriodSec:   int32(r.WarmUpPeriodSec),
+	}
+	// The threshold of Java rules is always per second.
+	if r.StatIntervalInMs > 0 && r.StatIntervalInMs != 1000 {
+		jr.Count = r.Threshold * 1000 / float64(r.StatIntervalInMs)
+	}
+	switch r.RelationStrategy {
+	case flow.CurrentResource:
+		jr.Strategy = javaFlowStrategyDirect
+	case flow.AssociatedResource:
+		jr.Strategy = javaFlowStrategyRelate
+		jr.RefResource = r.RefResource
+	default:
+		return nil, errors.Errorf("unsupported relation strategy: %d", r.RelationStrategy)
+	}
+	switch {
+	case r.TokenCalculateStrategy == flow.Direct && r.ControlBehavior == flow.Reject:
+		jr.ControlBehavior = javaControlBehaviorDefault
+	case r.TokenCalculateStrategy == flow.WarmUp && r.ControlBehavior == flow.Reject:
+		jr.ControlBehavior = javaControlBehaviorWarmUp
+	case r.TokenCalculateStrategy == flow.Direct && r.ControlBehavior == flow.Throttling:
+		jr.ControlBehavior = javaControlBehaviorRateLimiter
+	case r.TokenCalculateStrategy == flow.WarmUp && r.ControlBehavior == flow.Throttling:
+		jr.ControlBehavior = javaControlBehaviorWarmUpRateLimiter
+	default:
+		return nil, errors.Errorf("unsupported token calculate strategy and control behavior: %s, %s",
+			r.TokenCalculateStrategy, r.ControlBehavior)
+	}
+	if r.MaxQueueingRequests > 0 || r.WarmUpColdFactor > 0 || r.WarmUpCurve != flow.TokenBucketCurve || r.ColdStartCount > 0 ||
+		r.WarmUpRestartIdleFactor > 0 || len(r.DeploymentLabel) > 0 || r.WarningCount > 0 {
+		logging.Warn("[ruleconv] The fields only available in Go are dropped on converting to Java", "rule", r)
+	}
+	return jr, nil
+}
+
+// JavaFlowRulesParser parses the JSON array of Java flow rules as []*flow.Rule, which could be used as
+// the datasource.PropertyConverter of flow rules.
+func JavaFlowRulesParser(src []byte) (interface{}, error) {
+	return javaParser(src, func(src []byte) (interface{}, error) {
+		return FlowRulesFromJava(src)
+	})
+}
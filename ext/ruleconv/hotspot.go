@@ -0,0 +1,184 @@
+package ruleconv
+
+import (
+	"encoding/json"
+
+	"github.com/alibaba/sentinel-golang/core/hotspot"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/pkg/errors"
+)
+
+// JavaParamFlowRule is the ParamFlowRule of Sentinel Java.
+type JavaParamFlowRule struct {
+	ID       *int64 `json:"id,omitempty"`
+	Resource string `json:"resource"`
+	LimitApp string `json:"limitApp,omitempty"`
+	// Grade is the metric type, 0 for thread count and 1 for QPS.
+	Grade    int32   `json:"grade"`
+	ParamIdx int     `json:"paramIdx"`
+	Count    float64 `json:"count"`
+	// ControlBehavior is 0 for default and 2 for rate limiter.
+	ControlBehavior   int32               `json:"controlBehavior"`
+	MaxQueueingTimeMs int32               `json:"maxQueueingTimeMs"`
+	BurstCount        int32               `json:"burstCount"`
+	DurationInSec     int64               `json:"durationInSec"`
+	ParamFlowItemList []JavaParamFlowItem `json:"paramFlowItemList,omitempty"`
+	ClusterMode       bool                `json:"clusterMode"`
+}
+
+// JavaParamFlowItem is the specific threshold of the param value of Java ParamFlowRule.
+type JavaParamFlowItem struct {
+	Object string `json:"object"`
+	Count  int64  `json:"count"`
+	// ClassType is the Java class of the param value, e.g. int or java.lang.String.
+	ClassType string `json:"classType"`
+}
+
+var javaClassTypeToKind = map[string]hotspot.ParamKind{
+	"int":               hotspot.KindInt,
+	"long":              hotspot.KindInt,
+	"short":             hotspot.KindInt,
+	"byte":              hotspot.KindInt,
+	"java.lang.Integer": hotspot.KindInt,
+	"java.lang.Long":    hotspot.KindInt,
+	"java.lang.Short":   hotspot.KindInt,
+	"java.lang.Byte":    hotspot.KindInt,
+	"String":            hotspot.KindString,
+	"java.lang.String":  hotspot.KindString,
+	"boolean":           hotspot.KindBool,
+	"java.lang.Boolean": hotspot.KindBool,
+	"double":            hotspot.KindFloat64,
+	"float":             hotspot.KindFloat64,
+	"java.lang.Double":  hotspot.KindFloat64,
+	"java.lang.Float":   hotspot.KindFloat64,
+}
+
+var kindToJavaClassType = map[hotspot.ParamKind]string{
+	hotspot.KindInt:     "int",
+	hotspot.KindString:  "java.lang.String",
+	hotspot.KindBool:    "boolean",
+	hotspot.KindFloat64: "double",
+}
+
+// HotspotRulesFromJava converts the JSON array of Java param flow rules to the Go hotspot rules.
+func HotspotRulesFromJava(src []byte) ([]*hotspot.Rule, error) {
+	javaRules := make([]*JavaParamFlowRule, 0)
+	if err := unmarshalJavaRules(src, &javaRules); err != nil {
+		return nil, err
+	}
+	rules := make([]*hotspot.Rule, 0, len(javaRules))
+	for _, jr := range javaRules {
+		r, err := hotspotRuleFromJava(jr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fail to convert Java param flow rule of resource %s", jr.Resource)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+func hotspotRuleFromJava(jr *JavaParamFlowRule) (*hotspot.Rule, error) {
+	if err := checkJavaLimitApp(jr.LimitApp); err != nil {
+		return nil, err
+	}
+	r := &hotspot.Rule{
+		ID:            javaIDToGo(jr.ID),
+		Resource:      jr.Resource,
+		ParamIndex:    jr.ParamIdx,
+		Threshold:     jr.Count,
+		DurationInSec: jr.DurationInSec,
+		ClusterMode:   jr.ClusterMode,
+	}
+	if r.DurationInSec <= 0 {
+		// The default duration of Java rules.
+		r.DurationInSec = 1
+	}
+	switch jr.Grade {
+	case javaFlowGradeThread:
+		r.MetricType = hotspot.Concurrency
+	case javaFlowGradeQps:
+		r.MetricType = hotspot.QPS
+	default:
+		return nil, errors.Errorf("unsupported grade: %d", jr.Grade)
+	}
+	switch jr.ControlBehavior {
+	case javaControlBehaviorDefault:
+		r.ControlBehavior = hotspot.Reject
+		r.BurstCount = int64(jr.BurstCount)
+	case javaControlBehaviorRateLimiter:
+		r.ControlBehavior = hotspot.Throttling
+		r.MaxQueueingTimeMs = int64(jr.MaxQueueingTimeMs)
+	default:
+		return nil, errors.Errorf("unsupported control behavior: %d", jr.ControlBehavior)
+	}
+	for _, item := range jr.ParamFlowItemList {
+		kind, ok := javaClassTypeToKind[item.ClassType]
+		if !ok {
+			return nil, errors.Errorf("unsupported class type of param item: %s", item.ClassType)
+		}
+		r.SpecificItems = append(r.SpecificItems, hotspot.SpecificValue{
+			ValKind:   kind,
+			ValStr:    item.Object,
+			Threshold: item.Count,
+		})
+	}
+	return r, nil
+}
+
+// HotspotRulesToJava converts the Go hotspot rules to the JSON array of Java param flow rules.
+func HotspotRulesToJava(rules []*hotspot.Rule) ([]byte, error) {
+	javaRules := make([]*JavaParamFlowRule, 0, len(rules))
+	for _, r := range rules {
+		jr := &JavaParamFlowRule{
+			ID:            goIDToJava(r.ID),
+			Resource:      r.Resource,
+			LimitApp:      javaDefaultLimitApp,
+			ParamIdx:      r.ParamIndex,
+			Count:         r.Threshold,
+			DurationInSec: r.DurationInSec,
+			ClusterMode:   r.ClusterMode,
+		}
+		switch r.MetricType {
+		case hotspot.Concurrency:
+			jr.Grade = javaFlowGradeThread
+		case hotspot.QPS:
+			jr.Grade = javaFlowGradeQps
+		default:
+			return nil, errors.Errorf("fail to convert hotspot rule of resource %s: unsupported metric type: %s", r.Resource, r.MetricType)
+		}
+		switch r.ControlBehavior {
+		case hotspot.Reject:
+			jr.ControlBehavior = javaControlBehaviorDefault
+			jr.BurstCount = int32(r.BurstCount)
+		case hotspot.Throttling:
+			jr.ControlBehavior = javaControlBehaviorRateLimiter
+			jr.MaxQueueingTimeMs = int32(r.MaxQueueingTimeMs)
+		default:
+			return nil, errors.Errorf("fail to convert hotspot rule of resource %s: unsupported control behavior: %s", r.Resource, r.ControlBehavior)
+		}
+		for _, item := range r.SpecificItems {
+			classType, ok := kindToJavaClassType[item.ValKind]
+			if !ok {
+				return nil, errors.Errorf("fail to convert hotspot rule of resource %s: unsupported kind of specific item: %s", r.Resource, item.ValKind)
+			}
+			jr.ParamFlowItemList = append(jr.ParamFlowItemList, JavaParamFlowItem{
+				Object:    item.ValStr,
+				Count:     item.Threshold,
+				ClassType: classType,
+			})
+		}
+		if r.ParamsMaxCapacity > 0 || r.EvictionPolicy != hotspot.EvictionLRU || len(r.DeploymentLabel) > 0 {
+			logging.Warn("[ruleconv] The fields only available in Go are dropped on converting to Java", "rule", r)
+		}
+		javaRules = append(javaRules, jr)
+	}
+	return json.Marshal(javaRules)
+}
+
+// JavaHotspotRulesParser parses the JSON array of Java param flow rules as []*hotspot.Rule,
+// which could be used as the datasource.PropertyConverter of hotspot rules.
+func JavaHotspotRulesParser(src []byte) (interface{}, error) {
+	return javaParser(src, func(src []byte) (interface{}, error) {
+		return HotspotRulesFromJava(src)
+	})
+}
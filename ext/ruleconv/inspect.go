@@ -0,0 +1,199 @@
+package ruleconv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/hotspot"
+	"github.com/alibaba/sentinel-golang/core/system"
+	"github.com/pkg/errors"
+)
+
+// RuleError is the validation error of the rule at Index of the rule set.
+type RuleError struct {
+	Index int
+	Err   error
+}
+
+func (e RuleError) Error() string {
+	return fmt.Sprintf("rule[%d]: %s", e.Index, e.Err.Error())
+}
+
+var validators = map[string]func(rules interface{}) []RuleError{
+	ModuleFlow: func(rules interface{}) []RuleError {
+		var ret []RuleError
+		for i, r := range *rules.(*[]*flow.Rule) {
+			if err := flow.IsValidRule(r); err != nil {
+				ret = append(ret, RuleError{Index: i, Err: err})
+			}
+		}
+		return ret
+	},
+	ModuleCircuitBreaker: func(rules interface{}) []RuleError {
+		var ret []RuleError
+		for i, r := range *rules.(*[]*circuitbreaker.Rule) {
+			if err := circuitbreaker.IsValid(r); err != nil {
+				ret = append(ret, RuleError{Index: i, Err: err})
+			}
+		}
+		return ret
+	},
+	ModuleHotspot: func(rules interface{}) []RuleError {
+		var ret []RuleError
+		for i, r := range *rules.(*[]*hotspot.Rule) {
+			if err := hotspot.IsValidRule(r); err != nil {
+				ret = append(ret, RuleError{Index: i, Err: err})
+			}
+		}
+		return ret
+	},
+	ModuleSystem: func(rules interface{}) []RuleError {
+		var ret []RuleError
+		for i, r := range *rules.(*[]*system.Rule) {
+			if err := system.IsValidSystemRule(r); err != nil {
+				ret = append(ret, RuleError{Index: i, Err: err})
+			}
+		}
+		return ret
+	},
+}
+
+// Validate validates the JSON array of the rules of the module in the format (either FormatGo or FormatJava),
+// and returns the errors of the invalid rules. The error is returned if the rules can't be parsed at all.
+func Validate(module, format string, src []byte) ([]RuleError, error) {
+	validate, ok := validators[module]
+	if !ok {
+		return nil, errors.Errorf("unsupported rule module: %s", module)
+	}
+	rules, err := parseGoRules(module, format, src)
+	if err != nil {
+		return nil, err
+	}
+	return validate(rules), nil
+}
+
+// DiffType is the type of the difference between two rule sets.
+type DiffType string
+
+const (
+	DiffAdded   DiffType = "added"
+	DiffRemoved DiffType = "removed"
+	DiffChanged DiffType = "changed"
+)
+
+// RuleDiff is a difference between two rule sets. Old is absent for the added rule, and New is absent
+// for the removed rule.
+type RuleDiff struct {
+	Type DiffType        `json:"type"`
+	Key  string          `json:"key"`
+	Old  json.RawMessage `json:"old,omitempty"`
+	New  json.RawMessage `json:"new,omitempty"`
+}
+
+// Diff compares two JSON arrays of the Go rules of the module, and returns the differences ordered by the keys.
+// The rules are matched by the ID if present, otherwise by the resource (the metric type for the system rules)
+// and the order among the rules of the same resource. The rules are compared after being normalized,
+// so the field order and the absent default fields make no difference.
+func Diff(module string, oldSrc, newSrc []byte) ([]RuleDiff, error) {
+	oldRules, err := keyedRulesOf(module, oldSrc)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid old rules")
+	}
+	newRules, err := keyedRulesOf(module, newSrc)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid new rules")
+	}
+	ret := make([]RuleDiff, 0)
+	for key, o := range oldRules {
+		n, ok := newRules[key]
+		if !ok {
+			ret = append(ret, RuleDiff{Type: DiffRemoved, Key: key, Old: o})
+			continue
+		}
+		if !bytes.Equal(o, n) {
+			ret = append(ret, RuleDiff{Type: DiffChanged, Key: key, Old: o, New: n})
+		}
+	}
+	for key, n := range newRules {
+		if _, ok := oldRules[key]; !ok {
+			ret = append(ret, RuleDiff{Type: DiffAdded, Key: key, New: n})
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Key < ret[j].Key
+	})
+	return ret, nil
+}
+
+// parseGoRules parses the JSON array of the rules of the module into the pointer to the slice of Go rules.
+func parseGoRules(module, format string, src []byte) (interface{}, error) {
+	c, ok := converters[module]
+	if !ok {
+		return nil, errors.Errorf("unsupported rule module: %s", module)
+	}
+	if !isValidFormat(format) {
+		return nil, errors.Errorf("unsupported rule format: %s", format)
+	}
+	if format == FormatJava {
+		goSrc, err := Convert(module, FormatJava, FormatGo, src)
+		if err != nil {
+			return nil, err
+		}
+		src = goSrc
+	}
+	rules := c.newGoRules()
+	if len(src) == 0 {
+		return rules, nil
+	}
+	if err := json.Unmarshal(src, rules); err != nil {
+		return nil, errors.Wrap(err, "invalid Go rules")
+	}
+	return rules, nil
+}
+
+// keyedRulesOf returns the normalized JSON of the Go rules keyed by the rule keys.
+func keyedRulesOf(module string, src []byte) (map[string]json.RawMessage, error) {
+	rules, err := parseGoRules(module, FormatGo, src)
+	if err != nil {
+		return nil, err
+	}
+	slice := reflect.ValueOf(rules).Elem()
+	ret := make(map[string]json.RawMessage, slice.Len())
+	occurrences := make(map[string]int)
+	for i := 0; i < slice.Len(); i++ {
+		normalized, err := json.Marshal(slice.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		// decode into the map to read the key fields in common, which also sorts the fields
+		fields := make(map[string]interface{})
+		if err := json.Unmarshal(normalized, &fields); err != nil {
+			return nil, err
+		}
+		if normalized, err = json.Marshal(fields); err != nil {
+			return nil, err
+		}
+		key := ruleKeyOf(module, fields)
+		if id, ok := fields["id"].(string); ok && len(id) > 0 {
+			key = "id:" + id
+		} else {
+			occurrences[key]++
+			key = key + "#" + strconv.Itoa(occurrences[key])
+		}
+		ret[key] = normalized
+	}
+	return ret, nil
+}
+
+func ruleKeyOf(module string, fields map[string]interface{}) string {
+	if module == ModuleSystem {
+		return fmt.Sprintf("metricType:%v", fields["metricType"])
+	}
+	return fmt.Sprintf("resource:%v", fields["resource"])
+}
@@ -0,0 +1,68 @@
+package ruleconv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("Go", func(t *testing.T) {
+		errs, err := Validate(ModuleFlow, FormatGo, []byte(`[{"resource":"abc","threshold":10},{"resource":"","threshold":10},{"resource":"def","threshold":-1}]`))
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(errs))
+		assert.Equal(t, 1, errs[0].Index)
+		assert.Equal(t, 2, errs[1].Index)
+		assert.Contains(t, errs[0].Error(), "rule[1]")
+	})
+
+	t.Run("Java", func(t *testing.T) {
+		errs, err := Validate(ModuleFlow, FormatJava, []byte(`[{"resource":"abc","grade":1,"count":10}]`))
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(errs))
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		_, err := Validate(ModuleHotspot, FormatGo, []byte(`{`))
+		assert.NotNil(t, err)
+		_, err = Validate("foo", FormatGo, []byte(`[]`))
+		assert.NotNil(t, err)
+		_, err = Validate(ModuleSystem, "yaml", []byte(`[]`))
+		assert.NotNil(t, err)
+	})
+}
+
+func TestDiff(t *testing.T) {
+	oldSrc := []byte(`[
+		{"id":"r1","resource":"abc","threshold":10},
+		{"resource":"def","threshold":10},
+		{"resource":"def","threshold":20},
+		{"resource":"ghi","threshold":10}
+	]`)
+	newSrc := []byte(`[
+		{"threshold":10,"resource":"abc","id":"r1"},
+		{"resource":"def","threshold":10},
+		{"resource":"def","threshold":30},
+		{"resource":"jkl","threshold":10}
+	]`)
+	diffs, err := Diff(ModuleFlow, oldSrc, newSrc)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(diffs))
+	assert.Equal(t, DiffChanged, diffs[0].Type)
+	assert.Equal(t, "resource:def#2", diffs[0].Key)
+	assert.Contains(t, string(diffs[0].Old), `"threshold":20`)
+	assert.Contains(t, string(diffs[0].New), `"threshold":30`)
+	assert.Equal(t, DiffRemoved, diffs[1].Type)
+	assert.Equal(t, "resource:ghi#1", diffs[1].Key)
+	assert.Nil(t, diffs[1].New)
+	assert.Equal(t, DiffAdded, diffs[2].Type)
+	assert.Equal(t, "resource:jkl#1", diffs[2].Key)
+
+	diffs, err = Diff(ModuleSystem, []byte(`[{"metricType":0,"triggerCount":0.5}]`), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(diffs))
+	assert.Equal(t, "metricType:0#1", diffs[0].Key)
+
+	_, err = Diff(ModuleFlow, []byte(`[`), newSrc)
+	assert.NotNil(t, err)
+}
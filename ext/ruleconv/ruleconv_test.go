@@ -0,0 +1,183 @@
+package ruleconv
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/hotspot"
+	"github.com/alibaba/sentinel-golang/core/system"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlowRules(t *testing.T) {
+	src := `[
+		{"id":1,"app":"demo","resource":"abc","limitApp":"default","grade":1,"count":10,"strategy":0,"controlBehavior":0,"clusterMode":false},
+		{"id":2,"resource":"def","limitApp":"default","grade":1,"count":20,"strategy":1,"refResource":"abc","controlBehavior":3,"warmUpPeriodSec":10,"maxQueueingTimeMs":500}
+	]`
+	rules, err := FlowRulesFromJava([]byte(src))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(rules))
+	assert.Equal(t, &flow.Rule{ID: "1", Resource: "abc", Threshold: 10}, rules[0])
+	assert.Equal(t, &flow.Rule{
+		ID:                     "2",
+		Resource:               "def",
+		Threshold:              20,
+		RelationStrategy:       flow.AssociatedResource,
+		RefResource:            "abc",
+		TokenCalculateStrategy: flow.WarmUp,
+		ControlBehavior:        flow.Throttling,
+		WarmUpPeriodSec:        10,
+		MaxQueueingTimeMs:      500,
+	}, rules[1])
+
+	b, err := FlowRulesToJava(rules)
+	assert.Nil(t, err)
+	back, err := FlowRulesFromJava(b)
+	assert.Nil(t, err)
+	assert.Equal(t, rules, back)
+
+	b, err = FlowRulesToJava([]*flow.Rule{{ID: "abc", Resource: "abc", Threshold: 5, StatIntervalInMs: 500}})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `[{"resource":"abc","limitApp":"default","grade":1,"count":10,"strategy":0,"controlBehavior":0,"warmUpPeriodSec":0,"maxQueueingTimeMs":0,"clusterMode":false}]`, string(b))
+	_, err = FlowRulesToJava([]*flow.Rule{{Resource: "abc", Threshold: 1024, MetricType: flow.Throughput}})
+	assert.NotNil(t, err)
+	_, err = FlowRulesToJava([]*flow.Rule{{TargetTag: "tier=gold", Threshold: 10}})
+	assert.NotNil(t, err)
+	_, err = FlowRulesToJava([]*flow.Rule{{Resource: "abc", Threshold: 10, Category: base.CategoryRead}})
+	assert.NotNil(t, err)
+	_, err = FlowRulesToJava([]*flow.Rule{{Resource: "abc", Threshold: 10, SharedStatKey: "abc"}})
+	assert.NotNil(t, err)
+
+	for _, invalid := range []string{
+		`[{"resource":"abc","grade":0,"count":10}]`,
+		`[{"resource":"abc","grade":1,"count":10,"strategy":2}]`,
+		`[{"resource":"abc","grade":1,"count":10,"limitApp":"other"}]`,
+		`[{"resource":"abc","grade":1,"count":10,"clusterMode":true}]`,
+		`{}`,
+	} {
+		_, err := FlowRulesFromJava([]byte(invalid))
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestCircuitBreakerRules(t *testing.T) {
+	src := `[
+		{"resource":"abc","grade":0,"count":200,"timeWindow":10,"minRequestAmount":5,"slowRatioThreshold":0.5,"statIntervalMs":1000},
+		{"resource":"abc","grade":1,"count":0.3,"timeWindow":5,"minRequestAmount":10,"statIntervalMs":2000},
+		{"resource":"abc","grade":2,"count":50,"timeWindow":1}
+	]`
+	rules, err := CircuitBreakerRulesFromJava([]byte(src))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(rules))
+	assert.Equal(t, &circuitbreaker.Rule{
+		Resource:         "abc",
+		Strategy:         circuitbreaker.SlowRequestRatio,
+		RetryTimeoutMs:   10000,
+		MinRequestAmount: 5,
+		StatIntervalMs:   1000,
+		MaxAllowedRtMs:   200,
+		Threshold:        0.5,
+	}, rules[0])
+	assert.Equal(t, circuitbreaker.ErrorRatio, rules[1].Strategy)
+	assert.Equal(t, 0.3, rules[1].Threshold)
+	assert.Equal(t, circuitbreaker.ErrorCount, rules[2].Strategy)
+
+	b, err := CircuitBreakerRulesToJava(rules)
+	assert.Nil(t, err)
+	back, err := CircuitBreakerRulesFromJava(b)
+	assert.Nil(t, err)
+	assert.Equal(t, rules, back)
+
+	_, err = CircuitBreakerRulesFromJava([]byte(`[{"resource":"abc","grade":3}]`))
+	assert.NotNil(t, err)
+}
+
+func TestHotspotRules(t *testing.T) {
+	src := `[{"id":3,"resource":"abc","grade":1,"paramIdx":1,"count":100,"controlBehavior":0,"burstCount":5,"durationInSec":2,
+		"paramFlowItemList":[{"object":"vip","count":1000,"classType":"java.lang.String"},{"object":"7","count":0,"classType":"int"}]}]`
+	rules, err := HotspotRulesFromJava([]byte(src))
+	assert.Nil(t, err)
+	assert.Equal(t, []*hotspot.Rule{{
+		ID:              "3",
+		Resource:        "abc",
+		MetricType:      hotspot.QPS,
+		ControlBehavior: hotspot.Reject,
+		ParamIndex:      1,
+		Threshold:       100,
+		BurstCount:      5,
+		DurationInSec:   2,
+		SpecificItems: []hotspot.SpecificValue{
+			{ValKind: hotspot.KindString, ValStr: "vip", Threshold: 1000},
+			{ValKind: hotspot.KindInt, ValStr: "7", Threshold: 0},
+		},
+	}}, rules)
+
+	b, err := HotspotRulesToJava(rules)
+	assert.Nil(t, err)
+	back, err := HotspotRulesFromJava(b)
+	assert.Nil(t, err)
+	assert.Equal(t, rules, back)
+
+	_, err = HotspotRulesFromJava([]byte(`[{"resource":"abc","grade":1,"paramFlowItemList":[{"object":"a","classType":"java.util.Date"}]}]`))
+	assert.NotNil(t, err)
+}
+
+func TestSystemRules(t *testing.T) {
+	src := `[{"id":5,"highestSystemLoad":8,"highestCpuUsage":-1,"qps":-1,"avgRt":100,"maxThread":-1},{"maxThread":500}]`
+	rules, err := SystemRulesFromJava([]byte(src))
+	assert.Nil(t, err)
+	assert.Equal(t, []*system.Rule{
+		{ID: "5", MetricType: system.Load, TriggerCount: 8, Strategy: system.BBR},
+		{ID: "5", MetricType: system.AvgRT, TriggerCount: 100, Strategy: system.NoAdaptive},
+		{MetricType: system.Concurrency, TriggerCount: 500, Strategy: system.NoAdaptive},
+	}, rules)
+
+	b, err := SystemRulesToJava(rules)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `[{"id":5,"highestSystemLoad":8},{"id":5,"avgRt":100},{"maxThread":500}]`, string(b))
+}
+
+func TestConvert(t *testing.T) {
+	javaSrc := []byte(`[{"resource":"abc","limitApp":"default","grade":1,"count":10}]`)
+	goSrc, err := Convert(ModuleFlow, FormatJava, FormatGo, javaSrc)
+	assert.Nil(t, err)
+	rules := make([]*flow.Rule, 0)
+	assert.Nil(t, json.Unmarshal(goSrc, &rules))
+	assert.Equal(t, 1, len(rules))
+	assert.Equal(t, 10.0, rules[0].Threshold)
+
+	javaOut, err := Convert(ModuleFlow, FormatGo, FormatJava, goSrc)
+	assert.Nil(t, err)
+	assert.Contains(t, string(javaOut), `"grade":1`)
+
+	_, err = Convert(ModuleFlow, FormatJava, FormatJava, javaSrc)
+	assert.Nil(t, err)
+	_, err = Convert(ModuleSystem, FormatGo, FormatGo, []byte(`[{"metricType":0,"triggerCount":1}]`))
+	assert.Nil(t, err)
+
+	_, err = Convert("unknown", FormatJava, FormatGo, javaSrc)
+	assert.NotNil(t, err)
+	_, err = Convert(ModuleFlow, "yaml", FormatGo, javaSrc)
+	assert.NotNil(t, err)
+	_, err = Convert(ModuleFlow, FormatGo, FormatJava, []byte(`{`))
+	assert.NotNil(t, err)
+}
+
+func TestJavaParsers(t *testing.T) {
+	v, err := JavaFlowRulesParser(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, v)
+
+	v, err = JavaCircuitBreakerRulesParser([]byte(`[{"resource":"abc","grade":2,"count":5}]`))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(v.([]*circuitbreaker.Rule)))
+	v, err = JavaHotspotRulesParser([]byte(`[]`))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(v.([]*hotspot.Rule)))
+	v, err = JavaSystemRulesParser([]byte(`[{"qps":100}]`))
+	assert.Nil(t, err)
+	assert.Equal(t, system.InboundQPS, v.([]*system.Rule)[0].MetricType)
+}
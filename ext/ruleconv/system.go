@@ -0,0 +1,94 @@
+package ruleconv
+
+import (
+	"encoding/json"
+
+	"github.com/alibaba/sentinel-golang/core/system"
+	"github.com/alibaba/sentinel-golang/logging"
+	"github.com/pkg/errors"
+)
+
+// JavaSystemRule is the SystemRule of Sentinel Java. Each Java rule may carry multiple thresholds,
+// the absent or negative thresholds mean not set.
+type JavaSystemRule struct {
+	ID                *int64   `json:"id,omitempty"`
+	HighestSystemLoad *float64 `json:"highestSystemLoad,omitempty"`
+	HighestCpuUsage   *float64 `json:"highestCpuUsage,omitempty"`
+	Qps               *float64 `json:"qps,omitempty"`
+	AvgRt             *float64 `json:"avgRt,omitempty"`
+	MaxThread         *float64 `json:"maxThread,omitempty"`
+}
+
+// SystemRulesFromJava converts the JSON array of Java system rules to the Go system rules,
+// each threshold of the Java rule is converted to a Go rule.
+func SystemRulesFromJava(src []byte) ([]*system.Rule, error) {
+	javaRules := make([]*JavaSystemRule, 0)
+	if err := unmarshalJavaRules(src, &javaRules); err != nil {
+		return nil, err
+	}
+	rules := make([]*system.Rule, 0)
+	for _, jr := range javaRules {
+		id := javaIDToGo(jr.ID)
+		thresholds := []struct {
+			value    *float64
+			metric   system.MetricType
+			strategy system.AdaptiveStrategy
+		}{
+			// The system load is checked with BBR in Java.
+			{jr.HighestSystemLoad, system.Load, system.BBR},
+			{jr.HighestCpuUsage, system.CpuUsage, system.NoAdaptive},
+			{jr.Qps, system.InboundQPS, system.NoAdaptive},
+			{jr.AvgRt, system.AvgRT, system.NoAdaptive},
+			{jr.MaxThread, system.Concurrency, system.NoAdaptive},
+		}
+		for _, t := range thresholds {
+			if t.value == nil || *t.value < 0 {
+				continue
+			}
+			rules = append(rules, &system.Rule{
+				ID:           id,
+				MetricType:   t.metric,
+				TriggerCount: *t.value,
+				Strategy:     t.strategy,
+			})
+		}
+	}
+	return rules, nil
+}
+
+// SystemRulesToJava converts the Go system rules to the JSON array of Java system rules,
+// each Go rule is converted to a Java rule with single threshold.
+func SystemRulesToJava(rules []*system.Rule) ([]byte, error) {
+	javaRules := make([]*JavaSystemRule, 0, len(rules))
+	for _, r := range rules {
+		count := r.TriggerCount
+		jr := &JavaSystemRule{ID: goIDToJava(r.ID)}
+		switch r.MetricType {
+		case system.Load:
+			jr.HighestSystemLoad = &count
+		case system.CpuUsage:
+			jr.HighestCpuUsage = &count
+		case system.InboundQPS:
+			jr.Qps = &count
+		case system.AvgRT:
+			jr.AvgRt = &count
+		case system.Concurrency:
+			jr.MaxThread = &count
+		default:
+			return nil, errors.Errorf("fail to convert system rule: unsupported metric type: %s", r.MetricType)
+		}
+		if len(r.ExemptResources) > 0 {
+			logging.Warn("[ruleconv] The fields only available in Go are dropped on converting to Java", "rule", r)
+		}
+		javaRules = append(javaRules, jr)
+	}
+	return json.Marshal(javaRules)
+}
+
+// JavaSystemRulesParser parses the JSON array of Java system rules as []*system.Rule,
+// which could be used as the datasource.PropertyConverter of system rules.
+func JavaSystemRulesParser(src []byte) (interface{}, error) {
+	return javaParser(src, func(src []byte) (interface{}, error) {
+		return SystemRulesFromJava(src)
+	})
+}
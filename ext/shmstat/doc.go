@@ -0,0 +1,19 @@
+// Package shmstat provides the statistic backed by the shared memory, so that all the worker processes
+// on the host (e.g. the prefork servers of fasthttp) enforce a single per-host limit rather than N independent ones.
+//
+// The statistic of each key is a sliding window kept in a memory-mapped file, which is updated by the atomic
+// operations of all the processes mapping it. The files are placed in a tmpfs directory (e.g. /dev/shm) to avoid
+// any disk I/O. Set the factory before loading the flow rules with SharedStatKey:
+//
+//	flow.SetSharedStatFactory(shmstat.NewFactory("/dev/shm").SharedStat)
+//	flow.LoadRules([]*flow.Rule{{
+//		Resource:               "GET:/api/orders",
+//		TokenCalculateStrategy: flow.Direct,
+//		ControlBehavior:        flow.Reject,
+//		Threshold:              1000, // per host
+//		SharedStatKey:          "orders",
+//	}})
+//
+// The bucket rotation is not locked across the processes, so a few requests counted right at the rotation
+// may be lost, which is negligible for the rate limiting. The shared memory is only supported on Linux and macOS.
+package shmstat
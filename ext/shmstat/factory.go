@@ -0,0 +1,46 @@
+package shmstat
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	"github.com/alibaba/sentinel-golang/core/flow"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// Factory creates the shared memory statistics in the directory, which are cached per key,
+// so that reloading the rules doesn't map the files again.
+type Factory struct {
+	dir string
+
+	mux     sync.Mutex
+	metrics map[string]*Metric
+}
+
+// NewFactory creates the Factory of the statistic files in the directory, which should be a tmpfs (e.g. /dev/shm).
+func NewFactory(dir string) *Factory {
+	return &Factory{
+		dir:     dir,
+		metrics: make(map[string]*Metric),
+	}
+}
+
+// SharedStat returns the shared statistic of the key, which implements flow.SharedStatFactory.
+func (f *Factory) SharedStat(key string, sampleCount, intervalInMs uint32) (flow.SharedStat, error) {
+	name := fmt.Sprintf("sentinel-%s-%d-%d.stat", unsafeFileChars.ReplaceAllString(key, "_"), sampleCount, intervalInMs)
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	if m, ok := f.metrics[name]; ok {
+		return m, nil
+	}
+	m, err := NewMetric(filepath.Join(f.dir, name), sampleCount, intervalInMs)
+	if err != nil {
+		return nil, err
+	}
+	f.metrics[name] = m
+	return m, nil
+}
@@ -0,0 +1,223 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package shmstat
+
+import (
+	"os"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	metricMagic = int64(0x53454e54494e454c) // "SENTINEL"
+	headerSize  = 64
+	// bucketSize is the size of bucket: start time, counters of the events and min RT.
+	bucketSize = 8 * (2 + int(base.MetricEventTotal))
+)
+
+// Metric is the sliding window statistic in the shared memory, which implements base.ReadStat and base.WriteStat.
+type Metric struct {
+	data         []byte
+	sampleCount  uint32
+	intervalInMs uint32
+	bucketLength uint32
+}
+
+// NewMetric maps the statistic file of the path, which is created if absent. The processes mapping the same file
+// must use the same sample count and interval, or the error is returned.
+func NewMetric(path string, sampleCount, intervalInMs uint32) (*Metric, error) {
+	if sampleCount == 0 || intervalInMs == 0 || intervalInMs%sampleCount != 0 {
+		return nil, errors.Errorf("invalid sample count %d or interval %d", sampleCount, intervalInMs)
+	}
+	size := headerSize + int(sampleCount)*bucketSize
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to open the shared statistic file: %s", path)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to stat the shared statistic file: %s", path)
+	}
+	if info.Size() < int64(size) {
+		// the extended part is filled with zero, so the concurrent extending of the processes is harmless
+		if err := f.Truncate(int64(size)); err != nil {
+			return nil, errors.Wrapf(err, "fail to extend the shared statistic file: %s", path)
+		}
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to map the shared statistic file: %s", path)
+	}
+	m := &Metric{
+		data:         data,
+		sampleCount:  sampleCount,
+		intervalInMs: intervalInMs,
+		bucketLength: intervalInMs / sampleCount,
+	}
+	if err := m.initHeader(); err != nil {
+		_ = m.Close()
+		return nil, errors.Wrapf(err, "invalid shared statistic file: %s", path)
+	}
+	return m, nil
+}
+
+func (m *Metric) initHeader() error {
+	params := int64(m.sampleCount)<<32 | int64(m.intervalInMs)
+	paramsPtr := m.int64At(8)
+	if atomic.CompareAndSwapInt64(m.int64At(0), 0, metricMagic) {
+		atomic.StoreInt64(paramsPtr, params)
+		return nil
+	}
+	if atomic.LoadInt64(m.int64At(0)) != metricMagic {
+		return errors.New("unknown file format")
+	}
+	// wait for the params written by the process initializing the header
+	for i := 0; i < 1000 && atomic.LoadInt64(paramsPtr) == 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if p := atomic.LoadInt64(paramsPtr); p != params {
+		return errors.Errorf("mismatched sample count %d and interval %d", uint32(p>>32), uint32(p))
+	}
+	return nil
+}
+
+// Close unmaps the shared memory, the Metric must not be used any more.
+func (m *Metric) Close() error {
+	return syscall.Munmap(m.data)
+}
+
+func (m *Metric) int64At(offset int) *int64 {
+	return (*int64)(unsafe.Pointer(&m.data[offset]))
+}
+
+func (m *Metric) bucketOffset(idx uint64) int {
+	return headerSize + int(idx)*bucketSize
+}
+
+func (m *Metric) startOf(offset int) *int64 {
+	return m.int64At(offset)
+}
+
+func (m *Metric) counterOf(offset int, event base.MetricEvent) *int64 {
+	return m.int64At(offset + 8 + 8*int(event))
+}
+
+func (m *Metric) minRtOf(offset int) *int64 {
+	return m.int64At(offset + 8 + 8*int(base.MetricEventTotal))
+}
+
+// currentBucket returns the offset of the bucket of the time, which is reset if it's deprecated.
+// It returns -1 if the bucket is newer than the time, e.g. the clocks of processes are not in sync.
+func (m *Metric) currentBucket(now uint64) int {
+	bucketLength := uint64(m.bucketLength)
+	start := int64(now - now%bucketLength)
+	offset := m.bucketOffset(now / bucketLength % uint64(m.sampleCount))
+	for {
+		old := atomic.LoadInt64(m.startOf(offset))
+		if old == start {
+			return offset
+		}
+		if old > start {
+			return -1
+		}
+		if atomic.CompareAndSwapInt64(m.startOf(offset), old, start) {
+			for e := base.MetricEvent(0); e < base.MetricEventTotal; e++ {
+				atomic.StoreInt64(m.counterOf(offset, e), 0)
+			}
+			atomic.StoreInt64(m.minRtOf(offset), base.DefaultStatisticMaxRt)
+			return offset
+		}
+	}
+}
+
+func (m *Metric) AddCount(event base.MetricEvent, count int64) {
+	m.addCountWithTime(util.CurrentTimeMillis(), event, count)
+}
+
+func (m *Metric) addCountWithTime(now uint64, event base.MetricEvent, count int64) {
+	if event < 0 || event >= base.MetricEventTotal {
+		return
+	}
+	offset := m.currentBucket(now)
+	if offset < 0 {
+		return
+	}
+	atomic.AddInt64(m.counterOf(offset, event), count)
+	if event != base.MetricEventRt {
+		return
+	}
+	minRtPtr := m.minRtOf(offset)
+	for {
+		minRt := atomic.LoadInt64(minRtPtr)
+		if count >= minRt || atomic.CompareAndSwapInt64(minRtPtr, minRt, count) {
+			return
+		}
+	}
+}
+
+// forEachValidBucket calls the fn with the offsets of the buckets within the interval before the time.
+func (m *Metric) forEachValidBucket(now uint64, fn func(offset int)) {
+	bucketLength := uint64(m.bucketLength)
+	current := int64(now - now%bucketLength)
+	oldest := current - int64(m.intervalInMs) + int64(bucketLength)
+	for i := uint64(0); i < uint64(m.sampleCount); i++ {
+		offset := m.bucketOffset(i)
+		start := atomic.LoadInt64(m.startOf(offset))
+		if start >= oldest && start <= current {
+			fn(offset)
+		}
+	}
+}
+
+func (m *Metric) GetSum(event base.MetricEvent) int64 {
+	return m.getSumWithTime(util.CurrentTimeMillis(), event)
+}
+
+func (m *Metric) getSumWithTime(now uint64, event base.MetricEvent) int64 {
+	if event < 0 || event >= base.MetricEventTotal {
+		return 0
+	}
+	ret := int64(0)
+	m.forEachValidBucket(now, func(offset int) {
+		ret += atomic.LoadInt64(m.counterOf(offset, event))
+	})
+	return ret
+}
+
+func (m *Metric) GetQPS(event base.MetricEvent) float64 {
+	return float64(m.GetSum(event)) / (float64(m.intervalInMs) / 1000.0)
+}
+
+func (m *Metric) GetPreviousQPS(event base.MetricEvent) float64 {
+	return float64(m.getSumWithTime(util.CurrentTimeMillis()-uint64(m.bucketLength), event)) / (float64(m.intervalInMs) / 1000.0)
+}
+
+func (m *Metric) MinRT() float64 {
+	minRt := base.DefaultStatisticMaxRt
+	m.forEachValidBucket(util.CurrentTimeMillis(), func(offset int) {
+		if v := atomic.LoadInt64(m.minRtOf(offset)); v < minRt {
+			minRt = v
+		}
+	})
+	if minRt < 1 {
+		minRt = 1
+	}
+	return float64(minRt)
+}
+
+func (m *Metric) AvgRT() float64 {
+	now := util.CurrentTimeMillis()
+	complete := m.getSumWithTime(now, base.MetricEventComplete)
+	if complete <= 0 {
+		return 0
+	}
+	return float64(m.getSumWithTime(now, base.MetricEventRt)) / float64(complete)
+}
@@ -0,0 +1,98 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package shmstat
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/stat"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetric(t *testing.T) {
+	dir, err := ioutil.TempDir("", "shmstat")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.stat")
+
+	// the metrics mapping the same file act as the processes sharing the statistic
+	m1, err := NewMetric(path, 2, 1000)
+	assert.NoError(t, err)
+	defer m1.Close()
+	m2, err := NewMetric(path, 2, 1000)
+	assert.NoError(t, err)
+	defer m2.Close()
+	_, err = NewMetric(path, 4, 1000)
+	assert.Error(t, err)
+
+	now := util.CurrentTimeMillis()
+	now = now - now%500
+	m1.addCountWithTime(now, base.MetricEventPass, 3)
+	m2.addCountWithTime(now+100, base.MetricEventPass, 4)
+	m2.addCountWithTime(now+600, base.MetricEventPass, 5)
+	m1.addCountWithTime(now+600, base.MetricEventRt, 20)
+	m2.addCountWithTime(now+600, base.MetricEventRt, 10)
+	assert.Equal(t, int64(12), m1.getSumWithTime(now+600, base.MetricEventPass))
+	assert.Equal(t, int64(12), m2.getSumWithTime(now+600, base.MetricEventPass))
+	assert.Equal(t, int64(30), m2.getSumWithTime(now+600, base.MetricEventRt))
+	// the oldest bucket is out of the window
+	assert.Equal(t, int64(5), m1.getSumWithTime(now+1100, base.MetricEventPass))
+	// the deprecated bucket is reset
+	m2.addCountWithTime(now+1100, base.MetricEventPass, 1)
+	assert.Equal(t, int64(6), m1.getSumWithTime(now+1100, base.MetricEventPass))
+	// the time behind the bucket is ignored
+	m1.addCountWithTime(now, base.MetricEventPass, 100)
+	assert.Equal(t, int64(6), m1.getSumWithTime(now+1100, base.MetricEventPass))
+}
+
+func TestFactoryWithFlowRule(t *testing.T) {
+	dir, err := ioutil.TempDir("", "shmstat")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	f := NewFactory(dir)
+	s1, err := f.SharedStat("a/b", 2, 1000)
+	assert.NoError(t, err)
+	s2, err := f.SharedStat("a/b", 2, 1000)
+	assert.NoError(t, err)
+	assert.True(t, s1 == s2)
+	_, err = os.Stat(filepath.Join(dir, "sentinel-a_b-2-1000.stat"))
+	assert.NoError(t, err)
+
+	flow.SetSharedStatFactory(f.SharedStat)
+	defer flow.SetSharedStatFactory(nil)
+	_, err = flow.LoadRules([]*flow.Rule{{
+		Resource:               "shm-res",
+		TokenCalculateStrategy: flow.Direct,
+		ControlBehavior:        flow.Reject,
+		Threshold:              10,
+		StatIntervalInMs:       60000,
+		SharedStatKey:          "shm-res",
+	}})
+	assert.NoError(t, err)
+	defer flow.ClearRules()
+
+	// the passed requests of the other process on the host
+	other, err := NewMetric(filepath.Join(dir, "sentinel-shm-res-120-60000.stat"), 120, 60000)
+	assert.NoError(t, err)
+	defer other.Close()
+	other.AddCount(base.MetricEventPass, 10)
+
+	tc := flow.TrafficControllersFor("shm-res")[0]
+	assert.Equal(t, int64(10), tc.CurrentPassCount())
+	slot := &flow.Slot{}
+	ctx := &base.EntryContext{
+		Resource: base.NewResourceWrapper("shm-res", base.ResTypeCommon, base.Inbound),
+		StatNode: stat.GetOrCreateResourceNode("shm-res", base.ResTypeCommon),
+		Input:    &base.SentinelInput{AcquireCount: 1},
+	}
+	r := slot.Check(ctx)
+	assert.True(t, r != nil && r.Status() == base.ResultStatusBlocked)
+}
@@ -0,0 +1,44 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package shmstat
+
+import (
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/pkg/errors"
+)
+
+// Metric is not supported on current platform.
+type Metric struct{}
+
+// NewMetric always returns the error, as the shared memory is not supported on current platform.
+func NewMetric(path string, sampleCount, intervalInMs uint32) (*Metric, error) {
+	return nil, errors.New("shared memory statistic is not supported on current platform")
+}
+
+func (m *Metric) Close() error {
+	return nil
+}
+
+func (m *Metric) AddCount(event base.MetricEvent, count int64) {
+}
+
+func (m *Metric) GetSum(event base.MetricEvent) int64 {
+	return 0
+}
+
+func (m *Metric) GetQPS(event base.MetricEvent) float64 {
+	return 0
+}
+
+func (m *Metric) GetPreviousQPS(event base.MetricEvent) float64 {
+	return 0
+}
+
+func (m *Metric) MinRT() float64 {
+	return float64(base.DefaultStatisticMaxRt)
+}
+
+func (m *Metric) AvgRT() float64 {
+	return 0
+}
@@ -0,0 +1,31 @@
+// Package limiter provides a lightweight rate limiter facade backed by the flow rules.
+//
+// Unlike api.Entry, the limiter skips the slot chain and the resource statistic nodes. Each flow rule
+// of the resource is converted to a token bucket, the rate of which is Threshold per StatIntervalInMs
+// (1 second by default):
+//
+//  1. For Reject control behavior, the bucket allows bursts of up to Threshold tokens.
+//  2. For Throttling control behavior, the requests are paced evenly and may wait up to MaxQueueingTimeMs.
+//
+// WarmUp token calculate strategy is regarded as Direct, and rules with AssociatedResource relation strategy
+// are ignored, as the limiter doesn't hold any resource statistic.
+//
+// Here is the example code to use the limiter:
+//
+//	_, err := flow.LoadRules([]*flow.Rule{
+//	    {
+//	        Resource:               "some-hot-loop",
+//	        TokenCalculateStrategy: flow.Direct,
+//	        ControlBehavior:        flow.Reject,
+//	        Threshold:              1000,
+//	    },
+//	})
+//	...
+//	if limiter.Allow("some-hot-loop") {
+//	    // Passed, wrap the logic here.
+//	}
+//
+//	if err := limiter.Wait(ctx, "some-hot-loop"); err == nil {
+//	    // Passed after waiting, wrap the logic here.
+//	}
+package limiter
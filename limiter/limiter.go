@@ -0,0 +1,167 @@
+package limiter
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrLimited indicates that the request couldn't pass within the allowed waiting time.
+	ErrLimited = errors.New("rate limited")
+
+	bucketsMap = make(map[string]*resourceBuckets)
+	bucketsMux = new(sync.RWMutex)
+)
+
+// resourceBuckets is the token buckets of the flow rules of a resource, built at the rules version.
+type resourceBuckets struct {
+	version uint64
+	buckets []*tokenBucket
+	// ruleKeys is the key of the rule of each bucket, see ruleKeyOf.
+	ruleKeys []string
+}
+
+// Allow reports whether a request of the resource may pass now according to the flow rules.
+func Allow(resource string) bool {
+	return AllowN(resource, 1)
+}
+
+// AllowN reports whether n requests of the resource may pass now according to the flow rules.
+// For Throttling rules, AllowN may block for at most MaxQueueingTimeMs to pace the requests.
+func AllowN(resource string, n uint32) bool {
+	buckets := getBucketsOf(resource)
+	if len(buckets) == 0 {
+		return true
+	}
+	waitNs, ok := reserve(buckets, n, -1)
+	if !ok {
+		return false
+	}
+	if waitNs > 0 {
+		time.Sleep(time.Duration(waitNs))
+	}
+	return true
+}
+
+// Wait blocks until a request of the resource may pass according to the flow rules,
+// or returns an error if the request couldn't pass before ctx is done.
+func Wait(ctx context.Context, resource string) error {
+	return WaitN(ctx, resource, 1)
+}
+
+// WaitN blocks until n requests of the resource may pass according to the flow rules,
+// or returns an error if the requests couldn't pass before ctx is done.
+func WaitN(ctx context.Context, resource string, n uint32) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	buckets := getBucketsOf(resource)
+	if len(buckets) == 0 {
+		return nil
+	}
+	maxWaitNs := int64(math.MaxInt64)
+	if deadline, ok := ctx.Deadline(); ok {
+		maxWaitNs = int64(time.Until(deadline))
+	}
+	waitNs, ok := reserve(buckets, n, maxWaitNs)
+	if !ok {
+		return ErrLimited
+	}
+	if waitNs <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(time.Duration(waitNs))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		for _, b := range buckets {
+			b.cancel(n)
+		}
+		return ctx.Err()
+	}
+}
+
+// reserve takes n tokens from all the buckets, and returns the longest waiting duration.
+// If maxWaitNs is negative, the waiting time of each bucket is limited by its own max queueing time.
+func reserve(buckets []*tokenBucket, n uint32, maxWaitNs int64) (int64, bool) {
+	now := time.Now().UnixNano()
+	var waitNs int64
+	for i, b := range buckets {
+		limit := maxWaitNs
+		if limit < 0 {
+			limit = b.maxQueueingTimeNs
+		}
+		w, ok := b.reserve(n, now, limit)
+		if !ok {
+			// Give back the tokens taken from the previous buckets.
+			for _, taken := range buckets[:i] {
+				taken.cancel(n)
+			}
+			return 0, false
+		}
+		if w > waitNs {
+			waitNs = w
+		}
+	}
+	return waitNs, true
+}
+
+func getBucketsOf(resource string) []*tokenBucket {
+	version := flow.RulesVersion()
+	bucketsMux.RLock()
+	rb, exist := bucketsMap[resource]
+	bucketsMux.RUnlock()
+	if exist && rb.version == version {
+		return rb.buckets
+	}
+
+	bucketsMux.Lock()
+	defer bucketsMux.Unlock()
+	old := bucketsMap[resource]
+	if old != nil && old.version == version {
+		return old.buckets
+	}
+	// The flow rules were updated, so rebuild the buckets of the resource, while the buckets of
+	// the unchanged rules are kept with their tokens.
+	reusable := make(map[string][]*tokenBucket)
+	if old != nil {
+		for i, key := range old.ruleKeys {
+			reusable[key] = append(reusable[key], old.buckets[i])
+		}
+	}
+	rules := flow.GetRulesOfResource(resource)
+	rb = &resourceBuckets{
+		version:  version,
+		buckets:  make([]*tokenBucket, 0, len(rules)),
+		ruleKeys: make([]string, 0, len(rules)),
+	}
+	for i := range rules {
+		if rules[i].RelationStrategy == flow.AssociatedResource {
+			continue
+		}
+		key := ruleKeyOf(&rules[i])
+		var b *tokenBucket
+		if kept := reusable[key]; len(kept) > 0 {
+			// Each old bucket is kept at most once, so that the identical rules still have their own buckets.
+			b, reusable[key] = kept[0], kept[1:]
+		} else {
+			b = newTokenBucket(&rules[i])
+		}
+		rb.buckets = append(rb.buckets, b)
+		rb.ruleKeys = append(rb.ruleKeys, key)
+	}
+	bucketsMap[resource] = rb
+	return rb.buckets
+}
+
+// ruleKeyOf returns the identity of the rule, which covers all the fields of the rule.
+func ruleKeyOf(rule *flow.Rule) string {
+	return rule.String()
+}
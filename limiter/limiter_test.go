@@ -0,0 +1,129 @@
+package limiter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllow(t *testing.T) {
+	defer flow.ClearRules()
+
+	assert.True(t, Allow("abc"))
+
+	_, err := flow.LoadRules([]*flow.Rule{
+		{
+			Resource:               "abc",
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			Threshold:              10,
+		},
+	})
+	assert.Nil(t, err)
+
+	passed := 0
+	for i := 0; i < 20; i++ {
+		if Allow("abc") {
+			passed++
+		}
+	}
+	assert.Equal(t, 10, passed)
+
+	assert.Nil(t, flow.ClearRules())
+	assert.True(t, Allow("abc"))
+}
+
+func TestAllowThrottling(t *testing.T) {
+	defer flow.ClearRules()
+
+	_, err := flow.LoadRules([]*flow.Rule{
+		{
+			Resource:               "abc",
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Throttling,
+			Threshold:              100,
+			MaxQueueingTimeMs:      25,
+		},
+	})
+	assert.Nil(t, err)
+
+	passed := 0
+	for i := 0; i < 10; i++ {
+		if Allow("abc") {
+			passed++
+		}
+	}
+	// The first request passes immediately, and the rest are paced every 10ms.
+	assert.True(t, passed >= 3 && passed <= 10)
+}
+
+func TestWait(t *testing.T) {
+	defer flow.ClearRules()
+
+	_, err := flow.LoadRules([]*flow.Rule{
+		{
+			Resource:               "abc",
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			Threshold:              1,
+			StatIntervalInMs:       100,
+		},
+	})
+	assert.Nil(t, err)
+
+	assert.Nil(t, Wait(context.Background(), "abc"))
+	start := time.Now()
+	assert.Nil(t, Wait(context.Background(), "abc"))
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, ErrLimited, Wait(ctx, "abc"))
+}
+
+func TestAllow_RulesUpdated(t *testing.T) {
+	defer flow.ClearRules()
+
+	abcRule := &flow.Rule{
+		Resource:               "abc",
+		TokenCalculateStrategy: flow.Direct,
+		ControlBehavior:        flow.Reject,
+		Threshold:              10,
+		StatIntervalInMs:       100000,
+	}
+	_, err := flow.LoadRules([]*flow.Rule{abcRule})
+	assert.Nil(t, err)
+	for i := 0; i < 10; i++ {
+		assert.True(t, Allow("abc"))
+	}
+	assert.False(t, Allow("abc"))
+
+	// The bucket of the unchanged rule is kept when the rules of other resources are updated.
+	_, err = flow.LoadRules([]*flow.Rule{
+		abcRule,
+		{
+			Resource:               "def",
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			Threshold:              10,
+		},
+	})
+	assert.Nil(t, err)
+	assert.False(t, Allow("abc"))
+
+	// The bucket of the changed rule is rebuilt.
+	_, err = flow.LoadRules([]*flow.Rule{
+		{
+			Resource:               "abc",
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			Threshold:              20,
+			StatIntervalInMs:       100000,
+		},
+	})
+	assert.Nil(t, err)
+	assert.True(t, Allow("abc"))
+}
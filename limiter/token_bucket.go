@@ -0,0 +1,78 @@
+package limiter
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/flow"
+)
+
+// tokenBucket is a token bucket converted from the flow rule.
+type tokenBucket struct {
+	mux sync.Mutex
+	// ratePerNs is the amount of tokens generated per nanosecond.
+	ratePerNs float64
+	burst     float64
+	// maxQueueingTimeNs is the max waiting time of Allow, only available for Throttling.
+	maxQueueingTimeNs int64
+
+	tokens   float64
+	lastTime int64
+}
+
+func newTokenBucket(rule *flow.Rule) *tokenBucket {
+	intervalMs := rule.StatIntervalInMs
+	if intervalMs == 0 {
+		intervalMs = 1000
+	}
+	b := &tokenBucket{
+		ratePerNs: rule.Threshold / float64(time.Duration(intervalMs)*time.Millisecond),
+		burst:     rule.Threshold,
+	}
+	if rule.ControlBehavior == flow.Throttling {
+		// Pace the requests evenly.
+		b.burst = math.Min(1, rule.Threshold)
+		b.maxQueueingTimeNs = int64(time.Duration(rule.MaxQueueingTimeMs) * time.Millisecond)
+	}
+	b.tokens = b.burst
+	return b
+}
+
+// reserve takes n tokens from the bucket at now (in nanoseconds), and returns the duration to wait
+// until the tokens are available. If the tokens couldn't be available within maxWaitNs, it returns false
+// and nothing is taken.
+func (b *tokenBucket) reserve(n uint32, now int64, maxWaitNs int64) (int64, bool) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	if b.ratePerNs <= 0 {
+		return 0, false
+	}
+	tokens := b.tokens
+	if now > b.lastTime {
+		tokens = math.Min(b.burst, tokens+float64(now-b.lastTime)*b.ratePerNs)
+	} else {
+		now = b.lastTime
+	}
+	tokens -= float64(n)
+
+	var waitNs int64
+	if tokens < 0 {
+		waitNs = int64(math.Ceil(-tokens / b.ratePerNs))
+	}
+	if waitNs > maxWaitNs {
+		return 0, false
+	}
+	b.tokens = tokens
+	b.lastTime = now
+	return waitNs, true
+}
+
+// cancel gives back n tokens taken by a successful reservation.
+func (b *tokenBucket) cancel(n uint32) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	b.tokens = math.Min(b.burst, b.tokens+float64(n))
+}
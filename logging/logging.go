@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"runtime"
@@ -59,6 +60,13 @@ func NewConsoleLogger() Logger {
 	}
 }
 
+// NewWriterLogger creates the logger writing to the writer, e.g. the test output or the log collector.
+func NewWriterLogger(w io.Writer) Logger {
+	return &DefaultLogger{
+		log: log.New(w, "", 0),
+	}
+}
+
 // outputFile is the full path(absolute path)
 func NewSimpleFileLogger(filepath string) (Logger, error) {
 	logFile, err := os.OpenFile(filepath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0777)
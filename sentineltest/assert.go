@@ -0,0 +1,36 @@
+package sentineltest
+
+import (
+	"math"
+	"testing"
+)
+
+// AssertBlockRatio asserts that the block ratio of the result is within delta of the expected ratio.
+func AssertBlockRatio(t testing.TB, r *Result, expected, delta float64) bool {
+	t.Helper()
+	if ratio := r.BlockRatio(); math.Abs(ratio-expected) > delta {
+		t.Errorf("block ratio %.4f is not within %.4f of %.4f (total: %d, blocked: %d)", ratio, delta, expected, r.Total, r.Blocked)
+		return false
+	}
+	return true
+}
+
+// AssertBlockRatioBetween asserts that the block ratio of the result is in [min, max].
+func AssertBlockRatioBetween(t testing.TB, r *Result, min, max float64) bool {
+	t.Helper()
+	if ratio := r.BlockRatio(); ratio < min || ratio > max {
+		t.Errorf("block ratio %.4f is not in [%.4f, %.4f] (total: %d, blocked: %d)", ratio, min, max, r.Total, r.Blocked)
+		return false
+	}
+	return true
+}
+
+// AssertNoBlock asserts that none of the requests is blocked.
+func AssertNoBlock(t testing.TB, r *Result) bool {
+	t.Helper()
+	if r.Blocked > 0 {
+		t.Errorf("%d of %d requests are blocked: %v", r.Blocked, r.Total, r.BlockedByType)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+package sentineltest
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+// FakeClock is the virtual time source of Sentinel, which only moves forward when told to.
+type FakeClock struct {
+	nowNs uint64
+}
+
+// NewFakeClock creates the FakeClock starting from the given Unix timestamp in milliseconds.
+func NewFakeClock(startMs uint64) *FakeClock {
+	return &FakeClock{nowNs: startMs * util.UnixTimeUnitOffset}
+}
+
+// InstallFakeClock replaces the time source of Sentinel with a FakeClock starting from the beginning of the next
+// second of the real time, so that the buckets of the statistics are aligned. Reset restores the real clock.
+func InstallFakeClock() *FakeClock {
+	now := util.CurrentTimeMillis()
+	c := NewFakeClock(now - now%1000 + 1000)
+	util.SetClock(c)
+	return c
+}
+
+func (c *FakeClock) CurrentTimeMillis() uint64 {
+	return atomic.LoadUint64(&c.nowNs) / util.UnixTimeUnitOffset
+}
+
+func (c *FakeClock) CurrentTimeNano() uint64 {
+	return atomic.LoadUint64(&c.nowNs)
+}
+
+// Advance moves the clock forward by d, the negative durations are ignored.
+func (c *FakeClock) Advance(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	atomic.AddUint64(&c.nowNs, uint64(d))
+}
+
+// Set sets the clock to the given Unix timestamp in milliseconds, which must not be earlier than the current time.
+func (c *FakeClock) Set(ms uint64) {
+	c.advanceTo(ms * util.UnixTimeUnitOffset)
+}
+
+// advanceTo moves the clock forward to the given Unix timestamp in nanoseconds.
+func (c *FakeClock) advanceTo(ns uint64) {
+	for {
+		cur := atomic.LoadUint64(&c.nowNs)
+		if ns <= cur || atomic.CompareAndSwapUint64(&c.nowNs, cur, ns) {
+			return
+		}
+	}
+}
@@ -0,0 +1,28 @@
+// Package sentineltest provides the utilities for testing the rule configurations, so that the users could write
+// reliable integration tests of the protection (e.g. "the order API blocks about 80% of a 50 QPS burst").
+//
+// The utilities include:
+//
+//  1. FakeClock, the virtual time source of Sentinel (see util.SetClock), which makes the tests fast and deterministic;
+//  2. the traffic generators with the configurable arrival distributions (ConstantRate, PoissonRate, Bursts),
+//     which feed the entries under the FakeClock;
+//  3. the assertions over the block ratios of the traffic;
+//  4. Reset, which restores the state of all the global managers between tests (see api.ResetForTesting).
+//
+// Here is the example:
+//
+//	func TestOrderFlowRule(t *testing.T) {
+//	    defer sentineltest.Reset()
+//	    clock := sentineltest.InstallFakeClock()
+//
+//	    _, _ = flow.LoadRules([]*flow.Rule{{Resource: "order", Threshold: 10, ControlBehavior: flow.Reject}})
+//	    result := sentineltest.Run(clock, sentineltest.Traffic{
+//	        Resource: "order",
+//	        Arrival:  sentineltest.ConstantRate(50),
+//	        Duration: 10 * time.Second,
+//	    })
+//	    sentineltest.AssertBlockRatio(t, result, 0.8, 0.05)
+//	}
+//
+// The utilities replace the process-global state of Sentinel, so the tests using them must not run in parallel.
+package sentineltest
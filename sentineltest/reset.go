@@ -0,0 +1,11 @@
+package sentineltest
+
+import (
+	sentinel "github.com/alibaba/sentinel-golang/api"
+)
+
+// Reset restores the process-global state of Sentinel (see api.ResetForTesting), including the rules,
+// the statistics and the clock, so that the state doesn't leak to the following tests.
+func Reset() {
+	sentinel.ResetForTesting()
+}
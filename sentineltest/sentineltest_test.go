@@ -0,0 +1,93 @@
+//go:build !sentinel_noop
+// +build !sentinel_noop
+
+package sentineltest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/isolation"
+	"github.com/alibaba/sentinel-golang/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeClock(t *testing.T) {
+	c := NewFakeClock(1000)
+	c.Advance(1500 * time.Millisecond)
+	assert.Equal(t, uint64(2500), c.CurrentTimeMillis())
+	c.Set(2000)
+	assert.Equal(t, uint64(2500), c.CurrentTimeMillis(), "the clock never moves backwards")
+	c.Set(3000)
+	assert.Equal(t, uint64(3000*util.UnixTimeUnitOffset), c.CurrentTimeNano())
+}
+
+func TestArrivals(t *testing.T) {
+	assert.Equal(t, 10*time.Millisecond, ConstantRate(100).NextInterval())
+
+	b := Bursts(3, time.Second)
+	intervals := make([]time.Duration, 0)
+	for i := 0; i < 6; i++ {
+		intervals = append(intervals, b.NextInterval())
+	}
+	assert.Equal(t, []time.Duration{0, 0, 0, time.Second, 0, 0}, intervals)
+
+	p1, p2 := PoissonRate(100, 42), PoissonRate(100, 42)
+	var sum time.Duration
+	for i := 0; i < 1000; i++ {
+		d := p1.NextInterval()
+		assert.Equal(t, d, p2.NextInterval())
+		sum += d
+	}
+	assert.InDelta(t, float64(10*time.Second), float64(sum), float64(2*time.Second))
+}
+
+func TestRun_FlowRule(t *testing.T) {
+	defer Reset()
+	clock := InstallFakeClock()
+
+	_, err := flow.LoadRules([]*flow.Rule{
+		{Resource: "sentineltest-flow", Threshold: 10, StatIntervalInMs: 1000},
+	})
+	assert.Nil(t, err)
+	start := clock.CurrentTimeMillis()
+	r := Run(clock, Traffic{
+		Resource: "sentineltest-flow",
+		Arrival:  ConstantRate(50),
+		Duration: 10 * time.Second,
+	})
+	assert.Equal(t, start+10000, clock.CurrentTimeMillis())
+	assert.Equal(t, uint64(500), r.Total)
+	assert.Equal(t, uint64(100), r.Passed)
+	assert.Equal(t, uint64(400), r.BlockedByType[base.BlockTypeFlow])
+	AssertBlockRatio(t, r, 0.8, 0.01)
+	AssertBlockRatioBetween(t, r, 0.75, 0.85)
+}
+
+func TestRun_Concurrency(t *testing.T) {
+	defer Reset()
+	clock := InstallFakeClock()
+
+	_, err := isolation.LoadRules([]*isolation.Rule{
+		{Resource: "sentineltest-isolation", MetricType: isolation.Concurrency, Threshold: 5},
+	})
+	assert.Nil(t, err)
+	// 10 concurrent requests on average
+	r := Run(clock, Traffic{
+		Resource: "sentineltest-isolation",
+		Arrival:  ConstantRate(100),
+		Duration: 5 * time.Second,
+		Rt:       100 * time.Millisecond,
+	})
+	AssertBlockRatio(t, r, 0.5, 0.02)
+
+	assert.Nil(t, isolation.ClearRules())
+	AssertNoBlock(t, Run(clock, Traffic{
+		Resource: "sentineltest-isolation",
+		Arrival:  ConstantRate(100),
+		Duration: time.Second,
+		Rt:       100 * time.Millisecond,
+	}))
+}
@@ -0,0 +1,165 @@
+package sentineltest
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	sentinel "github.com/alibaba/sentinel-golang/api"
+	"github.com/alibaba/sentinel-golang/core/base"
+)
+
+// Arrival generates the intervals between the successive requests of the traffic.
+type Arrival interface {
+	// NextInterval returns the interval between the previous request and the next one.
+	NextInterval() time.Duration
+}
+
+type constantArrival struct {
+	interval time.Duration
+}
+
+func (a *constantArrival) NextInterval() time.Duration {
+	return a.interval
+}
+
+// ConstantRate returns the Arrival of the requests evenly spaced at the given QPS.
+func ConstantRate(qps float64) Arrival {
+	return &constantArrival{interval: intervalOf(qps)}
+}
+
+type poissonArrival struct {
+	qps float64
+	rnd *rand.Rand
+}
+
+func (a *poissonArrival) NextInterval() time.Duration {
+	return time.Duration(a.rnd.ExpFloat64() / a.qps * float64(time.Second))
+}
+
+// PoissonRate returns the Arrival of the Poisson process with the given average QPS, i.e. the intervals
+// are exponentially distributed. The same seed generates the same traffic, which keeps the tests reproducible.
+func PoissonRate(qps float64, seed int64) Arrival {
+	if qps <= 0 {
+		qps = math.SmallestNonzeroFloat64
+	}
+	return &poissonArrival{qps: qps, rnd: rand.New(rand.NewSource(seed))}
+}
+
+type burstArrival struct {
+	size   int
+	period time.Duration
+	sent   int
+}
+
+func (a *burstArrival) NextInterval() time.Duration {
+	a.sent++
+	if a.sent <= a.size {
+		return 0
+	}
+	a.sent = 1
+	return a.period
+}
+
+// Bursts returns the Arrival of the bursts of size requests (arriving at the same time) every period.
+func Bursts(size int, period time.Duration) Arrival {
+	if size <= 0 {
+		size = 1
+	}
+	return &burstArrival{size: size, period: period}
+}
+
+func intervalOf(qps float64) time.Duration {
+	if qps <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(float64(time.Second) / qps)
+}
+
+// EntryFunc creates the entry of the resource, e.g. based on api.Entry with the options.
+type EntryFunc func(resource string) (*base.SentinelEntry, *base.BlockError)
+
+// Traffic describes the traffic fed into a resource.
+type Traffic struct {
+	Resource string
+	Arrival  Arrival
+	// Duration is the time span of the traffic.
+	Duration time.Duration
+	// Rt is the time from the entry passed until it exits, 0 means the entry exits immediately.
+	Rt time.Duration
+	// Entry creates the entries, api.Entry of the resource with the default options if nil.
+	Entry EntryFunc
+}
+
+// Result is the outcome of the traffic.
+type Result struct {
+	Total   uint64
+	Passed  uint64
+	Blocked uint64
+	// BlockedByType is the amount of the blocked requests of each block type.
+	BlockedByType map[base.BlockType]uint64
+}
+
+// BlockRatio returns the ratio of the blocked requests, 0 if there are no requests.
+func (r *Result) BlockRatio() float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return float64(r.Blocked) / float64(r.Total)
+}
+
+type pendingExit struct {
+	atNs  uint64
+	entry *base.SentinelEntry
+}
+
+// Run feeds the traffic into the entries under the FakeClock, which is moved forward to the end of the traffic.
+// The passed entries exit after the Rt of the traffic, all of them have exited when Run returns.
+func Run(clock *FakeClock, traffic Traffic) *Result {
+	entryFn := traffic.Entry
+	if entryFn == nil {
+		entryFn = func(resource string) (*base.SentinelEntry, *base.BlockError) {
+			return sentinel.Entry(resource)
+		}
+	}
+	result := &Result{BlockedByType: make(map[base.BlockType]uint64)}
+	// the entries exit in FIFO order, as the Rt is the same
+	pending := make([]pendingExit, 0)
+	exitUntil := func(ns uint64) {
+		for len(pending) > 0 && pending[0].atNs <= ns {
+			clock.advanceTo(pending[0].atNs)
+			pending[0].entry.Exit()
+			pending = pending[1:]
+		}
+	}
+
+	start := clock.CurrentTimeNano()
+	end := start + uint64(traffic.Duration)
+	for next := start; next < end; {
+		exitUntil(next)
+		clock.advanceTo(next)
+		result.Total++
+		e, b := entryFn(traffic.Resource)
+		if b != nil {
+			result.Blocked++
+			result.BlockedByType[b.BlockType()]++
+		} else {
+			result.Passed++
+			if e != nil {
+				if traffic.Rt <= 0 {
+					e.Exit()
+				} else {
+					pending = append(pending, pendingExit{atNs: next + uint64(traffic.Rt), entry: e})
+				}
+			}
+		}
+		interval := traffic.Arrival.NextInterval()
+		if interval < 0 || uint64(interval) >= end-next {
+			break
+		}
+		next += uint64(interval)
+	}
+	exitUntil(math.MaxUint64)
+	clock.advanceTo(end)
+	return result
+}
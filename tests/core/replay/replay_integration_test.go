@@ -0,0 +1,40 @@
+package replay
+
+import (
+	"testing"
+
+	sentinel "github.com/alibaba/sentinel-golang/api"
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/flow"
+	"github.com/alibaba/sentinel-golang/core/replay"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplay(t *testing.T) {
+	_, err := flow.LoadRules([]*flow.Rule{
+		{
+			Resource:               "replay-abc",
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			Threshold:              10,
+			StatIntervalInMs:       1000,
+		},
+	})
+	assert.Nil(t, err)
+	defer flow.ClearRules()
+
+	hist := make([]uint64, len(replay.RtBucketBoundsMs)+1)
+	hist[3] = 10
+	p := &replay.Profile{Samples: []*replay.Sample{
+		{Timestamp: 100, Resource: "replay-abc", Requests: 30, RtHistogram: hist},
+		{Timestamp: 100, Resource: "replay-def", Requests: 5, RtHistogram: hist},
+		{Timestamp: 101, Resource: "replay-abc", Requests: 8, RtHistogram: hist},
+	}}
+	results := replay.Replay(p, func(res string) (*base.SentinelEntry, *base.BlockError) {
+		return sentinel.Entry(res, sentinel.WithTrafficType(base.Inbound))
+	})
+	assert.Equal(t, 3, len(results))
+	assert.Equal(t, replay.Result{Timestamp: 100, Resource: "replay-abc", Requests: 30, Passed: 10, Blocked: 20}, *results[0])
+	assert.Equal(t, replay.Result{Timestamp: 100, Resource: "replay-def", Requests: 5, Passed: 5}, *results[1])
+	assert.Equal(t, replay.Result{Timestamp: 101, Resource: "replay-abc", Requests: 8, Passed: 8}, *results[2])
+}
@@ -0,0 +1,62 @@
+package util
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock is the time source of Sentinel.
+type Clock interface {
+	// CurrentTimeMillis returns the current Unix timestamp in milliseconds.
+	CurrentTimeMillis() uint64
+	// CurrentTimeNano returns the current Unix timestamp in nanoseconds.
+	CurrentTimeNano() uint64
+}
+
+// RealClock is the Clock based on the system time.
+type RealClock struct {
+}
+
+func (c *RealClock) CurrentTimeMillis() uint64 {
+	return uint64(time.Now().UnixNano()) / UnixTimeUnitOffset
+}
+
+func (c *RealClock) CurrentTimeNano() uint64 {
+	return uint64(time.Now().UnixNano())
+}
+
+type clockHolder struct {
+	clock Clock
+}
+
+var (
+	realClock = &RealClock{}
+	clock     atomic.Value
+	// customClockSet indicates whether the clock is replaced, so that the default path avoids the interface calls.
+	customClockSet int32
+)
+
+func init() {
+	clock.Store(&clockHolder{clock: realClock})
+}
+
+// SetClock replaces the time source of Sentinel, e.g. with a mock clock in tests.
+// The cached time of the time ticker is bypassed once the clock is replaced. Nil resets to the RealClock.
+func SetClock(c Clock) {
+	if c == nil {
+		clock.Store(&clockHolder{clock: realClock})
+		atomic.StoreInt32(&customClockSet, 0)
+		return
+	}
+	clock.Store(&clockHolder{clock: c})
+	atomic.StoreInt32(&customClockSet, 1)
+}
+
+// CurrentClock returns the current time source of Sentinel.
+func CurrentClock() Clock {
+	return clock.Load().(*clockHolder).clock
+}
+
+func isCustomClockSet() bool {
+	return atomic.LoadInt32(&customClockSet) == 1
+}
@@ -0,0 +1,47 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockClock struct {
+	nowNs uint64
+}
+
+func (c *mockClock) CurrentTimeMillis() uint64 {
+	return c.nowNs / UnixTimeUnitOffset
+}
+
+func (c *mockClock) CurrentTimeNano() uint64 {
+	return c.nowNs
+}
+
+func TestSetClock(t *testing.T) {
+	defer SetClock(nil)
+
+	SetClock(&mockClock{nowNs: 1234 * UnixTimeUnitOffset})
+	assert.Equal(t, uint64(1234), CurrentTimeMillis())
+	assert.Equal(t, uint64(1234), PreciseTimeMillis())
+	assert.Equal(t, 1234*UnixTimeUnitOffset, CurrentTimeNano())
+
+	SetClock(nil)
+	_, ok := CurrentClock().(*RealClock)
+	assert.True(t, ok)
+	assert.True(t, CurrentTimeMillis() > 1234)
+}
+
+func TestStartTimeTickerWithResolution(t *testing.T) {
+	StartTimeTickerWithResolution(5 * time.Millisecond)
+	defer StartTimeTickerWithResolution(time.Millisecond)
+
+	assert.Equal(t, 5*time.Millisecond, TimeTickerResolution())
+	assert.True(t, CurrentTimeMillsWithTicker() > 0)
+	assert.True(t, CurrentTimeNanoWithTicker() > 0)
+	before := CurrentTimeMillis()
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, CurrentTimeMillis() > before)
+	assert.True(t, PreciseTimeMillis() >= CurrentTimeMillis())
+}
@@ -21,7 +21,11 @@ func FormatDate(tsMillis uint64) string {
 }
 
 // Returns the current Unix timestamp in milliseconds.
+// It's the cached coarse time if the time ticker is started, see StartTimeTicker.
 func CurrentTimeMillis() uint64 {
+	if isCustomClockSet() {
+		return CurrentClock().CurrentTimeMillis()
+	}
 	// Read from cache first.
 	tickerNow := CurrentTimeMillsWithTicker()
 	if tickerNow > uint64(0) {
@@ -30,7 +34,30 @@ func CurrentTimeMillis() uint64 {
 	return uint64(time.Now().UnixNano()) / UnixTimeUnitOffset
 }
 
+// PreciseTimeMillis returns the current Unix timestamp in milliseconds, bypassing the cached time of the time ticker.
+func PreciseTimeMillis() uint64 {
+	if isCustomClockSet() {
+		return CurrentClock().CurrentTimeMillis()
+	}
+	return uint64(time.Now().UnixNano()) / UnixTimeUnitOffset
+}
+
+// CoarseTimeNano returns the current Unix timestamp in nanoseconds.
+// It's the cached coarse time if the time ticker is started, see StartTimeTicker.
+func CoarseTimeNano() uint64 {
+	if isCustomClockSet() {
+		return CurrentClock().CurrentTimeNano()
+	}
+	if tickerNow := CurrentTimeNanoWithTicker(); tickerNow > 0 {
+		return tickerNow
+	}
+	return uint64(time.Now().UnixNano())
+}
+
 // Returns the current Unix timestamp in nanoseconds.
 func CurrentTimeNano() uint64 {
+	if isCustomClockSet() {
+		return CurrentClock().CurrentTimeNano()
+	}
 	return uint64(time.Now().UnixNano())
 }
@@ -5,21 +5,56 @@ import (
 	"time"
 )
 
-var nowInMs = uint64(0)
+var (
+	nowInMs = uint64(0)
+	nowInNs = uint64(0)
+
+	tickerResolutionNs = int64(time.Millisecond)
+	tickerStarted      = int32(0)
+)
 
 // StartTimeTicker starts a background task that caches current timestamp per millisecond,
 // which may provide better performance in high-concurrency scenarios.
 func StartTimeTicker() {
-	atomic.StoreUint64(&nowInMs, uint64(time.Now().UnixNano())/UnixTimeUnitOffset)
+	StartTimeTickerWithResolution(time.Millisecond)
+}
+
+// StartTimeTickerWithResolution starts a background task that caches current timestamp every resolution.
+// The coarser resolution saves more time syscalls on the hot paths, at the cost of less accurate time.
+// If the ticker has been started, only the resolution is updated.
+func StartTimeTickerWithResolution(resolution time.Duration) {
+	if resolution <= 0 {
+		resolution = time.Millisecond
+	}
+	atomic.StoreInt64(&tickerResolutionNs, int64(resolution))
+	if !atomic.CompareAndSwapInt32(&tickerStarted, 0, 1) {
+		return
+	}
+	refreshCachedTime()
 	go func() {
 		for {
-			now := uint64(time.Now().UnixNano()) / UnixTimeUnitOffset
-			atomic.StoreUint64(&nowInMs, now)
-			time.Sleep(time.Millisecond)
+			time.Sleep(time.Duration(atomic.LoadInt64(&tickerResolutionNs)))
+			refreshCachedTime()
 		}
 	}()
 }
 
+func refreshCachedTime() {
+	now := uint64(time.Now().UnixNano())
+	atomic.StoreUint64(&nowInNs, now)
+	atomic.StoreUint64(&nowInMs, now/UnixTimeUnitOffset)
+}
+
+// TimeTickerResolution returns the resolution of the time ticker.
+func TimeTickerResolution() time.Duration {
+	return time.Duration(atomic.LoadInt64(&tickerResolutionNs))
+}
+
 func CurrentTimeMillsWithTicker() uint64 {
 	return atomic.LoadUint64(&nowInMs)
 }
+
+// CurrentTimeNanoWithTicker returns the cached timestamp in nanoseconds, 0 if the ticker isn't started.
+func CurrentTimeNanoWithTicker() uint64 {
+	return atomic.LoadUint64(&nowInNs)
+}